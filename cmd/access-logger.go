@@ -0,0 +1,269 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Supported access log formats.
+const (
+	accessLogFormatCombined = "combined"
+	accessLogFormatJSON     = "json"
+)
+
+// Special MINIO_ACCESS_LOG value to send access logs to the local syslog.
+const accessLogSyslog = "syslog"
+
+const (
+	// Access log files are rotated once they reach this size.
+	accessLogMaxSize = 100 * humanize.MiByte
+	// Number of rotated access log files kept around.
+	accessLogMaxBackups = 5
+)
+
+// Time format used by the Apache combined log format.
+const combinedLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogEntry - one access log line, one per HTTP request.
+type accessLogEntry struct {
+	RemoteHost string        `json:"remoteHost"`
+	Time       time.Time     `json:"time"`
+	Method     string        `json:"method"`
+	RequestURI string        `json:"requestURI"`
+	Proto      string        `json:"proto"`
+	Status     int           `json:"status"`
+	Size       int64         `json:"size"`
+	Referer    string        `json:"referer"`
+	UserAgent  string        `json:"userAgent"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// accessLogger writes access log entries in a given format.
+type accessLogger struct {
+	mu     sync.Mutex
+	writer io.Writer
+	format string
+}
+
+// newAccessLogger - initializes a new access logger writing to
+// target, which is either a file path or "syslog".
+func newAccessLogger(target, format string) (*accessLogger, error) {
+	if format != accessLogFormatCombined && format != accessLogFormatJSON {
+		return nil, fmt.Errorf("Unsupported access log format %s", format)
+	}
+	var writer io.Writer
+	var err error
+	if target == accessLogSyslog {
+		writer, err = newSyslogAccessLogWriter()
+	} else {
+		writer, err = newRotatingFile(target, accessLogMaxSize, accessLogMaxBackups)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &accessLogger{writer: writer, format: format}, nil
+}
+
+// formatCombined - formats an entry in Apache combined log format.
+func (entry accessLogEntry) formatCombined() string {
+	referer, userAgent := entry.Referer, entry.UserAgent
+	if referer == "" {
+		referer = "-"
+	}
+	if userAgent == "" {
+		userAgent = "-"
+	}
+	return fmt.Sprintf("%s - - [%s] \"%s %s %s\" %d %d \"%s\" \"%s\"\n",
+		entry.RemoteHost, entry.Time.Format(combinedLogTimeFormat),
+		entry.Method, entry.RequestURI, entry.Proto,
+		entry.Status, entry.Size, referer, userAgent)
+}
+
+// Log - writes the entry in the configured format.
+func (l *accessLogger) Log(entry accessLogEntry) error {
+	var line []byte
+	switch l.format {
+	case accessLogFormatJSON:
+		jsonBytes, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		line = append(jsonBytes, '\n')
+	default:
+		line = []byte(entry.formatCombined())
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.writer.Write(line)
+	return err
+}
+
+// rotatingFile is an io.Writer which rotates the underlying file once
+// it grows beyond maxSize, keeping at most maxBackups older files named
+// <filename>.1 (most recent) to <filename>.<maxBackups>.
+type rotatingFile struct {
+	mu         sync.Mutex
+	filename   string
+	file       *os.File
+	size       int64
+	maxSize    int64
+	maxBackups int
+}
+
+// newRotatingFile - opens filename for appending.
+func newRotatingFile(filename string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{
+		filename:   filename,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	// Creates the named file with mode 0666, honors system umask.
+	file, err := os.OpenFile(r.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = fi.Size()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	for i := r.maxBackups - 1; i > 0; i-- {
+		// Missing backups are not an error, they simply don't exist yet.
+		os.Rename(fmt.Sprintf("%s.%d", r.filename, i), fmt.Sprintf("%s.%d", r.filename, i+1))
+	}
+	if r.maxBackups > 0 {
+		if err := os.Rename(r.filename, r.filename+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.filename); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// Write - writes p to the file, rotating it first when needed.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close - closes the underlying file.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// accessLogHandler writes one access log entry per request.
+type accessLogHandler struct {
+	handler http.Handler
+}
+
+// setAccessLogHandler logs all requests when MINIO_ACCESS_LOG is set.
+func setAccessLogHandler(h http.Handler) http.Handler {
+	return accessLogHandler{h}
+}
+
+func (h accessLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if globalAccessLogger == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	startTime := time.Now().UTC()
+	// Wraps w to record http response information
+	ww := &httpResponseRecorder{ResponseWriter: w}
+	h.handler.ServeHTTP(ww, r)
+
+	status := ww.respStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	remoteHost, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteHost = r.RemoteAddr
+	}
+	entry := accessLogEntry{
+		RemoteHost: remoteHost,
+		Time:       startTime,
+		Method:     r.Method,
+		RequestURI: r.RequestURI,
+		Proto:      r.Proto,
+		Status:     status,
+		Size:       ww.respBytes,
+		Referer:    r.Referer(),
+		UserAgent:  r.UserAgent(),
+		Duration:   time.Since(startTime),
+	}
+	errorIf(globalAccessLogger.Log(entry), "Unable to write access log entry.")
+}
+
+// initAccessLogger - initializes the global access logger from
+// MINIO_ACCESS_LOG and MINIO_ACCESS_LOG_FORMAT env.
+func initAccessLogger() error {
+	target := strings.TrimSpace(os.Getenv("MINIO_ACCESS_LOG"))
+	if target == "" {
+		return nil
+	}
+	format := strings.ToLower(strings.TrimSpace(os.Getenv("MINIO_ACCESS_LOG_FORMAT")))
+	if format == "" {
+		format = accessLogFormatCombined
+	}
+	logger, err := newAccessLogger(target, format)
+	if err != nil {
+		return err
+	}
+	globalAccessLogger = logger
+	return nil
+}
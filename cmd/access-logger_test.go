@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests formatting entries in Apache combined log format.
+func TestAccessLogEntryFormatCombined(t *testing.T) {
+	entry := accessLogEntry{
+		RemoteHost: "10.0.0.1",
+		Time:       time.Date(2017, time.March, 1, 10, 20, 30, 0, time.UTC),
+		Method:     "GET",
+		RequestURI: "/bucket/object?versionId=1",
+		Proto:      "HTTP/1.1",
+		Status:     200,
+		Size:       1024,
+		UserAgent:  "mc",
+	}
+	expected := "10.0.0.1 - - [01/Mar/2017:10:20:30 +0000] \"GET /bucket/object?versionId=1 HTTP/1.1\" 200 1024 \"-\" \"mc\"\n"
+	if line := entry.formatCombined(); line != expected {
+		t.Fatalf("Expected %q, got %q", expected, line)
+	}
+}
+
+// Tests rotating file keeps at most maxBackups files.
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "access.log")
+	r, err := newRotatingFile(filename, 10, 2)
+	if err != nil {
+		t.Fatalf("Unable to create rotating file, %s", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		if _, err = r.Write([]byte(line)); err != nil {
+			t.Fatalf("Unable to write, %s", err)
+		}
+	}
+
+	testCases := []struct {
+		filename string
+		content  string
+	}{
+		{filename, "line4\n"},
+		{filename + ".1", "line3\n"},
+		{filename + ".2", "line2\n"},
+	}
+	for i, testCase := range testCases {
+		content, err := ioutil.ReadFile(testCase.filename)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to read %s, %s", i+1, testCase.filename, err)
+		}
+		if string(content) != testCase.content {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.content, string(content))
+		}
+	}
+	if _, err = os.Stat(filename + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected only 2 backups to be kept, got %v", err)
+	}
+}
+
+// Tests access log handler writes one JSON line per request.
+func TestAccessLogHandler(t *testing.T) {
+	defer func() { globalAccessLogger = nil }()
+
+	var buf bytes.Buffer
+	globalAccessLogger = &accessLogger{writer: &buf, format: accessLogFormatJSON}
+
+	handler := setAccessLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+	r, err := http.NewRequest("GET", "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatalf("Unable to create request, %s", err)
+	}
+	r.RemoteAddr = "10.0.0.1:4321"
+	r.RequestURI = "/bucket/object"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	var entry accessLogEntry
+	if err = json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Unable to parse access log line %q, %s", buf.String(), err)
+	}
+	if entry.RemoteHost != "10.0.0.1" || entry.RequestURI != "/bucket/object" ||
+		entry.Status != http.StatusNotFound || entry.Size != int64(len("not found")) {
+		t.Errorf("Unexpected access log entry %#v", entry)
+	}
+}
+
+// Tests unsupported access log formats are rejected.
+func TestNewAccessLoggerFormat(t *testing.T) {
+	if _, err := newAccessLogger("access.log", "xml"); err == nil {
+		t.Fatal("Expected unsupported format to fail")
+	}
+}
@@ -0,0 +1,277 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Minio extension header carrying the approval token of a
+	// destructive operation.
+	minioApprovalTokenHeader = "X-Minio-Approval-Token"
+
+	// Default time an approval token may be used for.
+	defaultApprovalWindow = 15 * time.Minute
+
+	// Used approval tokens are recorded in the reserved bucket, named
+	// after their expiry and nonce, until they expire.
+	approvalsPrefix = "approvals"
+)
+
+// approvalOperations - destructive operations needing the approval of a
+// second credential, by the admin action the approver must be allowed.
+var approvalOperations = map[string]string{
+	"lock.clear":              adminActionLock,
+	"heal.format":             adminActionHeal,
+	"service.set-credentials": adminActionCredentials,
+	"config.set":              adminActionConfig,
+	"backup.restore":          adminActionConfig,
+	forceDeleteAuditOperation: adminActionAll,
+}
+
+// approvalConfig - two-person rule for destructive operations.
+type approvalConfig struct {
+	// Destructive operations need an approval token issued with a
+	// second credential if enabled.
+	Enabled bool `json:"enabled"`
+
+	// Time an approval token may be used for, e.g. "5m", defaults to
+	// 15 minutes.
+	Window string `json:"window"`
+}
+
+// validateApprovalConfig - validates approval settings.
+func validateApprovalConfig(config approvalConfig) error {
+	if config.Window == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(config.Window)
+	if err != nil {
+		return fmt.Errorf("window: %v", err)
+	}
+	if window <= 0 {
+		return errors.New("window: must be positive")
+	}
+	return nil
+}
+
+// getWindow - returns the time an approval token may be used for.
+func (config approvalConfig) getWindow() time.Duration {
+	if window, _ := time.ParseDuration(config.Window); window > 0 {
+		return window
+	}
+	return defaultApprovalWindow
+}
+
+// approvalClaims - the operation an approval token approves, signed
+// with the server secret key so that every server can verify it.
+type approvalClaims struct {
+	Operation string    `json:"operation"`
+	Bucket    string    `json:"bucket,omitempty"`
+	Approver  string    `json:"approver"`
+	Expires   time.Time `json:"expires"`
+	Nonce     string    `json:"nonce"`
+}
+
+var (
+	// errInvalidApprovalToken - the approval token is malformed or not
+	// signed by this deployment.
+	errInvalidApprovalToken = errors.New("Invalid approval token")
+
+	// errApprovalTokenUsed - the approval token was used already.
+	errApprovalTokenUsed = errors.New("Approval token already used")
+)
+
+// signApprovalClaims - returns the approval token of claims.
+func signApprovalClaims(claims approvalClaims, secretKey string) (string, error) {
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claimsBytes)
+	return payload + "." + hex.EncodeToString(sumHMAC([]byte(secretKey), []byte(payload))), nil
+}
+
+// parseApprovalToken - verifies the signature of an approval token and
+// returns its claims.
+func parseApprovalToken(token, secretKey string) (approvalClaims, error) {
+	fields := strings.SplitN(token, ".", 2)
+	if len(fields) != 2 {
+		return approvalClaims{}, errInvalidApprovalToken
+	}
+	signature, err := hex.DecodeString(fields[1])
+	if err != nil || !hmac.Equal(signature, sumHMAC([]byte(secretKey), []byte(fields[0]))) {
+		return approvalClaims{}, errInvalidApprovalToken
+	}
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(fields[0])
+	if err != nil {
+		return approvalClaims{}, errInvalidApprovalToken
+	}
+	var claims approvalClaims
+	if err = json.Unmarshal(claimsBytes, &claims); err != nil {
+		return approvalClaims{}, errInvalidApprovalToken
+	}
+	return claims, nil
+}
+
+// isApproverValid - returns true if accessKey is the server or an admin
+// access key allowed the action of operation.
+func isApproverValid(accessKey, operation string) bool {
+	if accessKey == serverConfig.GetCredential().AccessKey {
+		return true
+	}
+	adminCred, ok := globalAdminCredentials[accessKey]
+	return ok && adminCred.isAllowed(approvalOperations[operation])
+}
+
+// issueApproval - returns an approval token of approver for operation
+// on bucket, if any, and its expiry.
+func issueApproval(approver, operation, bucket string, now time.Time) (string, time.Time, error) {
+	claims := approvalClaims{
+		Operation: operation,
+		Bucket:    bucket,
+		Approver:  approver,
+		Expires:   now.Add(serverConfig.GetApproval().getWindow()).UTC(),
+		Nonce:     mustGetUUID(),
+	}
+	token, err := signApprovalClaims(claims, serverConfig.GetCredential().SecretKey)
+	return token, claims.Expires, err
+}
+
+// getApprovalPath - returns the path of the record of a used approval
+// token.
+func getApprovalPath(claims approvalClaims) string {
+	return pathJoin(approvalsPrefix, strconv.FormatInt(claims.Expires.Unix(), 10)+"."+claims.Nonce)
+}
+
+// consumeApproval - records the approval token of claims as used,
+// returns errApprovalTokenUsed if it was used already. Records of
+// expired tokens are removed.
+func consumeApproval(objAPI ObjectLayer, claims approvalClaims, now time.Time) error {
+	approvalPath := getApprovalPath(claims)
+
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, approvalPath)
+	objLock.Lock()
+	_, err := objAPI.GetObjectInfo(minioMetaBucket, approvalPath)
+	if err == nil {
+		objLock.Unlock()
+		return errApprovalTokenUsed
+	}
+	if !isErrObjectNotFound(err) {
+		objLock.Unlock()
+		return errorCause(err)
+	}
+	_, err = objAPI.PutObject(minioMetaBucket, approvalPath, 0, strings.NewReader(""), nil, "")
+	objLock.Unlock()
+	if err != nil {
+		return errorCause(err)
+	}
+
+	lo, err := objAPI.ListObjects(minioMetaBucket, approvalsPrefix+slashSeparator, "", "", 1000)
+	if err != nil {
+		return nil
+	}
+	for _, obj := range lo.Objects {
+		name := strings.TrimPrefix(obj.Name, approvalsPrefix+slashSeparator)
+		expires, pErr := strconv.ParseInt(strings.SplitN(name, ".", 2)[0], 10, 64)
+		if pErr == nil && now.Unix() > expires {
+			errorIf(objAPI.DeleteObject(minioMetaBucket, obj.Name), "Unable to remove used approval token %s.", name)
+		}
+	}
+	return nil
+}
+
+// releaseApproval - removes the record of the approval token of
+// claims, so that it can be used again.
+func releaseApproval(objAPI ObjectLayer, claims approvalClaims) error {
+	approvalPath := getApprovalPath(claims)
+
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, approvalPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	return errorCause(objAPI.DeleteObject(minioMetaBucket, approvalPath))
+}
+
+// getRequestApprover - returns the approver of the approval token of r
+// if it is validly signed, empty string otherwise.
+func getRequestApprover(r *http.Request) string {
+	token := r.Header.Get(minioApprovalTokenHeader)
+	if token == "" {
+		return ""
+	}
+	claims, err := parseApprovalToken(token, serverConfig.GetCredential().SecretKey)
+	if err != nil {
+		return ""
+	}
+	return claims.Approver
+}
+
+// checkApproval - verifies, if approvals are enabled, that r carries an
+// unused approval token for operation on bucket issued with another
+// credential than the one r is signed with. The token is reserved so
+// that no other request uses it meanwhile, the returned function must
+// be called with the outcome of the operation. The token is used up if
+// the operation succeeded, otherwise it may be used again.
+func checkApproval(objAPI ObjectLayer, r *http.Request, operation, bucket string) (approvalDone func(success bool), s3Error APIErrorCode) {
+	approvalDone = func(bool) {}
+	config := serverConfig.GetApproval()
+	if !config.Enabled {
+		return approvalDone, ErrNone
+	}
+	if objAPI == nil {
+		return approvalDone, ErrServerNotInitialized
+	}
+	token := r.Header.Get(minioApprovalTokenHeader)
+	if token == "" {
+		return approvalDone, ErrAdminApprovalRequired
+	}
+	claims, err := parseApprovalToken(token, serverConfig.GetCredential().SecretKey)
+	if err != nil {
+		return approvalDone, ErrAdminInvalidApproval
+	}
+	now := time.Now().UTC()
+	if claims.Operation != operation || claims.Bucket != bucket || now.After(claims.Expires) {
+		return approvalDone, ErrAdminInvalidApproval
+	}
+	// Approvals need a second person, whose credential is still valid.
+	if claims.Approver == getRequestAccessKey(r) || !isApproverValid(claims.Approver, operation) {
+		return approvalDone, ErrAdminInvalidApproval
+	}
+	if err = consumeApproval(objAPI, claims, now); err != nil {
+		if err == errApprovalTokenUsed {
+			return approvalDone, ErrAdminInvalidApproval
+		}
+		errorIf(err, "Unable to record use of approval token.")
+		return approvalDone, toAPIErrorCode(err)
+	}
+	return func(success bool) {
+		if !success {
+			errorIf(releaseApproval(objAPI, claims), "Unable to release approval token.")
+		}
+	}, ErrNone
+}
@@ -0,0 +1,86 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests validation of approval settings.
+func TestValidateApprovalConfig(t *testing.T) {
+	testCases := []struct {
+		config  approvalConfig
+		success bool
+	}{
+		{approvalConfig{}, true},
+		{approvalConfig{Enabled: true, Window: "5m"}, true},
+		{approvalConfig{Enabled: true, Window: "soon"}, false},
+		{approvalConfig{Enabled: true, Window: "-5m"}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateApprovalConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests signing and verification of approval tokens.
+func TestParseApprovalToken(t *testing.T) {
+	claims := approvalClaims{
+		Operation: "lock.clear",
+		Bucket:    "mybucket",
+		Approver:  "ADMINACCESSKEY",
+		Expires:   time.Date(2017, 6, 1, 0, 15, 0, 0, time.UTC),
+		Nonce:     "nonce",
+	}
+	token, err := signApprovalClaims(claims, "secretkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseApprovalToken(token, "secretkey")
+	if err != nil {
+		t.Fatalf("Failed to parse approval token - %v", err)
+	}
+	if parsed != claims {
+		t.Fatalf("Expected claims %#v, got %#v", claims, parsed)
+	}
+
+	forged, err := signApprovalClaims(approvalClaims{Operation: "heal.format", Approver: "ADMINACCESSKEY"}, "secretkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature := token[:strings.Index(token, ".")], token[strings.Index(token, ".")+1:]
+	for i, invalid := range []string{
+		"",
+		payload,
+		forged[:strings.Index(forged, ".")] + "." + signature,
+		payload + ".zz",
+	} {
+		if _, err = parseApprovalToken(invalid, "secretkey"); err != errInvalidApprovalToken {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, errInvalidApprovalToken, err)
+		}
+	}
+	if _, err = parseApprovalToken(token, "othersecretkey"); err != errInvalidApprovalToken {
+		t.Errorf("Expected token of another secret key to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Admin audit logs are kept in the reserved bucket, in segments
+	// under a directory per UTC day.
+	adminAuditPrefix = "audit/admin"
+
+	// Date format of admin audit log names and of the date query param.
+	adminAuditDateFormat = "2006-01-02"
+
+	// Entries per log segment. Log objects are immutable, so the last
+	// segment is rewritten on every append, its size bounds the cost.
+	adminAuditSegmentSize = 256
+
+	// Maximum size of admin API request bodies, which are read whole
+	// for signature verification.
+	maxAdminRequestSize = 8 * humanize.MiByte
+)
+
+// Path of the head of the admin audit log, pointing at its last entry.
+var adminAuditHeadPath = pathJoin(adminAuditPrefix, "head.json")
+
+// adminAuditEntry - one admin API call. Every entry carries the hash
+// of the previous entry of the log and a sequence number, so removing
+// or modifying an entry breaks the hash chain.
+type adminAuditEntry struct {
+	Seq           uint64    `json:"seq"`
+	Time          time.Time `json:"time"`
+	AccessKey     string    `json:"accessKey"`
+	RemoteHost    string    `json:"remoteHost"`
+	Operation     string    `json:"operation"`
+	Resource      string    `json:"resource,omitempty"`
+	Approver      string    `json:"approver,omitempty"`
+	PayloadSHA256 string    `json:"payloadSHA256"`
+	Status        int       `json:"status"`
+	PrevHash      string    `json:"prevHash"`
+	Hash          string    `json:"hash"`
+}
+
+// getAdminAuditKey - returns the key of the hash chain, the server
+// secret key, so that entries cannot be forged without it.
+func getAdminAuditKey() []byte {
+	return []byte(serverConfig.GetCredential().SecretKey)
+}
+
+// computeAdminAuditMAC - returns the HMAC-SHA256 of fields with key.
+func computeAdminAuditMAC(key []byte, fields ...string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// computeHash - returns the hash of the entry keyed with key, which
+// covers all fields but Hash itself. Resource and Approver are only
+// covered when set.
+func (e adminAuditEntry) computeHash(key []byte) string {
+	fields := []string{
+		strconv.FormatUint(e.Seq, 10),
+		e.Time.UTC().Format(time.RFC3339Nano),
+		e.AccessKey,
+		e.RemoteHost,
+		e.Operation,
+		e.PayloadSHA256,
+		strconv.Itoa(e.Status),
+		e.PrevHash,
+	}
+	if e.Resource != "" {
+		fields = append(fields, e.Resource)
+	}
+	if e.Approver != "" {
+		fields = append(fields, "approver:"+e.Approver)
+	}
+	return computeAdminAuditMAC(key, fields...)
+}
+
+// adminAuditHead - points at the last entry of the admin audit log, so
+// that appends need not search for it and truncated logs are detected.
+type adminAuditHead struct {
+	// Last segment and the number of entries in it.
+	Segment string `json:"segment"`
+	Entries int    `json:"entries"`
+	// Sequence number and hash of the last entry.
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+	MAC  string `json:"mac"`
+}
+
+// computeMAC - returns the MAC of the head keyed with key.
+func (h adminAuditHead) computeMAC(key []byte) string {
+	return computeAdminAuditMAC(key, h.Segment, strconv.Itoa(h.Entries), strconv.FormatUint(h.Seq, 10), h.Hash)
+}
+
+// adminAuditLog - response of the admin audit list API.
+type adminAuditLog struct {
+	Date    string            `json:"date"`
+	Entries []adminAuditEntry `json:"entries"`
+	// Index of the first entry breaking the hash chain, -1 if the
+	// log is intact. The number of entries if entries at the end of
+	// the log are missing.
+	BrokenAt int `json:"brokenAt"`
+}
+
+// getAdminAuditLogPath - returns the log directory path for date.
+func getAdminAuditLogPath(date time.Time) string {
+	return pathJoin(adminAuditPrefix, date.UTC().Format(adminAuditDateFormat))
+}
+
+// getAdminAuditSegmentPath - returns the path of the segment of the log
+// at logPath starting with the entry numbered seq, segment names sort
+// in the order of their entries.
+func getAdminAuditSegmentPath(logPath string, seq uint64) string {
+	return pathJoin(logPath, fmt.Sprintf("%020d.log", seq))
+}
+
+// readAdminAuditHead - reads the head of the log, which is empty if
+// nothing was logged yet.
+func readAdminAuditHead(objAPI ObjectLayer) (head adminAuditHead, err error) {
+	var buffer bytes.Buffer
+	if err = objAPI.GetObject(minioMetaBucket, adminAuditHeadPath, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) {
+			return head, nil
+		}
+		return head, errorCause(err)
+	}
+	err = json.Unmarshal(buffer.Bytes(), &head)
+	return head, err
+}
+
+// readAdminAuditSegment - reads the entries of the segment object at
+// segmentPath.
+func readAdminAuditSegment(objAPI ObjectLayer, segmentPath string) ([]adminAuditEntry, error) {
+	var buffer bytes.Buffer
+	if err := objAPI.GetObject(minioMetaBucket, segmentPath, 0, -1, &buffer); err != nil {
+		return nil, errorCause(err)
+	}
+	var entries []adminAuditEntry
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var entry adminAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// listAdminAuditEntries - lists objects, or directories if delimiter
+// is set, under prefix in the log in lexical order.
+func listAdminAuditEntries(objAPI ObjectLayer, prefix, delimiter string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		lo, err := objAPI.ListObjects(minioMetaBucket, prefix, marker, delimiter, maxObjectList)
+		if err != nil {
+			if isErrObjectNotFound(err) {
+				return nil, nil
+			}
+			return nil, errorCause(err)
+		}
+		for _, obj := range lo.Objects {
+			names = append(names, obj.Name)
+		}
+		names = append(names, lo.Prefixes...)
+		if !lo.IsTruncated {
+			sort.Strings(names)
+			return names, nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// readAdminAuditLog - reads the entries of all segments of the log at
+// logPath, a missing log has no entries.
+func readAdminAuditLog(objAPI ObjectLayer, logPath string) ([]adminAuditEntry, error) {
+	segments, err := listAdminAuditEntries(objAPI, logPath+slashSeparator, "")
+	if err != nil {
+		return nil, err
+	}
+	var entries []adminAuditEntry
+	for _, segment := range segments {
+		segmentEntries, err := readAdminAuditSegment(objAPI, segment)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, segmentEntries...)
+	}
+	return entries, nil
+}
+
+// readPrevAdminAuditEntry - reads the last entry logged on a day
+// before the log at logPath, the zero entry if there is none.
+func readPrevAdminAuditEntry(objAPI ObjectLayer, logPath string) (adminAuditEntry, error) {
+	logPaths, err := listAdminAuditEntries(objAPI, adminAuditPrefix+slashSeparator, slashSeparator)
+	if err != nil {
+		return adminAuditEntry{}, err
+	}
+	for i := len(logPaths) - 1; i >= 0; i-- {
+		prevLogPath := strings.TrimSuffix(logPaths[i], slashSeparator)
+		if prevLogPath >= logPath || prevLogPath == adminAuditHeadPath {
+			continue
+		}
+		segments, err := listAdminAuditEntries(objAPI, prevLogPath+slashSeparator, "")
+		if err != nil || len(segments) == 0 {
+			return adminAuditEntry{}, err
+		}
+		entries, err := readAdminAuditSegment(objAPI, segments[len(segments)-1])
+		if err != nil || len(entries) == 0 {
+			return adminAuditEntry{}, err
+		}
+		return entries[len(entries)-1], nil
+	}
+	return adminAuditEntry{}, nil
+}
+
+// verifyAdminAuditLog - returns the index of the first entry breaking
+// the hash chain which continues prev, the last entry logged before
+// entries, -1 if the chain is intact.
+func verifyAdminAuditLog(prev adminAuditEntry, entries []adminAuditEntry) int {
+	key := getAdminAuditKey()
+	for i, entry := range entries {
+		if entry.Seq != prev.Seq+1 || entry.PrevHash != prev.Hash || entry.Hash != entry.computeHash(key) {
+			return i
+		}
+		prev = entry
+	}
+	return -1
+}
+
+// getAdminAuditLog - reads the log of the day of date and verifies its
+// hash chain, from the last entry of the days before and, if the head
+// of the log is on that day, up to the head.
+func getAdminAuditLog(objAPI ObjectLayer, date time.Time) (adminAuditLog, error) {
+	logPath := getAdminAuditLogPath(date)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, adminAuditHeadPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	auditLog := adminAuditLog{Date: date.UTC().Format(adminAuditDateFormat)}
+	head, err := readAdminAuditHead(objAPI)
+	if err != nil {
+		return auditLog, err
+	}
+	if auditLog.Entries, err = readAdminAuditLog(objAPI, logPath); err != nil {
+		return auditLog, err
+	}
+	prev, err := readPrevAdminAuditEntry(objAPI, logPath)
+	if err != nil {
+		return auditLog, err
+	}
+
+	auditLog.BrokenAt = verifyAdminAuditLog(prev, auditLog.Entries)
+	if auditLog.BrokenAt == -1 && path.Dir(head.Segment) == logPath {
+		// Entries at the end of the last day are missing.
+		var last adminAuditEntry
+		if n := len(auditLog.Entries); n > 0 {
+			last = auditLog.Entries[n-1]
+		}
+		if head.MAC != head.computeMAC(getAdminAuditKey()) || last.Seq != head.Seq || last.Hash != head.Hash {
+			auditLog.BrokenAt = len(auditLog.Entries)
+		}
+	}
+	return auditLog, nil
+}
+
+// appendAdminAuditEntry - chains entry to the last entry of the log and
+// appends it to the last segment, or to a new one once the segment is
+// full or the day is over. Under the namespace lock of the head, which
+// is updated to point at entry.
+func appendAdminAuditEntry(objAPI ObjectLayer, entry adminAuditEntry) error {
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, adminAuditHeadPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	head, err := readAdminAuditHead(objAPI)
+	if err != nil {
+		return err
+	}
+	entry.Seq = head.Seq + 1
+	entry.PrevHash = head.Hash
+	key := getAdminAuditKey()
+	entry.Hash = entry.computeHash(key)
+
+	// Entries are never logged on a day before the last entry, even
+	// if the clock of this node is behind.
+	logPath := getAdminAuditLogPath(entry.Time)
+	if headLogPath := path.Dir(head.Segment); head.Segment != "" && headLogPath > logPath {
+		logPath = headLogPath
+	}
+
+	var buffer bytes.Buffer
+	if path.Dir(head.Segment) == logPath && head.Entries < adminAuditSegmentSize {
+		if err = objAPI.GetObject(minioMetaBucket, head.Segment, 0, -1, &buffer); err != nil && !isErrObjectNotFound(err) {
+			return errorCause(err)
+		}
+		head.Entries++
+	} else {
+		head.Segment = getAdminAuditSegmentPath(logPath, entry.Seq)
+		head.Entries = 1
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	buffer.Write(entryBytes)
+	buffer.WriteByte('\n')
+	if _, err = objAPI.PutObject(minioMetaBucket, head.Segment, int64(buffer.Len()), &buffer, nil, ""); err != nil {
+		return errorCause(err)
+	}
+
+	head.Seq = entry.Seq
+	head.Hash = entry.Hash
+	head.MAC = head.computeMAC(key)
+	headBytes, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	_, err = objAPI.PutObject(minioMetaBucket, adminAuditHeadPath, int64(len(headBytes)), bytes.NewReader(headBytes), nil, "")
+	return errorCause(err)
+}
+
+// auditAdminHandler - records every call of h into the admin audit
+// log as operation, e.g "service.restart". Requests failing signature
+// verification are not recorded, so that anonymous clients cannot
+// flood the log.
+func auditAdminHandler(operation string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Bodies are read whole for signature verification, before
+		// the client is known, so their size is capped.
+		if r.ContentLength > maxAdminRequestSize {
+			writeErrorResponse(w, ErrEntityTooLarge, r)
+			return
+		}
+		r.Body = ioutil.NopCloser(io.LimitReader(r.Body, maxAdminRequestSize))
+
+		if !isAdminRequestAuthenticated(r) {
+			h(w, r)
+			return
+		}
+
+		// The body was read by the signature verification already.
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeErrorResponse(w, ErrInternalError, r)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
+
+		entry := adminAuditEntry{
+			Time:          time.Now().UTC(),
+			AccessKey:     getRequestAccessKeyV4(r),
+			RemoteHost:    r.RemoteAddr,
+			Operation:     operation,
+			PayloadSHA256: getSHA256Hash(payload),
+		}
+
+		rec := &httpResponseRecorder{ResponseWriter: w, respStatusCode: http.StatusOK}
+		h(rec, r)
+		entry.Status = rec.respStatusCode
+
+		// Successful operations carrying an approval token were
+		// approved with it.
+		if entry.Status < http.StatusMultipleChoices {
+			entry.Approver = getRequestApprover(r)
+		}
+
+		objAPI := newObjectLayerFn()
+		if objAPI == nil {
+			return
+		}
+		errorIf(appendAdminAuditEntry(objAPI, entry), "Unable to record admin audit entry for %s.", entry.Operation)
+	}
+}
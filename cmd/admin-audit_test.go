@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling admin audit log tests for both XL multiple disks and single node setup.
+func TestAppendAdminAuditEntry(t *testing.T) {
+	ExecObjectLayerTest(t, testAppendAdminAuditEntry)
+}
+
+// Tests appending to and verifying the admin audit log.
+func testAppendAdminAuditEntry(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	now := time.Now().UTC()
+	yesterday := now.Add(-24 * time.Hour)
+
+	auditLog, err := getAdminAuditLog(obj, now)
+	if err != nil {
+		t.Fatalf("%s: Unable to read empty audit log: %v", instanceType, err)
+	}
+	if len(auditLog.Entries) != 0 || auditLog.BrokenAt != -1 {
+		t.Fatalf("%s: Expected no entries, got %d broken at %d", instanceType, len(auditLog.Entries), auditLog.BrokenAt)
+	}
+
+	// The chain continues from the last entry of yesterday.
+	operations := []string{"service.status", "config.set", "heal.object"}
+	for i, operation := range operations {
+		entry := adminAuditEntry{
+			Time:          now,
+			AccessKey:     "ADMINACCESSKEY",
+			Operation:     operation,
+			PayloadSHA256: getSHA256Hash(nil),
+			Status:        http.StatusOK,
+		}
+		if i == 0 {
+			entry.Time = yesterday
+		}
+		if err = appendAdminAuditEntry(obj, entry); err != nil {
+			t.Fatalf("%s: Unable to append audit entry: %v", instanceType, err)
+		}
+	}
+
+	auditLog, err = getAdminAuditLog(obj, now)
+	if err != nil {
+		t.Fatalf("%s: Unable to read audit log: %v", instanceType, err)
+	}
+	entries := auditLog.Entries
+	if len(entries) != len(operations)-1 {
+		t.Fatalf("%s: Expected %d entries, got %d", instanceType, len(operations)-1, len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Operation != operations[i+1] {
+			t.Errorf("%s: Expected entry %d to be %s, got %s", instanceType, i, operations[i+1], entry.Operation)
+		}
+	}
+	if auditLog.BrokenAt != -1 {
+		t.Fatalf("%s: Expected an intact audit log, broken at %d", instanceType, auditLog.BrokenAt)
+	}
+	prev, err := readPrevAdminAuditEntry(obj, getAdminAuditLogPath(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev.Operation != operations[0] || entries[0].PrevHash != prev.Hash {
+		t.Fatalf("%s: Expected the log to continue from %#v", instanceType, prev)
+	}
+
+	// Modifying an entry breaks the chain at that entry.
+	modified := append([]adminAuditEntry(nil), entries...)
+	modified[1].Status = http.StatusForbidden
+	if brokenAt := verifyAdminAuditLog(prev, modified); brokenAt != 1 {
+		t.Errorf("%s: Expected the audit log to be broken at 1, got %d", instanceType, brokenAt)
+	}
+
+	// Rehashing a modified entry without the key breaks the chain too.
+	modified[1].Hash = modified[1].computeHash([]byte("wrongsecretkey"))
+	if brokenAt := verifyAdminAuditLog(prev, modified); brokenAt != 1 {
+		t.Errorf("%s: Expected the audit log to be broken at 1, got %d", instanceType, brokenAt)
+	}
+
+	// Removing an entry breaks the chain at the next entry.
+	if brokenAt := verifyAdminAuditLog(prev, entries[1:]); brokenAt != 0 {
+		t.Errorf("%s: Expected the audit log to be broken at 0, got %d", instanceType, brokenAt)
+	}
+	if brokenAt := verifyAdminAuditLog(adminAuditEntry{}, entries); brokenAt != 0 {
+		t.Errorf("%s: Expected the audit log to be broken at 0, got %d", instanceType, brokenAt)
+	}
+
+	// Entries fill segments of adminAuditSegmentSize entries.
+	for i := 0; i < adminAuditSegmentSize; i++ {
+		entry := adminAuditEntry{Time: now, Operation: "service.status", Status: http.StatusOK}
+		if err = appendAdminAuditEntry(obj, entry); err != nil {
+			t.Fatalf("%s: Unable to append audit entry: %v", instanceType, err)
+		}
+	}
+	segments, err := listAdminAuditEntries(obj, getAdminAuditLogPath(now)+slashSeparator, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("%s: Expected 2 segments, got %v", instanceType, segments)
+	}
+	auditLog, err = getAdminAuditLog(obj, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(entries) + adminAuditSegmentSize; len(auditLog.Entries) != n || auditLog.BrokenAt != -1 {
+		t.Fatalf("%s: Expected %d intact entries, got %d broken at %d", instanceType, n, len(auditLog.Entries), auditLog.BrokenAt)
+	}
+
+	// Removing the last entry is detected from the head.
+	head, err := readAdminAuditHead(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segment, err := readAdminAuditSegment(obj, head.Segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	for _, entry := range segment[:len(segment)-1] {
+		entryBytes, merr := json.Marshal(entry)
+		if merr != nil {
+			t.Fatal(merr)
+		}
+		buffer.Write(entryBytes)
+		buffer.WriteByte('\n')
+	}
+	if _, err = obj.PutObject(minioMetaBucket, head.Segment, int64(buffer.Len()), &buffer, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	auditLog, err = getAdminAuditLog(obj, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(auditLog.Entries); auditLog.BrokenAt != n {
+		t.Errorf("%s: Expected the audit log to be broken at %d, got %d", instanceType, n, auditLog.BrokenAt)
+	}
+}
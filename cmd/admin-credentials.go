@@ -0,0 +1,177 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Admin API actions, each admin operation belongs to one of them.
+const (
+	adminActionAll          = "*"
+	adminActionService      = "service"
+	adminActionCredentials  = "credentials"
+	adminActionInfo         = "info"
+	adminActionLock         = "lock"
+	adminActionHeal         = "heal"
+	adminActionConfig       = "config"
+	adminActionPolicy       = "policy"
+	adminActionBucketConfig = "bucket-config"
+	adminActionAdopt        = "adopt"
+	adminActionAudit        = "audit"
+)
+
+// validAdminActions - all actions allowed in admin credentials.
+var validAdminActions = map[string]bool{
+	adminActionAll:          true,
+	adminActionService:      true,
+	adminActionCredentials:  true,
+	adminActionInfo:         true,
+	adminActionLock:         true,
+	adminActionHeal:         true,
+	adminActionConfig:       true,
+	adminActionPolicy:       true,
+	adminActionBucketConfig: true,
+	adminActionAdopt:        true,
+	adminActionAudit:        true,
+}
+
+// adminCredentialConfig - access keys for the admin API only, separate
+// from the S3 credential. Admin operations not listed in actions are
+// denied.
+type adminCredentialConfig struct {
+	AccessKey string   `json:"accessKey"`
+	SecretKey string   `json:"secretKey"`
+	Actions   []string `json:"actions"`
+}
+
+// adminCredential - compiled form of adminCredentialConfig.
+type adminCredential struct {
+	cred    credential
+	actions map[string]bool
+}
+
+// isAllowed - returns true if the credential may perform action.
+func (c adminCredential) isAllowed(action string) bool {
+	return c.actions[adminActionAll] || c.actions[action]
+}
+
+// adminCredentials - admin credentials by access key.
+type adminCredentials map[string]adminCredential
+
+// newAdminCredentials - validates and compiles configs, returns nil if
+// no admin credentials are configured.
+func newAdminCredentials(configs []adminCredentialConfig, serverCred credential) (adminCredentials, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	creds := make(adminCredentials)
+	for _, config := range configs {
+		if err := validateAuthKeys(config.AccessKey, config.SecretKey); err != nil {
+			return nil, err
+		}
+		if config.AccessKey == serverCred.AccessKey {
+			return nil, fmt.Errorf("Access key %s is already the server access key", config.AccessKey)
+		}
+		if _, ok := creds[config.AccessKey]; ok {
+			return nil, fmt.Errorf("Duplicate access key %s", config.AccessKey)
+		}
+		actions := make(map[string]bool)
+		for _, action := range config.Actions {
+			if !validAdminActions[action] {
+				return nil, fmt.Errorf("Unknown admin action %s for access key %s", action, config.AccessKey)
+			}
+			actions[action] = true
+		}
+		creds[config.AccessKey] = adminCredential{
+			cred:    newCredentialWithKeys(config.AccessKey, config.SecretKey),
+			actions: actions,
+		}
+	}
+	return creds, nil
+}
+
+// getRequestAccessKeyV4 - returns the access key of a signature V4
+// signed or presigned request, empty string otherwise.
+func getRequestAccessKeyV4(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned:
+		signV4Values, s3Error := parseSignV4(r.Header.Get("Authorization"))
+		if s3Error == ErrNone {
+			return signV4Values.Credential.accessKey
+		}
+	case authTypePresigned:
+		preSignValues, s3Error := parsePreSignV4(r.URL.Query())
+		if s3Error == ErrNone {
+			return preSignValues.Credential.accessKey
+		}
+	}
+	return ""
+}
+
+// checkAdminRequestAuthType - authenticates an admin API request for
+// action. Requests signed with the server credential may perform every
+// action, requests signed with an admin credential only the actions it
+// lists.
+func checkAdminRequestAuthType(r *http.Request, action string) APIErrorCode {
+	adminCred, ok := globalAdminCredentials[getRequestAccessKeyV4(r)]
+	if !ok {
+		return checkRequestAuthType(r, "", "", "")
+	}
+	if s3Error := isReqAuthenticatedWithCred(r, "", adminCred.cred); s3Error != ErrNone {
+		if s3Error != ErrSlowDown {
+			errorIf(errSignatureMismatch, "%s", dumpRequest(r))
+		}
+		return s3Error
+	}
+	if !adminCred.isAllowed(action) {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// checkRootAdminRequestAuthType - authenticates an admin API request
+// only the server credential may perform, such as one replying with
+// credentials, admin credentials are denied whatever their actions.
+func checkRootAdminRequestAuthType(r *http.Request) APIErrorCode {
+	if _, ok := globalAdminCredentials[getRequestAccessKeyV4(r)]; ok {
+		return ErrAccessDenied
+	}
+	return checkRequestAuthType(r, "", "", "")
+}
+
+// isAdminRequestAuthenticated - returns true if the request is signed
+// with the server credential or an admin credential, regardless of
+// the actions allowed.
+func isAdminRequestAuthenticated(r *http.Request) bool {
+	if adminCred, ok := globalAdminCredentials[getRequestAccessKeyV4(r)]; ok {
+		return isReqAuthenticatedWithCred(r, "", adminCred.cred) == ErrNone
+	}
+	return checkRequestAuthType(r, "", "", "") == ErrNone
+}
+
+// initAdminCredentials - initializes the global admin credentials from
+// server config.
+func initAdminCredentials() error {
+	creds, err := newAdminCredentials(serverConfig.GetAdminCredentials(), serverConfig.GetCredential())
+	if err != nil {
+		return err
+	}
+	globalAdminCredentials = creds
+	return nil
+}
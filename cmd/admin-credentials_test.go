@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validating admin credentials configuration.
+func TestNewAdminCredentials(t *testing.T) {
+	serverCred := newCredentialWithKeys("SERVERACCESSKEY", "serversecretkey")
+	testCases := []struct {
+		configs    []adminCredentialConfig
+		expectNil  bool
+		shouldPass bool
+	}{
+		// Nothing configured.
+		{nil, true, true},
+		// Valid admin credential.
+		{[]adminCredentialConfig{{"ADMINACCESSKEY", "adminsecretkey", []string{"heal", "config"}}}, false, true},
+		// Valid admin credential without actions, denied everything.
+		{[]adminCredentialConfig{{"ADMINACCESSKEY", "adminsecretkey", nil}}, false, true},
+		// Invalid access key.
+		{[]adminCredentialConfig{{"AK", "adminsecretkey", nil}}, false, false},
+		// Invalid secret key.
+		{[]adminCredentialConfig{{"ADMINACCESSKEY", "sk", nil}}, false, false},
+		// Same as the server access key.
+		{[]adminCredentialConfig{{"SERVERACCESSKEY", "adminsecretkey", nil}}, false, false},
+		// Duplicate access key.
+		{[]adminCredentialConfig{
+			{"ADMINACCESSKEY", "adminsecretkey", nil},
+			{"ADMINACCESSKEY", "othersecretkey", nil},
+		}, false, false},
+		// Unknown action.
+		{[]adminCredentialConfig{{"ADMINACCESSKEY", "adminsecretkey", []string{"s3:GetObject"}}}, false, false},
+	}
+	for i, testCase := range testCases {
+		creds, err := newAdminCredentials(testCase.configs, serverCred)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if testCase.shouldPass && (creds == nil) != testCase.expectNil {
+			t.Errorf("Test %d: Expected nil admin credentials to be %v", i+1, testCase.expectNil)
+		}
+	}
+}
+
+// Tests admin credential actions.
+func TestAdminCredentialIsAllowed(t *testing.T) {
+	creds, err := newAdminCredentials([]adminCredentialConfig{
+		{"HEALACCESSKEY", "healsecretkey", []string{adminActionHeal}},
+		{"ROOTACCESSKEY", "rootsecretkey", []string{adminActionAll}},
+	}, newCredentialWithKeys("SERVERACCESSKEY", "serversecretkey"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		accessKey string
+		action    string
+		allowed   bool
+	}{
+		{"HEALACCESSKEY", adminActionHeal, true},
+		{"HEALACCESSKEY", adminActionConfig, false},
+		{"HEALACCESSKEY", adminActionCredentials, false},
+		{"ROOTACCESSKEY", adminActionConfig, true},
+	}
+	for i, testCase := range testCases {
+		if allowed := creds[testCase.accessKey].isAllowed(testCase.action); allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %s to be allowed %v, got %v", i+1, testCase.action, testCase.allowed, allowed)
+		}
+	}
+}
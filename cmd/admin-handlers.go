@@ -21,16 +21,27 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/pkg/disk"
 )
 
 const (
 	minioAdminOpHeader   = "X-Minio-Operation"
 	minioConfigTmpFormat = "config-%s.json"
+
+	// Set on get-config responses if some nodes failed to reply.
+	minioAdminPartialHeader     = "X-Minio-Partial"
+	minioAdminFailedNodesHeader = "X-Minio-Failed-Nodes"
 )
 
 // Type-safe query params.
@@ -45,7 +56,22 @@ const (
 	mgmtDelimiter    mgmtQueryKey = "delimiter"
 	mgmtMarker       mgmtQueryKey = "marker"
 	mgmtMaxKey       mgmtQueryKey = "max-key"
+	mgmtTotal        mgmtQueryKey = "total"
+	mgmtNode         mgmtQueryKey = "node"
 	mgmtDryRun       mgmtQueryKey = "dry-run"
+	mgmtAccessKey    mgmtQueryKey = "accessKey"
+	mgmtAction       mgmtQueryKey = "action"
+	mgmtReferer      mgmtQueryKey = "referer"
+	mgmtBefore       mgmtQueryKey = "before"
+	mgmtID           mgmtQueryKey = "id"
+	mgmtOperation    mgmtQueryKey = "operation"
+	mgmtEnable       mgmtQueryKey = "enable"
+	mgmtAllow        mgmtQueryKey = "allow"
+	mgmtDeny         mgmtQueryKey = "deny"
+	mgmtToken        mgmtQueryKey = "token"
+
+	mgmtMaxConcurrent        mgmtQueryKey = "maxConcurrent"
+	mgmtMaxRequestsPerSecond mgmtQueryKey = "maxRequestsPerSecond"
 )
 
 // ServerVersion - server version
@@ -58,6 +84,10 @@ type ServerVersion struct {
 type ServerStatus struct {
 	ServerVersion ServerVersion `json:"serverVersion"`
 	Uptime        time.Duration `json:"uptime"`
+	// Partial is set if some servers failed to reply, the status
+	// is then computed from the servers that did.
+	Partial     bool          `json:"partial"`
+	FailedNodes []nodeSummary `json:"failedNodes,omitempty"`
 }
 
 // ServiceStatusHandler - GET /?service
@@ -66,9 +96,9 @@ type ServerStatus struct {
 // Fetches server status information like total disk space available
 // to use, online disks, offline disks and quorum threshold.
 func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionService)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -77,23 +107,26 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 
 	// Fetch uptimes from all peers. This may fail to due to lack
 	// of read-quorum availability.
-	uptime, err := getPeerUptimes(globalAdminPeers)
+	uptime, errs, err := getPeerUptimes(globalAdminPeers)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		errorIf(err, "Possibly failed to get uptime from majority of servers.")
 		return
 	}
 
 	// Create API response
+	failedNodes := getFailedNodes(globalAdminPeers, errs)
 	serverStatus := ServerStatus{
 		ServerVersion: serverVersion,
 		Uptime:        uptime,
+		Partial:       len(failedNodes) > 0,
+		FailedNodes:   failedNodes,
 	}
 
 	// Marshal API response
 	jsonBytes, err := json.Marshal(serverStatus)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		errorIf(err, "Failed to marshal storage info into json.")
 		return
 	}
@@ -108,9 +141,9 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 // Restarts minio server gracefully. In a distributed setup,  restarts
 // all the servers in the cluster.
 func (adminAPI adminAPIHandlers) ServiceRestartHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionService)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -133,23 +166,23 @@ type setCredsReq struct {
 // in the cluster.
 func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter, r *http.Request) {
 	// Authenticate request
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	// Avoid setting new credentials when they are already passed
 	// by the environment.
 	if globalIsEnvCreds {
-		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		writeErrorResponse(w, ErrMethodNotAllowed, r)
 		return
 	}
 
 	// Load request body
 	inputData, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		return
 	}
 
@@ -158,16 +191,35 @@ func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter
 	err = xml.Unmarshal(inputData, &req)
 	if err != nil {
 		errorIf(err, "Cannot unmarshal credentials request")
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		writeErrorResponse(w, ErrMalformedXML, r)
 		return
 	}
 
 	// Check passed credentials
 	err = validateAuthKeys(req.Username, req.Password)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Server access key must not shadow an admin access key or the
+	// access key of an IAM user.
+	if _, ok := globalAdminCredentials[req.Username]; ok {
+		writeErrorResponse(w, ErrAdminInvalidAccessKey, r)
+		return
+	}
+	if _, ok := getIAMUser(req.Username); ok {
+		writeErrorResponse(w, ErrAdminInvalidAccessKey, r)
+		return
+	}
+
+	approvalDone, adminAPIErr := checkApproval(newObjectLayerFn(), r, "service.set-credentials", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
+	approved := false
+	defer func() { approvalDone(approved) }()
 
 	creds := credential{
 		AccessKey: req.Username,
@@ -183,10 +235,14 @@ func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter
 	// Update local credentials in memory.
 	serverConfig.SetCredential(creds)
 	if err = serverConfig.Save(); err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		return
 	}
 
+	approved = true
+	raiseAlert(alertCredentialsChanged, "", "Credentials changed, new access key %s", creds.AccessKey)
+	serverEventNotify(ServerEventConfigChanged, "credential", "Credentials changed, new access key %s", creds.AccessKey)
+
 	// At this stage, the operation is successful, return 200 OK
 	w.WriteHeader(http.StatusOK)
 }
@@ -199,6 +255,7 @@ type ServerProperties struct {
 	CommitID string        `json:"commitID"`
 	Region   string        `json:"region"`
 	SQSARN   []string      `json:"sqsARN"`
+	TLSMode  string        `json:"tlsMode"`
 }
 
 // ServerConnStats holds transferred bytes from/to the server
@@ -208,31 +265,46 @@ type ServerConnStats struct {
 	Throughput       uint64 `json:"throughput,omitempty"`
 }
 
-// ServerInfo holds the information that will be returned by ServerInfo API
-type ServerInfo struct {
+// ServerDiskInfo holds the status of a disk local to a server.
+type ServerDiskInfo struct {
+	Path   string `json:"path"`
+	Online bool   `json:"online"`
+	Total  int64  `json:"total,omitempty"`
+	Free   int64  `json:"free,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ServerInfoData holds the information of a single server.
+type ServerInfoData struct {
 	StorageInfo StorageInfo      `json:"storage"`
 	ConnStats   ServerConnStats  `json:"network"`
 	Properties  ServerProperties `json:"server"`
+	Disks       []ServerDiskInfo `json:"disks"`
+	// Deep scrub progress, nil if deep scrubbing is disabled.
+	Scrub *ScrubInfo `json:"scrub,omitempty"`
+	// Stale data removed, nil if the janitor is disabled.
+	Janitor *JanitorInfo `json:"janitor,omitempty"`
+	// Event counters of the notification targets.
+	NotifyTargets []NotifyTargetStats `json:"notifyTargets,omitempty"`
+	// Disks of the failure domains, if configured.
+	FailureDomains []FailureDomainInfo `json:"failureDomains,omitempty"`
 }
 
-// ServerInfoHandler - GET /?server-info
-// ----------
-// Get server information
-func (adminAPI adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Request) {
-	// Authenticate request
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
-	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
-		return
-	}
+// ServerInfo holds the information of a single server returned by
+// ServerInfo API, Data is nil if the server could not be reached.
+type ServerInfo struct {
+	Addr   string          `json:"addr"`
+	Online bool            `json:"online"`
+	Error  string          `json:"error,omitempty"`
+	Data   *ServerInfoData `json:"data,omitempty"`
+}
 
-	// Build storage info
+// getLocalServerInfoData - returns the information of this server.
+func getLocalServerInfoData() (ServerInfoData, error) {
 	objLayer := newObjectLayerFn()
-	if objLayer == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
-		return
+	if objLayer == nil || globalBootTime.IsZero() {
+		return ServerInfoData{}, errServerNotInitialized
 	}
-	storage := objLayer.StorageInfo()
 
 	// Build list of enabled ARNs queues
 	var arns []string
@@ -240,46 +312,86 @@ func (adminAPI adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *htt
 		arns = append(arns, queueArn)
 	}
 
-	// Fetch uptimes from all peers. This may fail to due to lack
-	// of read-quorum availability.
-	uptime, err := getPeerUptimes(globalAdminPeers)
-	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-		errorIf(err, "Unable to get uptime from majority of servers.")
-		return
-	}
+	// Build status of local disks
+	var disks []ServerDiskInfo
+	for _, ep := range globalEndpoints {
+		if !isLocalStorage(ep) {
+			continue
+		}
+		diskInfo := ServerDiskInfo{Path: getPath(ep)}
+		info, err := disk.GetInfo(diskInfo.Path)
+		if err != nil {
+			diskInfo.Error = err.Error()
+		} else {
+			diskInfo.Online = true
+			diskInfo.Total = info.Total
+			diskInfo.Free = info.Free
+		}
+		disks = append(disks, diskInfo)
+	}
+
+	var scrub *ScrubInfo
+	if globalXLScrubber != nil {
+		info := globalXLScrubber.Info()
+		scrub = &info
+	}
+
+	var janitor *JanitorInfo
+	if globalJanitor != nil {
+		info := globalJanitor.Info()
+		janitor = &info
+	}
+
+	return ServerInfoData{
+		StorageInfo: objLayer.StorageInfo(),
+		ConnStats: ServerConnStats{
+			TotalInputBytes:  globalConnStats.getTotalInputBytes(),
+			TotalOutputBytes: globalConnStats.getTotalOutputBytes(),
+		},
+		Properties: ServerProperties{
+			Version:  Version,
+			CommitID: CommitID,
+			Region:   serverConfig.GetRegion(),
+			SQSARN:   arns,
+			Uptime:   time.Now().UTC().Sub(globalBootTime),
+			TLSMode:  getTLSMode(),
+		},
+		Disks:          disks,
+		Scrub:          scrub,
+		Janitor:        janitor,
+		NotifyTargets:  getNotifyTargetStats(arns),
+		FailureDomains: globalFailureDomains,
+	}, nil
+}
 
-	// Build server properties information
-	properties := ServerProperties{
-		Version:  Version,
-		CommitID: CommitID,
-		Region:   serverConfig.GetRegion(),
-		SQSARN:   arns,
-		Uptime:   uptime,
+// ServerInfoHandler - GET /?info
+// ----------
+// Get information of every server in the setup. Servers are queried
+// concurrently, a server which cannot be reached in time is reported
+// as offline instead of failing the whole request.
+func (adminAPI adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Request) {
+	// Authenticate request
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionInfo)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
 	}
 
-	// Build network info
-	connStats := ServerConnStats{
-		TotalInputBytes:  globalConnStats.getTotalInputBytes(),
-		TotalOutputBytes: globalConnStats.getTotalOutputBytes(),
+	if newObjectLayerFn() == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
 	}
 
-	// Build the whole returned information
-	info := ServerInfo{
-		StorageInfo: storage,
-		ConnStats:   connStats,
-		Properties:  properties,
-	}
+	infos := getPeerServerInfo(globalAdminPeers, adminPeerInfoTimeout)
 
 	// Marshal API response
-	jsonBytes, err := json.Marshal(info)
+	jsonBytes, err := json.Marshal(infos)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal storage info into json.")
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal server info into json.")
 		return
 	}
-	// Reply with storage information (across nodes in a
-	// distributed setup) as json.
+	// Reply with information of all servers as json.
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
@@ -312,23 +424,100 @@ func validateLockQueryParams(vars url.Values) (string, string, time.Duration, AP
 	return bucket, prefix, duration, ErrNone
 }
 
-// ListLocksHandler - GET /?lock&bucket=mybucket&prefix=myprefix&duration=duration
+// listLocksResponse - list locks API response, locks are listed by
+// object name one page at a time.
+type listLocksResponse struct {
+	Locks []VolumeLockInfo `json:"locks"`
+	// Number of locked objects matching the request, over all pages.
+	Total int `json:"total"`
+	// When IsTruncated is true NextMarker is the marker of the next
+	// page.
+	IsTruncated bool   `json:"isTruncated"`
+	NextMarker  string `json:"nextMarker,omitempty"`
+}
+
+// validateListLocksPageParams - Validates pagination query params of
+// list locks management API, max-key defaults to and is capped at
+// maxObjectList.
+func validateListLocksPageParams(vars url.Values) (string, int, APIErrorCode) {
+	marker := vars.Get(string(mgmtMarker))
+	maxKeyStr := vars.Get(string(mgmtMaxKey))
+	if maxKeyStr == "" {
+		return marker, maxObjectList, ErrNone
+	}
+	maxKey, err := strconv.Atoi(maxKeyStr)
+	if err != nil || maxKey < 0 {
+		return "", 0, ErrInvalidMaxKeys
+	}
+	if maxKey > maxObjectList {
+		maxKey = maxObjectList
+	}
+	return marker, maxKey, ErrNone
+}
+
+// byLockObject is a collection satisfying sort.Interface.
+type byLockObject []VolumeLockInfo
+
+func (l byLockObject) Len() int           { return len(l) }
+func (l byLockObject) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byLockObject) Less(i, j int) bool { return l[i].Object < l[j].Object }
+
+// paginateLocks - returns the page of volLocks of at most maxKey
+// objects following marker. All locks of an object are returned in
+// the same page.
+func paginateLocks(volLocks []VolumeLockInfo, marker string, maxKey int) listLocksResponse {
+	sort.Stable(byLockObject(volLocks))
+
+	resp := listLocksResponse{Locks: []VolumeLockInfo{}}
+	var objects int
+	for i, volLock := range volLocks {
+		if i > 0 && volLocks[i-1].Object == volLock.Object {
+			if objects <= maxKey && volLock.Object > marker {
+				resp.Locks = append(resp.Locks, volLock)
+			}
+			continue
+		}
+		resp.Total++
+		if volLock.Object <= marker {
+			continue
+		}
+		objects++
+		if objects > maxKey {
+			resp.IsTruncated = true
+			continue
+		}
+		resp.Locks = append(resp.Locks, volLock)
+		resp.NextMarker = volLock.Object
+	}
+	if !resp.IsTruncated {
+		resp.NextMarker = ""
+	}
+	return resp
+}
+
+// ListLocksHandler - GET /?lock&bucket=mybucket&prefix=myprefix&duration=duration&marker=mymarker&max-key=1000
 // - bucket is a mandatory query parameter
-// - prefix and older-than are optional query parameters
+// - prefix, duration, marker and max-key are optional query parameters
 // HTTP header x-minio-operation: list
 // ---------
-// Lists locks held on a given bucket, prefix and duration it was held for.
+// Lists locks held on a given bucket, prefix and duration it was
+// held for, upto max-key objects after marker.
 func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionLock)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	vars := r.URL.Query()
 	bucket, prefix, duration, adminAPIErr := validateLockQueryParams(vars)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+	marker, maxKey, adminAPIErr := validateListLocksPageParams(vars)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -336,15 +525,15 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	// are available for longer than duration.
 	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, duration)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		errorIf(err, "Failed to fetch lock information from remote nodes.")
 		return
 	}
 
-	// Marshal list of locks as json.
-	jsonBytes, err := json.Marshal(volLocks)
+	// Marshal the requested page of locks as json.
+	jsonBytes, err := json.Marshal(paginateLocks(volLocks, marker, maxKey))
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		errorIf(err, "Failed to marshal lock information into json.")
 		return
 	}
@@ -354,49 +543,101 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
-// ClearLocksHandler - POST /?lock&bucket=mybucket&prefix=myprefix&duration=duration
+// clearLocksResponse - clear locks API response.
+type clearLocksResponse struct {
+	// Number of objects whose locks were cleared.
+	Cleared int `json:"cleared"`
+}
+
+// isAdminPeer - returns true if addr is the address of one of the
+// servers of this deployment.
+func isAdminPeer(peers adminPeers, addr string) bool {
+	for _, peer := range peers {
+		if peer.addr == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearLocksHandler - POST /?lock&bucket=mybucket&prefix=myprefix&duration=duration&node=host:port
 // - bucket is a mandatory query parameter
-// - prefix and older-than are optional query parameters
+// - prefix, duration and node are optional query parameters
 // HTTP header x-minio-operation: clear
 // ---------
-// Clear locks held on a given bucket, prefix and duration it was held for.
+// Clear locks held on a given bucket, prefix and duration it was held
+// for. If node is set only the locks held by that server, which may be
+// offline, are cleared from the lock servers of all other servers.
 func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionLock)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	vars := r.URL.Query()
 	bucket, prefix, duration, adminAPIErr := validateLockQueryParams(vars)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
-	// Fetch lock information of locks matching bucket/prefix that
-	// are held for longer than duration.
-	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, duration)
-	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to fetch lock information from remote nodes.")
+	approvalDone, adminAPIErr := checkApproval(newObjectLayerFn(), r, "lock.clear", bucket)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
-
-	// Marshal list of locks as json.
-	jsonBytes, err := json.Marshal(volLocks)
+	approved := false
+	defer func() { approvalDone(approved) }()
+
+	var resp clearLocksResponse
+	if node := vars.Get(string(mgmtNode)); node != "" {
+		if !isAdminPeer(globalAdminPeers, node) {
+			writeErrorResponse(w, ErrAdminInvalidNode, r)
+			return
+		}
+
+		// Remove locks held by node matching bucket/prefix held
+		// longer than duration.
+		cleared, err := clearPeerNodeLocks(globalAdminPeers, node, bucket, prefix, duration)
+		if err != nil {
+			writeErrorResponse(w, ErrInternalError, r)
+			errorIf(err, "Failed to clear locks held by %s.", node)
+			return
+		}
+		resp.Cleared = cleared
+	} else {
+		// Fetch lock information of locks matching bucket/prefix that
+		// are held for longer than duration.
+		volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, duration)
+		if err != nil {
+			writeErrorResponse(w, ErrInternalError, r)
+			errorIf(err, "Failed to fetch lock information from remote nodes.")
+			return
+		}
+
+		// Remove lock matching bucket/prefix held longer than duration.
+		cleared := make(map[nsParam]struct{})
+		for _, volLock := range volLocks {
+			param := nsParam{volume: volLock.Bucket, path: volLock.Object}
+			if _, ok := cleared[param]; ok {
+				continue
+			}
+			globalNSMutex.ForceUnlock(volLock.Bucket, volLock.Object)
+			cleared[param] = struct{}{}
+		}
+		resp.Cleared = len(cleared)
+	}
+	approved = true
+
+	jsonBytes, err := json.Marshal(resp)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal lock information into json.")
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal clear locks response into json.")
 		return
 	}
 
-	// Remove lock matching bucket/prefix held longer than duration.
-	for _, volLock := range volLocks {
-		globalNSMutex.ForceUnlock(volLock.Bucket, volLock.Object)
-	}
-
-	// Reply with list of locks cleared, as json.
+	// Reply with the count of locks cleared, as json.
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
@@ -418,10 +659,17 @@ func validateHealQueryParams(vars url.Values) (string, string, string, string, i
 		return "", "", "", "", 0, ErrInvalidObjectName
 	}
 
-	// check if maxKey is a valid integer.
-	maxKey, err := strconv.Atoi(maxKeyStr)
-	if err != nil {
-		return "", "", "", "", 0, ErrInvalidMaxKeys
+	// check if maxKey is a valid integer, it defaults to and is
+	// capped at maxObjectList.
+	maxKey := maxObjectList
+	if maxKeyStr != "" {
+		var err error
+		if maxKey, err = strconv.Atoi(maxKeyStr); err != nil {
+			return "", "", "", "", 0, ErrInvalidMaxKeys
+		}
+		if maxKey > maxObjectList {
+			maxKey = maxObjectList
+		}
 	}
 
 	// Validate prefix, marker, delimiter and maxKey.
@@ -433,22 +681,50 @@ func validateHealQueryParams(vars url.Values) (string, string, string, string, i
 	return bucket, prefix, marker, delimiter, maxKey, ErrNone
 }
 
-// ListObjectsHealHandler - GET /?heal&bucket=mybucket&prefix=myprefix&marker=mymarker&delimiter=&mydelimiter&maxKey=1000
+// listObjectsHealResponse - list objects heal response, carrying the
+// count of objects needing heal over all pages when requested.
+type listObjectsHealResponse struct {
+	ListObjectsResponse
+	TotalCount *int `xml:"TotalCount,omitempty"`
+}
+
+// countObjectsHeal - returns the number of objects and prefixes
+// needing heal in bucket matching prefix, by listing all of them.
+func countObjectsHeal(objLayer ObjectLayer, bucket, prefix, delimiter string) (int, error) {
+	var count int
+	marker := ""
+	for {
+		objectInfos, err := objLayer.ListObjectsHeal(bucket, prefix, marker, delimiter, maxObjectList)
+		if err != nil {
+			return 0, err
+		}
+		count += len(objectInfos.Objects) + len(objectInfos.Prefixes)
+		if !objectInfos.IsTruncated {
+			return count, nil
+		}
+		marker = objectInfos.NextMarker
+	}
+}
+
+// ListObjectsHealHandler - GET /?heal&bucket=mybucket&prefix=myprefix&marker=mymarker&delimiter=&mydelimiter&max-key=1000&total=true
 // - bucket is mandatory query parameter
-// - rest are optional query parameters
-// List upto maxKey objects that need healing in a given bucket matching the given prefix.
+// - rest are optional query parameters, max-key defaults to 1000
+// List upto maxKey objects that need healing in a given bucket
+// matching the given prefix. With total=true the response also
+// carries the count of objects needing heal over all pages, which
+// requires listing all of them.
 func (adminAPI adminAPIHandlers) ListObjectsHealHandler(w http.ResponseWriter, r *http.Request) {
 	// Get object layer instance.
 	objLayer := newObjectLayerFn()
 	if objLayer == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -456,18 +732,29 @@ func (adminAPI adminAPIHandlers) ListObjectsHealHandler(w http.ResponseWriter, r
 	vars := r.URL.Query()
 	bucket, prefix, marker, delimiter, maxKey, adminAPIErr := validateHealQueryParams(vars)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	// Get the list objects to be healed.
 	objectInfos, err := objLayer.ListObjectsHeal(bucket, prefix, marker, delimiter, maxKey)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
-	listResponse := generateListObjectsV1Response(bucket, prefix, marker, delimiter, maxKey, objectInfos)
+	listResponse := listObjectsHealResponse{
+		ListObjectsResponse: generateListObjectsV1Response(bucket, prefix, marker, delimiter, maxKey, objectInfos),
+	}
+	if vars.Get(string(mgmtTotal)) == "true" {
+		total, err := countObjectsHeal(objLayer, bucket, prefix, delimiter)
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r)
+			return
+		}
+		listResponse.TotalCount = &total
+	}
+
 	// Write success response.
 	writeSuccessResponseXML(w, encodeResponse(listResponse))
 }
@@ -477,21 +764,21 @@ func (adminAPI adminAPIHandlers) ListBucketsHealHandler(w http.ResponseWriter, r
 	// Get object layer instance.
 	objLayer := newObjectLayerFn()
 	if objLayer == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	// Get the list buckets to be healed.
 	bucketsInfo, err := objLayer.ListBucketsHeal()
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -500,6 +787,55 @@ func (adminAPI adminAPIHandlers) ListBucketsHealHandler(w http.ResponseWriter, r
 	writeSuccessResponseXML(w, encodeResponse(listResponse))
 }
 
+// ListUnicodeDuplicatesHandler - GET /?heal&bucket=mybucket&prefix=myprefix
+// - bucket is mandatory query parameter
+// - prefix is an optional query parameter
+// Lists objects in a given bucket matching the given prefix whose names
+// differ only in Unicode normalization, e.g. NFC and NFD forms.
+func (adminAPI adminAPIHandlers) ListUnicodeDuplicatesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	prefix := vars.Get(string(mgmtPrefix))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+	if !IsValidObjectPrefix(prefix) {
+		writeErrorResponse(w, ErrInvalidObjectName, r)
+		return
+	}
+
+	duplicates, err := listUnicodeDuplicates(objLayer, bucket, prefix)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(duplicates)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal unicode duplicates into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // HealBucketHandler - POST /?heal&bucket=mybucket&dry-run
 // - x-minio-operation = bucket
 // - bucket is mandatory query parameter
@@ -508,14 +844,14 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	// Get object layer instance.
 	objLayer := newObjectLayerFn()
 	if objLayer == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -523,7 +859,7 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	vars := r.URL.Query()
 	bucket := vars.Get(string(mgmtBucket))
 	if err := checkBucketExist(bucket, objLayer); err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -536,9 +872,11 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	// Heal the given bucket.
 	err := objLayer.HealBucket(bucket)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
+	raiseAlert(alertHealCompleted, bucket, "Healed bucket %s", bucket)
+	serverEventNotify(ServerEventHealCompleted, bucket, "Healed bucket %s", bucket)
 
 	// Return 200 on success.
 	writeSuccessResponseHeadersOnly(w)
@@ -561,14 +899,14 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 	// Get object layer instance.
 	objLayer := newObjectLayerFn()
 	if objLayer == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -578,13 +916,13 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 
 	// Validate bucket and object names.
 	if err := checkBucketAndObjectNames(bucket, object); err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// Check if object exists.
 	if _, err := objLayer.GetObjectInfo(bucket, object); err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -597,14 +935,130 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 
 	err := objLayer.HealObject(bucket, object)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
+	// Objects are healed one by one, alert once per bucket.
+	raiseAlert(alertHealCompleted, bucket, "Healed objects of bucket %s", bucket)
 
 	// Return 200 on success.
 	writeSuccessResponseHeadersOnly(w)
 }
 
+// StartHealHandler - POST /?heal&bucket=mybucket&prefix=myprefix&dry-run
+// - x-minio-operation = start
+// - bucket is a mandatory query parameter
+// Starts healing a bucket and the objects under prefix in the
+// background, returns the token of the heal sequence.
+func (adminAPI adminAPIHandlers) StartHealHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Heal sequences are supported only for the erasure coded backend.
+	xl, ok := unwrapObjectLayer(objLayer).(*xlObjects)
+	if !ok {
+		writeErrorResponse(w, ErrNotImplemented, r)
+		return
+	}
+
+	// Validate bucket name and check if it exists.
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	prefix := vars.Get(string(mgmtPrefix))
+	if err := checkBucketExist(bucket, objLayer); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+	if !IsValidObjectPrefix(prefix) {
+		writeErrorResponse(w, ErrInvalidObjectName, r)
+		return
+	}
+
+	// if dry-run is present in query-params, then only perform validations and return success.
+	if isDryRun(vars) {
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	seq, err := globalHealSequences.start(xl, bucket, prefix)
+	if err == errHealAlreadyRunning {
+		writeErrorResponse(w, ErrAdminHealAlreadyRunning, r)
+		return
+	}
+	if err != nil {
+		errorIf(err, "Unable to start heal sequence.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(seq.Status())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal heal sequence status into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// HealStatusHandler - GET /?heal&token=mytoken
+// Streams the progress of a heal sequence started on this server, one
+// json encoded status per line every healStatusInterval, until the
+// sequence has finished.
+func (adminAPI adminAPIHandlers) HealStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	seq := globalHealSequences.get(r.URL.Query().Get(string(mgmtToken)))
+	if seq == nil {
+		writeErrorResponse(w, ErrAdminNoSuchHealSequence, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(healStatusInterval)
+	defer ticker.Stop()
+	for {
+		status := seq.Status()
+		statusBytes, err := json.Marshal(status)
+		if err != nil {
+			errorIf(err, "Failed to marshal heal sequence status into json.")
+			return
+		}
+		if _, err = w.Write(append(statusBytes, '\n')); err != nil {
+			return
+		}
+		w.(http.Flusher).Flush()
+		if status.State != healSequenceRunning {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-seq.doneCh:
+		case <-globalServiceDoneCh:
+			return
+		}
+	}
+}
+
 // HealFormatHandler - POST /?heal&dry-run
 // - x-minio-operation = format
 // - bucket and object are both mandatory query parameters
@@ -613,14 +1067,14 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 	// Get current object layer instance.
 	objectAPI := newObjectLayerFn()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -628,7 +1082,7 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 	// heal-format is only applicable to single node XL and
 	// distributed XL setup.
 	if !globalIsXL {
-		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		writeErrorResponse(w, ErrNotImplemented, r)
 		return
 	}
 
@@ -640,68 +1094,172 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	approvalDone, adminAPIErr := checkApproval(objectAPI, r, "heal.format", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+	approved := false
+	defer func() { approvalDone(approved) }()
+
 	// Create a new set of storage instances to heal format.json.
 	bootstrapDisks, err := initStorageDisks(globalEndpoints)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// Heal format.json on available storage.
 	err = healFormatXL(bootstrapDisks)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// Instantiate new object layer with newly formatted storage.
 	newObjectAPI, err := newXLObjects(bootstrapDisks)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// Set object layer with newly formatted storage to globalObjectAPI.
 	globalObjLayerMutex.Lock()
-	globalObjectAPI = newObjectAPI
+	globalObjectAPI = wrapObjectLayer(newObjectAPI)
 	globalObjLayerMutex.Unlock()
 
 	// Shutdown storage belonging to old object layer instance.
 	objectAPI.Shutdown()
 
+	approved = true
+
 	// Inform peers to reinitialize storage with newly formatted storage.
 	reInitPeerDisks(globalAdminPeers)
+	raiseAlert(alertHealCompleted, "", "Healed format of all disks")
+	serverEventNotify(ServerEventHealCompleted, "format", "Healed format of all disks")
 
 	// Return 200 on success.
 	writeSuccessResponseHeadersOnly(w)
 }
 
+// ListQuarantinedHandler - GET /?heal&bucket=mybucket
+// - x-minio-operation = list-quarantined
+// - bucket is optional query parameter
+// Lists the objects moved out of their bucket because their xl.json
+// is corrupted on too many disks, oldest first.
+func (adminAPI adminAPIHandlers) ListQuarantinedHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if bucket != "" && !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+
+	objects, err := listQuarantinedObjects(objLayer, bucket)
+	if err != nil {
+		errorIf(err, "Unable to list quarantined objects.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(objects)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal quarantined objects into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RestoreQuarantinedHandler - POST /?heal&id=quarantine-id
+// - x-minio-operation = restore-quarantined
+// - id is mandatory query parameter
+// Moves a quarantined object back into its bucket, replies with the
+// restored object.
+func (adminAPI adminAPIHandlers) RestoreQuarantinedHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionHeal)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	object, err := restoreQuarantinedObject(objLayer, r.URL.Query().Get(string(mgmtID)))
+	if err != nil {
+		errorIf(err, "Unable to restore quarantined object.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(object)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal restored object into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // GetConfigHandler - GET /?config
 // - x-minio-operation = get
 // Get config.json of this minio setup.
 func (adminAPI adminAPIHandlers) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
-	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	// Validate request signature, config.json holds the server and
+	// admin credentials so only the server credential may read it.
+	adminAPIErr := checkRootAdminRequestAuthType(r)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
 	// check if objectLayer is initialized, if not return.
 	if newObjectLayerFn() == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Get config.json from all nodes. In a single node setup, it
 	// returns local config.json.
-	configBytes, err := getPeerConfig(globalAdminPeers)
+	configBytes, errs, err := getPeerConfig(globalAdminPeers)
 	if err != nil {
 		errorIf(err, "Failed to get config from peers")
-		writeErrorResponse(w, toAdminAPIErrCode(err), r.URL)
+		writeErrorResponse(w, toAdminAPIErrCode(err), r)
 		return
 	}
 
+	// The response body is the config itself, so nodes which failed
+	// to reply are reported in headers.
+	if failedNodes := getFailedNodes(globalAdminPeers, errs); len(failedNodes) > 0 {
+		var names []string
+		for _, node := range failedNodes {
+			names = append(names, node.Name)
+		}
+		w.Header().Set(minioAdminPartialHeader, "true")
+		w.Header().Set(minioAdminFailedNodesHeader, strings.Join(names, ","))
+	}
+
 	writeSuccessResponseJSON(w, configBytes)
 }
 
@@ -726,10 +1284,32 @@ type nodeSummary struct {
 type setConfigResult struct {
 	NodeResults []nodeSummary `json:"nodeResults"`
 	Status      bool          `json:"status"`
+	// Partial is set if the config was set on a quorum of nodes
+	// but not on all of them.
+	Partial bool `json:"partial"`
+	// Results of connecting to the notification targets enabled
+	// in the config.
+	NotifyTargets []notifyTargetCheck `json:"notifyTargets,omitempty"`
+}
+
+// getFailedNodes - returns the summaries of peers whose errs are set.
+func getFailedNodes(peers adminPeers, errs []error) []nodeSummary {
+	var failedNodes []nodeSummary
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failedNodes = append(failedNodes, nodeSummary{
+			Name:   peers[i].addr,
+			ErrSet: true,
+			ErrMsg: err.Error(),
+		})
+	}
+	return failedNodes
 }
 
 // writeSetConfigResponse - writes setConfigResult value as json depending on the status.
-func writeSetConfigResponse(w http.ResponseWriter, peers adminPeers, errs []error, status bool, reqURL *url.URL) {
+func writeSetConfigResponse(w http.ResponseWriter, peers adminPeers, errs []error, targets []notifyTargetCheck, status bool, r *http.Request) {
 	var nodeResults []nodeSummary
 	// Build nodeResults based on error values received during
 	// set-config operation.
@@ -743,8 +1323,10 @@ func writeSetConfigResponse(w http.ResponseWriter, peers adminPeers, errs []erro
 	}
 
 	result := setConfigResult{
-		Status:      status,
-		NodeResults: nodeResults,
+		Status:        status,
+		NodeResults:   nodeResults,
+		Partial:       status && len(getFailedNodes(peers, errs)) > 0,
+		NotifyTargets: targets,
 	}
 
 	// The following elaborate json encoding is to avoid escaping
@@ -755,7 +1337,7 @@ func writeSetConfigResponse(w http.ResponseWriter, peers adminPeers, errs []erro
 	enc.SetEscapeHTML(false)
 	jsonErr := enc.Encode(result)
 	if jsonErr != nil {
-		writeErrorResponse(w, toAPIErrorCode(jsonErr), reqURL)
+		writeErrorResponse(w, toAPIErrorCode(jsonErr), r)
 		return
 	}
 
@@ -769,14 +1351,14 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	// Get current object layer instance.
 	objectAPI := newObjectLayerFn()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionConfig)
 	if adminAPIErr != ErrNone {
-		writeErrorResponse(w, adminAPIErr, r.URL)
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
 
@@ -784,10 +1366,43 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	configBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		errorIf(err, "Failed to read config from request body.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	targets := checkConfigNotifyTargets(configBytes)
+	if notifyTargetsFailed(targets) {
+		writeSetConfigResponse(w, globalAdminPeers, nil, targets, false, r)
+		return
+	}
+
+	approvalDone, adminAPIErr := checkApproval(objectAPI, r, "config.set", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
 		return
 	}
+	approved := false
+	defer func() { approvalDone(approved) }()
+
+	approved = setConfigAndRestart(w, r, configBytes, targets)
+}
+
+// checkConfigNotifyTargets - connects to the notification targets
+// enabled in configBytes. Servers do not start if they fail to connect
+// to one of them, so the config must be rejected before it is saved.
+// Targets are only connected to from this server.
+func checkConfigNotifyTargets(configBytes []byte) []notifyTargetCheck {
+	srvCfg := &serverConfigV16{}
+	if err := json.Unmarshal(configBytes, srvCfg); err != nil {
+		return nil
+	}
+	return checkNotifyTargets(srvCfg)
+}
 
+// setConfigAndRestart - saves configBytes as config.json on all
+// servers and restarts them, replies with the result of every server
+// and of the notification targets checked. Returns true on success.
+func setConfigAndRestart(w http.ResponseWriter, r *http.Request, configBytes []byte, targets []notifyTargetCheck) bool {
 	// Write config received from request onto a temporary file on
 	// all nodes.
 	tmpFileName := fmt.Sprintf(minioConfigTmpFormat, mustGetUUID())
@@ -796,8 +1411,8 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	// Check if the operation succeeded in quorum or more nodes.
 	rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1)
 	if rErr != nil {
-		writeSetConfigResponse(w, globalAdminPeers, errs, false, r.URL)
-		return
+		writeSetConfigResponse(w, globalAdminPeers, errs, targets, false, r)
+		return false
 	}
 
 	// Take a lock on minio/config.json. NB minio is a reserved
@@ -811,16 +1426,1009 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	errs = commitConfigPeers(globalAdminPeers, tmpFileName)
 	rErr = reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1)
 	if rErr != nil {
-		writeSetConfigResponse(w, globalAdminPeers, errs, false, r.URL)
-		return
+		writeSetConfigResponse(w, globalAdminPeers, errs, targets, false, r)
+		return false
 	}
 
 	// serverMux (cmd/server-mux.go) implements graceful shutdown,
 	// where all listeners are closed and process restart/shutdown
 	// happens after 5s or completion of all ongoing http
 	// requests, whichever is earlier.
-	writeSetConfigResponse(w, globalAdminPeers, errs, true, r.URL)
+	writeSetConfigResponse(w, globalAdminPeers, errs, targets, true, r)
+	serverEventNotify(ServerEventConfigChanged, "config", "Configuration changed, restarting all servers")
 
 	// Restart all node for the modified config to take effect.
 	sendServiceCmd(globalAdminPeers, serviceRestart)
+	return true
+}
+
+// policyValidationResult - represents the result of a validate-policy
+// operation.
+type policyValidationResult struct {
+	Valid      bool   `json:"valid"`
+	Statements int    `json:"statements"`
+	ErrCode    string `json:"errCode,omitempty"`
+	ErrMsg     string `json:"errMsg,omitempty"`
+}
+
+// ValidatePolicyHandler - POST /?policy&bucket=mybucket
+// - x-minio-operation = validate
+// - bucket is mandatory query parameter
+// Validates the bucket policy supplied in the request body for the
+// given bucket without applying it. Replies with the reason the
+// policy would be rejected, if any.
+func (adminAPI adminAPIHandlers) ValidatePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionPolicy)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// N B bucket need not exist, only its name is used to
+	// validate policy resources.
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+
+	// If Content-Length is greater than maximum allowed policy size.
+	if r.ContentLength > maxAccessPolicySize {
+		writeErrorResponse(w, ErrEntityTooLarge, r)
+		return
+	}
+
+	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAccessPolicySize))
+	if err != nil {
+		errorIf(err, "Unable to read policy from request body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	result := policyValidationResult{Valid: true}
+	policy, s3Error, err := validateBucketPolicy(bucket, policyBytes)
+	if s3Error != ErrNone {
+		result = policyValidationResult{
+			Valid:   false,
+			ErrCode: getAPIError(s3Error).Code,
+			ErrMsg:  err.Error(),
+		}
+	} else {
+		result.Statements = len(policy.Statements)
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal policy validation result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// Reasons reported by the policy simulator for its decision.
+const (
+	policyDecisionRootCredential   = "root-credential"
+	policyDecisionUnknownAccessKey = "unknown-access-key"
+	policyDecisionIAMUserPolicy    = "iam-user-policy"
+	policyDecisionBucketPolicy     = "bucket-policy"
+	policyDecisionNoBucketPolicy   = "no-bucket-policy"
+	policyDecisionImplicitDeny     = "implicit-deny"
+)
+
+// policySimulationResult - represents the result of a simulate-policy
+// operation.
+type policySimulationResult struct {
+	Allowed   bool             `json:"allowed"`
+	DecidedBy string           `json:"decidedBy"`
+	Statement *policyStatement `json:"statement,omitempty"`
+}
+
+// simulatePolicy - evaluates whether accessKey is allowed to perform
+// action on bucket/object and reports what decided it. An empty
+// accessKey simulates an anonymous request.
+func simulatePolicy(accessKey, action, bucket, object, prefix, referer string) policySimulationResult {
+	queryParams := make(url.Values)
+	if prefix != "" {
+		queryParams.Set("prefix", prefix)
+	}
+	arn := bucketARNPrefix + strings.TrimSuffix(path.Join(bucket, object), "/")
+
+	if accessKey != "" {
+		// Requests signed with the root credential bypass bucket
+		// policies, requests of IAM users are allowed only what the
+		// policy of the user allows.
+		if accessKey == serverConfig.GetCredential().AccessKey {
+			return policySimulationResult{Allowed: true, DecidedBy: policyDecisionRootCredential}
+		}
+		user, ok := getIAMUser(accessKey)
+		if !ok {
+			return policySimulationResult{Allowed: false, DecidedBy: policyDecisionUnknownAccessKey}
+		}
+		conditions := getIAMConditionKeyMap(accessKey, referer, queryParams)
+		return simulatePolicyStatements(action, arn, conditions, user.policy.Statements, policyDecisionIAMUserPolicy)
+	}
+
+	policy := globalBucketPolicies.GetBucketPolicy(bucket)
+	if policy == nil {
+		return policySimulationResult{Allowed: false, DecidedBy: policyDecisionNoBucketPolicy}
+	}
+	conditions := getAnonymousConditionKeyMap(referer, queryParams)
+	return simulatePolicyStatements(action, arn, conditions, policy.Statements, policyDecisionBucketPolicy)
+}
+
+// simulatePolicyStatements - reports whether statements allow action on
+// arn, decidedBy is reported when a statement decides it.
+func simulatePolicyStatements(action, arn string, conditions map[string]set.StringSet, statements []policyStatement, decidedBy string) policySimulationResult {
+	statement := bucketPolicyDecidingStatement(action, arn, conditions, statements)
+	if statement == nil {
+		return policySimulationResult{Allowed: false, DecidedBy: policyDecisionImplicitDeny}
+	}
+	return policySimulationResult{
+		Allowed:   statement.Effect == "Allow",
+		DecidedBy: decidedBy,
+		Statement: statement,
+	}
+}
+
+// SimulatePolicyHandler - GET /?policy&accessKey=key&action=s3:GetObject&bucket=mybucket&object=myobject
+// - x-minio-operation = simulate
+// - action and bucket are mandatory query parameters
+// - accessKey, object, prefix and referer are optional query parameters
+// Reports whether the given access key, or an anonymous client if
+// none is given, is allowed to perform action on bucket/object, and
+// which policy statement decided it.
+func (adminAPI adminAPIHandlers) SimulatePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionPolicy)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	action := vars.Get(string(mgmtAction))
+	bucket := vars.Get(string(mgmtBucket))
+	object := vars.Get(string(mgmtObject))
+	prefix := vars.Get(string(mgmtPrefix))
+	referer := vars.Get(string(mgmtReferer))
+
+	// Only concrete actions can be simulated.
+	if !supportedActionMap.Contains(action) || strings.Contains(action, "*") {
+		writeErrorResponse(w, ErrAdminInvalidPolicyAction, r)
+		return
+	}
+
+	// Validate bucket and object names.
+	if object != "" {
+		if err := checkBucketAndObjectNames(bucket, object); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r)
+			return
+		}
+	}
+	if err := checkBucketExist(bucket, objLayer); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	result := simulatePolicy(accessKey, action, bucket, object, prefix, referer)
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal policy simulation result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ExportBucketConfigHandler - GET /?bucket-config&bucket=mybucket
+// - x-minio-operation = export
+// - bucket is mandatory query parameter
+// Replies with the configuration bundle of the bucket, a JSON document
+// carrying its policy and notification configuration.
+func (adminAPI adminAPIHandlers) ExportBucketConfigHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionBucketConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if err := checkBucketExist(bucket, objLayer); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	bundle, err := exportBucketConfig(bucket, objLayer)
+	if err != nil {
+		errorIf(err, "Unable to export configuration of bucket %s.", bucket)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(bundle)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal bucket configuration bundle into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ImportBucketConfigHandler - PUT /?bucket-config&bucket=mybucket
+// - x-minio-operation = import
+// - bucket is mandatory query parameter
+// Replaces the configuration of the bucket with the bundle supplied in
+// the request body, as returned by export. Configuration missing from
+// the bundle is removed, nothing is changed if the bundle is invalid.
+func (adminAPI adminAPIHandlers) ImportBucketConfigHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionBucketConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if err := checkBucketExist(bucket, objLayer); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// If Content-Length is greater than maximum allowed bundle size.
+	if r.ContentLength > maxBucketConfigBundleSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r)
+		return
+	}
+
+	bundleBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBucketConfigBundleSize))
+	if err != nil {
+		errorIf(err, "Unable to read bucket configuration bundle from request body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	if s3Error := importBucketConfig(bucket, bundleBytes, objLayer); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// AdoptObjectsHandler - POST /?adopt&bucket=mybucket&prefix=myprefix
+// - x-minio-operation = adopt
+// - bucket is mandatory query parameter
+// - prefix is optional query parameter
+// Registers files copied into the bucket directory of an FS backend as
+// objects, replies with the number of files adopted and skipped.
+func (adminAPI adminAPIHandlers) AdoptObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionAdopt)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	prefix := vars.Get(string(mgmtPrefix))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+	if !IsValidObjectPrefix(prefix) {
+		writeErrorResponse(w, ErrInvalidObjectName, r)
+		return
+	}
+
+	result, err := adoptObjects(objLayer, bucket, prefix)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal adopt result into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ListAdminAuditHandler - GET /?audit&date=2017-06-01
+// HTTP header x-minio-operation: list
+// ----------
+// Lists the admin audit log of a UTC day, today if no date is
+// given, and verifies its hash chain.
+func (adminAPI adminAPIHandlers) ListAdminAuditHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionAudit)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	date := time.Now().UTC()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		var err error
+		if date, err = time.Parse(adminAuditDateFormat, dateStr); err != nil {
+			writeErrorResponse(w, ErrInvalidQueryParams, r)
+			return
+		}
+	}
+
+	auditLog, err := getAdminAuditLog(objLayer, date)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		errorIf(err, "Unable to read admin audit log of %s.", auditLog.Date)
+		return
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(auditLog)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal admin audit log into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RevokePresignedHandler - POST /?presign&accessKey=key&before=time
+// - accessKey and before are optional query parameters
+// HTTP header x-minio-operation: revoke
+// ---------
+// Revokes on all servers the presigned URLs signed with accessKey, the
+// server access key by default, before the given RFC 3339 time, the
+// current time by default. Presigned URLs signed later stay valid.
+func (adminAPI adminAPIHandlers) RevokePresignedHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	if accessKey == "" {
+		accessKey = serverConfig.GetCredential().AccessKey
+	} else if accessKey != serverConfig.GetCredential().AccessKey {
+		_, isAdmin := globalAdminCredentials[accessKey]
+		if _, isUser := getIAMUser(accessKey); !isAdmin && !isUser {
+			writeErrorResponse(w, ErrAdminInvalidAccessKey, r)
+			return
+		}
+	}
+	before := time.Now().UTC()
+	if beforeStr := vars.Get(string(mgmtBefore)); beforeStr != "" {
+		t, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil || t.After(before) {
+			writeErrorResponse(w, ErrInvalidQueryParams, r)
+			return
+		}
+		before = t
+	}
+
+	errs := revokePeerPresigned(globalAdminPeers, accessKey, before)
+	for i, err := range errs {
+		errorIf(err, "Unable to revoke presigned URLs on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "presign", "Presigned URLs of access key %s signed before %s revoked",
+		accessKey, before.Format(time.RFC3339))
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetRequestLimitHandler - PUT /?request-limit&accessKey=key&maxConcurrent=n&maxRequestsPerSecond=n
+// - all query parameters are optional
+// HTTP header x-minio-operation: set
+// ---------
+// Sets on all servers the limits of the requests signed with accessKey,
+// the server access key by default. Requests over the limits are
+// rejected with SlowDown, a limit of 0 or omitted is unlimited.
+func (adminAPI adminAPIHandlers) SetRequestLimitHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	accessKey := vars.Get(string(mgmtAccessKey))
+	if accessKey == "" {
+		accessKey = serverConfig.GetCredential().AccessKey
+	} else if accessKey != serverConfig.GetCredential().AccessKey {
+		_, isAdmin := globalAdminCredentials[accessKey]
+		if _, isUser := getIAMUser(accessKey); !isAdmin && !isUser {
+			writeErrorResponse(w, ErrAdminInvalidAccessKey, r)
+			return
+		}
+	}
+	var limit requestLimitConfig
+	for _, param := range []struct {
+		key   mgmtQueryKey
+		value *int
+	}{
+		{mgmtMaxConcurrent, &limit.MaxConcurrent},
+		{mgmtMaxRequestsPerSecond, &limit.MaxRequestsPerSecond},
+	} {
+		valueStr := vars.Get(string(param.key))
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			writeErrorResponse(w, ErrInvalidQueryParams, r)
+			return
+		}
+		*param.value = value
+	}
+	if err := validateRequestLimit(limit); err != nil {
+		writeErrorResponse(w, ErrInvalidQueryParams, r)
+		return
+	}
+
+	errs := setPeerRequestLimit(globalAdminPeers, accessKey, limit)
+	for i, err := range errs {
+		errorIf(err, "Unable to set request limits on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "requestLimits", "Request limits of access key %s set to %d concurrent and %d per second",
+		accessKey, limit.MaxConcurrent, limit.MaxRequestsPerSecond)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// RevokeBrowserSessionsHandler - POST /?browser-session&before=time
+// - before is an optional query parameter
+// HTTP header x-minio-operation: revoke
+// ---------
+// Revokes on all servers the browser sessions started before the given
+// RFC 3339 time, the current time by default. Browsers have to log in
+// again.
+func (adminAPI adminAPIHandlers) RevokeBrowserSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	before := time.Now().UTC()
+	if beforeStr := r.URL.Query().Get(string(mgmtBefore)); beforeStr != "" {
+		t, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil || t.After(before) {
+			writeErrorResponse(w, ErrInvalidQueryParams, r)
+			return
+		}
+		before = t
+	}
+
+	errs := revokePeerBrowserSessions(globalAdminPeers, before)
+	for i, err := range errs {
+		errorIf(err, "Unable to revoke browser sessions on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "browserSession", "Browser sessions started before %s revoked",
+		before.Format(time.RFC3339))
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetBrowserReadOnlyHandler - PUT /?browser-read-only&enable=true|false
+// HTTP header x-minio-operation: set
+// ---------
+// Makes the browser read-only on all servers, or writable again.
+// Read-only browsers list and download objects, but cannot upload or
+// delete them. The S3 API is not affected.
+func (adminAPI adminAPIHandlers) SetBrowserReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	readOnly, err := strconv.ParseBool(r.URL.Query().Get(string(mgmtEnable)))
+	if err != nil {
+		writeErrorResponse(w, ErrInvalidQueryParams, r)
+		return
+	}
+
+	errs := setPeerBrowserReadOnly(globalAdminPeers, readOnly)
+	for i, err := range errs {
+		errorIf(err, "Unable to set browser read-only mode on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "browserReadOnly", "Browser read-only mode set to %t", readOnly)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetBucketNetworkHandler - PUT /?bucket-network&bucket=mybucket&allow=cidr&deny=cidr
+// - allow and deny are optional and may be repeated
+// HTTP header x-minio-operation: set
+// ---------
+// Sets on all servers the client networks allowed to access bucket.
+// Requests from other clients are rejected with AccessDenied before
+// any policy is evaluated. Without networks, any client is allowed.
+func (adminAPI adminAPIHandlers) SetBucketNetworkHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionBucketConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+	config := bucketNetworkConfig{
+		Allow: vars[string(mgmtAllow)],
+		Deny:  vars[string(mgmtDeny)],
+	}
+	if err := validateBucketNetwork(config); err != nil {
+		writeErrorResponse(w, ErrInvalidQueryParams, r)
+		return
+	}
+
+	errs := setPeerBucketNetwork(globalAdminPeers, bucket, config)
+	for i, err := range errs {
+		errorIf(err, "Unable to set bucket networks on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "bucketNetworks", "Networks of bucket %s set to allow %v and deny %v",
+		bucket, config.Allow, config.Deny)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// readIAMUserConfig - reads the user config in the body of r.
+func readIAMUserConfig(r *http.Request) (config iamUserConfig, s3Error APIErrorCode) {
+	// If Content-Length is greater than maximum allowed user size.
+	if r.ContentLength > maxIAMUserConfigSize {
+		return config, ErrEntityTooLarge
+	}
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxIAMUserConfigSize))
+	if err != nil {
+		errorIf(err, "Unable to read IAM user from request body.")
+		return config, toAPIErrorCode(err)
+	}
+	if err = json.Unmarshal(configBytes, &config); err != nil {
+		return config, ErrInvalidRequestBody
+	}
+	return config, ErrNone
+}
+
+// validateIAMUser - returns the error replied for the user config
+// of accessKey, ErrNone if valid.
+func validateIAMUser(accessKey string, config iamUserConfig) APIErrorCode {
+	if err := validateAuthKeys(accessKey, config.SecretKey); err != nil {
+		return toAPIErrorCode(err)
+	}
+	if err := checkIAMAccessKey(accessKey, serverConfig.GetCredential(), serverConfig.GetAdminCredentials()); err != nil {
+		return ErrAdminInvalidAccessKey
+	}
+	if _, err := newIAMUser(accessKey, config); err != nil {
+		return ErrMalformedPolicy
+	}
+	return ErrNone
+}
+
+// setIAMUserOnPeers - sets the user config of accessKey on all
+// servers, replies an error if the quorum of servers is not met.
+func setIAMUserOnPeers(w http.ResponseWriter, r *http.Request, accessKey string, config iamUserConfig) bool {
+	errs := setPeerIAMUser(globalAdminPeers, accessKey, config)
+	for i, err := range errs {
+		errorIf(err, "Unable to set IAM user on peer %s.", globalAdminPeers[i].addr)
+	}
+	if rErr := reduceWriteQuorumErrs(errs, nil, len(globalAdminPeers)/2+1); rErr != nil {
+		writeErrorResponse(w, ErrAdminConfigNoQuorum, r)
+		return false
+	}
+	return true
+}
+
+// AddIAMUserHandler - PUT /?iam-user&accessKey=key
+// HTTP header x-minio-operation: add
+// ---------
+// Adds on all servers the IAM user with accessKey, or replaces it,
+// with the secret key and either the canned policy or the custom
+// policy in the JSON request body. Requests signed by the user are
+// allowed only the actions its policy allows.
+func (adminAPI adminAPIHandlers) AddIAMUserHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	config, s3Error := readIAMUserConfig(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	if s3Error = validateIAMUser(accessKey, config); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	if !setIAMUserOnPeers(w, r, accessKey, config) {
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "users", "IAM user %s added", accessKey)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetIAMUserPolicyHandler - PUT /?iam-user&accessKey=key
+// HTTP header x-minio-operation: set-policy
+// ---------
+// Replaces on all servers the policy of the IAM user with accessKey by
+// either the canned policy or the custom policy in the JSON request
+// body, the secret key of the user is kept.
+func (adminAPI adminAPIHandlers) SetIAMUserPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	user, ok := serverConfig.GetUsers()[accessKey]
+	if !ok {
+		writeErrorResponse(w, ErrAdminNoSuchIAMUser, r)
+		return
+	}
+	config, s3Error := readIAMUserConfig(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	config.SecretKey = user.SecretKey
+	if s3Error = validateIAMUser(accessKey, config); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	if !setIAMUserOnPeers(w, r, accessKey, config) {
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "users", "Policy of IAM user %s set", accessKey)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// RemoveIAMUserHandler - DELETE /?iam-user&accessKey=key
+// HTTP header x-minio-operation: remove
+// ---------
+// Removes on all servers the IAM user with accessKey, requests signed
+// by the user are rejected from now on.
+func (adminAPI adminAPIHandlers) RemoveIAMUserHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	if _, ok := serverConfig.GetUsers()[accessKey]; !ok {
+		writeErrorResponse(w, ErrAdminNoSuchIAMUser, r)
+		return
+	}
+
+	if !setIAMUserOnPeers(w, r, accessKey, iamUserConfig{}) {
+		return
+	}
+
+	serverEventNotify(ServerEventConfigChanged, "users", "IAM user %s removed", accessKey)
+
+	// At this stage, the operation is successful, return 200 OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// iamUserInfo - an IAM user as listed, without its secret key.
+type iamUserInfo struct {
+	AccessKey    string          `json:"accessKey"`
+	CannedPolicy string          `json:"cannedPolicy,omitempty"`
+	Policy       json.RawMessage `json:"policy,omitempty"`
+}
+
+// ListIAMUsersHandler - GET /?iam-user
+// HTTP header x-minio-operation: list
+// ---------
+// Lists the IAM users sorted by access key along with their policies,
+// secret keys are never listed.
+func (adminAPI adminAPIHandlers) ListIAMUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionCredentials)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	users := serverConfig.GetUsers()
+	accessKeys := make([]string, 0, len(users))
+	for accessKey := range users {
+		accessKeys = append(accessKeys, accessKey)
+	}
+	sort.Strings(accessKeys)
+	infos := make([]iamUserInfo, 0, len(users))
+	for _, accessKey := range accessKeys {
+		infos = append(infos, iamUserInfo{
+			AccessKey:    accessKey,
+			CannedPolicy: users[accessKey].CannedPolicy,
+			Policy:       users[accessKey].Policy,
+		})
+	}
+
+	// Marshal API response
+	jsonBytes, err := json.Marshal(infos)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal IAM users into json.")
+		return
+	}
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// backupResult - represents the result of a backup operation.
+type backupResult struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+}
+
+// CreateBackupHandler - POST /?backup
+// HTTP header x-minio-operation: create
+// ---------
+// Backs up the server configuration and the configuration of all
+// buckets into the backup bucket now, replies with the backup object.
+func (adminAPI adminAPIHandlers) CreateBackupHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	config := serverConfig.GetBackup()
+	if config.Bucket == "" {
+		writeErrorResponse(w, ErrAdminBackupNotConfigured, r)
+		return
+	}
+
+	object, err := createBackup(objLayer, config, time.Now().UTC())
+	if err != nil {
+		errorIf(err, "Unable to back up configuration into %s.", config.Bucket)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(backupResult{Bucket: config.Bucket, Object: object})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal backup result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RestoreBackupHandler - POST /?backup&object=name
+// - object is mandatory query parameter
+// - bucket is an optional query parameter
+// HTTP header x-minio-operation: restore
+// ---------
+// Restores the backup object of the given bucket, the backup bucket by
+// default. Buckets of the backup missing are created and their
+// configuration replaced, then the server configuration is saved on
+// all servers and they are restarted, as with set config.
+func (adminAPI adminAPIHandlers) RestoreBackupHandler(w http.ResponseWriter, r *http.Request) {
+	// Get object layer instance.
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, adminActionConfig)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	// Validate query params.
+	vars := r.URL.Query()
+	bucket := vars.Get(string(mgmtBucket))
+	if bucket == "" {
+		bucket = serverConfig.GetBackup().Bucket
+	}
+	if bucket == "" {
+		writeErrorResponse(w, ErrAdminBackupNotConfigured, r)
+		return
+	}
+	object := vars.Get(string(mgmtObject))
+	if !IsValidBucketName(bucket) || !IsValidObjectName(object) {
+		writeErrorResponse(w, ErrInvalidQueryParams, r)
+		return
+	}
+
+	backup, err := readBackup(objLayer, bucket, object)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	targets := checkConfigNotifyTargets(backup.Config)
+	if notifyTargetsFailed(targets) {
+		writeSetConfigResponse(w, globalAdminPeers, nil, targets, false, r)
+		return
+	}
+
+	approvalDone, adminAPIErr := checkApproval(objLayer, r, "backup.restore", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+	approved := false
+	defer func() { approvalDone(approved) }()
+
+	if s3Error := restoreBucketConfigs(objLayer, backup); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	approved = setConfigAndRestart(w, r, backup.Config, targets)
+}
+
+// approvalResult - represents the result of an approval operation.
+type approvalResult struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// IssueApprovalHandler - POST /?approval&operation=lock.clear&bucket=mybucket
+// - operation is mandatory query parameter
+// - bucket is an optional query parameter
+// HTTP header x-minio-operation: issue
+// ---------
+// Replies with an approval token for one call of the destructive
+// operation, on the given bucket if any, with another credential. The
+// credential of the request must be allowed the operation.
+func (adminAPI adminAPIHandlers) IssueApprovalHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate query params.
+	vars := r.URL.Query()
+	operation := vars.Get(string(mgmtOperation))
+	action, ok := approvalOperations[operation]
+	if !ok {
+		writeErrorResponse(w, ErrInvalidQueryParams, r)
+		return
+	}
+	bucket := vars.Get(string(mgmtBucket))
+	if bucket != "" && !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, action)
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r)
+		return
+	}
+
+	token, expires, err := issueApproval(getRequestAccessKeyV4(r), operation, bucket, time.Now().UTC())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to issue approval token.")
+		return
+	}
+
+	jsonBytes, err := json.Marshal(approvalResult{Token: token, Expires: expires})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r)
+		errorIf(err, "Failed to marshal approval result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
 }
@@ -22,9 +22,12 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -519,6 +522,47 @@ func TestListLocksHandler(t *testing.T) {
 	}
 }
 
+// Tests pagination of the list locks response.
+func TestPaginateLocks(t *testing.T) {
+	volLocks := []VolumeLockInfo{
+		{Bucket: "mybucket", Object: "c"},
+		{Bucket: "mybucket", Object: "a"},
+		{Bucket: "mybucket", Object: "b"},
+		{Bucket: "mybucket", Object: "a"},
+		{Bucket: "mybucket", Object: "d"},
+	}
+	testCases := []struct {
+		marker      string
+		maxKey      int
+		objects     []string
+		isTruncated bool
+		nextMarker  string
+	}{
+		{"", 1000, []string{"a", "a", "b", "c", "d"}, false, ""},
+		{"", 2, []string{"a", "a", "b"}, true, "b"},
+		{"b", 2, []string{"c", "d"}, false, ""},
+		{"a", 1, []string{"b"}, true, "b"},
+		{"d", 10, []string{}, false, ""},
+	}
+	for i, testCase := range testCases {
+		resp := paginateLocks(volLocks, testCase.marker, testCase.maxKey)
+		objects := []string{}
+		for _, volLock := range resp.Locks {
+			objects = append(objects, volLock.Object)
+		}
+		if !reflect.DeepEqual(objects, testCase.objects) {
+			t.Errorf("Test %d: Expected objects %v, got %v", i+1, testCase.objects, objects)
+		}
+		if resp.Total != 4 {
+			t.Errorf("Test %d: Expected total 4, got %d", i+1, resp.Total)
+		}
+		if resp.IsTruncated != testCase.isTruncated || resp.NextMarker != testCase.nextMarker {
+			t.Errorf("Test %d: Expected truncated %t with marker %q, got %t with marker %q", i+1,
+				testCase.isTruncated, testCase.nextMarker, resp.IsTruncated, resp.NextMarker)
+		}
+	}
+}
+
 // Test for locks clear management REST API.
 func TestClearLocksHandler(t *testing.T) {
 	adminTestBed, err := prepareAdminXLTestBed()
@@ -532,12 +576,15 @@ func TestClearLocksHandler(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to parse storage end point - %v", err)
 	}
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
 	initGlobalAdminPeers(eps)
 
 	testCases := []struct {
 		bucket         string
 		prefix         string
 		duration       string
+		node           string
 		expectedStatus int
 	}{
 		// Test 1 - valid testcase
@@ -568,10 +615,29 @@ func TestClearLocksHandler(t *testing.T) {
 			duration:       "1h",
 			expectedStatus: http.StatusBadRequest,
 		},
+		// Test 5 - valid node
+		{
+			bucket:         "mybucket",
+			prefix:         "myobject",
+			duration:       "1s",
+			node:           "127.0.0.1:9000",
+			expectedStatus: http.StatusOK,
+		},
+		// Test 6 - node not part of the deployment
+		{
+			bucket:         "mybucket",
+			prefix:         "myobject",
+			duration:       "1s",
+			node:           "192.168.1.12:9000",
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for i, test := range testCases {
 		queryVal := mkLockQueryVal(test.bucket, test.prefix, test.duration)
+		if test.node != "" {
+			queryVal.Set(string(mgmtNode), test.node)
+		}
 		req, err := newTestRequest("POST", "/?"+queryVal.Encode(), 0, nil)
 		if err != nil {
 			t.Fatalf("Test %d - Failed to construct clear locks request - %v", i+1, err)
@@ -737,6 +803,24 @@ func TestValidateHealQueryParams(t *testing.T) {
 			maxKeys:   "999999999999999999999999999",
 			apiErr:    ErrInvalidMaxKeys,
 		},
+		// 10. Valid params with empty max Keys.
+		{
+			bucket:    "mybucket",
+			prefix:    "prefix",
+			marker:    "prefix11",
+			delimiter: "/",
+			maxKeys:   "",
+			apiErr:    ErrNone,
+		},
+		// 11. Valid params with max Keys above the limit.
+		{
+			bucket:    "mybucket",
+			prefix:    "prefix",
+			marker:    "prefix11",
+			delimiter: "/",
+			maxKeys:   "5000",
+			apiErr:    ErrNone,
+		},
 	}
 	for i, test := range testCases {
 		vars := mkListObjectsQueryVal(test.bucket, test.prefix, test.marker, test.delimiter, test.maxKeys)
@@ -1053,6 +1137,108 @@ func TestHealObjectHandler(t *testing.T) {
 	}
 }
 
+// TestHealSequenceHandlers - test for StartHealHandler and HealStatusHandler.
+func TestHealSequenceHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucketName := "mybucket"
+	err = adminTestBed.objLayer.MakeBucket(bucketName)
+	if err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucketName, err)
+	}
+	_, err = adminTestBed.objLayer.PutObject(bucketName, "myprefix/myobject",
+		int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
+	if err != nil {
+		t.Fatalf("Failed to create object - %v", err)
+	}
+
+	cred := serverConfig.GetCredential()
+	startHeal := func(bucket, prefix, dryrun string) *httptest.ResponseRecorder {
+		queryVal := url.Values{}
+		queryVal.Set("heal", "")
+		queryVal.Set(string(mgmtBucket), bucket)
+		queryVal.Set(string(mgmtPrefix), prefix)
+		if dryrun != "" {
+			queryVal.Set(string(mgmtDryRun), dryrun)
+		}
+		req, rerr := newTestRequest("POST", "/?"+queryVal.Encode(), 0, nil)
+		if rerr != nil {
+			t.Fatalf("Failed to construct start heal request - %v", rerr)
+		}
+		req.Header.Set(minioAdminOpHeader, "start")
+		if rerr = signRequestV4(req, cred.AccessKey, cred.SecretKey); rerr != nil {
+			t.Fatalf("Failed to sign start heal request - %v", rerr)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		return rec
+	}
+	healStatus := func(token string) *httptest.ResponseRecorder {
+		queryVal := url.Values{}
+		queryVal.Set("heal", "")
+		queryVal.Set(string(mgmtToken), token)
+		req, rerr := newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+		if rerr != nil {
+			t.Fatalf("Failed to construct heal status request - %v", rerr)
+		}
+		if rerr = signRequestV4(req, cred.AccessKey, cred.SecretKey); rerr != nil {
+			t.Fatalf("Failed to sign heal status request - %v", rerr)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Invalid requests are rejected.
+	if rec := startHeal("bucketnotfound", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected %d for a missing bucket but received %d", http.StatusNotFound, rec.Code)
+	}
+	if rec := startHeal(bucketName, `invalid\\Prefix`, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected %d for an invalid prefix but received %d", http.StatusBadRequest, rec.Code)
+	}
+	if rec := startHeal(bucketName, "myprefix/", "yes"); rec.Code != http.StatusOK {
+		t.Errorf("Expected %d for a dry run but received %d", http.StatusOK, rec.Code)
+	}
+	if rec := healStatus("notoken"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected %d for an unknown token but received %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec := startHeal(bucketName, "myprefix/", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected to start healing but failed with %d", rec.Code)
+	}
+	var started HealSequenceStatus
+	if err = json.Unmarshal(rec.Body.Bytes(), &started); err != nil {
+		t.Fatalf("Failed to unmarshal heal sequence status - %v", err)
+	}
+	if started.Token == "" || started.Bucket != bucketName || started.Prefix != "myprefix/" {
+		t.Fatalf("Unexpected heal sequence status %#v", started)
+	}
+
+	// The status stream ends with the final status of the sequence.
+	rec = healStatus(started.Token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected heal status to succeed but failed with %d", rec.Code)
+	}
+	var status HealSequenceStatus
+	decoder := json.NewDecoder(rec.Body)
+	for decoder.More() {
+		if err = decoder.Decode(&status); err != nil {
+			t.Fatalf("Failed to decode heal sequence status - %v", err)
+		}
+	}
+	if status.Token != started.Token || status.State != healSequenceDone {
+		t.Fatalf("Expected heal sequence %s to be done, got %#v", started.Token, status)
+	}
+	if status.ObjectsScanned != 1 {
+		t.Errorf("Expected 1 object scanned, got %d", status.ObjectsScanned)
+	}
+}
+
 // TestHealFormatHandler - test for HealFormatHandler.
 func TestHealFormatHandler(t *testing.T) {
 	adminTestBed, err := prepareAdminXLTestBed()
@@ -1129,6 +1315,82 @@ func TestGetConfigHandler(t *testing.T) {
 		t.Errorf("Expected to succeed but failed with %d", rec.Code)
 	}
 
+	// Admin credentials are denied whatever their actions, the
+	// config holds the server credential.
+	adminCred := newCredentialWithKeys("ADMINCONFIGKEY", "adminconfigsecret")
+	globalAdminCredentials, err = newAdminCredentials([]adminCredentialConfig{{
+		AccessKey: adminCred.AccessKey,
+		SecretKey: adminCred.SecretKey,
+		Actions:   []string{adminActionAll},
+	}}, cred)
+	if err != nil {
+		t.Fatalf("Unable to create admin credentials - %v", err)
+	}
+	defer func() { globalAdminCredentials = nil }()
+
+	req, err = newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct get-config object request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "get")
+	if err = signRequestV4(req, adminCred.AccessKey, adminCred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign get-config request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected get config to fail with %d but got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+// Tests admin operations served under the versioned REST API paths.
+func TestAdminAPIv1Routes(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	testCases := []struct {
+		method     string
+		path       string
+		expectCode int
+	}{
+		{"GET", "/service", http.StatusOK},
+		{"GET", "/info", http.StatusOK},
+		{"GET", "/config", http.StatusOK},
+		{"GET", "/locks?bucket=mybucket&prefix=myobject&older-than=1s", http.StatusOK},
+		// Operations are not routed by the x-minio-operation header.
+		{"GET", "/?config", http.StatusNotFound},
+		{"GET", "/unknown", http.StatusNotFound},
+	}
+
+	cred := serverConfig.GetCredential()
+	for i, testCase := range testCases {
+		req, err := newTestRequest(testCase.method, adminAPIPathPrefix+testCase.path, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+	}
 }
 
 // TestSetConfigHandler - test for SetConfigHandler.
@@ -1189,6 +1451,83 @@ func TestSetConfigHandler(t *testing.T) {
 	}
 }
 
+// Test that set-config rejects a config with an unreachable
+// notification target.
+func TestSetConfigHandlerNotifyTargets(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	// Address nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := "http://" + listener.Addr().String()
+	listener.Close()
+
+	newConfigJSON := bytes.Replace(configJSON, []byte(`"enable": false,
+				"endpoint": ""`), []byte(`"enable": true,
+				"endpoint": "`+endpoint+`"`), 1)
+	if bytes.Equal(newConfigJSON, configJSON) {
+		t.Fatal("Failed to enable the webhook target of the config")
+	}
+
+	oldConfigBytes, err := ioutil.ReadFile(getConfigFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queryVal := url.Values{}
+	queryVal.Set("config", "")
+	req, err := newTestRequest("PUT", "/?"+queryVal.Encode(), int64(len(newConfigJSON)), bytes.NewReader(newConfigJSON))
+	if err != nil {
+		t.Fatalf("Failed to construct set-config request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "set")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign set-config request - %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected to succeed but failed with %d", rec.Code)
+	}
+
+	result := setConfigResult{}
+	if err = json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode set config result json %v", err)
+	}
+	if result.Status {
+		t.Error("Expected set-config to fail")
+	}
+	if len(result.NotifyTargets) != 1 || !result.NotifyTargets[0].ErrSet ||
+		result.NotifyTargets[0].ARN != "arn:minio:sqs:us-west-1:1:webhook" {
+		t.Errorf("Expected the webhook target to have failed, got %v", result.NotifyTargets)
+	}
+
+	// The config must be left untouched.
+	configBytes, err := ioutil.ReadFile(getConfigFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(configBytes, oldConfigBytes) {
+		t.Error("Expected config.json not to be modified")
+	}
+}
+
 // TestToAdminAPIErr - test for toAdminAPIErr helper function.
 func TestToAdminAPIErr(t *testing.T) {
 	testCases := []struct {
@@ -1253,15 +1592,15 @@ func TestWriteSetConfigResponse(t *testing.T) {
 		},
 	}
 
-	testURL, err := url.Parse("dummy.com")
+	testReq, err := http.NewRequest("PUT", "http://dummy.com", nil)
 	if err != nil {
-		t.Fatalf("Failed to parse a place-holder url")
+		t.Fatalf("Failed to create a place-holder request")
 	}
 
 	var actualResult setConfigResult
 	for i, test := range testCases {
 		rec := httptest.NewRecorder()
-		writeSetConfigResponse(rec, testPeers, test.errs, test.status, testURL)
+		writeSetConfigResponse(rec, testPeers, test.errs, nil, test.status, testReq)
 		resp := rec.Result()
 		jsonBytes, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
@@ -1290,3 +1629,1063 @@ func TestWriteSetConfigResponse(t *testing.T) {
 		}
 	}
 }
+
+// TestValidatePolicyHandler - test for ValidatePolicyHandler.
+func TestValidatePolicyHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	validPolicy := `{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::mybucket/public/*"]}]}`
+	foreignPolicy := `{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::otherbucket/*"]}]}`
+
+	testCases := []struct {
+		bucket       string
+		policy       string
+		expectedCode int
+		valid        bool
+		errCode      string
+	}{
+		// 1. Valid policy, bucket need not exist.
+		{"mybucket", validPolicy, http.StatusOK, true, ""},
+		// 2. Malformed JSON.
+		{"mybucket", `{"Version":`, http.StatusOK, false, "InvalidPolicyDocument"},
+		// 3. Resource outside of the bucket.
+		{"mybucket", foreignPolicy, http.StatusOK, false, "MalformedPolicy"},
+		// 4. Invalid bucket name.
+		{"my", validPolicy, http.StatusBadRequest, false, ""},
+	}
+
+	cred := serverConfig.GetCredential()
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("policy", "")
+		queryVal.Set("bucket", test.bucket)
+
+		req, err := newTestRequest("POST", "/?"+queryVal.Encode(), int64(len(test.policy)), bytes.NewReader([]byte(test.policy)))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct validate-policy request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "validate")
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign validate-policy request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status %d but received %d", i+1, test.expectedCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		result := policyValidationResult{}
+		if err = json.NewDecoder(rec.Body).Decode(&result); err != nil {
+			t.Fatalf("Test %d: Failed to decode validate-policy result json %v", i+1, err)
+		}
+		if result.Valid != test.valid {
+			t.Errorf("Test %d: Expected valid to be %v, got %v (%s)", i+1, test.valid, result.Valid, result.ErrMsg)
+		}
+		if result.ErrCode != test.errCode {
+			t.Errorf("Test %d: Expected error code %s, got %s", i+1, test.errCode, result.ErrCode)
+		}
+	}
+}
+
+// TestSimulatePolicyHandler - test for SimulatePolicyHandler.
+func TestSimulatePolicyHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucketName := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucketName); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucketName, err)
+	}
+	if err = initBucketPolicies(adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to initialize bucket policies - %v", err)
+	}
+
+	// Allow anonymous reads of public/ but deny public/secret/.
+	denyStatement := getReadOnlyObjectStatement(bucketName, "public/secret/")
+	denyStatement.Effect = "Deny"
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{denyStatement, getReadOnlyObjectStatement(bucketName, "public/")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+
+	// IAM user allowed only reads, regardless of the bucket policy.
+	if err = setIAMUser("minio-user", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}); err != nil {
+		t.Fatal(err)
+	}
+
+	cred := serverConfig.GetCredential()
+	testCases := []struct {
+		accessKey         string
+		action            string
+		bucket            string
+		object            string
+		expectedCode      int
+		expectedAllowed   bool
+		expectedDecidedBy string
+		expectedEffect    string
+	}{
+		// 1. Root credential is always allowed.
+		{cred.AccessKey, "s3:PutObject", bucketName, "private/obj", http.StatusOK, true, policyDecisionRootCredential, ""},
+		// 2. Unknown access key.
+		{"unknownkey", "s3:GetObject", bucketName, "public/obj", http.StatusOK, false, policyDecisionUnknownAccessKey, ""},
+		// 3. IAM user allowed by the policy of the user.
+		{"minio-user", "s3:GetObject", bucketName, "private/obj", http.StatusOK, true, policyDecisionIAMUserPolicy, "Allow"},
+		// 4. IAM user matching no statement of the policy of the user.
+		{"minio-user", "s3:PutObject", bucketName, "public/obj", http.StatusOK, false, policyDecisionImplicitDeny, ""},
+		// 5. Anonymous request allowed by bucket policy.
+		{"", "s3:GetObject", bucketName, "public/obj", http.StatusOK, true, policyDecisionBucketPolicy, "Allow"},
+		// 6. Anonymous request denied by bucket policy.
+		{"", "s3:GetObject", bucketName, "public/secret/obj", http.StatusOK, false, policyDecisionBucketPolicy, "Deny"},
+		// 7. Anonymous request matching no statement.
+		{"", "s3:PutObject", bucketName, "public/obj", http.StatusOK, false, policyDecisionImplicitDeny, ""},
+		// 8. Unsupported action.
+		{"", "s3:*", bucketName, "public/obj", http.StatusBadRequest, false, "", ""},
+		// 9. Non-existent bucket.
+		{"", "s3:GetObject", "nobucket", "public/obj", http.StatusNotFound, false, "", ""},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("policy", "")
+		queryVal.Set("accessKey", test.accessKey)
+		queryVal.Set("action", test.action)
+		queryVal.Set("bucket", test.bucket)
+		queryVal.Set("object", test.object)
+
+		req, err := newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct simulate-policy request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "simulate")
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign simulate-policy request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status %d but received %d", i+1, test.expectedCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		result := policySimulationResult{}
+		if err = json.NewDecoder(rec.Body).Decode(&result); err != nil {
+			t.Fatalf("Test %d: Failed to decode simulate-policy result json %v", i+1, err)
+		}
+		if result.Allowed != test.expectedAllowed {
+			t.Errorf("Test %d: Expected allowed to be %v, got %v", i+1, test.expectedAllowed, result.Allowed)
+		}
+		if result.DecidedBy != test.expectedDecidedBy {
+			t.Errorf("Test %d: Expected to be decided by %s, got %s", i+1, test.expectedDecidedBy, result.DecidedBy)
+		}
+		if test.expectedEffect != "" && (result.Statement == nil || result.Statement.Effect != test.expectedEffect) {
+			t.Errorf("Test %d: Expected deciding statement with effect %s, got %v", i+1, test.expectedEffect, result.Statement)
+		}
+	}
+}
+
+// TestExportImportBucketConfigHandler - test for ExportBucketConfigHandler
+// and ImportBucketConfigHandler.
+func TestExportImportBucketConfigHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	objLayer := adminTestBed.objLayer
+	for _, bucket := range []string{"srcbucket", "dstbucket"} {
+		if err = objLayer.MakeBucket(bucket); err != nil {
+			t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+		}
+	}
+	if err = initBucketPolicies(objLayer); err != nil {
+		t.Fatalf("Failed to initialize bucket policies - %v", err)
+	}
+
+	srcPolicy := `{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::srcbucket/public/*"]}]}`
+	policy, s3Error, _ := validateBucketPolicy("srcbucket", []byte(srcPolicy))
+	if s3Error != ErrNone {
+		t.Fatalf("Failed to validate bucket policy - %v", s3Error)
+	}
+	if err = persistAndNotifyBucketPolicyChange("srcbucket", policyChange{false, policy}, objLayer); err != nil {
+		t.Fatalf("Failed to set bucket policy - %v", err)
+	}
+
+	cred := serverConfig.GetCredential()
+	sendRequest := func(method, bucket, op string, body []byte) *httptest.ResponseRecorder {
+		queryVal := url.Values{}
+		queryVal.Set("bucket-config", "")
+		queryVal.Set("bucket", bucket)
+
+		req, rerr := newTestRequest(method, "/?"+queryVal.Encode(), int64(len(body)), bytes.NewReader(body))
+		if rerr != nil {
+			t.Fatalf("Failed to construct %s bucket-config request - %v", op, rerr)
+		}
+		req.Header.Set(minioAdminOpHeader, op)
+		if rerr = signRequestV4(req, cred.AccessKey, cred.SecretKey); rerr != nil {
+			t.Fatalf("Failed to sign %s bucket-config request - %v", op, rerr)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Export configuration of srcbucket.
+	rec := sendRequest("GET", "srcbucket", "export", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected export to succeed but failed with %d", rec.Code)
+	}
+	bundleBytes := rec.Body.Bytes()
+	var bundle bucketConfigBundle
+	if err = json.Unmarshal(bundleBytes, &bundle); err != nil {
+		t.Fatalf("Failed to decode bucket config bundle - %v", err)
+	}
+	if bundle.Version != bucketConfigBundleVersion || bundle.Bucket != "srcbucket" {
+		t.Fatalf("Unexpected bucket config bundle %s", string(bundleBytes))
+	}
+	if len(bundle.Policy) == 0 {
+		t.Fatal("Expected bucket policy in exported bundle")
+	}
+
+	// Policy resources refer to srcbucket, importing into dstbucket
+	// rewrites them to refer to dstbucket.
+	if rec = sendRequest("PUT", "dstbucket", "import", bundleBytes); rec.Code != http.StatusOK {
+		t.Fatalf("Expected import into dstbucket to succeed but failed with %d", rec.Code)
+	}
+	dstPolicy, err := readBucketPolicy("dstbucket", objLayer)
+	if err != nil {
+		t.Fatalf("Expected dstbucket to have a bucket policy - %v", err)
+	}
+	if !dstPolicy.Statements[0].Resources.Contains("arn:aws:s3:::dstbucket/public/*") {
+		t.Fatalf("Expected policy resources to refer to dstbucket, got %v", dstPolicy.Statements[0].Resources)
+	}
+
+	// Invalid lifecycle in the bundle leaves dstbucket untouched.
+	invalidBundle := []byte(`{"version":"1","bucket":"srcbucket","lifecycle":"<LifecycleConfiguration>"}`)
+	if rec = sendRequest("PUT", "dstbucket", "import", invalidBundle); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected import of invalid bundle to fail with %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if _, err = readBucketPolicy("dstbucket", objLayer); err != nil {
+		t.Fatalf("Expected bucket policy of dstbucket to be kept - %v", err)
+	}
+
+	// Import an empty bundle into srcbucket, removing its policy.
+	emptyBundle := []byte(`{"version":"1","bucket":"srcbucket"}`)
+	if rec = sendRequest("PUT", "srcbucket", "import", emptyBundle); rec.Code != http.StatusOK {
+		t.Fatalf("Expected import to succeed but failed with %d", rec.Code)
+	}
+	if _, err = readBucketPolicy("srcbucket", objLayer); err == nil {
+		t.Fatal("Expected bucket policy of srcbucket to be removed")
+	}
+
+	// Re-import the exported bundle, restoring the policy.
+	if rec = sendRequest("PUT", "srcbucket", "import", bundleBytes); rec.Code != http.StatusOK {
+		t.Fatalf("Expected import to succeed but failed with %d", rec.Code)
+	}
+	if _, err = readBucketPolicy("srcbucket", objLayer); err != nil {
+		t.Fatalf("Expected bucket policy of srcbucket to be restored - %v", err)
+	}
+
+	// Unsupported bundle version.
+	if rec = sendRequest("PUT", "srcbucket", "import", []byte(`{"version":"2"}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected unsupported version to fail with %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	// Non-existent bucket.
+	if rec = sendRequest("GET", "nosuchbucket", "export", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected export of missing bucket to fail with %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// TestListUnicodeDuplicatesHandler - test for ListUnicodeDuplicatesHandler.
+func TestListUnicodeDuplicatesHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucket := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucket); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+	}
+	data := []byte("hello")
+	for _, object := range []string{nfcCafe, nfdCafe} {
+		_, err = adminTestBed.objLayer.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, "")
+		if err != nil {
+			t.Fatalf("Failed to create object %q - %v", object, err)
+		}
+	}
+
+	testCases := []struct {
+		bucket        string
+		expectedCode  int
+		expectedCount int
+	}{
+		// 1. Valid bucket with one group of duplicates.
+		{bucket, http.StatusOK, 1},
+		// 2. Invalid bucket name.
+		{"my", http.StatusBadRequest, 0},
+		// 3. Non-existent bucket.
+		{"nosuchbucket", http.StatusNotFound, 0},
+	}
+
+	cred := serverConfig.GetCredential()
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("heal", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+
+		req, err := newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct list unicode duplicates request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "list-unicode-duplicates")
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign list unicode duplicates request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status %d but received %d", i+1, test.expectedCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var duplicates []unicodeDuplicate
+		if err = json.NewDecoder(rec.Body).Decode(&duplicates); err != nil {
+			t.Fatalf("Test %d: Failed to decode list unicode duplicates result json %v", i+1, err)
+		}
+		if len(duplicates) != test.expectedCount {
+			t.Errorf("Test %d: Expected %d duplicates, got %d", i+1, test.expectedCount, len(duplicates))
+		}
+	}
+}
+
+// TestAdminCredentials - tests that admin credentials are limited to
+// their actions and are not accepted by the S3 API.
+func TestAdminCredentials(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	adminCred := newCredentialWithKeys("ADMINSERVICEKEY", "adminservicesecret")
+	globalAdminCredentials, err = newAdminCredentials([]adminCredentialConfig{{
+		AccessKey: adminCred.AccessKey,
+		SecretKey: adminCred.SecretKey,
+		Actions:   []string{adminActionService},
+	}}, serverConfig.GetCredential())
+	if err != nil {
+		t.Fatalf("Unable to create admin credentials - %v", err)
+	}
+	defer func() { globalAdminCredentials = nil }()
+
+	// Service status is allowed.
+	req, err := getServiceCmdRequest(statusCmd, adminCred, nil)
+	if err != nil {
+		t.Fatalf("Failed to build service status request %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected service status to succeed but failed with %d", rec.Code)
+	}
+
+	// Service status with a wrong secret key is denied.
+	req, err = getServiceCmdRequest(statusCmd, credential{AccessKey: adminCred.AccessKey, SecretKey: "wrongadminsecret"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build service status request %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected service status to fail with %d but got %d", http.StatusForbidden, rec.Code)
+	}
+
+	// Get config is not among the actions, so it is denied.
+	req, err = newTestRequest("GET", "/?config=", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct get-config request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "get")
+	if err = signRequestV4(req, adminCred.AccessKey, adminCred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign get-config request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected get config to fail with %d but got %d", http.StatusForbidden, rec.Code)
+	}
+
+	// Admin credentials have no access to the S3 API.
+	if s3Error := isReqAuthenticated(req, ""); s3Error != ErrInvalidAccessKeyID {
+		t.Errorf("Expected S3 authentication to fail with %v but got %v", ErrInvalidAccessKeyID, s3Error)
+	}
+}
+
+// TestListAdminAuditHandler - tests that admin calls are recorded in
+// the admin audit log.
+func TestListAdminAuditHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+
+	// Signed service status is recorded.
+	req, err := getServiceCmdRequest(statusCmd, cred, nil)
+	if err != nil {
+		t.Fatalf("Failed to build service status request %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected service status to succeed but failed with %d", rec.Code)
+	}
+
+	// Service status failing signature verification is not recorded.
+	req, err = getServiceCmdRequest(statusCmd, credential{AccessKey: cred.AccessKey, SecretKey: "wrongserversecret"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build service status request %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected service status to fail with %d but got %d", http.StatusForbidden, rec.Code)
+	}
+
+	queryVal := url.Values{}
+	queryVal.Set("audit", "")
+	queryVal.Set("date", time.Now().UTC().Format(adminAuditDateFormat))
+	req, err = newTestRequest("GET", "/?"+queryVal.Encode(), 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct list audit request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "list")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign list audit request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected list audit to succeed but failed with %d", rec.Code)
+	}
+
+	var auditLog adminAuditLog
+	if err = json.Unmarshal(rec.Body.Bytes(), &auditLog); err != nil {
+		t.Fatalf("Failed to unmarshal audit log - %v", err)
+	}
+	if len(auditLog.Entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(auditLog.Entries))
+	}
+	entry := auditLog.Entries[0]
+	if entry.Operation != "service.status" || entry.AccessKey != cred.AccessKey || entry.Status != http.StatusOK {
+		t.Errorf("Unexpected audit entry %#v", entry)
+	}
+	if auditLog.BrokenAt != -1 {
+		t.Errorf("Expected an intact audit log, broken at %d", auditLog.BrokenAt)
+	}
+
+	// Invalid date.
+	req, err = newTestRequest("GET", "/?audit=&date=yesterday", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct list audit request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "list")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign list audit request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected list audit to fail with %d but got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	// Bodies too large are refused before reading them.
+	req, err = newTestRequest("GET", "/?audit=", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct list audit request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "list")
+	req.ContentLength = maxAdminRequestSize + 1
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "EntityTooLarge") {
+		t.Errorf("Expected list audit to fail with EntityTooLarge but got %d", rec.Code)
+	}
+}
+
+// TestServerInfoHandler - test for ServerInfoHandler.
+func TestServerInfoHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	req, err := newTestRequest("GET", "/?info", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct server info request - %v", err)
+	}
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign server info request - %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected to succeed but failed with %d", rec.Code)
+	}
+
+	var infos []ServerInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("Failed to unmarshal server info - %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("Expected info of 1 server, got %d", len(infos))
+	}
+	info := infos[0]
+	if !info.Online || info.Data == nil {
+		t.Fatalf("Expected the server to be online, got %#v", info)
+	}
+	if info.Data.Properties.Version != Version {
+		t.Errorf("Expected version %s, got %s", Version, info.Data.Properties.Version)
+	}
+	if len(info.Data.Disks) != len(adminTestBed.xlDirs) {
+		t.Fatalf("Expected %d local disks, got %d", len(adminTestBed.xlDirs), len(info.Data.Disks))
+	}
+	for _, diskInfo := range info.Data.Disks {
+		if !diskInfo.Online {
+			t.Errorf("Expected disk %s to be online, got error %s", diskInfo.Path, diskInfo.Error)
+		}
+	}
+}
+
+// TestSetRequestLimitHandler - test for SetRequestLimitHandler.
+func TestSetRequestLimitHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+	testCases := []struct {
+		query      string
+		expectCode int
+		limit      requestLimitConfig
+	}{
+		{"?maxConcurrent=10&maxRequestsPerSecond=100", http.StatusOK, requestLimitConfig{MaxConcurrent: 10, MaxRequestsPerSecond: 100}},
+		{"?accessKey=" + cred.AccessKey + "&maxConcurrent=5", http.StatusOK, requestLimitConfig{MaxConcurrent: 5}},
+		{"?maxConcurrent=-1", http.StatusBadRequest, requestLimitConfig{MaxConcurrent: 5}},
+		{"?maxRequestsPerSecond=ten", http.StatusBadRequest, requestLimitConfig{MaxConcurrent: 5}},
+		{"?accessKey=unknown&maxConcurrent=1", http.StatusBadRequest, requestLimitConfig{MaxConcurrent: 5}},
+		{"", http.StatusOK, requestLimitConfig{}},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("PUT", adminAPIPathPrefix+"/request-limit"+testCase.query, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+		if limit := serverConfig.GetRequestLimits()[cred.AccessKey]; limit != testCase.limit {
+			t.Errorf("Test %d: Expected request limits %v, got %v", i+1, testCase.limit, limit)
+		}
+	}
+}
+
+// TestRevokeBrowserSessionsHandler - test for RevokeBrowserSessionsHandler.
+func TestRevokeBrowserSessionsHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+	before := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		query         string
+		expectCode    int
+		revokedBefore time.Time
+	}{
+		{"?before=" + before.Format(time.RFC3339), http.StatusOK, before},
+		{"?before=yesterday", http.StatusBadRequest, before},
+		{"?before=" + time.Now().UTC().Add(time.Hour).Format(time.RFC3339), http.StatusBadRequest, before},
+		// Revocations are never moved back in time.
+		{"?before=" + before.Add(-time.Hour).Format(time.RFC3339), http.StatusOK, before},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("POST", adminAPIPathPrefix+"/browser-session/revoke"+testCase.query, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+		if revokedBefore := serverConfig.GetBrowserSession().RevokedBefore; !revokedBefore.Equal(testCase.revokedBefore) {
+			t.Errorf("Test %d: Expected revocation at %s, got %s", i+1, testCase.revokedBefore, revokedBefore)
+		}
+	}
+
+	// Without a time, sessions started until now are revoked.
+	req, err := newTestRequest("POST", "/?browser-session", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "revoke")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if revokedBefore := serverConfig.GetBrowserSession().RevokedBefore; !revokedBefore.After(before) {
+		t.Fatalf("Expected revocation at the current time, got %s", revokedBefore)
+	}
+}
+
+// TestClearLocksApproval - tests that clearing locks needs an approval
+// token of a second credential, used once and recorded in the admin
+// audit log.
+func TestClearLocksApproval(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	serverCred := serverConfig.GetCredential()
+	adminCred := newCredentialWithKeys("ADMINLOCKKEY", "adminlocksecret")
+	globalAdminCredentials, err = newAdminCredentials([]adminCredentialConfig{{
+		AccessKey: adminCred.AccessKey,
+		SecretKey: adminCred.SecretKey,
+		Actions:   []string{adminActionLock},
+	}}, serverCred)
+	if err != nil {
+		t.Fatalf("Unable to create admin credentials - %v", err)
+	}
+	defer func() { globalAdminCredentials = nil }()
+
+	serverConfig.SetApproval(approvalConfig{Enabled: true})
+	defer serverConfig.SetApproval(approvalConfig{})
+
+	sendRequest := func(method, path string, queryVal url.Values, cred credential, token string) *httptest.ResponseRecorder {
+		req, rerr := newTestRequest(method, path+"?"+queryVal.Encode(), 0, nil)
+		if rerr != nil {
+			t.Fatalf("Failed to construct request - %v", rerr)
+		}
+		if token != "" {
+			req.Header.Set(minioApprovalTokenHeader, token)
+		}
+		if rerr = signRequestV4(req, cred.AccessKey, cred.SecretKey); rerr != nil {
+			t.Fatalf("Failed to sign request - %v", rerr)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		return rec
+	}
+	issueApproval := func(cred credential, operation, bucket string) string {
+		rec := sendRequest("POST", "/minio/admin/v1/approval", url.Values{"operation": {operation}, "bucket": {bucket}}, cred, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected approval of %s to succeed but failed with %d", operation, rec.Code)
+		}
+		var result approvalResult
+		if err = json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result.Token
+	}
+	clearLocks := func(cred credential, token string) int {
+		return sendRequest("DELETE", "/minio/admin/v1/locks", mkLockQueryVal("mybucket", "", "1s"), cred, token).Code
+	}
+
+	// Approvals are limited to the actions of the approver.
+	if rec := sendRequest("POST", "/minio/admin/v1/approval", url.Values{"operation": {"heal.format"}}, adminCred, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected approval of heal format to fail with %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	if rec := sendRequest("POST", "/minio/admin/v1/approval", url.Values{"operation": {"config.get"}}, adminCred, ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected approval of unknown operation to fail with %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	if code := clearLocks(serverCred, ""); code != http.StatusForbidden {
+		t.Fatalf("Expected clear locks without approval to fail with %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Approved by the admin credential, used by the server credential.
+	token := issueApproval(adminCred, "lock.clear", "mybucket")
+
+	// Tokens are not used up by failed operations.
+	queryVal := mkLockQueryVal("mybucket", "", "1s")
+	queryVal.Set(string(mgmtNode), "unknown-node:9000")
+	if rec := sendRequest("DELETE", "/minio/admin/v1/locks", queryVal, serverCred, token); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected clear locks of an unknown node to fail with %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	if code := clearLocks(serverCred, token); code != http.StatusOK {
+		t.Fatalf("Expected approved clear locks to succeed, got %d", code)
+	}
+	auditLog, err := getAdminAuditLog(adminTestBed.objLayer, time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastEntry := auditLog.Entries[len(auditLog.Entries)-1]
+	if lastEntry.Operation != "lock.clear" || lastEntry.AccessKey != serverCred.AccessKey || lastEntry.Approver != adminCred.AccessKey {
+		t.Fatalf("Unexpected audit entry %#v", lastEntry)
+	}
+	if auditLog.BrokenAt != -1 {
+		t.Fatal("Expected audit log to be intact")
+	}
+
+	// Tokens are used once.
+	if code := clearLocks(serverCred, token); code != http.StatusForbidden {
+		t.Fatalf("Expected reuse of approval token to fail with %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Approvers cannot approve their own operations.
+	token = issueApproval(adminCred, "lock.clear", "mybucket")
+	if code := clearLocks(adminCred, token); code != http.StatusForbidden {
+		t.Fatalf("Expected self approved clear locks to fail with %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Tokens are bound to the bucket.
+	token = issueApproval(adminCred, "lock.clear", "otherbucket")
+	if code := clearLocks(serverCred, token); code != http.StatusForbidden {
+		t.Fatalf("Expected clear locks approved for another bucket to fail with %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Setting the config and restoring a backup need an approval of a
+	// credential allowed to change the config.
+	for _, operation := range []string{"config.set", "backup.restore"} {
+		if rec := sendRequest("POST", "/minio/admin/v1/approval", url.Values{"operation": {operation}}, adminCred, ""); rec.Code != http.StatusForbidden {
+			t.Fatalf("Expected approval of %s to fail with %d, got %d", operation, http.StatusForbidden, rec.Code)
+		}
+	}
+	if rec := sendRequest("PUT", "/minio/admin/v1/config", url.Values{}, serverCred, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected set config without approval to fail with %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+// TestSetBrowserReadOnlyHandler - test for SetBrowserReadOnlyHandler.
+func TestSetBrowserReadOnlyHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+	testCases := []struct {
+		query      string
+		expectCode int
+		readOnly   bool
+	}{
+		{"?enable=true", http.StatusOK, true},
+		{"?enable=maybe", http.StatusBadRequest, true},
+		{"", http.StatusBadRequest, true},
+		{"?enable=false", http.StatusOK, false},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("PUT", adminAPIPathPrefix+"/browser/read-only"+testCase.query, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+		if readOnly := serverConfig.GetBrowserReadOnly(); readOnly != testCase.readOnly {
+			t.Errorf("Test %d: Expected read-only %v, got %v", i+1, testCase.readOnly, readOnly)
+		}
+	}
+
+	// Legacy form of the operation.
+	req, err := newTestRequest("PUT", "/?browser-read-only&enable=true", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "set")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !serverConfig.GetBrowserReadOnly() {
+		t.Fatal("Expected the browser to be read-only")
+	}
+}
+
+// TestSetBucketNetworkHandler - test for SetBucketNetworkHandler.
+func TestSetBucketNetworkHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+	testCases := []struct {
+		query      string
+		expectCode int
+		network    bucketNetworkConfig
+	}{
+		{"?bucket=mybucket&allow=10.0.0.0/8&allow=192.168.0.0/16&deny=10.0.0.1", http.StatusOK,
+			bucketNetworkConfig{Allow: []string{"10.0.0.0/8", "192.168.0.0/16"}, Deny: []string{"10.0.0.1"}}},
+		{"?bucket=mybucket&allow=10.0.0.0/40", http.StatusBadRequest,
+			bucketNetworkConfig{Allow: []string{"10.0.0.0/8", "192.168.0.0/16"}, Deny: []string{"10.0.0.1"}}},
+		{"?bucket=My_Bucket&allow=10.0.0.0/8", http.StatusBadRequest,
+			bucketNetworkConfig{Allow: []string{"10.0.0.0/8", "192.168.0.0/16"}, Deny: []string{"10.0.0.1"}}},
+		// Without networks, any client is allowed.
+		{"?bucket=mybucket", http.StatusOK, bucketNetworkConfig{}},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("PUT", adminAPIPathPrefix+"/bucket-network"+testCase.query, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+		if network := serverConfig.GetBucketNetworks()["mybucket"]; !reflect.DeepEqual(network, testCase.network) {
+			t.Errorf("Test %d: Expected bucket networks %v, got %v", i+1, testCase.network, network)
+		}
+	}
+}
+
+// TestIAMUserHandlers - test for the IAM user admin handlers.
+func TestIAMUserHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+	defer resetIAMUsers()
+
+	// Initialize admin peers to make admin RPC calls.
+	eps, err := parseStorageEndpoints([]string{"http://127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Failed to parse storage end point - %v", err)
+	}
+
+	// Set globalMinioAddr to be able to distinguish local endpoints from remote.
+	globalMinioAddr = eps[0].Host
+	initGlobalAdminPeers(eps)
+
+	cred := serverConfig.GetCredential()
+	policy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::*"]}]}`
+	testCases := []struct {
+		method     string
+		path       string
+		body       string
+		accessKey  string
+		secretKey  string
+		expectCode int
+		expectUser iamUserConfig
+	}{
+		{"PUT", "/iam-user?accessKey=minio-user", `{"secretKey": "minio-user-secret", "cannedPolicy": "readonly"}`,
+			cred.AccessKey, cred.SecretKey, http.StatusOK,
+			iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}},
+		// IAM users may not use the admin API.
+		{"PUT", "/iam-user?accessKey=minio-user2", `{"secretKey": "minio-user-secret", "cannedPolicy": "readwrite"}`,
+			"minio-user", "minio-user-secret", http.StatusForbidden,
+			iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}},
+		{"PUT", "/iam-user?accessKey=" + cred.AccessKey, `{"secretKey": "minio-user-secret", "cannedPolicy": "readonly"}`,
+			cred.AccessKey, cred.SecretKey, http.StatusBadRequest,
+			iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}},
+		{"PUT", "/iam-user?accessKey=minio-user", `{"secretKey": "minio-user-secret", "cannedPolicy": "readmost"}`,
+			cred.AccessKey, cred.SecretKey, http.StatusBadRequest,
+			iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}},
+		// The secret key is kept when the policy is set.
+		{"PUT", "/iam-user/policy?accessKey=minio-user", `{"policy": ` + policy + `}`,
+			cred.AccessKey, cred.SecretKey, http.StatusOK,
+			iamUserConfig{SecretKey: "minio-user-secret", Policy: json.RawMessage(policy)}},
+		{"PUT", "/iam-user/policy?accessKey=minio-other", `{"cannedPolicy": "readonly"}`,
+			cred.AccessKey, cred.SecretKey, http.StatusNotFound,
+			iamUserConfig{SecretKey: "minio-user-secret", Policy: json.RawMessage(policy)}},
+		{"DELETE", "/iam-user?accessKey=minio-user", "",
+			cred.AccessKey, cred.SecretKey, http.StatusOK, iamUserConfig{}},
+		{"DELETE", "/iam-user?accessKey=minio-user", "",
+			cred.AccessKey, cred.SecretKey, http.StatusNotFound, iamUserConfig{}},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest(testCase.method, adminAPIPathPrefix+testCase.path,
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct request - %v", i+1, err)
+		}
+		if err = signRequestV4(req, testCase.accessKey, testCase.secretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+		if user := serverConfig.GetUsers()["minio-user"]; !reflect.DeepEqual(user, testCase.expectUser) {
+			t.Errorf("Test %d: Expected user %v, got %v", i+1, testCase.expectUser, user)
+		}
+		if _, ok := getIAMUser("minio-user"); ok == testCase.expectUser.isEmpty() {
+			t.Errorf("Test %d: Expected the user to exist %v", i+1, !testCase.expectUser.isEmpty())
+		}
+	}
+
+	// Secret keys are never listed.
+	if err = setIAMUser("minio-user", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyWriteOnly}); err != nil {
+		t.Fatal(err)
+	}
+	req, err := newTestRequest("GET", adminAPIPathPrefix+"/iam-users", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct request - %v", err)
+	}
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte("minio-user-secret")) {
+		t.Error("Expected secret keys not to be listed")
+	}
+	var infos []iamUserInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatal(err)
+	}
+	expected := []iamUserInfo{{AccessKey: "minio-user", CannedPolicy: iamPolicyWriteOnly}}
+	if !reflect.DeepEqual(infos, expected) {
+		t.Errorf("Expected users %v, got %v", expected, infos)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// States of heal sequences.
+	healSequenceRunning = "running"
+	healSequenceDone    = "done"
+	healSequenceFailed  = "failed"
+
+	// Finished heal sequences are kept this long for status requests.
+	healSequenceKeepDuration = time.Hour
+
+	// Interval between two progress updates of a heal status stream.
+	healStatusInterval = time.Second
+)
+
+var (
+	// errHealAlreadyRunning - a heal sequence is running on the same
+	// bucket and prefix.
+	errHealAlreadyRunning = errors.New("Heal sequence already running")
+
+	// errHealStopped - the server stopped before the sequence finished.
+	errHealStopped = errors.New("Server stopped before healing finished")
+)
+
+// HealSequenceStatus - progress of a heal sequence, streamed to
+// clients while it runs.
+type HealSequenceStatus struct {
+	Token     string    `json:"token"`
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix,omitempty"`
+	State     string    `json:"state"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+	// Objects listed, healed and failed to heal so far, objects which
+	// need no healing are only scanned.
+	ObjectsScanned int64 `json:"objectsScanned"`
+	ObjectsHealed  int64 `json:"objectsHealed"`
+	ObjectsFailed  int64 `json:"objectsFailed"`
+	BytesHealed    int64 `json:"bytesHealed"`
+	// Last error healing an object, or the error which ended the
+	// sequence if it failed.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// healSequence - heals a bucket and the objects under a prefix in the
+// background.
+type healSequence struct {
+	mu     sync.Mutex
+	status HealSequenceStatus
+	// Closed once the sequence has finished.
+	doneCh chan struct{}
+}
+
+// Status - returns a snapshot of the progress of the sequence.
+func (h *healSequence) Status() HealSequenceStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// update - applies fn to the status of the sequence.
+func (h *healSequence) update(fn func(status *HealSequenceStatus)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(&h.status)
+}
+
+// finish - ends the sequence, failed if err is not nil.
+func (h *healSequence) finish(err error) {
+	h.update(func(status *HealSequenceStatus) {
+		status.State = healSequenceDone
+		if err != nil {
+			status.State = healSequenceFailed
+			status.LastError = errorCause(err).Error()
+		}
+		status.EndTime = time.Now().UTC()
+	})
+	close(h.doneCh)
+}
+
+// healSequences - heal sequences of this server by token.
+type healSequences struct {
+	mu        sync.Mutex
+	sequences map[string]*healSequence
+}
+
+// Heal sequences started on this server.
+var globalHealSequences = &healSequences{sequences: make(map[string]*healSequence)}
+
+// start - starts healing bucket and the objects under prefix of xl,
+// unless a sequence is running on the same bucket and prefix.
+func (s *healSequences) start(xl *xlObjects, bucket, prefix string) (*healSequence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for token, seq := range s.sequences {
+		status := seq.Status()
+		if status.State == healSequenceRunning {
+			if status.Bucket == bucket && status.Prefix == prefix {
+				return nil, errHealAlreadyRunning
+			}
+			continue
+		}
+		// Forget sequences which finished long ago.
+		if now.Sub(status.EndTime) > healSequenceKeepDuration {
+			delete(s.sequences, token)
+		}
+	}
+
+	seq := &healSequence{
+		status: HealSequenceStatus{
+			Token:     mustGetUUID(),
+			Bucket:    bucket,
+			Prefix:    prefix,
+			State:     healSequenceRunning,
+			StartTime: now,
+		},
+		doneCh: make(chan struct{}),
+	}
+	s.sequences[seq.status.Token] = seq
+	go func() {
+		seq.finish(seq.run(xl))
+	}()
+	return seq, nil
+}
+
+// get - returns the sequence with token, nil if none.
+func (s *healSequences) get(token string) *healSequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sequences[token]
+}
+
+// run - heals the bucket, then lists the objects under the prefix and
+// heals those which need it. Failures to heal an object are counted,
+// the sequence goes on with the next object.
+func (h *healSequence) run(xl *xlObjects) error {
+	status := h.Status()
+	bucket, prefix := status.Bucket, status.Prefix
+	if err := xl.HealBucket(bucket); err != nil {
+		return err
+	}
+
+	// Walk the entries merged from all disks, objects missing on
+	// some of the disks would not be listed by a regular listing.
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+	listDir := listDirHealFactory(xl.isObject, xl.storageDisks...)
+	walkResultCh := startTreeWalk(bucket, prefix, "", true, listDir, nil, nil, endWalkCh)
+	for walkResult := range walkResultCh {
+		select {
+		case <-globalServiceDoneCh:
+			return errHealStopped
+		default:
+		}
+
+		if walkResult.err != nil {
+			// File not found is a valid case.
+			if walkResult.err == errFileNotFound {
+				return nil
+			}
+			return walkResult.err
+		}
+		// Directories have no erasure coded data.
+		if hasSuffix(walkResult.entry, slashSeparator) {
+			continue
+		}
+		h.healObject(xl, bucket, walkResult.entry)
+	}
+	return nil
+}
+
+// healObject - heals an object if it needs healing.
+func (h *healSequence) healObject(xl *xlObjects, bucket, object string) {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	partsMetadata, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	objectLock.RUnlock()
+
+	if !xlShouldHeal(partsMetadata, errs) {
+		h.update(func(status *HealSequenceStatus) {
+			status.ObjectsScanned++
+		})
+		return
+	}
+
+	err := xl.HealObject(bucket, object)
+	errorIf(err, "Unable to heal %s/%s.", bucket, object)
+	var objInfo ObjectInfo
+	if err == nil {
+		objInfo, err = xl.getObjectInfo(bucket, object)
+	}
+	h.update(func(status *HealSequenceStatus) {
+		status.ObjectsScanned++
+		if err != nil {
+			status.ObjectsFailed++
+			status.LastError = errorCause(err).Error()
+			return
+		}
+		status.ObjectsHealed++
+		status.BytesHealed += objInfo.Size
+	})
+}
@@ -0,0 +1,119 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// Tests that a heal sequence heals the objects under its prefix and
+// counts its progress.
+func TestHealSequence(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+	initNSLock(false)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	for _, object := range []string{"dir/healthy", "dir/sick", "other/sick"} {
+		if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Remove the objects from the first disk, to simulate the case
+	// where the disk was down when they were created.
+	for _, object := range []string{"dir/sick", "other/sick"} {
+		if err = os.RemoveAll(path.Join(fsDirs[0], bucket, object)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seqs := &healSequences{sequences: make(map[string]*healSequence)}
+	seq, err := seqs.start(xl, bucket, "dir/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seqs.get(seq.Status().Token) != seq {
+		t.Fatal("Expected to find the heal sequence by its token")
+	}
+
+	select {
+	case <-seq.doneCh:
+	case <-time.After(time.Minute):
+		t.Fatal("Heal sequence did not finish")
+	}
+
+	status := seq.Status()
+	if status.State != healSequenceDone {
+		t.Fatalf("Expected heal sequence to be done, got %s - %s", status.State, status.LastError)
+	}
+	if status.ObjectsScanned != 2 || status.ObjectsHealed != 1 || status.ObjectsFailed != 0 {
+		t.Errorf("Expected 2 objects scanned, 1 healed and 0 failed, got %d, %d and %d",
+			status.ObjectsScanned, status.ObjectsHealed, status.ObjectsFailed)
+	}
+	if status.BytesHealed != int64(len(data)) {
+		t.Errorf("Expected %d bytes healed, got %d", len(data), status.BytesHealed)
+	}
+
+	// Only the object under the prefix is healed.
+	if _, err = readXLMeta(xl.storageDisks[0], bucket, "dir/sick"); err != nil {
+		t.Errorf("Expected dir/sick to be healed, got %v", err)
+	}
+	if _, err = readXLMeta(xl.storageDisks[0], bucket, "other/sick"); err == nil {
+		t.Error("Expected other/sick not to be healed")
+	}
+}
+
+// Tests that only one heal sequence runs on a bucket and prefix.
+func TestHealSequenceAlreadyRunning(t *testing.T) {
+	seqs := &healSequences{sequences: make(map[string]*healSequence)}
+	running := &healSequence{
+		status: HealSequenceStatus{
+			Token:  mustGetUUID(),
+			Bucket: "bucket",
+			Prefix: "prefix",
+			State:  healSequenceRunning,
+		},
+		doneCh: make(chan struct{}),
+	}
+	seqs.sequences[running.status.Token] = running
+
+	if _, err := seqs.start(nil, "bucket", "prefix"); err != errHealAlreadyRunning {
+		t.Errorf("Expected %v, got %v", errHealAlreadyRunning, err)
+	}
+	if seqs.get("notoken") != nil {
+		t.Error("Expected no heal sequence for an unknown token")
+	}
+}
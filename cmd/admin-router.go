@@ -16,57 +16,234 @@
 
 package cmd
 
-import router "github.com/gorilla/mux"
+import (
+	"path"
+
+	router "github.com/gorilla/mux"
+)
+
+// Versioned admin REST API path prefix.
+var adminAPIPathPrefix = path.Join(minioReservedBucketPath, "admin", "v1")
 
 // adminAPIHandlers provides HTTP handlers for Minio admin API.
 type adminAPIHandlers struct {
 }
 
-// registerAdminRouter - Add handler functions for each service REST API routes.
+// registerAdminRouter - Add handler functions for each service REST API
+// routes, every call is recorded in the admin audit log. Operations are
+// served both under the versioned REST path prefix and, for backward
+// compatibility, on "/" routed by the x-minio-operation header.
 func registerAdminRouter(mux *router.Router) {
 
 	adminAPI := adminAPIHandlers{}
-	// Admin router
+
+	// Versioned admin router
+	adminV1Router := mux.NewRoute().PathPrefix(adminAPIPathPrefix).Subrouter()
+
+	/// Service operations
+
+	adminV1Router.Methods("GET").Path("/service").HandlerFunc(auditAdminHandler("service.status", adminAPI.ServiceStatusHandler))
+	adminV1Router.Methods("POST").Path("/service/restart").HandlerFunc(auditAdminHandler("service.restart", adminAPI.ServiceRestartHandler))
+	adminV1Router.Methods("PUT").Path("/service/credentials").HandlerFunc(auditAdminHandler("service.set-credentials", adminAPI.ServiceCredentialsHandler))
+
+	/// Info operations
+
+	adminV1Router.Methods("GET").Path("/info").HandlerFunc(auditAdminHandler("info", adminAPI.ServerInfoHandler))
+
+	/// Lock operations
+
+	adminV1Router.Methods("GET").Path("/locks").HandlerFunc(auditAdminHandler("lock.list", adminAPI.ListLocksHandler))
+	adminV1Router.Methods("DELETE").Path("/locks").HandlerFunc(auditAdminHandler("lock.clear", adminAPI.ClearLocksHandler))
+
+	/// Heal operations
+
+	adminV1Router.Methods("GET").Path("/heal/objects").HandlerFunc(auditAdminHandler("heal.list-objects", adminAPI.ListObjectsHealHandler))
+	adminV1Router.Methods("GET").Path("/heal/buckets").HandlerFunc(auditAdminHandler("heal.list-buckets", adminAPI.ListBucketsHealHandler))
+	adminV1Router.Methods("GET").Path("/heal/unicode-duplicates").HandlerFunc(auditAdminHandler("heal.list-unicode-duplicates", adminAPI.ListUnicodeDuplicatesHandler))
+	adminV1Router.Methods("POST").Path("/heal/bucket").HandlerFunc(auditAdminHandler("heal.bucket", adminAPI.HealBucketHandler))
+	adminV1Router.Methods("POST").Path("/heal/object").HandlerFunc(auditAdminHandler("heal.object", adminAPI.HealObjectHandler))
+	adminV1Router.Methods("POST").Path("/heal/format").HandlerFunc(auditAdminHandler("heal.format", adminAPI.HealFormatHandler))
+	adminV1Router.Methods("POST").Path("/heal/start").HandlerFunc(auditAdminHandler("heal.start", adminAPI.StartHealHandler))
+	adminV1Router.Methods("GET").Path("/heal/status").HandlerFunc(auditAdminHandler("heal.status", adminAPI.HealStatusHandler))
+	adminV1Router.Methods("GET").Path("/heal/quarantine").HandlerFunc(auditAdminHandler("heal.list-quarantined", adminAPI.ListQuarantinedHandler))
+	adminV1Router.Methods("POST").Path("/heal/quarantine/restore").HandlerFunc(auditAdminHandler("heal.restore-quarantined", adminAPI.RestoreQuarantinedHandler))
+
+	/// Config operations
+
+	adminV1Router.Methods("GET").Path("/config").HandlerFunc(auditAdminHandler("config.get", adminAPI.GetConfigHandler))
+	adminV1Router.Methods("PUT").Path("/config").HandlerFunc(auditAdminHandler("config.set", adminAPI.SetConfigHandler))
+
+	/// Policy operations
+
+	adminV1Router.Methods("POST").Path("/policy/validate").HandlerFunc(auditAdminHandler("policy.validate", adminAPI.ValidatePolicyHandler))
+	adminV1Router.Methods("GET").Path("/policy/simulate").HandlerFunc(auditAdminHandler("policy.simulate", adminAPI.SimulatePolicyHandler))
+
+	/// Bucket config operations
+
+	adminV1Router.Methods("GET").Path("/bucket-config").HandlerFunc(auditAdminHandler("bucket-config.export", adminAPI.ExportBucketConfigHandler))
+	adminV1Router.Methods("PUT").Path("/bucket-config").HandlerFunc(auditAdminHandler("bucket-config.import", adminAPI.ImportBucketConfigHandler))
+
+	/// Adopt operations
+
+	adminV1Router.Methods("POST").Path("/adopt").HandlerFunc(auditAdminHandler("adopt", adminAPI.AdoptObjectsHandler))
+
+	/// Audit operations
+
+	adminV1Router.Methods("GET").Path("/audit").HandlerFunc(auditAdminHandler("audit.list", adminAPI.ListAdminAuditHandler))
+
+	/// Presigned URL operations
+
+	adminV1Router.Methods("POST").Path("/presign/revoke").HandlerFunc(auditAdminHandler("presign.revoke", adminAPI.RevokePresignedHandler))
+
+	/// Request limit operations
+
+	adminV1Router.Methods("PUT").Path("/request-limit").HandlerFunc(auditAdminHandler("request-limit.set", adminAPI.SetRequestLimitHandler))
+
+	/// Bucket network operations
+
+	adminV1Router.Methods("PUT").Path("/bucket-network").HandlerFunc(auditAdminHandler("bucket-network.set", adminAPI.SetBucketNetworkHandler))
+
+	/// IAM user operations
+
+	adminV1Router.Methods("PUT").Path("/iam-user").HandlerFunc(auditAdminHandler("iam-user.add", adminAPI.AddIAMUserHandler))
+	adminV1Router.Methods("PUT").Path("/iam-user/policy").HandlerFunc(auditAdminHandler("iam-user.set-policy", adminAPI.SetIAMUserPolicyHandler))
+	adminV1Router.Methods("DELETE").Path("/iam-user").HandlerFunc(auditAdminHandler("iam-user.remove", adminAPI.RemoveIAMUserHandler))
+	adminV1Router.Methods("GET").Path("/iam-users").HandlerFunc(auditAdminHandler("iam-user.list", adminAPI.ListIAMUsersHandler))
+
+	/// Browser operations
+
+	adminV1Router.Methods("POST").Path("/browser-session/revoke").HandlerFunc(auditAdminHandler("browser-session.revoke", adminAPI.RevokeBrowserSessionsHandler))
+	adminV1Router.Methods("PUT").Path("/browser/read-only").HandlerFunc(auditAdminHandler("browser.read-only", adminAPI.SetBrowserReadOnlyHandler))
+
+	/// Backup operations
+
+	adminV1Router.Methods("POST").Path("/backup").HandlerFunc(auditAdminHandler("backup.create", adminAPI.CreateBackupHandler))
+	adminV1Router.Methods("POST").Path("/backup/restore").HandlerFunc(auditAdminHandler("backup.restore", adminAPI.RestoreBackupHandler))
+
+	/// Approval operations
+
+	adminV1Router.Methods("POST").Path("/approval").HandlerFunc(auditAdminHandler("approval.issue", adminAPI.IssueApprovalHandler))
+
+	// Legacy admin router, routed by the x-minio-operation header.
 	adminRouter := mux.NewRoute().PathPrefix("/").Subrouter()
 
 	/// Service operations
 
 	// Service status
-	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.ServiceStatusHandler)
+	adminRouter.Methods("GET").Queries("service", "").Headers(minioAdminOpHeader, "status").HandlerFunc(auditAdminHandler("service.status", adminAPI.ServiceStatusHandler))
 
 	// Service restart
-	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "restart").HandlerFunc(adminAPI.ServiceRestartHandler)
+	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "restart").HandlerFunc(auditAdminHandler("service.restart", adminAPI.ServiceRestartHandler))
 	// Service update credentials
-	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "set-credentials").HandlerFunc(adminAPI.ServiceCredentialsHandler)
+	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "set-credentials").HandlerFunc(auditAdminHandler("service.set-credentials", adminAPI.ServiceCredentialsHandler))
 
 	// Info operations
-	adminRouter.Methods("GET").Queries("info", "").HandlerFunc(adminAPI.ServerInfoHandler)
+	adminRouter.Methods("GET").Queries("info", "").HandlerFunc(auditAdminHandler("info", adminAPI.ServerInfoHandler))
 
 	/// Lock operations
 
 	// List Locks
-	adminRouter.Methods("GET").Queries("lock", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListLocksHandler)
+	adminRouter.Methods("GET").Queries("lock", "").Headers(minioAdminOpHeader, "list").HandlerFunc(auditAdminHandler("lock.list", adminAPI.ListLocksHandler))
 	// Clear locks
-	adminRouter.Methods("POST").Queries("lock", "").Headers(minioAdminOpHeader, "clear").HandlerFunc(adminAPI.ClearLocksHandler)
+	adminRouter.Methods("POST").Queries("lock", "").Headers(minioAdminOpHeader, "clear").HandlerFunc(auditAdminHandler("lock.clear", adminAPI.ClearLocksHandler))
 
 	/// Heal operations
 
 	// List Objects needing heal.
-	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-objects").HandlerFunc(adminAPI.ListObjectsHealHandler)
+	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-objects").HandlerFunc(auditAdminHandler("heal.list-objects", adminAPI.ListObjectsHealHandler))
 	// List Buckets needing heal.
-	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-buckets").HandlerFunc(adminAPI.ListBucketsHealHandler)
+	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-buckets").HandlerFunc(auditAdminHandler("heal.list-buckets", adminAPI.ListBucketsHealHandler))
+	// List objects whose names differ only in Unicode normalization.
+	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-unicode-duplicates").HandlerFunc(auditAdminHandler("heal.list-unicode-duplicates", adminAPI.ListUnicodeDuplicatesHandler))
 
 	// Heal Buckets.
-	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "bucket").HandlerFunc(adminAPI.HealBucketHandler)
+	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "bucket").HandlerFunc(auditAdminHandler("heal.bucket", adminAPI.HealBucketHandler))
 	// Heal Objects.
-	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "object").HandlerFunc(adminAPI.HealObjectHandler)
+	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "object").HandlerFunc(auditAdminHandler("heal.object", adminAPI.HealObjectHandler))
 	// Heal Format.
-	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "format").HandlerFunc(adminAPI.HealFormatHandler)
+	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "format").HandlerFunc(auditAdminHandler("heal.format", adminAPI.HealFormatHandler))
+	// Start heal sequence.
+	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "start").HandlerFunc(auditAdminHandler("heal.start", adminAPI.StartHealHandler))
+	// Stream heal sequence status.
+	adminRouter.Methods("GET").Queries("heal", "", "token", "{token:.*}").HandlerFunc(auditAdminHandler("heal.status", adminAPI.HealStatusHandler))
+	// List objects quarantined because of corrupted metadata.
+	adminRouter.Methods("GET").Queries("heal", "").Headers(minioAdminOpHeader, "list-quarantined").HandlerFunc(auditAdminHandler("heal.list-quarantined", adminAPI.ListQuarantinedHandler))
+	// Restore a quarantined object.
+	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "restore-quarantined").HandlerFunc(auditAdminHandler("heal.restore-quarantined", adminAPI.RestoreQuarantinedHandler))
 
 	/// Config operations
 
 	// Get config
-	adminRouter.Methods("GET").Queries("config", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetConfigHandler)
+	adminRouter.Methods("GET").Queries("config", "").Headers(minioAdminOpHeader, "get").HandlerFunc(auditAdminHandler("config.get", adminAPI.GetConfigHandler))
 	// Set Config
-	adminRouter.Methods("PUT").Queries("config", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetConfigHandler)
+	adminRouter.Methods("PUT").Queries("config", "").Headers(minioAdminOpHeader, "set").HandlerFunc(auditAdminHandler("config.set", adminAPI.SetConfigHandler))
+
+	/// Policy operations
+
+	// Validate bucket policy
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "validate").HandlerFunc(auditAdminHandler("policy.validate", adminAPI.ValidatePolicyHandler))
+	// Simulate policy evaluation
+	adminRouter.Methods("GET").Queries("policy", "").Headers(minioAdminOpHeader, "simulate").HandlerFunc(auditAdminHandler("policy.simulate", adminAPI.SimulatePolicyHandler))
+
+	/// Bucket config operations
+
+	// Export bucket config
+	adminRouter.Methods("GET").Queries("bucket-config", "").Headers(minioAdminOpHeader, "export").HandlerFunc(auditAdminHandler("bucket-config.export", adminAPI.ExportBucketConfigHandler))
+	// Import bucket config
+	adminRouter.Methods("PUT").Queries("bucket-config", "").Headers(minioAdminOpHeader, "import").HandlerFunc(auditAdminHandler("bucket-config.import", adminAPI.ImportBucketConfigHandler))
+
+	/// Adopt operations
+
+	// Adopt pre-existing files as objects
+	adminRouter.Methods("POST").Queries("adopt", "").Headers(minioAdminOpHeader, "adopt").HandlerFunc(auditAdminHandler("adopt", adminAPI.AdoptObjectsHandler))
+
+	/// Audit operations
+
+	// List admin audit log
+	adminRouter.Methods("GET").Queries("audit", "").Headers(minioAdminOpHeader, "list").HandlerFunc(auditAdminHandler("audit.list", adminAPI.ListAdminAuditHandler))
+
+	/// Presigned URL operations
+
+	// Revoke presigned URLs
+	adminRouter.Methods("POST").Queries("presign", "").Headers(minioAdminOpHeader, "revoke").HandlerFunc(auditAdminHandler("presign.revoke", adminAPI.RevokePresignedHandler))
+
+	/// Request limit operations
+
+	// Set request limits of an access key
+	adminRouter.Methods("PUT").Queries("request-limit", "").Headers(minioAdminOpHeader, "set").HandlerFunc(auditAdminHandler("request-limit.set", adminAPI.SetRequestLimitHandler))
+
+	/// Bucket network operations
+
+	// Set client networks allowed to access a bucket
+	adminRouter.Methods("PUT").Queries("bucket-network", "").Headers(minioAdminOpHeader, "set").HandlerFunc(auditAdminHandler("bucket-network.set", adminAPI.SetBucketNetworkHandler))
+
+	/// IAM user operations
+
+	// Add or replace an IAM user
+	adminRouter.Methods("PUT").Queries("iam-user", "").Headers(minioAdminOpHeader, "add").HandlerFunc(auditAdminHandler("iam-user.add", adminAPI.AddIAMUserHandler))
+	// Set the policy of an IAM user
+	adminRouter.Methods("PUT").Queries("iam-user", "").Headers(minioAdminOpHeader, "set-policy").HandlerFunc(auditAdminHandler("iam-user.set-policy", adminAPI.SetIAMUserPolicyHandler))
+	// Remove an IAM user
+	adminRouter.Methods("DELETE").Queries("iam-user", "").Headers(minioAdminOpHeader, "remove").HandlerFunc(auditAdminHandler("iam-user.remove", adminAPI.RemoveIAMUserHandler))
+	// List IAM users
+	adminRouter.Methods("GET").Queries("iam-user", "").Headers(minioAdminOpHeader, "list").HandlerFunc(auditAdminHandler("iam-user.list", adminAPI.ListIAMUsersHandler))
+
+	/// Browser operations
+
+	// Revoke browser sessions
+	adminRouter.Methods("POST").Queries("browser-session", "").Headers(minioAdminOpHeader, "revoke").HandlerFunc(auditAdminHandler("browser-session.revoke", adminAPI.RevokeBrowserSessionsHandler))
+	// Set browser read-only mode
+	adminRouter.Methods("PUT").Queries("browser-read-only", "").Headers(minioAdminOpHeader, "set").HandlerFunc(auditAdminHandler("browser.read-only", adminAPI.SetBrowserReadOnlyHandler))
+
+	/// Backup operations
+
+	// Back up configuration now
+	adminRouter.Methods("POST").Queries("backup", "").Headers(minioAdminOpHeader, "create").HandlerFunc(auditAdminHandler("backup.create", adminAPI.CreateBackupHandler))
+	// Restore configuration from a backup
+	adminRouter.Methods("POST").Queries("backup", "").Headers(minioAdminOpHeader, "restore").HandlerFunc(auditAdminHandler("backup.restore", adminAPI.RestoreBackupHandler))
+
+	/// Approval operations
+
+	// Issue an approval token of a destructive operation
+	adminRouter.Methods("POST").Queries("approval", "").Headers(minioAdminOpHeader, "issue").HandlerFunc(auditAdminHandler("approval.issue", adminAPI.IssueApprovalHandler))
 }
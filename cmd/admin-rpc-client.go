@@ -39,8 +39,21 @@ const (
 	getConfigRPC      = "Admin.GetConfig"
 	writeTmpConfigRPC = "Admin.WriteTmpConfig"
 	commitConfigRPC   = "Admin.CommitConfig"
+	serverInfoDataRPC = "Admin.ServerInfoData"
+	clearNodeLocksRPC = "Admin.ClearNodeLocks"
+	revokePresignRPC  = "Admin.RevokePresigned"
+	requestLimitRPC   = "Admin.SetRequestLimit"
+	revokeSessionsRPC = "Admin.RevokeBrowserSessions"
+	readOnlyRPC       = "Admin.SetBrowserReadOnly"
+	bucketNetworkRPC  = "Admin.SetBucketNetwork"
+	iamUserRPC        = "Admin.SetIAMUser"
 )
 
+// Maximum time to wait for a peer to reply with its server info.
+const adminPeerInfoTimeout = 10 * time.Second
+
+var errAdminPeerTimeout = errors.New("timed out waiting for the server to reply")
+
 // localAdminClient - represents admin operation to be executed locally.
 type localAdminClient struct {
 }
@@ -61,6 +74,14 @@ type adminCmdRunner interface {
 	GetConfig() ([]byte, error)
 	WriteTmpConfig(tmpFileName string, configBytes []byte) error
 	CommitConfig(tmpFileName string) error
+	ServerInfoData() (ServerInfoData, error)
+	ClearNodeLocks(node, bucket, prefix string, duration time.Duration) ([]string, error)
+	RevokePresigned(accessKey string, before time.Time) error
+	SetRequestLimit(accessKey string, limit requestLimitConfig) error
+	RevokeBrowserSessions(before time.Time) error
+	SetBrowserReadOnly(readOnly bool) error
+	SetBucketNetwork(bucket string, config bucketNetworkConfig) error
+	SetIAMUser(accessKey string, config iamUserConfig) error
 }
 
 // Restart - Sends a message over channel to the go-routine
@@ -96,6 +117,140 @@ func (rc remoteAdminClient) ListLocks(bucket, prefix string, duration time.Durat
 	return reply.volLocks, nil
 }
 
+// clearLocalNodeLocks - clears the locks held by node, by any node if
+// empty, on bucket matching prefix for longer than duration from the
+// local lock servers. Returns the lock resources cleared.
+func clearLocalNodeLocks(node, bucket, prefix string, duration time.Duration) []string {
+	resourcePrefix := bucket + slashSeparator + prefix
+	resources := []string{}
+	for _, locker := range globalLockServers {
+		resources = append(resources, locker.clearLocks(node, resourcePrefix, duration)...)
+	}
+	return resources
+}
+
+// ClearNodeLocks - Clears the locks held by node from the local lock
+// servers.
+func (lc localAdminClient) ClearNodeLocks(node, bucket, prefix string, duration time.Duration) ([]string, error) {
+	return clearLocalNodeLocks(node, bucket, prefix, duration), nil
+}
+
+// ClearNodeLocks - Clears the locks held by node from the lock
+// servers of the remote server, via RPC.
+func (rc remoteAdminClient) ClearNodeLocks(node, bucket, prefix string, duration time.Duration) ([]string, error) {
+	args := ClearNodeLocksArgs{
+		Node:     node,
+		Bucket:   bucket,
+		Prefix:   prefix,
+		Duration: duration,
+	}
+	var reply ClearNodeLocksReply
+	if err := rc.Call(clearNodeLocksRPC, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Resources, nil
+}
+
+// RevokePresigned - Revokes presigned URLs signed with accessKey
+// before the given time on this server.
+func (lc localAdminClient) RevokePresigned(accessKey string, before time.Time) error {
+	return revokePresigned(accessKey, before)
+}
+
+// RevokePresigned - Revokes presigned URLs signed with accessKey
+// before the given time on the remote server, via RPC.
+func (rc remoteAdminClient) RevokePresigned(accessKey string, before time.Time) error {
+	args := RevokePresignedArgs{
+		AccessKey: accessKey,
+		Before:    before,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(revokePresignRPC, &args, &reply)
+}
+
+// SetRequestLimit - Sets the request limits of accessKey on this
+// server.
+func (lc localAdminClient) SetRequestLimit(accessKey string, limit requestLimitConfig) error {
+	return setRequestLimit(accessKey, limit)
+}
+
+// SetRequestLimit - Sets the request limits of accessKey on the remote
+// server, via RPC.
+func (rc remoteAdminClient) SetRequestLimit(accessKey string, limit requestLimitConfig) error {
+	args := SetRequestLimitArgs{
+		AccessKey: accessKey,
+		Limit:     limit,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(requestLimitRPC, &args, &reply)
+}
+
+// RevokeBrowserSessions - Revokes browser sessions started before the
+// given time on this server.
+func (lc localAdminClient) RevokeBrowserSessions(before time.Time) error {
+	return revokeBrowserSessions(before)
+}
+
+// RevokeBrowserSessions - Revokes browser sessions started before the
+// given time on the remote server, via RPC.
+func (rc remoteAdminClient) RevokeBrowserSessions(before time.Time) error {
+	args := RevokeBrowserSessionsArgs{
+		Before: before,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(revokeSessionsRPC, &args, &reply)
+}
+
+// SetBrowserReadOnly - Makes the browser read-only, or writable again,
+// on this server.
+func (lc localAdminClient) SetBrowserReadOnly(readOnly bool) error {
+	return setBrowserReadOnly(readOnly)
+}
+
+// SetBrowserReadOnly - Makes the browser read-only, or writable again,
+// on the remote server, via RPC.
+func (rc remoteAdminClient) SetBrowserReadOnly(readOnly bool) error {
+	args := SetBrowserReadOnlyArgs{
+		ReadOnly: readOnly,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(readOnlyRPC, &args, &reply)
+}
+
+// SetBucketNetwork - Sets the client networks allowed to access bucket
+// on this server.
+func (lc localAdminClient) SetBucketNetwork(bucket string, config bucketNetworkConfig) error {
+	return setBucketNetwork(bucket, config)
+}
+
+// SetBucketNetwork - Sets the client networks allowed to access bucket
+// on the remote server, via RPC.
+func (rc remoteAdminClient) SetBucketNetwork(bucket string, config bucketNetworkConfig) error {
+	args := SetBucketNetworkArgs{
+		Bucket:  bucket,
+		Network: config,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(bucketNetworkRPC, &args, &reply)
+}
+
+// SetIAMUser - Adds, replaces or removes the IAM user with accessKey
+// on this server.
+func (lc localAdminClient) SetIAMUser(accessKey string, config iamUserConfig) error {
+	return setIAMUser(accessKey, config)
+}
+
+// SetIAMUser - Adds, replaces or removes the IAM user with accessKey
+// on the remote server, via RPC.
+func (rc remoteAdminClient) SetIAMUser(accessKey string, config iamUserConfig) error {
+	args := SetIAMUserArgs{
+		AccessKey: accessKey,
+		User:      config,
+	}
+	reply := AuthRPCReply{}
+	return rc.Call(iamUserRPC, &args, &reply)
+}
+
 // ReInitDisks - There is nothing to do here, heal format REST API
 // handler has already formatted and reinitialized the local disks.
 func (lc localAdminClient) ReInitDisks() error {
@@ -132,6 +287,23 @@ func (rc remoteAdminClient) Uptime() (time.Duration, error) {
 	return reply.Uptime, nil
 }
 
+// ServerInfoData - returns the server info of this server.
+func (lc localAdminClient) ServerInfoData() (ServerInfoData, error) {
+	return getLocalServerInfoData()
+}
+
+// ServerInfoData - returns the server info of the server to which
+// the RPC call is made.
+func (rc remoteAdminClient) ServerInfoData() (ServerInfoData, error) {
+	args := AuthRPCArgs{}
+	reply := ServerInfoDataReply{}
+	if err := rc.Call(serverInfoDataRPC, &args, &reply); err != nil {
+		return ServerInfoData{}, err
+	}
+
+	return reply.ServerInfoData, nil
+}
+
 // GetConfig - returns config.json of the local server.
 func (lc localAdminClient) GetConfig() ([]byte, error) {
 	if serverConfig == nil {
@@ -337,6 +509,45 @@ func listPeerLocksInfo(peers adminPeers, bucket, prefix string, duration time.Du
 	return groupedLockInfos, nil
 }
 
+// clearPeerNodeLocks - clears the locks held by node on bucket
+// matching prefix for longer than duration from the lock servers of
+// all peers, which need not include node itself. Returns the number
+// of lock resources cleared.
+func clearPeerNodeLocks(peers adminPeers, node, bucket, prefix string, duration time.Duration) (int, error) {
+	allResources := make([][]string, len(peers))
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			allResources[idx], errs[idx] = peer.cmdRunner.ClearNodeLocks(node, bucket, prefix, duration)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	// Summarizing errors received for ClearNodeLocks RPC across
+	// all nodes, a lock cleared on a minority of lock servers can
+	// not be acquired anyway.
+	errCount, err := reduceErrs(errs, []error{})
+	if err != nil {
+		if errCount >= (len(peers)/2 + 1) {
+			return 0, err
+		}
+		return 0, InsufficientReadQuorum{}
+	}
+
+	// Every lock is held on the lock servers of all nodes, count
+	// each resource once.
+	cleared := make(map[string]struct{})
+	for _, resources := range allResources {
+		for _, resource := range resources {
+			cleared[resource] = struct{}{}
+		}
+	}
+	return len(cleared), nil
+}
+
 // reInitPeerDisks - reinitialize disks and object layer on peer servers to use the new format.
 func reInitPeerDisks(peers adminPeers) error {
 	errs := make([]error, len(peers))
@@ -374,13 +585,14 @@ func (ts uptimeSlice) Swap(i, j int) {
 }
 
 // getPeerUptimes - returns the uptime since the last time read quorum
-// was established on success. Otherwise returns errXLReadQuorum.
-func getPeerUptimes(peers adminPeers) (time.Duration, error) {
+// was established on success, along with the errors of peers which
+// failed to reply. Otherwise returns errXLReadQuorum.
+func getPeerUptimes(peers adminPeers) (time.Duration, []error, error) {
 	// In a single node Erasure or FS backend setup the uptime of
 	// the setup is the uptime of the single minio server
 	// instance.
 	if !globalIsDistXL {
-		return time.Now().UTC().Sub(globalBootTime), nil
+		return time.Now().UTC().Sub(globalBootTime), nil, nil
 	}
 
 	uptimes := make(uptimeSlice, len(peers))
@@ -396,6 +608,12 @@ func getPeerUptimes(peers adminPeers) (time.Duration, error) {
 	}
 	wg.Wait()
 
+	// Save errors in peers order before sorting.
+	errs := make([]error, len(peers))
+	for i := range uptimes {
+		errs[i] = uptimes[i].err
+	}
+
 	// Sort uptimes in chronological order.
 	sort.Sort(uptimes)
 
@@ -420,17 +638,62 @@ func getPeerUptimes(peers adminPeers) (time.Duration, error) {
 	// Less than readQuorum "Admin.Uptime" RPC call returned
 	// successfully, so read-quorum unavailable.
 	if validCount < readQuorum {
-		return time.Duration(0), InsufficientReadQuorum{}
+		return time.Duration(0), errs, InsufficientReadQuorum{}
 	}
 
-	return latestUptime, nil
+	return latestUptime, errs, nil
+}
+
+// getPeerServerInfo - returns the server info of all peers, queried
+// concurrently. Peers which fail or do not reply within timeout are
+// reported as offline.
+func getPeerServerInfo(peers adminPeers, timeout time.Duration) []ServerInfo {
+	infos := make([]ServerInfo, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			infos[idx].Addr = peer.addr
+
+			type serverInfoResult struct {
+				data ServerInfoData
+				err  error
+			}
+			// Buffered, so that a late reply does not block the
+			// goroutine forever.
+			resultCh := make(chan serverInfoResult, 1)
+			go func() {
+				data, err := peer.cmdRunner.ServerInfoData()
+				resultCh <- serverInfoResult{data, err}
+			}()
+
+			var result serverInfoResult
+			select {
+			case result = <-resultCh:
+			case <-time.After(timeout):
+				result.err = errAdminPeerTimeout
+			}
+			if result.err != nil {
+				errorIf(result.err, "Unable to fetch server info from %s", peer.addr)
+				infos[idx].Error = result.err.Error()
+				return
+			}
+			infos[idx].Online = true
+			infos[idx].Data = &result.data
+		}(i, peer)
+	}
+	wg.Wait()
+	return infos
 }
 
 // getPeerConfig - Fetches config.json from all nodes in the setup and
-// returns the one that occurs in a majority of them.
-func getPeerConfig(peers adminPeers) ([]byte, error) {
+// returns the one that occurs in a majority of them, along with the
+// errors of nodes which failed to reply.
+func getPeerConfig(peers adminPeers) ([]byte, []error, error) {
 	if !globalIsDistXL {
-		return peers[0].cmdRunner.GetConfig()
+		configBytes, err := peers[0].cmdRunner.GetConfig()
+		return configBytes, nil, err
 	}
 
 	errs := make([]error, len(peers))
@@ -450,7 +713,7 @@ func getPeerConfig(peers adminPeers) ([]byte, error) {
 	// Find the maximally occurring config among peers in a
 	// distributed setup.
 
-	serverConfigs := make([]serverConfigV13, len(peers))
+	serverConfigs := make([]serverConfigV16, len(peers))
 	for i, configBytes := range configs {
 		if errs[i] != nil {
 			continue
@@ -460,24 +723,25 @@ func getPeerConfig(peers adminPeers) ([]byte, error) {
 		err := json.Unmarshal(configBytes, &serverConfigs[i])
 		if err != nil {
 			errorIf(err, "Failed to unmarshal serverConfig from ", peers[i].addr)
-			return nil, err
+			return nil, errs, err
 		}
 	}
 
 	configJSON, err := getValidServerConfig(serverConfigs, errs)
 	if err != nil {
 		errorIf(err, "Unable to find a valid server config")
-		return nil, traceError(err)
+		return nil, errs, traceError(err)
 	}
 
 	// Return the config.json that was present quorum or more
 	// number of disks.
-	return json.Marshal(configJSON)
+	configBytes, err := json.Marshal(configJSON)
+	return configBytes, errs, err
 }
 
 // getValidServerConfig - finds the server config that is present in
 // quorum or more number of servers.
-func getValidServerConfig(serverConfigs []serverConfigV13, errs []error) (serverConfigV13, error) {
+func getValidServerConfig(serverConfigs []serverConfigV16, errs []error) (serverConfigV16, error) {
 	// majority-based quorum
 	quorum := len(serverConfigs)/2 + 1
 
@@ -539,7 +803,7 @@ func getValidServerConfig(serverConfigs []serverConfigV13, errs []error) (server
 
 	// We find the maximally occurring server config and check if
 	// there is quorum.
-	var configJSON serverConfigV13
+	var configJSON serverConfigV16
 	maxOccurrence := 0
 	for i, count := range configCounter {
 		if maxOccurrence < count {
@@ -550,7 +814,7 @@ func getValidServerConfig(serverConfigs []serverConfigV13, errs []error) (server
 
 	// If quorum nodes don't agree.
 	if maxOccurrence < quorum {
-		return serverConfigV13{}, errXLWriteQuorum
+		return serverConfigV16{}, errXLWriteQuorum
 	}
 
 	return configJSON, nil
@@ -607,3 +871,99 @@ func commitConfigPeers(peers adminPeers, tmpFileName string) []error {
 	// Return errors (if any) received during rename.
 	return errs
 }
+
+// revokePeerPresigned - revokes presigned URLs signed with accessKey
+// before the given time on all peers.
+func revokePeerPresigned(peers adminPeers, accessKey string, before time.Time) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.RevokePresigned(accessKey, before)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// setPeerRequestLimit - sets the request limits of accessKey on all
+// peers.
+func setPeerRequestLimit(peers adminPeers, accessKey string, limit requestLimitConfig) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetRequestLimit(accessKey, limit)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// revokePeerBrowserSessions - revokes browser sessions started before
+// the given time on all peers.
+func revokePeerBrowserSessions(peers adminPeers, before time.Time) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.RevokeBrowserSessions(before)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// setPeerBrowserReadOnly - makes the browser read-only, or writable
+// again, on all peers.
+func setPeerBrowserReadOnly(peers adminPeers, readOnly bool) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetBrowserReadOnly(readOnly)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// setPeerBucketNetwork - sets the client networks allowed to access
+// bucket on all peers.
+func setPeerBucketNetwork(peers adminPeers, bucket string, config bucketNetworkConfig) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetBucketNetwork(bucket, config)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// setPeerIAMUser - adds, replaces or removes the IAM user with
+// accessKey on all peers.
+func setPeerIAMUser(peers adminPeers, accessKey string, config iamUserConfig) []error {
+	errs := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.SetIAMUser(accessKey, config)
+		}(i, peer)
+	}
+	wg.Wait()
+	return errs
+}
@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var (
@@ -30,6 +32,13 @@ var (
 		"secretKey": "minio123"
 	},
 	"region": "us-east-1",
+	"adminCredentials": [
+		{
+			"accessKey": "HEALBOTACCESSKEY",
+			"secretKey": "healbotsecretkey",
+			"actions": ["heal"]
+		}
+	],
 	"logger": {
 		"console": {
 			"enable": true,
@@ -220,7 +229,7 @@ var (
 
 // TestGetValidServerConfig - test for getValidServerConfig.
 func TestGetValidServerConfig(t *testing.T) {
-	var c1, c2 serverConfigV13
+	var c1, c2 serverConfigV16
 	err := json.Unmarshal(config1, &c1)
 	if err != nil {
 		t.Fatalf("json unmarshal of %s failed: %v", string(config1), err)
@@ -233,7 +242,7 @@ func TestGetValidServerConfig(t *testing.T) {
 
 	// Valid config.
 	noErrs := []error{nil, nil, nil, nil}
-	serverConfigs := []serverConfigV13{c1, c2, c1, c1}
+	serverConfigs := []serverConfigV16{c1, c2, c1, c1}
 	validConfig, err := getValidServerConfig(serverConfigs, noErrs)
 	if err != nil {
 		t.Errorf("Expected a valid config but received %v instead", err)
@@ -242,9 +251,12 @@ func TestGetValidServerConfig(t *testing.T) {
 	if !reflect.DeepEqual(validConfig, c1) {
 		t.Errorf("Expected valid config to be %v but received %v", config1, validConfig)
 	}
+	if len(validConfig.AdminCredentials) != 1 {
+		t.Errorf("Expected valid config to keep the admin credentials, got %v", validConfig.AdminCredentials)
+	}
 
 	// Invalid config - no quorum.
-	serverConfigs = []serverConfigV13{c1, c2, c2, c1}
+	serverConfigs = []serverConfigV16{c1, c2, c2, c1}
 	validConfig, err = getValidServerConfig(serverConfigs, noErrs)
 	if err != errXLWriteQuorum {
 		t.Errorf("Expected to fail due to lack of quorum but received %v", err)
@@ -252,9 +264,161 @@ func TestGetValidServerConfig(t *testing.T) {
 
 	// All errors
 	allErrs := []error{errDiskNotFound, errDiskNotFound, errDiskNotFound, errDiskNotFound}
-	serverConfigs = []serverConfigV13{{}, {}, {}, {}}
+	serverConfigs = []serverConfigV16{{}, {}, {}, {}}
 	validConfig, err = getValidServerConfig(serverConfigs, allErrs)
 	if err != errXLWriteQuorum {
 		t.Errorf("Expected to fail due to lack of quorum but received %v", err)
 	}
 }
+
+// serverInfoTestClient - admin client replying to ServerInfoData
+// after delay with err, if set.
+type serverInfoTestClient struct {
+	localAdminClient
+	delay time.Duration
+	err   error
+}
+
+func (c serverInfoTestClient) ServerInfoData() (ServerInfoData, error) {
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return ServerInfoData{}, c.err
+	}
+	return ServerInfoData{Properties: ServerProperties{Version: Version}}, nil
+}
+
+// TestGetPeerServerInfo - tests that unreachable peers are reported
+// as offline without failing the others.
+func TestGetPeerServerInfo(t *testing.T) {
+	errPeer := errors.New("peer is down")
+	peers := adminPeers{
+		{"127.0.0.1:9000", serverInfoTestClient{}},
+		{"127.0.0.2:9000", serverInfoTestClient{err: errPeer}},
+		{"127.0.0.3:9000", serverInfoTestClient{delay: time.Second}},
+	}
+
+	infos := getPeerServerInfo(peers, 100*time.Millisecond)
+	if len(infos) != len(peers) {
+		t.Fatalf("Expected %d server infos, got %d", len(peers), len(infos))
+	}
+	testCases := []struct {
+		online bool
+		err    error
+	}{
+		{true, nil},
+		{false, errPeer},
+		{false, errAdminPeerTimeout},
+	}
+	for i, testCase := range testCases {
+		info := infos[i]
+		if info.Addr != peers[i].addr {
+			t.Errorf("Test %d: Expected address %s, got %s", i+1, peers[i].addr, info.Addr)
+		}
+		if info.Online != testCase.online || (info.Data != nil) != testCase.online {
+			t.Errorf("Test %d: Expected online to be %v, got %#v", i+1, testCase.online, info)
+		}
+		if testCase.err != nil && info.Error != testCase.err.Error() {
+			t.Errorf("Test %d: Expected error %v, got %s", i+1, testCase.err, info.Error)
+		}
+	}
+	if infos[0].Data.Properties.Version != Version {
+		t.Errorf("Expected version %s, got %s", Version, infos[0].Data.Properties.Version)
+	}
+}
+
+// partialTestClient - admin client failing Uptime and GetConfig with
+// err, if set.
+type partialTestClient struct {
+	localAdminClient
+	err error
+}
+
+func (c partialTestClient) Uptime() (time.Duration, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return time.Hour, nil
+}
+
+func (c partialTestClient) GetConfig() ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return config1, nil
+}
+
+// TestGetPeersPartial - tests that uptime and config are returned
+// along with the errors of failed peers while a quorum replies.
+func TestGetPeersPartial(t *testing.T) {
+	globalIsDistXL = true
+	defer func() { globalIsDistXL = false }()
+
+	errPeer := errors.New("peer is down")
+	peers := adminPeers{
+		{"127.0.0.1:9000", partialTestClient{}},
+		{"127.0.0.2:9000", partialTestClient{}},
+		{"127.0.0.3:9000", partialTestClient{}},
+		{"127.0.0.4:9000", partialTestClient{err: errPeer}},
+	}
+
+	uptime, errs, err := getPeerUptimes(peers)
+	if err != nil {
+		t.Fatalf("Expected uptime to be returned, but failed with %v", err)
+	}
+	if uptime != time.Hour {
+		t.Errorf("Expected uptime %s, got %s", time.Hour, uptime)
+	}
+	failedNodes := getFailedNodes(peers, errs)
+	if len(failedNodes) != 1 || failedNodes[0].Name != "127.0.0.4:9000" || failedNodes[0].ErrMsg != errPeer.Error() {
+		t.Errorf("Expected 127.0.0.4:9000 to have failed, got %v", failedNodes)
+	}
+
+	_, errs, err = getPeerConfig(peers)
+	if err != nil {
+		t.Fatalf("Expected config to be returned, but failed with %v", err)
+	}
+	if failedNodes = getFailedNodes(peers, errs); len(failedNodes) != 1 || failedNodes[0].Name != "127.0.0.4:9000" {
+		t.Errorf("Expected 127.0.0.4:9000 to have failed, got %v", failedNodes)
+	}
+
+	// Without a quorum of replies the config is not returned.
+	peers[2].cmdRunner = partialTestClient{err: errPeer}
+	if _, _, err = getPeerConfig(peers); err == nil {
+		t.Errorf("Expected get config to fail without quorum")
+	}
+}
+
+// clearNodeLocksTestClient - admin client replying to ClearNodeLocks
+// with resources, or err if set.
+type clearNodeLocksTestClient struct {
+	localAdminClient
+	resources []string
+	err       error
+}
+
+func (c clearNodeLocksTestClient) ClearNodeLocks(node, bucket, prefix string, duration time.Duration) ([]string, error) {
+	return c.resources, c.err
+}
+
+// TestClearPeerNodeLocks - tests that locks cleared on several peers
+// are counted once and that a minority of failed peers is tolerated.
+func TestClearPeerNodeLocks(t *testing.T) {
+	errPeer := errors.New("peer is down")
+	peers := adminPeers{
+		{"127.0.0.1:9000", clearNodeLocksTestClient{resources: []string{"mybucket/obj1", "mybucket/obj2"}}},
+		{"127.0.0.2:9000", clearNodeLocksTestClient{resources: []string{"mybucket/obj2"}}},
+		{"127.0.0.3:9000", clearNodeLocksTestClient{err: errPeer}},
+	}
+	cleared, err := clearPeerNodeLocks(peers, "127.0.0.3:9000", "mybucket", "", 0)
+	if err != nil {
+		t.Fatalf("Expected to succeed but failed with %v", err)
+	}
+	if cleared != 2 {
+		t.Errorf("Expected 2 cleared locks, got %d", cleared)
+	}
+
+	peers[1].cmdRunner = clearNodeLocksTestClient{err: errPeer}
+	if _, err = clearPeerNodeLocks(peers, "127.0.0.3:9000", "mybucket", "", 0); err == nil {
+		t.Error("Expected to fail without a quorum of peers")
+	}
+}
@@ -59,10 +59,79 @@ type UptimeReply struct {
 	Uptime time.Duration
 }
 
+// ServerInfoDataReply - wraps the server info response over RPC.
+type ServerInfoDataReply struct {
+	AuthRPCReply
+	ServerInfoData ServerInfoData
+}
+
+// ClearNodeLocksArgs - wraps ClearNodeLocks API's query values to
+// send over RPC.
+type ClearNodeLocksArgs struct {
+	AuthRPCArgs
+	Node     string
+	Bucket   string
+	Prefix   string
+	Duration time.Duration
+}
+
+// ClearNodeLocksReply - wraps the resources whose locks were cleared
+// over RPC.
+type ClearNodeLocksReply struct {
+	AuthRPCReply
+	Resources []string
+}
+
+// RevokePresignedArgs - wraps RevokePresigned API's query values to
+// send over RPC.
+type RevokePresignedArgs struct {
+	AuthRPCArgs
+	AccessKey string
+	Before    time.Time
+}
+
+// SetRequestLimitArgs - wraps SetRequestLimit API's query values to
+// send over RPC.
+type SetRequestLimitArgs struct {
+	AuthRPCArgs
+	AccessKey string
+	Limit     requestLimitConfig
+}
+
+// RevokeBrowserSessionsArgs - wraps RevokeBrowserSessions API's query
+// values to send over RPC.
+type RevokeBrowserSessionsArgs struct {
+	AuthRPCArgs
+	Before time.Time
+}
+
+// SetBrowserReadOnlyArgs - wraps SetBrowserReadOnly API's query values
+// to send over RPC.
+type SetBrowserReadOnlyArgs struct {
+	AuthRPCArgs
+	ReadOnly bool
+}
+
+// SetBucketNetworkArgs - wraps SetBucketNetwork API's query values to
+// send over RPC.
+type SetBucketNetworkArgs struct {
+	AuthRPCArgs
+	Bucket  string
+	Network bucketNetworkConfig
+}
+
+// SetIAMUserArgs - wraps SetIAMUser API's access key and user to send
+// over RPC.
+type SetIAMUserArgs struct {
+	AuthRPCArgs
+	AccessKey string
+	User      iamUserConfig
+}
+
 // ConfigReply - wraps the server config response over RPC.
 type ConfigReply struct {
 	AuthRPCReply
-	Config []byte // json-marshalled bytes of serverConfigV13
+	Config []byte // json-marshalled bytes of serverConfigV16
 }
 
 // Restart - Restart this instance of minio server.
@@ -113,7 +182,7 @@ func (s *adminCmd) ReInitDisks(args *AuthRPCArgs, reply *AuthRPCReply) error {
 
 	// Replace object layer with newly formatted storage.
 	globalObjLayerMutex.Lock()
-	globalObjectAPI = newObjectAPI
+	globalObjectAPI = wrapObjectLayer(newObjectAPI)
 	globalObjLayerMutex.Unlock()
 
 	// Shutdown storage belonging to old object layer instance.
@@ -122,6 +191,92 @@ func (s *adminCmd) ReInitDisks(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	return nil
 }
 
+// ServerInfoData - returns the server info of this server.
+func (s *adminCmd) ServerInfoData(args *AuthRPCArgs, reply *ServerInfoDataReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	data, err := getLocalServerInfoData()
+	if err != nil {
+		return err
+	}
+
+	*reply = ServerInfoDataReply{ServerInfoData: data}
+	return nil
+}
+
+// ClearNodeLocks - clears the locks held by a node on the lock
+// servers of this server.
+func (s *adminCmd) ClearNodeLocks(args *ClearNodeLocksArgs, reply *ClearNodeLocksReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	resources := clearLocalNodeLocks(args.Node, args.Bucket, args.Prefix, args.Duration)
+	*reply = ClearNodeLocksReply{Resources: resources}
+	return nil
+}
+
+// RevokePresigned - revokes presigned URLs signed with an access key
+// before the given time on this server.
+func (s *adminCmd) RevokePresigned(args *RevokePresignedArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return revokePresigned(args.AccessKey, args.Before)
+}
+
+// SetRequestLimit - sets the request limits of an access key on this
+// server.
+func (s *adminCmd) SetRequestLimit(args *SetRequestLimitArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setRequestLimit(args.AccessKey, args.Limit)
+}
+
+// RevokeBrowserSessions - revokes browser sessions started before the
+// given time on this server.
+func (s *adminCmd) RevokeBrowserSessions(args *RevokeBrowserSessionsArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return revokeBrowserSessions(args.Before)
+}
+
+// SetBrowserReadOnly - makes the browser read-only, or writable again,
+// on this server.
+func (s *adminCmd) SetBrowserReadOnly(args *SetBrowserReadOnlyArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setBrowserReadOnly(args.ReadOnly)
+}
+
+// SetBucketNetwork - sets the client networks allowed to access a
+// bucket on this server.
+func (s *adminCmd) SetBucketNetwork(args *SetBucketNetworkArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setBucketNetwork(args.Bucket, args.Network)
+}
+
+// SetIAMUser - adds, replaces or removes an IAM user on this server.
+func (s *adminCmd) SetIAMUser(args *SetIAMUserArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return setIAMUser(args.AccessKey, args.User)
+}
+
 // Uptime - returns the time when object layer was initialized on this server.
 func (s *adminCmd) Uptime(args *AuthRPCArgs, reply *UptimeReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -180,7 +180,7 @@ func TestGetConfig(t *testing.T) {
 		t.Errorf("Expected GetConfig to pass but failed with %v", err)
 	}
 
-	var config serverConfigV13
+	var config serverConfigV16
 	err = json.Unmarshal(configReply.Config, &config)
 	if err != nil {
 		t.Errorf("Expected json unmarshal to pass but failed with %v", err)
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var adoptCmd = cli.Command{
+	Name:   "adopt",
+	Usage:  "Register existing files in an FS backend as objects.",
+	Action: mainAdopt,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} PATH BUCKET [PREFIX]
+
+DESCRIPTION:
+   Registers files copied into PATH/BUCKET as objects without copying
+   them, files whose names are not valid object names are skipped. The
+   ETag of an adopted object is computed on its first complete download.
+   The same operation is available on a running server through the
+   admin API.
+
+EXAMPLES:
+   1. Adopt all files copied into "/home/shared/mybucket":
+       $ {{.HelpName}} /home/shared mybucket
+
+   2. Adopt files under the prefix "photos/2017/":
+       $ {{.HelpName}} /home/shared mybucket photos/2017/
+`,
+}
+
+func mainAdopt(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 && len(ctx.Args()) != 3 {
+		cli.ShowCommandHelpAndExit(ctx, "adopt", 1)
+	}
+
+	fsPath, bucket, prefix := ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Get(2)
+	// XL backends cannot adopt files in place, a missing format.json
+	// is fine as it is created on the first server start.
+	format, err := loadFormatFS(fsPath)
+	if err != nil && err != errUnformattedDisk {
+		console.Fatalf("Unable to load 'format.json' of %s. Err: %s.\n", fsPath, err)
+	}
+	if err == nil && format.Format != "fs" {
+		console.Fatalf("Unable to adopt files in %s, backend format is %s, only fs is supported.\n", fsPath, format.Format)
+	}
+	result, err := adoptFSObjects(fsPath, bucket, prefix)
+	if err != nil {
+		console.Fatalf("Unable to adopt files in %s. Err: %s.\n", pathJoin(fsPath, bucket), errorCause(err))
+	}
+
+	for _, name := range result.Skipped {
+		console.Printf("Skipped: %s: not a valid object name\n", name)
+	}
+	if result.SkippedCount > int64(len(result.Skipped)) {
+		console.Printf("Skipped %d more files.\n", result.SkippedCount-int64(len(result.Skipped)))
+	}
+	console.Printf("Adopted %d objects of %d bytes, %d files already were objects.\n",
+		result.Adopted, result.Size, result.Existing)
+}
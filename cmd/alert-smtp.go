@@ -0,0 +1,217 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Default templates for alert mails, see alertMessage for the
+// fields available to custom templates.
+const (
+	defaultSMTPSubjectTemplate = "[minio] {{.Kind}} on {{.Server}}"
+	defaultSMTPBodyTemplate    = "{{.Message}}\n\nServer: {{.Server}}\nEvent: {{.Kind}}\nTime: {{.Time}}\n"
+)
+
+// smtpNotify - sends alerts as mails through a SMTP server.
+type smtpNotify struct {
+	Enable bool `json:"enable"`
+	// SMTP server address in host:port format.
+	Address  string   `json:"address"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	// Use implicit TLS (usually port 465), otherwise STARTTLS is
+	// used whenever the server supports it.
+	Secure bool `json:"secure"`
+	// Optional text/template for subject and body.
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// alertMessage - fields available to SMTP subject and body templates.
+type alertMessage struct {
+	Kind    string
+	Server  string
+	Subject string
+	Message string
+	Time    string
+}
+
+// smtpConn is a logrus hook mailing every entry to the recipients.
+type smtpConn struct {
+	config  smtpNotify
+	host    string
+	subject *template.Template
+	body    *template.Template
+}
+
+// Initializes a new SMTP alert target.
+func newSMTPAlertTarget(sNotify smtpNotify) (*logrus.Logger, error) {
+	host, _, err := net.SplitHostPort(sNotify.Address)
+	if err != nil {
+		return nil, err
+	}
+	if sNotify.From == "" || len(sNotify.To) == 0 {
+		return nil, errInvalidArgument
+	}
+
+	subjectTmpl, bodyTmpl := sNotify.Subject, sNotify.Body
+	if subjectTmpl == "" {
+		subjectTmpl = defaultSMTPSubjectTemplate
+	}
+	if bodyTmpl == "" {
+		bodyTmpl = defaultSMTPBodyTemplate
+	}
+	subject, err := template.New("subject").Parse(subjectTmpl)
+	if err != nil {
+		return nil, err
+	}
+	body, err := template.New("body").Parse(bodyTmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := smtpConn{
+		config:  sNotify,
+		host:    host,
+		subject: subject,
+		body:    body,
+	}
+
+	alertLog := logrus.New()
+	alertLog.Out = ioutil.Discard
+	alertLog.Hooks.Add(conn)
+
+	return alertLog, nil
+}
+
+// buildMessage - renders the mail including its headers.
+func (s smtpConn) buildMessage(msg alertMessage) ([]byte, error) {
+	var subject, body bytes.Buffer
+	if err := s.subject.Execute(&subject, msg); err != nil {
+		return nil, err
+	}
+	if err := s.body.Execute(&body, msg); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(s.config.To, ", "))
+	// Headers must not span lines, templates could render newlines.
+	fmt.Fprintf(&buf, "Subject: %s\r\n", strings.Replace(subject.String(), "\n", " ", -1))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(strings.Replace(body.String(), "\n", "\r\n", -1))
+	return buf.Bytes(), nil
+}
+
+// dial - connects to the SMTP server, upgrading to TLS when possible.
+func (s smtpConn) dial() (*smtp.Client, error) {
+	tlsConfig := &tls.Config{ServerName: s.host, RootCAs: globalRootCAs}
+	if s.config.Secure {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: defaultDialTimeout}, "tcp", s.config.Address, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, s.host)
+	}
+
+	conn, err := net.DialTimeout("tcp", s.config.Address, defaultDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Fire is called when an alert should be mailed.
+func (s smtpConn) Fire(entry *logrus.Entry) error {
+	msg := alertMessage{
+		Message: entry.Message,
+		Time:    entry.Time.UTC().Format(time.RFC3339),
+	}
+	msg.Kind, _ = entry.Data["kind"].(string)
+	msg.Server, _ = entry.Data["server"].(string)
+	msg.Subject, _ = entry.Data["subject"].(string)
+
+	data, err := s.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if s.config.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(s.config.From); err != nil {
+		return err
+	}
+	for _, to := range s.config.To {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Levels are Required for logrus hook implementation
+func (smtpConn) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.InfoLevel,
+	}
+}
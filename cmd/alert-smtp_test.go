@@ -0,0 +1,167 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests mails are rendered from the configured templates.
+func TestSMTPBuildMessage(t *testing.T) {
+	target, err := newSMTPAlertTarget(smtpNotify{
+		Address: "localhost:25",
+		From:    "minio@example.com",
+		To:      []string{"ops@example.com", "oncall@example.com"},
+		Subject: "{{.Kind}}: {{.Subject}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conn smtpConn
+	for _, hook := range target.Hooks[logrus.InfoLevel] {
+		conn = hook.(smtpConn)
+	}
+
+	data, err := conn.buildMessage(alertMessage{
+		Kind:    alertDiskOffline,
+		Server:  "10.0.0.1:9000",
+		Subject: "/mnt/disk1",
+		Message: "Disk /mnt/disk1 is offline",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(data)
+	for _, expected := range []string{
+		"From: minio@example.com\r\n",
+		"To: ops@example.com, oncall@example.com\r\n",
+		"Subject: disk-offline: /mnt/disk1\r\n",
+		"\r\n\r\nDisk /mnt/disk1 is offline\r\n",
+		"Server: 10.0.0.1:9000\r\n",
+	} {
+		if !strings.Contains(msg, expected) {
+			t.Errorf("Expected %q in message %q", expected, msg)
+		}
+	}
+}
+
+// Tests invalid SMTP configurations are rejected.
+func TestNewSMTPAlertTarget(t *testing.T) {
+	testCases := []struct {
+		config     smtpNotify
+		shouldPass bool
+	}{
+		{smtpNotify{Address: "localhost:25", From: "a@example.com", To: []string{"b@example.com"}}, true},
+		{smtpNotify{Address: "localhost", From: "a@example.com", To: []string{"b@example.com"}}, false},
+		{smtpNotify{Address: "localhost:25", To: []string{"b@example.com"}}, false},
+		{smtpNotify{Address: "localhost:25", From: "a@example.com"}, false},
+		{smtpNotify{Address: "localhost:25", From: "a@example.com", To: []string{"b@example.com"}, Body: "{{.Kind"}, false},
+	}
+	for i, testCase := range testCases {
+		_, err := newSMTPAlertTarget(testCase.config)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+}
+
+// Runs a minimal SMTP server accepting a single mail, the mail
+// data is sent on the returned channel.
+func startTestSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataCh := make(chan string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 Go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				dataCh <- string(data)
+				tp.PrintfLine("250 OK")
+			case "QUIT":
+				tp.PrintfLine("221 Bye")
+				return
+			default:
+				tp.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), dataCh
+}
+
+// Tests alerts are mailed through the SMTP server.
+func TestSMTPAlertTarget(t *testing.T) {
+	addr, dataCh := startTestSMTPServer(t)
+	target, err := newSMTPAlertTarget(smtpNotify{
+		Address: addr,
+		From:    "minio@example.com",
+		To:      []string{"ops@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.WithFields(logrus.Fields{
+		"kind":   alertCredentialsChanged,
+		"server": "10.0.0.1:9000",
+	}).Info("Credentials changed")
+
+	select {
+	case data := <-dataCh:
+		reader := textproto.NewReader(bufio.NewReader(strings.NewReader(data)))
+		header, err := reader.ReadMIMEHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subject := header.Get("Subject"); subject != "[minio] credentials-changed on 10.0.0.1:9000" {
+			t.Errorf("Unexpected subject %q", subject)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for mail")
+	}
+}
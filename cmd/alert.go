@@ -0,0 +1,219 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Kinds of alerts raised by the server.
+const (
+	alertErrorRate   = "error-rate"
+	alertQuorumLost  = "quorum-lost"
+	alertDiskOffline = "disk-offline"
+	alertBitrot      = "bitrot"
+
+	// Heals of a bucket or its objects.
+	alertHealCompleted = "heal-completed"
+
+	// Administrative events, always sent.
+	alertCredentialsChanged = "credentials-changed"
+)
+
+// The same kind of failure or heal alert is not raised again within
+// this period, so that a persistent failure or a heal of many objects
+// does not flood the targets.
+const alertCooldown = 15 * time.Minute
+
+// Kinds of alerts subject to alertCooldown.
+var alertCooldownKinds = set.CreateStringSet(alertErrorRate, alertQuorumLost, alertDiskOffline, alertBitrot, alertHealCompleted)
+
+// alertConfig - configures alert thresholds and targets.
+type alertConfig struct {
+	// Number of server errors (HTTP 5xx) in a minute which raises an
+	// error-rate alert, 0 disables error-rate alerts.
+	ErrorsPerMinute int `json:"errorsPerMinute"`
+
+	// Alert targets.
+	Webhook webhookNotify `json:"webhook"`
+	SMTP    smtpNotify    `json:"smtp"`
+}
+
+// alerter keeps track of thresholds and sends alerts to all targets.
+type alerter struct {
+	mu      sync.Mutex
+	targets []*logrus.Logger
+
+	// Last time each kind of alert was raised, per subject.
+	lastRaised map[string]time.Time
+
+	// Error rate tracking over a one minute window.
+	errorsPerMinute int
+	errorCount      int
+	windowStart     time.Time
+}
+
+func newAlerter(errorsPerMinute int, targets ...*logrus.Logger) *alerter {
+	return &alerter{
+		targets:         targets,
+		lastRaised:      make(map[string]time.Time),
+		errorsPerMinute: errorsPerMinute,
+	}
+}
+
+// Initializes a new webhook alert target.
+func newWebhookAlertTarget(endpoint string) (*logrus.Logger, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != httpScheme && u.Scheme != httpsScheme {
+		return nil, fmt.Errorf("Unsupported alert webhook endpoint %s", endpoint)
+	}
+
+	conn := httpConn{
+		// Configure aggressive timeouts for client posts.
+		Client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		Endpoint: endpoint,
+	}
+
+	alertLog := logrus.New()
+	alertLog.Out = ioutil.Discard
+
+	// Set default JSON formatter.
+	alertLog.Formatter = new(logrus.JSONFormatter)
+
+	alertLog.Hooks.Add(conn)
+
+	return alertLog, nil
+}
+
+// initAlerter - initializes the global alerter from server config,
+// alerting stays disabled when no target is enabled.
+func initAlerter() error {
+	aConfig := serverConfig.GetAlert()
+
+	var targets []*logrus.Logger
+	if aConfig.Webhook.Enable {
+		target, err := newWebhookAlertTarget(aConfig.Webhook.Endpoint)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+	if aConfig.SMTP.Enable {
+		target, err := newSMTPAlertTarget(aConfig.SMTP)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	globalAlerter = newAlerter(aConfig.ErrorsPerMinute, targets...)
+	return nil
+}
+
+// raise - sends an alert to all targets, unless the same kind of
+// failure alert was raised for the same subject (e.g. a disk) within
+// alertCooldown.
+func (a *alerter) raise(kind, subject, msg string) {
+	if alertCooldownKinds.Contains(kind) {
+		key := kind + "/" + subject
+		a.mu.Lock()
+		now := time.Now().UTC()
+		if last, ok := a.lastRaised[key]; ok && now.Sub(last) < alertCooldown {
+			a.mu.Unlock()
+			return
+		}
+		a.lastRaised[key] = now
+		a.mu.Unlock()
+	}
+
+	fields := logrus.Fields{
+		"kind":   kind,
+		"server": globalMinioAddr,
+	}
+	if subject != "" {
+		fields["subject"] = subject
+	}
+	// Targets may be slow, never block the caller.
+	go func() {
+		for _, target := range a.targets {
+			target.WithFields(fields).Info(msg)
+		}
+	}()
+}
+
+// countError - records a server error and raises an error-rate
+// alert once the configured threshold is crossed within a minute.
+func (a *alerter) countError() {
+	if a.errorsPerMinute <= 0 {
+		return
+	}
+
+	a.mu.Lock()
+	now := time.Now().UTC()
+	if now.Sub(a.windowStart) >= time.Minute {
+		a.windowStart = now
+		a.errorCount = 0
+	}
+	a.errorCount++
+	crossed := a.errorCount == a.errorsPerMinute
+	a.mu.Unlock()
+
+	if crossed {
+		a.raise(alertErrorRate, "", fmt.Sprintf("%d server errors within a minute", a.errorsPerMinute))
+	}
+}
+
+// raiseAlert - raises an alert of the given kind if alerting is enabled.
+func raiseAlert(kind, subject string, format string, args ...interface{}) {
+	if globalAlerter == nil {
+		return
+	}
+	globalAlerter.raise(kind, subject, fmt.Sprintf(format, args...))
+}
+
+// countErrorForAlert - records a server error if alerting is enabled.
+func countErrorForAlert() {
+	if globalAlerter == nil {
+		return
+	}
+	globalAlerter.countError()
+}
@@ -0,0 +1,155 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lines []string
+	for _, line := range bytes.Split(bytes.TrimSpace(b.buf.Bytes()), []byte("\n")) {
+		if len(line) > 0 {
+			lines = append(lines, string(line))
+		}
+	}
+	return lines
+}
+
+// Returns a logrus logger writing JSON entries into buf.
+func newTestAlertTarget(buf *syncBuffer) *logrus.Logger {
+	target := logrus.New()
+	target.Out = buf
+	target.Formatter = new(logrus.JSONFormatter)
+	return target
+}
+
+// Waits until buf holds n lines or times out.
+func waitForAlerts(t *testing.T, buf *syncBuffer, n int) []string {
+	for i := 0; i < 100; i++ {
+		if lines := buf.Lines(); len(lines) >= n {
+			return lines
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d alerts, got %v", n, buf.Lines())
+	return nil
+}
+
+// Tests alerts honor the cooldown per kind and subject.
+func TestAlerterRaise(t *testing.T) {
+	var buf syncBuffer
+	a := newAlerter(0, newTestAlertTarget(&buf))
+
+	a.raise(alertDiskOffline, "disk1", "Disk disk1 is offline")
+	waitForAlerts(t, &buf, 1)
+	// Same kind and subject is suppressed.
+	a.raise(alertDiskOffline, "disk1", "Disk disk1 is offline")
+	// Different subject is not.
+	a.raise(alertDiskOffline, "disk2", "Disk disk2 is offline")
+	lines := waitForAlerts(t, &buf, 2)
+	time.Sleep(50 * time.Millisecond)
+	if lines = buf.Lines(); len(lines) != 2 {
+		t.Fatalf("Expected 2 alerts, got %v", lines)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["kind"] != alertDiskOffline || fields["subject"] != "disk2" || fields["msg"] != "Disk disk2 is offline" {
+		t.Errorf("Unexpected alert %v", fields)
+	}
+}
+
+// Tests heals of the objects of a bucket raise a single alert.
+func TestAlerterRaiseHealCooldown(t *testing.T) {
+	var buf syncBuffer
+	a := newAlerter(0, newTestAlertTarget(&buf))
+
+	for i := 0; i < 3; i++ {
+		a.raise(alertHealCompleted, "bucket", "Healed objects of bucket bucket")
+	}
+	waitForAlerts(t, &buf, 1)
+	time.Sleep(50 * time.Millisecond)
+	if lines := buf.Lines(); len(lines) != 1 {
+		t.Fatalf("Expected 1 alert, got %v", lines)
+	}
+}
+
+// Tests error-rate alert is raised once the threshold is crossed.
+func TestAlerterCountError(t *testing.T) {
+	var buf syncBuffer
+	a := newAlerter(3, newTestAlertTarget(&buf))
+
+	a.countError()
+	a.countError()
+	time.Sleep(50 * time.Millisecond)
+	if lines := buf.Lines(); len(lines) != 0 {
+		t.Fatalf("Expected no alerts below threshold, got %v", lines)
+	}
+	a.countError()
+	lines := waitForAlerts(t, &buf, 1)
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["kind"] != alertErrorRate {
+		t.Errorf("Expected %s alert, got %v", alertErrorRate, fields)
+	}
+}
+
+// Tests invalid webhook endpoints are rejected.
+func TestNewWebhookAlertTarget(t *testing.T) {
+	if _, err := newWebhookAlertTarget("http://localhost:8080/alerts"); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	if _, err := newWebhookAlertTarget("localhost:8080"); err == nil {
+		t.Error("Expected endpoint without scheme to fail")
+	}
+}
+
+// Tests administrative events are not subject to the cooldown.
+func TestAlerterRaiseNoCooldown(t *testing.T) {
+	var buf syncBuffer
+	a := newAlerter(0, newTestAlertTarget(&buf))
+
+	a.raise(alertCredentialsChanged, "", "Credentials changed")
+	a.raise(alertCredentialsChanged, "", "Credentials changed")
+	waitForAlerts(t, &buf, 2)
+}
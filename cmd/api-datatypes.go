@@ -23,6 +23,9 @@ import (
 const (
 	// Response request id.
 	responseRequestIDKey = "x-amz-request-id"
+
+	// Response host id.
+	responseHostIDKey = "x-amz-id-2"
 )
 
 // ObjectIdentifier carries key name for the object to delete.
@@ -37,6 +40,13 @@ type createBucketLocationConfiguration struct {
 	Location string   `xml:"LocationConstraint"`
 }
 
+// versioningConfiguration - versioning state of a bucket, sent and
+// returned by the bucket versioning APIs.
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration" json:"-"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
 // DeleteObjectsRequest - xml carrying the object key names which needs to be deleted.
 type DeleteObjectsRequest struct {
 	// Element to enable quiet mode for the request
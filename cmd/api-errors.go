@@ -19,6 +19,8 @@ package cmd
 import (
 	"encoding/xml"
 	"net/http"
+
+	"github.com/minio/minio/pkg/bpool"
 )
 
 // APIError structure
@@ -33,11 +35,21 @@ type APIErrorResponse struct {
 	XMLName    xml.Name `xml:"Error" json:"-"`
 	Code       string
 	Message    string
-	Key        string
-	BucketName string
+	Key        string `xml:"Key,omitempty"`
+	BucketName string `xml:"BucketName,omitempty"`
 	Resource   string
-	RequestID  string `xml:"RequestId"`
-	HostID     string `xml:"HostId"`
+	// Region expected by the server, on region errors.
+	Region string `xml:"Region,omitempty"`
+	// Signature errors, what the client sent and what the server
+	// signed.
+	AWSAccessKeyID        string `xml:"AWSAccessKeyId,omitempty"`
+	StringToSign          string `xml:"StringToSign,omitempty"`
+	SignatureProvided     string `xml:"SignatureProvided,omitempty"`
+	StringToSignBytes     string `xml:"StringToSignBytes,omitempty"`
+	CanonicalRequest      string `xml:"CanonicalRequest,omitempty"`
+	CanonicalRequestBytes string `xml:"CanonicalRequestBytes,omitempty"`
+	RequestID             string `xml:"RequestId"`
+	HostID                string `xml:"HostId"`
 }
 
 // APIErrorCode type of error status.
@@ -103,6 +115,7 @@ const (
 	ErrMalformedCredentialRegion
 	ErrMalformedExpires
 	ErrNegativeExpires
+	ErrMaximumExpires
 	ErrAuthHeaderEmpty
 	ErrExpiredPresignRequest
 	ErrRequestNotReadyYet
@@ -112,6 +125,14 @@ const (
 	ErrInvalidQueryParams
 	ErrBucketAlreadyOwnedByYou
 	ErrInvalidDuration
+	ErrTooManyBuckets
+	ErrSlowDown
+	ErrMetadataTooLarge
+	ErrAnonymousResponseHeaders
+	ErrNoSuchVersion
+	ErrInvalidVersionID
+	ErrNoSuchLifecycleConfiguration
+	ErrInvalidLifecycleRule
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -127,6 +148,11 @@ const (
 
 	// S3 extended errors.
 	ErrContentSHA256Mismatch
+	ErrInvalidChecksum
+	ErrChecksumMismatch
+	ErrInvalidObjectAttributes
+	ErrInvalidSourceMtime
+	ErrObjectOverwriteDenied
 
 	// Add new extended error codes here.
 
@@ -138,6 +164,12 @@ const (
 	ErrPolicyNesting
 	ErrInvalidObjectName
 	ErrServerNotInitialized
+	ErrPolicyTooManyStatements
+	ErrBucketNameNotAllowed
+	ErrObjectNameNotAllowed
+	ErrBucketReadOnly
+	ErrInvalidArchive
+	ErrObjectDirMetadata
 	// Add new extended error codes here.
 	// Please open a https://github.com/minio/minio/issues before adding
 	// new error codes here.
@@ -145,6 +177,18 @@ const (
 	ErrAdminInvalidAccessKey
 	ErrAdminInvalidSecretKey
 	ErrAdminConfigNoQuorum
+	ErrAdminInvalidPolicyAction
+	ErrAdminInvalidBucketConfig
+	ErrAdminInvalidNode
+	ErrAdminNoSuchQuarantinedObject
+	ErrAdminQuarantinedObjectExists
+	ErrAdminBackupNotConfigured
+	ErrAdminInvalidBackup
+	ErrAdminApprovalRequired
+	ErrAdminInvalidApproval
+	ErrAdminNoSuchHealSequence
+	ErrAdminHealAlreadyRunning
+	ErrAdminNoSuchIAMUser
 )
 
 // error code to APIError structure, these fields carry respective
@@ -442,6 +486,11 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "X-Amz-Expires should be a number",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMaximumExpires: {
+		Code:           "AuthorizationQueryParametersError",
+		Description:    "X-Amz-Expires must be less than the maximum expiry allowed by the server",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrNegativeExpires: {
 		Code:           "AuthorizationQueryParametersError",
 		Description:    "X-Amz-Expires must be non-negative",
@@ -493,6 +542,46 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Duration provided in the request is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrTooManyBuckets: {
+		Code:           "TooManyBuckets",
+		Description:    "You have attempted to create more buckets than allowed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSlowDown: {
+		Code:           "SlowDown",
+		Description:    "Please reduce your request rate.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrMetadataTooLarge: {
+		Code:           "MetadataTooLarge",
+		Description:    "Your metadata headers exceed the maximum allowed metadata size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAnonymousResponseHeaders: {
+		Code:           "InvalidRequest",
+		Description:    "Request specific response headers cannot be used for anonymous GET requests.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchVersion: {
+		Code:           "NoSuchVersion",
+		Description:    "The specified version does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidVersionID: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid version id specified",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidLifecycleRule: {
+		Code:           "InvalidArgument",
+		Description:    "Lifecycle rule IDs must be unique and at most 255 characters, expiration days must be positive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Bucket notification related errors.
 	ErrEventNotification: {
@@ -547,6 +636,31 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The provided 'x-amz-content-sha256' header does not match what was computed.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidChecksum: {
+		Code:           "InvalidRequest",
+		Description:    "Value for x-amz-checksum header is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrChecksumMismatch: {
+		Code:           "BadDigest",
+		Description:    "The x-amz-checksum you specified did not match what we received.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectAttributes: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid attribute name specified in x-amz-object-attributes header.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidSourceMtime: {
+		Code:           "InvalidArgument",
+		Description:    "Value for x-minio-source-mtime header must be an RFC 3339 time not in the future.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectOverwriteDenied: {
+		Code:           "PreconditionFailed",
+		Description:    "The object already exists and the bucket does not allow overwrites.",
+		HTTPStatusCode: http.StatusPreconditionFailed,
+	},
 
 	/// Minio extensions.
 	ErrStorageFull: {
@@ -584,6 +698,36 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Server not initialized, please try again.",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrPolicyTooManyStatements: {
+		Code:           "XMinioPolicyTooManyStatements",
+		Description:    "Bucket policy has more statements than the server allows. Please merge or remove a few statements.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketNameNotAllowed: {
+		Code:           "XMinioBucketNameNotAllowed",
+		Description:    "The specified bucket name is not allowed by the bucket creation policy.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrObjectNameNotAllowed: {
+		Code:           "XMinioObjectNameNotAllowed",
+		Description:    "Object name is not allowed in a bucket in strict names mode, it must be a valid Windows and NFS file path.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketReadOnly: {
+		Code:           "AccessDenied",
+		Description:    "The specified bucket is a read-only bucket mount.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidArchive: {
+		Code:           "XMinioInvalidArchive",
+		Description:    "The uploaded archive is malformed or not a tar, tar.gz or zip file.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectDirMetadata: {
+		Code:           "XMinioObjectDirMetadata",
+		Description:    "Directory objects keep no metadata, only the content type application/octet-stream or application/x-directory is accepted.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrAdminInvalidAccessKey: {
 		Code:           "XMinioAdminInvalidAccessKey",
 		Description:    "The access key is invalid.",
@@ -599,6 +743,66 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Configuration update failed because server quorum was not met",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrAdminInvalidPolicyAction: {
+		Code:           "XMinioAdminInvalidPolicyAction",
+		Description:    "The action is not a supported bucket policy action.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidBucketConfig: {
+		Code:           "XMinioAdminInvalidBucketConfig",
+		Description:    "The bucket configuration bundle is malformed or has an unsupported version.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidNode: {
+		Code:           "XMinioAdminInvalidNode",
+		Description:    "The node is not a server of this deployment.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminNoSuchQuarantinedObject: {
+		Code:           "XMinioAdminNoSuchQuarantinedObject",
+		Description:    "No quarantined object has the given id.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminQuarantinedObjectExists: {
+		Code:           "XMinioAdminQuarantinedObjectExists",
+		Description:    "An object with the same name as the quarantined object exists, delete it before restoring.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrAdminBackupNotConfigured: {
+		Code:           "XMinioAdminBackupNotConfigured",
+		Description:    "No backup bucket is configured.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidBackup: {
+		Code:           "XMinioAdminInvalidBackup",
+		Description:    "The backup object is malformed or has an unsupported version.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminApprovalRequired: {
+		Code:           "XMinioAdminApprovalRequired",
+		Description:    "This operation needs an approval token issued with a second credential.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrAdminInvalidApproval: {
+		Code:           "XMinioAdminInvalidApproval",
+		Description:    "The approval token is invalid, expired, already used, issued for another operation or with the credential of the request.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrAdminNoSuchHealSequence: {
+		Code:           "XMinioAdminNoSuchHealSequence",
+		Description:    "No heal sequence has the given token, it may have finished too long ago or been started on another server.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminHealAlreadyRunning: {
+		Code:           "XMinioAdminHealAlreadyRunning",
+		Description:    "A heal sequence is already running on the bucket and prefix.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrAdminNoSuchIAMUser: {
+		Code:           "XMinioAdminNoSuchIAMUser",
+		Description:    "No IAM user has the given access key.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 
 	// Add your error structure here.
 }
@@ -618,14 +822,34 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrSignatureDoesNotMatch
 	case errContentSHA256Mismatch:
 		apiErr = ErrContentSHA256Mismatch
+	case errChecksumMismatch:
+		apiErr = ErrChecksumMismatch
 	case errDataTooLarge:
 		apiErr = ErrEntityTooLarge
 	case errDataTooSmall:
 		apiErr = ErrEntityTooSmall
+	case errMetadataTooLarge:
+		apiErr = ErrMetadataTooLarge
 	case errInvalidAccessKeyLength:
 		apiErr = ErrAdminInvalidAccessKey
 	case errInvalidSecretKeyLength:
 		apiErr = ErrAdminInvalidSecretKey
+	case errTooManyBuckets:
+		apiErr = ErrTooManyBuckets
+	case errBucketNameNotAllowed:
+		apiErr = ErrBucketNameNotAllowed
+	case errInvalidArchive:
+		apiErr = ErrInvalidArchive
+	case errNoSuchQuarantinedObject:
+		apiErr = ErrAdminNoSuchQuarantinedObject
+	case errQuarantinedObjectExists:
+		apiErr = ErrAdminQuarantinedObjectExists
+	case errInvalidBackup:
+		apiErr = ErrAdminInvalidBackup
+	case errObjectOverwriteDenied:
+		apiErr = ErrObjectOverwriteDenied
+	case bpool.ErrBpoolTimeout:
+		apiErr = ErrSlowDown
 	}
 
 	if apiErr != ErrNone {
@@ -656,8 +880,16 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchKey
 	case ObjectNameInvalid:
 		apiErr = ErrInvalidObjectName
+	case ObjectNameNotAllowed:
+		apiErr = ErrObjectNameNotAllowed
+	case ObjectDirMetadata:
+		apiErr = ErrObjectDirMetadata
+	case BucketReadOnly:
+		apiErr = ErrBucketReadOnly
 	case InvalidUploadID:
 		apiErr = ErrNoSuchUpload
+	case VersionNotFound:
+		apiErr = ErrNoSuchVersion
 	case InvalidPart:
 		apiErr = ErrInvalidPart
 	case InsufficientWriteQuorum:
@@ -670,6 +902,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrNotImplemented
 	case InvalidUploadIDKeyCombination:
 		apiErr = ErrNotImplemented
+	case NotImplemented:
+		apiErr = ErrNotImplemented
 	case MalformedUploadID:
 		apiErr = ErrNoSuchUpload
 	case PartTooSmall:
@@ -694,12 +928,19 @@ func getAPIError(code APIErrorCode) APIError {
 
 // getErrorResponse gets in standard error and resource value and
 // provides a encodable populated response values
-func getAPIErrorResponse(err APIError, resource string) APIErrorResponse {
+func getAPIErrorResponse(err APIError, resource, requestID string) APIErrorResponse {
+	var bucket, object string
+	// Admin and browser requests do not address a bucket.
+	if !hasPrefix(resource, minioReservedBucketPath+slashSeparator) {
+		bucket, object = path2BucketAndObject(resource)
+	}
 	return APIErrorResponse{
-		Code:      err.Code,
-		Message:   err.Description,
-		Resource:  resource,
-		RequestID: "3L137",
-		HostID:    "3L137",
+		Code:       err.Code,
+		Message:    err.Description,
+		Key:        object,
+		BucketName: bucket,
+		Resource:   resource,
+		RequestID:  requestID,
+		HostID:     getHostID(),
 	}
 }
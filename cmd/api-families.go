@@ -0,0 +1,80 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// S3 API families which can be disabled.
+const (
+	apiFamilyRead           = "read"
+	apiFamilyList           = "list"
+	apiFamilyWrite          = "write"
+	apiFamilyDelete         = "delete"
+	apiFamilyMultipart      = "multipart"
+	apiFamilyBucketCreation = "bucket-creation"
+	apiFamilyPolicy         = "policy"
+	apiFamilyNotification   = "notification"
+)
+
+var validAPIFamilies = map[string]bool{
+	apiFamilyRead:           true,
+	apiFamilyList:           true,
+	apiFamilyWrite:          true,
+	apiFamilyDelete:         true,
+	apiFamilyMultipart:      true,
+	apiFamilyBucketCreation: true,
+	apiFamilyPolicy:         true,
+	apiFamilyNotification:   true,
+}
+
+// validateDisabledAPIs - validates the disabled API families.
+func validateDisabledAPIs(families []string) error {
+	for _, family := range families {
+		if !validAPIFamilies[family] {
+			return fmt.Errorf("unknown API family %s", family)
+		}
+	}
+	return nil
+}
+
+// isAPIFamilyDisabled - returns true if family is disabled.
+func isAPIFamilyDisabled(family string) bool {
+	if serverConfig == nil {
+		return false
+	}
+	for _, disabled := range serverConfig.GetDisabledAPIs() {
+		if disabled == family {
+			return true
+		}
+	}
+	return false
+}
+
+// apiFamilyHandler - rejects requests to h with MethodNotAllowed while
+// its API family is disabled.
+func apiFamilyHandler(family string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isAPIFamilyDisabled(family) {
+			writeErrorResponse(w, ErrMethodNotAllowed, r)
+			return
+		}
+		h(w, r)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validation of disabled API families.
+func TestValidateDisabledAPIs(t *testing.T) {
+	testCases := []struct {
+		families []string
+		success  bool
+	}{
+		{nil, true},
+		{[]string{"delete", "multipart", "bucket-creation"}, true},
+		{[]string{"read", "list", "write", "policy", "notification"}, true},
+		{[]string{"deletes"}, false},
+		{[]string{""}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateDisabledAPIs(testCase.families)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Wrapper for calling disabled API family tests for both XL multiple disks and single node setup.
+func TestDisabledAPIs(t *testing.T) {
+	ExecObjectLayerAPITest(t, testDisabledAPIs, nil)
+}
+
+// testDisabledAPIs - Tests that requests to disabled API families are
+// rejected with MethodNotAllowed.
+func testDisabledAPIs(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	if _, err := obj.PutObject(bucketName, "object", 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+		t.Fatalf("%s: Failed to create object: <ERROR> %v", instanceType, err)
+	}
+
+	// All handlers are registered, serving the global object layer.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	serverConfig.SetDisabledAPIs([]string{apiFamilyDelete, apiFamilyMultipart, apiFamilyBucketCreation})
+	defer serverConfig.SetDisabledAPIs(nil)
+
+	testCases := []struct {
+		method     string
+		url        string
+		expectCode int
+	}{
+		{"DELETE", getDeleteObjectURL("", bucketName, "object"), http.StatusMethodNotAllowed},
+		{"DELETE", getDeleteBucketURL("", bucketName), http.StatusMethodNotAllowed},
+		{"POST", getNewMultipartURL("", bucketName, "object"), http.StatusMethodNotAllowed},
+		{"PUT", getMakeBucketURL("", "newbucket"), http.StatusMethodNotAllowed},
+		// Other families are served.
+		{"GET", getGetObjectURL("", bucketName, "object"), http.StatusOK},
+		{"GET", getListObjectsV1URL("", bucketName, ""), http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4(testCase.method, testCase.url, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create request: <ERROR> %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, testCase.expectCode, rec.Code)
+		}
+	}
+
+	// Deletes are served again once enabled.
+	serverConfig.SetDisabledAPIs(nil)
+	req, err := newTestSignedRequestV4("DELETE", getDeleteObjectURL("", bucketName, "object"), 0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create request: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNoContent, rec.Code)
+	}
+}
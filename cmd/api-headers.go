@@ -18,11 +18,17 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/minio/sha256-simd"
 )
 
 // Returns a hexadecimal representation of time at the
@@ -31,10 +37,31 @@ func mustGetRequestID(t time.Time) string {
 	return fmt.Sprintf("%X", t.UnixNano())
 }
 
+var (
+	hostIDOnce sync.Once
+	hostID     string
+)
+
+// getHostID - returns an opaque id of this server, sent with every
+// reply so that failed requests can be traced to the server which
+// answered them, like the host id of AWS S3.
+func getHostID() string {
+	hostIDOnce.Do(func() {
+		hostname, _ := os.Hostname()
+		sum := sha256.Sum256([]byte(hostname + globalMinioAddr))
+		hostID = base64.StdEncoding.EncodeToString(sum[:])
+	})
+	return hostID
+}
+
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
-	// Set unique request ID for each reply.
-	w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	// Set unique request ID for each reply, unless already set by
+	// an error response carrying it in its body.
+	if w.Header().Get(responseRequestIDKey) == "" {
+		w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	}
+	w.Header().Set(responseHostIDKey, getHostID())
 	w.Header().Set("Server", globalServerUserAgent)
 	w.Header().Set("Accept-Ranges", "bytes")
 }
@@ -67,14 +94,61 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 
 	// Set all other user defined metadata.
 	for k, v := range objInfo.UserDefined {
+		// Preserved source metadata is only exposed on demand.
+		if strings.HasPrefix(k, minioSourceMetaPrefix) && !globalExposeSourceMetadata {
+			continue
+		}
+		if strings.HasPrefix(k, userMetaPrefix) {
+			v = encodeUserMetadataValue(v)
+		}
 		w.Header().Set(k, v)
 	}
 
 	// for providing ranged content
-	if contentRange != nil && contentRange.offsetBegin > -1 {
+	if isPartialContent(contentRange) {
 		// Override content-length
 		w.Header().Set("Content-Length", strconv.FormatInt(contentRange.getLength(), 10))
 		w.Header().Set("Content-Range", contentRange.String())
-		w.WriteHeader(http.StatusPartialContent)
 	}
 }
+
+// isPartialContent - returns true if contentRange selects a part of
+// the object, to be sent with status 206 once all headers are set.
+func isPartialContent(contentRange *httpRange) bool {
+	return contentRange != nil && contentRange.offsetBegin > -1
+}
+
+// Minio extension headers describing optimal range boundaries.
+const (
+	minioBlockSizeHeader = "X-Minio-Block-Size"
+	minioPartSizesHeader = "X-Minio-Part-Sizes"
+)
+
+// formatPartSizes - run length encodes part sizes, e.g. parts of
+// 5MiB, 5MiB and 1KiB are encoded as "5242880*2,1024".
+func formatPartSizes(partSizes []int64) string {
+	var runs []string
+	for i := 0; i < len(partSizes); {
+		j := i + 1
+		for j < len(partSizes) && partSizes[j] == partSizes[i] {
+			j++
+		}
+		if j-i > 1 {
+			runs = append(runs, fmt.Sprintf("%d*%d", partSizes[i], j-i))
+		} else {
+			runs = append(runs, strconv.FormatInt(partSizes[i], 10))
+		}
+		i = j
+	}
+	return strings.Join(runs, ",")
+}
+
+// Write object range hint headers, so that clients can issue range
+// GETs aligned to erasure blocks.
+func setRangeHintHeaders(w http.ResponseWriter, objInfo ObjectInfo) {
+	if objInfo.RangeHint == nil {
+		return
+	}
+	w.Header().Set(minioBlockSizeHeader, strconv.FormatInt(objInfo.RangeHint.BlockSize, 10))
+	w.Header().Set(minioPartSizesHeader, formatPartSizes(objInfo.RangeHint.PartSizes))
+}
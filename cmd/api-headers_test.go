@@ -39,3 +39,21 @@ func TestNewRequestID(t *testing.T) {
 		}
 	}
 }
+
+// Tests run length encoding of part sizes.
+func TestFormatPartSizes(t *testing.T) {
+	testCases := []struct {
+		partSizes []int64
+		expected  string
+	}{
+		{nil, ""},
+		{[]int64{1024}, "1024"},
+		{[]int64{5242880, 5242880, 1024}, "5242880*2,1024"},
+		{[]int64{1, 2, 2, 2, 1}, "1,2*3,1"},
+	}
+	for i, testCase := range testCases {
+		if got := formatPartSizes(testCase.partSizes); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
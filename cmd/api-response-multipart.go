@@ -43,9 +43,10 @@ type completeMultipartAPIError struct {
 func writePartSmallErrorResponse(w http.ResponseWriter, r *http.Request, err PartTooSmall) {
 
 	apiError := getAPIError(toAPIErrorCode(err))
+	setCommonHeaders(w)
 	// Generate complete multipart error response.
-	errorResponse := getAPIErrorResponse(apiError, r.URL.Path)
-	cmpErrResp := completeMultipartAPIError{err.PartSize, int64(5242880), err.PartNumber, err.PartETag, errorResponse}
+	errorResponse := getAPIErrorResponse(apiError, r.URL.Path, w.Header().Get(responseRequestIDKey))
+	cmpErrResp := completeMultipartAPIError{err.PartSize, globalMinPartSize, err.PartNumber, err.PartETag, errorResponse}
 	encodedErrorResponse := encodeResponse(cmpErrResp)
 
 	// respond with 400 bad request.
@@ -19,7 +19,6 @@ package cmd
 import (
 	"encoding/xml"
 	"net/http"
-	"net/url"
 	"path"
 	"time"
 )
@@ -99,6 +98,33 @@ type ListObjectsV2Response struct {
 	EncodingType string `xml:"EncodingType,omitempty"`
 }
 
+// ListVersionsResponse - format for list object versions response.
+type ListVersionsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult" json:"-"`
+
+	Name            string
+	Prefix          string
+	KeyMarker       string
+	VersionIDMarker string `xml:"VersionIdMarker"`
+
+	// When response is truncated (the IsTruncated element value in the response
+	// is true), the key and version id to use as key-marker and
+	// version-id-marker in the subsequent request.
+	NextKeyMarker       string `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string `xml:"NextVersionIdMarker,omitempty"`
+
+	MaxKeys   int
+	Delimiter string
+	// A flag that indicates whether or not ListObjectVersions returned all of the results
+	// that satisfied the search criteria.
+	IsTruncated bool
+
+	// Versions and delete markers in listing order, newest first for
+	// each object.
+	Versions       []interface{}
+	CommonPrefixes []CommonPrefix
+}
+
 // Part container for part metadata.
 type Part struct {
 	PartNumber   int
@@ -201,6 +227,35 @@ type Object struct {
 	HealObjectInfo *HealObjectInfo `xml:"HealObjectInfo,omitempty"`
 }
 
+// ObjectVersion container for a version of an object.
+type ObjectVersion struct {
+	XMLName      xml.Name `xml:"Version" json:"-"`
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string
+	Size         int64
+
+	// Owner of the object.
+	Owner Owner
+
+	// The class of storage used to store the object.
+	StorageClass string
+}
+
+// DeleteMarker container for a delete marker of an object.
+type DeleteMarker struct {
+	XMLName      xml.Name `xml:"DeleteMarker" json:"-"`
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+
+	// Owner of the delete marker.
+	Owner Owner
+}
+
 // CopyObjectResponse container returns ETag and LastModified of the successfully copied object
 type CopyObjectResponse struct {
 	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult" json:"-"`
@@ -208,6 +263,42 @@ type CopyObjectResponse struct {
 	ETag         string   // md5sum of the copied object.
 }
 
+// ObjectChecksum container for checksums of an object.
+type ObjectChecksum struct {
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+// GetObjectAttributesResponse container returns requested attributes of an object.
+type GetObjectAttributesResponse struct {
+	XMLName      xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAttributesResponse" json:"-"`
+	ETag         string          `xml:"ETag,omitempty"`
+	Checksum     *ObjectChecksum `xml:"Checksum,omitempty"`
+	ObjectSize   *int64          `xml:"ObjectSize,omitempty"`
+	StorageClass string          `xml:"StorageClass,omitempty"`
+}
+
+// BucketUsageResponse - minio extension, format for prefix usage response.
+type BucketUsageResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ BucketUsageResult" json:"-"`
+
+	Bucket       string
+	Prefix       string
+	Size         int64
+	ObjectsCount int64
+}
+
+// ExtractArchiveResponse - minio extension, format for archive
+// extraction response.
+type ExtractArchiveResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ExtractArchiveResult" json:"-"`
+
+	Bucket       string
+	Prefix       string
+	Size         int64
+	ObjectsCount int64
+}
+
 // CopyObjectPartResponse container returns ETag and LastModified of the successfully copied object
 type CopyObjectPartResponse struct {
 	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyPartResult" json:"-"`
@@ -398,6 +489,62 @@ func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter
 	return data
 }
 
+// generates an ListObjectVersions response for the said bucket with other enumerated options.
+func generateListVersionsResponse(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int, resp ListObjectVersionsInfo) ListVersionsResponse {
+	var versions []interface{}
+	var prefixes []CommonPrefix
+	var owner = Owner{}
+	var data = ListVersionsResponse{}
+
+	owner.ID = globalMinioDefaultOwnerID
+	owner.DisplayName = globalMinioDefaultOwnerID
+
+	for _, version := range resp.Versions {
+		lastModified := version.ModTime.UTC().Format(timeFormatAMZLong)
+		if version.DeleteMarker {
+			versions = append(versions, DeleteMarker{
+				Key:          version.Name,
+				VersionID:    version.VersionID,
+				IsLatest:     version.IsLatest,
+				LastModified: lastModified,
+				Owner:        owner,
+			})
+			continue
+		}
+		var content = ObjectVersion{}
+		content.Key = version.Name
+		content.VersionID = version.VersionID
+		content.IsLatest = version.IsLatest
+		content.LastModified = lastModified
+		if version.MD5Sum != "" {
+			content.ETag = "\"" + version.MD5Sum + "\""
+		}
+		content.Size = version.Size
+		content.StorageClass = globalMinioDefaultStorageClass
+		content.Owner = owner
+		versions = append(versions, content)
+	}
+	data.Name = bucket
+	data.Versions = versions
+
+	data.Prefix = prefix
+	data.KeyMarker = keyMarker
+	data.VersionIDMarker = versionIDMarker
+	data.Delimiter = delimiter
+	data.MaxKeys = maxKeys
+
+	data.NextKeyMarker = resp.NextKeyMarker
+	data.NextVersionIDMarker = resp.NextVersionIDMarker
+	data.IsTruncated = resp.IsTruncated
+	for _, prefix := range resp.Prefixes {
+		var prefixItem = CommonPrefix{}
+		prefixItem.Prefix = prefix
+		prefixes = append(prefixes, prefixItem)
+	}
+	data.CommonPrefixes = prefixes
+	return data
+}
+
 // generates CopyObjectResponse from etag and lastModified time.
 func generateCopyObjectResponse(etag string, lastModified time.Time) CopyObjectResponse {
 	return CopyObjectResponse{
@@ -406,6 +553,31 @@ func generateCopyObjectResponse(etag string, lastModified time.Time) CopyObjectR
 	}
 }
 
+// generates GetObjectAttributesResponse with requested attributes of an object.
+func generateGetObjectAttributesResponse(objInfo ObjectInfo, attributes map[string]bool) GetObjectAttributesResponse {
+	var data GetObjectAttributesResponse
+	if attributes[objectAttributeETag] {
+		data.ETag = objInfo.MD5Sum
+	}
+	if attributes[objectAttributeChecksum] {
+		checksum := ObjectChecksum{
+			ChecksumCRC32C: objInfo.UserDefined[amzChecksumCRC32C],
+			ChecksumSHA256: objInfo.UserDefined[amzChecksumSHA256],
+		}
+		if checksum.ChecksumCRC32C != "" || checksum.ChecksumSHA256 != "" {
+			data.Checksum = &checksum
+		}
+	}
+	if attributes[objectAttributeObjectSize] {
+		size := objInfo.Size
+		data.ObjectSize = &size
+	}
+	if attributes[objectAttributeStorageClass] {
+		data.StorageClass = globalMinioDefaultStorageClass
+	}
+	return data
+}
+
 // generates CopyObjectPartResponse from etag and lastModified time.
 func generateCopyObjectPartResponse(etag string, lastModified time.Time) CopyObjectPartResponse {
 	return CopyObjectPartResponse{
@@ -525,6 +697,8 @@ const (
 	mimeJSON mimeType = "application/json"
 	// Means response type is XML.
 	mimeXML mimeType = "application/xml"
+	// Means response type is HTML.
+	mimeHTML mimeType = "text/html; charset=utf-8"
 )
 
 // writeSuccessResponseJSON writes success headers and response if any,
@@ -554,11 +728,14 @@ func writeSuccessResponseHeadersOnly(w http.ResponseWriter) {
 	writeResponse(w, http.StatusOK, nil, mimeNone)
 }
 
-// writeErrorRespone writes error headers
-func writeErrorResponse(w http.ResponseWriter, errorCode APIErrorCode, reqURL *url.URL) {
+// writeErrorRespone writes error headers, the body carries the same
+// request id as the `x-amz-request-id` header.
+func writeErrorResponse(w http.ResponseWriter, errorCode APIErrorCode, r *http.Request) {
 	apiError := getAPIError(errorCode)
+	setCommonHeaders(w)
 	// Generate error response.
-	errorResponse := getAPIErrorResponse(apiError, reqURL.Path)
+	errorResponse := getAPIErrorResponse(apiError, r.URL.Path, w.Header().Get(responseRequestIDKey))
+	setSignatureErrorDetails(&errorResponse, errorCode, r)
 	encodedErrorResponse := encodeResponse(errorResponse)
 	writeResponse(w, apiError.HTTPStatusCode, encodedErrorResponse, mimeXML)
 }
@@ -36,30 +36,35 @@ func registerAPIRouter(mux *router.Router) {
 	// Bucket router
 	bucket := apiRouter.PathPrefix("/{bucket}").Subrouter()
 
+	// Handlers of API families which can be disabled with the
+	// disabledAPIs config are wrapped with apiFamilyHandler.
+
 	/// Object operations
 
 	// HeadObject
-	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(api.HeadObjectHandler)
+	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyRead, api.HeadObjectHandler))
 	// CopyObjectPart
-	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
+	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.CopyObjectPartHandler)).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 	// PutObjectPart
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.PutObjectPartHandler)).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 	// ListObjectPxarts
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.ListObjectPartsHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.ListObjectPartsHandler)).Queries("uploadId", "{uploadId:.*}")
 	// CompleteMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.CompleteMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
 	// NewMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.NewMultipartUploadHandler)).Queries("uploads", "")
 	// AbortMultipartUpload
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.AbortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.AbortMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
+	// GetObjectAttributes
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyRead, api.GetObjectAttributesHandler)).Queries("attributes", "")
 	// GetObject
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyRead, api.GetObjectHandler))
 	// CopyObject
-	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectHandler)
+	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(apiFamilyHandler(apiFamilyWrite, api.CopyObjectHandler))
 	// PutObject
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyWrite, api.PutObjectHandler))
 	// DeleteObject
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectHandler)
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(apiFamilyHandler(apiFamilyDelete, api.DeleteObjectHandler))
 
 	/// Bucket operations
 
@@ -69,33 +74,51 @@ func registerAPIRouter(mux *router.Router) {
 	bucket.Methods("GET").HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
 	// GetBucketNotification
 	bucket.Methods("GET").HandlerFunc(api.GetBucketNotificationHandler).Queries("notification", "")
+	// GetBucketVersioning
+	bucket.Methods("GET").HandlerFunc(api.GetBucketVersioningHandler).Queries("versioning", "")
+	// GetBucketLifecycle
+	bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
 	// ListenBucketNotification
-	bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyNotification, api.ListenBucketNotificationHandler)).Queries("events", "{events:.*}")
 	// ListMultipartUploads
-	bucket.Methods("GET").HandlerFunc(api.ListMultipartUploadsHandler).Queries("uploads", "")
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyMultipart, api.ListMultipartUploadsHandler)).Queries("uploads", "")
+	// GetBucketTar (minio extension)
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyRead, api.GetBucketTarHandler)).Queries("tar", "")
+	// GetBucketUsage (minio extension)
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyList, api.GetBucketUsageHandler)).Queries("du", "")
+	// ListObjectVersions
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyList, api.ListObjectVersionsHandler)).Queries("versions", "")
 	// ListObjectsV2
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyList, api.ListObjectsV2Handler)).Queries("list-type", "2")
 	// ListObjectsV1 (Legacy)
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
+	bucket.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyList, api.ListObjectsV1Handler))
 	// PutBucketPolicy
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
+	bucket.Methods("PUT").HandlerFunc(apiFamilyHandler(apiFamilyPolicy, api.PutBucketPolicyHandler)).Queries("policy", "")
 	// PutBucketNotification
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketNotificationHandler).Queries("notification", "")
+	bucket.Methods("PUT").HandlerFunc(apiFamilyHandler(apiFamilyNotification, api.PutBucketNotificationHandler)).Queries("notification", "")
+	// PutBucketVersioning
+	bucket.Methods("PUT").HandlerFunc(api.PutBucketVersioningHandler).Queries("versioning", "")
+	// PutBucketLifecycle
+	bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+	// PutBucketExtract (minio extension)
+	bucket.Methods("PUT").HandlerFunc(apiFamilyHandler(apiFamilyWrite, api.PutBucketExtractHandler)).Queries("extract", "")
 	// PutBucket
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
+	bucket.Methods("PUT").HandlerFunc(apiFamilyHandler(apiFamilyBucketCreation, api.PutBucketHandler))
 	// HeadBucket
 	bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
 	// PostPolicy
-	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(api.PostPolicyBucketHandler)
+	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(apiFamilyHandler(apiFamilyWrite, api.PostPolicyBucketHandler))
 	// DeleteMultipleObjects
-	bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler)
+	bucket.Methods("POST").HandlerFunc(apiFamilyHandler(apiFamilyDelete, api.DeleteMultipleObjectsHandler))
 	// DeleteBucketPolicy
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketPolicyHandler).Queries("policy", "")
+	bucket.Methods("DELETE").HandlerFunc(apiFamilyHandler(apiFamilyPolicy, api.DeleteBucketPolicyHandler)).Queries("policy", "")
+	// DeleteBucketLifecycle
+	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
 	// DeleteBucket
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
+	bucket.Methods("DELETE").HandlerFunc(apiFamilyHandler(apiFamilyDelete, api.DeleteBucketHandler))
 
 	/// Root operation
 
 	// ListBuckets
-	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
+	apiRouter.Methods("GET").HandlerFunc(apiFamilyHandler(apiFamilyList, api.ListBucketsHandler))
 }
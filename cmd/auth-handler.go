@@ -107,6 +107,20 @@ func getRequestAuthType(r *http.Request) authType {
 }
 
 func checkRequestAuthType(r *http.Request, bucket, policyAction, region string) APIErrorCode {
+	return checkRequestAuthTypeIAM(r, bucket, policyAction, policyAction, region)
+}
+
+// checkOwnerRequestAuthType - authenticates a request for an operation
+// bucket policies cannot allow. Only signed requests of the server
+// access key, or of IAM users allowed iamAction, may perform it.
+func checkOwnerRequestAuthType(r *http.Request, iamAction, region string) APIErrorCode {
+	return checkRequestAuthTypeIAM(r, "", "", iamAction, region)
+}
+
+// checkRequestAuthTypeIAM - authenticates a request, anonymous
+// requests must be allowed policyAction by the bucket policy and
+// signed requests of IAM users iamAction by their policy.
+func checkRequestAuthTypeIAM(r *http.Request, bucket, policyAction, iamAction, region string) APIErrorCode {
 	reqAuthType := getRequestAuthType(r)
 
 	switch reqAuthType {
@@ -114,15 +128,25 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 		// Signature V2 validation.
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			// Requests over the limits of their access key are
+			// validly signed.
+			if s3Error != ErrSlowDown {
+				errorIf(errSignatureMismatch, dumpRequest(r))
+			}
+			return s3Error
 		}
-		return s3Error
+		return checkIAMPolicy(r, getRequestAccessKey(r), iamAction, r.URL.Path)
 	case authTypeSigned, authTypePresigned:
 		s3Error := isReqAuthenticated(r, region)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			// Requests over the limits of their access key are
+			// validly signed.
+			if s3Error != ErrSlowDown {
+				errorIf(errSignatureMismatch, dumpRequest(r))
+			}
+			return s3Error
 		}
-		return s3Error
+		return checkIAMPolicy(r, getRequestAccessKey(r), iamAction, r.URL.Path)
 	}
 
 	if reqAuthType == authTypeAnonymous && policyAction != "" {
@@ -138,9 +162,14 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
-		return doesSignV2Match(r)
+		s3Error = doesSignV2Match(r)
+	} else {
+		s3Error = doesPresignV2SignatureMatch(r)
 	}
-	return doesPresignV2SignatureMatch(r)
+	if s3Error != ErrNone {
+		return s3Error
+	}
+	return checkRequestLimit(r, getRequestAccessKey(r))
 }
 
 func reqSignatureV4Verify(r *http.Request) (s3Error APIErrorCode) {
@@ -150,16 +179,41 @@ func reqSignatureV4Verify(r *http.Request) (s3Error APIErrorCode) {
 	if skipContentSha256Cksum(r) {
 		sha256sum = unsignedPayload
 	}
+	return doesSignatureV4Match(getSignatureCredential(getRequestAccessKey(r)), sha256sum, r, serverConfig.GetRegion())
+}
+
+// doesSignatureV4Match - verifies the signature V4 of r against cred,
+// on a mismatch saves the canonical request and string to sign
+// calculated for the error response. Requests with a valid signature
+// are admitted within the limits of cred.
+func doesSignatureV4Match(cred credential, sha256sum string, r *http.Request, region string) (s3Error APIErrorCode) {
+	var canonicalRequest, stringToSign string
 	if isRequestSignatureV4(r) {
-		return doesSignatureMatch(sha256sum, r, serverConfig.GetRegion())
+		canonicalRequest, stringToSign, s3Error = doesSignatureMatchWithCred(cred, sha256sum, r, region)
 	} else if isRequestPresignedSignatureV4(r) {
-		return doesPresignedSignatureMatch(sha256sum, r, serverConfig.GetRegion())
+		canonicalRequest, stringToSign, s3Error = doesPresignedSignatureMatchWithCred(cred, sha256sum, r, region)
+	} else {
+		return ErrAccessDenied
 	}
-	return ErrAccessDenied
+	if s3Error == ErrSignatureDoesNotMatch {
+		setSignatureDetails(r, canonicalRequest, stringToSign)
+	}
+	if s3Error != ErrNone {
+		return s3Error
+	}
+	return checkRequestLimit(r, cred.AccessKey)
 }
 
 // Verify if request has valid AWS Signature Version '4'.
 func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
+	if r == nil {
+		return ErrInternalError
+	}
+	return isReqAuthenticatedWithCred(r, region, getSignatureCredential(getRequestAccessKey(r)))
+}
+
+// Verify if request has valid AWS Signature Version '4' for the given credential.
+func isReqAuthenticatedWithCred(r *http.Request, region string, cred credential) (s3Error APIErrorCode) {
 	if r == nil {
 		return ErrInternalError
 	}
@@ -183,12 +237,7 @@ func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
 	} else {
 		sha256sum = getSHA256Hash(payload)
 	}
-	if isRequestSignatureV4(r) {
-		return doesSignatureMatch(sha256sum, r, region)
-	} else if isRequestPresignedSignatureV4(r) {
-		return doesPresignedSignatureMatch(sha256sum, r, region)
-	}
-	return ErrAccessDenied
+	return doesSignatureV4Match(cred, sha256sum, r, region)
 }
 
 // authHandler - handles all the incoming authorization headers and validates them if possible.
@@ -222,6 +271,11 @@ func isSupportedS3AuthType(aType authType) bool {
 func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	aType := getRequestAuthType(r)
 	if isSupportedS3AuthType(aType) {
+		// Requests over the limits of their access key are rejected
+		// once their signature is verified.
+		var release func()
+		r, release = trackRequestLimit(r)
+		defer release()
 		// Let top level caller validate for anonymous and known signed requests.
 		a.handler.ServeHTTP(w, r)
 		return
@@ -234,5 +288,5 @@ func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		a.handler.ServeHTTP(w, r)
 		return
 	}
-	writeErrorResponse(w, ErrSignatureVersionNotSupported, r.URL)
+	writeErrorResponse(w, ErrSignatureVersionNotSupported, r)
 }
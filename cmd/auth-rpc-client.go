@@ -135,6 +135,18 @@ func (authClient *AuthRPCClient) Call(serviceMethod string, args interface {
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 
+	// Traced calls pass their span to the server, which records its
+	// own span of the call as a child.
+	if span := globalTracer.startSpan(serviceMethod, traceKindClient); span != nil {
+		if targs, ok := args.(interface {
+			setTraceContext(ctx traceContext)
+		}); ok {
+			targs.setTraceContext(span.context())
+		}
+		span.setTag("peer.address", authClient.config.serverAddr)
+		defer func() { span.finish(err) }()
+	}
+
 	for i := range newRetryTimer(authClient.config.retryUnit, authClient.config.retryCap, doneCh) {
 		if err = authClient.call(serviceMethod, args, reply); err == rpc.ErrShutdown {
 			// As connection at server side is closed, close the rpc client.
@@ -0,0 +1,290 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Current version of the backup format.
+	serverBackupVersion = "1"
+
+	// Default interval between scheduled backups.
+	defaultBackupInterval = 24 * time.Hour
+
+	// Default prefix of backup objects in the backup bucket.
+	defaultBackupPrefix = "minio-backup/"
+
+	// Time format of backup object names, sorting them by time.
+	backupTimeFormat = "20060102T150405Z"
+)
+
+// backupConfig - scheduled backups of the server configuration and
+// bucket metadata into a bucket of this deployment.
+type backupConfig struct {
+	// Bucket receiving the backups, backups are disabled if empty.
+	Bucket string `json:"bucket"`
+
+	// Prefix of backup objects, "minio-backup/" if empty.
+	Prefix string `json:"prefix"`
+
+	// Interval between backups, e.g. "6h", 24 hours if empty.
+	Interval string `json:"interval"`
+
+	// Number of backups kept, older ones are removed, 0 keeps all.
+	Keep int `json:"keep"`
+}
+
+// validateBackupConfig - validates backup settings.
+func validateBackupConfig(config backupConfig) error {
+	if config.Bucket == "" {
+		return nil
+	}
+	if !IsValidBucketName(config.Bucket) {
+		return errors.New("bucket: invalid bucket name " + config.Bucket)
+	}
+	if !IsValidObjectName(config.getObjectName(time.Time{})) {
+		return errors.New("prefix: invalid object prefix " + config.Prefix)
+	}
+	if config.Interval != "" {
+		interval, err := time.ParseDuration(config.Interval)
+		if err != nil {
+			return fmt.Errorf("interval: %v", err)
+		}
+		if interval <= 0 {
+			return errors.New("interval: must be positive")
+		}
+	}
+	if config.Keep < 0 {
+		return errors.New("keep: must not be negative")
+	}
+	return nil
+}
+
+// getPrefix - returns the prefix of backup objects.
+func (config backupConfig) getPrefix() string {
+	if config.Prefix == "" {
+		return defaultBackupPrefix
+	}
+	return config.Prefix
+}
+
+// getInterval - returns the interval between scheduled backups.
+func (config backupConfig) getInterval() time.Duration {
+	if interval, _ := time.ParseDuration(config.Interval); interval > 0 {
+		return interval
+	}
+	return defaultBackupInterval
+}
+
+// getObjectName - returns the name of the backup object taken at t.
+func (config backupConfig) getObjectName(t time.Time) string {
+	return config.getPrefix() + "backup-" + t.UTC().Format(backupTimeFormat) + ".json"
+}
+
+// serverBackup - a snapshot of the server configuration, including
+// credentials, and of the policy and notification configuration of
+// every bucket.
+type serverBackup struct {
+	Version string               `json:"version"`
+	Time    time.Time            `json:"time"`
+	Config  json.RawMessage      `json:"config"`
+	Buckets []bucketConfigBundle `json:"buckets"`
+}
+
+// newServerBackup - takes a snapshot of the configuration.
+func newServerBackup(objAPI ObjectLayer, now time.Time) (serverBackup, error) {
+	serverConfigMu.RLock()
+	configBytes, err := json.Marshal(serverConfig)
+	serverConfigMu.RUnlock()
+	if err != nil {
+		return serverBackup{}, err
+	}
+
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		return serverBackup{}, err
+	}
+	backup := serverBackup{
+		Version: serverBackupVersion,
+		Time:    now.UTC(),
+		Config:  configBytes,
+	}
+	for _, bucket := range buckets {
+		bundle, err := exportBucketConfig(bucket.Name, objAPI)
+		if err != nil {
+			return serverBackup{}, err
+		}
+		backup.Buckets = append(backup.Buckets, bundle)
+	}
+	return backup, nil
+}
+
+// createBackup - saves a snapshot of the configuration as the object
+// of the backup bucket named after t. Nothing is done if the object
+// exists already, i.e. another server took the backup. Backups beyond
+// the number to keep are removed, oldest first.
+func createBackup(objAPI ObjectLayer, config backupConfig, t time.Time) (string, error) {
+	object := config.getObjectName(t)
+
+	objectLock := globalNSMutex.NewNSLock(config.Bucket, object)
+	objectLock.Lock()
+	_, err := objAPI.GetObjectInfo(config.Bucket, object)
+	if err == nil {
+		objectLock.Unlock()
+		return object, nil
+	}
+	if !isErrObjectNotFound(err) {
+		objectLock.Unlock()
+		return "", err
+	}
+	backup, err := newServerBackup(objAPI, t)
+	if err != nil {
+		objectLock.Unlock()
+		return "", err
+	}
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		objectLock.Unlock()
+		return "", err
+	}
+	metadata := map[string]string{"content-type": "application/json"}
+	_, err = objAPI.PutObject(config.Bucket, object, int64(len(backupBytes)), bytes.NewReader(backupBytes), metadata, "")
+	objectLock.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	return object, pruneBackups(objAPI, config)
+}
+
+// pruneBackups - removes the oldest backups beyond the number to keep.
+func pruneBackups(objAPI ObjectLayer, config backupConfig) error {
+	if config.Keep == 0 {
+		return nil
+	}
+	prefix := config.getPrefix() + "backup-"
+	var backups []string
+	marker := ""
+	for {
+		lo, err := objAPI.ListObjects(config.Bucket, prefix, marker, "", 1000)
+		if err != nil {
+			return err
+		}
+		for _, obj := range lo.Objects {
+			if strings.HasSuffix(obj.Name, ".json") {
+				backups = append(backups, obj.Name)
+			}
+		}
+		if !lo.IsTruncated {
+			break
+		}
+		marker = lo.NextMarker
+	}
+	sort.Strings(backups)
+	for len(backups) > config.Keep {
+		objectLock := globalNSMutex.NewNSLock(config.Bucket, backups[0])
+		objectLock.Lock()
+		err := objAPI.DeleteObject(config.Bucket, backups[0])
+		objectLock.Unlock()
+		if err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// readBackup - reads and validates the backup object of the backup
+// bucket.
+func readBackup(objAPI ObjectLayer, bucket, object string) (serverBackup, error) {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	var buffer bytes.Buffer
+	if err := objAPI.GetObject(bucket, object, 0, -1, &buffer); err != nil {
+		return serverBackup{}, err
+	}
+	var backup serverBackup
+	if err := json.Unmarshal(buffer.Bytes(), &backup); err != nil {
+		return serverBackup{}, errInvalidBackup
+	}
+	if backup.Version != serverBackupVersion || len(backup.Config) == 0 {
+		return serverBackup{}, errInvalidBackup
+	}
+	return backup, nil
+}
+
+// errInvalidBackup - the backup object is not a valid backup.
+var errInvalidBackup = errors.New("Invalid backup")
+
+// restoreBucketConfigs - restores the configuration of every bucket of
+// backup, creating the buckets which do not exist.
+func restoreBucketConfigs(objAPI ObjectLayer, backup serverBackup) APIErrorCode {
+	for _, bundle := range backup.Buckets {
+		bucketLock := globalNSMutex.NewNSLock(bundle.Bucket, "")
+		bucketLock.Lock()
+		err := objAPI.MakeBucket(bundle.Bucket)
+		bucketLock.Unlock()
+		if err != nil {
+			if _, ok := errorCause(err).(BucketExists); !ok {
+				return toAPIErrorCode(err)
+			}
+		}
+		bundleBytes, err := json.Marshal(bundle)
+		if err != nil {
+			return toAPIErrorCode(err)
+		}
+		if s3Error := importBucketConfig(bundle.Bucket, bundleBytes, objAPI); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+	return ErrNone
+}
+
+// startBackups - takes a backup every interval into the backup bucket,
+// if configured, until the server stops. Backups are named after the
+// start of their interval, so that only one of the servers of a
+// distributed setup takes it.
+func startBackups(objAPI ObjectLayer) {
+	go func() {
+		for {
+			config := serverConfig.GetBackup()
+			interval := config.getInterval()
+			select {
+			case <-time.After(interval):
+				if config.Bucket == "" {
+					continue
+				}
+				t := time.Now().UTC().Truncate(interval)
+				if _, err := createBackup(objAPI, config, t); err != nil {
+					errorIf(err, "Unable to back up configuration into %s.", config.Bucket)
+				}
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
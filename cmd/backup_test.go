@@ -0,0 +1,186 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests validation of backup settings.
+func TestValidateBackupConfig(t *testing.T) {
+	testCases := []struct {
+		config  backupConfig
+		success bool
+	}{
+		{backupConfig{}, true},
+		{backupConfig{Bucket: "backups"}, true},
+		{backupConfig{Bucket: "backups", Prefix: "minio/", Interval: "6h", Keep: 7}, true},
+		// Settings are ignored while disabled.
+		{backupConfig{Interval: "never"}, true},
+		{backupConfig{Bucket: "Backups"}, false},
+		{backupConfig{Bucket: "backups", Prefix: "/minio"}, false},
+		{backupConfig{Bucket: "backups", Interval: "never"}, false},
+		{backupConfig{Bucket: "backups", Interval: "-1h"}, false},
+		{backupConfig{Bucket: "backups", Keep: -1}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateBackupConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests taking, pruning and restoring backups.
+func TestCreateRestoreBackup(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	objLayer := adminTestBed.objLayer
+	for _, bucket := range []string{"backups", "srcbucket"} {
+		if err = objLayer.MakeBucket(bucket); err != nil {
+			t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+		}
+	}
+	if err = initBucketPolicies(objLayer); err != nil {
+		t.Fatalf("Failed to initialize bucket policies - %v", err)
+	}
+	srcPolicy := `{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::srcbucket/public/*"]}]}`
+	policy, s3Error, _ := validateBucketPolicy("srcbucket", []byte(srcPolicy))
+	if s3Error != ErrNone {
+		t.Fatalf("Failed to validate bucket policy - %v", s3Error)
+	}
+	if err = persistAndNotifyBucketPolicyChange("srcbucket", policyChange{false, policy}, objLayer); err != nil {
+		t.Fatalf("Failed to set bucket policy - %v", err)
+	}
+
+	// Backups beyond keep are removed, oldest first, and backups of
+	// the same time are taken once.
+	config := backupConfig{Bucket: "backups", Keep: 2}
+	start := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	var objects []string
+	for i := 0; i < 3; i++ {
+		object, cErr := createBackup(objLayer, config, start.Add(time.Duration(i)*time.Hour))
+		if cErr != nil {
+			t.Fatalf("Backup %d: Failed to take backup - %v", i+1, cErr)
+		}
+		objects = append(objects, object)
+	}
+	if objects[0] != "minio-backup/backup-20170601T000000Z.json" {
+		t.Fatalf("Unexpected backup object name %s", objects[0])
+	}
+	if object, _ := createBackup(objLayer, config, start.Add(2*time.Hour)); object != objects[2] {
+		t.Fatalf("Expected backup %s, got %s", objects[2], object)
+	}
+	lo, err := objLayer.ListObjects("backups", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lo.Objects) != 2 || lo.Objects[0].Name != objects[1] || lo.Objects[1].Name != objects[2] {
+		t.Fatalf("Expected backups %v, got %v", objects[1:], lo.Objects)
+	}
+
+	backup, err := readBackup(objLayer, "backups", objects[2])
+	if err != nil {
+		t.Fatalf("Failed to read backup - %v", err)
+	}
+	var savedConfig serverConfigV16
+	if err = json.Unmarshal(backup.Config, &savedConfig); err != nil {
+		t.Fatal(err)
+	}
+	if savedConfig.Credential.SecretKey != serverConfig.GetCredential().SecretKey {
+		t.Fatal("Expected backup to carry the server credentials")
+	}
+
+	// Restoring creates missing buckets and replaces their configuration.
+	if err = persistAndNotifyBucketPolicyChange("srcbucket", policyChange{true, nil}, objLayer); err != nil {
+		t.Fatalf("Failed to remove bucket policy - %v", err)
+	}
+	if err = objLayer.DeleteBucket("srcbucket"); err != nil {
+		t.Fatalf("Failed to delete bucket - %v", err)
+	}
+	if s3Error = restoreBucketConfigs(objLayer, backup); s3Error != ErrNone {
+		t.Fatalf("Failed to restore bucket configuration - %v", s3Error)
+	}
+	if _, err = readBucketPolicy("srcbucket", objLayer); err != nil {
+		t.Fatalf("Expected bucket policy of srcbucket to be restored - %v", err)
+	}
+
+	// Other objects are not backups.
+	data := []byte("hello")
+	if _, err = objLayer.PutObject("backups", "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readBackup(objLayer, "backups", "object"); err != errInvalidBackup {
+		t.Fatalf("Expected %v, got %v", errInvalidBackup, err)
+	}
+}
+
+// Tests the create backup admin handler.
+func TestCreateBackupHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	if err = adminTestBed.objLayer.MakeBucket("backups"); err != nil {
+		t.Fatalf("Failed to make bucket - %v", err)
+	}
+
+	cred := serverConfig.GetCredential()
+	sendRequest := func() *httptest.ResponseRecorder {
+		req, rerr := newTestRequest("POST", "/minio/admin/v1/backup", 0, nil)
+		if rerr != nil {
+			t.Fatalf("Failed to construct backup request - %v", rerr)
+		}
+		if rerr = signRequestV4(req, cred.AccessKey, cred.SecretKey); rerr != nil {
+			t.Fatalf("Failed to sign backup request - %v", rerr)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := sendRequest(); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected backup without backup bucket to fail with %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	serverConfig.SetBackup(backupConfig{Bucket: "backups"})
+	rec := sendRequest()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected backup to succeed but failed with %d", rec.Code)
+	}
+	var result backupResult
+	if err = json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = adminTestBed.objLayer.GetObjectInfo(result.Bucket, result.Object); err != nil {
+		t.Fatalf("Expected backup %s/%s to exist - %v", result.Bucket, result.Object, err)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "errors"
+
+// errBrowserReadOnly - browser requests changing buckets, objects,
+// policies or credentials are rejected while the browser is read-only.
+var errBrowserReadOnly = errors.New("The browser is read-only, uploads and deletes are disabled")
+
+// setBrowserReadOnly - makes the browser read-only, or writable again,
+// from now on and saves it in the config. The S3 API is not affected.
+func setBrowserReadOnly(readOnly bool) error {
+	serverConfig.SetBrowserReadOnly(readOnly)
+	return serverConfig.Save()
+}
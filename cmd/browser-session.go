@@ -0,0 +1,162 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// Interval between removals of expired sessions from the idle
+// session tracker.
+const browserSessionSweepInterval = time.Minute
+
+// browserSessionConfig - lifetime and revocation of browser sessions.
+type browserSessionConfig struct {
+	// Lifetime of browser sessions, e.g. "8h", defaults to 24 hours.
+	Duration string `json:"duration"`
+
+	// Browser sessions not used for longer, e.g. "30m", are closed,
+	// never if empty.
+	IdleTimeout string `json:"idleTimeout"`
+
+	// Browser sessions started before are rejected.
+	RevokedBefore time.Time `json:"revokedBefore"`
+}
+
+// validateBrowserSessionConfig - validates browser session settings.
+func validateBrowserSessionConfig(config browserSessionConfig) error {
+	for _, d := range []struct {
+		key   string
+		value string
+	}{
+		{"duration", config.Duration},
+		{"idleTimeout", config.IdleTimeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", d.key, err)
+		}
+		if duration <= 0 {
+			return errors.New(d.key + ": must be positive")
+		}
+	}
+	return nil
+}
+
+// getDuration - returns the lifetime of browser sessions.
+func (config browserSessionConfig) getDuration() time.Duration {
+	if duration, _ := time.ParseDuration(config.Duration); duration > 0 {
+		return duration
+	}
+	return defaultJWTExpiry
+}
+
+// getIdleTimeout - returns the idle timeout of browser sessions, zero
+// if sessions are never closed for being idle.
+func (config browserSessionConfig) getIdleTimeout() time.Duration {
+	idleTimeout, _ := time.ParseDuration(config.IdleTimeout)
+	return idleTimeout
+}
+
+// browserSessionState - last use of a browser session.
+type browserSessionState struct {
+	lastSeen time.Time
+	expires  time.Time
+	closed   bool
+}
+
+// browserSessionTracker - tracks the use of browser sessions on this
+// server to close idle ones. Sessions are forgotten once they expire.
+type browserSessionTracker struct {
+	mu       sync.Mutex
+	sessions map[string]*browserSessionState
+	swept    time.Time
+}
+
+func newBrowserSessionTracker() *browserSessionTracker {
+	return &browserSessionTracker{sessions: make(map[string]*browserSessionState)}
+}
+
+// touch - records the use of session id expiring at expires at now,
+// returns false if the session was idle for longer than idleTimeout.
+// Sessions seen for the first time are accepted.
+func (t *browserSessionTracker) touch(id string, expires, now time.Time, idleTimeout time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.swept) > browserSessionSweepInterval {
+		for sessionID, state := range t.sessions {
+			if now.After(state.expires) {
+				delete(t.sessions, sessionID)
+			}
+		}
+		t.swept = now
+	}
+
+	state, ok := t.sessions[id]
+	if !ok {
+		t.sessions[id] = &browserSessionState{lastSeen: now, expires: expires}
+		return true
+	}
+	if state.closed || now.Sub(state.lastSeen) > idleTimeout {
+		state.closed = true
+		return false
+	}
+	state.lastSeen = now
+	return true
+}
+
+// checkBrowserSession - verifies that the browser session of a valid
+// JWT token is neither revoked nor idle for too long.
+func checkBrowserSession(jwtToken *jwtgo.Token) error {
+	claims, ok := jwtToken.Claims.(jwtgo.MapClaims)
+	if !ok {
+		return errAuthentication
+	}
+	config := serverConfig.GetBrowserSession()
+	if !config.RevokedBefore.IsZero() {
+		issuedAt, _ := claims["iat"].(float64)
+		if int64(issuedAt) < config.RevokedBefore.Unix() {
+			return errAuthentication
+		}
+	}
+	// Tokens of inter-node calls do not carry a session id.
+	sessionID, _ := claims["jti"].(string)
+	if idleTimeout := config.getIdleTimeout(); idleTimeout > 0 && sessionID != "" {
+		expires, _ := claims["exp"].(float64)
+		if !globalBrowserSessions.touch(sessionID, time.Unix(int64(expires), 0), time.Now().UTC(), idleTimeout) {
+			return errAuthentication
+		}
+	}
+	return nil
+}
+
+// revokeBrowserSessions - rejects browser sessions started before the
+// given time from now on, and saves it in the config. Revocations are
+// never moved back in time.
+func revokeBrowserSessions(before time.Time) error {
+	serverConfig.RevokeBrowserSessions(before)
+	return serverConfig.Save()
+}
@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// Tests validation of browser session settings.
+func TestValidateBrowserSessionConfig(t *testing.T) {
+	testCases := []struct {
+		config  browserSessionConfig
+		success bool
+	}{
+		{browserSessionConfig{}, true},
+		{browserSessionConfig{Duration: "8h", IdleTimeout: "30m"}, true},
+		{browserSessionConfig{Duration: "eight hours"}, false},
+		{browserSessionConfig{Duration: "-1h"}, false},
+		{browserSessionConfig{IdleTimeout: "0s"}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateBrowserSessionConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests that the session tracker closes idle sessions.
+func TestBrowserSessionTracker(t *testing.T) {
+	tracker := newBrowserSessionTracker()
+	now := time.Now().UTC()
+	expires := now.Add(time.Hour)
+	idleTimeout := 10 * time.Minute
+
+	if !tracker.touch("session", expires, now, idleTimeout) {
+		t.Fatal("Expected new session to be accepted")
+	}
+	if !tracker.touch("session", expires, now.Add(5*time.Minute), idleTimeout) {
+		t.Fatal("Expected session used within the idle timeout to be accepted")
+	}
+	if tracker.touch("session", expires, now.Add(16*time.Minute), idleTimeout) {
+		t.Fatal("Expected idle session to be rejected")
+	}
+	if tracker.touch("session", expires, now.Add(17*time.Minute), idleTimeout) {
+		t.Fatal("Expected closed session to stay rejected")
+	}
+	if !tracker.touch("other", expires, now.Add(17*time.Minute), idleTimeout) {
+		t.Fatal("Expected other session to be accepted")
+	}
+
+	// Expired sessions are forgotten.
+	tracker.touch("later", now.Add(3*time.Hour), now.Add(2*time.Hour), idleTimeout)
+	if _, ok := tracker.sessions["session"]; ok {
+		t.Fatal("Expected expired session to be removed")
+	}
+}
+
+// Tests the duration, idle timeout and revocation of browser sessions.
+func TestCheckBrowserSession(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	parseToken := func(token string) jwtgo.MapClaims {
+		jwtToken, pErr := jwtgo.Parse(token, keyFuncCallback)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		return jwtToken.Claims.(jwtgo.MapClaims)
+	}
+
+	serverConfig.SetBrowserSession(browserSessionConfig{Duration: "1h", IdleTimeout: "30m"})
+	token, err := authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := parseToken(token)
+	if lifetime := int64(claims["exp"].(float64) - claims["iat"].(float64)); lifetime != 3600 {
+		t.Fatalf("Expected session duration of 3600 seconds, got %d", lifetime)
+	}
+	if !isWebTokenValid(token) {
+		t.Fatal("Expected token of a new session to be valid")
+	}
+
+	// Idle sessions are closed.
+	jti := claims["jti"].(string)
+	globalBrowserSessions.sessions[jti].lastSeen = time.Now().UTC().Add(-time.Hour)
+	if isWebTokenValid(token) {
+		t.Fatal("Expected token of an idle session to be rejected")
+	}
+
+	// Inter-node tokens are not tracked as browser sessions.
+	nodeToken, err := authenticateNode(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parseToken(nodeToken)["jti"]; ok {
+		t.Fatal("Expected inter-node token without session id")
+	}
+
+	// Sessions started before the revocation are rejected, tokens
+	// are issued with a precision of one second.
+	serverConfig.SetBrowserSession(browserSessionConfig{})
+	token, err = authenticateWeb(cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = revokeBrowserSessions(time.Now().UTC().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if isWebTokenValid(token) {
+		t.Fatal("Expected token of a revoked session to be rejected")
+	}
+
+	// Revocations are saved and never moved back in time.
+	revokedBefore := serverConfig.GetBrowserSession().RevokedBefore
+	if err = revokeBrowserSessions(revokedBefore.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err = loadConfig(envParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if !serverConfig.GetBrowserSession().RevokedBefore.Equal(revokedBefore) {
+		t.Fatalf("Expected revocation at %s, got %s", revokedBefore, serverConfig.GetBrowserSession().RevokedBefore)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Current version of the bucket configuration bundle.
+const bucketConfigBundleVersion = "1"
+
+// Maximum size of a bucket configuration bundle.
+const maxBucketConfigBundleSize = 1 * humanize.MiByte
+
+// bucketConfigBundle - the full configuration of a bucket as a single
+// JSON document, used to replicate bucket configuration across
+// deployments. Tagging and quota are not supported by the server yet,
+// a bundle carries bucket policy, notification and lifecycle only.
+type bucketConfigBundle struct {
+	Version string `json:"version"`
+	Bucket  string `json:"bucket"`
+	// Bucket policy JSON document, omitted if no policy is set.
+	Policy json.RawMessage `json:"policy,omitempty"`
+	// Bucket notification XML document, as accepted by the S3
+	// PutBucketNotification API, omitted if no notification is set.
+	Notification string `json:"notification,omitempty"`
+	// Bucket lifecycle XML document, as accepted by the S3
+	// PutBucketLifecycleConfiguration API, omitted if no lifecycle
+	// is set.
+	Lifecycle string `json:"lifecycle,omitempty"`
+}
+
+// exportBucketConfig - collects the configuration of bucket.
+func exportBucketConfig(bucket string, objAPI ObjectLayer) (bucketConfigBundle, error) {
+	bundle := bucketConfigBundle{
+		Version: bucketConfigBundleVersion,
+		Bucket:  bucket,
+	}
+
+	policyReader, err := readBucketPolicyJSON(bucket, objAPI)
+	if err == nil {
+		var policyBytes []byte
+		if policyBytes, err = ioutil.ReadAll(policyReader); err != nil {
+			return bucketConfigBundle{}, err
+		}
+		bundle.Policy = policyBytes
+	} else if _, ok := err.(BucketPolicyNotFound); !ok {
+		return bucketConfigBundle{}, err
+	}
+
+	ncfg, err := loadNotificationConfig(bucket, objAPI)
+	if err == nil {
+		var notificationBytes []byte
+		if notificationBytes, err = xml.Marshal(ncfg); err != nil {
+			return bucketConfigBundle{}, err
+		}
+		bundle.Notification = string(notificationBytes)
+	} else if err != errNoSuchNotifications {
+		return bucketConfigBundle{}, err
+	}
+
+	lcfg, err := readBucketLifecycle(bucket, objAPI)
+	if err == nil {
+		var lifecycleBytes []byte
+		if lifecycleBytes, err = xml.Marshal(lcfg); err != nil {
+			return bucketConfigBundle{}, err
+		}
+		bundle.Lifecycle = string(lifecycleBytes)
+	} else if err != errNoSuchLifecycleConfiguration {
+		return bucketConfigBundle{}, err
+	}
+
+	return bundle, nil
+}
+
+// rewritePolicyBucket - rewrites the resources of policyBytes which
+// refer to srcBucket to refer to dstBucket instead.
+func rewritePolicyBucket(policyBytes []byte, srcBucket, dstBucket string) ([]byte, error) {
+	var policy bucketPolicy
+	if err := json.Unmarshal(policyBytes, &policy); err != nil {
+		return nil, err
+	}
+	srcARN := bucketARNPrefix + srcBucket
+	dstARN := bucketARNPrefix + dstBucket
+	for i, statement := range policy.Statements {
+		resources := set.NewStringSet()
+		for resource := range statement.Resources {
+			if resource == srcARN || strings.HasPrefix(resource, srcARN+"/") {
+				resource = dstARN + strings.TrimPrefix(resource, srcARN)
+			}
+			resources.Add(resource)
+		}
+		policy.Statements[i].Resources = resources
+	}
+	return json.Marshal(policy)
+}
+
+// parseBucketConfigBundle - parses and validates a bucket configuration
+// bundle for bucket, which may differ from the exported bucket. Policy
+// resources of the exported bucket are rewritten to refer to bucket.
+func parseBucketConfigBundle(bucket string, bundleBytes []byte) (*bucketPolicy, *notificationConfig, *lifecycleConfiguration, APIErrorCode) {
+	var bundle bucketConfigBundle
+	if err := json.Unmarshal(bundleBytes, &bundle); err != nil {
+		return nil, nil, nil, ErrAdminInvalidBucketConfig
+	}
+	if bundle.Version != bucketConfigBundleVersion {
+		return nil, nil, nil, ErrAdminInvalidBucketConfig
+	}
+
+	var policy *bucketPolicy
+	if len(bundle.Policy) > 0 {
+		policyBytes := []byte(bundle.Policy)
+		if bundle.Bucket != "" && bundle.Bucket != bucket {
+			var err error
+			if policyBytes, err = rewritePolicyBucket(policyBytes, bundle.Bucket, bucket); err != nil {
+				return nil, nil, nil, ErrMalformedPolicy
+			}
+		}
+		var s3Error APIErrorCode
+		var err error
+		if policy, s3Error, err = validateBucketPolicy(bucket, policyBytes); s3Error != ErrNone {
+			errorIf(err, "Unable to validate bucket policy of the bucket configuration bundle.")
+			return nil, nil, nil, s3Error
+		}
+	}
+
+	// An empty notification configuration removes all notifications.
+	ncfg := &notificationConfig{}
+	if bundle.Notification != "" {
+		if err := xml.Unmarshal([]byte(bundle.Notification), ncfg); err != nil {
+			return nil, nil, nil, ErrMalformedXML
+		}
+		if s3Error := validateNotificationConfig(*ncfg); s3Error != ErrNone {
+			return nil, nil, nil, s3Error
+		}
+	}
+
+	var lcfg *lifecycleConfiguration
+	if bundle.Lifecycle != "" {
+		lcfg = &lifecycleConfiguration{}
+		if err := xml.Unmarshal([]byte(bundle.Lifecycle), lcfg); err != nil {
+			return nil, nil, nil, ErrMalformedXML
+		}
+		if s3Error := validateLifecycleConfig(*lcfg); s3Error != ErrNone {
+			return nil, nil, nil, s3Error
+		}
+	}
+
+	return policy, ncfg, lcfg, ErrNone
+}
+
+// applyBucketConfig - replaces the configuration of bucket, a nil
+// policy or lifecycle removes it.
+func applyBucketConfig(bucket string, policy *bucketPolicy, ncfg *notificationConfig, lcfg *lifecycleConfiguration, objAPI ObjectLayer) error {
+	// Acquire a write lock on bucket before modifying its policy.
+	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	bucketLock.Lock()
+	pCh := policyChange{IsRemove: policy == nil, BktPolicy: policy}
+	err := persistAndNotifyBucketPolicyChange(bucket, pCh, objAPI)
+	bucketLock.Unlock()
+	if err != nil {
+		if _, ok := err.(BucketPolicyNotFound); !ok || policy != nil {
+			return err
+		}
+	}
+
+	if err = PutBucketNotificationConfig(bucket, ncfg, objAPI); err != nil {
+		return err
+	}
+
+	if lcfg == nil {
+		return removeBucketLifecycle(bucket, objAPI)
+	}
+	return persistBucketLifecycle(bucket, lcfg, objAPI)
+}
+
+// importBucketConfig - replaces the configuration of bucket with the
+// bundle, configuration missing from the bundle is removed. Nothing
+// is changed unless the whole bundle is valid, the previous
+// configuration is restored if applying the bundle fails.
+func importBucketConfig(bucket string, bundleBytes []byte, objAPI ObjectLayer) APIErrorCode {
+	policy, ncfg, lcfg, s3Error := parseBucketConfigBundle(bucket, bundleBytes)
+	if s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Save the current configuration to roll back to.
+	prevBundle, err := exportBucketConfig(bucket, objAPI)
+	if err != nil {
+		errorIf(err, "Unable to read bucket configuration of %s.", bucket)
+		return toAPIErrorCode(err)
+	}
+
+	if err = applyBucketConfig(bucket, policy, ncfg, lcfg, objAPI); err != nil {
+		errorIf(err, "Unable to import bucket configuration of %s.", bucket)
+		rollbackBucketConfig(bucket, prevBundle, objAPI)
+		return toAPIErrorCode(err)
+	}
+	return ErrNone
+}
+
+// rollbackBucketConfig - restores the configuration of bucket saved
+// before a failed import.
+func rollbackBucketConfig(bucket string, prevBundle bucketConfigBundle, objAPI ObjectLayer) {
+	prevBytes, err := json.Marshal(prevBundle)
+	if err != nil {
+		errorIf(err, "Unable to roll back bucket configuration of %s.", bucket)
+		return
+	}
+	policy, ncfg, lcfg, s3Error := parseBucketConfigBundle(bucket, prevBytes)
+	if s3Error != ErrNone {
+		errorIf(errInvalidArgument, "Unable to roll back bucket configuration of %s.", bucket)
+		return
+	}
+	errorIf(applyBucketConfig(bucket, policy, ncfg, lcfg, objAPI), "Unable to roll back bucket configuration of %s.", bucket)
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Lock held while counting and creating buckets.
+const bucketCreationLockPath = "bucket-creation.lock"
+
+// bucketCreationConfig - restricts creation of new buckets, so that
+// tenants of a shared deployment cannot squat bucket names. Existing
+// buckets are never affected.
+type bucketCreationConfig struct {
+	// Maximum number of buckets, 0 means unlimited.
+	MaxBuckets int `json:"maxBuckets"`
+
+	// New bucket names must start with one of the prefixes, if any.
+	NamePrefixes []string `json:"namePrefixes"`
+
+	// New bucket names must fully match the regular expression, if set.
+	NamePattern string `json:"namePattern"`
+}
+
+// bucketCreationPolicy - compiled form of bucketCreationConfig.
+type bucketCreationPolicy struct {
+	maxBuckets   int
+	namePrefixes []string
+	nameRegexp   *regexp.Regexp
+}
+
+// newBucketCreationPolicy - validates and compiles config, returns
+// nil if config does not restrict bucket creation.
+func newBucketCreationPolicy(config bucketCreationConfig) (*bucketCreationPolicy, error) {
+	if config.MaxBuckets < 0 {
+		return nil, fmt.Errorf("Invalid maximum number of buckets %d", config.MaxBuckets)
+	}
+	policy := &bucketCreationPolicy{maxBuckets: config.MaxBuckets}
+	for _, prefix := range config.NamePrefixes {
+		if prefix == "" {
+			return nil, fmt.Errorf("Empty bucket name prefix")
+		}
+		policy.namePrefixes = append(policy.namePrefixes, prefix)
+	}
+	if config.NamePattern != "" {
+		nameRegexp, err := regexp.Compile("^(?:" + config.NamePattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid bucket name pattern %s: %v", config.NamePattern, err)
+		}
+		policy.nameRegexp = nameRegexp
+	}
+	if policy.maxBuckets == 0 && len(policy.namePrefixes) == 0 && policy.nameRegexp == nil {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// isNameAllowed - checks bucket against the name prefixes and pattern.
+func (p *bucketCreationPolicy) isNameAllowed(bucket string) bool {
+	if len(p.namePrefixes) > 0 {
+		hasPrefix := false
+		for _, prefix := range p.namePrefixes {
+			if strings.HasPrefix(bucket, prefix) {
+				hasPrefix = true
+				break
+			}
+		}
+		if !hasPrefix {
+			return false
+		}
+	}
+	return p.nameRegexp == nil || p.nameRegexp.MatchString(bucket)
+}
+
+// checkMakeBucket - returns an error if the policy does not allow
+// creating bucket, a nil policy allows everything.
+func (p *bucketCreationPolicy) checkMakeBucket(bucket string, objAPI ObjectLayer) error {
+	if p == nil {
+		return nil
+	}
+	if !p.isNameAllowed(bucket) {
+		return traceError(errBucketNameNotAllowed)
+	}
+	if p.maxBuckets > 0 {
+		buckets, err := objAPI.ListBuckets()
+		if err != nil {
+			return err
+		}
+		if len(buckets) >= p.maxBuckets {
+			return traceError(errTooManyBuckets)
+		}
+	}
+	return nil
+}
+
+// makeBucket - creates bucket if the policy allows it. Counting the
+// existing buckets and creating the new one happen under a lock shared
+// by all buckets, so concurrent requests cannot exceed the maximum.
+func (p *bucketCreationPolicy) makeBucket(bucket string, objAPI ObjectLayer) error {
+	if p != nil && p.maxBuckets > 0 {
+		creationLock := globalNSMutex.NewNSLock(minioMetaBucket, bucketCreationLockPath)
+		creationLock.Lock()
+		defer creationLock.Unlock()
+	}
+	if err := p.checkMakeBucket(bucket, objAPI); err != nil {
+		return err
+	}
+	if err := objAPI.MakeBucket(bucket); err != nil {
+		errorIf(err, "Unable to create a bucket.")
+		return err
+	}
+	return nil
+}
+
+// initBucketCreationPolicy - initializes the global bucket creation
+// policy from server config.
+func initBucketCreationPolicy() error {
+	policy, err := newBucketCreationPolicy(serverConfig.GetBucketCreation())
+	if err != nil {
+		return err
+	}
+	globalBucketCreationPolicy = policy
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validation of bucket creation configuration.
+func TestNewBucketCreationPolicy(t *testing.T) {
+	testCases := []struct {
+		config    bucketCreationConfig
+		expectNil bool
+		expectErr bool
+	}{
+		// Nothing restricted.
+		{bucketCreationConfig{}, true, false},
+		{bucketCreationConfig{MaxBuckets: 10}, false, false},
+		{bucketCreationConfig{NamePrefixes: []string{"tenant-"}}, false, false},
+		{bucketCreationConfig{NamePattern: "[a-z]+"}, false, false},
+		// Invalid configurations.
+		{bucketCreationConfig{MaxBuckets: -1}, true, true},
+		{bucketCreationConfig{NamePrefixes: []string{""}}, true, true},
+		{bucketCreationConfig{NamePattern: "[a-z"}, true, true},
+	}
+
+	for i, testCase := range testCases {
+		policy, err := newBucketCreationPolicy(testCase.config)
+		if testCase.expectErr != (err != nil) {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+		if testCase.expectNil != (policy == nil) {
+			t.Errorf("Test %d: Expected nil policy %v, got %v", i+1, testCase.expectNil, policy)
+		}
+	}
+}
+
+// Tests bucket name checks of the bucket creation policy.
+func TestBucketCreationPolicyIsNameAllowed(t *testing.T) {
+	policy, err := newBucketCreationPolicy(bucketCreationConfig{
+		NamePrefixes: []string{"tenant1-", "tenant2-"},
+		NamePattern:  "[a-z0-9-]+",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		bucket  string
+		allowed bool
+	}{
+		{"tenant1-photos", true},
+		{"tenant2-videos", true},
+		{"tenant3-photos", false},
+		{"photos", false},
+		// Pattern must match the whole name.
+		{"tenant1-photos.archive", false},
+	}
+
+	for i, testCase := range testCases {
+		if allowed := policy.isNameAllowed(testCase.bucket); allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %s to be allowed %v, got %v", i+1, testCase.bucket, testCase.allowed, allowed)
+		}
+	}
+
+	// A nil policy allows everything.
+	var nilPolicy *bucketCreationPolicy
+	if err = nilPolicy.checkMakeBucket("anything", nil); err != nil {
+		t.Errorf("Expected nil policy to allow bucket creation, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// directoryIndexConfig - buckets whose anonymous listings are rendered
+// as an HTML directory index for browsers. Anonymous listings are only
+// allowed when the bucket policy allows `s3:ListBucket`.
+type directoryIndexConfig struct {
+	// Buckets with a directory index, '*' wildcards are supported.
+	Buckets []string `json:"buckets"`
+}
+
+// isDirectoryIndexBucket - returns true if bucket has a directory index.
+func isDirectoryIndexBucket(bucket string) bool {
+	if serverConfig == nil || isMinioMetaBucketName(bucket) {
+		return false
+	}
+	for _, pattern := range serverConfig.GetDirectoryIndex().Buckets {
+		if wildcard.MatchSimple(pattern, bucket) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDirectoryIndexRequest - returns true if r is an anonymous listing
+// of bucket by a browser, to be answered with a directory index.
+func isDirectoryIndexRequest(r *http.Request, bucket string) bool {
+	return getRequestAuthType(r) == authTypeAnonymous && guessIsBrowserReq(r) && isDirectoryIndexBucket(bucket)
+}
+
+// directoryIndexEntry - a common prefix or an object of a directory
+// index.
+type directoryIndexEntry struct {
+	Name         string
+	Link         string
+	Size         int64
+	LastModified string
+}
+
+// directoryIndex - contents of a directory index page.
+type directoryIndex struct {
+	Bucket   string
+	Prefix   string
+	Parent   string
+	Prefixes []directoryIndexEntry
+	Objects  []directoryIndexEntry
+	Next     string
+}
+
+var directoryIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Bucket}}/{{.Prefix}}</title></head>
+<body>
+<h1>Index of {{.Bucket}}/{{.Prefix}}</h1>
+<table>
+<tr><th>Name</th><th>Last modified</th><th>Size</th></tr>
+{{if .Parent}}<tr><td><a href="{{.Parent}}">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Prefixes}}<tr><td><a href="{{.Link}}">{{.Name}}</a></td><td></td><td>-</td></tr>
+{{end}}{{range .Objects}}<tr><td><a href="{{.Link}}">{{.Name}}</a></td><td>{{.LastModified}}</td><td>{{.Size}}</td></tr>
+{{end}}</table>
+{{if .Next}}<p><a href="{{.Next}}">Next page</a></p>
+{{end}}</body>
+</html>
+`))
+
+// getDirectoryIndexLink - returns the link to the listing of prefix in
+// bucket, continuing after marker if set.
+func getDirectoryIndexLink(bucket, prefix, marker string) string {
+	query := make(url.Values)
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+	link := (&url.URL{Path: "/" + bucket + "/"}).String()
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// writeDirectoryIndex - writes the listing of prefix in bucket as an
+// HTML page, with links to the common prefixes and the objects.
+func writeDirectoryIndex(w http.ResponseWriter, r *http.Request, bucket, prefix string, info ListObjectsInfo) {
+	index := directoryIndex{
+		Bucket: bucket,
+		Prefix: prefix,
+	}
+	if prefix != "" {
+		parent := path.Dir(strings.TrimSuffix(prefix, slashSeparator))
+		if parent == "." {
+			parent = ""
+		} else {
+			parent += slashSeparator
+		}
+		index.Parent = getDirectoryIndexLink(bucket, parent, "")
+	}
+	for _, p := range info.Prefixes {
+		index.Prefixes = append(index.Prefixes, directoryIndexEntry{
+			Name: strings.TrimPrefix(p, prefix),
+			Link: getDirectoryIndexLink(bucket, p, ""),
+		})
+	}
+	for _, object := range info.Objects {
+		index.Objects = append(index.Objects, directoryIndexEntry{
+			Name:         strings.TrimPrefix(object.Name, prefix),
+			Link:         (&url.URL{Path: "/" + bucket + "/" + object.Name}).String(),
+			Size:         object.Size,
+			LastModified: object.ModTime.UTC().Format(time.RFC1123),
+		})
+	}
+	if info.IsTruncated {
+		index.Next = getDirectoryIndexLink(bucket, prefix, info.NextMarker)
+	}
+
+	var buf bytes.Buffer
+	if err := directoryIndexTemplate.Execute(&buf, index); err != nil {
+		errorIf(err, "Unable to render directory index of %s.", bucket)
+		writeErrorResponse(w, ErrInternalError, r)
+		return
+	}
+	writeResponse(w, http.StatusOK, buf.Bytes(), mimeHTML)
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling directory index tests for both XL multiple disks and single node setup.
+func TestDirectoryIndex(t *testing.T) {
+	ExecObjectLayerAPITest(t, testDirectoryIndex, []string{"ListObjectsV1"})
+}
+
+// testDirectoryIndex - Tests that anonymous browser listings of buckets
+// with a directory index are rendered as HTML.
+func testDirectoryIndex(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	for _, object := range []string{"a&b.txt", "dir/a.txt", "dir/sub/b.txt"} {
+		if _, err := obj.PutObject(bucketName, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatalf("%s: Failed to create object: <ERROR> %v", instanceType, err)
+		}
+	}
+
+	listBucket := func(prefix, userAgent string) *httptest.ResponseRecorder {
+		queryValue := url.Values{}
+		if prefix != "" {
+			queryValue.Set("prefix", prefix)
+		}
+		req, err := newTestRequest("GET", makeTestTargetURL("", bucketName, "", queryValue), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+		}
+		req.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	browser := "Mozilla/5.0"
+
+	// Anonymous listings need the bucket policy to allow them.
+	serverConfig.SetDirectoryIndex(directoryIndexConfig{Buckets: []string{bucketName}})
+	defer serverConfig.SetDirectoryIndex(directoryIndexConfig{})
+	if rec := listBucket("", browser); rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getReadOnlyBucketStatement(bucketName, ""), getReadOnlyObjectStatement(bucketName, "")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	testCases := []struct {
+		prefix    string
+		userAgent string
+		html      bool
+		contains  []string
+	}{
+		{"", browser, true, []string{`href="/` + bucketName + `/?prefix=dir%2F"`, `>dir/<`, `href="/` + bucketName + `/a&amp;b.txt"`, `>a&amp;b.txt<`}},
+		{"dir/", browser, true, []string{`href="/` + bucketName + `/"`, `>sub/<`, `href="/` + bucketName + `/dir/a.txt"`, `>a.txt<`}},
+		{"dir/sub/", browser, true, []string{`href="/` + bucketName + `/?prefix=dir%2F"`, `>b.txt<`}},
+		// Other clients are answered with the S3 listing.
+		{"", "aws-cli/1.11", false, []string{"<Key>dir/sub/b.txt</Key>"}},
+	}
+	for i, testCase := range testCases {
+		rec := listBucket(testCase.prefix, testCase.userAgent)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, http.StatusOK, rec.Code)
+		}
+		if isHTML := strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html"); isHTML != testCase.html {
+			t.Errorf("%s: Test %d: Expected HTML %v, got content type %s", instanceType, i+1, testCase.html, rec.Header().Get("Content-Type"))
+		}
+		body := rec.Body.String()
+		for _, s := range testCase.contains {
+			if !strings.Contains(body, s) {
+				t.Errorf("%s: Test %d: Expected response to contain %s, got %s", instanceType, i+1, s, body)
+			}
+		}
+	}
+
+	// Buckets without a directory index are answered with the S3 listing.
+	serverConfig.SetDirectoryIndex(directoryIndexConfig{Buckets: []string{"other-*"}})
+	if rec := listBucket("", browser); strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("%s: Expected S3 listing of bucket without a directory index, got %s", instanceType, rec.Body.String())
+	}
+}
@@ -0,0 +1,214 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// Magic numbers of the supported archive formats, tar has none.
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	zipEmpty  = []byte("PK\x05\x06")
+	gzipMagic = []byte("\x1f\x8b")
+)
+
+// spoolArchive - copies an uploaded archive of size bytes to a
+// temporary file, verifying its md5 and sha256 sums if not empty. Zip
+// archives need random access, and objects are only extracted from
+// archives known to be intact. The caller closes and removes the file.
+func spoolArchive(reader io.Reader, size int64, md5Hex, sha256Hex string) (*os.File, error) {
+	archive, err := ioutil.TempFile("", "minio-extract-")
+	if err != nil {
+		return nil, traceError(err)
+	}
+
+	md5Writer := md5.New()
+	var sha256Writer hash.Hash
+	writers := []io.Writer{archive, md5Writer}
+	if sha256Hex != "" {
+		sha256Writer = sha256.New()
+		writers = append(writers, sha256Writer)
+	}
+	_, err = io.CopyN(io.MultiWriter(writers...), reader, size)
+	if err == io.EOF {
+		err = IncompleteBody{}
+	}
+	if err == nil && md5Hex != "" {
+		if calculated := hex.EncodeToString(md5Writer.Sum(nil)); calculated != md5Hex {
+			err = BadDigest{ExpectedMD5: md5Hex, CalculatedMD5: calculated}
+		}
+	}
+	if err == nil && sha256Writer != nil && hex.EncodeToString(sha256Writer.Sum(nil)) != sha256Hex {
+		err = SHA256Mismatch{}
+	}
+	if err != nil {
+		archive.Close()
+		os.Remove(archive.Name())
+		return nil, traceError(err)
+	}
+	return archive, nil
+}
+
+// archiveReader - reports errors reading an archive as malformed
+// archives, instead of failures of the object layer.
+type archiveReader struct {
+	io.Reader
+}
+
+func (r archiveReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		err = errInvalidArchive
+	}
+	return n, err
+}
+
+// getArchiveObjectName - returns the object name of an archive entry
+// under prefix, empty for entries without a name. Relative components
+// are resolved within the archive, entries can not escape prefix.
+func getArchiveObjectName(prefix, name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return ""
+	}
+	return prefix + name
+}
+
+// getTarEntryMetadata - returns metadata of an object from the
+// extended attributes of its tar entry, as stored by `tar --xattrs`.
+// user.mime_type sets the content type, other user attributes are
+// saved as user metadata.
+func getTarEntryMetadata(header *tar.Header) map[string]string {
+	metadata := make(map[string]string)
+	for key, value := range header.Xattrs {
+		if !strings.HasPrefix(key, "user.") {
+			continue
+		}
+		if key == "user.mime_type" {
+			metadata["content-type"] = value
+			continue
+		}
+		metadata[http.CanonicalHeaderKey(userMetaPrefix+strings.TrimPrefix(key, "user."))] = value
+	}
+	return metadata
+}
+
+// extractArchive - creates an object under prefix for every regular
+// file of a tar, tar.gz or zip archive, calling onObject after each
+// of them under its namespace lock. Returns the number and total size of the created objects.
+// Objects created before an error are left in place.
+func extractArchive(objAPI ObjectLayer, archive io.ReaderAt, size int64, bucket, prefix string,
+	onObject func(ObjectInfo)) (count, total int64, err error) {
+	putObject := func(name string, size int64, reader io.Reader, metadata map[string]string) error {
+		object := getArchiveObjectName(prefix, name)
+		if object == "" {
+			return nil
+		}
+		if isMaxObjectSize(size) {
+			return traceError(errDataTooLarge)
+		}
+		if isMetadataTooLarge(metadata) {
+			return traceError(errMetadataTooLarge)
+		}
+		reader, err := globalContentTypePolicy.setContentType(bucket, object, size, metadata, archiveReader{reader})
+		if err != nil {
+			return traceError(err)
+		}
+
+		// Lock the object.
+		objectLock := globalNSMutex.NewNSLock(bucket, object)
+		objectLock.Lock()
+		defer objectLock.Unlock()
+		if err = checkObjectOverwrite(objAPI, bucket, object); err != nil {
+			return err
+		}
+		objInfo, err := objAPI.PutObject(bucket, object, size, reader, metadata, "")
+		if err != nil {
+			return err
+		}
+		count++
+		total += objInfo.Size
+		onObject(objInfo)
+		return nil
+	}
+
+	magic := make([]byte, 4)
+	n, _ := archive.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	if bytes.HasPrefix(magic, zipMagic) || bytes.HasPrefix(magic, zipEmpty) {
+		zr, zErr := zip.NewReader(archive, size)
+		if zErr != nil {
+			return 0, 0, traceError(errInvalidArchive)
+		}
+		for _, file := range zr.File {
+			if !file.FileInfo().Mode().IsRegular() {
+				continue
+			}
+			rc, oErr := file.Open()
+			if oErr != nil {
+				return count, total, traceError(errInvalidArchive)
+			}
+			err = putObject(file.Name, int64(file.UncompressedSize64), rc, make(map[string]string))
+			rc.Close()
+			if err != nil {
+				return count, total, err
+			}
+		}
+		return count, total, nil
+	}
+
+	var reader io.Reader = io.NewSectionReader(archive, 0, size)
+	if bytes.HasPrefix(magic, gzipMagic) {
+		gzr, gErr := gzip.NewReader(reader)
+		if gErr != nil {
+			return 0, 0, traceError(errInvalidArchive)
+		}
+		defer gzr.Close()
+		reader = gzr
+	}
+	tr := tar.NewReader(reader)
+	for {
+		header, tErr := tr.Next()
+		if tErr == io.EOF {
+			return count, total, nil
+		}
+		if tErr != nil {
+			return count, total, traceError(errInvalidArchive)
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if err = putObject(header.Name, header.Size, tr, getTarEntryMetadata(header)); err != nil {
+			return count, total, err
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// Minio extension header on DeleteBucket, removes a non-empty
+	// bucket along with all its objects when set to "true".
+	minioForceDeleteHeader = "X-Minio-Force-Delete"
+
+	// Admin audit operation of forced bucket deletes.
+	forceDeleteAuditOperation = "bucket.force-delete"
+)
+
+// isForceDeleteRequest - returns true if the client asked to remove a
+// bucket along with its objects.
+func isForceDeleteRequest(r *http.Request) bool {
+	return r.Header.Get(minioForceDeleteHeader) == "true"
+}
+
+// isBucketDeleteProtected - returns true if the bucket policy denies
+// s3:DeleteObject on any of the objects in the bucket, such objects
+// are meant to be retained and must not be removed by a forced delete.
+func isBucketDeleteProtected(bucket string) bool {
+	policy := globalBucketPolicies.GetBucketPolicy(bucket)
+	if policy == nil {
+		return false
+	}
+	for _, statement := range policy.Statements {
+		if statement.Effect == "Deny" && bucketPolicyActionMatch("s3:DeleteObject", statement) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBucketObjects - deletes all objects of a bucket, calling
+// onDelete with the name of each deleted object as it goes, so that
+// names are never collected. Objects are listed recursively in batches
+// of 1000 after the last one listed, each of them is deleted under its
+// namespace lock, which is held while onDelete is called. Noncurrent
+// versions of the objects are removed last.
+func deleteBucketObjects(objAPI ObjectLayer, bucket string, onDelete func(object string)) error {
+	marker := ""
+	for {
+		// Listing continues after the last listed object, entries
+		// which could not be deleted are never listed again.
+		lo, err := objAPI.ListObjects(bucket, "", marker, "", 1000)
+		if err != nil {
+			return err
+		}
+		for _, obj := range lo.Objects {
+			marker = obj.Name
+			objectLock := globalNSMutex.NewNSLock(bucket, obj.Name)
+			objectLock.Lock()
+			err = objAPI.DeleteObject(bucket, obj.Name)
+			if err == nil {
+				onDelete(obj.Name)
+			}
+			objectLock.Unlock()
+			if err != nil && !isErrObjectNotFound(err) {
+				return err
+			}
+		}
+		if !lo.IsTruncated || len(lo.Objects) == 0 {
+			// Versions archived by the deletes are removed too.
+			return purgeObjectVersions(objAPI, bucket)
+		}
+	}
+}
+
+// recordForceDelete - records a forced bucket delete in the admin
+// audit log, as it removes data no client has asked for by name.
+func recordForceDelete(objAPI ObjectLayer, r *http.Request, bucket string, status int) {
+	entry := adminAuditEntry{
+		Time:          time.Now().UTC(),
+		AccessKey:     getRequestAccessKeyV4(r),
+		RemoteHost:    r.RemoteAddr,
+		Operation:     forceDeleteAuditOperation,
+		Resource:      bucket,
+		PayloadSHA256: getSHA256Hash(nil),
+		Status:        status,
+	}
+	if status < http.StatusMultipleChoices {
+		entry.Approver = getRequestApprover(r)
+	}
+	errorIf(appendAdminAuditEntry(objAPI, entry), "Unable to record admin audit entry for %s.", entry.Operation)
+}
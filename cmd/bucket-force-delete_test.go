@@ -0,0 +1,79 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// stuckObjects - lists objects of which those sorting before stuck
+// are never deleted.
+type stuckObjects struct {
+	ObjectLayer
+	objects []string
+	stuck   string
+}
+
+func (l *stuckObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	if bucket == minioMetaBucket {
+		return result, nil
+	}
+	i := sort.SearchStrings(l.objects, marker)
+	if i < len(l.objects) && l.objects[i] == marker {
+		i++
+	}
+	for ; i < len(l.objects) && len(result.Objects) < maxKeys; i++ {
+		result.Objects = append(result.Objects, ObjectInfo{Bucket: bucket, Name: l.objects[i]})
+	}
+	result.IsTruncated = i < len(l.objects)
+	return result, nil
+}
+
+func (l *stuckObjects) DeleteObject(bucket, object string) error {
+	if object < l.stuck {
+		return traceError(ObjectNotFound{Bucket: bucket, Object: object})
+	}
+	i := sort.SearchStrings(l.objects, object)
+	l.objects = append(l.objects[:i], l.objects[i+1:]...)
+	return nil
+}
+
+// Tests that deleting the objects of a bucket ends even if more objects
+// than a listing batch cannot be found to be deleted.
+func TestDeleteBucketObjectsStuck(t *testing.T) {
+	initNSLock(false)
+
+	objAPI := &stuckObjects{stuck: "object-1100"}
+	for i := 0; i < 2500; i++ {
+		objAPI.objects = append(objAPI.objects, fmt.Sprintf("object-%04d", i))
+	}
+
+	deleted := 0
+	if err := deleteBucketObjects(objAPI, "bucket", func(object string) {
+		if object < objAPI.stuck {
+			t.Errorf("Expected no delete of %s", object)
+		}
+		deleted++
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1400 || len(objAPI.objects) != 1100 {
+		t.Fatalf("Expected 1400 objects deleted and 1100 left, got %d and %d", deleted, len(objAPI.objects))
+	}
+}
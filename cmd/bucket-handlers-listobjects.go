@@ -65,12 +65,12 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -87,7 +87,7 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 	// Validate the query params before beginning to serve the request.
 	// fetch-owner is not validated since it is a boolean
 	if s3Error := validateListObjectsArgs(prefix, marker, delimiter, maxKeys); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 	// Inititate a list objects operation based on the input params.
@@ -96,7 +96,7 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 	listObjectsInfo, err := objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
 	if err != nil {
 		errorIf(err, "Unable to list objects.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -118,21 +118,28 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	// Extract all the litsObjectsV1 query params to their native values.
 	prefix, marker, delimiter, maxKeys, _ := getListObjectsV1Args(r.URL.Query())
 
+	// Browsers are shown the directory index of buckets having one,
+	// listed one level at a time.
+	directoryIndex := isDirectoryIndexRequest(r, bucket)
+	if directoryIndex {
+		delimiter = slashSeparator
+	}
+
 	// Validate all the query params before beginning to serve the request.
 	if s3Error := validateListObjectsArgs(prefix, marker, delimiter, maxKeys); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -142,7 +149,11 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	listObjectsInfo, err := objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
 	if err != nil {
 		errorIf(err, "Unable to list objects.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+	if directoryIndex {
+		writeDirectoryIndex(w, r, bucket, prefix, listObjectsInfo)
 		return
 	}
 	response := generateListObjectsV1Response(bucket, prefix, marker, delimiter, maxKeys, listObjectsInfo)
@@ -150,3 +161,63 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	// Write success response.
 	writeSuccessResponseXML(w, encodeResponse(response))
 }
+
+// getPrefixUsage - returns total size and number of objects under
+// prefix, objects are listed recursively in batches of 1000.
+func getPrefixUsage(objectAPI ObjectLayer, bucket, prefix string) (size int64, count int64, err error) {
+	marker := ""
+	for {
+		lo, err := objectAPI.ListObjects(bucket, prefix, marker, "", 1000)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, obj := range lo.Objects {
+			size += obj.Size
+			count++
+		}
+		if !lo.IsTruncated {
+			return size, count, nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// GetBucketUsageHandler - GET Bucket usage (minio extension)
+// --------------------------
+// This implementation of the GET operation returns the total size
+// and number of objects under a prefix, so that clients do not have
+// to list and sum all the objects themselves.
+func (api objectAPIHandlers) GetBucketUsageHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Usage reveals no more than listing the prefix would.
+	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	size, count, err := getPrefixUsage(objectAPI, bucket, prefix)
+	if err != nil {
+		errorIf(err, "Unable to compute usage of %s/%s.", bucket, prefix)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	response := BucketUsageResponse{
+		Bucket:       bucket,
+		Prefix:       prefix,
+		Size:         size,
+		ObjectsCount: count,
+	}
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
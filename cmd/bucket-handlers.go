@@ -18,12 +18,15 @@ package cmd
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -59,7 +62,16 @@ func enforceBucketPolicy(bucket, action, resource, referer string, queryParams u
 	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
 	arn := bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(resource, "/"), "/")
 
-	// Get conditions for policy verification.
+	// Validate action, resource and conditions with current policy statements.
+	if !bucketPolicyEvalStatements(action, arn, getAnonymousConditionKeyMap(referer, queryParams), policy.Statements) {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// getAnonymousConditionKeyMap - returns conditions of an anonymous
+// request for bucket policy verification.
+func getAnonymousConditionKeyMap(referer string, queryParams url.Values) map[string]set.StringSet {
 	conditionKeyMap := make(map[string]set.StringSet)
 	for queryParam := range queryParams {
 		conditionKeyMap[queryParam] = set.CreateStringSet(queryParams.Get(queryParam))
@@ -70,11 +82,13 @@ func enforceBucketPolicy(bucket, action, resource, referer string, queryParams u
 		conditionKeyMap["referer"] = set.CreateStringSet(referer)
 	}
 
-	// Validate action, resource and conditions with current policy statements.
-	if !bucketPolicyEvalStatements(action, arn, conditionKeyMap, policy.Statements) {
-		return ErrAccessDenied
+	// Policy variables are never resolved for anonymous requests,
+	// make sure they cannot be spoofed through query params.
+	for variable := range supportedPolicyVariables {
+		delete(conditionKeyMap, variable)
 	}
-	return ErrNone
+
+	return conditionKeyMap
 }
 
 // Check if the action is allowed on the bucket/prefix.
@@ -101,7 +115,7 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
@@ -111,13 +125,13 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		s3Error = checkRequestAuthType(r, "", "s3:GetBucketLocation", serverConfig.GetRegion())
 	}
 	if s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
 		errorIf(err, "Unable to fetch bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -149,24 +163,24 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucketMultipartUploads", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	prefix, keyMarker, uploadIDMarker, delimiter, maxUploads, _ := getBucketMultipartResources(r.URL.Query())
 	if maxUploads < 0 {
-		writeErrorResponse(w, ErrInvalidMaxUploads, r.URL)
+		writeErrorResponse(w, ErrInvalidMaxUploads, r)
 		return
 	}
 	if keyMarker != "" {
 		// Marker not common with prefix is not implemented.
 		if !hasPrefix(keyMarker, prefix) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
+			writeErrorResponse(w, ErrNotImplemented, r)
 			return
 		}
 	}
@@ -174,7 +188,7 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
 		errorIf(err, "Unable to list multipart uploads.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	// generate response
@@ -192,28 +206,48 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	// ListBuckets does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
-	if s3Error == ErrInvalidRegion {
-		// Clients like boto3 send listBuckets() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
-	}
-	if s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
-		return
+	// Anonymous requests are checked against the policy of each bucket below.
+	anonymous := getRequestAuthType(r) == authTypeAnonymous
+	if !anonymous {
+		// ListBuckets does not have any bucket action.
+		s3Error := checkOwnerRequestAuthType(r, "s3:ListAllMyBuckets", globalMinioDefaultRegion)
+		if s3Error == ErrInvalidRegion {
+			// Clients like boto3 send listBuckets() call signed with region that is configured.
+			s3Error = checkOwnerRequestAuthType(r, "s3:ListAllMyBuckets", serverConfig.GetRegion())
+		}
+		if s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r)
+			return
+		}
 	}
 	// Invoke the list buckets.
 	bucketsInfo, err := objectAPI.ListBuckets()
 	if err != nil {
 		errorIf(err, "Unable to list buckets.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
+	if anonymous {
+		// List only buckets whose policy allows s3:ListAllMyBuckets.
+		var allowedBuckets []BucketInfo
+		for _, bucketInfo := range bucketsInfo {
+			if enforceBucketPolicy(bucketInfo.Name, "s3:ListAllMyBuckets", "/"+bucketInfo.Name,
+				r.Referer(), r.URL.Query()) == ErrNone {
+				allowedBuckets = append(allowedBuckets, bucketInfo)
+			}
+		}
+		if len(allowedBuckets) == 0 {
+			writeErrorResponse(w, ErrAccessDenied, r)
+			return
+		}
+		bucketsInfo = allowedBuckets
+	}
+
 	// Generate response.
 	response := generateListBucketsResponse(bucketsInfo)
 	encodedSuccessResponse := encodeResponse(response)
@@ -229,26 +263,26 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	// Content-Length is required and should be non-zero
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/multiobjectdeleteapi.html
 	if r.ContentLength <= 0 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 
 	// Content-Md5 is requied should be set
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/multiobjectdeleteapi.html
 	if _, ok := r.Header["Content-Md5"]; !ok {
-		writeErrorResponse(w, ErrMissingContentMD5, r.URL)
+		writeErrorResponse(w, ErrMissingContentMD5, r)
 		return
 	}
 
@@ -258,7 +292,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	// Read incoming body XML bytes.
 	if _, err := io.ReadFull(r.Body, deleteXMLBytes); err != nil {
 		errorIf(err, "Unable to read HTTP body.")
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		return
 	}
 
@@ -266,12 +300,13 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	deleteObjects := &DeleteObjectsRequest{}
 	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
 		errorIf(err, "Unable to unmarshal delete objects request XML.")
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		writeErrorResponse(w, ErrMalformedXML, r)
 		return
 	}
 
 	var wg = &sync.WaitGroup{} // Allocate a new wait group.
 	var dErrs = make([]error, len(deleteObjects.Objects))
+	var sequencers = make([]string, len(deleteObjects.Objects))
 
 	// Delete all requested objects in parallel.
 	for index, object := range deleteObjects.Objects {
@@ -286,24 +321,28 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			if dErr != nil {
 				dErrs[i] = dErr
 			}
+			sequencers[i] = getEventSequencer(bucket, obj.ObjectName)
 		}(index, object)
 	}
 	wg.Wait()
 
 	// Collect deleted objects and errors if any.
 	var deletedObjects []ObjectIdentifier
+	var deletedSequencers []string
 	var deleteErrors []DeleteError
 	for index, err := range dErrs {
 		object := deleteObjects.Objects[index]
 		// Success deleted objects are collected separately.
 		if err == nil {
 			deletedObjects = append(deletedObjects, object)
+			deletedSequencers = append(deletedSequencers, sequencers[index])
 			continue
 		}
 		if _, ok := errorCause(err).(ObjectNotFound); ok {
 			// If the object is not found it should be
 			// accounted as deleted as per S3 spec.
 			deletedObjects = append(deletedObjects, object)
+			deletedSequencers = append(deletedSequencers, sequencers[index])
 			continue
 		}
 		errorIf(err, "Unable to delete object. %s", object.ObjectName)
@@ -323,7 +362,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 
 	// Notify deleted event for objects.
-	for _, dobj := range deletedObjects {
+	for i, dobj := range deletedObjects {
 		eventNotify(eventData{
 			Type:   ObjectRemovedDelete,
 			Bucket: bucket,
@@ -333,6 +372,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			ReqParams: map[string]string{
 				"sourceIPAddress": r.RemoteAddr,
 			},
+			Sequencer: deletedSequencers[i],
 		})
 	}
 }
@@ -343,18 +383,18 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// PutBucket does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
+	s3Error := checkOwnerRequestAuthType(r, "s3:CreateBucket", globalMinioDefaultRegion)
 	if s3Error == ErrInvalidRegion {
 		// Clients like boto3 send putBucket() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = checkOwnerRequestAuthType(r, "s3:CreateBucket", serverConfig.GetRegion())
 	}
 	if s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -364,7 +404,7 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	// Validate if incoming location constraint is valid, reject
 	// requests which do not follow valid region requirements.
 	if s3Error := isValidLocationConstraint(r); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -372,11 +412,10 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
-	// Proceed to creating a bucket.
-	err := objectAPI.MakeBucket(bucket)
-	if err != nil {
-		errorIf(err, "Unable to create a bucket.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+	// Proceed to creating a bucket, enforcing bucket creation
+	// restrictions, if configured.
+	if err := globalBucketCreationPolicy.makeBucket(bucket, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -393,14 +432,14 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// Require Content-Length to be set in the request
 	size := r.ContentLength
 	if size < 0 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 
@@ -409,7 +448,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	reader, err := r.MultipartReader()
 	if err != nil {
 		errorIf(err, "Unable to initialize multipart reader.")
-		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r)
 		return
 	}
 
@@ -417,7 +456,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	form, err := reader.ReadForm(maxFormMemory)
 	if err != nil {
 		errorIf(err, "Unable to initialize multipart reader.")
-		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r)
 		return
 	}
 
@@ -428,13 +467,13 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	fileBody, fileName, fileSize, formValues, err := extractPostPolicyFormValues(form)
 	if err != nil {
 		errorIf(err, "Unable to parse form values.")
-		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r)
 		return
 	}
 
 	// Check if file is provided, error out otherwise.
 	if fileBody == nil {
-		writeErrorResponse(w, ErrPOSTFileRequired, r.URL)
+		writeErrorResponse(w, ErrPOSTFileRequired, r)
 		return
 	}
 
@@ -454,47 +493,63 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// Verify policy signature.
 	apiErr := doesPolicySignatureMatch(formValues)
 	if apiErr != ErrNone {
-		writeErrorResponse(w, apiErr, r.URL)
+		writeErrorResponse(w, apiErr, r)
+		return
+	}
+
+	// IAM users must be allowed to put the object.
+	if apiErr = checkIAMPolicy(r, getPolicyAccessKey(formValues), "s3:PutObject", path.Join(bucket, object)); apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r)
 		return
 	}
 
 	policyBytes, err := base64.StdEncoding.DecodeString(formValues["Policy"])
 	if err != nil {
-		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r)
 		return
 	}
 
 	postPolicyForm, err := parsePostPolicyForm(string(policyBytes))
 	if err != nil {
-		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r)
 		return
 	}
 
 	// Make sure formValues adhere to policy restrictions.
 	if apiErr = checkPostPolicy(formValues, postPolicyForm); apiErr != ErrNone {
-		writeErrorResponse(w, apiErr, r.URL)
+		writeErrorResponse(w, apiErr, r)
 		return
 	}
 
 	// Ensure that the object size is within expected range, also the file size
-	// should not exceed the maximum single Put size (5 GiB)
+	// should not exceed the maximum single Put size (5 GiB by default)
 	lengthRange := postPolicyForm.Conditions.ContentLengthRange
 	if lengthRange.Valid {
 		if fileSize < lengthRange.Min {
 			errorIf(err, "Unable to create object.")
-			writeErrorResponse(w, toAPIErrorCode(errDataTooSmall), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(errDataTooSmall), r)
 			return
 		}
 
-		if fileSize > lengthRange.Max || fileSize > maxObjectSize {
+		if fileSize > lengthRange.Max || isMaxObjectSize(fileSize) {
 			errorIf(err, "Unable to create object.")
-			writeErrorResponse(w, toAPIErrorCode(errDataTooLarge), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(errDataTooLarge), r)
 			return
 		}
 	}
 
 	// Extract metadata to be saved from received Form.
 	metadata := extractMetadataFromForm(formValues)
+	if isMetadataTooLarge(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r)
+		return
+	}
+	fileReader, err := globalContentTypePolicy.setContentType(bucket, object, fileSize, metadata, fileBody)
+	if err != nil {
+		errorIf(err, "Unable to read object data.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
 
 	sha256sum := ""
 
@@ -502,10 +557,15 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	objInfo, err := objectAPI.PutObject(bucket, object, fileSize, fileBody, metadata, sha256sum)
+	if err = checkObjectOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	objInfo, err := objectAPI.PutObject(bucket, object, fileSize, fileReader, metadata, sha256sum)
 	if err != nil {
 		errorIf(err, "Unable to create object.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
@@ -592,13 +652,13 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// DeleteBucket does not have any bucket action.
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:DeleteBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -609,13 +669,61 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
+	// The approval of a force delete is used up only if the bucket
+	// is deleted.
+	approvalDone, approved := func(bool) {}, false
+	defer func() { approvalDone(approved) }()
+
+	if isForceDeleteRequest(r) {
+		// Objects protected by the bucket policy are never removed.
+		if isBucketDeleteProtected(bucket) {
+			recordForceDelete(objectAPI, r, bucket, http.StatusForbidden)
+			writeErrorResponse(w, ErrAccessDenied, r)
+			return
+		}
+		var s3Error APIErrorCode
+		if approvalDone, s3Error = checkApproval(objectAPI, r, forceDeleteAuditOperation, bucket); s3Error != ErrNone {
+			recordForceDelete(objectAPI, r, bucket, getAPIError(s3Error).HTTPStatusCode)
+			writeErrorResponse(w, s3Error, r)
+			return
+		}
+		err := deleteBucketObjects(objectAPI, bucket, func(object string) {
+			eventNotify(eventData{
+				Type:   ObjectRemovedDelete,
+				Bucket: bucket,
+				ObjInfo: ObjectInfo{
+					Name: object,
+				},
+				ReqParams: map[string]string{
+					"sourceIPAddress": r.RemoteAddr,
+				},
+			})
+		})
+		if err != nil {
+			errorIf(err, "Unable to delete objects of bucket %s.", bucket)
+			apiErr := toAPIErrorCode(err)
+			recordForceDelete(objectAPI, r, bucket, getAPIError(apiErr).HTTPStatusCode)
+			writeErrorResponse(w, apiErr, r)
+			return
+		}
+	}
+
 	// Attempt to delete bucket.
 	if err := objectAPI.DeleteBucket(bucket); err != nil {
 		errorIf(err, "Unable to delete a bucket.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		apiErr := toAPIErrorCode(err)
+		if isForceDeleteRequest(r) {
+			recordForceDelete(objectAPI, r, bucket, getAPIError(apiErr).HTTPStatusCode)
+		}
+		writeErrorResponse(w, apiErr, r)
 		return
 	}
 
+	approved = true
+	if isForceDeleteRequest(r) {
+		recordForceDelete(objectAPI, r, bucket, http.StatusNoContent)
+	}
+
 	// Delete bucket access policy, if present - ignore any errors.
 	_ = removeBucketPolicy(bucket, objectAPI)
 
@@ -625,6 +733,188 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	// Delete listener config, if present - ignore any errors.
 	_ = removeListenerConfig(bucket, objectAPI)
 
+	// Delete versioning config, if present - ignore any errors.
+	_ = removeBucketVersioning(bucket, objectAPI)
+
+	// Delete lifecycle config, if present - ignore any errors.
+	_ = removeBucketLifecycle(bucket, objectAPI)
+
 	// Write success response.
 	writeSuccessNoContent(w)
 }
+
+// GetBucketTarHandler - GET Bucket tar (minio extension)
+// -----------------------
+// This implementation of the GET operation streams all objects under a
+// prefix as a tar archive, so that clients fetching many small objects
+// do not pay for one request per object.
+func (api objectAPIHandlers) GetBucketTarHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		errorIf(err, "Unable to fetch bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Anonymous requests get only the objects the bucket policy
+	// allows them to read.
+	anonymous := getRequestAuthType(r) == authTypeAnonymous
+	canRead := func(object string) bool {
+		return !anonymous || isBucketActionAllowed("s3:GetObject", bucket, object)
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.WriteHeader(http.StatusOK)
+
+	// Errors past this point cut the archive short, the status has
+	// already been sent.
+	prefix := r.URL.Query().Get("prefix")
+	err := writeBucketTar(objectAPI, w, bucket, prefix, canRead)
+	errorIf(err, "Unable to write tar of %s/%s to client.", bucket, prefix)
+}
+
+// PutBucketExtractHandler - PUT Bucket extract (minio extension)
+// -----------------------
+// This implementation of the PUT operation creates an object under a
+// prefix for every file of an uploaded tar, tar.gz or zip archive, so
+// that clients uploading many small objects do not pay for one request
+// per object.
+func (api objectAPIHandlers) PutBucketExtractHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	// Get Content-Md5 sent by client and verify if valid
+	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
+	if err != nil {
+		errorIf(err, "Unable to validate content-md5 format.")
+		writeErrorResponse(w, ErrInvalidDigest, r)
+		return
+	}
+
+	/// if Content-Length is unknown/missing, deny the request
+	size := r.ContentLength
+	rAuthType := getRequestAuthType(r)
+	if rAuthType == authTypeStreamingSigned {
+		sizeStr := r.Header.Get("x-amz-decoded-content-length")
+		size, err = strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value %s", sizeStr)
+			writeErrorResponse(w, toAPIErrorCode(err), r)
+			return
+		}
+	}
+	if size == -1 {
+		writeErrorResponse(w, ErrMissingContentLength, r)
+		return
+	}
+
+	/// maximum Upload size for archives in a single operation
+	if isMaxObjectSize(size) {
+		writeErrorResponse(w, ErrEntityTooLarge, r)
+		return
+	}
+
+	var reader io.Reader = r.Body
+	sha256sum := ""
+	switch rAuthType {
+	default:
+		// For all unknown auth types return error. Anonymous
+		// requests are denied as well, the object names are not
+		// known before the archive is spooled.
+		writeErrorResponse(w, ErrAccessDenied, r)
+		return
+	case authTypeStreamingSigned:
+		// Initialize stream signature verifier.
+		var s3Error APIErrorCode
+		reader, s3Error = newSignV4ChunkedReader(r)
+		if s3Error != ErrNone {
+			errorIf(errSignatureMismatch, "%s", dumpRequest(r))
+			writeErrorResponse(w, s3Error, r)
+			return
+		}
+	case authTypeSignedV2, authTypePresignedV2:
+		if s3Error := isReqAuthenticatedV2(r); s3Error != ErrNone {
+			errorIf(errSignatureMismatch, "%s", dumpRequest(r))
+			writeErrorResponse(w, s3Error, r)
+			return
+		}
+	case authTypePresigned, authTypeSigned:
+		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
+			errorIf(errSignatureMismatch, "%s", dumpRequest(r))
+			writeErrorResponse(w, s3Error, r)
+			return
+		}
+		if !skipContentSha256Cksum(r) {
+			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
+		}
+	}
+
+	// IAM users must be allowed to put any object in the bucket, the
+	// object names are not known yet.
+	if s3Error := checkIAMPolicy(r, getRequestAccessKey(r), "s3:PutObject", path.Join(bucket, "*")); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	if _, err = objectAPI.GetBucketInfo(bucket); err != nil {
+		errorIf(err, "Unable to fetch bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	archive, err := spoolArchive(reader, size, hex.EncodeToString(md5Bytes), sha256sum)
+	if err != nil {
+		errorIf(err, "Unable to receive archive for %s.", bucket)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	prefix := r.URL.Query().Get("prefix")
+	count, total, err := extractArchive(objectAPI, archive, size, bucket, prefix, func(objInfo ObjectInfo) {
+		// Notify object created event.
+		eventNotify(eventData{
+			Type:    ObjectCreatedPut,
+			Bucket:  bucket,
+			ObjInfo: objInfo,
+			ReqParams: map[string]string{
+				"sourceIPAddress": r.RemoteAddr,
+			},
+		})
+	})
+	if err != nil {
+		errorIf(err, "Unable to extract archive to %s/%s.", bucket, prefix)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	response := ExtractArchiveResponse{
+		Bucket:       bucket,
+		Prefix:       prefix,
+		Size:         total,
+		ObjectsCount: count,
+	}
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
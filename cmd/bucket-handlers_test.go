@@ -17,13 +17,21 @@
 package cmd
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
 )
 
 // Wrapper for calling GetBucketPolicy HTTP handler tests for both XL multiple disks and single node setup.
@@ -144,7 +152,7 @@ func testGetBucketLocationHandler(obj ObjectLayer, instanceType, bucketName stri
 	}
 
 	// Test for Anonymous/unsigned http request.
-	// ListBucketsHandler doesn't support bucket policies, setting the policies shouldn't make any difference.
+	// A write only policy doesn't allow s3:GetBucketLocation, setting it shouldn't make any difference.
 	anonReq, err := newTestRequest("GET", getBucketLocationURL("", bucketName), 0, nil)
 	if err != nil {
 		t.Fatalf("Minio %s: Failed to create an anonymous request.", instanceType)
@@ -583,7 +591,7 @@ func testListBucketsHandler(obj ObjectLayer, instanceType, bucketName string, ap
 	}
 
 	// Test for Anonymous/unsigned http request.
-	// ListBucketsHandler doesn't support bucket policies, setting the policies shouldn't make a difference.
+	// A write only policy doesn't allow s3:ListAllMyBuckets, setting it shouldn't make a difference.
 	anonReq, err := newTestRequest("GET", getListBucketURL(""), 0, nil)
 
 	if err != nil {
@@ -610,6 +618,48 @@ func testListBucketsHandler(obj ObjectLayer, instanceType, bucketName string, ap
 	ExecObjectLayerAPINilTest(t, "", "", instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling anonymous ListBuckets tests for both XL multiple disks and single node setup.
+func TestListBucketsHandlerAnonymous(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListBucketsHandlerAnonymous, []string{"ListBuckets"})
+}
+
+// testListBucketsHandlerAnonymous - Tests that anonymous requests list
+// the buckets whose policy allows s3:ListAllMyBuckets.
+func testListBucketsHandlerAnonymous(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	if err := obj.MakeBucket("private-bucket"); err != nil {
+		t.Fatalf("%s: Failed to create bucket: <ERROR> %v", instanceType, err)
+	}
+
+	statement := getReadOnlyBucketStatement(bucketName, "")
+	statement.Actions = set.CreateStringSet("s3:ListAllMyBuckets")
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{statement},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	rec := httptest.NewRecorder()
+	req, err := newTestRequest("GET", getListBucketURL(""), 0, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	var response ListBucketsResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s: Unable to parse response: <ERROR> %v", instanceType, err)
+	}
+	buckets := response.Buckets.Buckets
+	if len(buckets) != 1 || buckets[0].Name != bucketName {
+		t.Errorf("%s: Expected only bucket `%s` to be listed, but instead found %v", instanceType, bucketName, buckets)
+	}
+}
+
 // Wrapper for calling DeleteMultipleObjects HTTP handler tests for both XL multiple disks and single node setup.
 func TestAPIDeleteMultipleObjectsHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testAPIDeleteMultipleObjectsHandler, []string{"DeleteMultipleObjects"})
@@ -771,3 +821,476 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 	// `ExecObjectLayerAPINilTest` manages the operation.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Wrapper for calling PutBucket HTTP handler tests for both XL multiple disks and single node setup.
+func TestPutBucketHandlerCreationPolicy(t *testing.T) {
+	ExecObjectLayerAPITest(t, testPutBucketHandlerCreationPolicy, []string{"PutBucket"})
+}
+
+func testPutBucketHandlerCreationPolicy(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	// One bucket already exists, allow one more bucket prefixed with "tenant-".
+	policy, err := newBucketCreationPolicy(bucketCreationConfig{
+		MaxBuckets:   2,
+		NamePrefixes: []string{"tenant-"},
+	})
+	if err != nil {
+		t.Fatalf("%s: Failed to create bucket creation policy - %v", instanceType, err)
+	}
+	globalBucketCreationPolicy = policy
+	defer func() { globalBucketCreationPolicy = nil }()
+
+	testCases := []struct {
+		bucketName         string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Name without an allowed prefix.
+		{"other-bucket", http.StatusForbidden},
+		// Test case - 2.
+		// Allowed name within the bucket limit.
+		{"tenant-bucket1", http.StatusOK},
+		// Test case - 3.
+		// Allowed name beyond the bucket limit.
+		{"tenant-bucket2", http.StatusBadRequest},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getMakeBucketURL("", testCase.bucketName), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for PutBucket: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+}
+
+// isBucketNotFoundErr - returns true if err is BucketNotFound.
+func isBucketNotFoundErr(err error) bool {
+	_, ok := errorCause(err).(BucketNotFound)
+	return ok
+}
+
+// Wrapper for calling DeleteBucket HTTP handler tests with force delete for both XL multiple disks and single node setup.
+func TestDeleteBucketHandlerForceDelete(t *testing.T) {
+	ExecObjectLayerAPITest(t, testDeleteBucketHandlerForceDelete, []string{"DeleteBucket"})
+}
+
+func testDeleteBucketHandlerForceDelete(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	initBucketPolicies(obj)
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatal("Notifier initialization failed.")
+	}
+
+	contentBytes := []byte("hello")
+	for _, objectName := range []string{"a", "dir/b", "dir/sub/c"} {
+		if _, err := obj.PutObject(bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
+			make(map[string]string), ""); err != nil {
+			t.Fatalf("%s: Failed to upload object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+	}
+
+	// Deny s3:DeleteObject on the bucket to protect its objects.
+	statement := getReadWriteObjectStatement(bucketName, "")
+	statement.Effect = "Deny"
+	statement.Actions = set.CreateStringSet("s3:DeleteObject")
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{statement},
+	}
+
+	testCases := []struct {
+		forceDelete        bool
+		protected          bool
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Non-empty bucket without force delete.
+		{false, false, http.StatusConflict},
+		// Test case - 2.
+		// Force delete of a bucket protected by its policy.
+		{true, true, http.StatusForbidden},
+		// Test case - 3.
+		// Force delete removes the objects and the bucket.
+		{true, false, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.protected {
+			globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+		} else {
+			globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("DELETE", getDeleteBucketURL("", bucketName), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for DeleteBucket: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.forceDelete {
+			req.Header.Set(minioForceDeleteHeader, "true")
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	if _, err := obj.GetBucketInfo(bucketName); !isBucketNotFoundErr(err) {
+		t.Fatalf("%s: Expected bucket to be deleted, got %v", instanceType, err)
+	}
+
+	// Both refused and successful forced deletes are audited.
+	auditLog, err := getAdminAuditLog(obj, time.Now())
+	entries := auditLog.Entries
+	if err != nil {
+		t.Fatalf("%s: Failed to read admin audit log: <ERROR> %v", instanceType, err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("%s: Expected 2 audit entries, found %d", instanceType, len(entries))
+	}
+	for i, status := range []int{http.StatusForbidden, http.StatusNoContent} {
+		entry := entries[i]
+		if entry.Operation != forceDeleteAuditOperation || entry.Resource != bucketName || entry.Status != status {
+			t.Errorf("%s: Unexpected audit entry %#v", instanceType, entry)
+		}
+	}
+	if auditLog.BrokenAt != -1 {
+		t.Errorf("%s: Expected intact audit log, broken at %d", instanceType, auditLog.BrokenAt)
+	}
+}
+
+// Wrapper for calling GetBucketUsage HTTP handler tests for both XL multiple disks and single node setup.
+func TestGetBucketUsageHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testGetBucketUsageHandler, []string{"GetBucketUsage"})
+}
+
+func testGetBucketUsageHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objects := map[string]int{
+		"a":               1,
+		"photos/a.jpg":    10,
+		"photos/b.jpg":    20,
+		"photos/2017/c":   30,
+		"photosarchive/d": 40,
+	}
+	for objectName, size := range objects {
+		data := bytes.Repeat([]byte("a"), size)
+		if _, err := obj.PutObject(bucketName, objectName, int64(size), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: Failed to upload object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+	}
+
+	testCases := []struct {
+		bucketName         string
+		prefix             string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+		expectedSize       int64
+		expectedCount      int64
+	}{
+		// Test case - 1.
+		// Whole bucket.
+		{bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK, 101, 5},
+		// Test case - 2.
+		// Prefix matches names, not only directories.
+		{bucketName, "photos", credentials.AccessKey, credentials.SecretKey, http.StatusOK, 100, 4},
+		// Test case - 3.
+		// Directory prefix.
+		{bucketName, "photos/", credentials.AccessKey, credentials.SecretKey, http.StatusOK, 60, 3},
+		// Test case - 4.
+		// Prefix without objects.
+		{bucketName, "videos/", credentials.AccessKey, credentials.SecretKey, http.StatusOK, 0, 0},
+		// Test case - 5.
+		// Non-existent bucket.
+		{"abcd", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound, 0, 0},
+		// Test case - 6.
+		// Anonymous request without a bucket policy.
+		{bucketName, "", "", "", http.StatusForbidden, 0, 0},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketUsageURL("", testCase.bucketName, testCase.prefix),
+			0, nil, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for GetBucketUsage: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var response BucketUsageResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if response.Prefix != testCase.prefix || response.Size != testCase.expectedSize || response.ObjectsCount != testCase.expectedCount {
+			t.Errorf("Test %d: %s: Expected size %d and %d objects under `%s`, but instead found %#v", i+1, instanceType,
+				testCase.expectedSize, testCase.expectedCount, testCase.prefix, response)
+		}
+	}
+}
+
+// Wrapper for calling GetBucketTar HTTP handler tests for both XL multiple disks and single node setup.
+func TestGetBucketTarHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testGetBucketTarHandler, []string{"GetBucketTar"})
+}
+
+func testGetBucketTarHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	initBucketPolicies(obj)
+
+	objects := map[string]string{
+		"a":               "a",
+		"photos/a.jpg":    "photo a",
+		"photos/b.jpg":    "",
+		"photos/2017/c":   "photo c",
+		"photosarchive/d": "d",
+	}
+	for objectName, data := range objects {
+		if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader([]byte(data)), nil, ""); err != nil {
+			t.Fatalf("%s: Failed to upload object %s: <ERROR> %v", instanceType, objectName, err)
+		}
+	}
+
+	// Anonymous requests may read only photos/2017/.
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: getReadOnlyStatement(bucketName, "photos/2017/"),
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	testCases := []struct {
+		bucketName         string
+		prefix             string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+		expectedObjects    []string
+	}{
+		// Test case - 1.
+		// Whole bucket.
+		{bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK,
+			[]string{"a", "photos/2017/c", "photos/a.jpg", "photos/b.jpg", "photosarchive/d"}},
+		// Test case - 2.
+		// Directory prefix.
+		{bucketName, "photos/", credentials.AccessKey, credentials.SecretKey, http.StatusOK,
+			[]string{"photos/2017/c", "photos/a.jpg", "photos/b.jpg"}},
+		// Test case - 3.
+		// Prefix without objects.
+		{bucketName, "videos/", credentials.AccessKey, credentials.SecretKey, http.StatusOK, nil},
+		// Test case - 4.
+		// Non-existent bucket.
+		{"abcd", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound, nil},
+		// Test case - 5.
+		// Anonymous requests get only the objects they may read.
+		{bucketName, "photos/", "", "", http.StatusOK, []string{"photos/2017/c"}},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketTarURL("", testCase.bucketName, testCase.prefix),
+			0, nil, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for GetBucketTar: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		if contentType := rec.Header().Get("Content-Type"); contentType != "application/x-tar" {
+			t.Fatalf("Test %d: %s: Expected content type `application/x-tar`, but instead found `%s`", i+1, instanceType, contentType)
+		}
+
+		var objectNames []string
+		tr := tar.NewReader(rec.Body)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Test %d: %s: Unable to read tar: <ERROR> %v", i+1, instanceType, err)
+			}
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("Test %d: %s: Unable to read tar entry %s: <ERROR> %v", i+1, instanceType, header.Name, err)
+			}
+			if string(data) != objects[header.Name] {
+				t.Errorf("Test %d: %s: Expected `%s` in %s, but instead found `%s`", i+1, instanceType, objects[header.Name], header.Name, data)
+			}
+			objectNames = append(objectNames, header.Name)
+		}
+		if strings.Join(objectNames, ",") != strings.Join(testCase.expectedObjects, ",") {
+			t.Errorf("Test %d: %s: Expected objects %v, but instead found %v", i+1, instanceType, testCase.expectedObjects, objectNames)
+		}
+	}
+}
+
+// Returns a tar archive of files, with extended attributes xattrs on each
+// of them, gzip compressed if compress is set.
+func newTestTar(t *testing.T, files map[string]string, xattrs map[string]string, compress bool) []byte {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gzw *gzip.Writer
+	if compress {
+		gzw = gzip.NewWriter(&buf)
+		w = gzw
+	}
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(&tar.Header{Name: "dir/", Mode: 0755, Typeflag: tar.TypeDir}); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Xattrs: xattrs}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gzw != nil {
+		if err := gzw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// Returns a zip archive of files.
+func newTestZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = fw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Wrapper for calling PutBucketExtract HTTP handler tests for both XL multiple disks and single node setup.
+func TestPutBucketExtractHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testPutBucketExtractHandler, []string{"PutBucketExtract"})
+}
+
+func testPutBucketExtractHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	files := map[string]string{
+		"a.txt":       "a",
+		"dir/b.jpg":   "b",
+		"./dir/c":     "",
+		"../escape/d": "d",
+	}
+	extracted := map[string]string{
+		"a.txt":     "a",
+		"dir/b.jpg": "b",
+		"dir/c":     "",
+		"escape/d":  "d",
+	}
+	xattrs := map[string]string{
+		"user.mime_type":   "text/plain",
+		"user.author":      "minio",
+		"security.selinux": "unconfined",
+	}
+	truncated := newTestTar(t, files, nil, true)
+	truncated = truncated[:len(truncated)/2]
+
+	testCases := []struct {
+		bucketName         string
+		prefix             string
+		archive            []byte
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+		expectedMetadata   map[string]string
+	}{
+		// Test case - 1.
+		// Tar with extended attributes.
+		{bucketName, "tar/", newTestTar(t, files, xattrs, false), credentials.AccessKey, credentials.SecretKey, http.StatusOK,
+			map[string]string{"content-type": "text/plain", "X-Amz-Meta-Author": "minio"}},
+		// Test case - 2.
+		// Compressed tar.
+		{bucketName, "tgz/", newTestTar(t, files, nil, true), credentials.AccessKey, credentials.SecretKey, http.StatusOK,
+			map[string]string{"content-type": "", "X-Amz-Meta-Author": ""}},
+		// Test case - 3.
+		// Zip.
+		{bucketName, "zip/", newTestZip(t, files), credentials.AccessKey, credentials.SecretKey, http.StatusOK, nil},
+		// Test case - 4.
+		// Truncated archive.
+		{bucketName, "bad/", truncated, credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest, nil},
+		// Test case - 5.
+		// Not an archive.
+		{bucketName, "bad/", []byte("hello world"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest, nil},
+		// Test case - 6.
+		// Non-existent bucket.
+		{"abcd", "", newTestZip(t, files), credentials.AccessKey, credentials.SecretKey, http.StatusNotFound, nil},
+		// Test case - 7.
+		// Anonymous request.
+		{bucketName, "anon/", newTestZip(t, files), "", "", http.StatusForbidden, nil},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketExtractURL("", testCase.bucketName, testCase.prefix),
+			int64(len(testCase.archive)), bytes.NewReader(testCase.archive), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for PutBucketExtract: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var response ExtractArchiveResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if response.Prefix != testCase.prefix || response.ObjectsCount != int64(len(extracted)) || response.Size != 3 {
+			t.Errorf("Test %d: %s: Expected %d objects of 3 bytes under `%s`, but instead found %#v", i+1, instanceType,
+				len(extracted), testCase.prefix, response)
+		}
+		for name, data := range extracted {
+			var buf bytes.Buffer
+			if err = obj.GetObject(bucketName, testCase.prefix+name, 0, int64(len(data)), &buf); err != nil {
+				t.Fatalf("Test %d: %s: Unable to read %s: <ERROR> %v", i+1, instanceType, name, err)
+			}
+			if buf.String() != data {
+				t.Errorf("Test %d: %s: Expected `%s` in %s, but instead found `%s`", i+1, instanceType, data, name, buf.String())
+			}
+		}
+		objInfo, err := obj.GetObjectInfo(bucketName, testCase.prefix+"dir/c")
+		if err != nil {
+			t.Fatalf("Test %d: %s: Unable to stat dir/c: <ERROR> %v", i+1, instanceType, err)
+		}
+		for key, value := range testCase.expectedMetadata {
+			if objInfo.UserDefined[key] != value {
+				t.Errorf("Test %d: %s: Expected metadata %s to be `%s`, but instead found `%s`", i+1, instanceType, key, value, objInfo.UserDefined[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetBucketLifecycleHandler - GET Bucket lifecycle
+// ----------
+// Returns the lifecycle configuration of a bucket.
+func (api objectAPIHandlers) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkOwnerRequestAuthType(r, "s3:GetLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	config, err := readBucketLifecycle(bucket, objectAPI)
+	if err == errNoSuchLifecycleConfiguration {
+		writeErrorResponse(w, ErrNoSuchLifecycleConfiguration, r)
+		return
+	}
+	if err != nil {
+		errorIf(err, "Unable to read bucket lifecycle.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// PutBucketLifecycleHandler - PUT Bucket lifecycle
+// ----------
+// Replaces the lifecycle configuration of a bucket. Rules expire the
+// objects under their prefix a number of days after they were last
+// modified.
+func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkOwnerRequestAuthType(r, "s3:PutLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketLifecycle always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	var config lifecycleConfiguration
+	if err := xml.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse lifecycle configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r)
+		return
+	}
+	if s3Error := validateLifecycleConfig(config); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	if err := persistBucketLifecycle(bucket, &config, objectAPI); err != nil {
+		errorIf(err, "Unable to write bucket lifecycle.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteBucketLifecycleHandler - DELETE Bucket lifecycle
+// ----------
+// Removes the lifecycle configuration of a bucket, objects are no
+// longer expired.
+func (api objectAPIHandlers) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkOwnerRequestAuthType(r, "s3:PutLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	if err := removeBucketLifecycle(bucket, objectAPI); err != nil {
+		errorIf(err, "Unable to remove bucket lifecycle.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
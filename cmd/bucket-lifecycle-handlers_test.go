@@ -0,0 +1,95 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Wrapper for calling bucket lifecycle handler tests for both XL multiple disks and single node setup.
+func TestBucketLifecycleHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketLifecycleHandlers, []string{
+		"GetBucketLifecycle", "PutBucketLifecycle", "DeleteBucketLifecycle",
+	})
+}
+
+// testBucketLifecycleHandlers - tests setting, reading and removing the
+// lifecycle configuration of a bucket through the S3 API.
+func testBucketLifecycleHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	serve := func(method, urlStr string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	lifecycleURL := makeTestTargetURL("", bucketName, "", url.Values{"lifecycle": []string{""}})
+
+	if rec := serve("GET", lifecycleURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected no lifecycle configuration, got %d", instanceType, rec.Code)
+	}
+
+	testCases := []struct {
+		config       string
+		expectedCode int
+	}{
+		// Malformed XML.
+		{`<LifecycleConfiguration><Rule>`, http.StatusBadRequest},
+		// No rules.
+		{`<LifecycleConfiguration></LifecycleConfiguration>`, http.StatusBadRequest},
+		// Invalid status.
+		{`<LifecycleConfiguration><Rule><Prefix>logs/</Prefix><Status>On</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusBadRequest},
+		// Days must be positive.
+		{`<LifecycleConfiguration><Rule><Prefix>logs/</Prefix><Status>Enabled</Status><Expiration><Days>0</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusBadRequest},
+		// Duplicate rule IDs.
+		{`<LifecycleConfiguration><Rule><ID>a</ID><Prefix>x/</Prefix><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>` +
+			`<Rule><ID>a</ID><Prefix>y/</Prefix><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusBadRequest},
+		// Transitions are not implemented.
+		{`<LifecycleConfiguration><Rule><Prefix>logs/</Prefix><Status>Enabled</Status><Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule></LifecycleConfiguration>`, http.StatusNotImplemented},
+		// Valid configuration.
+		{`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		if rec := serve("PUT", lifecycleURL, []byte(testCase.config)); rec.Code != testCase.expectedCode {
+			t.Errorf("%s: Test %d: Expected %d, got %d: %s", instanceType, i+1, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := serve("GET", lifecycleURL, nil)
+	var config lifecycleConfiguration
+	if err := xml.Unmarshal(rec.Body.Bytes(), &config); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("%s: Failed to get lifecycle configuration, got %d: %s", instanceType, rec.Code, rec.Body.String())
+	}
+	if len(config.Rules) != 1 || config.Rules[0].ID != "logs" || config.Rules[0].getPrefix() != "logs/" || config.Rules[0].Expiration.Days != 30 {
+		t.Fatalf("%s: Unexpected lifecycle configuration %s", instanceType, rec.Body.String())
+	}
+
+	if rec = serve("DELETE", lifecycleURL, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Failed to delete lifecycle configuration, got %d", instanceType, rec.Code)
+	}
+	if rec = serve("GET", lifecycleURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected lifecycle configuration to be removed, got %d", instanceType, rec.Code)
+	}
+}
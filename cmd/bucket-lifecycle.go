@@ -0,0 +1,329 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"path"
+	"time"
+)
+
+const (
+	// Lifecycle configuration of a bucket, saved under the bucket
+	// config prefix.
+	bucketLifecycleConfig = "lifecycle.xml"
+
+	// Maximum number of rules of a lifecycle configuration.
+	maxLifecycleRules = 1000
+
+	// Maximum length of a lifecycle rule ID.
+	maxLifecycleRuleIDLength = 255
+
+	// Status of lifecycle rules.
+	lifecycleRuleEnabled  = "Enabled"
+	lifecycleRuleDisabled = "Disabled"
+
+	// Interval between two passes expiring objects. Objects expire at
+	// the granularity of days, the interval only bounds how late.
+	lifecycleExpiryInterval = time.Hour
+)
+
+// errNoSuchLifecycleConfiguration - no lifecycle configuration is set
+// on the bucket.
+var errNoSuchLifecycleConfiguration = errors.New("The lifecycle configuration does not exist")
+
+// lifecycleUnsupported - lifecycle actions and filters the server does
+// not implement, configurations using them are rejected.
+type lifecycleUnsupported struct{}
+
+// lifecycleFilter - selects the objects a lifecycle rule applies to.
+type lifecycleFilter struct {
+	Prefix string                `xml:"Prefix"`
+	Tag    *lifecycleUnsupported `xml:"Tag,omitempty"`
+	And    *lifecycleUnsupported `xml:"And,omitempty"`
+}
+
+// lifecycleExpiration - expires objects a number of days after they
+// were last modified.
+type lifecycleExpiration struct {
+	Days                      int    `xml:"Days,omitempty"`
+	Date                      string `xml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker string `xml:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+// lifecycleRule - a rule of a lifecycle configuration. The prefix is
+// either given directly, as in older configurations, or by a filter.
+type lifecycleRule struct {
+	ID         string               `xml:"ID,omitempty"`
+	Prefix     string               `xml:"Prefix,omitempty"`
+	Filter     *lifecycleFilter     `xml:"Filter,omitempty"`
+	Status     string               `xml:"Status"`
+	Expiration *lifecycleExpiration `xml:"Expiration,omitempty"`
+
+	Transition                     *lifecycleUnsupported `xml:"Transition,omitempty"`
+	NoncurrentVersionTransition    *lifecycleUnsupported `xml:"NoncurrentVersionTransition,omitempty"`
+	NoncurrentVersionExpiration    *lifecycleUnsupported `xml:"NoncurrentVersionExpiration,omitempty"`
+	AbortIncompleteMultipartUpload *lifecycleUnsupported `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// lifecycleConfiguration - represents `lifecycle.xml`, as accepted by
+// the S3 PutBucketLifecycleConfiguration API.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+// getPrefix - returns the prefix of the objects rule applies to.
+func (rule lifecycleRule) getPrefix() string {
+	if rule.Filter != nil {
+		return rule.Filter.Prefix
+	}
+	return rule.Prefix
+}
+
+// getExpiryTime - returns when an object last modified at modTime
+// expires, the end of the day UTC the rule's days after modTime.
+func (rule lifecycleRule) getExpiryTime(modTime time.Time) time.Time {
+	const day = 24 * time.Hour
+	expiry := modTime.UTC().Add(time.Duration(rule.Expiration.Days) * day)
+	if midnight := expiry.Truncate(day); !midnight.Equal(expiry) {
+		return midnight.Add(day)
+	}
+	return expiry
+}
+
+// isExpired - returns true if the rule expires objInfo at now.
+func (rule lifecycleRule) isExpired(objInfo ObjectInfo, now time.Time) bool {
+	if rule.Status != lifecycleRuleEnabled || objInfo.IsDir {
+		return false
+	}
+	if !hasPrefix(objInfo.Name, rule.getPrefix()) {
+		return false
+	}
+	return !now.Before(rule.getExpiryTime(objInfo.ModTime))
+}
+
+// validateLifecycleConfig - validates a lifecycle configuration, rules
+// may only expire current objects after a number of days.
+func validateLifecycleConfig(config lifecycleConfiguration) APIErrorCode {
+	if len(config.Rules) == 0 || len(config.Rules) > maxLifecycleRules {
+		return ErrMalformedXML
+	}
+	ids := make(map[string]bool)
+	for _, rule := range config.Rules {
+		if len(rule.ID) > maxLifecycleRuleIDLength {
+			return ErrInvalidLifecycleRule
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrInvalidLifecycleRule
+			}
+			ids[rule.ID] = true
+		}
+		if rule.Status != lifecycleRuleEnabled && rule.Status != lifecycleRuleDisabled {
+			return ErrMalformedXML
+		}
+		if rule.Filter != nil && rule.Prefix != "" {
+			return ErrMalformedXML
+		}
+		if rule.Filter != nil && (rule.Filter.Tag != nil || rule.Filter.And != nil) {
+			return ErrNotImplemented
+		}
+		if rule.Transition != nil || rule.NoncurrentVersionTransition != nil ||
+			rule.NoncurrentVersionExpiration != nil || rule.AbortIncompleteMultipartUpload != nil {
+			return ErrNotImplemented
+		}
+		if rule.Expiration == nil {
+			return ErrMalformedXML
+		}
+		if rule.Expiration.Date != "" || rule.Expiration.ExpiredObjectDeleteMarker != "" {
+			return ErrNotImplemented
+		}
+		if rule.Expiration.Days <= 0 {
+			return ErrInvalidLifecycleRule
+		}
+	}
+	return ErrNone
+}
+
+// readBucketLifecycle - returns the lifecycle configuration of bucket,
+// errNoSuchLifecycleConfiguration if none is set.
+func readBucketLifecycle(bucket string, objAPI ObjectLayer) (*lifecycleConfiguration, error) {
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	if err := objAPI.GetObject(minioMetaBucket, lcPath, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchLifecycleConfiguration
+		}
+		return nil, err
+	}
+	config := &lifecycleConfiguration{}
+	if err := xml.Unmarshal(buffer.Bytes(), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// persistBucketLifecycle - saves a validated lifecycle configuration
+// of bucket.
+func persistBucketLifecycle(bucket string, config *lifecycleConfiguration, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	_, err = objAPI.PutObject(minioMetaBucket, lcPath, int64(len(buf)), bytes.NewReader(buf), nil, getSHA256Hash(buf))
+	return err
+}
+
+// removeBucketLifecycle - removes the lifecycle configuration of
+// bucket, if any.
+func removeBucketLifecycle(bucket string, objAPI ObjectLayer) error {
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	if err := objAPI.DeleteObject(minioMetaBucket, lcPath); err != nil && !isErrObjectNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// expireObject - deletes objInfo of bucket if rule still expires it,
+// the object may have been overwritten since it was listed. Returns
+// true if the object was deleted.
+func expireObject(objAPI ObjectLayer, bucket string, rule lifecycleRule, objInfo ObjectInfo, now time.Time) (bool, error) {
+	objectLock := globalNSMutex.NewNSLock(bucket, objInfo.Name)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, objInfo.Name)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !rule.isExpired(objInfo, now) {
+		return false, nil
+	}
+	if err = objAPI.DeleteObject(bucket, objInfo.Name); err != nil {
+		if isErrObjectNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	eventNotify(eventData{
+		Type:   ObjectRemovedDelete,
+		Bucket: bucket,
+		ObjInfo: ObjectInfo{
+			Name: objInfo.Name,
+		},
+	})
+	return true, nil
+}
+
+// expireBucketObjects - deletes the objects of bucket expired by the
+// enabled rules of config at now, returns the number of objects
+// deleted.
+func expireBucketObjects(objAPI ObjectLayer, bucket string, config *lifecycleConfiguration, now time.Time) (expired int, err error) {
+	for _, rule := range config.Rules {
+		if rule.Status != lifecycleRuleEnabled {
+			continue
+		}
+		marker := ""
+		for {
+			result, err := objAPI.ListObjects(bucket, rule.getPrefix(), marker, "", maxObjectList)
+			if err != nil {
+				return expired, err
+			}
+			for _, objInfo := range result.Objects {
+				if !rule.isExpired(objInfo, now) {
+					continue
+				}
+				deleted, err := expireObject(objAPI, bucket, rule, objInfo, now)
+				if err != nil {
+					return expired, err
+				}
+				if deleted {
+					expired++
+				}
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker = result.NextMarker
+		}
+	}
+	return expired, nil
+}
+
+// expireObjects - deletes the expired objects of all buckets with a
+// lifecycle configuration at now.
+func expireObjects(objAPI ObjectLayer, now time.Time) error {
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		config, err := readBucketLifecycle(bucket.Name, objAPI)
+		if err == errNoSuchLifecycleConfiguration {
+			continue
+		}
+		if err == nil {
+			_, err = expireBucketObjects(objAPI, bucket.Name, config, now)
+		}
+		// Carry on with the other buckets.
+		errorIf(err, "Unable to expire objects of bucket %s.", bucket.Name)
+	}
+	return nil
+}
+
+// startLifecycleExpiry - deletes expired objects every
+// lifecycleExpiryInterval until the server stops. In distributed mode
+// only the server of the first endpoint expires objects, so that
+// versioned buckets get one delete marker per expired object.
+func startLifecycleExpiry(objAPI ObjectLayer) {
+	if globalIsDistXL && (len(globalEndpoints) == 0 || !isLocalStorage(globalEndpoints[0])) {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(lifecycleExpiryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(expireObjects(objAPI, time.Now().UTC()), "Unable to expire objects.")
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Tests the expiry time of objects, rounded up to midnight UTC.
+func TestLifecycleRuleExpiryTime(t *testing.T) {
+	rule := lifecycleRule{Expiration: &lifecycleExpiration{Days: 2}}
+	testCases := []struct {
+		modTime, expiry time.Time
+	}{
+		{time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2017, 3, 1, 10, 30, 0, 0, time.UTC), time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2017, 3, 31, 23, 59, 59, 0, time.UTC), time.Date(2017, 4, 3, 0, 0, 0, 0, time.UTC)},
+		// Local times are converted to UTC first.
+		{time.Date(2017, 3, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)), time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, testCase := range testCases {
+		if expiry := rule.getExpiryTime(testCase.modTime); !expiry.Equal(testCase.expiry) {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expiry, expiry)
+		}
+	}
+}
+
+// Wrapper for calling lifecycle expiry tests for both XL multiple disks and single node setup.
+func TestExpireBucketObjects(t *testing.T) {
+	ExecObjectLayerTest(t, testExpireBucketObjects)
+}
+
+// testExpireBucketObjects - tests that enabled rules delete the objects
+// under their prefix once expired.
+func testExpireBucketObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	objects := []string{"logs/a", "logs/2017/b", "archive/c", "d"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	config := &lifecycleConfiguration{
+		Rules: []lifecycleRule{
+			{ID: "logs", Filter: &lifecycleFilter{Prefix: "logs/"}, Status: lifecycleRuleEnabled, Expiration: &lifecycleExpiration{Days: 7}},
+			{ID: "archive", Prefix: "archive/", Status: lifecycleRuleDisabled, Expiration: &lifecycleExpiration{Days: 1}},
+		},
+	}
+	if s3Error := validateLifecycleConfig(*config); s3Error != ErrNone {
+		t.Fatalf("%s: Expected valid lifecycle configuration, got %v", instanceType, s3Error)
+	}
+
+	now := time.Now().UTC()
+	if expired, err := expireBucketObjects(obj, bucket, config, now.Add(6*24*time.Hour)); err != nil || expired != 0 {
+		t.Fatalf("%s: Expected no object to expire, got %d, %v", instanceType, expired, err)
+	}
+	if expired, err := expireBucketObjects(obj, bucket, config, now.Add(8*24*time.Hour)); err != nil || expired != 2 {
+		t.Fatalf("%s: Expected 2 objects to expire, got %d, %v", instanceType, expired, err)
+	}
+	result, err := obj.ListObjects(bucket, "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 2 || result.Objects[0].Name != "archive/c" || result.Objects[1].Name != "d" {
+		t.Fatalf("%s: Expected archive/c and d to be kept, got %+v", instanceType, result.Objects)
+	}
+}
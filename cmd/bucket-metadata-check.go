@@ -0,0 +1,187 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// Interval between two consistency checks of bucket metadata.
+const bucketMetaCheckInterval = 10 * time.Minute
+
+// errBucketMetaDiverged - bucket metadata differed between disks, or
+// between this server and the disks, and was repaired.
+var errBucketMetaDiverged = errors.New("Bucket metadata diverged")
+
+// getBucketMetaPaths - returns paths of all metadata files of a
+// bucket in the reserved bucket.
+func getBucketMetaPaths(bucket string) []string {
+	return []string{
+		pathJoin(bucketConfigPrefix, bucket, bucketPolicyConfig),
+		pathJoin(bucketConfigPrefix, bucket, bucketNotificationConfig),
+		pathJoin(bucketConfigPrefix, bucket, bucketListenerConfig),
+		pathJoin(bucketConfigPrefix, bucket, bucketVersioningConfig),
+		pathJoin(bucketConfigPrefix, bucket, bucketLifecycleConfig),
+	}
+}
+
+// repairBucketMetadata - makes the metadata files of a bucket agree
+// on all disks, returns the number of files repaired. Files missing
+// on at least a write quorum of disks were removed, copies left behind
+// by disks which missed the removal are deleted. Files present on a
+// quorum of disks are healed to the disks with missing or outdated
+// copies.
+func (xl xlObjects) repairBucketMetadata(bucket string) (repaired int, err error) {
+	for _, metaPath := range getBucketMetaPaths(bucket) {
+		n, err := xl.repairBucketMetaFile(metaPath)
+		if err != nil {
+			return repaired, err
+		}
+		repaired += n
+	}
+	return repaired, nil
+}
+
+func (xl xlObjects) repairBucketMetaFile(metaPath string) (int, error) {
+	metaLock := globalNSMutex.NewNSLock(minioMetaBucket, metaPath)
+	metaLock.Lock()
+	defer metaLock.Unlock()
+
+	partsMetadata, errs := readAllXLMetadata(xl.storageDisks, minioMetaBucket, metaPath)
+	found, notFound := 0, 0
+	for _, err := range errs {
+		switch errorCause(err) {
+		case nil:
+			found++
+		case errFileNotFound:
+			notFound++
+		}
+	}
+	if found == 0 {
+		return 0, nil
+	}
+
+	// A successful write leaves less than a write quorum of disks
+	// without the file, so it was removed.
+	if notFound >= xl.getWriteQuorum() {
+		if err := xl.deleteObject(minioMetaBucket, metaPath); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	if !xlShouldHeal(partsMetadata, errs) {
+		return 0, nil
+	}
+	if err := healObject(xl.storageDisks, minioMetaBucket, metaPath, xl.readQuorum); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// isSameBucketPolicy - returns true if both policies marshal to the
+// same document, policies received from peers may differ from the
+// parsed ones in nil and empty fields.
+func isSameBucketPolicy(a, b *bucketPolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aBytes, aErr := json.Marshal(a)
+	bBytes, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aBytes, bBytes)
+}
+
+// isSameNotificationConfig - returns true if both configs marshal to
+// the same document.
+func isSameNotificationConfig(a, b *notificationConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aBytes, aErr := xml.Marshal(a)
+	bBytes, bErr := xml.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aBytes, bBytes)
+}
+
+// checkBucketMetadata - repairs bucket metadata diverged between
+// disks, then replaces bucket policies, versioning statuses and
+// notification configs cached by this server which differ from the
+// ones read with quorum.
+// Returns the number of repairs. xl is nil in FS mode.
+func checkBucketMetadata(objAPI ObjectLayer, xl *xlObjects) (repaired int, err error) {
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		return 0, err
+	}
+	for _, bucket := range buckets {
+		if xl != nil {
+			n, rErr := xl.repairBucketMetadata(bucket.Name)
+			errorIf(rErr, "Unable to repair metadata of bucket %s.", bucket.Name)
+			repaired += n
+		}
+
+		policy, pErr := readBucketPolicy(bucket.Name, objAPI)
+		if isErrBucketPolicyNotFound(pErr) {
+			policy, pErr = nil, nil
+		}
+		if pErr == nil && !isSameBucketPolicy(policy, globalBucketPolicies.GetBucketPolicy(bucket.Name)) {
+			globalBucketPolicies.SetBucketPolicy(bucket.Name, policyChange{policy == nil, policy})
+			repaired++
+		}
+
+		status, vErr := readBucketVersioning(objAPI, bucket.Name)
+		if vErr == nil && globalBucketVersioning != nil && status != globalBucketVersioning.GetBucketVersioning(bucket.Name) {
+			globalBucketVersioning.SetBucketVersioning(bucket.Name, status)
+			repaired++
+		}
+
+		nConfig, nErr := loadNotificationConfig(bucket.Name, objAPI)
+		if nErr == errNoSuchNotifications {
+			nConfig, nErr = nil, nil
+		}
+		if nErr == nil && !isSameNotificationConfig(nConfig, globalEventNotifier.GetBucketNotificationConfig(bucket.Name)) {
+			globalEventNotifier.SetBucketNotificationConfig(bucket.Name, nConfig)
+			repaired++
+		}
+	}
+	return repaired, nil
+}
+
+// startBucketMetaChecker - checks bucket metadata for divergence at
+// bucketMetaCheckInterval until the server stops, xl is nil in FS
+// mode.
+func startBucketMetaChecker(objAPI ObjectLayer, xl *xlObjects) {
+	go func() {
+		ticker := time.NewTicker(bucketMetaCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				repaired, err := checkBucketMetadata(objAPI, xl)
+				errorIf(err, "Unable to check bucket metadata.")
+				if repaired > 0 {
+					errorIf(errBucketMetaDiverged, "Repaired %d diverged bucket metadata entries.", repaired)
+				}
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+// Tests that diverged bucket policies are repaired on disks and in the
+// policies cached by this server.
+func TestCheckBucketMetadata(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = initBucketPolicies(obj); err != nil {
+		t.Fatal(err)
+	}
+	if err = initEventNotifier(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getReadOnlyBucketStatement(bucket, "")},
+	}
+	if err = writeBucketPolicy(bucket, obj, policy); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing diverged yet, except for the policy cache.
+	repaired, err := checkBucketMetadata(obj, xl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repaired != 1 {
+		t.Fatalf("Expected 1 repair, got %d", repaired)
+	}
+	if !isSameBucketPolicy(policy, globalBucketPolicies.GetBucketPolicy(bucket)) {
+		t.Fatal("Expected cached policy to be replaced")
+	}
+	if repaired, err = checkBucketMetadata(obj, xl); err != nil || repaired != 0 {
+		t.Fatalf("Expected no repairs, got %d, %v", repaired, err)
+	}
+
+	// Policy missing on one disk is healed.
+	policyPath := path.Join(fsDirs[0], minioMetaBucket, bucketConfigPrefix, bucket, bucketPolicyConfig)
+	if err = os.RemoveAll(policyPath); err != nil {
+		t.Fatal(err)
+	}
+	if repaired, err = checkBucketMetadata(obj, xl); err != nil || repaired != 1 {
+		t.Fatalf("Expected 1 repair, got %d, %v", repaired, err)
+	}
+	if _, err = os.Stat(path.Join(policyPath, xlMetaJSONFile)); err != nil {
+		t.Fatalf("Expected policy to be healed, got %v", err)
+	}
+
+	// Policy left behind on one disk by a removal is deleted, and
+	// removed from the cache.
+	for _, fsDir := range fsDirs[1:] {
+		if err = os.RemoveAll(path.Join(fsDir, minioMetaBucket, bucketConfigPrefix, bucket, bucketPolicyConfig)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repaired, err = checkBucketMetadata(obj, xl); err != nil || repaired != 2 {
+		t.Fatalf("Expected 2 repairs, got %d, %v", repaired, err)
+	}
+	if _, err = os.Stat(policyPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected policy to be deleted, got %v", err)
+	}
+	if globalBucketPolicies.GetBucketPolicy(bucket) != nil {
+		t.Fatal("Expected cached policy to be removed")
+	}
+}
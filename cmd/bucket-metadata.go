@@ -30,6 +30,9 @@ type BucketMetaState interface {
 	// Updates bucket policy
 	UpdateBucketPolicy(args *SetBucketPolicyPeerArgs) error
 
+	// Updates bucket versioning
+	UpdateBucketVersioning(args *SetBucketVersioningPeerArgs) error
+
 	// Sends event
 	SendEvent(args *EventArgs) error
 }
@@ -88,6 +91,19 @@ func (lc *localBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArgs
 	return globalBucketPolicies.SetBucketPolicy(args.Bucket, pCh)
 }
 
+// localBucketMetaState.UpdateBucketVersioning - updates in-memory global
+// bucket versioning info.
+func (lc *localBucketMetaState) UpdateBucketVersioning(args *SetBucketVersioningPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	globalBucketVersioning.SetBucketVersioning(args.Bucket, args.Status)
+	return nil
+}
+
 // localBucketMetaState.SendEvent - sends event to local event notifier via
 // `globalEventNotifier`
 func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
@@ -126,6 +142,13 @@ func (rc *remoteBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArg
 	return rc.Call("S3.SetBucketPolicyPeer", args, &reply)
 }
 
+// remoteBucketMetaState.UpdateBucketVersioning - sends bucket versioning
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketVersioning(args *SetBucketVersioningPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.SetBucketVersioningPeer", args, &reply)
+}
+
 // remoteBucketMetaState.SendEvent - sends event for bucket listener to remote
 // peer via RPC call.
 func (rc *remoteBucketMetaState) SendEvent(args *EventArgs) error {
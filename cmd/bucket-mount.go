@@ -0,0 +1,463 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// bucketMountConfig - maps a read-only bucket to a host directory,
+// configured in config.json.
+type bucketMountConfig struct {
+	Bucket string `json:"bucket"`
+	Path   string `json:"path"`
+}
+
+// errBucketMountsXL - bucket mounts serve plain files which XL cannot.
+var errBucketMountsXL = errors.New("bucket mounts are only supported in FS mode")
+
+// bucketMount - read-only bucket backed by an arbitrary directory,
+// files under the directory are served as objects without any
+// additional metadata.
+type bucketMount struct {
+	bucket  string
+	dirPath string
+}
+
+// objectPath - returns the path of an object under the mount,
+// rejecting names which would escape the mounted directory.
+func (m bucketMount) objectPath(object string) (string, error) {
+	objPath := pathJoin(m.dirPath, object)
+	if !hasPrefix(objPath, pathJoin(m.dirPath, slashSeparator)) {
+		return "", traceError(ObjectNameInvalid{Bucket: m.bucket, Object: object})
+	}
+	return objPath, nil
+}
+
+// getBucketInfo - returns bucket info of the mount.
+func (m bucketMount) getBucketInfo() (BucketInfo, error) {
+	st, err := fsStatDir(m.dirPath)
+	if err != nil {
+		return BucketInfo{}, toObjectErr(err, m.bucket)
+	}
+	return BucketInfo{
+		Name:    m.bucket,
+		Created: st.ModTime(),
+	}, nil
+}
+
+// getObjectInfo - returns info of a file under the mount.
+func (m bucketMount) getObjectInfo(object string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(m.bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	objPath, err := m.objectPath(object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := fsStatFile(objPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, m.bucket, object)
+	}
+	return fsMetaV1{}.ToObjectInfo(m.bucket, object, fi), nil
+}
+
+// getObject - reads length bytes at offset of a file under the mount.
+func (m bucketMount) getObject(object string, offset int64, length int64, writer io.Writer) error {
+	if err := checkGetObjArgs(m.bucket, object); err != nil {
+		return err
+	}
+
+	// Offset cannot be negative, writer cannot be nil.
+	if offset < 0 || writer == nil {
+		return toObjectErr(traceError(errUnexpected), m.bucket, object)
+	}
+
+	objPath, err := m.objectPath(object)
+	if err != nil {
+		return err
+	}
+	reader, size, err := fsOpenFile(objPath, offset)
+	if err != nil {
+		return toObjectErr(err, m.bucket, object)
+	}
+	defer reader.Close()
+
+	// For negative length we read everything.
+	if length < 0 {
+		length = size - offset
+	}
+
+	// Reply back invalid range if the input offset and length fall out of range.
+	if offset > size || offset+length > size {
+		return traceError(InvalidRange{offset, length, size})
+	}
+
+	bufSize := int64(readSizeV1)
+	if length > 0 && bufSize > length {
+		bufSize = length
+	}
+	buf, err := globalBufferPool.Get(bufSize)
+	if err != nil {
+		return toObjectErr(traceError(err), m.bucket, object)
+	}
+	defer globalBufferPool.Put(buf)
+
+	_, err = io.CopyBuffer(writer, io.LimitReader(reader, length), buf)
+	return toObjectErr(traceError(err), m.bucket, object)
+}
+
+// listObjects - lists files under the mount, follows fsObjects.ListObjects
+// without re-using tree walks between requests.
+func (m bucketMount) listObjects(prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	if !IsValidObjectPrefix(prefix) {
+		return ListObjectsInfo{}, traceError(ObjectNameInvalid{Bucket: m.bucket, Object: prefix})
+	}
+	if delimiter != "" && delimiter != slashSeparator {
+		return ListObjectsInfo{}, traceError(UnsupportedDelimiter{Delimiter: delimiter})
+	}
+	if marker != "" && !hasPrefix(marker, prefix) {
+		return ListObjectsInfo{}, traceError(InvalidMarkerPrefixCombination{Marker: marker, Prefix: prefix})
+	}
+
+	if _, err := fsStatDir(m.dirPath); err != nil {
+		return ListObjectsInfo{}, toObjectErr(err, m.bucket)
+	}
+
+	// With max keys of zero we have reached eof, return right here.
+	if maxKeys == 0 {
+		return ListObjectsInfo{}, nil
+	}
+
+	// For delimiter and prefix as '/' we do not list anything at all.
+	if delimiter == slashSeparator && prefix == slashSeparator {
+		return ListObjectsInfo{}, nil
+	}
+
+	// Over flowing count - reset to maxObjectList.
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	// Default is recursive, if delimiter is set then list non recursive.
+	recursive := delimiter != slashSeparator
+
+	isLeaf := func(bucket, object string) bool {
+		return !hasSuffix(object, slashSeparator)
+	}
+	listDir := func(bucket, prefixDir, prefixEntry string) (entries []string, delayIsLeaf bool, err error) {
+		entries, err = readDir(pathJoin(m.dirPath, prefixDir))
+		if err != nil {
+			return nil, false, err
+		}
+		entries, delayIsLeaf = filterListEntries(bucket, prefixDir, entries, prefixEntry, isLeaf)
+		return entries, delayIsLeaf, nil
+	}
+
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+	walkResultCh := startTreeWalk(m.bucket, prefix, marker, recursive, listDir, isLeaf, nil, endWalkCh)
+
+	result := ListObjectsInfo{IsTruncated: true}
+	for i := 0; i < maxKeys; i++ {
+		walkResult, ok := <-walkResultCh
+		if !ok {
+			result.IsTruncated = false
+			break
+		}
+		if walkResult.err != nil {
+			// File not found is a valid case.
+			if errorCause(walkResult.err) == errFileNotFound {
+				return ListObjectsInfo{}, nil
+			}
+			return ListObjectsInfo{}, toObjectErr(walkResult.err, m.bucket, prefix)
+		}
+		result.NextMarker = walkResult.entry
+		if hasSuffix(walkResult.entry, slashSeparator) {
+			result.Prefixes = append(result.Prefixes, walkResult.entry)
+		} else {
+			fi, err := fsStatFile(pathJoin(m.dirPath, walkResult.entry))
+			if err != nil {
+				return ListObjectsInfo{}, toObjectErr(err, m.bucket, walkResult.entry)
+			}
+			result.Objects = append(result.Objects, fsMetaV1{}.ToObjectInfo(m.bucket, walkResult.entry, fi))
+		}
+		if walkResult.end {
+			result.IsTruncated = false
+			break
+		}
+	}
+	if !result.IsTruncated {
+		result.NextMarker = ""
+	}
+	return result, nil
+}
+
+// bucketMountObjects - serves read-only bucket mounts next to the
+// buckets of the wrapped object layer, all writes to mounted buckets
+// are rejected with BucketReadOnly.
+type bucketMountObjects struct {
+	ObjectLayer
+	mounts map[string]bucketMount
+}
+
+// validateBucketMounts - validates that every mount has a valid and
+// unique bucket name and an absolute path to an existing directory.
+func validateBucketMounts(configs []bucketMountConfig) error {
+	buckets := make(map[string]struct{})
+	for _, config := range configs {
+		if !IsValidBucketName(config.Bucket) || isMinioMetaBucketName(config.Bucket) {
+			return fmt.Errorf("invalid bucket name ‘%s’ for bucket mount", config.Bucket)
+		}
+		if _, ok := buckets[config.Bucket]; ok {
+			return fmt.Errorf("bucket ‘%s’ is mounted more than once", config.Bucket)
+		}
+		buckets[config.Bucket] = struct{}{}
+		if !filepath.IsAbs(config.Path) {
+			return fmt.Errorf("bucket mount path ‘%s’ is not an absolute path", config.Path)
+		}
+		if fi, err := os.Stat(config.Path); err != nil {
+			return fmt.Errorf("unable to access bucket mount path ‘%s’: %v", config.Path, err)
+		} else if !fi.IsDir() {
+			return fmt.Errorf("bucket mount path ‘%s’ is not a directory", config.Path)
+		}
+	}
+	return nil
+}
+
+// newBucketMountObjectLayer - wraps objAPI to serve the configured
+// bucket mounts, mounted buckets must not shadow existing buckets.
+func newBucketMountObjectLayer(objAPI ObjectLayer, configs []bucketMountConfig) (ObjectLayer, error) {
+	if err := validateBucketMounts(configs); err != nil {
+		return nil, err
+	}
+	mounts := make(map[string]bucketMount)
+	for _, config := range configs {
+		if _, err := objAPI.GetBucketInfo(config.Bucket); err == nil {
+			return nil, fmt.Errorf("bucket mount ‘%s’ conflicts with an existing bucket", config.Bucket)
+		}
+		mounts[config.Bucket] = bucketMount{
+			bucket:  config.Bucket,
+			dirPath: filepath.ToSlash(filepath.Clean(config.Path)),
+		}
+	}
+	return bucketMountObjects{objAPI, mounts}, nil
+}
+
+// isMounted - returns the mount of the bucket, if any.
+func (l bucketMountObjects) isMounted(bucket string) (bucketMount, bool) {
+	m, ok := l.mounts[bucket]
+	return m, ok
+}
+
+// MakeBucket - mounted buckets always exist.
+func (l bucketMountObjects) MakeBucket(bucket string) error {
+	if _, ok := l.isMounted(bucket); ok {
+		return traceError(BucketExists{Bucket: bucket})
+	}
+	return l.ObjectLayer.MakeBucket(bucket)
+}
+
+// GetBucketInfo - returns bucket info of mounted and regular buckets.
+func (l bucketMountObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
+	if m, ok := l.isMounted(bucket); ok {
+		return m.getBucketInfo()
+	}
+	return l.ObjectLayer.GetBucketInfo(bucket)
+}
+
+// ListBuckets - lists regular buckets along with all accessible mounts.
+func (l bucketMountObjects) ListBuckets() ([]BucketInfo, error) {
+	buckets, err := l.ObjectLayer.ListBuckets()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range l.mounts {
+		bucketInfo, err := m.getBucketInfo()
+		if err != nil {
+			// Skip mounts whose directory has disappeared.
+			continue
+		}
+		buckets = append(buckets, bucketInfo)
+	}
+	sort.Sort(byBucketName(buckets))
+	return buckets, nil
+}
+
+// DeleteBucket - mounted buckets cannot be deleted.
+func (l bucketMountObjects) DeleteBucket(bucket string) error {
+	if _, ok := l.isMounted(bucket); ok {
+		return traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.DeleteBucket(bucket)
+}
+
+// ListObjects - lists objects of mounted and regular buckets.
+func (l bucketMountObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	if m, ok := l.isMounted(bucket); ok {
+		return m.listObjects(prefix, marker, delimiter, maxKeys)
+	}
+	return l.ObjectLayer.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+}
+
+// GetObject - reads objects of mounted and regular buckets.
+func (l bucketMountObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	if m, ok := l.isMounted(bucket); ok {
+		return m.getObject(object, startOffset, length, writer)
+	}
+	return l.ObjectLayer.GetObject(bucket, object, startOffset, length, writer)
+}
+
+// GetObjectInfo - returns info of objects of mounted and regular buckets.
+func (l bucketMountObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	if m, ok := l.isMounted(bucket); ok {
+		return m.getObjectInfo(object)
+	}
+	return l.ObjectLayer.GetObjectInfo(bucket, object)
+}
+
+// PutObject - rejects writes to mounted buckets.
+func (l bucketMountObjects) PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (ObjectInfo, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return ObjectInfo{}, traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.PutObject(bucket, object, size, data, metadata, sha256sum)
+}
+
+// CopyObject - rejects copies into mounted buckets, copies out of
+// mounted buckets are streamed into the destination.
+func (l bucketMountObjects) CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (ObjectInfo, error) {
+	if _, ok := l.isMounted(destBucket); ok {
+		return ObjectInfo{}, traceError(BucketReadOnly{Bucket: destBucket})
+	}
+	m, ok := l.isMounted(srcBucket)
+	if !ok {
+		return l.ObjectLayer.CopyObject(srcBucket, srcObject, destBucket, destObject, metadata)
+	}
+	objInfo, err := m.getObjectInfo(srcObject)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(m.getObject(srcObject, 0, objInfo.Size, pipeWriter))
+	}()
+	defer pipeReader.Close()
+	return l.ObjectLayer.PutObject(destBucket, destObject, objInfo.Size, pipeReader, metadata, "")
+}
+
+// DeleteObject - rejects deletes from mounted buckets.
+func (l bucketMountObjects) DeleteObject(bucket, object string) error {
+	if _, ok := l.isMounted(bucket); ok {
+		return traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.DeleteObject(bucket, object)
+}
+
+// ListMultipartUploads - mounted buckets never have uploads in progress.
+func (l bucketMountObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return ListMultipartsInfo{
+			KeyMarker:      keyMarker,
+			UploadIDMarker: uploadIDMarker,
+			MaxUploads:     maxUploads,
+			Prefix:         prefix,
+			Delimiter:      delimiter,
+		}, nil
+	}
+	return l.ObjectLayer.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+}
+
+// NewMultipartUpload - rejects uploads to mounted buckets.
+func (l bucketMountObjects) NewMultipartUpload(bucket, object string, metadata map[string]string) (string, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return "", traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.NewMultipartUpload(bucket, object, metadata)
+}
+
+// CopyObjectPart - rejects part copies into mounted buckets, part
+// copies out of mounted buckets are streamed into the destination.
+func (l bucketMountObjects) CopyObjectPart(srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int, startOffset int64, length int64) (PartInfo, error) {
+	if _, ok := l.isMounted(destBucket); ok {
+		return PartInfo{}, traceError(BucketReadOnly{Bucket: destBucket})
+	}
+	m, ok := l.isMounted(srcBucket)
+	if !ok {
+		return l.ObjectLayer.CopyObjectPart(srcBucket, srcObject, destBucket, destObject, uploadID, partID, startOffset, length)
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(m.getObject(srcObject, startOffset, length, pipeWriter))
+	}()
+	defer pipeReader.Close()
+	return l.ObjectLayer.PutObjectPart(destBucket, destObject, uploadID, partID, length, pipeReader, "", "")
+}
+
+// PutObjectPart - rejects uploads to mounted buckets.
+func (l bucketMountObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (PartInfo, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return PartInfo{}, traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.PutObjectPart(bucket, object, uploadID, partID, size, data, md5Hex, sha256sum)
+}
+
+// ListObjectParts - mounted buckets never have uploads in progress.
+func (l bucketMountObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker int, maxParts int) (ListPartsInfo, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return ListPartsInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
+	}
+	return l.ObjectLayer.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+}
+
+// AbortMultipartUpload - mounted buckets never have uploads in progress.
+func (l bucketMountObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+	if _, ok := l.isMounted(bucket); ok {
+		return traceError(InvalidUploadID{UploadID: uploadID})
+	}
+	return l.ObjectLayer.AbortMultipartUpload(bucket, object, uploadID)
+}
+
+// CompleteMultipartUpload - rejects uploads to mounted buckets.
+func (l bucketMountObjects) CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []completePart) (ObjectInfo, error) {
+	if _, ok := l.isMounted(bucket); ok {
+		return ObjectInfo{}, traceError(BucketReadOnly{Bucket: bucket})
+	}
+	return l.ObjectLayer.CompleteMultipartUpload(bucket, object, uploadID, uploadedParts)
+}
+
+// unwrapObjectLayer - returns the object layer wrapped by the
+// bucket mount, name normalization and FUSE compatibility layers.
+func unwrapObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	for {
+		switch l := objAPI.(type) {
+		case nfcObjects:
+			objAPI = l.ObjectLayer
+		case bucketMountObjects:
+			objAPI = l.ObjectLayer
+		case fuseObjects:
+			objAPI = l.ObjectLayer
+		default:
+			return objAPI
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests validation of bucket mount configurations.
+func TestValidateBucketMounts(t *testing.T) {
+	dir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	if err := mkdirAll(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		mounts     []bucketMountConfig
+		shouldPass bool
+	}{
+		{nil, true},
+		{[]bucketMountConfig{{"datasets", dir}}, true},
+		{[]bucketMountConfig{{"Data_Sets", dir}}, false},
+		{[]bucketMountConfig{{minioMetaBucket, dir}}, false},
+		{[]bucketMountConfig{{"datasets", dir}, {"datasets", dir}}, false},
+		{[]bucketMountConfig{{"datasets", "relative/path"}}, false},
+		{[]bucketMountConfig{{"datasets", filepath.Join(dir, "missing")}}, false},
+		{[]bucketMountConfig{{"datasets", file}}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateBucketMounts(testCase.mounts)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// Tests serving a read-only bucket mount next to regular buckets.
+func TestBucketMountObjects(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	fsObj := initFSObjects(disk, t)
+	defer removeAll(getConfigDir())
+
+	mountDir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(mountDir)
+	data := []byte("hello world")
+	for _, name := range []string{"a.txt", "photos/b.jpg", "photos/2017/c.jpg"} {
+		if err := mkdirAll(filepath.Dir(pathJoin(mountDir, name)), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(pathJoin(mountDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := fsObj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newBucketMountObjectLayer(fsObj, []bucketMountConfig{{"bucket", mountDir}}); err == nil {
+		t.Fatal("Expected mount shadowing an existing bucket to fail")
+	}
+	obj, err := newBucketMountObjectLayer(fsObj, []bucketMountConfig{{"datasets", mountDir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := obj.ListBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 2 || buckets[0].Name != "bucket" || buckets[1].Name != "datasets" {
+		t.Fatalf("Unexpected buckets %v", buckets)
+	}
+	if _, ok := errorCause(obj.MakeBucket("datasets")).(BucketExists); !ok {
+		t.Fatal("Expected BucketExists")
+	}
+
+	// Reads.
+	objInfo, err := obj.GetObjectInfo("datasets", "photos/b.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(data)) || objInfo.ContentType != "image/jpeg" {
+		t.Fatalf("Unexpected object info %v", objInfo)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject("datasets", "photos/b.jpg", 6, 5, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "world" {
+		t.Fatalf("Expected \"world\", got %q", buf.String())
+	}
+	if _, err = obj.GetObjectInfo("datasets", "../"+filepath.Base(disk)+"/bucket"); err == nil {
+		t.Fatal("Expected objects outside of the mount to be inaccessible")
+	}
+	if _, err = obj.GetObjectInfo("datasets", "missing"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+
+	// Listing.
+	result, err := obj.ListObjects("datasets", "", "", slashSeparator, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "a.txt" || !reflect.DeepEqual(result.Prefixes, []string{"photos/"}) {
+		t.Fatalf("Unexpected listing %v", result)
+	}
+	var names []string
+	marker := ""
+	for {
+		result, err = obj.ListObjects("datasets", "", marker, "", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, objInfo := range result.Objects {
+			names = append(names, objInfo.Name)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	if !reflect.DeepEqual(names, []string{"a.txt", "photos/2017/c.jpg", "photos/b.jpg"}) {
+		t.Fatalf("Unexpected paginated listing %v", names)
+	}
+
+	// Writes are rejected.
+	readOnly := func(err error) bool {
+		_, ok := errorCause(err).(BucketReadOnly)
+		return ok
+	}
+	if _, err = obj.PutObject("datasets", "new", int64(len(data)), bytes.NewReader(data), nil, ""); !readOnly(err) {
+		t.Fatalf("Expected BucketReadOnly, got %v", err)
+	}
+	if err = obj.DeleteObject("datasets", "a.txt"); !readOnly(err) {
+		t.Fatalf("Expected BucketReadOnly, got %v", err)
+	}
+	if err = obj.DeleteBucket("datasets"); !readOnly(err) {
+		t.Fatalf("Expected BucketReadOnly, got %v", err)
+	}
+	if _, err = obj.NewMultipartUpload("datasets", "new", nil); !readOnly(err) {
+		t.Fatalf("Expected BucketReadOnly, got %v", err)
+	}
+	if _, err = obj.CopyObject("bucket", "a.txt", "datasets", "copy", nil); !readOnly(err) {
+		t.Fatalf("Expected BucketReadOnly, got %v", err)
+	}
+
+	// Copying out of the mount into a regular bucket.
+	if _, err = obj.CopyObject("datasets", "a.txt", "bucket", "copy", nil); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err = obj.GetObject("bucket", "copy", 0, -1, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("Expected %q, got %q", data, buf.Bytes())
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// bucketNetworkConfig - client networks allowed to access a bucket.
+type bucketNetworkConfig struct {
+	// Only clients in these networks, CIDRs or addresses, may access
+	// the bucket, any client if empty.
+	Allow []string `json:"allow"`
+
+	// Clients in these networks, CIDRs or addresses, may not access
+	// the bucket, even if allowed.
+	Deny []string `json:"deny"`
+}
+
+// isEmpty - returns true if no network is allowed or denied.
+func (config bucketNetworkConfig) isEmpty() bool {
+	return len(config.Allow) == 0 && len(config.Deny) == 0
+}
+
+// parseNetwork - parses a CIDR, or an address as a single address
+// network.
+func parseNetwork(network string) (*net.IPNet, error) {
+	if ip := net.ParseIP(network); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(network)
+	return ipNet, err
+}
+
+// validateBucketNetwork - validates the networks of a bucket.
+func validateBucketNetwork(config bucketNetworkConfig) error {
+	for _, networks := range []struct {
+		key    string
+		values []string
+	}{
+		{"allow", config.Allow},
+		{"deny", config.Deny},
+	} {
+		for _, network := range networks.values {
+			if _, err := parseNetwork(network); err != nil {
+				return fmt.Errorf("%s: invalid network %s", networks.key, network)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBucketNetworks - validates the networks of all buckets.
+func validateBucketNetworks(configs map[string]bucketNetworkConfig) error {
+	for bucket, config := range configs {
+		if !IsValidBucketName(bucket) {
+			return errors.New("invalid bucket name " + bucket)
+		}
+		if err := validateBucketNetwork(config); err != nil {
+			return fmt.Errorf("%s: %v", bucket, err)
+		}
+	}
+	return nil
+}
+
+// containsIP - returns true if ip is in one of networks, invalid
+// networks are ignored.
+func containsIP(networks []string, ip net.IP) bool {
+	for _, network := range networks {
+		if ipNet, err := parseNetwork(network); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowed - returns true if a client at ip may access the bucket.
+// Denied networks take precedence over allowed ones.
+func (config bucketNetworkConfig) isAllowed(ip net.IP) bool {
+	if ip == nil {
+		return config.isEmpty()
+	}
+	if containsIP(config.Deny, ip) {
+		return false
+	}
+	return len(config.Allow) == 0 || containsIP(config.Allow, ip)
+}
+
+// getRequestBucket - returns the bucket of S3 API requests and of
+// browser uploads and downloads, "" for other requests.
+func getRequestBucket(r *http.Request) string {
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	if bucket != minioReservedBucket {
+		return bucket
+	}
+	for _, prefix := range []string{"upload", "download"} {
+		if strings.HasPrefix(object, prefix+slashSeparator) {
+			bucket, _ = path2BucketAndObject(strings.TrimPrefix(object, prefix))
+			return bucket
+		}
+	}
+	return ""
+}
+
+// getCopySourceBucket - returns the bucket of the copy source of S3
+// CopyObject and CopyObjectPart requests, "" for other requests.
+func getCopySourceBucket(r *http.Request) string {
+	cpSrcPath := r.Header.Get("X-Amz-Copy-Source")
+	if cpSrcPath == "" {
+		return ""
+	}
+	if unescaped, err := url.QueryUnescape(cpSrcPath); err == nil {
+		cpSrcPath = unescaped
+	}
+	bucket, _ := path2BucketAndObject(cpSrcPath)
+	return bucket
+}
+
+// getRequestIP - returns the address of the client of r, nil if
+// unknown.
+func getRequestIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// errBucketNetworkDenied - the client is outside of the networks
+// allowed to access the bucket.
+var errBucketNetworkDenied = errors.New("Access to the bucket is denied from this network")
+
+// isBucketNetworkAllowed - returns true if the client of r may access
+// bucket.
+func isBucketNetworkAllowed(r *http.Request, bucket string) bool {
+	if serverConfig == nil || bucket == "" {
+		return true
+	}
+	config, ok := serverConfig.GetBucketNetworks()[bucket]
+	return !ok || config.isAllowed(getRequestIP(r))
+}
+
+// Rejects requests to buckets, or copying from buckets, from clients
+// outside of their allowed networks. Browser RPC calls and zip
+// downloads name the bucket in their body, they are checked by their
+// handlers.
+type bucketNetworkHandler struct {
+	handler http.Handler
+}
+
+func setBucketNetworkHandler(h http.Handler) http.Handler {
+	return bucketNetworkHandler{h}
+}
+
+func (h bucketNetworkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, bucket := range []string{getRequestBucket(r), getCopySourceBucket(r)} {
+		if !isBucketNetworkAllowed(r, bucket) {
+			writeErrorResponse(w, ErrAccessDenied, r)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// setBucketNetwork - sets the networks allowed to access bucket from
+// now on, removes them if empty, and saves them in the config.
+func setBucketNetwork(bucket string, config bucketNetworkConfig) error {
+	serverConfig.SetBucketNetwork(bucket, config)
+	return serverConfig.Save()
+}
@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validation of bucket networks.
+func TestValidateBucketNetworks(t *testing.T) {
+	testCases := []struct {
+		networks map[string]bucketNetworkConfig
+		success  bool
+	}{
+		{nil, true},
+		{map[string]bucketNetworkConfig{"bucket": {Allow: []string{"10.0.0.0/8", "192.168.1.1"}, Deny: []string{"fd00::/8"}}}, true},
+		{map[string]bucketNetworkConfig{"bucket": {Allow: []string{"10.0.0.0/33"}}}, false},
+		{map[string]bucketNetworkConfig{"bucket": {Deny: []string{"example.com"}}}, false},
+		{map[string]bucketNetworkConfig{"B": {Allow: []string{"10.0.0.0/8"}}}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateBucketNetworks(testCase.networks)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests which clients bucket networks allow.
+func TestBucketNetworkIsAllowed(t *testing.T) {
+	testCases := []struct {
+		config  bucketNetworkConfig
+		ip      string
+		allowed bool
+	}{
+		{bucketNetworkConfig{}, "203.0.113.1", true},
+		{bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}}, "10.1.2.3", true},
+		{bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}}, "203.0.113.1", false},
+		{bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1"}}, "10.0.0.1", false},
+		{bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1"}}, "10.0.0.2", true},
+		{bucketNetworkConfig{Deny: []string{"203.0.113.0/24"}}, "203.0.113.1", false},
+		{bucketNetworkConfig{Deny: []string{"203.0.113.0/24"}}, "10.0.0.1", true},
+		{bucketNetworkConfig{Allow: []string{"fd00::/8"}}, "fd00::1", true},
+		{bucketNetworkConfig{Allow: []string{"::1"}}, "127.0.0.1", false},
+		{bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}}, "", false},
+	}
+	for i, testCase := range testCases {
+		if allowed := testCase.config.isAllowed(net.ParseIP(testCase.ip)); allowed != testCase.allowed {
+			t.Errorf("Test %d: Expected %v for %s, got %v", i+1, testCase.allowed, testCase.ip, allowed)
+		}
+	}
+}
+
+// Tests the bucket of requests subject to bucket networks.
+func TestGetRequestBucket(t *testing.T) {
+	testCases := []struct {
+		path   string
+		bucket string
+	}{
+		{"/", ""},
+		{"/bucket", "bucket"},
+		{"/bucket/dir/object", "bucket"},
+		{"/minio/upload/bucket/object", "bucket"},
+		{"/minio/download/bucket/object", "bucket"},
+		{"/minio/webrpc", ""},
+		{"/minio/admin/v1/config", ""},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", "http://127.0.0.1:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bucket := getRequestBucket(req); bucket != testCase.bucket {
+			t.Errorf("Test %d: Expected bucket %q, got %q", i+1, testCase.bucket, bucket)
+		}
+	}
+}
+
+// Tests that requests from clients outside of the networks of a bucket
+// are rejected.
+func TestBucketNetworkHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	if err = setBucketNetwork("internal", bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}}); err != nil {
+		t.Fatal(err)
+	}
+	handler := setBucketNetworkHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	testCases := []struct {
+		path       string
+		copySource string
+		remoteAddr string
+		expectCode int
+	}{
+		{"/internal/object", "", "10.1.2.3:5000", http.StatusOK},
+		{"/internal/object", "", "203.0.113.1:5000", http.StatusForbidden},
+		{"/minio/download/internal/object", "", "203.0.113.1:5000", http.StatusForbidden},
+		{"/public/object", "", "203.0.113.1:5000", http.StatusOK},
+		// Copying from a bucket is restricted like reading it.
+		{"/public/object", "/internal/object", "10.1.2.3:5000", http.StatusOK},
+		{"/public/object", "/internal/object", "203.0.113.1:5000", http.StatusForbidden},
+		{"/public/object", "internal%2Fobject", "203.0.113.1:5000", http.StatusForbidden},
+		{"/public/object", "/public/other", "203.0.113.1:5000", http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		req, rErr := http.NewRequest("GET", "http://127.0.0.1:9000"+testCase.path, nil)
+		if rErr != nil {
+			t.Fatal(rErr)
+		}
+		if testCase.copySource != "" {
+			req.Header.Set("X-Amz-Copy-Source", testCase.copySource)
+		}
+		req.RemoteAddr = testCase.remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectCode, rec.Code)
+		}
+	}
+
+	// Networks are saved, and removed when empty.
+	if err = loadConfig(envParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := serverConfig.GetBucketNetworks()["internal"]; !ok {
+		t.Fatalf("Expected bucket networks to be saved, got %v", serverConfig.GetBucketNetworks())
+	}
+	if err = setBucketNetwork("internal", bucketNetworkConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := serverConfig.GetBucketNetworks()["internal"]; ok {
+		t.Fatalf("Expected bucket networks to be removed, got %v", serverConfig.GetBucketNetworks())
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// noOverwriteConfig - buckets whose objects cannot be overwritten once
+// written, e.g. buckets of audit logs. Deleting objects is governed by
+// the bucket policy as usual.
+type noOverwriteConfig struct {
+	// Buckets denying overwrites, '*' wildcards are supported.
+	Buckets []string `json:"buckets"`
+}
+
+// errObjectOverwriteDenied - the object exists in a bucket denying
+// overwrites.
+var errObjectOverwriteDenied = errors.New("Object already exists and the bucket does not allow overwrites")
+
+// isNoOverwriteBucket - returns true if bucket denies overwrites.
+func isNoOverwriteBucket(bucket string) bool {
+	if serverConfig == nil || isMinioMetaBucketName(bucket) {
+		return false
+	}
+	for _, pattern := range serverConfig.GetNoOverwrite().Buckets {
+		if wildcard.MatchSimple(pattern, bucket) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkObjectOverwrite - returns errObjectOverwriteDenied if object
+// exists in a bucket denying overwrites. Must be called with the write
+// lock of the object held, so that the object is not created before it
+// is written.
+func checkObjectOverwrite(objAPI ObjectLayer, bucket, object string) error {
+	if !isNoOverwriteBucket(bucket) {
+		return nil
+	}
+	_, err := objAPI.GetObjectInfo(bucket, object)
+	if err == nil {
+		return errObjectOverwriteDenied
+	}
+	if isErrObjectNotFound(err) {
+		return nil
+	}
+	return err
+}
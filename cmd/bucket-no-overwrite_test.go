@@ -0,0 +1,113 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling no overwrite tests for both XL multiple disks and single node setup.
+func TestNoOverwrite(t *testing.T) {
+	ExecObjectLayerAPITest(t, testNoOverwrite, []string{"CopyObject", "PutObject", "CompleteMultipart"})
+}
+
+// testNoOverwrite - Tests that objects of buckets denying overwrites
+// are written once.
+func testNoOverwrite(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+
+	sendRequest := func(method, url string, body []byte, header map[string]string) int {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create request: <ERROR> %v", instanceType, err)
+		}
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		if len(header) > 0 {
+			if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+				t.Fatalf("%s: Failed to sign request: <ERROR> %v", instanceType, err)
+			}
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	putObject := func(object string) int {
+		return sendRequest("PUT", getPutObjectURL("", bucketName, object), data, nil)
+	}
+	copyObject := func(object string) int {
+		return sendRequest("PUT", getCopyObjectURL("", bucketName, object), nil, map[string]string{
+			"X-Amz-Copy-Source": "/" + bucketName + "/source",
+		})
+	}
+	completeMultipart := func(object string) int {
+		uploadID, err := obj.NewMultipartUpload(bucketName, object, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create upload: <ERROR> %v", instanceType, err)
+		}
+		part, err := obj.PutObjectPart(bucketName, object, uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+		if err != nil {
+			t.Fatalf("%s: Failed to upload part: <ERROR> %v", instanceType, err)
+		}
+		completeBytes, err := xml.Marshal(&completeMultipartUpload{Parts: []completePart{{PartNumber: 1, ETag: part.ETag}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sendRequest("POST", getCompleteMultipartUploadURL("", bucketName, object, uploadID), completeBytes, nil)
+	}
+
+	if code := putObject("source"); code != http.StatusOK {
+		t.Fatalf("%s: Expected upload to succeed, got %d", instanceType, code)
+	}
+
+	serverConfig.SetNoOverwrite(noOverwriteConfig{Buckets: []string{bucketName[:3] + "*"}})
+	defer serverConfig.SetNoOverwrite(noOverwriteConfig{})
+
+	testCases := []struct {
+		write func(object string) int
+	}{
+		{putObject},
+		{copyObject},
+		{completeMultipart},
+	}
+	for i, testCase := range testCases {
+		object := "object" + string(rune('a'+i))
+		// New objects are written.
+		if code := testCase.write(object); code != http.StatusOK {
+			t.Errorf("%s: Test %d: Expected write of new object to succeed, got %d", instanceType, i+1, code)
+		}
+		// Existing objects are not overwritten.
+		if code := testCase.write(object); code != http.StatusPreconditionFailed {
+			t.Errorf("%s: Test %d: Expected overwrite to fail with %d, got %d", instanceType, i+1, http.StatusPreconditionFailed, code)
+		}
+	}
+	if code := copyObject("source"); code != http.StatusPreconditionFailed {
+		t.Errorf("%s: Expected copy onto itself to fail with %d, got %d", instanceType, http.StatusPreconditionFailed, code)
+	}
+
+	// Other buckets allow overwrites.
+	serverConfig.SetNoOverwrite(noOverwriteConfig{Buckets: []string{"other-*"}})
+	if code := putObject("source"); code != http.StatusOK {
+		t.Errorf("%s: Expected overwrite to succeed, got %d", instanceType, code)
+	}
+}
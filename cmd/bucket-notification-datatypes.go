@@ -163,7 +163,8 @@ const (
 	// ref: http://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
 	eventSource = "aws:s3"
 
-	// Event version number defaulting to the value in S3 spec.
+	// Event version number of the S3 2.0 schema, kept for server
+	// events and notification events in compat mode.
 	// ref: http://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
 	eventVersion = "2.0"
 )
@@ -179,6 +180,7 @@ type NotificationEvent struct {
 	RequestParameters map[string]string `json:"requestParameters"`
 	ResponseElements  map[string]string `json:"responseElements"`
 	S3                eventMeta         `json:"s3"`
+	GlacierEventData  *glacierEventData `json:"glacierEventData,omitempty"`
 }
 
 // Represents the minio sqs type and account id's.
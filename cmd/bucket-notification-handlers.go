@@ -42,12 +42,12 @@ const (
 func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
 	objAPI := api.ObjectAPI()
 	if objAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:GetBucketNotification", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -57,7 +57,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -65,7 +65,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 	nConfig, err := loadNotificationConfig(bucket, objAPI)
 	if err != nil && err != errNoSuchNotifications {
 		errorIf(err, "Unable to read notification configuration.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	// For no notifications we write a dummy XML.
@@ -77,7 +77,7 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 	if err != nil {
 		// For any marshalling failure.
 		errorIf(err, "Unable to marshal notification configuration into XML.", err)
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -96,12 +96,12 @@ func (api objectAPIHandlers) GetBucketNotificationHandler(w http.ResponseWriter,
 func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:PutBucketNotification", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -111,14 +111,14 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 	_, err := objectAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// If Content-Length is unknown or zero, deny the request.
 	// PutBucketNotification always needs a Content-Length.
 	if r.ContentLength == -1 || r.ContentLength == 0 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 
@@ -131,7 +131,7 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 	}
 	if err != nil {
 		errorIf(err, "Unable to read incoming body.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -140,20 +140,20 @@ func (api objectAPIHandlers) PutBucketNotificationHandler(w http.ResponseWriter,
 	notificationConfigBytes := buffer.Bytes()
 	if err = xml.Unmarshal(notificationConfigBytes, &notificationCfg); err != nil {
 		errorIf(err, "Unable to parse notification configuration XML.")
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		writeErrorResponse(w, ErrMalformedXML, r)
 		return
 	} // Successfully marshalled notification configuration.
 
 	// Validate unmarshalled bucket notification configuration.
 	if s3Error := validateNotificationConfig(notificationCfg); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	// Put bucket notification config.
 	err = PutBucketNotificationConfig(bucket, &notificationCfg, objectAPI)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -248,12 +248,12 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 	// Validate if bucket exists.
 	objAPI := api.ObjectAPI()
 	if objAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:ListenBucketNotification", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -264,19 +264,19 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 	prefixes, suffixes, events := getListenBucketNotificationResources(r.URL.Query())
 
 	if err := validateFilterValues(prefixes); err != ErrNone {
-		writeErrorResponse(w, err, r.URL)
+		writeErrorResponse(w, err, r)
 		return
 	}
 
 	if err := validateFilterValues(suffixes); err != ErrNone {
-		writeErrorResponse(w, err, r.URL)
+		writeErrorResponse(w, err, r)
 		return
 	}
 
 	// Validate all the resource events.
 	for _, event := range events {
 		if errCode := checkEvent(event); errCode != ErrNone {
-			writeErrorResponse(w, errCode, r.URL)
+			writeErrorResponse(w, errCode, r)
 			return
 		}
 	}
@@ -284,7 +284,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to get bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -337,7 +337,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 	// Add channel for listener events
 	if err = globalEventNotifier.AddListenerChan(accountARN, nEventCh); err != nil {
 		errorIf(err, "Error adding a listener!")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	// Remove listener channel after the writer has closed or the
@@ -354,7 +354,7 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 
 	err = AddBucketListenerConfig(bucket, &lc, objAPI)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	defer RemoveBucketListenerConfig(bucket, &lc, objAPI)
@@ -30,28 +30,45 @@ import (
 	"github.com/minio/minio/pkg/wildcard"
 )
 
-// maximum supported access policy size.
-const maxAccessPolicySize = 20 * humanize.KiByte
+const (
+	// maximum supported access policy size.
+	maxAccessPolicySize = 20 * humanize.KiByte
+
+	// default maximum number of statements in an access policy, a
+	// 20KiB policy cannot hold many more than this.
+	defaultMaxPolicyStatements = 256
+)
 
 // Verify if a given action is valid for the url path based on the
 // existing bucket access policy.
 func bucketPolicyEvalStatements(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) bool {
-	for _, statement := range statements {
-		if bucketPolicyMatchStatement(action, resource, conditions, statement) {
-			if statement.Effect == "Allow" {
-				return true
-			}
-			// Do not uncomment kept here for readability.
-			// else statement.Effect == "Deny"
-			return false
+	statement := bucketPolicyDecidingStatement(action, resource, conditions, statements)
+	if statement == nil {
+		// None match so deny.
+		return false
+	}
+	// Deny statements are always ordered before Allow statements,
+	// so the first matching statement decides.
+	return statement.Effect == "Allow"
+}
+
+// bucketPolicyDecidingStatement returns the first statement matching
+// action, resource and conditions, which decides whether the action
+// is allowed. Returns nil if no statement matches.
+func bucketPolicyDecidingStatement(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) *policyStatement {
+	for i := range statements {
+		if bucketPolicyMatchStatement(action, resource, conditions, statements[i]) {
+			return &statements[i]
 		}
 	}
-	// None match so deny.
-	return false
+	return nil
 }
 
 // Verify if action, resource and conditions match input policy statement.
 func bucketPolicyMatchStatement(action string, resource string, conditions map[string]set.StringSet, statement policyStatement) bool {
+	// Substitute policy variables in resources with request values.
+	statement.Resources = resolvePolicyVariables(statement.Resources, conditions)
+
 	// Verify if action, resource and condition match in given statement.
 	return (bucketPolicyActionMatch(action, statement) &&
 		bucketPolicyResourceMatch(resource, statement) &&
@@ -90,6 +107,44 @@ func bucketPolicyResourceMatch(resource string, statement policyStatement) bool
 	return !statement.Resources.FuncMatch(resourceMatch, resource).IsEmpty()
 }
 
+// resolvePolicyVariables - substitutes policy variables such as
+// ${aws:username} in resources with their values in conditions. A
+// resource referring to a variable without a value can never match,
+// so it is dropped.
+func resolvePolicyVariables(resources set.StringSet, conditions map[string]set.StringSet) set.StringSet {
+	if resources.FuncMatch(func(resource, _ string) bool {
+		return hasPolicyVariables(resource)
+	}, "").IsEmpty() {
+		// Nothing to substitute.
+		return resources
+	}
+
+	resolved := set.NewStringSet()
+	for resource := range resources {
+		if !hasPolicyVariables(resource) {
+			resolved.Add(resource)
+			continue
+		}
+		unresolved := false
+		resource = policyVariableRegex.ReplaceAllStringFunc(resource, func(variable string) string {
+			values := conditions[policyVariableRegex.FindStringSubmatch(variable)[1]]
+			if len(values) == 1 {
+				for value := range values {
+					if value != "" {
+						return value
+					}
+				}
+			}
+			unresolved = true
+			return variable
+		})
+		if !unresolved {
+			resolved.Add(resource)
+		}
+	}
+	return resolved
+}
+
 // Verify if given condition matches with policy statement.
 func bucketPolicyConditionMatch(conditions map[string]set.StringSet, statement policyStatement) bool {
 	// Supports following conditions.
@@ -172,12 +227,12 @@ func bucketPolicyConditionMatch(conditions map[string]set.StringSet, statement p
 func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
 	objAPI := api.ObjectAPI()
 	if objAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:PutBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -188,19 +243,19 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// If Content-Length is unknown or zero, deny the
 	// request. PutBucketPolicy always needs a Content-Length.
 	if r.ContentLength == -1 || r.ContentLength == 0 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 	// If Content-Length is greater than maximum allowed policy size.
 	if r.ContentLength > maxAccessPolicySize {
-		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		writeErrorResponse(w, ErrEntityTooLarge, r)
 		return
 	}
 
@@ -210,13 +265,13 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAccessPolicySize))
 	if err != nil {
 		errorIf(err, "Unable to read from client.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	// Parse validate and save bucket policy.
 	if s3Error := parseAndPersistBucketPolicy(bucket, policyBytes, objAPI); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -231,12 +286,12 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
 	objAPI := api.ObjectAPI()
 	if objAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:DeleteBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -247,7 +302,7 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -256,9 +311,9 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 	if err := persistAndNotifyBucketPolicyChange(bucket, policyChange{true, nil}, objAPI); err != nil {
 		switch err.(type) {
 		case BucketPolicyNotFound:
-			writeErrorResponse(w, ErrNoSuchBucketPolicy, r.URL)
+			writeErrorResponse(w, ErrNoSuchBucketPolicy, r)
 		default:
-			writeErrorResponse(w, ErrInternalError, r.URL)
+			writeErrorResponse(w, ErrInternalError, r)
 		}
 		return
 	}
@@ -274,12 +329,12 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
 	objAPI := api.ObjectAPI()
 	if objAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	if s3Error := checkOwnerRequestAuthType(r, "s3:GetBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -290,7 +345,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
 		errorIf(err, "Unable to find bucket info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -300,9 +355,9 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 		errorIf(err, "Unable to read bucket policy.")
 		switch err.(type) {
 		case BucketPolicyNotFound:
-			writeErrorResponse(w, ErrNoSuchBucketPolicy, r.URL)
+			writeErrorResponse(w, ErrNoSuchBucketPolicy, r)
 		default:
-			writeErrorResponse(w, ErrInternalError, r.URL)
+			writeErrorResponse(w, ErrInternalError, r)
 		}
 		return
 	}
@@ -84,6 +84,66 @@ func TestBucketPolicyResourceMatch(t *testing.T) {
 	}
 }
 
+// Tests validate substitution of policy variables in resources.
+func TestResolvePolicyVariables(t *testing.T) {
+	homeResource := bucketARNPrefix + "minio-bucket/home/${aws:username}/*"
+	publicResource := bucketARNPrefix + "minio-bucket/public/*"
+
+	testCases := []struct {
+		conditions       map[string]set.StringSet
+		resourceToMatch  string
+		expectedResource set.StringSet
+		expectedMatch    bool
+	}{
+		// Test case - 1.
+		// Variable is substituted with its value.
+		{
+			map[string]set.StringSet{"aws:username": set.CreateStringSet("alice")},
+			bucketARNPrefix + "minio-bucket/home/alice/photo.jpg",
+			set.CreateStringSet(bucketARNPrefix+"minio-bucket/home/alice/*", publicResource),
+			true,
+		},
+		// Test case - 2.
+		// Value of the variable does not grant access to other homes.
+		{
+			map[string]set.StringSet{"aws:username": set.CreateStringSet("alice")},
+			bucketARNPrefix + "minio-bucket/home/bob/photo.jpg",
+			set.CreateStringSet(bucketARNPrefix+"minio-bucket/home/alice/*", publicResource),
+			false,
+		},
+		// Test case - 3.
+		// Resources with unresolved variables are dropped.
+		{
+			map[string]set.StringSet{},
+			bucketARNPrefix + "minio-bucket/home/${aws:username}/photo.jpg",
+			set.CreateStringSet(publicResource),
+			false,
+		},
+		// Test case - 4.
+		// Empty values do not resolve variables.
+		{
+			map[string]set.StringSet{"aws:username": set.CreateStringSet("")},
+			bucketARNPrefix + "minio-bucket/home//photo.jpg",
+			set.CreateStringSet(publicResource),
+			false,
+		},
+	}
+	for i, testCase := range testCases {
+		resources := resolvePolicyVariables(set.CreateStringSet(homeResource, publicResource), testCase.conditions)
+		if !resources.Equals(testCase.expectedResource) {
+			t.Errorf("Test %d: Expected resources %v, got %v", i+1, testCase.expectedResource, resources)
+		}
+		statement := policyStatement{
+			Actions:   set.CreateStringSet("s3:GetObject"),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(homeResource, publicResource),
+		}
+		if match := bucketPolicyMatchStatement("s3:GetObject", testCase.resourceToMatch, testCase.conditions, statement); match != testCase.expectedMatch {
+			t.Errorf("Test %d: Expected statement match to be `%v`, got `%v`", i+1, testCase.expectedMatch, match)
+		}
+	}
+}
+
 // TestBucketPolicyActionMatch - Test validates whether given action on the
 // bucket/object matches the allowed actions in policyStatement.
 // This test preserves the allowed actions for all 3 sets of policies, that is read-write,read-only, write-only.
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -37,7 +38,8 @@ var conditionKeyActionMap = map[string]set.StringSet{
 // supportedActionMap - lists all the actions supported by minio.
 var supportedActionMap = set.CreateStringSet("*", "s3:*", "s3:GetObject",
 	"s3:ListBucket", "s3:PutObject", "s3:GetBucketLocation", "s3:DeleteObject",
-	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts")
+	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts",
+	"s3:ListAllMyBuckets", "s3:GetObjectVersion", "s3:DeleteObjectVersion", "s3:ListBucketVersions")
 
 // supported Conditions type.
 var supportedConditionsType = set.CreateStringSet("StringEquals", "StringNotEquals", "StringLike", "StringNotLike")
@@ -46,6 +48,17 @@ var supportedConditionsType = set.CreateStringSet("StringEquals", "StringNotEqua
 // supported keys for the conditions.
 var supportedConditionsKey = set.CreateStringSet("s3:prefix", "s3:max-keys", "aws:Referer")
 
+// supportedPolicyVariables - policy variables which may be used in
+// statement resources, refer
+// http://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_variables.html
+var supportedPolicyVariables = set.CreateStringSet("aws:username", "aws:userid")
+
+// Policy variables are only honored with this policy version.
+const policyVariablesVersion = "2012-10-17"
+
+// Matches a policy variable of the form ${aws:username}.
+var policyVariableRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+
 // supportedEffectMap - supported effects.
 var supportedEffectMap = set.CreateStringSet("Allow", "Deny")
 
@@ -120,10 +133,35 @@ func isValidResources(resources set.StringSet) (err error) {
 			err = errors.New("Invalid resource style found: ‘" + resource + "’, please validate your policy document")
 			return err
 		}
+		if err = isValidPolicyVariables(resourceSuffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isValidPolicyVariables - policy variables in a resource should be
+// supported and may only appear in the object part of the resource.
+func isValidPolicyVariables(resourceSuffix string) error {
+	if !hasPolicyVariables(resourceSuffix) {
+		return nil
+	}
+	if hasPolicyVariables(strings.Split(resourceSuffix, "/")[0]) {
+		return errors.New("Policy variables are not allowed in bucket name: ‘" + resourceSuffix + "’, please validate your policy document")
+	}
+	for _, match := range policyVariableRegex.FindAllStringSubmatch(resourceSuffix, -1) {
+		if !supportedPolicyVariables.Contains(match[1]) {
+			return errors.New("Unsupported policy variable found: ‘" + match[0] + "’, please validate your policy document")
+		}
 	}
 	return nil
 }
 
+// hasPolicyVariables - returns true if resource has any policy variables.
+func hasPolicyVariables(resource string) bool {
+	return strings.Contains(resource, "${") && policyVariableRegex.MatchString(resource)
+}
+
 // Parse principals parses a incoming json. Handles cases for
 // these three combinations.
 // - "Principal": "*",
@@ -175,7 +213,7 @@ func isValidPrincipals(principal interface{}) (err error) {
 		return err
 	}
 	if unsuppPrincipals := principals.Difference(set.CreateStringSet([]string{"*"}...)); !unsuppPrincipals.IsEmpty() {
-		// Minio does not support or implement IAM, "*" is the only valid value.
+		// IAM users are not principals of bucket policies, "*" is the only valid value.
 		// Amazon s3 doc on principals: http://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements.html#Principal
 		err = fmt.Errorf("Unsupported principals found: ‘%#v’, please validate your policy document", unsuppPrincipals)
 		return err
@@ -225,6 +263,7 @@ var invalidPrefixActions = set.StringSet{
 	"s3:GetBucketLocation":          {},
 	"s3:ListBucket":                 {},
 	"s3:ListBucketMultipartUploads": {},
+	"s3:ListAllMyBuckets":           {},
 	// Add actions which do not honor prefixes.
 }
 
@@ -292,6 +331,17 @@ func checkBucketPolicyResources(bucket string, bucketPolicy *bucketPolicy) APIEr
 	return ErrNone
 }
 
+// checkBucketPolicyLimits validates the complexity of an unmarshalled
+// bucket policy against the configured limits. Limits are enforced
+// only when a new policy is set, policies already persisted continue
+// to load even if the limits are lowered later.
+func checkBucketPolicyLimits(bucketPolicy *bucketPolicy) APIErrorCode {
+	if len(bucketPolicy.Statements) > globalMaxPolicyStatements {
+		return ErrPolicyTooManyStatements
+	}
+	return ErrNone
+}
+
 // parseBucketPolicy - parses and validates if bucket policy is of
 // proper JSON and follows allowed restrictions with policy standards.
 func parseBucketPolicy(bucketPolicyReader io.Reader, policy *bucketPolicy) (err error) {
@@ -313,16 +363,25 @@ func parseBucketPolicy(bucketPolicyReader io.Reader, policy *bucketPolicy) (err
 		return err
 	}
 
+	// Statement principals should be supported format.
+	for _, statement := range policy.Statements {
+		if err := isValidPrincipals(statement.Principal); err != nil {
+			return err
+		}
+	}
+	return validatePolicyStatements(policy, isValidActions)
+}
+
+// validatePolicyStatements - validates the statements of policy except
+// their principals, with isValidActions validating their actions.
+// Deny statements are ordered before Allow statements on success.
+func validatePolicyStatements(policy *bucketPolicy, isValidActions func(set.StringSet) error) error {
 	// Loop through all policy statements and validate entries.
 	for _, statement := range policy.Statements {
 		// Statement effect should be valid.
 		if err := isValidEffect(statement.Effect); err != nil {
 			return err
 		}
-		// Statement principal should be supported format.
-		if err := isValidPrincipals(statement.Principal); err != nil {
-			return err
-		}
 		// Statement actions should be valid.
 		if err := isValidActions(statement.Actions); err != nil {
 			return err
@@ -335,6 +394,15 @@ func parseBucketPolicy(bucketPolicyReader io.Reader, policy *bucketPolicy) (err
 		if err := isValidConditions(statement.Actions, statement.Conditions); err != nil {
 			return err
 		}
+		// Policy variables are only valid with the version which
+		// introduced them.
+		if policy.Version != policyVariablesVersion {
+			for resource := range statement.Resources {
+				if hasPolicyVariables(resource) {
+					return fmt.Errorf("Policy variables require policy version ‘%s’, please validate your policy document", policyVariablesVersion)
+				}
+			}
+		}
 	}
 
 	// Separate deny and allow statements, so that we can apply deny
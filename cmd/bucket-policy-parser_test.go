@@ -279,10 +279,18 @@ func TestIsValidResources(t *testing.T) {
 		// Valid resource shouldn't have slash('/') followed by bucketARNPrefix.
 		{[]string{bucketARNPrefix + "/"}, errors.New("Invalid resource style found: ‘arn:aws:s3:::/’, please validate your policy document"), false},
 
+		// Test Case - 5.
+		// Policy variables are not allowed in bucket name.
+		{[]string{bucketARNPrefix + "${aws:username}/*"}, errors.New("Policy variables are not allowed in bucket name: ‘${aws:username}/*’, please validate your policy document"), false},
+		// Test Case - 6.
+		// Unsupported policy variable.
+		{[]string{bucketARNPrefix + "my-bucket/${aws:SourceIp}/*"}, errors.New("Unsupported policy variable found: ‘${aws:SourceIp}’, please validate your policy document"), false},
+
 		// Test cases with valid Resources.
 		{[]string{bucketARNPrefix + "my-bucket"}, nil, true},
 		{[]string{bucketARNPrefix + "my-bucket/Asia/*"}, nil, true},
 		{[]string{bucketARNPrefix + "my-bucket/Asia/India/*"}, nil, true},
+		{[]string{bucketARNPrefix + "my-bucket/home/${aws:username}/*"}, nil, true},
 	}
 	for i, testCase := range testCases {
 		err := isValidResources(set.CreateStringSet(testCase.resources...))
@@ -787,3 +795,53 @@ func TestAWSRefererCondition(t *testing.T) {
 		}
 	}
 }
+
+// Tests validate bucket policy statement limits.
+func TestCheckBucketPolicyLimits(t *testing.T) {
+	defer func(maxStatements int) { globalMaxPolicyStatements = maxStatements }(globalMaxPolicyStatements)
+	globalMaxPolicyStatements = 2
+
+	testCases := []struct {
+		statements []policyStatement
+		apiErrCode APIErrorCode
+	}{
+		// Test case - 1.
+		// statements within limit.
+		{getReadOnlyStatement("minio-bucket", ""), ErrNone},
+		// Test case - 2.
+		// statements exceeding limit.
+		{append(getReadOnlyStatement("minio-bucket", ""), getWriteOnlyObjectStatement("minio-bucket", "")), ErrPolicyTooManyStatements},
+	}
+	for i, testCase := range testCases {
+		policy := &bucketPolicy{Version: "1.0", Statements: testCase.statements}
+		if apiErrCode := checkBucketPolicyLimits(policy); apiErrCode != testCase.apiErrCode {
+			t.Errorf("Test %d: Expected error code %v, got %v", i+1, testCase.apiErrCode, apiErrCode)
+		}
+	}
+}
+
+// Tests validate policy variables are only accepted with supported policy version.
+func TestParseBucketPolicyVariables(t *testing.T) {
+	testCases := []struct {
+		version    string
+		shouldPass bool
+	}{
+		{policyVariablesVersion, true},
+		{"2008-10-17", false},
+	}
+	for i, testCase := range testCases {
+		statement := getReadOnlyObjectStatement("minio-bucket", "home/${aws:username}/")
+		policy := bucketPolicy{Version: testCase.version, Statements: []policyStatement{statement}}
+		policyBytes, err := json.Marshal(policy)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to marshal bucket policy: %v", i+1, err)
+		}
+		err = parseBucketPolicy(bytes.NewReader(policyBytes), &bucketPolicy{})
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, but passed instead", i+1)
+		}
+	}
+}
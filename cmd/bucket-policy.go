@@ -19,6 +19,8 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"sync"
 )
@@ -219,17 +221,37 @@ func writeBucketPolicy(bucket string, objAPI ObjectLayer, bpy *bucketPolicy) err
 	return nil
 }
 
-func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI ObjectLayer) APIErrorCode {
+// validateBucketPolicy - parses and validates a bucket policy for the
+// given bucket without persisting it. On failure the returned error
+// describes the reason the policy was rejected.
+func validateBucketPolicy(bucket string, policyBytes []byte) (*bucketPolicy, APIErrorCode, error) {
 	// Parse bucket policy.
 	var policy = &bucketPolicy{}
 	err := parseBucketPolicy(bytes.NewReader(policyBytes), policy)
 	if err != nil {
-		errorIf(err, "Unable to parse bucket policy.")
-		return ErrInvalidPolicyDocument
+		return nil, ErrInvalidPolicyDocument, err
 	}
 
 	// Parse check bucket policy.
 	if s3Error := checkBucketPolicyResources(bucket, policy); s3Error != ErrNone {
+		return nil, s3Error, errors.New(getAPIError(s3Error).Description)
+	}
+
+	// Check bucket policy against configured limits.
+	if s3Error := checkBucketPolicyLimits(policy); s3Error != ErrNone {
+		return nil, s3Error, fmt.Errorf("Policy has %d statements, maximum allowed is %d",
+			len(policy.Statements), globalMaxPolicyStatements)
+	}
+
+	return policy, ErrNone, nil
+}
+
+func parseAndPersistBucketPolicy(bucket string, policyBytes []byte, objAPI ObjectLayer) APIErrorCode {
+	policy, s3Error, err := validateBucketPolicy(bucket, policyBytes)
+	if s3Error != ErrNone {
+		if s3Error == ErrInvalidPolicyDocument {
+			errorIf(err, "Unable to parse bucket policy.")
+		}
 		return s3Error
 	}
 
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"io"
+)
+
+// writeBucketTar - writes all objects of a bucket under prefix to w as
+// a tar archive. Objects are listed in batches of 1000 and copied one
+// at a time, so that memory use does not depend on the number or size
+// of the objects. Objects for which canRead returns false are left out.
+func writeBucketTar(objAPI ObjectLayer, w io.Writer, bucket, prefix string, canRead func(object string) bool) error {
+	tw := tar.NewWriter(w)
+	marker := ""
+	for {
+		lo, err := objAPI.ListObjects(bucket, prefix, marker, "", 1000)
+		if err != nil {
+			return err
+		}
+		for _, obj := range lo.Objects {
+			if !canRead(obj.Name) {
+				continue
+			}
+			if err = writeObjectTar(objAPI, tw, bucket, obj.Name); err != nil {
+				return err
+			}
+		}
+		if !lo.IsTruncated {
+			break
+		}
+		marker = lo.NextMarker
+	}
+	return tw.Close()
+}
+
+// writeObjectTar - writes an object as a tar entry named after it.
+// Objects deleted since they were listed are skipped.
+func writeObjectTar(objAPI ObjectLayer, tw *tar.Writer, bucket, object string) error {
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	header := &tar.Header{
+		Name:     object,
+		Mode:     0644,
+		Size:     objInfo.Size,
+		ModTime:  objInfo.ModTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err = tw.WriteHeader(header); err != nil {
+		return err
+	}
+	return objAPI.GetObject(bucket, object, 0, objInfo.Size, tw)
+}
@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetBucketVersioningHandler - GET Bucket versioning
+// ----------
+// Returns the versioning state of a bucket, no status is returned
+// for buckets on which versioning was never enabled.
+func (api objectAPIHandlers) GetBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkOwnerRequestAuthType(r, "s3:GetBucketVersioning", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	status, err := objectAPI.GetBucketVersioning(bucket)
+	if err != nil {
+		errorIf(err, "Unable to read bucket versioning.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(versioningConfiguration{Status: status}))
+}
+
+// PutBucketVersioningHandler - PUT Bucket versioning
+// ----------
+// Enables or suspends versioning of a bucket. Once enabled, versioning
+// can only be suspended.
+func (api objectAPIHandlers) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkOwnerRequestAuthType(r, "s3:PutBucketVersioning", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketVersioning always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	var config versioningConfiguration
+	if err := xml.Unmarshal(buffer.Bytes(), &config); err != nil {
+		errorIf(err, "Unable to parse versioning configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r)
+		return
+	}
+	if config.Status != versioningEnabled && config.Status != versioningSuspended {
+		writeErrorResponse(w, ErrMalformedXML, r)
+		return
+	}
+
+	if err := objectAPI.SetBucketVersioning(bucket, config.Status); err != nil {
+		errorIf(err, "Unable to set bucket versioning.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListObjectVersionsHandler - GET Bucket Object versions
+// --------------------------
+// Returns some or all (up to 1000) of the versions of the objects in a
+// bucket, newest first for each object.
+func (api objectAPIHandlers) ListObjectVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucketVersions", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	prefix, keyMarker, versionIDMarker, delimiter, maxKeys, _ := getListObjectVersionsArgs(r.URL.Query())
+
+	// Validate all the query params before beginning to serve the request.
+	if s3Error := validateListObjectsArgs(prefix, keyMarker, delimiter, maxKeys); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	if versionIDMarker != "" && (keyMarker == "" || !isValidVersionID(versionIDMarker)) {
+		writeErrorResponse(w, ErrInvalidVersionID, r)
+		return
+	}
+
+	listVersionsInfo, err := objectAPI.ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+	if err != nil {
+		errorIf(err, "Unable to list object versions.")
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+	response := generateListVersionsResponse(bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys, listVersionsInfo)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// setVersionIDHeader - sets the version id of objects written to
+// buckets with versioning enabled.
+func setVersionIDHeader(w http.ResponseWriter, objInfo ObjectInfo) {
+	if versionID := objInfo.UserDefined[versionIDKey]; versionID != "" {
+		w.Header().Set(versionIDKey, versionID)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling bucket versioning handler tests for both XL multiple disks and single node setup.
+func TestBucketVersioningHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketVersioningHandlers, []string{
+		"GetBucketVersioning", "PutBucketVersioning", "ListObjectVersions",
+		"GetObject", "PutObject", "DeleteObject",
+	})
+}
+
+// testBucketVersioningHandlers - tests enabling versioning and reading,
+// listing and deleting versions of objects through the S3 API.
+func testBucketVersioningHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "object"
+	serve := func(method, urlStr string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	versioningURL := makeTestTargetURL("", bucketName, "", url.Values{"versioning": []string{""}})
+	versionURL := func(versionID string) string {
+		return makeTestTargetURL("", bucketName, objectName, url.Values{"versionId": []string{versionID}})
+	}
+
+	// Invalid status is rejected.
+	rec := serve("PUT", versioningURL, []byte(`<VersioningConfiguration><Status>Disabled</Status></VersioningConfiguration>`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected invalid status to be rejected, got %d", instanceType, rec.Code)
+	}
+	rec = serve("PUT", versioningURL, []byte(`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Status>Enabled</Status></VersioningConfiguration>`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected versioning to be enabled, got %d: %s", instanceType, rec.Code, rec.Body.String())
+	}
+	rec = serve("GET", versioningURL, nil)
+	var config versioningConfiguration
+	if err := xml.Unmarshal(rec.Body.Bytes(), &config); err != nil || config.Status != versioningEnabled {
+		t.Fatalf("%s: Expected versioning to be enabled, got %s, %v", instanceType, rec.Body.String(), err)
+	}
+
+	var versionIDs []string
+	for _, data := range []string{"first", "second"} {
+		rec = serve("PUT", getPutObjectURL("", bucketName, objectName), []byte(data))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Failed to put object, got %d", instanceType, rec.Code)
+		}
+		versionIDs = append(versionIDs, rec.Header().Get(versionIDKey))
+	}
+	if versionIDs[0] == "" || versionIDs[0] == versionIDs[1] {
+		t.Fatalf("%s: Expected distinct version ids, got %v", instanceType, versionIDs)
+	}
+
+	rec = serve("GET", versionURL(versionIDs[0]), nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "first" || rec.Header().Get(versionIDKey) != versionIDs[0] {
+		t.Fatalf("%s: Expected first version, got %d: %s", instanceType, rec.Code, rec.Body.String())
+	}
+	if rec = serve("GET", versionURL("invalid"), nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected invalid version id to be rejected, got %d", instanceType, rec.Code)
+	}
+	if rec = serve("GET", versionURL(mustGetUUID()), nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected unknown version not to be found, got %d", instanceType, rec.Code)
+	}
+
+	rec = serve("GET", makeTestTargetURL("", bucketName, "", url.Values{"versions": []string{""}}), nil)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || strings.Count(body, "<Version>") != 2 || !strings.Contains(body, "<VersionId>"+versionIDs[0]+"</VersionId>") {
+		t.Fatalf("%s: Unexpected versions listing %d: %s", instanceType, rec.Code, body)
+	}
+
+	rec = serve("DELETE", versionURL(versionIDs[1]), nil)
+	if rec.Code != http.StatusNoContent || rec.Header().Get(versionIDKey) != versionIDs[1] {
+		t.Fatalf("%s: Failed to delete version, got %d", instanceType, rec.Code)
+	}
+	rec = serve("GET", getGetObjectURL("", bucketName, objectName), nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "first" {
+		t.Fatalf("%s: Expected previous version to be restored, got %d: %s", instanceType, rec.Code, rec.Body.String())
+	}
+}
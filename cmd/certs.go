@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
 )
@@ -136,3 +137,26 @@ func loadRootCAs() {
 		globalRootCAs.AppendCertsFromPEM(caCert)
 	}
 }
+
+var errNoRPCClientCAs = errors.New("No CA certificates found in the certs/CAs directory")
+
+// loadRPCClientCAs returns a pool of the CAs under certs/CAs only,
+// unlike globalRootCAs without the system CAs, so that certificates
+// issued by public CAs are not accepted from peers.
+func loadRPCClientCAs() (*x509.CertPool, error) {
+	caFiles := getCAFiles()
+	if len(caFiles) == 0 {
+		return nil, errNoRPCClientCAs
+	}
+	pool := x509.NewCertPool()
+	for _, caFile := range caFiles {
+		caCert, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("No PEM encoded certificate found in %s", caFile)
+		}
+	}
+	return pool, nil
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -106,3 +107,44 @@ func TestParseInvalidCertificateChain(t *testing.T) {
 		t.Fatalf("Expected error but none occurred")
 	}
 }
+
+// Tests loading the CAs authenticating inter-node RPC clients.
+func TestLoadRPCClientCAs(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// The system CAs are not used in place of missing CAs.
+	if _, err = loadRPCClientCAs(); err != errNoRPCClientCAs {
+		t.Fatalf("Expected %v, got %v", errNoRPCClientCAs, err)
+	}
+
+	caDir := filepath.Join(getCertsPath(), globalMinioCertsCADir)
+	if err = os.MkdirAll(caDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	caCert, _, err := generateTLSCertKey("node1.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(caDir, "ca.crt"), caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := loadRPCClientCAs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.Subjects()) != 1 {
+		t.Fatalf("Expected 1 CA, got %d", len(pool.Subjects()))
+	}
+
+	// Files which are not certificates are an error.
+	if err = ioutil.WriteFile(filepath.Join(caDir, "junk.crt"), []byte("junk"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadRPCClientCAs(); err == nil {
+		t.Fatal("Expected an error loading a file which is not a certificate")
+	}
+}
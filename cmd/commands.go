@@ -17,18 +17,66 @@
 package cmd
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/minio/cli"
-	"github.com/minio/minio/pkg/trie"
 )
 
 // Collection of minio commands currently supported are.
 var commands = []cli.Command{}
 
-// Collection of minio commands currently supported in a trie tree.
-var commandsTree = trie.NewTrie()
+// Collection of minio command names currently supported, sorted.
+var commandsIndex = &commandIndex{}
 
 // registerCommand registers a cli command.
 func registerCommand(command cli.Command) {
 	commands = append(commands, command)
-	commandsTree.Insert(command.Name)
+	commandsIndex.insert(command.Name)
+}
+
+// commandIndex keeps command names sorted, so that commands close to
+// a mistyped command are found without walking all the names.
+type commandIndex struct {
+	names []string
+}
+
+// insert - adds name to the index, keeping it sorted.
+func (idx *commandIndex) insert(name string) {
+	i := sort.SearchStrings(idx.names, name)
+	if i < len(idx.names) && idx.names[i] == name {
+		return
+	}
+	idx.names = append(idx.names, "")
+	copy(idx.names[i+1:], idx.names[i:])
+	idx.names[i] = name
+}
+
+// closest - returns the commands prefixed by command, followed by the
+// commands within a Damerau-Levenshtein distance of 1, which allows
+// missed, wrongly added and even transposed characters.
+func (idx *commandIndex) closest(command string) []string {
+	var closestCommands []string
+
+	// Prefixed commands are contiguous in the sorted index.
+	start := sort.SearchStrings(idx.names, command)
+	end := start
+	for end < len(idx.names) && strings.HasPrefix(idx.names[end], command) {
+		end++
+	}
+	closestCommands = append(closestCommands, idx.names[start:end]...)
+
+	for i, name := range idx.names {
+		if i >= start && i < end {
+			continue
+		}
+		// The distance is at least the difference in length.
+		if lenDiff := len(name) - len(command); lenDiff > 1 || lenDiff < -1 {
+			continue
+		}
+		if DamerauLevenshteinDistance(command, name) < 2 {
+			closestCommands = append(closestCommands, name)
+		}
+	}
+	return closestCommands
 }
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/minio/cli"
@@ -39,3 +40,37 @@ func TestRegisterCommand(t *testing.T) {
 		t.Fatalf("Unexpected number of commands found %d", ccount)
 	}
 }
+
+// Tests finding commands close to a mistyped command.
+func TestCommandIndexClosest(t *testing.T) {
+	idx := &commandIndex{}
+	for _, name := range []string{"version", "server", "update", "service", "server"} {
+		idx.insert(name)
+	}
+	if !reflect.DeepEqual(idx.names, []string{"server", "service", "update", "version"}) {
+		t.Fatalf("Unexpected command index %v", idx.names)
+	}
+
+	testCases := []struct {
+		command  string
+		expected []string
+	}{
+		// Prefix matches.
+		{"ser", []string{"server", "service"}},
+		{"v", []string{"version"}},
+		// Missed, added and transposed characters.
+		{"sever", []string{"server"}},
+		{"updatee", []string{"update"}},
+		{"verison", []string{"version"}},
+		// Exact match is not suggested twice.
+		{"server", []string{"server"}},
+		// Nothing close.
+		{"heal", nil},
+	}
+
+	for i, testCase := range testCases {
+		if closest := idx.closest(testCase.command); !reflect.DeepEqual(closest, testCase.expected) {
+			t.Errorf("Test %d: Expected %v for %s, got %v", i+1, testCase.expected, testCase.command, closest)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var completionCmd = cli.Command{
+	Name:   "completion",
+	Usage:  "Print shell completion script.",
+	Action: mainCompletion,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} SHELL
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+SHELL:
+   bash, zsh
+
+EXAMPLES:
+   1. Enable completion in the current bash session:
+       $ source <({{.HelpName}} bash)
+
+   2. Install completion for zsh, the directory must be listed in $fpath:
+       $ {{.HelpName}} zsh > ~/.zsh/completion/_minio
+`,
+}
+
+// Flags whose value is a directory.
+var completionDirFlags = map[string]bool{
+	"config-dir": true,
+}
+
+// Commands whose arguments are directories.
+var completionPathArgs = map[string]bool{
+	"server":  true,
+	"fs-heal": true,
+	"adopt":   true,
+	"xl-meta": true,
+}
+
+// Commands whose arguments are one of a fixed set of words.
+var completionArgWords = map[string][]string{
+	"completion": {"bash", "zsh"},
+	"config":     {"get", "set", "validate"},
+}
+
+// completionFlag - a flag as seen by shell completion.
+type completionFlag struct {
+	names    []string // Names prefixed with "-" or "--".
+	usage    string
+	hasValue bool
+	isDir    bool
+}
+
+// completionCommand - a command as seen by shell completion.
+type completionCommand struct {
+	name  string
+	usage string
+	flags []completionFlag
+	// Commands with path arguments, e.g. server export paths.
+	hasPathArgs bool
+	// Fixed set of arguments, if any.
+	argWords []string
+}
+
+// withHelpFlag - returns a copy of flags including the help flag.
+func withHelpFlag(flags []cli.Flag) []cli.Flag {
+	return append(append([]cli.Flag{}, flags...), cli.HelpFlag)
+}
+
+// newCompletionFlags - converts cli flags for completion.
+func newCompletionFlags(flags []cli.Flag) []completionFlag {
+	var cflags []completionFlag
+	for _, flag := range flags {
+		cflag := completionFlag{hasValue: true}
+		switch f := flag.(type) {
+		case cli.BoolFlag:
+			cflag.hasValue = false
+			cflag.usage = f.Usage
+		case cli.BoolTFlag:
+			cflag.hasValue = false
+			cflag.usage = f.Usage
+		case cli.StringFlag:
+			cflag.usage = f.Usage
+		}
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			name = strings.TrimSpace(name)
+			if completionDirFlags[name] {
+				cflag.isDir = true
+			}
+			if len(name) == 1 {
+				cflag.names = append(cflag.names, "-"+name)
+			} else {
+				cflag.names = append(cflag.names, "--"+name)
+			}
+		}
+		cflags = append(cflags, cflag)
+	}
+	return cflags
+}
+
+// newCompletionCommands - converts commands for completion.
+func newCompletionCommands(commands []cli.Command) []completionCommand {
+	var ccmds []completionCommand
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+		ccmds = append(ccmds, completionCommand{
+			name:        command.Name,
+			usage:       command.Usage,
+			flags:       newCompletionFlags(withHelpFlag(command.Flags)),
+			hasPathArgs: completionPathArgs[command.Name],
+			argWords:    completionArgWords[command.Name],
+		})
+	}
+	return ccmds
+}
+
+// flagWords - returns all names of flags.
+func flagWords(flags []completionFlag) string {
+	var words []string
+	for _, flag := range flags {
+		words = append(words, flag.names...)
+	}
+	return strings.Join(words, " ")
+}
+
+// bashCompletion - generates the bash completion script.
+func bashCompletion(globalCFlags []completionFlag, ccmds []completionCommand) string {
+	var buf bytes.Buffer
+	buf.WriteString("# bash completion for minio\n\n_minio() {\n")
+	buf.WriteString("    local cur prev command i\n")
+	buf.WriteString("    COMPREPLY=()\n")
+	buf.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	buf.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+
+	// Flag values, directories or nothing to complete.
+	var dirFlags, valueFlags []string
+	allFlags := append([]completionFlag{}, globalCFlags...)
+	for _, ccmd := range ccmds {
+		allFlags = append(allFlags, ccmd.flags...)
+	}
+	seen := make(map[string]bool)
+	for _, flag := range allFlags {
+		for _, name := range flag.names {
+			if seen[name] || !flag.hasValue {
+				continue
+			}
+			seen[name] = true
+			if flag.isDir {
+				dirFlags = append(dirFlags, name)
+			} else {
+				valueFlags = append(valueFlags, name)
+			}
+		}
+	}
+	buf.WriteString("    case \"$prev\" in\n")
+	if len(dirFlags) > 0 {
+		fmt.Fprintf(&buf, "        %s)\n            COMPREPLY=( $(compgen -d -- \"$cur\") )\n            return 0\n            ;;\n", strings.Join(dirFlags, "|"))
+	}
+	if len(valueFlags) > 0 {
+		fmt.Fprintf(&buf, "        %s)\n            return 0\n            ;;\n", strings.Join(valueFlags, "|"))
+	}
+	buf.WriteString("    esac\n\n")
+
+	// Find the command being completed.
+	var names []string
+	for _, ccmd := range ccmds {
+		names = append(names, ccmd.name)
+	}
+	buf.WriteString("    command=\"\"\n")
+	buf.WriteString("    for (( i=1; i < COMP_CWORD; i++ )); do\n")
+	buf.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	fmt.Fprintf(&buf, "            %s)\n                command=\"${COMP_WORDS[i]}\"\n                break\n                ;;\n", strings.Join(names, "|"))
+	buf.WriteString("        esac\n    done\n\n")
+
+	buf.WriteString("    case \"$command\" in\n")
+	fmt.Fprintf(&buf, "        \"\")\n            COMPREPLY=( $(compgen -W \"%s %s\" -- \"$cur\") )\n            ;;\n",
+		strings.Join(names, " "), flagWords(globalCFlags))
+	for _, ccmd := range ccmds {
+		fmt.Fprintf(&buf, "        %s)\n", ccmd.name)
+		if ccmd.hasPathArgs {
+			fmt.Fprintf(&buf, "            if [[ \"$cur\" == -* ]]; then\n                COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n            else\n                COMPREPLY=( $(compgen -d -- \"$cur\") )\n            fi\n",
+				flagWords(ccmd.flags))
+		} else {
+			words := append(append([]string{}, ccmd.argWords...), flagWords(ccmd.flags))
+			fmt.Fprintf(&buf, "            COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(words, " "))
+		}
+		buf.WriteString("            ;;\n")
+	}
+	buf.WriteString("    esac\n}\n\ncomplete -F _minio minio\n")
+	return buf.String()
+}
+
+// zshQuote - escapes s for use in a single quoted zsh _arguments spec.
+func zshQuote(s string) string {
+	s = strings.Replace(s, "'", `'\''`, -1)
+	s = strings.Replace(s, "[", `\[`, -1)
+	return strings.Replace(s, "]", `\]`, -1)
+}
+
+// zshFlagSpecs - returns _arguments specs of flags.
+func zshFlagSpecs(flags []completionFlag) []string {
+	var specs []string
+	for _, flag := range flags {
+		action := ""
+		if flag.isDir {
+			action = ":directory:_files -/"
+		} else if flag.hasValue {
+			action = ":value: "
+		}
+		usage := zshQuote(flag.usage)
+		if len(flag.names) == 1 {
+			specs = append(specs, fmt.Sprintf("'%s[%s]%s'", flag.names[0], usage, action))
+			continue
+		}
+		exclusive := strings.Join(flag.names, " ")
+		specs = append(specs, fmt.Sprintf("'(%s)'{%s}'[%s]%s'", exclusive, strings.Join(flag.names, ","), usage, action))
+	}
+	return specs
+}
+
+// zshCompletion - generates the zsh completion script.
+func zshCompletion(globalCFlags []completionFlag, ccmds []completionCommand) string {
+	var buf bytes.Buffer
+	buf.WriteString("#compdef minio\n\n_minio() {\n")
+	buf.WriteString("    local context state line\n")
+	buf.WriteString("    local -a commands\n    commands=(\n")
+	for _, ccmd := range ccmds {
+		fmt.Fprintf(&buf, "        '%s:%s'\n", ccmd.name, strings.Replace(ccmd.usage, "'", `'\''`, -1))
+	}
+	buf.WriteString("    )\n\n")
+
+	buf.WriteString("    _arguments -C \\\n")
+	for _, spec := range zshFlagSpecs(globalCFlags) {
+		fmt.Fprintf(&buf, "        %s \\\n", spec)
+	}
+	buf.WriteString("        '1: :->command' \\\n        '*:: :->args'\n\n")
+
+	buf.WriteString("    case $state in\n")
+	buf.WriteString("        command)\n            _describe 'command' commands\n            ;;\n")
+	buf.WriteString("        args)\n            case $words[1] in\n")
+	for _, ccmd := range ccmds {
+		fmt.Fprintf(&buf, "                %s)\n                    _arguments \\\n", ccmd.name)
+		for _, spec := range zshFlagSpecs(ccmd.flags) {
+			fmt.Fprintf(&buf, "                        %s \\\n", spec)
+		}
+		if ccmd.hasPathArgs {
+			buf.WriteString("                        '*:path:_files -/'\n")
+		} else if len(ccmd.argWords) > 0 {
+			fmt.Fprintf(&buf, "                        '1:argument:(%s)'\n", strings.Join(ccmd.argWords, " "))
+		} else {
+			buf.WriteString("                        '*: :'\n")
+		}
+		buf.WriteString("                    ;;\n")
+	}
+	buf.WriteString("            esac\n            ;;\n    esac\n}\n\n_minio \"$@\"\n")
+	return buf.String()
+}
+
+func mainCompletion(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "completion", 1)
+	}
+
+	globalCFlags := newCompletionFlags(withHelpFlag(globalFlags))
+	ccmds := newCompletionCommands(commands)
+	switch shell := ctx.Args().First(); shell {
+	case "bash":
+		console.Print(bashCompletion(globalCFlags, ccmds))
+	case "zsh":
+		console.Print(zshCompletion(globalCFlags, ccmds))
+	default:
+		console.Fatalf("Unsupported shell %s, supported shells are bash and zsh.\n", shell)
+	}
+}
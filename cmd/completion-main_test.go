@@ -0,0 +1,88 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+// Tests generation of shell completion scripts.
+func TestShellCompletion(t *testing.T) {
+	testCommands := []cli.Command{serverCmd, versionCmd, updateCmd, completionCmd}
+	globalCFlags := newCompletionFlags(withHelpFlag(globalFlags))
+	ccmds := newCompletionCommands(testCommands)
+
+	testCases := []struct {
+		shell    string
+		script   string
+		expected []string
+	}{
+		{"bash", bashCompletion(globalCFlags, ccmds), []string{
+			"server|version|update|completion)",
+			"--config-dir|-C)\n            COMPREPLY=( $(compgen -d -- \"$cur\") )",
+			"compgen -W \"--address --listeners --witness --check --config-dir -C --quiet",
+			"compgen -W \"bash zsh",
+			"complete -F _minio minio",
+		}},
+		{"zsh", zshCompletion(globalCFlags, ccmds), []string{
+			"#compdef minio",
+			"'server:Start object storage server.'",
+			"{--config-dir,-C}'[Path to configuration directory.]:directory:_files -/'",
+			"'*:path:_files -/'",
+			"'1:argument:(bash zsh)'",
+		}},
+	}
+
+	for i, testCase := range testCases {
+		for _, expected := range testCase.expected {
+			if !strings.Contains(testCase.script, expected) {
+				t.Errorf("Test %d: Expected %s completion to contain %q", i+1, testCase.shell, expected)
+			}
+		}
+
+		// Check script syntax if the shell is available.
+		shellPath, err := exec.LookPath(testCase.shell)
+		if err != nil {
+			continue
+		}
+		scriptFile, err := ioutil.TempFile("", "minio-completion")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(scriptFile.Name())
+		if _, err = scriptFile.WriteString(testCase.script); err != nil {
+			t.Fatal(err)
+		}
+		scriptFile.Close()
+		if out, err := exec.Command(shellPath, "-n", scriptFile.Name()).CombinedOutput(); err != nil {
+			t.Errorf("Test %d: Invalid %s completion script: %v %s", i+1, testCase.shell, err, out)
+		}
+	}
+}
+
+// Tests quoting of zsh _arguments specs.
+func TestZshQuote(t *testing.T) {
+	if quoted := zshQuote("Don't [really]"); quoted != `Don'\''t \[really\]` {
+		t.Errorf("Unexpected quoted string %s", quoted)
+	}
+}
@@ -0,0 +1,418 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/quick"
+)
+
+var configCmd = cli.Command{
+	Name:  "config",
+	Usage: "Manage server configuration while the server is stopped.",
+	Subcommands: []cli.Command{
+		configGetCmd,
+		configSetCmd,
+		configValidateCmd,
+	},
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} COMMAND [FLAGS] [ARGS...]
+
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{ "\t" }}{{.Usage}}
+   {{end}}
+KEYS:
+   Keys are dotted paths of the JSON fields in config.json, e.g. "region",
+   "credential.accessKey", "logger.console.level" or "notify.webhook.1.endpoint".
+`,
+}
+
+var configGetCmd = cli.Command{
+	Name:   "get",
+	Usage:  "Print the value of a configuration key.",
+	Flags:  globalFlags,
+	Action: mainConfigGet,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS] [KEY]
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXAMPLES:
+   1. Print the whole configuration:
+       $ {{.HelpName}}
+
+   2. Print the console logger level:
+       $ {{.HelpName}} logger.console.level
+`,
+}
+
+var configSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Set the value of a configuration key.",
+	Flags:  globalFlags,
+	Action: mainConfigSet,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS] KEY VALUE
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+VALUE:
+   String fields take VALUE as is, all other fields take VALUE as JSON.
+   The previous config.json is saved as config.json.old.
+
+EXAMPLES:
+   1. Change the region:
+       $ {{.HelpName}} region us-west-1
+
+   2. Enable the webhook notification target:
+       $ {{.HelpName}} notify.webhook.1 '{"enable": true, "endpoint": "http://localhost:3000/"}'
+`,
+}
+
+var configValidateCmd = cli.Command{
+	Name:   "validate",
+	Usage:  "Validate the configuration file.",
+	Flags:  globalFlags,
+	Action: mainConfigValidate,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS]
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXIT STATUS:
+   0 - Configuration is valid.
+   1 - Configuration is invalid or could not be read.
+
+EXAMPLES:
+   1. Validate the configuration in a custom configuration directory:
+       $ {{.HelpName}} --config-dir /etc/minio
+`,
+}
+
+// errUnknownConfigKey - returned for keys not present in the config.
+var errUnknownConfigKey = errors.New("Unknown config key")
+
+// Sets the configuration directory from the --config-dir flag of the
+// command or of the application, exits if the directory is empty.
+func mustSetConfigDirFromContext(c *cli.Context) {
+	configDir := c.String("config-dir")
+	if !c.IsSet("config-dir") && c.GlobalIsSet("config-dir") {
+		configDir = c.GlobalString("config-dir")
+	}
+	if configDir == "" {
+		console.Fatalf("Configuration directory cannot be empty.")
+	}
+
+	// Set configuration directory.
+	setConfigDir(configDir)
+}
+
+// readConfigFile - reads config.json from the configuration directory,
+// unlike loadConfig no environment overrides are applied and the
+// global server config is left untouched.
+func readConfigFile() (*serverConfigV16, error) {
+	configFile := getConfigFile()
+	if _, err := os.Stat(configFile); err != nil {
+		return nil, err
+	}
+
+	srvCfg := &serverConfigV16{}
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// validateConfig - validates all fields of the server config which
+// are otherwise only checked on server startup.
+func validateConfig(srvCfg *serverConfigV16) error {
+	if srvCfg.Version != v16 {
+		return fmt.Errorf("Unsupported config version `%s`, run `minio server` once to migrate it", srvCfg.Version)
+	}
+	if err := validateAuthKeys(srvCfg.Credential.AccessKey, srvCfg.Credential.SecretKey); err != nil {
+		return fmt.Errorf("credential: %v", err)
+	}
+	if browser := strings.ToLower(srvCfg.Browser); browser != "on" && browser != "off" {
+		return fmt.Errorf("browser: invalid value `%s`, expected \"on\" or \"off\"", srvCfg.Browser)
+	}
+	if srvCfg.Logger != nil {
+		levels := []struct {
+			key    string
+			enable bool
+			level  string
+		}{
+			{"logger.console.level", srvCfg.Logger.Console.Enable, srvCfg.Logger.Console.Level},
+			{"logger.file.level", srvCfg.Logger.File.Enable, srvCfg.Logger.File.Level},
+			{"logger.syslog.level", srvCfg.Logger.Syslog.Enable, srvCfg.Logger.Syslog.Level},
+			{"logger.http.level", srvCfg.Logger.HTTP.Enable, srvCfg.Logger.HTTP.Level},
+		}
+		for _, l := range levels {
+			if !l.enable {
+				continue
+			}
+			if _, err := logrus.ParseLevel(l.level); err != nil {
+				return fmt.Errorf("%s: %v", l.key, err)
+			}
+		}
+	}
+	if srvCfg.Alert.ErrorsPerMinute < 0 {
+		return errors.New("alert.errorsPerMinute: must not be negative")
+	}
+	if _, err := newBucketCreationPolicy(srvCfg.BucketCreation); err != nil {
+		return fmt.Errorf("bucketCreation: %v", err)
+	}
+	if _, err := newStrictNamesPolicy(srvCfg.StrictNames); err != nil {
+		return fmt.Errorf("strictNames: %v", err)
+	}
+	if _, err := newContentTypePolicy(srvCfg.ContentType); err != nil {
+		return fmt.Errorf("contentType: %v", err)
+	}
+	if err := validateBucketMounts(srvCfg.BucketMounts); err != nil {
+		return fmt.Errorf("bucketMounts: %v", err)
+	}
+	if _, err := newAdminCredentials(srvCfg.AdminCredentials, srvCfg.Credential); err != nil {
+		return fmt.Errorf("adminCredentials: %v", err)
+	}
+	if err := validatePresignConfig(srvCfg.Presign); err != nil {
+		return fmt.Errorf("presign: %v", err)
+	}
+	if _, err := newTracer(srvCfg.Tracing); err != nil {
+		return fmt.Errorf("tracing: %v", err)
+	}
+	if _, err := validateStatsdConfig(srvCfg.Statsd); err != nil {
+		return fmt.Errorf("statsd: %v", err)
+	}
+	if err := validateRequestLimits(srvCfg.RequestLimits); err != nil {
+		return fmt.Errorf("requestLimits: %v", err)
+	}
+	if err := validateBrowserSessionConfig(srvCfg.BrowserSession); err != nil {
+		return fmt.Errorf("browserSession: %v", err)
+	}
+	if err := validateEventSchemaConfig(srvCfg.EventSchema); err != nil {
+		return fmt.Errorf("eventSchema: %v", err)
+	}
+	if err := validateBackupConfig(srvCfg.Backup); err != nil {
+		return fmt.Errorf("backup: %v", err)
+	}
+	if err := validateApprovalConfig(srvCfg.Approval); err != nil {
+		return fmt.Errorf("approval: %v", err)
+	}
+	if err := validateDiscoveryConfig(srvCfg.Discovery); err != nil {
+		return fmt.Errorf("discovery: %v", err)
+	}
+	if err := validateFailureDomainsConfig(srvCfg.FailureDomains); err != nil {
+		return fmt.Errorf("failureDomains: %v", err)
+	}
+	if err := validateCompressionConfig(srvCfg.Compression); err != nil {
+		return fmt.Errorf("compression: %v", err)
+	}
+	if err := validateBucketNetworks(srvCfg.BucketNetworks); err != nil {
+		return fmt.Errorf("bucketNetworks: %v", err)
+	}
+	if err := validateDisabledAPIs(srvCfg.DisabledAPIs); err != nil {
+		return fmt.Errorf("disabledAPIs: %v", err)
+	}
+	if err := validateCompat(srvCfg.Compat); err != nil {
+		return fmt.Errorf("compat: %v", err)
+	}
+	if _, err := newIAMUsers(srvCfg.Users, srvCfg.Credential, srvCfg.AdminCredentials); err != nil {
+		return fmt.Errorf("users: %v", err)
+	}
+	return nil
+}
+
+// configToDoc - converts the server config to its generic JSON form.
+func configToDoc(srvCfg *serverConfigV16) (map[string]interface{}, error) {
+	configBytes, err := json.Marshal(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	if err = json.Unmarshal(configBytes, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// lookupConfigKey - returns the value of a dotted key in the generic
+// JSON form of the config, an empty key returns the whole document.
+func lookupConfigKey(doc map[string]interface{}, key string) (interface{}, error) {
+	if key == "" {
+		return doc, nil
+	}
+	var value interface{} = doc
+	for _, field := range strings.Split(key, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errUnknownConfigKey
+		}
+		if value, ok = object[field]; !ok {
+			return nil, errUnknownConfigKey
+		}
+	}
+	return value, nil
+}
+
+// getConfigKey - returns the value of a dotted key of the server config.
+func getConfigKey(srvCfg *serverConfigV16, key string) (interface{}, error) {
+	doc, err := configToDoc(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	return lookupConfigKey(doc, key)
+}
+
+// setConfigKey - returns a copy of the server config with the dotted
+// key set to value. Keys of string fields take the value as is, all
+// other keys take the value as JSON and must decode into the field.
+func setConfigKey(srvCfg *serverConfigV16, key, value string) (*serverConfigV16, error) {
+	if key == "" || key == "version" {
+		return nil, fmt.Errorf("%s: key cannot be set", key)
+	}
+	doc, err := configToDoc(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(key, ".")
+	parent, err := lookupConfigKey(doc, strings.Join(fields[:len(fields)-1], "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", key, err)
+	}
+	object, ok := parent.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: %v", key, errUnknownConfigKey)
+	}
+
+	var newValue interface{}
+	if _, isString := object[fields[len(fields)-1]].(string); isString {
+		newValue = value
+	} else if err = json.Unmarshal([]byte(value), &newValue); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON value: %v", key, err)
+	}
+	object[fields[len(fields)-1]] = newValue
+
+	configBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	newCfg := &serverConfigV16{}
+	if err = json.Unmarshal(configBytes, newCfg); err != nil {
+		return nil, fmt.Errorf("%s: invalid value: %v", key, err)
+	}
+
+	// New keys which do not map to a config field are dropped while
+	// decoding, reject them instead of silently ignoring the value.
+	if _, err = getConfigKey(newCfg, key); err != nil {
+		return nil, fmt.Errorf("%s: %v", key, err)
+	}
+	return newCfg, nil
+}
+
+func mainConfigGet(ctx *cli.Context) {
+	if len(ctx.Args()) > 1 {
+		cli.ShowCommandHelpAndExit(ctx, "get", 1)
+	}
+	mustSetConfigDirFromContext(ctx)
+
+	srvCfg, err := readConfigFile()
+	if err != nil {
+		console.Fatalf("Unable to read config file %s. Err: %s.\n", getConfigFile(), err)
+	}
+	key := ctx.Args().First()
+	value, err := getConfigKey(srvCfg, key)
+	if err != nil {
+		console.Fatalf("Unable to get `%s`. Err: %s.\n", key, err)
+	}
+
+	// Print strings as is so that they can be used in scripts.
+	if s, ok := value.(string); ok {
+		console.Println(s)
+		return
+	}
+	valueBytes, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		console.Fatalf("Unable to get `%s`. Err: %s.\n", key, err)
+	}
+	console.Println(string(valueBytes))
+}
+
+func mainConfigSet(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1)
+	}
+	mustSetConfigDirFromContext(ctx)
+
+	srvCfg, err := readConfigFile()
+	if err != nil {
+		console.Fatalf("Unable to read config file %s. Err: %s.\n", getConfigFile(), err)
+	}
+	key, value := ctx.Args().Get(0), ctx.Args().Get(1)
+	newCfg, err := setConfigKey(srvCfg, key, value)
+	if err != nil {
+		console.Fatalf("Unable to set `%s`. Err: %s.\n", key, err)
+	}
+	if err = validateConfig(newCfg); err != nil {
+		console.Fatalf("Unable to set `%s`, the resulting config is invalid. Err: %s.\n", key, err)
+	}
+	if err = newCfg.Save(); err != nil {
+		console.Fatalf("Unable to save config file %s. Err: %s.\n", getConfigFile(), err)
+	}
+}
+
+func mainConfigValidate(ctx *cli.Context) {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "validate", 1)
+	}
+	mustSetConfigDirFromContext(ctx)
+
+	srvCfg, err := readConfigFile()
+	if err != nil {
+		console.Fatalf("Unable to read config file %s. Err: %s.\n", getConfigFile(), err)
+	}
+	if err = validateConfig(srvCfg); err != nil {
+		console.Fatalf("Invalid config file %s. Err: %s.\n", getConfigFile(), err)
+	}
+	console.Println("Config file " + getConfigFile() + " is valid.")
+}
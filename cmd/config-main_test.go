@@ -0,0 +1,138 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests reading, setting and validating config keys.
+func TestConfigKeys(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	srvCfg, err := readConfigFile()
+	if err != nil {
+		t.Fatalf("Unable to read config file: %s", err)
+	}
+	if err = validateConfig(srvCfg); err != nil {
+		t.Fatalf("Expected a fresh config to be valid, got %s", err)
+	}
+
+	testCases := []struct {
+		key           string
+		value         string
+		expectedValue interface{}
+		shouldPass    bool
+	}{
+		// String fields take the value as is.
+		{"region", "us-west-1", "us-west-1", true},
+		{"logger.console.level", "debug", "debug", true},
+		// Other fields take the value as JSON.
+		{"logger.console.enable", "false", false, true},
+		{"alert.errorsPerMinute", "10", float64(10), true},
+		{"bucketCreation.maxBuckets", "5", float64(5), true},
+		{"notify.webhook.2", `{"enable": false, "endpoint": ""}`, map[string]interface{}{"enable": false, "endpoint": ""}, true},
+		// Invalid JSON and type mismatches.
+		{"alert.errorsPerMinute", "ten", nil, false},
+		{"logger.console.enable", `"yes"`, nil, false},
+		// Unknown keys and keys which cannot be set.
+		{"unknown", "value", nil, false},
+		{"logger.unknown.level", "debug", nil, false},
+		{"region.name", "us-west-1", nil, false},
+		{"version", "16", nil, false},
+		{"", "value", nil, false},
+	}
+	for i, testCase := range testCases {
+		newCfg, err := setConfigKey(srvCfg, testCase.key, testCase.value)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected an error setting %s", i+1, testCase.key)
+			}
+			continue
+		}
+		value, err := getConfigKey(newCfg, testCase.key)
+		if err != nil {
+			t.Errorf("Test %d: unable to get %s: %s", i+1, testCase.key, err)
+			continue
+		}
+		if !isSameConfigValue(value, testCase.expectedValue) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedValue, value)
+		}
+	}
+
+	// Values which decode but do not validate.
+	invalidCases := []struct {
+		key   string
+		value string
+	}{
+		{"browser", "maybe"},
+		{"credential.secretKey", "short"},
+		{"logger.console.level", "loud"},
+		{"alert.errorsPerMinute", "-1"},
+		{"bucketCreation.namePattern", "("},
+	}
+	for i, testCase := range invalidCases {
+		newCfg, err := setConfigKey(srvCfg, testCase.key, testCase.value)
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if err = validateConfig(newCfg); err == nil {
+			t.Errorf("Test %d: expected %s=%s to be invalid", i+1, testCase.key, testCase.value)
+		}
+	}
+
+	// Save and read back a change.
+	newCfg, err := setConfigKey(srvCfg, "region", "eu-west-1")
+	if err != nil {
+		t.Fatalf("Unable to set region: %s", err)
+	}
+	if err = newCfg.Save(); err != nil {
+		t.Fatalf("Unable to save config: %s", err)
+	}
+	if srvCfg, err = readConfigFile(); err != nil {
+		t.Fatalf("Unable to read config file: %s", err)
+	}
+	if srvCfg.Region != "eu-west-1" {
+		t.Errorf("Expected region eu-west-1, got %s", srvCfg.Region)
+	}
+}
+
+// Compares values of the generic JSON form of the config.
+func isSameConfigValue(a, b interface{}) bool {
+	aMap, aOk := a.(map[string]interface{})
+	bMap, bOk := b.(map[string]interface{})
+	if aOk != bOk {
+		return false
+	}
+	if !aOk {
+		return a == b
+	}
+	for k, v := range bMap {
+		if !isSameConfigValue(aMap[k], v) {
+			return false
+		}
+	}
+	return true
+}
@@ -78,6 +78,14 @@ func migrateConfig() error {
 	if err := migrateV13ToV14(); err != nil {
 		return err
 	}
+	// Migration version '14' to '15'.
+	if err := migrateV14ToV15(); err != nil {
+		return err
+	}
+	// Migration version '15' to '16'.
+	if err := migrateV15ToV16(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1000,3 +1008,108 @@ func migrateV13ToV14() error {
 	)
 	return nil
 }
+
+// Version '14' to '15' migration. Adds syslog and http loggers,
+// both disabled by default.
+func migrateV14ToV15() error {
+	cv14, err := loadConfigV14()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘14’. %v", err)
+	}
+	if cv14.Version != "14" {
+		return nil
+	}
+
+	// Copy over fields from V14 into V15 config struct
+	srvConfig := &serverConfigV15{
+		Logger: &logger{},
+	}
+	srvConfig.Version = "15"
+	srvConfig.Credential = cv14.Credential
+	srvConfig.Region = cv14.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Browser = cv14.Browser
+	srvConfig.Logger.Console = cv14.Logger.Console
+	srvConfig.Logger.File = cv14.Logger.File
+	srvConfig.Notify = cv14.Notify
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+
+	configFile := getConfigFile()
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv14.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv14.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '15' to '16' migration. Adds alerting, server events, IAM
+// users and other optional features, all disabled by default.
+func migrateV15ToV16() error {
+	cv15, err := loadConfigV15()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘15’. %v", err)
+	}
+	if cv15.Version != "15" {
+		return nil
+	}
+
+	// Copy over fields from V15 into V16 config struct
+	srvConfig := &serverConfigV16{}
+	srvConfig.Version = "16"
+	srvConfig.Credential = cv15.Credential
+	srvConfig.Region = cv15.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Browser = cv15.Browser
+	srvConfig.Logger = cv15.Logger
+	srvConfig.Notify = cv15.Notify
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+
+	configFile := getConfigFile()
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv15.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv15.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
@@ -0,0 +1,775 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/quick"
+)
+
+// Read Write mutex for safe access to ServerConfig.
+var serverConfigMu sync.RWMutex
+
+// Config version
+var v16 = "16"
+
+// serverConfigV16 server configuration version '16' which is like
+// version '15' except it adds support of alerting, server events,
+// IAM users and other optional features.
+type serverConfigV16 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+	Browser    string     `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Alerting configuration.
+	Alert alertConfig `json:"alert"`
+
+	// Server events configuration.
+	ServerEvents []serverEventConfig `json:"serverEvents"`
+
+	// Bucket creation restrictions.
+	BucketCreation bucketCreationConfig `json:"bucketCreation"`
+
+	// Strict object names configuration.
+	StrictNames strictNamesConfig `json:"strictNames"`
+
+	// Content type policy of new objects.
+	ContentType contentTypeConfig `json:"contentType"`
+
+	// Read-only bucket mounts of host directories.
+	BucketMounts []bucketMountConfig `json:"bucketMounts"`
+
+	// Admin API only credentials.
+	AdminCredentials []adminCredentialConfig `json:"adminCredentials"`
+
+	// Presigned URL restrictions.
+	Presign presignConfig `json:"presign"`
+
+	// Tracing configuration.
+	Tracing tracingConfig `json:"tracing"`
+
+	// StatsD metrics configuration.
+	Statsd statsdConfig `json:"statsd"`
+
+	// Request limits by access key.
+	RequestLimits map[string]requestLimitConfig `json:"requestLimits"`
+
+	// Buckets with an HTML directory index.
+	DirectoryIndex directoryIndexConfig `json:"directoryIndex"`
+
+	// Browser session lifetime and revocation.
+	BrowserSession browserSessionConfig `json:"browserSession"`
+
+	// Schema of bucket notification events.
+	EventSchema eventSchemaConfig `json:"eventSchema"`
+
+	// Scheduled backups of the configuration.
+	Backup backupConfig `json:"backup"`
+
+	// Two-person rule for destructive operations.
+	Approval approvalConfig `json:"approval"`
+
+	// Buckets denying object overwrites.
+	NoOverwrite noOverwriteConfig `json:"noOverwrite"`
+
+	// Service discovery of the addresses of peers.
+	Discovery discoveryConfig `json:"discovery"`
+
+	// Failure domains of the endpoints.
+	FailureDomains failureDomainsConfig `json:"failureDomains"`
+
+	// Browser uploads and deletes disabled.
+	BrowserReadOnly bool `json:"browserReadOnly"`
+
+	// Compression of GET object responses.
+	Compression compressionConfig `json:"compression"`
+
+	// Client networks allowed by bucket.
+	BucketNetworks map[string]bucketNetworkConfig `json:"bucketNetworks"`
+
+	// Disabled S3 API families.
+	DisabledAPIs []string `json:"disabledAPIs"`
+
+	// Client compatibility profile, e.g. "fuse".
+	Compat string `json:"compat"`
+
+	// IAM users by access key.
+	Users map[string]iamUserConfig `json:"users"`
+}
+
+func newServerConfigV16() *serverConfigV16 {
+	srvCfg := &serverConfigV16{
+		Version: v16,
+		Region:  globalMinioDefaultRegion,
+		Logger:  &logger{},
+		Notify:  &notifier{},
+	}
+	srvCfg.SetCredential(newCredential())
+	srvCfg.SetBrowser("on")
+	// Enable console logger by default on a fresh run.
+	srvCfg.Logger.Console = consoleLogger{
+		Enable: true,
+		Level:  "error",
+	}
+
+	// Make sure to initialize notification configs.
+	srvCfg.Notify.AMQP = make(map[string]amqpNotify)
+	srvCfg.Notify.AMQP["1"] = amqpNotify{}
+	srvCfg.Notify.ElasticSearch = make(map[string]elasticSearchNotify)
+	srvCfg.Notify.ElasticSearch["1"] = elasticSearchNotify{}
+	srvCfg.Notify.Redis = make(map[string]redisNotify)
+	srvCfg.Notify.Redis["1"] = redisNotify{}
+	srvCfg.Notify.NATS = make(map[string]natsNotify)
+	srvCfg.Notify.NATS["1"] = natsNotify{}
+	srvCfg.Notify.PostgreSQL = make(map[string]postgreSQLNotify)
+	srvCfg.Notify.PostgreSQL["1"] = postgreSQLNotify{}
+	srvCfg.Notify.Kafka = make(map[string]kafkaNotify)
+	srvCfg.Notify.Kafka["1"] = kafkaNotify{}
+	srvCfg.Notify.Webhook = make(map[string]webhookNotify)
+	srvCfg.Notify.Webhook["1"] = webhookNotify{}
+
+	return srvCfg
+}
+
+// newConfig - initialize a new server config, saves env parameters if
+// found, otherwise use default parameters
+func newConfig(envParams envParams) error {
+	// Initialize server config.
+	srvCfg := newServerConfigV16()
+
+	// If env is set for a fresh start, save them to config file.
+	if globalIsEnvCreds {
+		srvCfg.SetCredential(envParams.creds)
+	}
+
+	if globalIsEnvBrowser {
+		srvCfg.SetBrowser(envParams.browser)
+	}
+
+	// Create config path.
+	if err := createConfigDir(); err != nil {
+		return err
+	}
+
+	// hold the mutex lock before a new config is assigned.
+	// Save the new config globally.
+	// unlock the mutex.
+	serverConfigMu.Lock()
+	serverConfig = srvCfg
+	serverConfigMu.Unlock()
+
+	// Save config into file.
+	return serverConfig.Save()
+}
+
+// loadConfig - loads a new config from disk, overrides params from env
+// if found and valid
+func loadConfig(envParams envParams) error {
+	configFile := getConfigFile()
+	if _, err := os.Stat(configFile); err != nil {
+		return err
+	}
+
+	srvCfg := &serverConfigV16{}
+
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return err
+	}
+
+	if err = qc.Load(configFile); err != nil {
+		return err
+	}
+
+	// If env is set override the credentials from config file.
+	if globalIsEnvCreds {
+		srvCfg.SetCredential(envParams.creds)
+	}
+
+	if globalIsEnvBrowser {
+		srvCfg.SetBrowser(envParams.browser)
+	}
+
+	if strings.ToLower(srvCfg.GetBrowser()) == "off" {
+		globalIsBrowserEnabled = false
+	}
+
+	// hold the mutex lock before a new config is assigned.
+	serverConfigMu.Lock()
+	// Save the loaded config globally.
+	serverConfig = srvCfg
+	serverConfigMu.Unlock()
+
+	if serverConfig.Version != v16 {
+		return errors.New("Unsupported config version `" + serverConfig.Version + "`.")
+	}
+
+	return nil
+}
+
+// serverConfig server config.
+var serverConfig *serverConfigV16
+
+// GetVersion get current config version.
+func (s serverConfigV16) GetVersion() string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Version
+}
+
+// SetRegion set new region.
+func (s *serverConfigV16) SetRegion(region string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Region = region
+}
+
+// GetRegion get current region.
+func (s serverConfigV16) GetRegion() string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Region
+}
+
+// SetCredentials set new credentials.
+func (s *serverConfigV16) SetCredential(creds credential) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Set updated credential.
+	s.Credential = newCredentialWithKeys(creds.AccessKey, creds.SecretKey)
+}
+
+// GetCredentials get current credentials.
+func (s serverConfigV16) GetCredential() credential {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Credential
+}
+
+// SetBrowser set if browser is enabled.
+func (s *serverConfigV16) SetBrowser(v string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Set browser param
+	s.Browser = v
+}
+
+// GetCredentials get current credentials.
+func (s serverConfigV16) GetBrowser() string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Browser
+}
+
+// SetAlert set new alerting configuration.
+func (s *serverConfigV16) SetAlert(aConfig alertConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Alert = aConfig
+}
+
+// GetAlert get current alerting configuration.
+func (s serverConfigV16) GetAlert() alertConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Alert
+}
+
+// SetServerEvents set new server events configuration.
+func (s *serverConfigV16) SetServerEvents(configs []serverEventConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.ServerEvents = configs
+}
+
+// GetServerEvents get current server events configuration.
+func (s serverConfigV16) GetServerEvents() []serverEventConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.ServerEvents
+}
+
+// SetBucketCreation set new bucket creation restrictions.
+func (s *serverConfigV16) SetBucketCreation(config bucketCreationConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BucketCreation = config
+}
+
+// GetBucketCreation get current bucket creation restrictions.
+func (s serverConfigV16) GetBucketCreation() bucketCreationConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketCreation
+}
+
+// SetStrictNames set new strict object names configuration.
+func (s *serverConfigV16) SetStrictNames(config strictNamesConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.StrictNames = config
+}
+
+// GetStrictNames get current strict object names configuration.
+func (s serverConfigV16) GetStrictNames() strictNamesConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.StrictNames
+}
+
+// SetContentType set new content type policy.
+func (s *serverConfigV16) SetContentType(config contentTypeConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.ContentType = config
+}
+
+// GetContentType get current content type policy.
+func (s serverConfigV16) GetContentType() contentTypeConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.ContentType
+}
+
+// SetBucketMounts set new read-only bucket mounts.
+func (s *serverConfigV16) SetBucketMounts(mounts []bucketMountConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BucketMounts = mounts
+}
+
+// GetBucketMounts get current read-only bucket mounts.
+func (s serverConfigV16) GetBucketMounts() []bucketMountConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketMounts
+}
+
+// SetAdminCredentials set new admin API credentials.
+func (s *serverConfigV16) SetAdminCredentials(configs []adminCredentialConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.AdminCredentials = configs
+}
+
+// GetAdminCredentials get current admin API credentials.
+func (s serverConfigV16) GetAdminCredentials() []adminCredentialConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.AdminCredentials
+}
+
+// SetPresign set new presigned URL restrictions.
+func (s *serverConfigV16) SetPresign(config presignConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Presign = config
+}
+
+// GetPresign get current presigned URL restrictions.
+func (s serverConfigV16) GetPresign() presignConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Presign
+}
+
+// SetTracing set new tracing configuration.
+func (s *serverConfigV16) SetTracing(config tracingConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Tracing = config
+}
+
+// GetTracing get current tracing configuration.
+func (s serverConfigV16) GetTracing() tracingConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Tracing
+}
+
+// SetStatsd set new StatsD metrics configuration.
+func (s *serverConfigV16) SetStatsd(config statsdConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Statsd = config
+}
+
+// GetStatsd get current StatsD metrics configuration.
+func (s serverConfigV16) GetStatsd() statsdConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Statsd
+}
+
+// RevokePresigned revoke presigned URLs signed with accessKey before
+// the given time, unless a later revocation is in place.
+func (s *serverConfigV16) RevokePresigned(accessKey string, before time.Time) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Copied, readers may hold the previous map.
+	revokedBefore := make(map[string]time.Time, len(s.Presign.RevokedBefore)+1)
+	for key, t := range s.Presign.RevokedBefore {
+		revokedBefore[key] = t
+	}
+	if before.After(revokedBefore[accessKey]) {
+		revokedBefore[accessKey] = before.UTC()
+	}
+	s.Presign.RevokedBefore = revokedBefore
+}
+
+// SetRequestLimit set the request limits of accessKey, removes them if
+// unlimited.
+func (s *serverConfigV16) SetRequestLimit(accessKey string, limit requestLimitConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Copied, readers may hold the previous map.
+	limits := make(map[string]requestLimitConfig, len(s.RequestLimits)+1)
+	for key, l := range s.RequestLimits {
+		limits[key] = l
+	}
+	if limit.isUnlimited() {
+		delete(limits, accessKey)
+	} else {
+		limits[accessKey] = limit
+	}
+	s.RequestLimits = limits
+}
+
+// GetRequestLimits get current request limits by access key.
+func (s serverConfigV16) GetRequestLimits() map[string]requestLimitConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.RequestLimits
+}
+
+// SetDirectoryIndex set new bucket directory index configuration.
+func (s *serverConfigV16) SetDirectoryIndex(config directoryIndexConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.DirectoryIndex = config
+}
+
+// GetDirectoryIndex get current bucket directory index configuration.
+func (s serverConfigV16) GetDirectoryIndex() directoryIndexConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.DirectoryIndex
+}
+
+// SetBrowserSession set new browser session settings.
+func (s *serverConfigV16) SetBrowserSession(config browserSessionConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BrowserSession = config
+}
+
+// GetBrowserSession get current browser session settings.
+func (s serverConfigV16) GetBrowserSession() browserSessionConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BrowserSession
+}
+
+// RevokeBrowserSessions revoke browser sessions started before the
+// given time, unless a later revocation is in place.
+func (s *serverConfigV16) RevokeBrowserSessions(before time.Time) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	if before.After(s.BrowserSession.RevokedBefore) {
+		s.BrowserSession.RevokedBefore = before.UTC()
+	}
+}
+
+// SetEventSchema set new schema of notification events.
+func (s *serverConfigV16) SetEventSchema(config eventSchemaConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.EventSchema = config
+}
+
+// GetEventSchema get current schema of notification events.
+func (s serverConfigV16) GetEventSchema() eventSchemaConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.EventSchema
+}
+
+// SetBackup set new backup settings.
+func (s *serverConfigV16) SetBackup(config backupConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Backup = config
+}
+
+// GetBackup get current backup settings.
+func (s serverConfigV16) GetBackup() backupConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Backup
+}
+
+// SetApproval set new approval settings.
+func (s *serverConfigV16) SetApproval(config approvalConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Approval = config
+}
+
+// GetApproval get current approval settings.
+func (s serverConfigV16) GetApproval() approvalConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Approval
+}
+
+// SetNoOverwrite set new buckets denying overwrites.
+func (s *serverConfigV16) SetNoOverwrite(config noOverwriteConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.NoOverwrite = config
+}
+
+// GetNoOverwrite get current buckets denying overwrites.
+func (s serverConfigV16) GetNoOverwrite() noOverwriteConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.NoOverwrite
+}
+
+// SetDiscovery set new service discovery settings.
+func (s *serverConfigV16) SetDiscovery(config discoveryConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Discovery = config
+}
+
+// GetDiscovery get current service discovery settings.
+func (s serverConfigV16) GetDiscovery() discoveryConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Discovery
+}
+
+// SetFailureDomains set new failure domains.
+func (s *serverConfigV16) SetFailureDomains(config failureDomainsConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.FailureDomains = config
+}
+
+// GetFailureDomains get current failure domains.
+func (s serverConfigV16) GetFailureDomains() failureDomainsConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.FailureDomains
+}
+
+// SetBrowserReadOnly set if the browser is read-only.
+func (s *serverConfigV16) SetBrowserReadOnly(readOnly bool) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.BrowserReadOnly = readOnly
+}
+
+// GetBrowserReadOnly get if the browser is read-only.
+func (s serverConfigV16) GetBrowserReadOnly() bool {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BrowserReadOnly
+}
+
+// SetCompression set new response compression settings.
+func (s *serverConfigV16) SetCompression(config compressionConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Compression = config
+}
+
+// GetCompression get current response compression settings.
+func (s serverConfigV16) GetCompression() compressionConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Compression
+}
+
+// SetBucketNetwork set the client networks allowed to access bucket,
+// removes them if empty.
+func (s *serverConfigV16) SetBucketNetwork(bucket string, config bucketNetworkConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Copied, readers may hold the previous map.
+	networks := make(map[string]bucketNetworkConfig, len(s.BucketNetworks)+1)
+	for key, n := range s.BucketNetworks {
+		networks[key] = n
+	}
+	if config.isEmpty() {
+		delete(networks, bucket)
+	} else {
+		networks[bucket] = config
+	}
+	s.BucketNetworks = networks
+}
+
+// GetBucketNetworks get current client networks allowed by bucket.
+func (s serverConfigV16) GetBucketNetworks() map[string]bucketNetworkConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.BucketNetworks
+}
+
+// SetDisabledAPIs set new disabled API families.
+func (s *serverConfigV16) SetDisabledAPIs(families []string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.DisabledAPIs = families
+}
+
+// GetDisabledAPIs get current disabled API families.
+func (s serverConfigV16) GetDisabledAPIs() []string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.DisabledAPIs
+}
+
+// SetCompat set new client compatibility profile.
+func (s *serverConfigV16) SetCompat(compat string) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Compat = compat
+}
+
+// GetCompat get current client compatibility profile.
+func (s serverConfigV16) GetCompat() string {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Compat
+}
+
+// SetUser set the IAM user with accessKey, removes it if empty.
+func (s *serverConfigV16) SetUser(accessKey string, config iamUserConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	// Copied, readers may hold the previous map.
+	users := make(map[string]iamUserConfig, len(s.Users)+1)
+	for key, u := range s.Users {
+		users[key] = u
+	}
+	if config.isEmpty() {
+		delete(users, accessKey)
+	} else {
+		users[accessKey] = config
+	}
+	s.Users = users
+}
+
+// GetUsers get current IAM users by access key.
+func (s serverConfigV16) GetUsers() map[string]iamUserConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Users
+}
+
+// Save config.
+func (s serverConfigV16) Save() error {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	// get config file.
+	configFile := getConfigFile()
+
+	// initialize quick.
+	qc, err := quick.New(&s)
+	if err != nil {
+		return err
+	}
+
+	// Save config file.
+	return qc.Save(configFile)
+}
@@ -102,8 +102,8 @@ func TestServerConfig(t *testing.T) {
 	})
 
 	// Match version.
-	if serverConfig.GetVersion() != v14 {
-		t.Errorf("Expecting version %s found %s", serverConfig.GetVersion(), v14)
+	if serverConfig.GetVersion() != v16 {
+		t.Errorf("Expecting version %s found %s", serverConfig.GetVersion(), v16)
 	}
 
 	// Attempt to save.
@@ -30,7 +30,11 @@ import (
 // for future bit-rot protection.
 func erasureCreateFile(disks []StorageAPI, volume, path string, reader io.Reader, allowEmpty bool, blockSize int64, dataBlocks int, parityBlocks int, algo string, writeQuorum int) (bytesWritten int64, checkSums []string, err error) {
 	// Allocated blockSized buffer for reading from incoming stream.
-	buf := make([]byte, blockSize)
+	buf, err := globalBufferPool.Get(blockSize)
+	if err != nil {
+		return 0, nil, traceError(err)
+	}
+	defer globalBufferPool.Put(buf)
 
 	hashWriters := newHashWriters(len(disks), algo)
 
@@ -98,7 +102,9 @@ func encodeData(dataBuffer []byte, dataBlocks, parityBlocks int) ([][]byte, erro
 	}
 
 	// Encode parity blocks using data blocks.
+	globalErasureWorkers.acquire()
 	err = rs.Encode(blocks)
+	globalErasureWorkers.release()
 	if err != nil {
 		return nil, traceError(err)
 	}
@@ -328,6 +328,9 @@ func decodeData(enBlocks [][]byte, dataBlocks, parityBlocks int) error {
 		return traceError(err)
 	}
 
+	globalErasureWorkers.acquire()
+	defer globalErasureWorkers.release()
+
 	// Reconstruct encoded blocks.
 	err = rs.Reconstruct(enBlocks)
 	if err != nil {
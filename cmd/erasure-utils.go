@@ -21,7 +21,6 @@ import (
 	"errors"
 	"hash"
 	"io"
-	"sync"
 
 	"github.com/klauspost/reedsolomon"
 	"github.com/minio/sha256-simd"
@@ -60,23 +59,17 @@ func newHash(algo string) (h hash.Hash) {
 	return h
 }
 
-// Hash buffer pool is a pool of reusable
-// buffers used while checksumming a stream.
-var hashBufferPool = sync.Pool{
-	New: func() interface{} {
-		b := make([]byte, readSizeV1)
-		return &b
-	},
-}
-
 // hashSum calculates the hash of the entire path and returns.
 func hashSum(disk StorageAPI, volume, path string, writer hash.Hash) ([]byte, error) {
-	// Fetch staging a new staging buffer from the pool.
-	bufp := hashBufferPool.Get().(*[]byte)
-	defer hashBufferPool.Put(bufp)
+	// Fetch a new staging buffer from the pool.
+	buf, err := globalBufferPool.Get(readSizeV1)
+	if err != nil {
+		return nil, traceError(err)
+	}
+	defer globalBufferPool.Put(buf)
 
 	// Copy entire buffer to writer.
-	if err := copyBuffer(writer, disk, volume, path, *bufp); err != nil {
+	if err = copyBuffer(writer, disk, volume, path, buf); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,62 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"math"
+	"os"
+	"runtime"
+
+	"github.com/minio/minio/pkg/sys"
+)
+
+// erasureWorkers - bounds the number of erasure encode and decode
+// operations running concurrently, each of which is parallelized
+// over GOMAXPROCS by the erasure coding library.
+type erasureWorkers chan struct{}
+
+// newErasureWorkers - returns n erasure workers.
+func newErasureWorkers(n int) erasureWorkers {
+	return make(erasureWorkers, n)
+}
+
+// acquire - waits for a free worker.
+func (w erasureWorkers) acquire() {
+	w <- struct{}{}
+}
+
+// release - frees a worker taken with acquire.
+func (w erasureWorkers) release() {
+	<-w
+}
+
+// setMaxProcs - limits GOMAXPROCS to the cgroup CPU quota of the
+// process, so that containers with a few CPUs on a large host are not
+// oversubscribed. An explicit GOMAXPROCS env is always honored.
+func setMaxProcs() error {
+	if os.Getenv("GOMAXPROCS") != "" {
+		return nil
+	}
+	limit, err := sys.GetCPULimit()
+	if err != nil {
+		return err
+	}
+	if procs := int(math.Ceil(limit)); procs > 0 && procs < runtime.NumCPU() {
+		runtime.GOMAXPROCS(procs)
+	}
+	return nil
+}
@@ -75,6 +75,10 @@ type eventNotifier struct {
 	// servers, internally to a particular server that is
 	// connected to the client.
 	internal internalNotifier
+
+	// `serverEventConfigs` routes server events to external
+	// targets, loaded from config.json.
+	serverEventConfigs []serverEventConfig
 }
 
 // Represents data to be sent with notification event.
@@ -83,13 +87,18 @@ type eventData struct {
 	Bucket    string
 	ObjInfo   ObjectInfo
 	ReqParams map[string]string
+
+	// Sequencer of the change, taken under the object lock when the
+	// event is sent after the lock is released. Taken when the event
+	// is constructed if empty.
+	Sequencer string
 }
 
 // New notification event constructs a new notification event message from
 // input request metadata which completed successfully.
 func newNotificationEvent(event eventData) NotificationEvent {
-	// Fetch the region.
-	region := serverConfig.GetRegion()
+	// Fetch the event schema.
+	schema := serverConfig.GetEventSchema()
 
 	// Fetch the credentials.
 	creds := serverConfig.GetCredential()
@@ -108,15 +117,21 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	// Fetch a hexadecimal representation of event time in nano seconds.
 	uniqueID := mustGetRequestID(eventTime)
 
+	// Sequencer ordering the events of the object.
+	sequencer := event.Sequencer
+	if sequencer == "" {
+		sequencer = getEventSequencer(event.Bucket, event.ObjInfo.Name)
+	}
+
 	/// Construct a new object created event.
 
 	// Following blocks fills in all the necessary details of s3
 	// event message structure.
 	// http://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
 	nEvent := NotificationEvent{
-		EventVersion:      eventVersion,
+		EventVersion:      schema.getVersion(),
 		EventSource:       eventSource,
-		AwsRegion:         region,
+		AwsRegion:         schema.getRegion(),
 		EventTime:         eventTime.Format(timeFormatAMZ),
 		EventName:         event.Type.String(),
 		UserIdentity:      identity{creds.AccessKey},
@@ -133,7 +148,7 @@ func newNotificationEvent(event eventData) NotificationEvent {
 			Bucket: bucketMeta{
 				Name:          event.Bucket,
 				OwnerIdentity: identity{creds.AccessKey},
-				ARN:           bucketARNPrefix + event.Bucket,
+				ARN:           schema.getBucketARN(event.Bucket),
 			},
 		},
 	}
@@ -145,7 +160,7 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	if event.Type == ObjectRemovedDelete {
 		nEvent.S3.Object = objectMeta{
 			Key:       escapedObj,
-			Sequencer: uniqueID,
+			Sequencer: sequencer,
 		}
 		return nEvent
 	}
@@ -155,13 +170,18 @@ func newNotificationEvent(event eventData) NotificationEvent {
 		Key:       escapedObj,
 		ETag:      event.ObjInfo.MD5Sum,
 		Size:      event.ObjInfo.Size,
-		Sequencer: uniqueID,
+		Sequencer: sequencer,
 	}
 
 	// Success.
 	return nEvent
 }
 
+// Fetch server event configs.
+func (en eventNotifier) GetServerEventConfigs() []serverEventConfig {
+	return en.serverEventConfigs
+}
+
 // Fetch all external targets. This returns a copy of the current map of
 // external notification targets.
 func (en eventNotifier) GetAllExternalTargets() map[string]*logrus.Logger {
@@ -553,6 +573,7 @@ func addQueueTarget(queueTargets map[string]*logrus.Logger,
 	// Using accountID we can now initialize a new AMQP logrus instance.
 	logger, err := newTargetFunc(accountID)
 	if err == nil {
+		countNotifyTargetEvents(queueARN, logger)
 		queueTargets[queueARN] = logger
 	}
 
@@ -716,6 +737,12 @@ func initEventNotifier(objAPI ObjectLayer) error {
 		return err
 	}
 
+	// Validate server event configs against queue targets.
+	serverEventConfigs := serverConfig.GetServerEvents()
+	if err = checkServerEventConfigs(serverEventConfigs, queueTargets); err != nil {
+		return err
+	}
+
 	// Initialize internal listener targets
 	listenTargets := make(map[string]*listenerLogger)
 	for _, listeners := range lConfigs {
@@ -746,6 +773,7 @@ func initEventNotifier(objAPI ObjectLayer) error {
 			listenerConfigs:    lConfigs,
 			connectedListeners: make(map[string]chan []NotificationEvent),
 		},
+		serverEventConfigs: serverEventConfigs,
 	}
 
 	return nil
@@ -0,0 +1,95 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Event version of the S3 2.1 schema, which adds glacierEventData to
+// the 2.0 one.
+// ref: http://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
+const eventVersion21 = "2.1"
+
+// Valid partitions of bucket ARNs, e.g. "aws" or "minio".
+var validARNPartition = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// eventSchemaConfig - schema of bucket notification events.
+type eventSchemaConfig struct {
+	// Event version, "2.1" by default, "2.0" keeps the schema of
+	// previous releases for consumers validating it strictly.
+	Version string `json:"version"`
+
+	// Region in awsRegion, the server region if empty.
+	Region string `json:"region"`
+
+	// Partition of the bucket ARN, "aws" if empty, which gives
+	// arn:aws:s3:::bucket.
+	ARNPartition string `json:"arnPartition"`
+}
+
+// validateEventSchemaConfig - validates the schema of notification
+// events.
+func validateEventSchemaConfig(config eventSchemaConfig) error {
+	switch config.Version {
+	case "", eventVersion, eventVersion21:
+	default:
+		return errors.New("version: unsupported event version " + config.Version)
+	}
+	if config.ARNPartition != "" && !validARNPartition.MatchString(config.ARNPartition) {
+		return errors.New("arnPartition: invalid partition " + config.ARNPartition)
+	}
+	return nil
+}
+
+// getVersion - returns the event version of notification events.
+func (config eventSchemaConfig) getVersion() string {
+	if config.Version == "" {
+		return eventVersion21
+	}
+	return config.Version
+}
+
+// getRegion - returns the region of notification events.
+func (config eventSchemaConfig) getRegion() string {
+	if config.Region == "" {
+		return serverConfig.GetRegion()
+	}
+	return config.Region
+}
+
+// getBucketARN - returns the ARN of bucket in notification events.
+func (config eventSchemaConfig) getBucketARN(bucket string) string {
+	if config.ARNPartition == "" {
+		return bucketARNPrefix + bucket
+	}
+	return "arn:" + config.ARNPartition + ":s3:::" + bucket
+}
+
+// Restore details of glacierEventData, sent by S3 for restores of
+// archived objects only.
+type restoreEventData struct {
+	LifecycleRestorationExpiryTime string `json:"lifecycleRestorationExpiryTime"`
+	LifecycleRestoreStorageClass   string `json:"lifecycleRestoreStorageClass"`
+}
+
+// Notification event glacier metadata of the 2.1 schema. Minio has no
+// archived objects, so events never carry it.
+type glacierEventData struct {
+	RestoreEventData restoreEventData `json:"restoreEventData"`
+}
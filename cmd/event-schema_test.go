@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Tests validation of the notification event schema.
+func TestValidateEventSchemaConfig(t *testing.T) {
+	testCases := []struct {
+		config  eventSchemaConfig
+		success bool
+	}{
+		{eventSchemaConfig{}, true},
+		{eventSchemaConfig{Version: "2.0"}, true},
+		{eventSchemaConfig{Version: "2.1", Region: "eu-west-1", ARNPartition: "minio"}, true},
+		{eventSchemaConfig{Version: "3.0"}, false},
+		{eventSchemaConfig{ARNPartition: "aws:s3"}, false},
+		{eventSchemaConfig{ARNPartition: "AWS"}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateEventSchemaConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests the version, region and bucket ARN of notification events.
+func TestNotificationEventSchema(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer serverConfig.SetEventSchema(eventSchemaConfig{})
+
+	testCases := []struct {
+		config  eventSchemaConfig
+		version string
+		region  string
+		arn     string
+	}{
+		{eventSchemaConfig{}, "2.1", globalMinioDefaultRegion, "arn:aws:s3:::bucket"},
+		{eventSchemaConfig{Version: "2.0"}, "2.0", globalMinioDefaultRegion, "arn:aws:s3:::bucket"},
+		{eventSchemaConfig{Region: "eu-west-1", ARNPartition: "minio"}, "2.1", "eu-west-1", "arn:minio:s3:::bucket"},
+	}
+	for i, testCase := range testCases {
+		serverConfig.SetEventSchema(testCase.config)
+		event := newNotificationEvent(eventData{
+			Type:    ObjectCreatedPut,
+			Bucket:  "bucket",
+			ObjInfo: ObjectInfo{Name: "object", Size: 1, MD5Sum: "etag"},
+		})
+		if event.EventVersion != testCase.version || event.AwsRegion != testCase.region || event.S3.Bucket.ARN != testCase.arn {
+			t.Errorf("Test %d: Expected version %s, region %s and ARN %s, got %s, %s and %s", i+1,
+				testCase.version, testCase.region, testCase.arn, event.EventVersion, event.AwsRegion, event.S3.Bucket.ARN)
+		}
+		if event.S3.Object.Sequencer == "" {
+			t.Errorf("Test %d: Expected a sequencer", i+1)
+		}
+
+		// Events without archived objects carry no glacierEventData.
+		data, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "glacierEventData") {
+			t.Errorf("Test %d: Unexpected glacierEventData in %s", i+1, data)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Sequencers of object keys not changed for longer are forgotten, the
+// clock is trusted to have moved past them.
+const eventSequencerWindow = 10 * time.Minute
+
+// eventSequencer - hands out the sequencers of notification events,
+// which increase with every change of an object key on this server.
+// Sequencers are commit times in nanoseconds, as fixed width
+// hexadecimal strings so that they compare as strings like they do
+// as numbers.
+type eventSequencer struct {
+	mu    sync.Mutex
+	last  map[string]int64
+	swept time.Time
+}
+
+func newEventSequencer() *eventSequencer {
+	return &eventSequencer{last: make(map[string]int64)}
+}
+
+// next - returns the sequencer of a change of key committed at
+// commitTime, greater than the previous sequencer of key even if the
+// clock went back.
+func (s *eventSequencer) next(key string, commitTime time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if commitTime.Sub(s.swept) > eventSequencerWindow {
+		oldest := commitTime.Add(-eventSequencerWindow).UnixNano()
+		for k, seq := range s.last {
+			if seq < oldest {
+				delete(s.last, k)
+			}
+		}
+		s.swept = commitTime
+	}
+
+	seq := commitTime.UnixNano()
+	if last, ok := s.last[key]; ok && seq <= last {
+		seq = last + 1
+	}
+	s.last[key] = seq
+	return fmt.Sprintf("%016X", seq)
+}
+
+// getEventSequencer - returns the sequencer of a change of an object
+// committed now, callers hold the object lock so that sequencers
+// follow the order of the changes.
+func getEventSequencer(bucket, object string) string {
+	return globalEventSequencer.next(pathJoin(bucket, object), time.Now().UTC())
+}
@@ -0,0 +1,81 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests that sequencers increase for every change of an object key.
+func TestEventSequencer(t *testing.T) {
+	s := newEventSequencer()
+	now := time.Now().UTC()
+
+	first := s.next("bucket/object", now)
+	if len(first) != 16 {
+		t.Fatalf("Expected a fixed width sequencer, got %s", first)
+	}
+
+	// Changes committed at the same time, or after the clock went
+	// back, still get greater sequencers.
+	second := s.next("bucket/object", now)
+	third := s.next("bucket/object", now.Add(-time.Second))
+	if !(first < second && second < third) {
+		t.Fatalf("Expected increasing sequencers, got %s, %s and %s", first, second, third)
+	}
+
+	// Other keys are ordered on their own.
+	if other := s.next("bucket/other", now.Add(-time.Second)); other >= first {
+		t.Fatalf("Expected sequencer of another key from its commit time, got %s after %s", other, first)
+	}
+
+	// Keys not changed for longer than the window are forgotten.
+	s.next("bucket/later", now.Add(2*eventSequencerWindow))
+	if _, ok := s.last["bucket/object"]; ok {
+		t.Fatal("Expected sequencer of an old change to be forgotten")
+	}
+}
+
+// Tests that events carry the sequencer taken when the change was
+// committed.
+func TestNotificationEventSequencer(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	deleteSequencer := getEventSequencer("bucket", "object")
+	putEvent := newNotificationEvent(eventData{
+		Type:    ObjectCreatedPut,
+		Bucket:  "bucket",
+		ObjInfo: ObjectInfo{Name: "object"},
+	})
+	deleteEvent := newNotificationEvent(eventData{
+		Type:      ObjectRemovedDelete,
+		Bucket:    "bucket",
+		ObjInfo:   ObjectInfo{Name: "object"},
+		Sequencer: deleteSequencer,
+	})
+	if deleteEvent.S3.Object.Sequencer != deleteSequencer {
+		t.Fatalf("Expected sequencer %s, got %s", deleteSequencer, deleteEvent.S3.Object.Sequencer)
+	}
+	if putEvent.S3.Object.Sequencer <= deleteSequencer {
+		t.Fatalf("Expected sequencer of the later change to be greater, got %s after %s", putEvent.S3.Object.Sequencer, deleteSequencer)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// failureDomainsConfig - labels of the failure domains, e.g. racks or
+// zones, to the endpoints in them. Endpoints are matched by host, e.g.
+// "192.168.1.11", or by host and path, e.g. "192.168.1.11/mnt/export1",
+// '*' wildcards are supported.
+//
+// Every object is erasure coded across all the disks with as many
+// parity as data blocks, so the loss of a failure domain never loses
+// data as long as it holds at most half of the disks.
+type failureDomainsConfig map[string][]string
+
+// validateFailureDomainsConfig - validates failure domain labels.
+func validateFailureDomainsConfig(config failureDomainsConfig) error {
+	for name, patterns := range config {
+		if name == "" {
+			return errors.New("empty failure domain name")
+		}
+		if len(patterns) == 0 {
+			return fmt.Errorf("%s: no endpoints", name)
+		}
+	}
+	return nil
+}
+
+// matchFailureDomain - returns true if pattern matches endpoint ep.
+func matchFailureDomain(pattern string, ep *url.URL) bool {
+	host := ep.Host
+	if h, _, err := net.SplitHostPort(ep.Host); err == nil {
+		host = h
+	}
+	return wildcard.MatchSimple(pattern, host) || wildcard.MatchSimple(pattern, host+getPath(ep))
+}
+
+// FailureDomainInfo holds the disks of a failure domain, its loss is
+// tolerated if it holds at most ParityDisks disks.
+type FailureDomainInfo struct {
+	Name        string   `json:"name"`
+	Disks       []string `json:"disks"`
+	ParityDisks int      `json:"parityDisks"`
+}
+
+// getFailureDomains - returns the failure domains of endpoints sorted by
+// name, fails unless every endpoint is in exactly one failure domain
+// holding at most half of the disks. Returns nil if none are configured.
+func getFailureDomains(config failureDomainsConfig, endpoints []*url.URL) ([]FailureDomainInfo, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+	if len(endpoints) < 2 {
+		return nil, errors.New("Failure domains can only be used with an XL setup")
+	}
+
+	var names []string
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parityDisks := len(endpoints) / 2
+	domains := make([]FailureDomainInfo, len(names))
+	for i, name := range names {
+		domains[i] = FailureDomainInfo{Name: name, ParityDisks: parityDisks}
+	}
+	for _, ep := range endpoints {
+		found := -1
+		for i, name := range names {
+			for _, pattern := range config[name] {
+				if !matchFailureDomain(pattern, ep) {
+					continue
+				}
+				if found != -1 && found != i {
+					return nil, fmt.Errorf("%s is in failure domains %s and %s", ep, names[found], name)
+				}
+				found = i
+			}
+		}
+		if found == -1 {
+			return nil, fmt.Errorf("%s is in no failure domain", ep)
+		}
+		domains[found].Disks = append(domains[found].Disks, ep.String())
+	}
+	for _, domain := range domains {
+		if len(domain.Disks) > parityDisks {
+			return nil, fmt.Errorf("Failure domain %s holds %d of %d disks, its loss would exceed the %d parity disks",
+				domain.Name, len(domain.Disks), len(endpoints), parityDisks)
+		}
+	}
+	return domains, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// Tests validation of failure domain labels.
+func TestValidateFailureDomainsConfig(t *testing.T) {
+	testCases := []struct {
+		config  failureDomainsConfig
+		success bool
+	}{
+		{nil, true},
+		{failureDomainsConfig{"rack1": {"host1"}, "rack2": {"host2/*"}}, true},
+		{failureDomainsConfig{"": {"host1"}}, false},
+		{failureDomainsConfig{"rack1": {}}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateFailureDomainsConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests the assignment of endpoints to failure domains.
+func TestGetFailureDomains(t *testing.T) {
+	var endpoints []*url.URL
+	for _, s := range []string{
+		"http://host1:9000/export1", "http://host1:9000/export2",
+		"http://host2:9000/export1", "http://host2:9000/export2",
+	} {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		endpoints = append(endpoints, u)
+	}
+
+	testCases := []struct {
+		config  failureDomainsConfig
+		domains []FailureDomainInfo
+		success bool
+	}{
+		{nil, nil, true},
+		{
+			failureDomainsConfig{"rack2": {"host2"}, "rack1": {"host1"}},
+			[]FailureDomainInfo{
+				{"rack1", []string{"http://host1:9000/export1", "http://host1:9000/export2"}, 2},
+				{"rack2", []string{"http://host2:9000/export1", "http://host2:9000/export2"}, 2},
+			},
+			true,
+		},
+		// Matched by host and path.
+		{
+			failureDomainsConfig{"a": {"*/export1"}, "b": {"*/export2"}},
+			[]FailureDomainInfo{
+				{"a", []string{"http://host1:9000/export1", "http://host2:9000/export1"}, 2},
+				{"b", []string{"http://host1:9000/export2", "http://host2:9000/export2"}, 2},
+			},
+			true,
+		},
+		// More disks than parity in a failure domain.
+		{failureDomainsConfig{"rack1": {"host1", "host2/export1"}, "rack2": {"host2/export2"}}, nil, false},
+		// Endpoint in no failure domain.
+		{failureDomainsConfig{"rack1": {"host1"}}, nil, false},
+		// Endpoint in two failure domains.
+		{failureDomainsConfig{"rack1": {"host1", "host2/export1"}, "rack2": {"host2"}}, nil, false},
+	}
+	for i, testCase := range testCases {
+		domains, err := getFailureDomains(testCase.config, endpoints)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+			continue
+		}
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: Expected failure, got success", i+1)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(domains, testCase.domains) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.domains, domains)
+		}
+	}
+
+	// Failure domains need an XL setup.
+	if _, err := getFailureDomains(failureDomainsConfig{"rack1": {"*"}}, endpoints[:1]); err == nil {
+		t.Error("Expected failure domains of an FS setup to fail")
+	}
+}
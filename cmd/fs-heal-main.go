@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var fsHealCmd = cli.Command{
+	Name:   "fs-heal",
+	Usage:  "Check and repair metadata of a stopped FS backend.",
+	Action: mainFSHeal,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Report problems without repairing them.",
+		},
+	},
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} {{if .VisibleFlags}}[FLAGS] {{end}}PATH
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+DESCRIPTION:
+   Repairs format.json, fs.json of objects and multipart uploads, uploads.json
+   and removes temporary files left behind after an unclean shutdown. Object
+   data is never modified. The server using PATH must be stopped.
+
+EXIT STATUS:
+   0 - No problems found or all problems repaired.
+   1 - Problems found in dry run mode, or the repair failed.
+
+EXAMPLES:
+   1. Report problems in "/home/shared" without repairing them:
+       $ {{.HelpName}} --dry-run /home/shared
+
+   2. Repair "/home/shared":
+       $ {{.HelpName}} /home/shared
+`,
+}
+
+func mainFSHeal(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "fs-heal", 1)
+	}
+
+	dryRun := ctx.Bool("dry-run")
+	issues, err := healFS(ctx.Args().First(), dryRun)
+	for _, issue := range issues {
+		status := "Found"
+		if issue.Repaired {
+			status = "Repaired"
+		}
+		console.Printf("%s: %s: %s\n", status, issue.Path, issue.Problem)
+	}
+	if err != nil {
+		console.Fatalf("Unable to heal %s. Err: %s.\n", ctx.Args().First(), err)
+	}
+
+	if len(issues) == 0 {
+		console.Println("No problems found.")
+		return
+	}
+	if dryRun {
+		console.Printf("%d problem(s) found.\n", len(issues))
+		os.Exit(1)
+	}
+	console.Printf("%d problem(s) repaired.\n", len(issues))
+}
@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// adoptResult - result of adopting pre-existing files as objects.
+type adoptResult struct {
+	// Number and total size of files adopted as objects.
+	Adopted int64 `json:"adopted"`
+	Size    int64 `json:"size"`
+
+	// Number of files which already were objects.
+	Existing int64 `json:"existing"`
+
+	// Files which cannot be objects because of their names, at
+	// most maxObjectList names are returned.
+	SkippedCount int64    `json:"skippedCount"`
+	Skipped      []string `json:"skipped,omitempty"`
+}
+
+// adoptFSObjects - registers files under the bucket directory of the
+// FS backend at fsPath as objects by creating their fs.json, files are
+// left untouched. The md5Sum of adopted objects is computed lazily on
+// their first complete read, see GetObject.
+func adoptFSObjects(fsPath, bucket, prefix string) (result adoptResult, err error) {
+	if !IsValidBucketName(bucket) || isMinioMetaBucketName(bucket) {
+		return result, traceError(BucketNameInvalid{Bucket: bucket})
+	}
+	if !IsValidObjectPrefix(prefix) {
+		return result, traceError(ObjectNameInvalid{Bucket: bucket, Object: prefix})
+	}
+	bucketPath := pathJoin(fsPath, bucket)
+	if _, err = fsStatDir(bucketPath); err != nil {
+		return result, toObjectErr(err, bucket)
+	}
+
+	// Walk from the deepest directory of the prefix.
+	walkPath := bucketPath
+	if i := strings.LastIndex(prefix, slashSeparator); i != -1 {
+		walkPath = pathJoin(bucketPath, prefix[:i])
+	}
+	err = filepath.Walk(preparePath(walkPath), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		object := strings.TrimPrefix(filepath.ToSlash(path), bucketPath+slashSeparator)
+		if !strings.HasPrefix(object, prefix) {
+			return nil
+		}
+		if !IsValidObjectName(object) {
+			result.SkippedCount++
+			if len(result.Skipped) < maxObjectList {
+				result.Skipped = append(result.Skipped, object)
+			}
+			return nil
+		}
+
+		fsMetaPath := pathJoin(fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+		if _, err = fsStatFile(fsMetaPath); err == nil {
+			result.Existing++
+			return nil
+		} else if errorCause(err) != errFileNotFound {
+			return err
+		}
+		if err = mkdirAll(pathJoin(fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object), 0777); err != nil {
+			return err
+		}
+		if err = writeFSMetadata(fsMetaPath, newFSMetaV1()); err != nil {
+			return err
+		}
+		result.Adopted++
+		result.Size += fi.Size()
+		return nil
+	})
+	if err != nil {
+		return result, toObjectErr(traceError(errorCause(err)), bucket, prefix)
+	}
+	return result, nil
+}
+
+// AdoptObjects - registers pre-existing files in the bucket directory
+// as objects, see adoptFSObjects.
+func (fs fsObjects) AdoptObjects(bucket, prefix string) (adoptResult, error) {
+	return adoptFSObjects(fs.fsPath, bucket, prefix)
+}
+
+// adoptObjects - adopts pre-existing files as objects, only supported
+// by the FS backend since XL cannot use files which are not erasure
+// coded in place.
+func adoptObjects(objAPI ObjectLayer, bucket, prefix string) (adoptResult, error) {
+	fs, ok := unwrapObjectLayer(objAPI).(*fsObjects)
+	if !ok {
+		return adoptResult{}, traceError(NotImplemented{})
+	}
+	return fs.AdoptObjects(bucket, prefix)
+}
+
+// saveAdoptedMD5 - saves the md5Sum computed on the first complete
+// read of an adopted object, unless the object changed meanwhile.
+func (fs fsObjects) saveAdoptedMD5(bucket, object, md5Sum string, modTime time.Time, size int64) {
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	wlk, err := fs.rwPool.Write(fsMetaPath)
+	if err != nil {
+		return
+	}
+	defer wlk.Close()
+
+	fsMeta := fsMetaV1{}
+	if _, err = fsMeta.ReadFrom(wlk); err != nil || fsMeta.Meta["md5Sum"] != "" {
+		return
+	}
+	fi, err := fsStatFile(pathJoin(fs.fsPath, bucket, object))
+	if err != nil || !fi.ModTime().Equal(modTime) || fi.Size() != size {
+		return
+	}
+	if fsMeta.Meta == nil {
+		fsMeta.Meta = make(map[string]string)
+	}
+	fsMeta.Meta["md5Sum"] = md5Sum
+	_, err = fsMeta.WriteTo(wlk)
+	errorIf(err, "Unable to save md5Sum of adopted object %s/%s.", bucket, object)
+}
@@ -0,0 +1,124 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests adopting pre-existing files as objects.
+func TestAdoptFSObjects(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	defer removeAll(getConfigDir())
+
+	bucket := "bucket"
+	data := []byte("hello world")
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.PutObject(bucket, "existing", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Files copied into the bucket directory.
+	for _, name := range []string{"a.txt", "photos/b.jpg", "photos/2017/c.jpg", "back\\slash"} {
+		if err := mkdirAll(filepath.Dir(pathJoin(disk, bucket, name)), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(pathJoin(disk, bucket, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		bucket, prefix string
+		expected       adoptResult
+		shouldPass     bool
+	}{
+		{bucket, "photos/", adoptResult{Adopted: 2, Size: 22}, true},
+		// Already adopted files are counted as existing.
+		{bucket, "", adoptResult{Adopted: 1, Size: 11, Existing: 3, SkippedCount: 1, Skipped: []string{"back\\slash"}}, true},
+		{bucket, "none", adoptResult{}, true},
+		{"missing", "", adoptResult{}, false},
+		{minioMetaBucket, "", adoptResult{}, false},
+	}
+	for i, testCase := range testCases {
+		result, err := adoptFSObjects(disk, testCase.bucket, testCase.prefix)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if result.Adopted != testCase.expected.Adopted || result.Size != testCase.expected.Size ||
+			result.Existing != testCase.expected.Existing || result.SkippedCount != testCase.expected.SkippedCount ||
+			len(result.Skipped) != len(testCase.expected.Skipped) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, result)
+		}
+	}
+
+	// The md5Sum of an adopted object is saved on its first complete read.
+	objInfo, err := obj.GetObjectInfo(bucket, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.MD5Sum != "" {
+		t.Fatalf("Expected no md5Sum before the first read, got %s", objInfo.MD5Sum)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, "a.txt", 0, int64(len(data)), &buffer); err != nil {
+		t.Fatal(err)
+	}
+	md5Sum := md5.Sum(data)
+	expectedMD5 := hex.EncodeToString(md5Sum[:])
+	for i := 0; i < 100 && objInfo.MD5Sum == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if objInfo, err = obj.GetObjectInfo(bucket, "a.txt"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if objInfo.MD5Sum != expectedMD5 {
+		t.Errorf("Expected md5Sum %s after the first read, got %s", expectedMD5, objInfo.MD5Sum)
+	}
+}
+
+// Tests adopting files is not supported by XL.
+func TestAdoptObjectsXL(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = adoptObjects(obj, "bucket", ""); toAPIErrorCode(err) != ErrNotImplemented {
+		t.Fatalf("Expected NotImplemented, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"strings"
+)
+
+// Directory objects are zero byte objects named with a trailing slash,
+// such as the directory markers created by Hadoop S3A and fuse clients.
+// They are stored as empty directories and listed as objects as long as
+// nothing is written under them.
+
+// isObjectDir - returns true if object names an empty directory of bucket.
+func (fs fsObjects) isObjectDir(bucket, object string) bool {
+	dirPath := pathJoin(fs.fsPath, bucket, object)
+	if _, err := fsStatDir(dirPath); err != nil {
+		return false
+	}
+	return isDirEmpty(dirPath)
+}
+
+// putObjectDir - creates the directory object, also creating the
+// directories it is under. Metadata which would be lost is rejected.
+func (fs fsObjects) putObjectDir(bucket, object string, metadata map[string]string) (ObjectInfo, error) {
+	if err := checkObjectDirArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := checkObjectDirMetadata(bucket, object, metadata); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := globalStrictNamesPolicy.checkStrictObjectName(bucket, strings.TrimSuffix(object, slashSeparator)); err != nil {
+		return ObjectInfo{}, err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+
+	dirPath := pathJoin(fs.fsPath, bucket, object)
+	if err := checkPathLength(dirPath); err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	if err := mkdirAll(dirPath, 0777); err != nil {
+		// One of the parents is an object.
+		if isSysErrNotDir(err) || isSysErrPathNotFound(err) {
+			err = errFileAccessDenied
+		}
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	fi, err := fsStatDir(dirPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	return dirObjectInfo(bucket, object, fi.ModTime()), nil
+}
+
+// getObjectDirInfo - returns the info of the directory object. Other
+// names with a trailing slash, including directories once objects are
+// written under them, are invalid object names as they always were.
+func (fs fsObjects) getObjectDirInfo(bucket, object string) (ObjectInfo, error) {
+	if err := checkObjectDirArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+	fi, err := fsStatDir(pathJoin(fs.fsPath, bucket, object))
+	if err != nil || !fs.isObjectDir(bucket, object) {
+		return ObjectInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
+	}
+	return dirObjectInfo(bucket, object, fi.ModTime()), nil
+}
+
+// getObjectDir - reads the empty content of the directory object.
+func (fs fsObjects) getObjectDir(bucket, object string, offset int64, length int64, writer io.Writer) error {
+	if _, err := fs.getObjectDirInfo(bucket, object); err != nil {
+		return err
+	}
+	if offset != 0 || length > 0 {
+		return traceError(InvalidRange{offset, length, 0})
+	}
+	return nil
+}
+
+// deleteObjectDir - deletes the directory object, and the directories
+// it is under if they are left empty, as for any other object.
+func (fs fsObjects) deleteObjectDir(bucket, object string) error {
+	if _, err := fs.getObjectDirInfo(bucket, object); err != nil {
+		// Names which are not directory objects have no object to delete.
+		if isErrObjectNameInvalid(err) {
+			return traceError(ObjectNotFound{Bucket: bucket, Object: object})
+		}
+		return err
+	}
+	// Trim the trailing slash, fsDeleteFile removes the parents of
+	// the path it is given.
+	dirPath := pathJoin(fs.fsPath, bucket, strings.TrimSuffix(object, slashSeparator))
+	if err := fsDeleteFile(pathJoin(fs.fsPath, bucket), dirPath); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
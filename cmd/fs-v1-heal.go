@@ -0,0 +1,487 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	pathutil "path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/lock"
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+// Matches part files of an FS multipart upload, e.g. "object1".
+var fsPartFileRegexp = regexp.MustCompile(`^object([0-9]+)$`)
+
+// fsHealIssue - a problem found in the metadata of an FS backend.
+type fsHealIssue struct {
+	Path     string // Path relative to the FS backend directory.
+	Problem  string
+	Repaired bool
+}
+
+// fsHealer - checks and repairs the metadata of an FS backend which
+// is not in use by a running server.
+type fsHealer struct {
+	fsPath string
+	dryRun bool
+	issues []fsHealIssue
+}
+
+// healFS - checks the metadata of the FS backend at fsPath, i.e.
+// format.json, fs.json of objects and multipart uploads, uploads.json
+// and temporary files left behind, and repairs it unless dryRun is
+// set. Returns all the problems found.
+func healFS(fsPath string, dryRun bool) ([]fsHealIssue, error) {
+	fsPath, err := filepath.Abs(fsPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = fsStatDir(pathJoin(fsPath, minioMetaBucket)); err != nil {
+		return nil, fmt.Errorf("%s is not a minio FS backend, %s", fsPath, errorCause(err))
+	}
+
+	h := &fsHealer{fsPath: fsPath, dryRun: dryRun}
+	for _, heal := range []func() error{
+		h.healFormat,
+		h.healTmp,
+		h.healJournals,
+		h.healObjects,
+		h.healMultipart,
+	} {
+		if err = heal(); err != nil {
+			return h.issues, errorCause(err)
+		}
+	}
+	return h.issues, nil
+}
+
+// found - records a problem, it is repaired by the caller unless
+// running in dry run mode.
+func (h *fsHealer) found(path, format string, args ...interface{}) {
+	relPath, err := filepath.Rel(h.fsPath, path)
+	if err != nil {
+		relPath = path
+	}
+	h.issues = append(h.issues, fsHealIssue{
+		Path:     filepath.ToSlash(relPath),
+		Problem:  fmt.Sprintf(format, args...),
+		Repaired: !h.dryRun,
+	})
+}
+
+// Returns true if err is the result of decoding an empty or corrupt
+// JSON file.
+func isCorruptJSONErr(err error) bool {
+	switch errorCause(err).(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return true
+	}
+	return errorCause(err) == io.EOF
+}
+
+// Returns the hex encoded md5 sum of the file at filePath.
+func fsFileMD5(filePath string) (string, error) {
+	reader, _, err := fsOpenFile(filePath, 0)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	md5Writer := md5.New()
+	if _, err = io.Copy(md5Writer, reader); err != nil {
+		return "", traceError(err)
+	}
+	return hex.EncodeToString(md5Writer.Sum(nil)), nil
+}
+
+// Reads fs.json at fsMetaPath.
+func readFSMetadata(fsMetaPath string) (fsMeta fsMetaV1, err error) {
+	metadataBytes, err := ioutil.ReadFile(preparePath(fsMetaPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fsMeta, traceError(errFileNotFound)
+		}
+		return fsMeta, traceError(err)
+	}
+	if len(metadataBytes) == 0 {
+		return fsMeta, traceError(io.EOF)
+	}
+	if err = json.Unmarshal(metadataBytes, &fsMeta); err != nil {
+		return fsMeta, traceError(err)
+	}
+	return fsMeta, nil
+}
+
+// Writes fs.json at fsMetaPath, replacing any previous content.
+func writeFSMetadata(fsMetaPath string, fsMeta fsMetaV1) error {
+	lk, err := lock.LockedOpenFile(preparePath(fsMetaPath), os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return traceError(err)
+	}
+	defer lk.Close()
+
+	_, err = fsMeta.WriteTo(lk)
+	return err
+}
+
+// healFormat - recreates a missing or corrupt format.json.
+func (h *fsHealer) healFormat() error {
+	formatPath := pathJoin(h.fsPath, minioMetaBucket, fsFormatJSONFile)
+	format, err := loadFormatFS(h.fsPath)
+	switch {
+	case err == errUnformattedDisk:
+		h.found(formatPath, "format.json is missing")
+	case isCorruptJSONErr(err):
+		h.found(formatPath, "format.json is corrupt")
+	case err != nil:
+		return err
+	case format.Format != "fs":
+		return fmt.Errorf("%s is not an FS backend, found format `%s`", h.fsPath, format.Format)
+	default:
+		return nil
+	}
+	if h.dryRun {
+		return nil
+	}
+	if err = os.Remove(preparePath(formatPath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return saveFormatFS(formatPath, newFSFormatV1())
+}
+
+// healTmp - removes temporary files, including partially appended
+// multipart uploads, left behind by servers which did not shut down.
+func (h *fsHealer) healTmp() error {
+	tmpPath := pathJoin(h.fsPath, minioMetaTmpBucket)
+	entries, err := readDir(tmpPath)
+	if err != nil {
+		if err == errFileNotFound {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		entryPath := pathJoin(tmpPath, strings.TrimSuffix(entry, slashSeparator))
+		h.found(entryPath, "stale temporary data")
+		if h.dryRun {
+			continue
+		}
+		if err = fsRemoveAll(entryPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// healJournals - completes metadata updates interrupted by a crash
+// or power loss, before the metadata is checked.
+func (h *fsHealer) healJournals() error {
+	journals, err := fsFindJournals(h.fsPath)
+	if err != nil {
+		return err
+	}
+	for _, journalPath := range journals {
+		h.found(journalPath, "interrupted metadata update")
+		if h.dryRun {
+			continue
+		}
+		if _, err = fsReplayJournal(journalPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// healObjects - removes fs.json of objects which do not exist and
+// recreates corrupt fs.json of existing objects.
+func (h *fsHealer) healObjects() error {
+	bucketsPath := pathJoin(h.fsPath, minioMetaBucket, bucketMetaPrefix)
+	var metaPaths []string
+	err := filepath.Walk(preparePath(bucketsPath), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// fs.json of objects are at buckets/<bucket>/<object>/fs.json.
+		if !fi.IsDir() && fi.Name() == fsMetaJSONFile && strings.Count(filepath.ToSlash(path[len(bucketsPath):]), slashSeparator) >= 3 {
+			metaPaths = append(metaPaths, filepath.ToSlash(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, metaPath := range metaPaths {
+		objectPath := pathJoin(h.fsPath, strings.TrimPrefix(pathutil.Dir(metaPath), bucketsPath))
+		if _, err = fsStatFile(objectPath); err != nil {
+			if errorCause(err) != errFileNotFound {
+				return err
+			}
+			h.found(metaPath, "fs.json of a missing object")
+			if h.dryRun {
+				continue
+			}
+			if err = fsDeleteFile(bucketsPath, metaPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err = readFSMetadata(metaPath); err == nil {
+			continue
+		} else if !isCorruptJSONErr(err) {
+			return err
+		}
+		h.found(metaPath, "fs.json is corrupt, content-type and user metadata are lost")
+		if h.dryRun {
+			continue
+		}
+		md5Sum, err := fsFileMD5(objectPath)
+		if err != nil {
+			return err
+		}
+		fsMeta := newFSMetaV1()
+		fsMeta.Meta = map[string]string{"md5Sum": md5Sum}
+		if err = writeFSMetadata(metaPath, fsMeta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if name is the directory name of an upload id.
+func isUploadIDDir(name string) bool {
+	_, err := uuid.Parse(name)
+	return err == nil
+}
+
+// healMultipart - reconciles uploads.json of every object with the
+// multipart uploads present on disk.
+func (h *fsHealer) healMultipart() error {
+	multipartPath := pathJoin(h.fsPath, minioMetaMultipartBucket)
+	objectDirs := make(map[string]bool)
+	err := filepath.Walk(preparePath(multipartPath), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		path = filepath.ToSlash(path)
+		if !fi.IsDir() && fi.Name() == uploadsJSONFile {
+			objectDirs[pathutil.Dir(path)] = true
+		} else if fi.IsDir() && isUploadIDDir(fi.Name()) && pathutil.Dir(pathutil.Dir(path)) != multipartPath {
+			objectDirs[pathutil.Dir(path)] = true
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for objectDir := range objectDirs {
+		dirs = append(dirs, objectDir)
+	}
+	sort.Strings(dirs)
+	for _, objectDir := range dirs {
+		if err = h.healUploads(multipartPath, objectDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// healUploads - reconciles uploads.json in objectDir with its upload
+// directories.
+func (h *fsHealer) healUploads(multipartPath, objectDir string) error {
+	uploadsPath := pathJoin(objectDir, uploadsJSONFile)
+	uploadIDs := newUploadsV1("fs")
+	changed := false
+	uploadsBytes, err := ioutil.ReadFile(preparePath(uploadsPath))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	uploadsExist := err == nil
+	if uploadsExist {
+		if len(uploadsBytes) == 0 || json.Unmarshal(uploadsBytes, &uploadIDs) != nil {
+			h.found(uploadsPath, "uploads.json is corrupt")
+			uploadIDs = newUploadsV1("fs")
+			changed = true
+		}
+	}
+
+	// Upload directories present on disk.
+	entries, err := readDir(objectDir)
+	if err != nil {
+		return err
+	}
+	uploadDirs := make(map[string]os.FileInfo)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry, slashSeparator)
+		if !strings.HasSuffix(entry, slashSeparator) || !isUploadIDDir(name) {
+			continue
+		}
+		fi, err := fsStatDir(pathJoin(objectDir, name))
+		if err != nil {
+			return err
+		}
+		uploadDirs[name] = fi
+		if err = h.healUpload(pathJoin(objectDir, name)); err != nil {
+			return err
+		}
+	}
+
+	// Drop uploads without data, add uploads missing from uploads.json.
+	for _, upload := range append([]uploadInfo(nil), uploadIDs.Uploads...) {
+		if _, ok := uploadDirs[upload.UploadID]; !ok {
+			h.found(uploadsPath, "upload %s has no data", upload.UploadID)
+			uploadIDs.RemoveUploadID(upload.UploadID)
+			changed = true
+		}
+	}
+	for name, fi := range uploadDirs {
+		if !uploadIDs.HasUploadID(name) {
+			h.found(uploadsPath, "upload %s is missing from uploads.json", name)
+			uploadIDs.AddUploadID(name, fi.ModTime().UTC())
+			changed = true
+		}
+	}
+
+	if !changed || h.dryRun {
+		return nil
+	}
+	if uploadIDs.IsEmpty() {
+		if !uploadsExist {
+			return nil
+		}
+		return fsDeleteFile(multipartPath, uploadsPath)
+	}
+	lk, err := lock.LockedOpenFile(preparePath(uploadsPath), os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return traceError(err)
+	}
+	defer lk.Close()
+	_, err = uploadIDs.WriteTo(lk)
+	return err
+}
+
+// healUpload - reconciles fs.json of an upload with its part files,
+// missing and truncated parts are dropped and parts missing from
+// fs.json are added.
+func (h *fsHealer) healUpload(uploadDir string) error {
+	fsMetaPath := pathJoin(uploadDir, fsMetaJSONFile)
+	changed := false
+	fsMeta, err := readFSMetadata(fsMetaPath)
+	if err != nil {
+		if errorCause(err) == errFileNotFound {
+			h.found(fsMetaPath, "fs.json is missing")
+		} else if isCorruptJSONErr(err) {
+			h.found(fsMetaPath, "fs.json is corrupt, content-type and user metadata are lost")
+		} else {
+			return err
+		}
+		fsMeta = newFSMetaV1()
+		changed = true
+	}
+
+	entries, err := readDir(uploadDir)
+	if err != nil {
+		return err
+	}
+	partFiles := make(map[string]os.FileInfo)
+	for _, entry := range entries {
+		if !fsPartFileRegexp.MatchString(entry) {
+			continue
+		}
+		fi, err := fsStatFile(pathJoin(uploadDir, entry))
+		if err != nil {
+			return err
+		}
+		partFiles[entry] = fi
+	}
+
+	// Parts listed in fs.json must have a part file of the same size,
+	// truncated part files are removed so that they can be uploaded again.
+	var parts []objectPartInfo
+	truncated := make(map[string]bool)
+	for _, part := range fsMeta.Parts {
+		fi, ok := partFiles[part.Name]
+		switch {
+		case !ok:
+			h.found(uploadDir, "part %d is missing", part.Number)
+			changed = true
+		case fi.Size() != part.Size:
+			h.found(uploadDir, "part %d is truncated", part.Number)
+			truncated[part.Name] = true
+			changed = true
+		default:
+			parts = append(parts, part)
+		}
+	}
+	fsMeta.Parts = parts
+
+	// Part files are renamed into place once fully written, add the
+	// ones which were not recorded in fs.json.
+	var missingParts []string
+	for name := range partFiles {
+		partNumber, err := strconv.Atoi(fsPartFileRegexp.FindStringSubmatch(name)[1])
+		if err != nil {
+			return err
+		}
+		if truncated[name] || fsMeta.ObjectPartIndex(partNumber) != -1 {
+			continue
+		}
+		h.found(uploadDir, "part %d is missing from fs.json", partNumber)
+		missingParts = append(missingParts, name)
+		changed = true
+	}
+
+	if !changed || h.dryRun {
+		return nil
+	}
+	for name := range truncated {
+		if err = fsRemoveFile(pathJoin(uploadDir, name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range missingParts {
+		partNumber, _ := strconv.Atoi(fsPartFileRegexp.FindStringSubmatch(name)[1])
+		md5Sum, err := fsFileMD5(pathJoin(uploadDir, name))
+		if err != nil {
+			return err
+		}
+		fsMeta.AddObjectPart(partNumber, name, md5Sum, partFiles[name].Size())
+	}
+	return writeFSMetadata(fsMetaPath, fsMeta)
+}
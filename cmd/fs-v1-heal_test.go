@@ -0,0 +1,197 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests checking and repairing FS backend metadata.
+func TestHealFS(t *testing.T) {
+	const lostMetadataProblem = "fs.json is corrupt, content-type and user metadata are lost"
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	defer removeAll(getConfigDir())
+
+	bucket := "bucket"
+	data := []byte("hello world")
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"healthy", "corrupt", "gone"} {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newUpload := func(object string, parts int) string {
+		uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i <= parts; i++ {
+			if _, err = obj.PutObjectPart(bucket, object, uploadID, i, int64(len(data)), bytes.NewReader(data), "", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return uploadID
+	}
+	uploadID1 := newUpload("mp1", 2)
+	uploadID2 := newUpload("mp2", 1)
+	uploadID3 := newUpload("mp3", 1)
+	if err := obj.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	metaPath := func(elems ...string) string {
+		return pathJoin(append([]string{disk, minioMetaBucket}, elems...)...)
+	}
+	multipartPath := func(elems ...string) string {
+		return pathJoin(append([]string{disk, minioMetaMultipartBucket, bucket}, elems...)...)
+	}
+	writeFile := func(path string, content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Simulate the state left behind by a power loss.
+	writeFile(metaPath(fsFormatJSONFile), "")
+	writeFile(metaPath(bucketMetaPrefix, bucket, "corrupt", fsMetaJSONFile), "{")
+	removeAll(pathJoin(disk, bucket, "gone"))
+	if err := mkdirAll(pathJoin(disk, minioMetaTmpBucket, mustGetUUID()), 0777); err != nil {
+		t.Fatal(err)
+	}
+	removeAll(multipartPath("mp1", uploadID1, "object2"))
+	writeFile(multipartPath("mp2", uploadID2, fsMetaJSONFile), "")
+	writeFile(multipartPath("mp2", uploadsJSONFile), "garbage")
+	removeAll(multipartPath("mp3", uploadsJSONFile))
+
+	mpPath := func(object string, elems ...string) string {
+		return pathJoin(append([]string{minioMetaMultipartBucket, bucket, object}, elems...)...)
+	}
+	expectedProblems := map[string]bool{
+		pathJoin(minioMetaBucket, fsFormatJSONFile) + ": format.json is corrupt":                                      true,
+		pathJoin(minioMetaBucket, bucketMetaPrefix, bucket, "corrupt", fsMetaJSONFile) + ": " + lostMetadataProblem:   true,
+		pathJoin(minioMetaBucket, bucketMetaPrefix, bucket, "gone", fsMetaJSONFile) + ": fs.json of a missing object": true,
+		mpPath("mp1", uploadID1) + ": part 2 is missing":                                                              true,
+		mpPath("mp2", uploadID2, fsMetaJSONFile) + ": " + lostMetadataProblem:                                         true,
+		mpPath("mp2", uploadID2) + ": part 1 is missing from fs.json":                                                 true,
+		mpPath("mp2", uploadsJSONFile) + ": uploads.json is corrupt":                                                  true,
+		mpPath("mp2", uploadsJSONFile) + ": upload " + uploadID2 + " is missing from uploads.json":                    true,
+		mpPath("mp3", uploadsJSONFile) + ": upload " + uploadID3 + " is missing from uploads.json":                    true,
+	}
+
+	// Dry run reports problems without repairing them.
+	issues, err := healFS(disk, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One more problem for the stale temporary directory.
+	if len(issues) != len(expectedProblems)+1 {
+		t.Fatalf("Expected %d problems, got %d: %v", len(expectedProblems)+1, len(issues), issues)
+	}
+	for _, issue := range issues {
+		if issue.Repaired {
+			t.Errorf("Expected %s to not be repaired in dry run mode", issue.Path)
+		}
+		if !expectedProblems[issue.Path+": "+issue.Problem] && issue.Problem != "stale temporary data" {
+			t.Errorf("Unexpected problem %s: %s", issue.Path, issue.Problem)
+		}
+	}
+	if formatBytes, _ := ioutil.ReadFile(metaPath(fsFormatJSONFile)); len(formatBytes) != 0 {
+		t.Errorf("Expected format.json to be left as is in dry run mode")
+	}
+
+	// Repair all problems, a second run must find none.
+	if issues, err = healFS(disk, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		if !issue.Repaired {
+			t.Errorf("Expected %s to be repaired", issue.Path)
+		}
+	}
+	if issues, err = healFS(disk, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("Expected no problems after repair, got %v", issues)
+	}
+
+	// The repaired backend must be usable.
+	obj = initFSObjects(disk, t)
+	objInfo, err := obj.GetObjectInfo(bucket, "corrupt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Sum := md5.Sum(data)
+	if objInfo.MD5Sum != hex.EncodeToString(md5Sum[:]) {
+		t.Errorf("Expected md5Sum %s, got %s", hex.EncodeToString(md5Sum[:]), objInfo.MD5Sum)
+	}
+	if _, err = os.Stat(metaPath(bucketMetaPrefix, bucket, "gone")); !os.IsNotExist(err) {
+		t.Errorf("Expected metadata of a missing object to be removed")
+	}
+	for _, testCase := range []struct {
+		object   string
+		uploadID string
+		parts    int
+	}{
+		{"mp1", uploadID1, 1},
+		{"mp2", uploadID2, 1},
+		{"mp3", uploadID3, 1},
+	} {
+		result, err := obj.ListObjectParts(bucket, testCase.object, testCase.uploadID, 0, 1000)
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.object, err)
+		}
+		if len(result.Parts) != testCase.parts {
+			t.Errorf("%s: expected %d parts, got %d", testCase.object, testCase.parts, len(result.Parts))
+		}
+		uploadsBytes, err := ioutil.ReadFile(multipartPath(testCase.object, uploadsJSONFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadIDs := uploadsV1{}
+		if err = json.Unmarshal(uploadsBytes, &uploadIDs); err != nil {
+			t.Fatal(err)
+		}
+		if !uploadIDs.HasUploadID(testCase.uploadID) {
+			t.Errorf("%s: expected upload %s in uploads.json", testCase.object, testCase.uploadID)
+		}
+	}
+}
+
+// Tests healing a directory which is not an FS backend.
+func TestHealFSNotBackend(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	if err := mkdirAll(disk, 0777); err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(disk)
+
+	if _, err := healFS(disk, false); err == nil {
+		t.Fatal("Expected an error healing a directory without .minio.sys")
+	}
+}
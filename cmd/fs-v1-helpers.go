@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	pathutil "path"
+	"time"
 )
 
 // Removes only the file at given path does not remove
@@ -311,6 +312,18 @@ func fsFAllocate(fd int, offset int64, len int64) (err error) {
 	return nil
 }
 
+// Sets the access and modification times of the file at the given
+// path.
+func fsSetModTime(filePath string, modTime time.Time) error {
+	if err := checkPathLength(filePath); err != nil {
+		return traceError(err)
+	}
+	if err := os.Chtimes(preparePath(filePath), modTime, modTime); err != nil {
+		return traceError(err)
+	}
+	return nil
+}
+
 // Renames source path to destination path, creates all the
 // missing parents if they don't exist.
 func fsRenameFile(sourcePath, destPath string) error {
@@ -323,6 +336,18 @@ func fsRenameFile(sourcePath, destPath string) error {
 	return nil
 }
 
+// fsLinkFile - creates a hard link to sourcePath at destPath, creating
+// the parent directories of destPath.
+func fsLinkFile(sourcePath, destPath string) error {
+	if err := mkdirAll(pathutil.Dir(destPath), 0777); err != nil {
+		return traceError(err)
+	}
+	if err := os.Link(preparePath(sourcePath), preparePath(destPath)); err != nil {
+		return traceError(err)
+	}
+	return nil
+}
+
 // Delete a file and its parent if it is empty at the destination path.
 // this function additionally protects the basePath from being deleted.
 func fsDeleteFile(basePath, deletePath string) error {
@@ -0,0 +1,191 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/minio/minio/pkg/lock"
+)
+
+// Suffix of the journal of a metadata file, e.g. `fs.json.journal`.
+// The journal holds the new content of the file while it is being
+// rewritten in place.
+const fsJournalSuffix = ".journal"
+
+// fsSyncDir - flushes the entries of the directory at dirPath to
+// disk. Directories cannot be synced on windows.
+func fsSyncDir(dirPath string) error {
+	if runtime.GOOS == globalWindowsOSName {
+		return nil
+	}
+	dir, err := os.Open(preparePath(dirPath))
+	if err != nil {
+		return traceError(err)
+	}
+	defer dir.Close()
+	return traceError(dir.Sync())
+}
+
+// fsWriteJournal - writes data to the journal at journalPath and
+// flushes it, along with its directory entry, to disk.
+func fsWriteJournal(journalPath string, data []byte) error {
+	f, err := os.OpenFile(preparePath(journalPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return traceError(err)
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return traceError(err)
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return traceError(err)
+	}
+	if err = f.Close(); err != nil {
+		return traceError(err)
+	}
+	return fsSyncDir(filepath.Dir(journalPath))
+}
+
+// fsWriteJournaled - replaces the content of the write locked metadata
+// file lk with data. The file is rewritten in place, readers keep
+// their open handles, so data is first written to a journal which is
+// replayed by fsRecoverJournals if the server stops halfway.
+func fsWriteJournaled(lk *lock.LockedFile, data []byte) error {
+	journalPath := lk.Name() + fsJournalSuffix
+	if err := fsWriteJournal(journalPath, data); err != nil {
+		return err
+	}
+	if err := lk.Truncate(0); err != nil {
+		return traceError(err)
+	}
+	if _, err := lk.Write(data); err != nil {
+		return traceError(err)
+	}
+	if err := lk.Sync(); err != nil {
+		return traceError(err)
+	}
+	if err := os.Remove(journalPath); err != nil && !os.IsNotExist(err) {
+		return traceError(err)
+	}
+	return nil
+}
+
+// fsReplayJournal - completes the interrupted update of the metadata
+// file of the journal at journalPath and removes the journal. A
+// journal which was not fully written is removed, the metadata file
+// was not modified yet. Returns true if the metadata file was
+// rewritten.
+func fsReplayJournal(journalPath string) (bool, error) {
+	metaPath := strings.TrimSuffix(journalPath, fsJournalSuffix)
+
+	// Holding the lock of the metadata file, the journal is not
+	// written concurrently by a server sharing the backend.
+	lk, err := lock.LockedOpenFile(preparePath(metaPath), os.O_WRONLY, 0666)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return false, traceError(err)
+		}
+		// Metadata removed since, e.g. the object was deleted.
+		if err = os.Remove(preparePath(journalPath)); err != nil && !os.IsNotExist(err) {
+			return false, traceError(err)
+		}
+		return false, nil
+	}
+	defer lk.Close()
+
+	data, err := ioutil.ReadFile(preparePath(journalPath))
+	if err != nil {
+		// Update completed while waiting for the lock.
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, traceError(err)
+	}
+
+	var content interface{}
+	replay := len(data) > 0 && json.Unmarshal(data, &content) == nil
+	if replay {
+		if err = lk.Truncate(0); err != nil {
+			return false, traceError(err)
+		}
+		if _, err = lk.Write(data); err != nil {
+			return false, traceError(err)
+		}
+		if err = lk.Sync(); err != nil {
+			return false, traceError(err)
+		}
+	}
+	if err = os.Remove(preparePath(journalPath)); err != nil && !os.IsNotExist(err) {
+		return false, traceError(err)
+	}
+	return replay, nil
+}
+
+// fsFindJournals - returns the journals of the object and multipart
+// upload metadata of the FS backend at fsPath.
+func fsFindJournals(fsPath string) ([]string, error) {
+	var journals []string
+	for _, metaPath := range []string{
+		pathJoin(fsPath, minioMetaBucket, bucketMetaPrefix),
+		pathJoin(fsPath, minioMetaMultipartBucket),
+	} {
+		err := filepath.Walk(preparePath(metaPath), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !fi.IsDir() && strings.HasSuffix(fi.Name(), fsJournalSuffix) {
+				journals = append(journals, filepath.ToSlash(path))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, traceError(err)
+		}
+	}
+	return journals, nil
+}
+
+// fsRecoverJournals - completes the metadata updates of the FS backend
+// at fsPath interrupted by a crash or power loss. Returns the number of
+// metadata files rewritten.
+func fsRecoverJournals(fsPath string) (int, error) {
+	journals, err := fsFindJournals(fsPath)
+	if err != nil {
+		return 0, err
+	}
+	var recovered int
+	for _, journalPath := range journals {
+		replayed, err := fsReplayJournal(journalPath)
+		if err != nil {
+			return recovered, err
+		}
+		if replayed {
+			recovered++
+		}
+	}
+	return recovered, nil
+}
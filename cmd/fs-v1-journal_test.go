@@ -0,0 +1,153 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/pkg/lock"
+)
+
+// Tests journaled rewrites of metadata files.
+func TestFSWriteJournaled(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	metaPath := filepath.Join(dir, fsMetaJSONFile)
+	if err = ioutil.WriteFile(metaPath, []byte(`{"version":"1.0.0","format":"fs","meta":{"etag":"old-etag-value"}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	lk, err := lock.LockedOpenFile(metaPath, os.O_RDWR, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lk.Close()
+
+	// Shorter content than the previous one.
+	fsMeta := newFSMetaV1()
+	if _, err = fsMeta.WriteTo(lk); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(metaPath + fsJournalSuffix); !os.IsNotExist(err) {
+		t.Fatalf("Expected journal to be removed, got %v", err)
+	}
+	readMeta, err := readFSMetadata(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readMeta.Format != fsMetaFormat || len(readMeta.Meta) != 0 {
+		t.Fatalf("Unexpected fs.json %#v", readMeta)
+	}
+}
+
+// Tests recovery of interrupted metadata updates at startup.
+func TestFSRecoverJournals(t *testing.T) {
+	fsDir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	metaDir := filepath.Join(fsDir, minioMetaBucket, bucketMetaPrefix, "bucket")
+	writeFile := func(path, content string) {
+		if err = os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newContent := `{"version":"1.0.0","format":"fs","meta":{"md5Sum":"new"}}`
+	oldContent := `{"version":"1.0.0","format":"fs","meta":{"md5Sum":"old"}}`
+
+	// Power loss while rewriting fs.json, after the journal was written.
+	truncatedPath := filepath.Join(metaDir, "truncated", fsMetaJSONFile)
+	writeFile(truncatedPath, "")
+	writeFile(truncatedPath+fsJournalSuffix, newContent)
+
+	// Power loss while writing the journal, fs.json is intact.
+	partialPath := filepath.Join(metaDir, "partial", fsMetaJSONFile)
+	writeFile(partialPath, oldContent)
+	writeFile(partialPath+fsJournalSuffix, newContent[:20])
+
+	// Journal of a deleted object.
+	deletedPath := filepath.Join(metaDir, "deleted", fsMetaJSONFile)
+	writeFile(deletedPath+fsJournalSuffix, newContent)
+
+	// Journal of uploads.json.
+	uploadsPath := filepath.Join(fsDir, minioMetaMultipartBucket, "bucket", "object", uploadsJSONFile)
+	writeFile(uploadsPath, `{"version":"1.0.0","format":"fs","uploadIds":[{"uploadId":"a`)
+	writeFile(uploadsPath+fsJournalSuffix, `{"version":"1.0.0","format":"fs","uploadIds":[]}`)
+
+	// Journals are found by fs-heal in dry run mode.
+	issues, err := healFS(fsDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var journalIssues int
+	for _, issue := range issues {
+		if issue.Problem == "interrupted metadata update" {
+			journalIssues++
+		}
+	}
+	if journalIssues != 4 {
+		t.Fatalf("Expected 4 interrupted updates, got %v", issues)
+	}
+
+	recovered, err := fsRecoverJournals(fsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != 2 {
+		t.Fatalf("Expected 2 recovered updates, got %d", recovered)
+	}
+
+	testCases := []struct {
+		path    string
+		content string
+	}{
+		{truncatedPath, newContent},
+		{partialPath, oldContent},
+		{uploadsPath, `{"version":"1.0.0","format":"fs","uploadIds":[]}`},
+	}
+	for i, testCase := range testCases {
+		content, err := ioutil.ReadFile(testCase.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != testCase.content {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.content, content)
+		}
+	}
+	if _, err = os.Stat(deletedPath); !os.IsNotExist(err) {
+		t.Errorf("Expected fs.json of deleted object not to be recreated, got %v", err)
+	}
+
+	journals, err := fsFindJournals(fsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 0 {
+		t.Fatalf("Expected journals to be removed, got %v", journals)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"os"
+	"sort"
+)
+
+// Entries of a directory listing held in memory at most, more entries
+// are sorted in runs of this size spilled to temporary files, which are
+// merged while the listing is streamed. A variable so that tests can
+// lower it.
+var fsListDirRunSize = 100000
+
+// Separates the entries of a run file, entries never contain NUL.
+const fsListDirRunSep = 0
+
+// fsDirRun - sorted run of directory entries spilled to a file.
+type fsDirRun struct {
+	file   *os.File
+	reader *bufio.Reader
+	// Next entry of the run.
+	entry string
+}
+
+// next - reads the next entry of the run, io.EOF once all are read.
+func (r *fsDirRun) next() error {
+	entry, err := r.reader.ReadString(fsListDirRunSep)
+	if err != nil {
+		return err
+	}
+	r.entry = entry[:len(entry)-1]
+	return nil
+}
+
+// fsDirRunHeap - runs by their next entry, implements heap.Interface.
+type fsDirRunHeap []*fsDirRun
+
+func (h fsDirRunHeap) Len() int            { return len(h) }
+func (h fsDirRunHeap) Less(i, j int) bool  { return h[i].entry < h[j].entry }
+func (h fsDirRunHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *fsDirRunHeap) Push(x interface{}) { *h = append(*h, x.(*fsDirRun)) }
+func (h *fsDirRunHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	run := old[n-1]
+	*h = old[:n-1]
+	return run
+}
+
+// fsDirEntryStream - sorted entries of an FS directory. Small listings
+// are sorted in memory, large ones are merged from the runs spilled to
+// temporary files.
+type fsDirEntryStream struct {
+	// Entries sorted in memory, nil if spilled.
+	entries []string
+	// Runs with entries left to merge, and all runs to remove on Close.
+	runs    fsDirRunHeap
+	allRuns []*fsDirRun
+}
+
+func (s *fsDirEntryStream) Next() (string, bool, error) {
+	if s.allRuns == nil {
+		if len(s.entries) == 0 {
+			return "", false, nil
+		}
+		entry := s.entries[0]
+		s.entries = s.entries[1:]
+		return entry, true, nil
+	}
+
+	if len(s.runs) == 0 {
+		return "", false, nil
+	}
+	run := s.runs[0]
+	entry := run.entry
+	if err := run.next(); err != nil {
+		if err != io.EOF {
+			return "", false, traceError(err)
+		}
+		heap.Pop(&s.runs)
+	} else {
+		heap.Fix(&s.runs, 0)
+	}
+	return entry, true, nil
+}
+
+func (s *fsDirEntryStream) Close() {
+	for _, run := range s.allRuns {
+		run.file.Close()
+		os.Remove(run.file.Name())
+	}
+	s.entries, s.runs, s.allRuns = nil, nil, nil
+}
+
+// spill - writes entries sorted to a new run file under tmpDir.
+func (s *fsDirEntryStream) spill(tmpDir string, entries []string) error {
+	sort.Strings(entries)
+	file, err := os.Create(pathJoin(tmpDir, mustGetUUID()))
+	if err != nil {
+		return traceError(err)
+	}
+	run := &fsDirRun{file: file}
+	s.allRuns = append(s.allRuns, run)
+
+	writer := bufio.NewWriter(file)
+	for _, entry := range entries {
+		writer.WriteString(entry)
+		writer.WriteByte(fsListDirRunSep)
+	}
+	if err = writer.Flush(); err != nil {
+		return traceError(err)
+	}
+	if _, err = file.Seek(0, 0); err != nil {
+		return traceError(err)
+	}
+	run.reader = bufio.NewReader(file)
+	return nil
+}
+
+// listDirStream - lists an FS directory in batches, keeping only the
+// entries matching prefixEntry from markerDir on. Up to fsListDirRunSize
+// entries are sorted in memory, larger listings are sorted in runs
+// spilled to the temporary directory and merged as they are read.
+func (fs fsObjects) listDirStream(bucket, prefixDir, prefixEntry, markerDir string) (dirEntryStream, error) {
+	tmpDir := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID)
+	stream := &fsDirEntryStream{}
+	var entries []string
+	err := readDirBatches(pathJoin(fs.fsPath, bucket, prefixDir), func(batch []string) error {
+		for _, entry := range batch {
+			if entry < markerDir || !hasPrefix(entry, prefixEntry) {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) < fsListDirRunSize {
+				continue
+			}
+			if err := stream.spill(tmpDir, entries); err != nil {
+				return err
+			}
+			entries = nil
+		}
+		return nil
+	})
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+
+	if stream.allRuns == nil {
+		// Everything fits in memory.
+		sort.Strings(entries)
+		stream.entries = entries
+		return stream, nil
+	}
+	if len(entries) > 0 {
+		if err = stream.spill(tmpDir, entries); err != nil {
+			stream.Close()
+			return nil, err
+		}
+	}
+	for _, run := range stream.allRuns {
+		if err = run.next(); err != nil {
+			// Runs are never empty.
+			stream.Close()
+			return nil, traceError(err)
+		}
+		stream.runs = append(stream.runs, run)
+	}
+	heap.Init(&stream.runs)
+	return stream, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests that listings larger than fsListDirRunSize are merged from
+// spilled runs in order, and that the runs are removed afterwards.
+func TestFSListDirStreamSpill(t *testing.T) {
+	defer func(runSize int) { fsListDirRunSize = runSize }(fsListDirRunSize)
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	fs := obj.(*fsObjects)
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	var objects []string
+	for i := 0; i < 20; i++ {
+		objects = append(objects, fmt.Sprintf("obj%02d", i), fmt.Sprintf("dir%02d/obj", i))
+	}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listAll := func(prefix, delimiter string, maxKeys int) (names []string) {
+		marker := ""
+		for {
+			result, err := obj.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, result.Prefixes...)
+			for _, objInfo := range result.Objects {
+				names = append(names, objInfo.Name)
+			}
+			if !result.IsTruncated {
+				return names
+			}
+			marker = result.NextMarker
+		}
+	}
+
+	testCases := []struct {
+		prefix    string
+		delimiter string
+		maxKeys   int
+	}{
+		{"", "", 1000},
+		{"", "", 7},
+		{"", slashSeparator, 1000},
+		{"", slashSeparator, 3},
+		{"obj1", "", 4},
+		{"dir1", slashSeparator, 1000},
+	}
+	for i, testCase := range testCases {
+		fsListDirRunSize = 100000
+		expected := listAll(testCase.prefix, testCase.delimiter, testCase.maxKeys)
+		fsListDirRunSize = 3
+		got := listAll(testCase.prefix, testCase.delimiter, testCase.maxKeys)
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, expected, got)
+		}
+	}
+
+	stream, err := fs.listDirStream(bucket, "", "obj", "obj05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []string
+	for {
+		entry, ok, err := stream.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	if len(stream.(*fsDirEntryStream).allRuns) != 5 {
+		t.Errorf("Expected 15 entries to be spilled in 5 runs, got %d runs", len(stream.(*fsDirEntryStream).allRuns))
+	}
+	stream.Close()
+	var expected []string
+	for i := 5; i < 20; i++ {
+		expected = append(expected, fmt.Sprintf("obj%02d", i))
+	}
+	if !reflect.DeepEqual(expected, entries) {
+		t.Errorf("Expected %v, got %v", expected, entries)
+	}
+
+	// All runs are removed once the listings are done.
+	tmpEntries, err := readDir(pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmpEntries) != 0 {
+		t.Errorf("Expected spilled runs to be removed, found %v", tmpEntries)
+	}
+}
@@ -132,12 +132,8 @@ func (m *fsMetaV1) WriteTo(lk *lock.LockedFile) (n int64, err error) {
 		return 0, traceError(err)
 	}
 
-	if err = lk.Truncate(0); err != nil {
-		return 0, traceError(err)
-	}
-
-	if _, err = lk.Write(metadataBytes); err != nil {
-		return 0, traceError(err)
+	if err = fsWriteJournaled(lk, metadataBytes); err != nil {
+		return 0, err
 	}
 
 	// Success.
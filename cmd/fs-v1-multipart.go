@@ -255,7 +255,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 			isLeaf := fs.isMultipartUpload
 			listDir := fs.listDirFactory(isLeaf)
 			walkResultCh = startTreeWalk(minioMetaMultipartBucket, multipartPrefixPath,
-				multipartMarkerPath, recursive, listDir, isLeaf, endWalkCh)
+				multipartMarkerPath, recursive, listDir, isLeaf, nil, endWalkCh)
 		}
 
 		// List until maxUploads requested.
@@ -421,6 +421,9 @@ func (fs fsObjects) NewMultipartUpload(bucket, object string, meta map[string]st
 	if err := checkNewMultipartArgs(bucket, object, fs); err != nil {
 		return "", err
 	}
+	if err := globalStrictNamesPolicy.checkStrictObjectName(bucket, object); err != nil {
+		return "", err
+	}
 
 	if _, err := fs.statBucketDir(bucket); err != nil {
 		return "", toObjectErr(err, bucket)
@@ -496,40 +499,24 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		return PartInfo{}, toObjectErr(err, bucket)
 	}
 
-	// Hold the lock so that two parallel complete-multipart-uploads
-	// do not leave a stale uploads.json behind.
-	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
-	objectMPartPathLock.Lock()
-	defer objectMPartPathLock.Unlock()
-
-	// Disallow any parallel abort or complete multipart operations.
-	uploadsPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, bucket, object, uploadsJSONFile)
-	if _, err := fs.rwPool.Open(uploadsPath); err != nil {
-		if err == errFileNotFound || err == errFileAccessDenied {
-			return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
-		}
-		return PartInfo{}, toObjectErr(traceError(err), bucket, object)
-	}
-	defer fs.rwPool.Close(uploadsPath)
-
 	uploadIDPath := pathJoin(bucket, object, uploadID)
 
+	// Only the uploadID is locked, never the whole object, so that
+	// parts of other multipart uploads on the same object are not
+	// serialized behind this one.
+	preUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	preUploadIDLock.RLock()
 	// Just check if the uploadID exists to avoid copy if it doesn't.
 	fsMetaPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, fsMetaJSONFile)
-	rwlk, err := fs.rwPool.Write(fsMetaPath)
-	if err != nil {
+	if _, err := fsStatFile(fsMetaPath); err != nil {
+		preUploadIDLock.RUnlock()
+		err = errorCause(err)
 		if err == errFileNotFound || err == errFileAccessDenied {
 			return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
 		}
 		return PartInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
-	defer rwlk.Close()
-
-	fsMeta := fsMetaV1{}
-	_, err = fsMeta.ReadFrom(rwlk)
-	if err != nil {
-		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPath)
-	}
+	preUploadIDLock.RUnlock()
 
 	partSuffix := fmt.Sprintf("object%d", partID)
 	tmpPartPath := uploadID + "." + mustGetUUID() + "." + partSuffix
@@ -560,7 +547,11 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	if size > 0 && bufSize > size {
 		bufSize = size
 	}
-	buf := make([]byte, int(bufSize))
+	buf, err := globalBufferPool.Get(bufSize)
+	if err != nil {
+		return PartInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	defer globalBufferPool.Put(buf)
 
 	fsPartPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tmpPartPath)
 	bytesWritten, cErr := fsCreateFile(fsPartPath, teeReader, buf, size)
@@ -595,6 +586,29 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		}
 	}
 
+	// Hold write lock on the uploadID so that no one aborts or
+	// completes it while the part is being committed.
+	postUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	postUploadIDLock.Lock()
+	defer postUploadIDLock.Unlock()
+
+	// The uploadID may have been aborted or completed while the part
+	// was being written, validate it again.
+	rwlk, err := fs.rwPool.Write(fsMetaPath)
+	if err != nil {
+		if err == errFileNotFound || err == errFileAccessDenied {
+			return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
+		}
+		return PartInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	defer rwlk.Close()
+
+	fsMeta := fsMetaV1{}
+	_, err = fsMeta.ReadFrom(rwlk)
+	if err != nil {
+		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPath)
+	}
+
 	partPath := pathJoin(bucket, object, uploadID, partSuffix)
 	// Lock the part so that another part upload with same part-number gets blocked
 	// while the part is getting appended in the background.
@@ -744,7 +758,7 @@ func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (objInfo ObjectInfo, err error) {
 	if err := checkCompleteMultipartArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -761,6 +775,13 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 
 	uploadIDPath := pathJoin(bucket, object, uploadID)
 
+	// Hold lock so that there is no competing put-object-part,
+	// abort-multipart-upload or complete-multipart-upload on this
+	// uploadID.
+	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	uploadIDLock.Lock()
+	defer uploadIDLock.Unlock()
+
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
 	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
@@ -794,6 +815,20 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		fs.rwPool.Close(fsMetaPathMultipart)
 		return ObjectInfo{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPathMultipart)
 	}
+	if len(fsMeta.Meta) == 0 {
+		fsMeta.Meta = make(map[string]string)
+	}
+
+	// Keep the previous version if versioning is enabled or suspended
+	// on the bucket.
+	versionDone, err := preserveObjectVersion(fs, bucket, object, fsMeta.Meta)
+	if err != nil {
+		fs.rwPool.Close(fsMetaPathMultipart)
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	defer func() {
+		versionDone(err == nil)
+	}()
 
 	// Wait for any competing PutObject() operation on bucket/object, since same namespace
 	// would be acquired for `fs.json`.
@@ -834,7 +869,12 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		defer fsRemoveFile(fsTmpObjPath)
 
 		// Allocate staging buffer.
-		var buf = make([]byte, readSizeV1)
+		buf, err := globalBufferPool.Get(readSizeV1)
+		if err != nil {
+			fs.rwPool.Close(fsMetaPathMultipart)
+			return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+		}
+		defer globalBufferPool.Put(buf)
 
 		// Validate all parts and then commit to disk.
 		for i, part := range parts {
@@ -906,9 +946,6 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 	fsMeta.Parts = nil
 
 	// Save additional metadata.
-	if len(fsMeta.Meta) == 0 {
-		fsMeta.Meta = make(map[string]string)
-	}
 	fsMeta.Meta["md5Sum"] = s3MD5
 
 	// Write all the set metadata.
@@ -966,6 +1003,12 @@ func (fs fsObjects) AbortMultipartUpload(bucket, object, uploadID string) error
 
 	uploadIDPath := pathJoin(bucket, object, uploadID)
 
+	// Hold lock so that there is no competing
+	// complete-multipart-upload or put-object-part.
+	uploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	uploadIDLock.Lock()
+	defer uploadIDLock.Unlock()
+
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
 	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket,
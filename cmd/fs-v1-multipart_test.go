@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestFSWriteUploadJSON - tests for writeUploadJSON for FS
@@ -184,3 +186,73 @@ func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 		}
 	}
 }
+
+// TestPutObjectPartConcurrentUploads - test that parts of concurrent
+// multipart uploads on the same object do not block each other.
+func TestPutObjectPartConcurrentUploads(t *testing.T) {
+	// Prepare for tests
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+
+	bucketName := "bucket"
+	objectName := "object"
+
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal("Cannot create bucket, err: ", err)
+	}
+
+	slowUploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	data := []byte("12345")
+
+	// Start a part upload whose body is stalled half way.
+	pipeReader, pipeWriter := io.Pipe()
+	slowDoneCh := make(chan error, 1)
+	go func() {
+		_, perr := obj.PutObjectPart(bucketName, objectName, slowUploadID, 1, int64(len(data)), pipeReader, "", "")
+		slowDoneCh <- perr
+	}()
+	if _, err = pipeWriter.Write(data[:2]); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// A part for the other upload must go through meanwhile.
+	doneCh := make(chan error, 1)
+	go func() {
+		_, perr := obj.PutObjectPart(bucketName, objectName, uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+		doneCh <- perr
+	}()
+	select {
+	case err = <-doneCh:
+		if err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("PutObjectPart blocked by a part upload of another uploadID")
+	}
+
+	// Finish the stalled upload.
+	if _, err = pipeWriter.Write(data[2:]); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	pipeWriter.Close()
+	if err = <-slowDoneCh; err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// Both uploads are intact, the last one to complete wins.
+	for _, id := range []string{slowUploadID, uploadID} {
+		parts := []completePart{{PartNumber: 1, ETag: "827ccb0eea8a706c4c34a16891f84e7b"}}
+		if _, err = obj.CompleteMultipartUpload(bucketName, objectName, id, parts); err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "io"
+
+// GetBucketVersioning - returns the versioning status of a bucket.
+func (fs fsObjects) GetBucketVersioning(bucket string) (string, error) {
+	return getBucketVersioning(fs, bucket)
+}
+
+// SetBucketVersioning - enables or suspends versioning on a bucket.
+func (fs fsObjects) SetBucketVersioning(bucket, status string) error {
+	return setBucketVersioning(fs, bucket, status)
+}
+
+// GetObjectVersion - reads a version of an object.
+func (fs fsObjects) GetObjectVersion(bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	return getObjectVersion(fs, bucket, object, versionID, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - returns info of a version of an object.
+func (fs fsObjects) GetObjectVersionInfo(bucket, object, versionID string) (ObjectInfo, error) {
+	return getObjectVersionInfo(fs, bucket, object, versionID)
+}
+
+// DeleteObjectVersion - permanently deletes a version of an object.
+func (fs fsObjects) DeleteObjectVersion(bucket, object, versionID string) error {
+	return deleteObjectVersion(fs, bucket, object, versionID, fs.deleteObject)
+}
+
+// ListObjectVersions - lists versions of objects of a bucket.
+func (fs fsObjects) ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	return listObjectVersions(fs, bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+}
+
+// linkObjectData - hard links the data of an object to another object,
+// whose `fs.json` is not written.
+func (fs fsObjects) linkObjectData(srcBucket, srcObject, dstBucket, dstObject string) error {
+	return fsLinkFile(pathJoin(fs.fsPath, srcBucket, srcObject), pathJoin(fs.fsPath, dstBucket, dstObject))
+}
@@ -137,6 +137,11 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to recognize backend format, Disk is not in FS format. %s", format.Format)
 	}
 
+	// Complete metadata updates interrupted by a crash or power loss.
+	if _, err = fsRecoverJournals(fsPath); err != nil {
+		return nil, fmt.Errorf("Unable to recover interrupted metadata updates, %s", errorCause(err))
+	}
+
 	// Initialize fs objects.
 	fs := &fsObjects{
 		fsPath:        fsPath,
@@ -158,6 +163,12 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to load all bucket policies. %s", err)
 	}
 
+	// Initialize and load the versioning status of buckets.
+	err = initBucketVersioning(fs)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load the versioning status of buckets. %s", err)
+	}
+
 	// Initialize a new event notifier.
 	err = initEventNotifier(fs)
 	if err != nil {
@@ -329,6 +340,11 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 		return toObjectErr(err, bucket)
 	}
 
+	// Buckets are not empty while noncurrent versions of objects are kept.
+	if err = checkBucketVersionsEmpty(fs, bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
 	// Attempt to delete regular bucket.
 	if err = fsRemoveDir(bucketDir); err != nil {
 		return toObjectErr(err, bucket)
@@ -368,21 +384,42 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	// Check if this request is only metadata update.
 	cpMetadataOnly := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	if cpMetadataOnly {
+		// Keep the previous version if versioning is enabled or
+		// suspended on the bucket.
+		var versionDone func(committed bool)
+		versionDone, err = preserveObjectMetadataVersion(fs, srcBucket, srcObject, metadata)
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+
 		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, srcBucket, srcObject, fsMetaJSONFile)
 		var wlk *lock.LockedFile
 		wlk, err = fs.rwPool.Write(fsMetaPath)
 		if err != nil {
+			versionDone(false)
 			return ObjectInfo{}, toObjectErr(traceError(err), srcBucket, srcObject)
 		}
 		// This close will allow for locks to be synchronized on `fs.json`.
 		defer wlk.Close()
 
 		// Save objects' metadata in `fs.json`.
+		modTime, setModTime := popObjectModTime(metadata)
 		fsMeta := newFSMetaV1()
 		fsMeta.Meta = metadata
 		if _, err = fsMeta.WriteTo(wlk); err != nil {
+			versionDone(false)
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
+		versionDone(true)
+		if setModTime {
+			srcPath := pathJoin(fs.fsPath, srcBucket, srcObject)
+			if err = fsSetModTime(srcPath, modTime); err != nil {
+				return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+			}
+			if fi, err = fsStatFile(srcPath); err != nil {
+				return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+			}
+		}
 
 		// Return the new object info.
 		return fsMeta.ToObjectInfo(srcBucket, srcObject, fi), nil
@@ -422,6 +459,9 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
 func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64, writer io.Writer) (err error) {
+	if hasSuffix(object, slashSeparator) {
+		return fs.getObjectDir(bucket, object, offset, length, writer)
+	}
 	if err = checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -440,17 +480,31 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 		return toObjectErr(traceError(errUnexpected), bucket, object)
 	}
 
+	// Adopted objects have no md5Sum until their first complete read.
+	var md5Pending bool
 	if bucket != minioMetaBucket {
 		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
-		_, err = fs.rwPool.Open(fsMetaPath)
+		var rlk *lock.RLockedFile
+		rlk, err = fs.rwPool.Open(fsMetaPath)
 		if err != nil && err != errFileNotFound {
 			return toObjectErr(traceError(err), bucket, object)
 		}
+		if err == nil {
+			fsMeta := fsMetaV1{}
+			_, rerr := fsMeta.ReadFrom(rlk.LockedFile)
+			md5Pending = rerr == nil && fsMeta.Meta["md5Sum"] == ""
+		}
 		defer fs.rwPool.Close(fsMetaPath)
 	}
 
 	// Read the object, doesn't exist returns an s3 compatible error.
 	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	var fi os.FileInfo
+	if md5Pending {
+		if fi, err = fsStatFile(fsObjPath); err != nil {
+			return toObjectErr(err, bucket, object)
+		}
+	}
 	reader, size, err := fsOpenFile(fsObjPath, offset)
 	if err != nil {
 		return toObjectErr(err, bucket, object)
@@ -473,9 +527,23 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 	}
 
 	// Allocate a staging buffer.
-	buf := make([]byte, int(bufSize))
+	buf, err := globalBufferPool.Get(bufSize)
+	if err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+	defer globalBufferPool.Put(buf)
+
+	var md5Writer hash.Hash
+	if md5Pending && offset == 0 && length == size {
+		md5Writer = md5.New()
+		writer = io.MultiWriter(writer, md5Writer)
+	}
 
 	_, err = io.CopyBuffer(writer, io.LimitReader(reader, length), buf)
+	if err == nil && md5Writer != nil {
+		// Saved in the background, fs.json is read locked until we return.
+		go fs.saveAdoptedMD5(bucket, object, hex.EncodeToString(md5Writer.Sum(nil)), fi.ModTime(), fi.Size())
+	}
 
 	return toObjectErr(traceError(err), bucket, object)
 }
@@ -517,6 +585,9 @@ func (fs fsObjects) getObjectInfo(bucket, object string) (ObjectInfo, error) {
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
 func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	if hasSuffix(object, slashSeparator) {
+		return fs.getObjectDirInfo(bucket, object)
+	}
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -533,15 +604,17 @@ func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 // Additionally writes `fs.json` which carries the necessary metadata
 // for future object operations.
 func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
-	// This is a special case with size as '0' and object ends with
-	// a slash separator, we treat it like a valid operation and
-	// return success.
+	// Zero byte objects ending with a slash separator are
+	// directory objects.
 	if isObjectDir(object, size) {
-		return dirObjectInfo(bucket, object, size, metadata), nil
+		return fs.putObjectDir(bucket, object, metadata)
 	}
 	if err = checkPutObjectArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
+	if err = globalStrictNamesPolicy.checkStrictObjectName(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
 
 	if _, err = fs.statBucketDir(bucket); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket)
@@ -551,6 +624,17 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	if metadata == nil {
 		metadata = make(map[string]string)
 	}
+	modTime, setModTime := popObjectModTime(metadata)
+
+	// Keep the previous version if versioning is enabled or suspended
+	// on the bucket.
+	versionDone, err := preserveObjectVersion(fs, bucket, object, metadata)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	defer func() {
+		versionDone(err == nil)
+	}()
 
 	fsMeta := newFSMetaV1()
 	fsMeta.Meta = metadata
@@ -598,7 +682,11 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	if size > 0 && bufSize > size {
 		bufSize = size
 	}
-	buf := make([]byte, int(bufSize))
+	buf, err := globalBufferPool.Get(bufSize)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+	}
+	defer globalBufferPool.Put(buf)
 	teeReader := io.TeeReader(limitDataReader, multiWriter)
 	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tempObj)
 	bytesWritten, err := fsCreateFile(fsTmpObjPath, teeReader, buf, size)
@@ -642,6 +730,13 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
+	// The modification time of the object is the one of its file.
+	if setModTime {
+		if err = fsSetModTime(fsTmpObjPath, modTime); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	}
+
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
 	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
@@ -668,6 +763,9 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 // DeleteObject - deletes an object from a bucket, this operation is destructive
 // and there are no rollbacks supported.
 func (fs fsObjects) DeleteObject(bucket, object string) error {
+	if hasSuffix(object, slashSeparator) {
+		return fs.deleteObjectDir(bucket, object)
+	}
 	if err := checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -676,6 +774,19 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 		return toObjectErr(err, bucket)
 	}
 
+	// Keep the current version and a delete marker if versioning is
+	// enabled or suspended on the bucket.
+	versionDone, err := preserveDeletedObject(fs, bucket, object)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	err = fs.deleteObject(bucket, object)
+	versionDone(err == nil)
+	return err
+}
+
+// deleteObject - deletes an object and its `fs.json`.
+func (fs fsObjects) deleteObject(bucket, object string) error {
 	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
 	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
 	if bucket != minioMetaBucket {
@@ -765,7 +876,16 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	}
 
 	// Convert entry to ObjectInfo
-	entryToObjectInfo := func(entry string) (objInfo ObjectInfo, err error) {
+	entryToObjectInfo := func(walkResult treeWalkResult) (objInfo ObjectInfo, err error) {
+		entry := walkResult.entry
+		if walkResult.dirObject {
+			var fi os.FileInfo
+			fi, err = fsStatDir(pathJoin(fs.fsPath, bucket, entry))
+			if err != nil {
+				return ObjectInfo{}, toObjectErr(err, bucket, entry)
+			}
+			return dirObjectInfo(bucket, entry, fi.ModTime()), nil
+		}
 		if hasSuffix(entry, slashSeparator) {
 			// Object name needs to be full path.
 			objInfo.Name = entry
@@ -786,14 +906,10 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	walkResultCh, endWalkCh := fs.listPool.Release(listParams{bucket, recursive, marker, prefix, heal})
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
-		isLeaf := func(bucket, object string) bool {
-			// bucket argument is unused as we don't need to StatFile
-			// to figure if it's a file, just need to check that the
-			// object string does not end with "/".
-			return !hasSuffix(object, slashSeparator)
-		}
-		listDir := fs.listDirFactory(isLeaf)
-		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
+		// Objects are files without a trailing "/", the sorted entries
+		// are in listing order without any isLeaf check. Directories
+		// are streamed to keep large ones out of memory.
+		walkResultCh = startTreeWalkStream(bucket, prefix, marker, recursive, fs.listDirStream, fs.isObjectDir, endWalkCh)
 	}
 
 	var objInfos []ObjectInfo
@@ -816,7 +932,7 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 			}
 			return ListObjectsInfo{}, toObjectErr(walkResult.err, bucket, prefix)
 		}
-		objInfo, err := entryToObjectInfo(walkResult.entry)
+		objInfo, err := entryToObjectInfo(walkResult)
 		if err != nil {
 			return ListObjectsInfo{}, nil
 		}
@@ -832,7 +948,13 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 	// Save list routine for the next marker if we haven't reached EOF.
 	params := listParams{bucket, recursive, nextMarker, prefix, heal}
 	if !eof {
-		fs.listPool.Set(params, walkResultCh, endWalkCh)
+		if globalStrictConsistency {
+			// The walk has run ahead of this page, the next page
+			// walks again to include objects written meanwhile.
+			close(endWalkCh)
+		} else {
+			fs.listPool.Set(params, walkResultCh, endWalkCh)
+		}
 	}
 
 	result := ListObjectsInfo{IsTruncated: !eof}
@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"bufio"
+	"crypto/x509"
 	"net"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
@@ -131,6 +133,26 @@ func (h redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
+// Adds standard security headers to all responses.
+type securityHeadersHandler struct {
+	handler http.Handler
+}
+
+func setSecurityHeadersHandler(h http.Handler) http.Handler {
+	return securityHeadersHandler{h}
+}
+
+func (h securityHeadersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Prevent browsers from MIME-sniffing content away from the declared type.
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// HSTS is only honored when delivered over TLS, ask browsers to
+	// stick to https for one year.
+	if r.TLS != nil {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
 // Adds Cache-Control header
 type cacheControlHandler struct {
 	handler http.Handler
@@ -171,12 +193,77 @@ func (h minioPrivateBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	// For all non browser requests, reject access to 'minioReservedBucketPath'.
 	bucketName, _ := urlPath2BucketObjectName(r.URL)
 	if !guessIsBrowserReq(r) && isMinioReservedBucket(bucketName) && isMinioMetaBucket(bucketName) {
-		writeErrorResponse(w, ErrAllAccessDisabled, r.URL)
+		writeErrorResponse(w, ErrAllAccessDisabled, r)
 		return
 	}
 	h.handler.ServeHTTP(w, r)
 }
 
+// Inter-node RPC path prefixes, all of them are served under
+// minioReservedBucketPath.
+var rpcPathPrefixes = []string{
+	path.Join(minioReservedBucketPath, adminPath),
+	path.Join(minioReservedBucketPath, lockRPCPath),
+	path.Join(minioReservedBucketPath, storageRPCPath),
+	path.Join(minioReservedBucketPath, s3Path),
+	path.Join(minioReservedBucketPath, browserPeerPath),
+}
+
+// guessIsRPCReq - returns true if the request is for an inter-node RPC path.
+func guessIsRPCReq(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	// Versioned admin API shares its prefix with the admin RPC path.
+	if req.URL.Path == adminAPIPathPrefix || hasPrefix(req.URL.Path, adminAPIPathPrefix+"/") {
+		return false
+	}
+	for _, prefix := range rpcPathPrefixes {
+		if req.URL.Path == prefix || hasPrefix(req.URL.Path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type rpcClientAuthHandler struct {
+	handler http.Handler
+}
+
+// setRPCClientAuthHandler rejects inter-node RPC requests which are not
+// authenticated with a TLS client certificate verified by the CAs under
+// certs/CAs and issued to one of the nodes of the setup, only active
+// when MINIO_RPC_CLIENT_AUTH is turned on.
+func setRPCClientAuthHandler(h http.Handler) http.Handler {
+	return rpcClientAuthHandler{h}
+}
+
+// isPeerCertificate - returns true if cert is issued to the host of one
+// of the endpoints of the setup, as nodes are dialed by these names.
+func isPeerCertificate(cert *x509.Certificate) bool {
+	for _, ep := range globalEndpoints {
+		host, _, err := net.SplitHostPort(ep.Host)
+		if err != nil {
+			host = ep.Host
+		}
+		if host != "" && cert.VerifyHostname(host) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (h rpcClientAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if globalRPCClientAuth && guessIsRPCReq(r) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+			!isPeerCertificate(r.TLS.VerifiedChains[0][0]) {
+			writeErrorResponse(w, ErrAccessDenied, r)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
 type timeValidityHandler struct {
 	handler http.Handler
 }
@@ -233,14 +320,14 @@ func (h timeValidityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			// All our internal APIs are sensitive towards Date
 			// header, for all requests where Date header is not
 			// present we will reject such clients.
-			writeErrorResponse(w, apiErr, r.URL)
+			writeErrorResponse(w, apiErr, r)
 			return
 		}
 		// Verify if the request date header is shifted by less than globalMaxSkewTime parameter in the past
 		// or in the future, reject request otherwise.
 		curTime := time.Now().UTC()
 		if curTime.Sub(amzDate) > globalMaxSkewTime || amzDate.Sub(curTime) > globalMaxSkewTime {
-			writeErrorResponse(w, ErrRequestTimeTooSkewed, r.URL)
+			writeErrorResponse(w, ErrRequestTimeTooSkewed, r)
 			return
 		}
 	}
@@ -314,13 +401,10 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 var notimplementedBucketResourceNames = map[string]bool{
 	"acl":            true,
 	"cors":           true,
-	"lifecycle":      true,
 	"logging":        true,
 	"replication":    true,
 	"tagging":        true,
-	"versions":       true,
 	"requestPayment": true,
-	"versioning":     true,
 	"website":        true,
 }
 
@@ -329,6 +413,7 @@ var notimplementedObjectResourceNames = map[string]bool{
 	"torrent": true,
 	"acl":     true,
 	"policy":  true,
+	"restore": true,
 }
 
 // Resource handler ServeHTTP() wrapper
@@ -338,20 +423,20 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// If bucketName is present and not objectName check for bucket level resource queries.
 	if bucketName != "" && objectName == "" {
 		if ignoreNotImplementedBucketResources(r) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
+			writeErrorResponse(w, ErrNotImplemented, r)
 			return
 		}
 	}
 	// If bucketName and objectName are present check for its resource queries.
 	if bucketName != "" && objectName != "" {
 		if ignoreNotImplementedObjectResources(r) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
+			writeErrorResponse(w, ErrNotImplemented, r)
 			return
 		}
 	}
 	// A put method on path "/" doesn't make sense, ignore it.
 	if r.Method == httpPUT && r.URL.Path == "/" && r.Header.Get(minioAdminOpHeader) == "" {
-		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		writeErrorResponse(w, ErrNotImplemented, r)
 		return
 	}
 
@@ -364,11 +449,15 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 type httpResponseRecorder struct {
 	http.ResponseWriter
 	respStatusCode int
+	respBytes      int64
 }
 
-// Wraps ResponseWriter's Write()
+// Wraps ResponseWriter's Write() and record the number
+// of bytes written
 func (rww *httpResponseRecorder) Write(b []byte) (int, error) {
-	return rww.ResponseWriter.Write(b)
+	n, err := rww.ResponseWriter.Write(b)
+	rww.respBytes += int64(n)
+	return n, err
 }
 
 // Wraps ResponseWriter's Flush()
@@ -402,8 +491,23 @@ func (h httpStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ww := &httpResponseRecorder{ResponseWriter: w}
 
 	// Execute the request
+	startTime := time.Now()
 	h.handler.ServeHTTP(ww, r)
 
 	// Update http statistics
 	globalHTTPStats.updateStats(r, ww)
+
+	// Inter-node RPC connections are not API requests.
+	if r.Method != http.MethodConnect {
+		status := ww.respStatusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		globalStatsd.recordRequest(r.Method, status, time.Since(startTime))
+	}
+
+	// Server errors count towards the error-rate alert.
+	if ww.respStatusCode >= http.StatusInternalServerError {
+		countErrorForAlert()
+	}
 }
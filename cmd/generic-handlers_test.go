@@ -17,7 +17,12 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -88,3 +93,116 @@ func TestGuessIsBrowser(t *testing.T) {
 		t.Fatal("Test shouldn't report as browser for a non browser request.")
 	}
 }
+
+// Tests guessIsRPCReq function for all its criteria.
+func TestGuessIsRPCReq(t *testing.T) {
+	if guessIsRPCReq(nil) {
+		t.Fatal("Unexpected return for nil request")
+	}
+	testCases := []struct {
+		reqPath string
+		isRPC   bool
+	}{
+		{"/minio/admin", true},
+		{"/minio/lock/mnt/disk1", true},
+		{"/minio/storage/mnt/disk1", true},
+		{"/minio/s3/remote", true},
+		{"/minio/browser/setauth", true},
+		{"/minio/webrpc", false},
+		{"/minio/administrator", false},
+		{"/minio/admin/v1", false},
+		{"/minio/admin/v1/service", false},
+		{"/bucket/object", false},
+		{"/", false},
+	}
+	for i, testCase := range testCases {
+		r, err := http.NewRequest("POST", "http://localhost:9000"+testCase.reqPath, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to create request, %s", i+1, err)
+		}
+		if isRPC := guessIsRPCReq(r); isRPC != testCase.isRPC {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.isRPC, isRPC)
+		}
+	}
+}
+
+// Tests rpcClientAuthHandler rejects unauthenticated RPC requests.
+func TestRPCClientAuthHandler(t *testing.T) {
+	defer func() { globalRPCClientAuth = false }()
+	defer func(endpoints []*url.URL) { globalEndpoints = endpoints }(globalEndpoints)
+	globalEndpoints = []*url.URL{
+		{Scheme: "https", Host: "node1.example.com:9000", Path: "/mnt/disk1"},
+		{Scheme: "https", Host: "192.168.1.12:9000", Path: "/mnt/disk2"},
+	}
+
+	handler := setRPCClientAuthHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	verifiedTLSFor := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+	verifiedTLS := verifiedTLSFor(&x509.Certificate{DNSNames: []string{"node1.example.com"}})
+	verifiedIPTLS := verifiedTLSFor(&x509.Certificate{IPAddresses: []net.IP{net.ParseIP("192.168.1.12")}})
+	// Verified, but issued to a host which is not part of the setup.
+	otherTLS := verifiedTLSFor(&x509.Certificate{DNSNames: []string{"www.example.com"}})
+
+	testCases := []struct {
+		clientAuth     bool
+		reqPath        string
+		tlsState       *tls.ConnectionState
+		expectedStatus int
+	}{
+		// Client auth disabled, everything passes through.
+		{false, "/minio/storage/mnt/disk1", nil, http.StatusOK},
+		// Non RPC requests do not need a client certificate.
+		{true, "/bucket/object", nil, http.StatusOK},
+		{true, "/minio/storage/mnt/disk1", nil, http.StatusForbidden},
+		{true, "/minio/lock/mnt/disk1", &tls.ConnectionState{}, http.StatusForbidden},
+		{true, "/minio/admin", verifiedTLS, http.StatusOK},
+		{true, "/minio/lock/mnt/disk1", verifiedIPTLS, http.StatusOK},
+		{true, "/minio/storage/mnt/disk1", otherTLS, http.StatusForbidden},
+	}
+	for i, testCase := range testCases {
+		globalRPCClientAuth = testCase.clientAuth
+		r, err := http.NewRequest("POST", "http://localhost:9000"+testCase.reqPath, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to create request, %s", i+1, err)
+		}
+		r.TLS = testCase.tlsState
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+	}
+}
+
+// Tests security headers are set on all responses.
+func TestSecurityHeadersHandler(t *testing.T) {
+	handler := setSecurityHeadersHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		tlsState     *tls.ConnectionState
+		expectedHSTS string
+	}{
+		{nil, ""},
+		{&tls.ConnectionState{}, "max-age=31536000"},
+	}
+	for i, testCase := range testCases {
+		r, err := http.NewRequest("GET", "http://localhost:9000/bucket/object", nil)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to create request, %s", i+1, err)
+		}
+		r.TLS = testCase.tlsState
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		if v := rec.Header().Get("X-Content-Type-Options"); v != "nosniff" {
+			t.Errorf("Test %d: Expected X-Content-Type-Options nosniff, got %s", i+1, v)
+		}
+		if v := rec.Header().Get("Strict-Transport-Security"); v != testCase.expectedHSTS {
+			t.Errorf("Test %d: Expected Strict-Transport-Security %s, got %s", i+1, testCase.expectedHSTS, v)
+		}
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"net/url"
 	"runtime"
@@ -24,6 +25,7 @@ import (
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
+	"github.com/minio/minio/pkg/bpool"
 )
 
 // minio configuration related constants.
@@ -55,6 +57,10 @@ const (
 
 	// The maximum allowed difference between the request generation time and the server processing time
 	globalMaxSkewTime = 15 * time.Minute
+
+	// The maximum time a request waits for buffer memory before it is
+	// rejected with SlowDown.
+	globalBufferPoolTimeout = 1 * time.Minute
 )
 
 var (
@@ -80,6 +86,9 @@ var (
 	globalMinioPort = "9000"
 	// Holds the host that was passed using --address
 	globalMinioHost = ""
+	// Holds the hosts to bind to passed using --listeners, the host of
+	// --address is bound to if empty.
+	globalListenHosts []string
 
 	// Holds the list of API endpoints for a given server.
 	globalAPIEndpoints = []string{}
@@ -102,6 +111,9 @@ var (
 	// url.URL endpoints of disks that belong to the object storage.
 	globalEndpoints = []*url.URL{}
 
+	// Failure domains of the endpoints, nil unless configured.
+	globalFailureDomains []FailureDomainInfo
+
 	// Global server's network statistics
 	globalConnStats = newConnStats()
 
@@ -111,6 +123,134 @@ var (
 	// Time when object layer was initialized on start up.
 	globalBootTime time.Time
 
+	// Maximum number of statements allowed in a bucket policy, can be
+	// changed through MINIO_POLICY_MAX_STATEMENTS env.
+	globalMaxPolicyStatements = defaultMaxPolicyStatements
+
+	// Maximum size of the user metadata of an object, can be changed
+	// through MINIO_METADATA_MAX_SIZE env.
+	globalMaxUserMetadataSize int64 = defaultMaxUserMetadataSize
+
+	// Maximum size of objects uploaded or copied in a single request,
+	// can be changed through MINIO_OBJECT_MAX_SIZE env.
+	globalMaxObjectSize int64 = defaultMaxObjectSize
+
+	// Minimum and maximum size of the parts of a multipart upload, can
+	// be changed through MINIO_PART_MIN_SIZE and MINIO_PART_MAX_SIZE
+	// env. The last part may be smaller than the minimum.
+	globalMinPartSize int64 = defaultMinPartSize
+	globalMaxPartSize int64 = defaultMaxPartSize
+
+	// Highest part number of a multipart upload, can be changed through
+	// MINIO_UPLOAD_MAX_PARTS env.
+	globalMaxPartID = defaultMaxPartID
+
+	// Maximum size of objects uploaded with chunked transfer encoding
+	// and no Content-Length, can be changed through
+	// MINIO_CHUNKED_UPLOAD_MAX_SIZE env.
+	globalMaxChunkedUploadSize = globalMaxObjectSize
+
+	// Set to true if inter-node RPC requests must present a TLS client
+	// certificate signed by a trusted CA, set via MINIO_RPC_CLIENT_AUTH env.
+	globalRPCClientAuth = false
+
+	// CAs installed under certs/CAs which must have signed the client
+	// certificates of peers when globalRPCClientAuth is set. The system
+	// CAs are never trusted for this.
+	globalRPCClientCAs *x509.CertPool
+
+	// Set to true to restrict TLS to FIPS approved cipher suites and
+	// curves, set via MINIO_TLS_FIPS env.
+	globalTLSFIPS = false
+
+	// Minimum TLS version and cipher suites accepted by the server, set
+	// via MINIO_TLS_MIN_VERSION and MINIO_TLS_CIPHERS env. An empty list
+	// of cipher suites means the defaults are used.
+	globalTLSMinVersion   uint16 = tls.VersionTLS12
+	globalTLSCipherSuites []uint16
+
+	// Access logger, nil unless enabled via MINIO_ACCESS_LOG env.
+	globalAccessLogger *accessLogger
+
+	// Alerter, nil unless an alert target is configured.
+	globalAlerter *alerter
+
+	// Tracer, nil unless a tracing endpoint is configured.
+	globalTracer *tracer
+
+	// StatsD metrics emitter, nil unless a StatsD address is
+	// configured.
+	globalStatsd *statsdEmitter
+
+	// Bucket creation policy, nil unless bucket creation is restricted.
+	globalBucketCreationPolicy *bucketCreationPolicy
+
+	// Strict names policy, nil unless a bucket is in strict names mode.
+	globalStrictNamesPolicy *strictNamesPolicy
+
+	// Content type policy, nil unless sniffing or a bucket mapping is
+	// configured.
+	globalContentTypePolicy *contentTypePolicy
+
+	// Admin API only credentials, nil unless configured.
+	globalAdminCredentials adminCredentials
+
+	// Tracks requests against the limits of their access keys.
+	globalRequestLimiter = newRequestLimiter()
+
+	// Tracks the use of browser sessions to close idle ones.
+	globalBrowserSessions = newBrowserSessionTracker()
+
+	// Addresses of the hosts of endpoints found by service discovery.
+	globalPeerResolver = &peerResolver{}
+
+	// Hands out the sequencers of notification events.
+	globalEventSequencer = newEventSequencer()
+
+	// Set to true if MINIO_SOURCE_METADATA env is "on", exposes
+	// preserved X-Minio-Source-* metadata in object responses.
+	globalExposeSourceMetadata = false
+
+	// Set to true if MINIO_NORMALIZE_OBJECT_NAMES env is "on", new
+	// object names are normalized to Unicode NFC.
+	globalNormalizeObjectNames = false
+
+	// Pool of erasure coding and copy buffers, capped by the memory
+	// limit set with MINIO_MEMORY_LIMIT env.
+	globalBufferPool = bpool.NewCappedPool(0, globalBufferPoolTimeout)
+
+	// Bounds concurrent erasure encode and decode operations, set
+	// with MINIO_ERASURE_WORKERS env.
+	globalErasureWorkers = newErasureWorkers(runtime.GOMAXPROCS(0))
+
+	// Memory used for ranges prefetched after aligned range reads,
+	// set with MINIO_READAHEAD_CACHE env, 0 if read ahead is disabled.
+	globalReadAheadCacheSize int64
+
+	// Format of new `xl.json`, set with MINIO_XL_META_FORMAT env.
+	globalXLMetaFormat = xlMetaFormatJSON
+
+	// Set to true if MINIO_CONSISTENCY env is "strict", listings and
+	// object info reflect every completed write even on degraded sets.
+	globalStrictConsistency = false
+
+	// Grant policy of namespace locks, set with MINIO_LOCK_POLICY env.
+	globalLockPolicy = lockPolicyFIFO
+
+	// Set to true if MINIO_LOCKLESS_READS env is "on", GET and HEAD
+	// object requests do not take namespace read locks.
+	globalLocklessReads = false
+
+	// Rate in bytes per second of deep scrubbing set with
+	// MINIO_DEEP_SCRUB env, 0 if deep scrubbing is disabled.
+	globalDeepScrubRate int64
+
+	// Interval between janitor passes and age of the stale data they
+	// remove, set with MINIO_JANITOR_INTERVAL and MINIO_JANITOR_AGE.
+	// The interval is 0 if the janitor is disabled.
+	globalJanitorInterval = defaultJanitorInterval
+	globalJanitorAge      = defaultJanitorAge
+
 	// Add new variable global values here.
 )
 
@@ -18,9 +18,13 @@ package cmd
 
 import (
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"sort"
 	"strings"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // Validates location constraint in PutBucket request body.
@@ -86,6 +90,41 @@ func isMetadataReplace(h http.Header) bool {
 	return h.Get("X-Amz-Metadata-Directive") == "REPLACE"
 }
 
+// isChunkedRequest - returns true if the request body is sent with
+// chunked transfer encoding, its size is then only known at EOF.
+func isChunkedRequest(r *http.Request) bool {
+	for _, encoding := range r.TransferEncoding {
+		if encoding == "chunked" {
+			return true
+		}
+	}
+	return false
+}
+
+// maxSizeReader - reads from reader, failing with errDataTooLarge once
+// more than max bytes were read.
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func newMaxSizeReader(reader io.Reader, max int64) io.Reader {
+	return &maxSizeReader{reader, max}
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	// Read one byte more than allowed to detect oversized data.
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.reader.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return 0, errDataTooLarge
+	}
+	return n, err
+}
+
 // Splits an incoming path into bucket and object components.
 func path2BucketAndObject(path string) (bucket, object string) {
 	// Skip the first element if it is '/', split the rest.
@@ -103,6 +142,63 @@ func path2BucketAndObject(path string) (bucket, object string) {
 	return bucket, object
 }
 
+// Metadata keys preserving the ETag and Last-Modified of the object an
+// object was copied or migrated from.
+const (
+	minioSourceMetaPrefix      = "X-Minio-Source-"
+	minioSourceETagKey         = minioSourceMetaPrefix + "Etag"
+	minioSourceLastModifiedKey = minioSourceMetaPrefix + "Last-Modified"
+)
+
+// setSourceMetadata - records the ETag and Last-Modified of srcInfo
+// in metadata. If the source was itself copied its preserved values
+// are kept, so that they refer to the original object.
+func setSourceMetadata(metadata map[string]string, srcInfo ObjectInfo) {
+	if etag, ok := srcInfo.UserDefined[minioSourceETagKey]; ok {
+		metadata[minioSourceETagKey] = etag
+	} else if srcInfo.MD5Sum != "" {
+		metadata[minioSourceETagKey] = srcInfo.MD5Sum
+	}
+	if lastModified, ok := srcInfo.UserDefined[minioSourceLastModifiedKey]; ok {
+		metadata[minioSourceLastModifiedKey] = lastModified
+	} else {
+		metadata[minioSourceLastModifiedKey] = srcInfo.ModTime.UTC().Format(http.TimeFormat)
+	}
+}
+
+// Prefix of user metadata keys.
+const userMetaPrefix = "X-Amz-Meta-"
+
+// Default maximum size of the user metadata of an object, S3 allows
+// 2KiB.
+const defaultMaxUserMetadataSize = 8 * humanize.KiByte
+
+// userMetadataSize - returns the size of the user metadata in
+// metadata, the sum of the lengths of the UTF-8 encoded keys, without
+// the X-Amz-Meta- prefix, and values.
+func userMetadataSize(metadata map[string]string) int64 {
+	var size int64
+	for key, value := range metadata {
+		if strings.HasPrefix(key, userMetaPrefix) {
+			size += int64(len(key) - len(userMetaPrefix) + len(value))
+		}
+	}
+	return size
+}
+
+// isMetadataTooLarge - returns true if the user metadata in metadata
+// exceeds globalMaxUserMetadataSize.
+func isMetadataTooLarge(metadata map[string]string) bool {
+	return userMetadataSize(metadata) > globalMaxUserMetadataSize
+}
+
+// encodeUserMetadataValue - returns value RFC 2047 encoded if it holds
+// non US-ASCII characters, which cannot be sent in HTTP headers
+// otherwise. Other values are returned as is.
+func encodeUserMetadataValue(value string) string {
+	return mime.BEncoding.Encode("UTF-8", value)
+}
+
 // extractMetadataFromHeader extracts metadata from HTTP header.
 func extractMetadataFromHeader(header http.Header) map[string]string {
 	metadata := make(map[string]string)
@@ -118,12 +214,28 @@ func extractMetadataFromHeader(header http.Header) map[string]string {
 		}
 	}
 	// Go through all other headers for any additional headers that needs to be saved.
+	// Keys are visited in sorted order, so that values of keys differing
+	// only in case are joined in the same order on every request.
+	keys := make([]string, 0, len(header))
 	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
 		cKey := http.CanonicalHeaderKey(key)
-		if strings.HasPrefix(cKey, "X-Amz-Meta-") {
-			metadata[cKey] = header.Get(key)
+		if strings.HasPrefix(cKey, userMetaPrefix) {
+			// Repeated user metadata headers are joined with
+			// a comma, like S3 does.
+			values := header[key]
+			if value, ok := metadata[cKey]; ok {
+				values = append([]string{value}, values...)
+			}
+			metadata[cKey] = strings.Join(values, ",")
 		} else if strings.HasPrefix(key, "X-Minio-Meta-") {
 			metadata[cKey] = header.Get(key)
+		} else if strings.HasPrefix(cKey, minioSourceMetaPrefix) && cKey != minioSourceMtimeHeader {
+			// Preserved source metadata set by migration tools.
+			metadata[cKey] = header.Get(key)
 		}
 	}
 	// Return.
@@ -147,7 +259,7 @@ func extractMetadataFromForm(formValues map[string]string) map[string]string {
 	// Go through all other form values for any additional headers that needs to be saved.
 	for key := range formValues {
 		cKey := http.CanonicalHeaderKey(key)
-		if strings.HasPrefix(cKey, "X-Amz-Meta-") {
+		if strings.HasPrefix(cKey, userMetaPrefix) {
 			metadata[cKey] = formValues[key]
 		} else if strings.HasPrefix(cKey, "X-Minio-Meta-") {
 			metadata[cKey] = formValues[key]
@@ -22,7 +22,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Tests validate bucket LocationConstraint.
@@ -115,6 +117,27 @@ func TestExtractMetadataHeaders(t *testing.T) {
 				"X-Amz-Meta-Appid":   "amz-meta",
 				"X-Minio-Meta-Appid": "minio-meta"},
 		},
+		// Validate if preserved source metadata is extracted.
+		{
+			header: http.Header{
+				"X-Minio-Source-Etag":          []string{"abcd"},
+				"X-Minio-Source-Last-Modified": []string{"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+			metadata: map[string]string{
+				"X-Minio-Source-Etag":          "abcd",
+				"X-Minio-Source-Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT"},
+		},
+		// Validate if repeated user metadata is joined in order,
+		// regardless of the case of the keys.
+		{
+			header: http.Header{
+				"X-Amz-Meta-Color": []string{"red", "green"},
+				"x-amz-meta-color": []string{"blue"},
+			},
+			metadata: map[string]string{
+				"X-Amz-Meta-Color": "red,green,blue",
+			},
+		},
 	}
 
 	// Validate if the extracting headers.
@@ -125,3 +148,65 @@ func TestExtractMetadataHeaders(t *testing.T) {
 		}
 	}
 }
+
+// Tests measuring user metadata against the size limit.
+func TestIsMetadataTooLarge(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		size     int64
+		tooLarge bool
+	}{
+		// Only user metadata is counted, without the key prefix.
+		{map[string]string{"X-Amz-Meta-Name": "value", "content-type": "text/plain"}, 9, false},
+		// UTF-8 values are counted in bytes.
+		{map[string]string{"X-Amz-Meta-Name": "wörld"}, 10, false},
+		// Metadata at the limit is allowed.
+		{map[string]string{"X-Amz-Meta-A": strings.Repeat("a", defaultMaxUserMetadataSize-1)}, defaultMaxUserMetadataSize, false},
+		// Metadata above the limit is not.
+		{map[string]string{"X-Amz-Meta-A": strings.Repeat("a", defaultMaxUserMetadataSize)}, defaultMaxUserMetadataSize + 1, true},
+	}
+	for i, testCase := range testCases {
+		if size := userMetadataSize(testCase.metadata); size != testCase.size {
+			t.Errorf("Test %d: Expected size %d, got %d", i+1, testCase.size, size)
+		}
+		if tooLarge := isMetadataTooLarge(testCase.metadata); tooLarge != testCase.tooLarge {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.tooLarge, tooLarge)
+		}
+	}
+}
+
+// Tests preserving source metadata of copied objects.
+func TestSetSourceMetadata(t *testing.T) {
+	modTime := time.Date(2017, time.March, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		srcInfo  ObjectInfo
+		expected map[string]string
+	}{
+		// Source ETag and Last-Modified are preserved.
+		{
+			srcInfo: ObjectInfo{MD5Sum: "abcd", ModTime: modTime},
+			expected: map[string]string{
+				minioSourceETagKey:         "abcd",
+				minioSourceLastModifiedKey: "Wed, 01 Mar 2017 10:00:00 GMT",
+			},
+		},
+		// Source which was itself copied keeps the original values.
+		{
+			srcInfo: ObjectInfo{MD5Sum: "abcd", ModTime: modTime, UserDefined: map[string]string{
+				minioSourceETagKey:         "efgh",
+				minioSourceLastModifiedKey: "Sun, 01 Jan 2017 10:00:00 GMT",
+			}},
+			expected: map[string]string{
+				minioSourceETagKey:         "efgh",
+				minioSourceLastModifiedKey: "Sun, 01 Jan 2017 10:00:00 GMT",
+			},
+		},
+	}
+	for i, testCase := range testCases {
+		metadata := make(map[string]string)
+		setSourceMetadata(metadata, testCase.srcInfo)
+		if !reflect.DeepEqual(metadata, testCase.expected) {
+			t.Errorf("Test %d: Expected %#v, got %#v", i+1, testCase.expected, metadata)
+		}
+	}
+}
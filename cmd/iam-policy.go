@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Canned policies of IAM users.
+const (
+	iamPolicyReadWrite = "readwrite"
+	iamPolicyReadOnly  = "readonly"
+	iamPolicyWriteOnly = "writeonly"
+)
+
+// iamCannedPolicies - policies which may be attached to IAM users by
+// name.
+var iamCannedPolicies = map[string]bucketPolicy{
+	iamPolicyReadWrite: newIAMCannedPolicy("s3:*"),
+	iamPolicyReadOnly: newIAMCannedPolicy("s3:GetBucketLocation", "s3:ListAllMyBuckets",
+		"s3:ListBucket", "s3:ListBucketVersions", "s3:GetObject", "s3:GetObjectVersion"),
+	iamPolicyWriteOnly: newIAMCannedPolicy("s3:PutObject"),
+}
+
+// newIAMCannedPolicy - returns a policy allowing actions on all buckets
+// and objects.
+func newIAMCannedPolicy(actions ...string) bucketPolicy {
+	return bucketPolicy{
+		Version: policyVariablesVersion,
+		Statements: []policyStatement{{
+			Actions:   set.CreateStringSet(actions...),
+			Effect:    "Allow",
+			Resources: set.CreateStringSet(bucketARNPrefix + "*"),
+		}},
+	}
+}
+
+// supportedIAMActionMap - lists all the actions which may be allowed
+// to IAM users, the actions of bucket policies and the operations
+// only the owner of buckets may perform otherwise.
+var supportedIAMActionMap = supportedActionMap.Union(set.CreateStringSet(
+	"s3:CreateBucket", "s3:DeleteBucket",
+	"s3:GetBucketPolicy", "s3:PutBucketPolicy", "s3:DeleteBucketPolicy",
+	"s3:GetBucketNotification", "s3:PutBucketNotification", "s3:ListenBucketNotification",
+	"s3:GetLifecycleConfiguration", "s3:PutLifecycleConfiguration",
+	"s3:GetBucketVersioning", "s3:PutBucketVersioning"))
+
+// isValidIAMActions - are actions valid in a user policy.
+func isValidIAMActions(actions set.StringSet) error {
+	// Statement actions cannot be empty.
+	if len(actions) == 0 {
+		return errors.New("Action list cannot be empty")
+	}
+	if unsupportedActions := actions.Difference(supportedIAMActionMap); !unsupportedActions.IsEmpty() {
+		return fmt.Errorf("Unsupported actions found: ‘%#v’, please validate your policy document", unsupportedActions)
+	}
+	return nil
+}
+
+// parseIAMPolicy - parses and validates a user policy. User policies
+// are bucket policies without principals, the user they are attached
+// to is their principal.
+func parseIAMPolicy(policyReader io.Reader, policy *bucketPolicy) error {
+	if err := json.NewDecoder(policyReader).Decode(policy); err != nil {
+		return err
+	}
+
+	// Policy version cannot be empty.
+	if len(policy.Version) == 0 {
+		return errors.New("Policy version cannot be empty")
+	}
+
+	// Policy statements cannot be empty.
+	if len(policy.Statements) == 0 {
+		return errors.New("Policy statement cannot be empty")
+	}
+
+	for _, statement := range policy.Statements {
+		if statement.Principal != nil {
+			return errors.New("Principal is not allowed in user policies, please validate your policy document")
+		}
+	}
+	return validatePolicyStatements(policy, isValidIAMActions)
+}
@@ -0,0 +1,63 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// Tests validation of user policies.
+func TestParseIAMPolicy(t *testing.T) {
+	testCases := []struct {
+		policy     string
+		shouldPass bool
+	}{
+		// Bucket operations may be allowed to users.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:CreateBucket", "s3:PutBucketPolicy"], "Resource": ["arn:aws:s3:::*"]}]}`, true},
+		// Policy variables refer to the user.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::home/${aws:username}/*"]}]}`, true},
+		// Users are the principal of their policies.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::*"]}]}`, false},
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:PutBucketAcl"], "Resource": ["arn:aws:s3:::*"]}]}`, false},
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["bucket/*"]}]}`, false},
+		{`{"Version": "2012-10-17", "Statement": []}`, false},
+		{`{"Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::*"]}]}`, false},
+		{`{"Version": `, false},
+	}
+	for i, testCase := range testCases {
+		var policy bucketPolicy
+		err := parseIAMPolicy(strings.NewReader(testCase.policy), &policy)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+
+	// Deny statements are ordered first.
+	var policy bucketPolicy
+	if err := parseIAMPolicy(strings.NewReader(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:*"], "Resource": ["arn:aws:s3:::*"]},
+		{"Effect": "Deny", "Action": ["s3:DeleteBucket"], "Resource": ["arn:aws:s3:::*"]}]}`), &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Statements[0].Effect != "Deny" {
+		t.Errorf("Expected the deny statement first, got %v", policy.Statements)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// iamUserConfig - an additional user of the S3 API, allowed the
+// actions of either a canned policy or a custom policy.
+type iamUserConfig struct {
+	SecretKey    string          `json:"secretKey"`
+	CannedPolicy string          `json:"cannedPolicy,omitempty"`
+	Policy       json.RawMessage `json:"policy,omitempty"`
+}
+
+// isEmpty - returns true if config removes the user.
+func (config iamUserConfig) isEmpty() bool {
+	return config.SecretKey == ""
+}
+
+// Maximum size of a user config, its policy along with its keys.
+const maxIAMUserConfigSize = maxAccessPolicySize + humanize.KiByte
+
+// iamUser - compiled form of iamUserConfig.
+type iamUser struct {
+	cred   credential
+	policy bucketPolicy
+}
+
+// iamUsers - IAM users by access key.
+type iamUsers map[string]iamUser
+
+// newIAMUser - validates and compiles the config of the user with
+// accessKey.
+func newIAMUser(accessKey string, config iamUserConfig) (iamUser, error) {
+	if err := validateAuthKeys(accessKey, config.SecretKey); err != nil {
+		return iamUser{}, err
+	}
+	var policy bucketPolicy
+	switch {
+	case config.CannedPolicy != "" && len(config.Policy) != 0:
+		return iamUser{}, fmt.Errorf("Access key %s has both a canned and a custom policy", accessKey)
+	case config.CannedPolicy != "":
+		var ok bool
+		if policy, ok = iamCannedPolicies[config.CannedPolicy]; !ok {
+			return iamUser{}, fmt.Errorf("Unknown canned policy %s for access key %s", config.CannedPolicy, accessKey)
+		}
+	case len(config.Policy) != 0:
+		if err := parseIAMPolicy(bytes.NewReader(config.Policy), &policy); err != nil {
+			return iamUser{}, fmt.Errorf("Invalid policy for access key %s: %v", accessKey, err)
+		}
+	default:
+		return iamUser{}, fmt.Errorf("Access key %s has no policy", accessKey)
+	}
+	return iamUser{
+		cred:   newCredentialWithKeys(accessKey, config.SecretKey),
+		policy: policy,
+	}, nil
+}
+
+// newIAMUsers - validates and compiles configs, access keys of IAM
+// users may be neither the server access key nor admin access keys.
+func newIAMUsers(configs map[string]iamUserConfig, serverCred credential, adminCreds []adminCredentialConfig) (iamUsers, error) {
+	users := make(iamUsers, len(configs))
+	for accessKey, config := range configs {
+		if err := checkIAMAccessKey(accessKey, serverCred, adminCreds); err != nil {
+			return nil, err
+		}
+		user, err := newIAMUser(accessKey, config)
+		if err != nil {
+			return nil, err
+		}
+		users[accessKey] = user
+	}
+	return users, nil
+}
+
+// checkIAMAccessKey - returns an error if accessKey of an IAM user is
+// already the server access key or an admin access key.
+func checkIAMAccessKey(accessKey string, serverCred credential, adminCreds []adminCredentialConfig) error {
+	if accessKey == serverCred.AccessKey {
+		return fmt.Errorf("Access key %s is already the server access key", accessKey)
+	}
+	for _, adminCred := range adminCreds {
+		if accessKey == adminCred.AccessKey {
+			return fmt.Errorf("Access key %s is already an admin access key", accessKey)
+		}
+	}
+	return nil
+}
+
+var (
+	globalIAMUsersMu sync.RWMutex
+	// IAM users by access key, replaced as a whole on changes.
+	globalIAMUsers iamUsers
+)
+
+// getIAMUser - returns the IAM user with accessKey.
+func getIAMUser(accessKey string) (iamUser, bool) {
+	globalIAMUsersMu.RLock()
+	defer globalIAMUsersMu.RUnlock()
+
+	user, ok := globalIAMUsers[accessKey]
+	return user, ok
+}
+
+// initIAMUsers - initializes the global IAM users from server config.
+func initIAMUsers() error {
+	users, err := newIAMUsers(serverConfig.GetUsers(), serverConfig.GetCredential(), serverConfig.GetAdminCredentials())
+	if err != nil {
+		return err
+	}
+
+	globalIAMUsersMu.Lock()
+	globalIAMUsers = users
+	globalIAMUsersMu.Unlock()
+	return nil
+}
+
+// setIAMUser - adds or replaces the IAM user with accessKey from now
+// on, removes it if config is empty, and saves it in the config.
+func setIAMUser(accessKey string, config iamUserConfig) error {
+	if !config.isEmpty() {
+		if err := checkIAMAccessKey(accessKey, serverConfig.GetCredential(), serverConfig.GetAdminCredentials()); err != nil {
+			return err
+		}
+		if _, err := newIAMUser(accessKey, config); err != nil {
+			return err
+		}
+	}
+	serverConfig.SetUser(accessKey, config)
+	if err := initIAMUsers(); err != nil {
+		return err
+	}
+	return serverConfig.Save()
+}
+
+// getSignatureCredential - returns the credential the signature of
+// requests with accessKey is verified against, the server credential
+// unless accessKey is of an IAM user.
+func getSignatureCredential(accessKey string) credential {
+	if user, ok := getIAMUser(accessKey); ok {
+		return user.cred
+	}
+	return serverConfig.GetCredential()
+}
+
+// checkIAMPolicy - returns ErrNone if the request of accessKey, already
+// authenticated, may perform action on resource. Requests of the
+// server access key may perform every action, requests of IAM users
+// only those their policy or the bucket policy allows and neither
+// denies, never an empty action.
+func checkIAMPolicy(r *http.Request, accessKey, action, resource string) APIErrorCode {
+	user, ok := getIAMUser(accessKey)
+	if !ok {
+		return ErrNone
+	}
+	if action == "" {
+		return ErrAccessDenied
+	}
+
+	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
+	resource = strings.TrimSuffix(strings.TrimPrefix(resource, "/"), "/")
+	arn := bucketARNPrefix + resource
+
+	conditions := getIAMConditionKeyMap(accessKey, r.Referer(), r.URL.Query())
+	statements := []*policyStatement{bucketPolicyDecidingStatement(action, arn, conditions, user.policy.Statements)}
+	if globalBucketPolicies != nil {
+		bucket := strings.SplitN(resource, slashSeparator, 2)[0]
+		if policy := globalBucketPolicies.GetBucketPolicy(bucket); policy != nil {
+			statements = append(statements, bucketPolicyDecidingStatement(action, arn, conditions, policy.Statements))
+		}
+	}
+
+	// Deny statements are ordered before Allow statements, so a
+	// deciding Allow statement means the policy denies nothing.
+	allowed := false
+	for _, statement := range statements {
+		if statement == nil {
+			continue
+		}
+		if statement.Effect != "Allow" {
+			return ErrAccessDenied
+		}
+		allowed = true
+	}
+	if !allowed {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// getIAMConditionKeyMap - returns the condition keys of requests of the
+// IAM user with accessKey, policy variables resolve to the access key
+// of the user.
+func getIAMConditionKeyMap(accessKey, referer string, queryParams url.Values) map[string]set.StringSet {
+	conditions := getAnonymousConditionKeyMap(referer, queryParams)
+	conditions["aws:username"] = set.CreateStringSet(accessKey)
+	conditions["aws:userid"] = set.CreateStringSet(accessKey)
+	return conditions
+}
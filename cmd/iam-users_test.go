@@ -0,0 +1,211 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// resetIAMUsers - removes all IAM users.
+func resetIAMUsers() {
+	globalIAMUsersMu.Lock()
+	globalIAMUsers = nil
+	globalIAMUsersMu.Unlock()
+}
+
+// Tests validation of IAM users.
+func TestNewIAMUsers(t *testing.T) {
+	serverCred := newCredentialWithKeys("minio-server", "minio-server-secret")
+	adminCreds := []adminCredentialConfig{{AccessKey: "minio-admin", SecretKey: "minio-admin-secret", Actions: []string{"*"}}}
+	policy := json.RawMessage(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::*"]}]}`)
+
+	testCases := []struct {
+		accessKey  string
+		config     iamUserConfig
+		shouldPass bool
+	}{
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}, true},
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret", Policy: policy}, true},
+		// Exactly one policy.
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret"}, false},
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly, Policy: policy}, false},
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: "readmost"}, false},
+		{"minio-user", iamUserConfig{SecretKey: "minio-user-secret", Policy: json.RawMessage(`{"Version": "2012-10-17"}`)}, false},
+		// Invalid keys.
+		{"ab", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}, false},
+		{"minio-user", iamUserConfig{SecretKey: "short", CannedPolicy: iamPolicyReadOnly}, false},
+		// Access keys already in use.
+		{"minio-server", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}, false},
+		{"minio-admin", iamUserConfig{SecretKey: "minio-user-secret", CannedPolicy: iamPolicyReadOnly}, false},
+	}
+	for i, testCase := range testCases {
+		users, err := newIAMUsers(map[string]iamUserConfig{testCase.accessKey: testCase.config}, serverCred, adminCreds)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if testCase.shouldPass && users[testCase.accessKey].cred.SecretKey != testCase.config.SecretKey {
+			t.Errorf("Test %d: Expected the secret key of the user, got %v", i+1, users[testCase.accessKey].cred)
+		}
+	}
+}
+
+// Tests evaluation of the policies of IAM users.
+func TestCheckIAMPolicy(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetIAMUsers()
+
+	serverConfig.SetUser("reader", iamUserConfig{SecretKey: "reader-secret", CannedPolicy: iamPolicyReadOnly})
+	serverConfig.SetUser("writer", iamUserConfig{SecretKey: "writer-secret", CannedPolicy: iamPolicyWriteOnly})
+	serverConfig.SetUser("homeuser", iamUserConfig{SecretKey: "homeuser-secret", Policy: json.RawMessage(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": ["arn:aws:s3:::home/${aws:username}/*"]},
+		{"Effect": "Deny", "Action": ["s3:PutObject"], "Resource": ["arn:aws:s3:::home/${aws:username}/locked/*"]}]}`)})
+	if err = initIAMUsers(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bucket policy granting every user its home in the shared bucket.
+	var policy bucketPolicy
+	if err = parseBucketPolicy(bytes.NewReader([]byte(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": ["*"]}, "Action": ["s3:GetObject", "s3:PutObject"], "Resource": ["arn:aws:s3:::shared/home/${aws:username}/*"]},
+		{"Effect": "Deny", "Principal": {"AWS": ["*"]}, "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::shared/secret/*"]}]}`)), &policy); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketPolicies = &bucketPolicies{
+		rwMutex:             &sync.RWMutex{},
+		bucketPolicyConfigs: map[string]*bucketPolicy{"shared": &policy},
+	}
+	defer func() { globalBucketPolicies = nil }()
+
+	testCases := []struct {
+		accessKey string
+		action    string
+		resource  string
+		expected  APIErrorCode
+	}{
+		// The server access key may perform every action.
+		{serverConfig.GetCredential().AccessKey, "", "/bucket", ErrNone},
+		{"reader", "s3:GetObject", "/bucket/object", ErrNone},
+		{"reader", "s3:ListAllMyBuckets", "/", ErrNone},
+		{"reader", "s3:PutObject", "/bucket/object", ErrAccessDenied},
+		{"reader", "s3:CreateBucket", "/bucket", ErrAccessDenied},
+		// IAM users may never perform operations without an action.
+		{"reader", "", "/bucket", ErrAccessDenied},
+		{"writer", "s3:PutObject", "/bucket/object", ErrNone},
+		{"writer", "s3:GetObject", "/bucket/object", ErrAccessDenied},
+		{"homeuser", "s3:PutObject", "/home/homeuser/object", ErrNone},
+		{"homeuser", "s3:GetObject", "/home/homeuser/dir/object", ErrNone},
+		{"homeuser", "s3:GetObject", "/home/reader/object", ErrAccessDenied},
+		{"homeuser", "s3:PutObject", "/home/homeuser/locked/object", ErrAccessDenied},
+		// Bucket policies resolve policy variables to the user too.
+		{"writer", "s3:GetObject", "/shared/home/writer/object", ErrNone},
+		{"writer", "s3:GetObject", "/shared/home/reader/object", ErrAccessDenied},
+		// Deny statements of the bucket policy win over the user policy.
+		{"reader", "s3:GetObject", "/shared/secret/object", ErrAccessDenied},
+	}
+	for i, testCase := range testCases {
+		r, err := http.NewRequest("GET", "http://localhost"+testCase.resource+"?aws:username=reader", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s3Error := checkIAMPolicy(r, testCase.accessKey, testCase.action, testCase.resource); s3Error != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests S3 API requests signed by IAM users.
+func TestIAMUserRequests(t *testing.T) {
+	defer resetIAMUsers()
+	ExecObjectLayerAPITest(t, testIAMUserRequests, []string{"PutObject", "GetObject", "PutBucket", "PutBucketPolicy"})
+}
+
+func testIAMUserRequests(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	if err := setIAMUser("reader", iamUserConfig{SecretKey: "reader-secret", CannedPolicy: iamPolicyReadOnly}); err != nil {
+		t.Fatal(err)
+	}
+	if err := setIAMUser("homeuser", iamUserConfig{SecretKey: "homeuser-secret", Policy: json.RawMessage(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": ["arn:aws:s3:::` + bucketName + `/${aws:username}/*"]}]}`)}); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	if _, err := obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	type signer func(method, urlStr string, contentLength int64, body []byte, accessKey, secretKey string) (*http.Request, error)
+	signV4 := func(method, urlStr string, contentLength int64, body []byte, accessKey, secretKey string) (*http.Request, error) {
+		return newTestSignedRequestV4(method, urlStr, contentLength, bytes.NewReader(body), accessKey, secretKey)
+	}
+	signV2 := func(method, urlStr string, contentLength int64, body []byte, accessKey, secretKey string) (*http.Request, error) {
+		return newTestSignedRequestV2(method, urlStr, contentLength, bytes.NewReader(body), accessKey, secretKey)
+	}
+	signStreaming := func(method, urlStr string, contentLength int64, body []byte, accessKey, secretKey string) (*http.Request, error) {
+		return newTestStreamingSignedRequest(method, urlStr, contentLength, 2, bytes.NewReader(body), accessKey, secretKey)
+	}
+
+	testCases := []struct {
+		sign       signer
+		method     string
+		path       string
+		accessKey  string
+		secretKey  string
+		expectCode int
+	}{
+		{signV4, "GET", "/" + bucketName + "/object", "reader", "reader-secret", http.StatusOK},
+		{signV2, "GET", "/" + bucketName + "/object", "reader", "reader-secret", http.StatusOK},
+		{signV4, "GET", "/" + bucketName + "/object", "reader", "wrong-secret", http.StatusForbidden},
+		{signV4, "PUT", "/" + bucketName + "/object", "reader", "reader-secret", http.StatusForbidden},
+		{signV4, "PUT", "/newbucket", "reader", "reader-secret", http.StatusForbidden},
+		{signV4, "PUT", "/" + bucketName + "?policy", "reader", "reader-secret", http.StatusForbidden},
+		{signV4, "PUT", "/" + bucketName + "/homeuser/object", "homeuser", "homeuser-secret", http.StatusOK},
+		{signV2, "PUT", "/" + bucketName + "/homeuser/object", "homeuser", "homeuser-secret", http.StatusOK},
+		{signStreaming, "PUT", "/" + bucketName + "/homeuser/object", "homeuser", "homeuser-secret", http.StatusOK},
+		{signStreaming, "PUT", "/" + bucketName + "/homeuser/object", "homeuser", "wrong-secret", http.StatusForbidden},
+		{signV4, "PUT", "/" + bucketName + "/reader/object", "homeuser", "homeuser-secret", http.StatusForbidden},
+		{signV4, "GET", "/" + bucketName + "/object", "homeuser", "homeuser-secret", http.StatusForbidden},
+		{signV4, "PUT", "/" + bucketName + "/object", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		var body []byte
+		var contentLength int64
+		if testCase.method == "PUT" && testCase.path != "/newbucket" {
+			body, contentLength = data, int64(len(data))
+		}
+		req, err := testCase.sign(testCase.method, testCase.path, contentLength, body, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to create request - %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectCode {
+			t.Errorf("%s: Test %d: Expected status %d, got %d: %s", instanceType, i+1, testCase.expectCode, rec.Code, rec.Body.String())
+		}
+	}
+}
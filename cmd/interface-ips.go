@@ -67,3 +67,53 @@ func getInterfaceIPv4s() ([]net.IP, error) {
 	sort.Sort(sort.Reverse(byLastOctetValue(nips)))
 	return nips, nil
 }
+
+// getInterfaceIPs - returns the IPv4 addresses of getInterfaceIPv4s
+// followed by the IPv6 addresses of all network interfaces. Link-local
+// IPv6 addresses are skipped as they are only reachable with a zone.
+func getInterfaceIPs() ([]net.IP, error) {
+	nips, err := getInterfaceIPv4s()
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to determine network interface address. %s", err)
+	}
+	for _, addr := range addrs {
+		nip, _, err := net.ParseCIDR(addr.String())
+		if err != nil {
+			continue
+		}
+		if nip.To4() == nil && !nip.IsLinkLocalUnicast() {
+			nips = append(nips, nip)
+		}
+	}
+	return nips, nil
+}
+
+// getInterfaceHosts - returns the addresses of the network interface
+// name, link-local IPv6 addresses with their zone, e.g "fe80::1%eth0".
+func getInterfaceHosts(name string) ([]string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to determine addresses of network interface %s. %s", name, err)
+	}
+	var hosts []string
+	for _, addr := range addrs {
+		nip, _, err := net.ParseCIDR(addr.String())
+		if err != nil {
+			continue
+		}
+		if nip.To4() == nil && nip.IsLinkLocalUnicast() {
+			hosts = append(hosts, nip.String()+"%"+name)
+			continue
+		}
+		hosts = append(hosts, nip.String())
+	}
+	return hosts, nil
+}
@@ -0,0 +1,327 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Default interval between two janitor passes.
+	defaultJanitorInterval = time.Hour
+
+	// Default and minimum age of the newest file of a stale entry.
+	// Uploads in progress write to their entries continuously, the
+	// age leaves room for slow clients.
+	defaultJanitorAge = 24 * time.Hour
+	minJanitorAge     = time.Hour
+)
+
+// JanitorInfo - stale data removed from the local disks of a server.
+type JanitorInfo struct {
+	// Age of the newest file of the entries removed.
+	Age time.Duration `json:"age"`
+	// Start and end of the last pass.
+	LastRunStart time.Time `json:"lastRunStart,omitempty"`
+	LastRunEnd   time.Time `json:"lastRunEnd,omitempty"`
+	// Totals since the server started.
+	TmpEntriesRemoved int64 `json:"tmpEntriesRemoved"`
+	UploadsRemoved    int64 `json:"uploadsRemoved"`
+	BytesReclaimed    int64 `json:"bytesReclaimed"`
+	// Last error of the last pass, if any.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// janitor - removes stale temporary data, left behind by requests
+// interrupted by a crash, and multipart uploads no longer listed in
+// `uploads.json` from the local disks.
+type janitor struct {
+	disks []StorageAPI
+	age   time.Duration
+	// Temporary entry of this server which is never removed, the FS
+	// temporary directory of the running process.
+	skipTmpEntry string
+
+	mu   sync.Mutex
+	info JanitorInfo
+}
+
+// Janitor of this server, nil unless enabled.
+var globalJanitor *janitor
+
+func newJanitor(disks []StorageAPI, age time.Duration, skipTmpEntry string) *janitor {
+	return &janitor{
+		disks:        disks,
+		age:          age,
+		skipTmpEntry: skipTmpEntry,
+		info:         JanitorInfo{Age: age},
+	}
+}
+
+// startJanitor - starts removing stale data from the local disks of
+// objAPI every interval until the server stops.
+func startJanitor(objAPI ObjectLayer, interval, age time.Duration) error {
+	var disks []StorageAPI
+	var skipTmpEntry string
+	switch obj := objAPI.(type) {
+	case *xlObjects:
+		for _, disk := range obj.storageDisks {
+			if disk != nil && isLocalDisk(disk) {
+				disks = append(disks, disk)
+			}
+		}
+	case *fsObjects:
+		disk, err := newPosix(obj.fsPath)
+		if err != nil {
+			return err
+		}
+		disks = append(disks, disk)
+		skipTmpEntry = obj.fsUUID
+	}
+
+	globalJanitor = newJanitor(disks, age, skipTmpEntry)
+	go func() {
+		for {
+			select {
+			case <-time.After(interval):
+				globalJanitor.run()
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Info - returns a snapshot of the removed data.
+func (j *janitor) Info() JanitorInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info
+}
+
+// janitorStats - data removed from a disk by a pass.
+type janitorStats struct {
+	tmpEntries int64
+	uploads    int64
+	bytes      int64
+	err        error
+}
+
+// run - one pass over all local disks, in parallel.
+func (j *janitor) run() {
+	j.mu.Lock()
+	j.info.LastRunStart = time.Now().UTC()
+	j.mu.Unlock()
+
+	stats := make([]janitorStats, len(j.disks))
+	var wg sync.WaitGroup
+	for i, disk := range j.disks {
+		wg.Add(1)
+		go func(i int, disk StorageAPI) {
+			defer wg.Done()
+			staleBefore := time.Now().UTC().Add(-j.age)
+			if err := j.cleanTmp(disk, staleBefore, &stats[i]); err != nil {
+				stats[i].err = err
+				return
+			}
+			stats[i].err = j.cleanMultipart(disk, "", staleBefore, &stats[i])
+		}(i, disk)
+	}
+	wg.Wait()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.LastRunEnd = time.Now().UTC()
+	j.info.LastError = ""
+	for i, s := range stats {
+		j.info.TmpEntriesRemoved += s.tmpEntries
+		j.info.UploadsRemoved += s.uploads
+		j.info.BytesReclaimed += s.bytes
+		if s.err != nil {
+			errorIf(s.err, "Unable to remove stale data from %s.", j.disks[i])
+			j.info.LastError = errorCause(s.err).Error()
+		}
+	}
+}
+
+// statTree - returns the total size and the newest modification time
+// of the files under entryPath of volume.
+func statTree(disk StorageAPI, volume, entryPath string) (size int64, newest time.Time, err error) {
+	if !hasSuffix(entryPath, slashSeparator) {
+		fi, err := disk.StatFile(volume, entryPath)
+		if err != nil {
+			return 0, newest, err
+		}
+		return fi.Size, fi.ModTime, nil
+	}
+	entries, err := disk.ListDir(volume, entryPath)
+	if err != nil {
+		return 0, newest, err
+	}
+	for _, entry := range entries {
+		entrySize, entryNewest, err := statTree(disk, volume, pathJoin(entryPath, entry))
+		if err != nil {
+			return 0, newest, err
+		}
+		size += entrySize
+		if entryNewest.After(newest) {
+			newest = entryNewest
+		}
+	}
+	return size, newest, nil
+}
+
+// removeStale - removes entryPath of volume if all its files were
+// last modified before staleBefore. Returns the size of the removed
+// files and whether entryPath was removed.
+func removeStale(disk StorageAPI, volume, entryPath string, staleBefore time.Time) (int64, bool, error) {
+	size, newest, err := statTree(disk, volume, entryPath)
+	if err != nil {
+		// Removed concurrently, e.g. by the request which created it.
+		if err == errFileNotFound {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	if newest.After(staleBefore) {
+		return 0, false, nil
+	}
+	if hasSuffix(entryPath, slashSeparator) {
+		if err = cleanupDir(disk, volume, entryPath); err != nil && errorCause(err) != errFileNotFound {
+			return 0, false, err
+		}
+	}
+	// Also removes directories without files, left behind by cleanupDir.
+	if err = disk.DeleteFile(volume, entryPath); err != nil && err != errFileNotFound {
+		return 0, false, err
+	}
+	return size, true, nil
+}
+
+// cleanTmp - removes stale entries of the temporary volume.
+func (j *janitor) cleanTmp(disk StorageAPI, staleBefore time.Time, stats *janitorStats) error {
+	entries, err := disk.ListDir(minioMetaTmpBucket, "")
+	if err != nil {
+		if err == errFileNotFound || err == errVolumeNotFound {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if j.skipTmpEntry != "" && strings.TrimSuffix(entry, slashSeparator) == j.skipTmpEntry {
+			continue
+		}
+		size, removed, err := removeStale(disk, minioMetaTmpBucket, entry, staleBefore)
+		if err != nil {
+			return err
+		}
+		if removed {
+			stats.tmpEntries++
+			stats.bytes += size
+		}
+	}
+	return nil
+}
+
+// cleanMultipart - removes stale uploads under dirPath of the
+// multipart volume which are not listed in the `uploads.json` of
+// their object. Upload directories hold only files, object
+// directories hold `uploads.json` and are walked recursively.
+func (j *janitor) cleanMultipart(disk StorageAPI, dirPath string, staleBefore time.Time, stats *janitorStats) error {
+	entries, err := disk.ListDir(minioMetaMultipartBucket, dirPath)
+	if err != nil {
+		if err == errFileNotFound || err == errVolumeNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Uploads are at bucket/object/uploadID, the object may have
+	// slashes.
+	hasUploads := strings.Count(dirPath, slashSeparator) >= 2
+	liveUploads := make(map[string]bool)
+	for _, entry := range entries {
+		if entry != uploadsJSONFile || !hasUploads {
+			continue
+		}
+		uploads, err := readUploadsJSON(dirPath, "", disk)
+		if err != nil {
+			// Unreadable or removed concurrently, leave the uploads
+			// of this object alone.
+			hasUploads = false
+			break
+		}
+		for _, upload := range uploads.Uploads {
+			liveUploads[upload.UploadID] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if !hasSuffix(entry, slashSeparator) {
+			continue
+		}
+		entryPath := pathJoin(dirPath, entry)
+		isUpload := hasUploads
+		if isUpload {
+			if isUpload, err = isUploadDir(disk, entryPath); err != nil {
+				return err
+			}
+		}
+		if !isUpload {
+			if err = j.cleanMultipart(disk, entryPath, staleBefore, stats); err != nil {
+				return err
+			}
+			continue
+		}
+		if liveUploads[strings.TrimSuffix(entry, slashSeparator)] {
+			continue
+		}
+		size, removed, err := removeStale(disk, minioMetaMultipartBucket, entryPath, staleBefore)
+		if err != nil {
+			return err
+		}
+		if removed {
+			stats.uploads++
+			stats.bytes += size
+		}
+	}
+	return nil
+}
+
+// isUploadDir - returns true if dirPath of the multipart volume holds
+// files only, none of them `uploads.json`.
+func isUploadDir(disk StorageAPI, dirPath string) (bool, error) {
+	entries, err := disk.ListDir(minioMetaMultipartBucket, dirPath)
+	if err != nil {
+		if err == errFileNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(entries) == 0 {
+		return false, nil
+	}
+	for _, entry := range entries {
+		if entry == uploadsJSONFile || hasSuffix(entry, slashSeparator) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests removal of stale temporary entries and orphaned uploads.
+func TestJanitorRun(t *testing.T) {
+	diskPath, err := ioutil.TempDir(globalTestTmpDir, "minio-janitor-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(diskPath)
+
+	disk, err := newPosix(diskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, volume := range []string{minioMetaBucket, minioMetaTmpBucket, minioMetaMultipartBucket} {
+		if err = disk.MakeVol(volume); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := time.Now().Add(-2 * defaultJanitorAge)
+	writeFile := func(volume, filePath string, data []byte, modTime time.Time) {
+		if err = disk.AppendFile(volume, filePath, data); err != nil {
+			t.Fatal(err)
+		}
+		fsPath := filepath.Join(diskPath, volume, filePath)
+		if err = os.Chtimes(fsPath, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Temporary entries, the entry of the running process is kept.
+	writeFile(minioMetaTmpBucket, "stale/part.1", make([]byte, 100), old)
+	writeFile(minioMetaTmpBucket, "stale/dir/part.2", make([]byte, 50), old)
+	writeFile(minioMetaTmpBucket, "stalefile", make([]byte, 10), old)
+	writeFile(minioMetaTmpBucket, "active/part.1", make([]byte, 100), old)
+	writeFile(minioMetaTmpBucket, "active/part.2", make([]byte, 100), time.Now())
+	writeFile(minioMetaTmpBucket, "self/fs.json", make([]byte, 100), old)
+
+	// Uploads, only the orphaned one is removed.
+	uploads := newUploadsV1("xl")
+	uploads.AddUploadID("live", old)
+	uploadsJSON, err := json.Marshal(uploads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(minioMetaMultipartBucket, "bucket/dir/object/uploads.json", uploadsJSON, old)
+	writeFile(minioMetaMultipartBucket, "bucket/dir/object/live/xl.json", make([]byte, 100), old)
+	writeFile(minioMetaMultipartBucket, "bucket/dir/object/orphan/xl.json", make([]byte, 100), old)
+	writeFile(minioMetaMultipartBucket, "bucket/dir/object/orphan/part.1", make([]byte, 1000), old)
+	writeFile(minioMetaMultipartBucket, "bucket/dir/object/recent/xl.json", make([]byte, 100), time.Now())
+
+	j := newJanitor([]StorageAPI{disk}, defaultJanitorAge, "self")
+	j.run()
+
+	info := j.Info()
+	if info.LastError != "" {
+		t.Fatalf("Unexpected error %s", info.LastError)
+	}
+	if info.TmpEntriesRemoved != 2 || info.UploadsRemoved != 1 || info.BytesReclaimed != 1260 {
+		t.Fatalf("Unexpected janitor info %#v", info)
+	}
+	if info.LastRunStart.IsZero() || info.LastRunEnd.Before(info.LastRunStart) {
+		t.Fatalf("Unexpected run times %v, %v", info.LastRunStart, info.LastRunEnd)
+	}
+
+	testCases := []struct {
+		volume  string
+		entry   string
+		removed bool
+	}{
+		{minioMetaTmpBucket, "stale", true},
+		{minioMetaTmpBucket, "stalefile", true},
+		{minioMetaTmpBucket, "active", false},
+		{minioMetaTmpBucket, "self", false},
+		{minioMetaMultipartBucket, "bucket/dir/object/uploads.json", false},
+		{minioMetaMultipartBucket, "bucket/dir/object/live", false},
+		{minioMetaMultipartBucket, "bucket/dir/object/orphan", true},
+		{minioMetaMultipartBucket, "bucket/dir/object/recent", false},
+	}
+	for i, testCase := range testCases {
+		_, err = os.Stat(filepath.Join(diskPath, testCase.volume, testCase.entry))
+		if testCase.removed != os.IsNotExist(err) {
+			t.Errorf("Test %d: Expected %s removed %v, got %v", i+1, testCase.entry, testCase.removed, err)
+		}
+	}
+
+	// Nothing left to remove.
+	j.run()
+	if info = j.Info(); info.TmpEntriesRemoved != 2 || info.UploadsRemoved != 1 {
+		t.Fatalf("Unexpected janitor info %#v", info)
+	}
+}
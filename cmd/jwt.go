@@ -31,7 +31,8 @@ import (
 const (
 	jwtAlgorithm = "Bearer"
 
-	// Default JWT token for web handlers is one day.
+	// Default JWT token for web handlers is one day, unless another
+	// browser session duration is configured.
 	defaultJWTExpiry = 24 * time.Hour
 
 	// Inter-node JWT token expiry is 100 years approx.
@@ -48,7 +49,10 @@ var (
 	errNoAuthToken          = errors.New("JWT token missing")
 )
 
-func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string, error) {
+// authenticateJWT - returns a token valid for expiry if the credentials
+// are valid. sessionID identifies browser sessions, it is empty for
+// inter-node tokens.
+func authenticateJWT(accessKey, secretKey string, expiry time.Duration, sessionID string) (string, error) {
 	// Trim spaces.
 	accessKey = strings.TrimSpace(accessKey)
 
@@ -80,21 +84,25 @@ func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string,
 	}
 
 	utcNow := time.Now().UTC()
-	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
+	claims := jwtgo.MapClaims{
 		"exp": utcNow.Add(expiry).Unix(),
 		"iat": utcNow.Unix(),
 		"sub": accessKey,
-	})
+	}
+	if sessionID != "" {
+		claims["jti"] = sessionID
+	}
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, claims)
 
 	return token.SignedString([]byte(serverCred.SecretKey))
 }
 
 func authenticateNode(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultInterNodeJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, defaultInterNodeJWTExpiry, "")
 }
 
 func authenticateWeb(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, serverConfig.GetBrowserSession().getDuration(), mustGetUUID())
 }
 
 func keyFuncCallback(jwtToken *jwtgo.Token) (interface{}, error) {
@@ -115,6 +123,18 @@ func isAuthTokenValid(tokenString string) bool {
 	return jwtToken.Valid
 }
 
+// isWebTokenValid - returns true if tokenString is a valid token of a
+// browser session which is neither revoked nor idle for too long.
+func isWebTokenValid(tokenString string) bool {
+	jwtToken, err := jwtgo.Parse(tokenString, keyFuncCallback)
+	if err != nil {
+		errorIf(err, "Unable to parse JWT token string")
+		return false
+	}
+
+	return jwtToken.Valid && checkBrowserSession(jwtToken) == nil
+}
+
 func isHTTPRequestValid(req *http.Request) bool {
 	return webRequestAuthenticate(req) == nil
 }
@@ -134,5 +154,5 @@ func webRequestAuthenticate(req *http.Request) error {
 	if !jwtToken.Valid {
 		return errAuthentication
 	}
-	return nil
+	return checkBrowserSession(jwtToken)
 }
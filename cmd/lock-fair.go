@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// Lock grant policies, set with MINIO_LOCK_POLICY env.
+const (
+	// Requests are granted the lock in the order they were made.
+	lockPolicyFIFO = "fifo"
+	// Queued writers are granted the lock before queued readers.
+	lockPolicyWriterPriority = "writer-priority"
+)
+
+// lockWaiter - a request queued on a fairRWMutex.
+type lockWaiter struct {
+	writer bool
+	since  time.Time
+	ready  chan struct{}
+}
+
+// fairLockStats - metrics of a fairRWMutex.
+type fairLockStats struct {
+	// Number of times the lock was granted.
+	Acquired uint64
+	// Number of readers and writers currently queued.
+	WaitingReaders int
+	WaitingWriters int
+	// Total and longest time granted requests were queued.
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// add - returns the metrics of s accumulated with o.
+func (s fairLockStats) add(o fairLockStats) fairLockStats {
+	s.Acquired += o.Acquired
+	s.WaitingReaders += o.WaitingReaders
+	s.WaitingWriters += o.WaitingWriters
+	s.TotalWait += o.TotalWait
+	if o.MaxWait > s.MaxWait {
+		s.MaxWait = o.MaxWait
+	}
+	return s
+}
+
+// fairRWMutex - read-write mutex granting the lock in the order it was
+// requested. A reader queues behind any earlier writer, so a steady
+// stream of readers can not starve writers. With writerPriority, queued
+// writers are granted the lock before queued readers, at the risk of
+// starving readers instead.
+type fairRWMutex struct {
+	writerPriority bool
+
+	mutex   sync.Mutex
+	readers int  // Number of readers holding the lock.
+	writer  bool // Set if a writer holds the lock.
+	queue   []*lockWaiter
+	stats   fairLockStats
+
+	// Number of readers and writers released by ForceUnlock which
+	// have not called RUnlock or Unlock yet.
+	forcedReaders int
+	forcedWriters int
+}
+
+// newFairRWMutex - returns a new unlocked fairRWMutex.
+func newFairRWMutex(writerPriority bool) *fairRWMutex {
+	return &fairRWMutex{writerPriority: writerPriority}
+}
+
+// Lock - locks m for writing, blocks until granted.
+func (m *fairRWMutex) Lock() {
+	m.acquire(true)
+}
+
+// RLock - locks m for reading, blocks until granted.
+func (m *fairRWMutex) RLock() {
+	m.acquire(false)
+}
+
+// Unlock - releases the write lock.
+func (m *fairRWMutex) Unlock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.writer {
+		if m.forcedWriters > 0 {
+			m.forcedWriters--
+			return
+		}
+		panic("fairRWMutex: Unlock of unlocked mutex")
+	}
+	m.writer = false
+	m.wakeup()
+}
+
+// RUnlock - releases a read lock.
+func (m *fairRWMutex) RUnlock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.readers == 0 {
+		if m.forcedReaders > 0 {
+			m.forcedReaders--
+			return
+		}
+		panic("fairRWMutex: RUnlock of unlocked mutex")
+	}
+	m.readers--
+	m.wakeup()
+}
+
+// ForceUnlock - releases the lock from its current holders and grants
+// it to the queued requests. The released holders may still call
+// Unlock or RUnlock later.
+func (m *fairRWMutex) ForceUnlock() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.writer {
+		m.forcedWriters++
+	}
+	m.forcedReaders += m.readers
+	m.writer = false
+	m.readers = 0
+	m.wakeup()
+}
+
+// Stats - returns the metrics of m.
+func (m *fairRWMutex) Stats() fairLockStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.stats
+}
+
+func (m *fairRWMutex) acquire(writer bool) {
+	m.mutex.Lock()
+	// Only take the lock right away if nobody is queued before us.
+	if len(m.queue) == 0 && m.canGrant(writer) {
+		m.grant(writer, 0)
+		m.mutex.Unlock()
+		return
+	}
+	waiter := &lockWaiter{
+		writer: writer,
+		since:  time.Now(),
+		ready:  make(chan struct{}),
+	}
+	m.queue = append(m.queue, waiter)
+	if writer {
+		m.stats.WaitingWriters++
+	} else {
+		m.stats.WaitingReaders++
+	}
+	m.mutex.Unlock()
+
+	// Wait for wakeup to hand us the lock.
+	<-waiter.ready
+}
+
+// canGrant - returns true if a request can hold the lock along with
+// the current holders. Caller must hold m.mutex.
+func (m *fairRWMutex) canGrant(writer bool) bool {
+	if writer {
+		return !m.writer && m.readers == 0
+	}
+	return !m.writer
+}
+
+// grant - hands the lock to a request queued for wait. Caller must
+// hold m.mutex.
+func (m *fairRWMutex) grant(writer bool, wait time.Duration) {
+	if writer {
+		m.writer = true
+	} else {
+		m.readers++
+	}
+	m.stats.Acquired++
+	m.stats.TotalWait += wait
+	if wait > m.stats.MaxWait {
+		m.stats.MaxWait = wait
+	}
+}
+
+// wakeup - grants the lock to queued requests which can hold it. Caller
+// must hold m.mutex.
+func (m *fairRWMutex) wakeup() {
+	now := time.Now()
+	if m.writerPriority {
+		for i, waiter := range m.queue {
+			if !waiter.writer {
+				continue
+			}
+			// Readers do not pass a queued writer.
+			if m.canGrant(true) {
+				m.dequeue(i, now)
+			}
+			return
+		}
+	}
+	for len(m.queue) > 0 && m.canGrant(m.queue[0].writer) {
+		m.dequeue(0, now)
+	}
+}
+
+// dequeue - grants the lock to the i-th queued request. Caller must
+// hold m.mutex.
+func (m *fairRWMutex) dequeue(i int, now time.Time) {
+	waiter := m.queue[i]
+	m.queue = append(m.queue[:i], m.queue[i+1:]...)
+	if waiter.writer {
+		m.stats.WaitingWriters--
+	} else {
+		m.stats.WaitingReaders--
+	}
+	m.grant(waiter.writer, now.Sub(waiter.since))
+	close(waiter.ready)
+}
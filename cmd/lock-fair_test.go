@@ -0,0 +1,139 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// waitForQueued - waits until readers and writers are queued on m.
+func waitForQueued(t *testing.T, m *fairRWMutex, readers, writers int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		stats := m.Stats()
+		if stats.WaitingReaders == readers && stats.WaitingWriters == writers {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d readers and %d writers to queue, got %#v", readers, writers, m.Stats())
+}
+
+// Tests that readers share the lock and a reader queues behind a
+// waiting writer.
+func TestFairRWMutexReaderQueuesBehindWriter(t *testing.T) {
+	m := newFairRWMutex(false)
+	m.RLock()
+	m.RLock()
+
+	granted := make(chan string, 2)
+	go func() {
+		m.Lock()
+		granted <- "writer"
+		m.Unlock()
+	}()
+	waitForQueued(t, m, 0, 1)
+
+	go func() {
+		m.RLock()
+		granted <- "reader"
+		m.RUnlock()
+	}()
+	waitForQueued(t, m, 1, 1)
+
+	m.RUnlock()
+	m.RUnlock()
+	if first := <-granted; first != "writer" {
+		t.Errorf("Expected the writer to be granted first, got %s", first)
+	}
+	if second := <-granted; second != "reader" {
+		t.Errorf("Expected the reader to be granted second, got %s", second)
+	}
+
+	stats := m.Stats()
+	if stats.Acquired != 4 || stats.WaitingReaders != 0 || stats.WaitingWriters != 0 {
+		t.Errorf("Unexpected stats %#v", stats)
+	}
+	if stats.MaxWait <= 0 || stats.TotalWait < stats.MaxWait {
+		t.Errorf("Expected wait times to be recorded, got %#v", stats)
+	}
+}
+
+// Tests the order queued requests are granted the lock with and
+// without writer priority.
+func TestFairRWMutexGrantOrder(t *testing.T) {
+	testCases := []struct {
+		writerPriority bool
+		order          []string
+	}{
+		{false, []string{"reader", "writer"}},
+		{true, []string{"writer", "reader"}},
+	}
+	for i, testCase := range testCases {
+		m := newFairRWMutex(testCase.writerPriority)
+		m.Lock()
+
+		granted := make(chan string, 2)
+		go func() {
+			m.RLock()
+			granted <- "reader"
+			m.RUnlock()
+		}()
+		waitForQueued(t, m, 1, 0)
+		go func() {
+			m.Lock()
+			granted <- "writer"
+			m.Unlock()
+		}()
+		waitForQueued(t, m, 1, 1)
+
+		m.Unlock()
+		for j, expected := range testCase.order {
+			if got := <-granted; got != expected {
+				t.Errorf("Test %d: Expected %s to be granted %d, got %s", i+1, expected, j+1, got)
+			}
+		}
+	}
+}
+
+// Tests that ForceUnlock grants the lock to queued requests and the
+// released holder can still unlock.
+func TestFairRWMutexForceUnlock(t *testing.T) {
+	m := newFairRWMutex(false)
+	m.Lock()
+
+	granted := make(chan struct{})
+	go func() {
+		m.RLock()
+		close(granted)
+	}()
+	waitForQueued(t, m, 1, 0)
+
+	m.ForceUnlock()
+	select {
+	case <-granted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the queued reader to be granted the lock")
+	}
+
+	// The released writer unlocks without affecting the reader.
+	m.Unlock()
+	m.RUnlock()
+	m.Lock()
+	m.Unlock()
+}
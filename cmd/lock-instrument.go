@@ -224,6 +224,24 @@ func (n *nsLockMap) deleteLockInfoEntryForOps(param nsParam, opsID string) error
 	return nil
 }
 
+// deleteRunningLockInfo - Deletes the lock info of the operations
+// holding the lock on given (volume, path). Called on ForceUnlock.
+func (n *nsLockMap) deleteRunningLockInfo(param nsParam) {
+	infoMap, found := n.debugLockMap[param]
+	if !found {
+		return
+	}
+	for opsID, lockInfo := range infoMap.lockInfo {
+		if lockInfo.status != runningStatus {
+			continue
+		}
+		// Update global and (volume, path) lock status.
+		n.counters.lockRemoved(true)
+		infoMap.counters.lockRemoved(true)
+		delete(infoMap.lockInfo, opsID)
+	}
+}
+
 // Return randomly generated string ID
 func getOpsID() string {
 	const opsIDLen = 16
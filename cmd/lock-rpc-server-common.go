@@ -72,3 +72,37 @@ func getLongLivedLocks(m map[string][]lockRequesterInfo, interval time.Duration)
 	}
 	return rslt
 }
+
+// clearLocks removes the locks held by node, by any node if empty, for
+// longer than duration on resources matching resourcePrefix and returns
+// the names of the resources whose locks were removed.
+func (l *lockServer) clearLocks(node, resourcePrefix string, duration time.Duration) []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Fetch current time once instead of fetching system time for every lock.
+	timeNow := time.Now().UTC()
+	cleared := []string{}
+	for name, lri := range l.lockMap {
+		if !hasPrefix(name, resourcePrefix) {
+			continue
+		}
+		var uids []string
+		for _, entry := range lri {
+			if node != "" && entry.node != node {
+				continue
+			}
+			if timeNow.Sub(entry.timestamp) < duration {
+				continue
+			}
+			uids = append(uids, entry.uid)
+		}
+		for _, uid := range uids {
+			l.removeEntry(name, uid, &lri)
+		}
+		if len(uids) > 0 {
+			cleared = append(cleared, name)
+		}
+	}
+	return cleared
+}
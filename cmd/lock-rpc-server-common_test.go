@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 )
@@ -174,3 +175,58 @@ func TestLockRpcServerGetLongLivedLocks(t *testing.T) {
 		}
 	}
 }
+
+// Tests clearing locks by owner node and age.
+func TestLockRpcServerClearLocks(t *testing.T) {
+	testPath, locker, _ := createLockTestServer(t)
+	defer removeAll(testPath)
+
+	now := time.Now().UTC()
+	newLri := func(node, uid string, age time.Duration) lockRequesterInfo {
+		return lockRequesterInfo{node: node, uid: uid, timestamp: now.Add(-age), timeLastCheck: now}
+	}
+	resetLockMap := func() {
+		locker.lockMap = map[string][]lockRequesterInfo{
+			"mybucket/obj1":  {newLri("node1", "1", time.Hour)},
+			"mybucket/obj2":  {newLri("node1", "2", time.Hour), newLri("node2", "3", time.Hour)},
+			"mybucket/obj3":  {newLri("node1", "4", time.Second)},
+			"mybucket2/obj1": {newLri("node1", "5", time.Hour)},
+		}
+	}
+
+	testCases := []struct {
+		node      string
+		prefix    string
+		duration  time.Duration
+		cleared   []string
+		remaining []string
+	}{
+		{"node1", "mybucket/", time.Minute, []string{"mybucket/obj1", "mybucket/obj2"}, []string{"mybucket/obj2", "mybucket/obj3", "mybucket2/obj1"}},
+		{"node2", "mybucket/", 0, []string{"mybucket/obj2"}, []string{"mybucket/obj1", "mybucket/obj2", "mybucket/obj3", "mybucket2/obj1"}},
+		{"", "mybucket/obj", time.Minute, []string{"mybucket/obj1", "mybucket/obj2"}, []string{"mybucket/obj3", "mybucket2/obj1"}},
+		{"node3", "mybucket/", 0, []string{}, []string{"mybucket/obj1", "mybucket/obj2", "mybucket/obj3", "mybucket2/obj1"}},
+	}
+	for i, testCase := range testCases {
+		resetLockMap()
+		cleared := locker.clearLocks(testCase.node, testCase.prefix, testCase.duration)
+		sort.Strings(cleared)
+		if !reflect.DeepEqual(cleared, testCase.cleared) {
+			t.Errorf("Test %d: Expected cleared %v, got %v", i+1, testCase.cleared, cleared)
+		}
+		remaining := []string{}
+		for name := range locker.lockMap {
+			remaining = append(remaining, name)
+		}
+		sort.Strings(remaining)
+		if !reflect.DeepEqual(remaining, testCase.remaining) {
+			t.Errorf("Test %d: Expected remaining %v, got %v", i+1, testCase.remaining, remaining)
+		}
+	}
+
+	// Only the entry held by node1 is removed from a shared lock.
+	resetLockMap()
+	locker.clearLocks("node1", "mybucket/obj2", 0)
+	if lri := locker.lockMap["mybucket/obj2"]; len(lri) != 1 || lri[0].node != "node2" {
+		t.Errorf("Expected the lock of node2 to remain, got %#v", lri)
+	}
+}
@@ -62,6 +62,10 @@ type lockServer struct {
 	lockMap map[string][]lockRequesterInfo
 }
 
+// Lock servers of the local storage endpoints, nil unless this
+// instance is a distributed setup.
+var globalLockServers []*lockServer
+
 // Start lock maintenance from all lock servers.
 func startLockMaintainence(lockServers []*lockServer) {
 	for _, locker := range lockServers {
@@ -90,6 +94,7 @@ func startLockMaintainence(lockServers []*lockServer) {
 func registerDistNSLockRouter(mux *router.Router, serverConfig serverCmdConfig) error {
 	// Initialize a new set of lock servers.
 	lockServers := newLockServers(serverConfig)
+	globalLockServers = lockServers
 
 	// Start lock maintenance from all lock servers.
 	startLockMaintainence(lockServers)
@@ -52,6 +52,16 @@ type VolumeLockInfo struct {
 	// State information containing state of the locks for all operations
 	// on given <volume,path> pair.
 	LockDetailsOnObject []OpsLockState `json:"lockOwners"`
+
+	// Metrics of the lock queue of the server reporting the lock.
+	// Number of times the lock was granted.
+	LocksGranted uint64 `json:"locksGranted"`
+	// Number of operations currently waiting to read and write.
+	WaitingReaders int `json:"waitingReaders"`
+	WaitingWriters int `json:"waitingWriters"`
+	// Total and longest time granted operations waited for the lock.
+	TotalWait time.Duration `json:"totalWait"`
+	MaxWait   time.Duration `json:"maxWait"`
 }
 
 // OpsLockState - structure to fill in state information of the lock.
@@ -90,6 +100,12 @@ func listLocksInfo(bucket, prefix string, duration time.Duration) []VolumeLockIn
 			TotalBlockedLocks:     debugLock.counters.blocked,
 			LocksAcquiredOnObject: debugLock.counters.granted,
 		}
+		stats := globalNSMutex.lockStats(param)
+		volLockInfo.LocksGranted = stats.Acquired
+		volLockInfo.WaitingReaders = stats.WaitingReaders
+		volLockInfo.WaitingWriters = stats.WaitingWriters
+		volLockInfo.TotalWait = stats.TotalWait
+		volLockInfo.MaxWait = stats.MaxWait
 		// Filter locks that are held on bucket, prefix.
 		for opsID, lockInfo := range debugLock.lockInfo {
 			// filter locks that were held for longer than duration.
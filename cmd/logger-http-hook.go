@@ -0,0 +1,176 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// Default number of entries sent in a single request.
+	defaultHTTPLogBatchSize = 100
+	// Pending entries are sent at least this often.
+	httpLogFlushInterval = 5 * time.Second
+	// Maximum number of entries queued, newer entries are
+	// dropped while the endpoint is not keeping up.
+	httpLogQueueSize = 10000
+	// Timeout for a single request to the endpoint.
+	httpLogRequestTimeout = 10 * time.Second
+)
+
+// httpLogger - sends log entries in batches to a HTTP endpoint.
+type httpLogger struct {
+	Enable    bool   `json:"enable"`
+	Endpoint  string `json:"endpoint"`
+	Level     string `json:"level"`
+	BatchSize int    `json:"batchSize"`
+}
+
+// httpHook queues log entries and POSTs them as a JSON array to
+// the endpoint once a batch is full or the flush interval elapses.
+type httpHook struct {
+	endpoint      string
+	levels        []logrus.Level
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+	entryCh       chan []byte
+}
+
+func newHTTPHook(endpoint string, level logrus.Level, batchSize int, flushInterval time.Duration) (*httpHook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != httpScheme && u.Scheme != httpsScheme {
+		return nil, fmt.Errorf("Unsupported http logger endpoint %s", endpoint)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultHTTPLogBatchSize
+	}
+	h := &httpHook{
+		endpoint:      endpoint,
+		levels:        logLevelsUpTo(level),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		client: &http.Client{
+			Timeout: httpLogRequestTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: globalRootCAs},
+			},
+		},
+		entryCh: make(chan []byte, httpLogQueueSize),
+	}
+	go h.run()
+	return h, nil
+}
+
+func enableHTTPLogger() {
+	hlogger := serverConfig.Logger.GetHTTP()
+	if !hlogger.Enable {
+		return
+	}
+
+	lvl, err := logrus.ParseLevel(hlogger.Level)
+	fatalIf(err, "Unknown log level found in the config file.")
+
+	hook, err := newHTTPHook(hlogger.Endpoint, lvl, hlogger.BatchSize, httpLogFlushInterval)
+	fatalIf(err, "Invalid http logger configuration.")
+
+	httpLogger := logrus.New()
+
+	// Add a http hook.
+	httpLogger.Hooks.Add(hook)
+
+	// Set default JSON formatter.
+	httpLogger.Out = ioutil.Discard
+	httpLogger.Formatter = new(logrus.JSONFormatter)
+	httpLogger.Level = lvl // Minimum log level.
+
+	log.mu.Lock()
+	log.loggers = append(log.loggers, httpLogger)
+	log.mu.Unlock()
+}
+
+// run - collects queued entries and sends them in batches.
+func (h *httpHook) run() {
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	var batch [][]byte
+	for {
+		select {
+		case entry := <-h.entryCh:
+			batch = append(batch, entry)
+			if len(batch) < h.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		// Errors are not logged to avoid feeding back into this logger.
+		h.send(batch)
+		batch = nil
+	}
+}
+
+// send - POSTs a batch of JSON entries as a JSON array.
+func (h *httpHook) send(batch [][]byte) error {
+	body := append([]byte("["), bytes.Join(batch, []byte(","))...)
+	body = append(body, ']')
+	resp, err := h.client.Post(h.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Unexpected response from %s: %s", h.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// Fire fires the http logger hook and queues the entry.
+func (h *httpHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return fmt.Errorf("Unable to read entry, %v", err)
+	}
+	select {
+	case h.entryCh <- bytes.TrimSpace([]byte(line)):
+		return nil
+	default:
+		return fmt.Errorf("http logger queue is full, dropping entry")
+	}
+}
+
+// Levels - indicate log levels supported, up to the configured level.
+func (h *httpHook) Levels() []logrus.Level {
+	return h.levels
+}
@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests http hook sends entries as a JSON array once a batch is full.
+func TestHTTPHookBatch(t *testing.T) {
+	batchCh := make(chan []map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batchCh <- batch
+	}))
+	defer server.Close()
+
+	hook, err := newHTTPHook(server.URL, logrus.ErrorLevel, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testLog := logrus.New()
+	testLog.Formatter = new(logrus.JSONFormatter)
+	for _, cause := range []string{"first", "second"} {
+		if err = hook.Fire(testLog.WithField("cause", cause)); err != nil {
+			t.Fatalf("Unable to fire http hook, %s", err)
+		}
+	}
+
+	select {
+	case batch := <-batchCh:
+		if len(batch) != 2 || batch[0]["cause"] != "first" || batch[1]["cause"] != "second" {
+			t.Errorf("Unexpected batch %v", batch)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for log batch")
+	}
+}
+
+// Tests invalid http logger endpoints are rejected.
+func TestNewHTTPHook(t *testing.T) {
+	testCases := []struct {
+		endpoint   string
+		shouldPass bool
+	}{
+		{"http://localhost:8080/logs", true},
+		{"https://logs.example.com/ingest", true},
+		{"ftp://localhost/logs", false},
+		{"%zz", false},
+	}
+	for i, testCase := range testCases {
+		_, err := newHTTPHook(testCase.endpoint, logrus.ErrorLevel, 0, time.Hour)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+}
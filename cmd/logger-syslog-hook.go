@@ -0,0 +1,208 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Supported syslog transports.
+const (
+	syslogNetworkUDP = "udp"
+	syslogNetworkTCP = "tcp"
+	syslogNetworkTLS = "tls"
+)
+
+// syslog facility 'daemon' as defined by RFC5424.
+const syslogFacilityDaemon = 3
+
+// RFC5424 timestamp format, at most microsecond precision.
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// Maximum number of messages queued, newer messages are dropped
+// while the syslog server is slow or unreachable.
+const syslogQueueSize = 10000
+
+// syslogLogger - logs to a remote syslog server using RFC5424.
+type syslogLogger struct {
+	Enable  bool   `json:"enable"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Level   string `json:"level"`
+}
+
+// syslogHook queues log entries which are sent in the background to a
+// syslog server over UDP, TCP or TLS, so that logging never waits for
+// the server.
+type syslogHook struct {
+	network  string
+	address  string
+	hostname string
+	levels   []logrus.Level
+	msgCh    chan string
+	conn     net.Conn // Only used by the sending go-routine.
+}
+
+func newSyslogHook(network, address string, level logrus.Level) (*syslogHook, error) {
+	switch network {
+	case syslogNetworkUDP, syslogNetworkTCP, syslogNetworkTLS:
+	default:
+		return nil, fmt.Errorf("Unsupported syslog network %s", network)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	h := &syslogHook{
+		network:  network,
+		address:  address,
+		hostname: hostname,
+		levels:   logLevelsUpTo(level),
+		msgCh:    make(chan string, syslogQueueSize),
+	}
+	go h.run()
+	return h, nil
+}
+
+func enableSyslogLogger() {
+	slogger := serverConfig.Logger.GetSyslog()
+	if !slogger.Enable {
+		return
+	}
+
+	lvl, err := logrus.ParseLevel(slogger.Level)
+	fatalIf(err, "Unknown log level found in the config file.")
+
+	hook, err := newSyslogHook(slogger.Network, slogger.Address, lvl)
+	fatalIf(err, "Invalid syslog logger configuration.")
+
+	syslogLogger := logrus.New()
+
+	// Add a syslog hook.
+	syslogLogger.Hooks.Add(hook)
+
+	// Set default JSON formatter.
+	syslogLogger.Out = ioutil.Discard
+	syslogLogger.Formatter = new(logrus.JSONFormatter)
+	syslogLogger.Level = lvl // Minimum log level.
+
+	log.mu.Lock()
+	log.loggers = append(log.loggers, syslogLogger)
+	log.mu.Unlock()
+}
+
+// syslogSeverity - maps logrus levels to RFC5424 severities.
+func syslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return 0 // Emergency
+	case logrus.FatalLevel:
+		return 2 // Critical
+	case logrus.ErrorLevel:
+		return 3 // Error
+	case logrus.WarnLevel:
+		return 4 // Warning
+	case logrus.InfoLevel:
+		return 6 // Informational
+	}
+	return 7 // Debug
+}
+
+// formatSyslogMessage - formats an RFC5424 syslog message.
+func formatSyslogMessage(hostname string, t time.Time, level logrus.Level, msg string) string {
+	pri := syslogFacilityDaemon*8 + syslogSeverity(level)
+	return fmt.Sprintf("<%d>1 %s %s minio %d - - %s", pri,
+		t.UTC().Format(syslogTimeFormat), hostname, os.Getpid(), msg)
+}
+
+func (h *syslogHook) dial() (net.Conn, error) {
+	switch h.network {
+	case syslogNetworkTLS:
+		host, _, err := net.SplitHostPort(h.address)
+		if err != nil {
+			return nil, err
+		}
+		dialer := &net.Dialer{Timeout: defaultDialTimeout}
+		return tls.DialWithDialer(dialer, "tcp", h.address, &tls.Config{
+			ServerName: host,
+			RootCAs:    globalRootCAs,
+		})
+	default:
+		return net.DialTimeout(h.network, h.address, defaultDialTimeout)
+	}
+}
+
+// write - writes msg, framed with octet counting for stream
+// transports as described by RFC6587.
+func (h *syslogHook) write(msg string) (err error) {
+	if h.conn == nil {
+		if h.conn, err = h.dial(); err != nil {
+			return err
+		}
+	}
+	if h.network != syslogNetworkUDP {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	if _, err = h.conn.Write([]byte(msg)); err != nil {
+		h.conn.Close()
+		h.conn = nil
+	}
+	return err
+}
+
+// run - sends queued messages to the server.
+func (h *syslogHook) run() {
+	for msg := range h.msgCh {
+		// Retry once on a fresh connection, the server may have
+		// closed an idle one. Errors are not logged to avoid
+		// feeding back into this logger.
+		if err := h.write(msg); err != nil {
+			h.write(msg)
+		}
+	}
+}
+
+// Fire fires the syslog logger hook and queues the entry.
+func (h *syslogHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return fmt.Errorf("Unable to read entry, %v", err)
+	}
+	msg := formatSyslogMessage(h.hostname, entry.Time, entry.Level, strings.TrimSuffix(line, "\n"))
+	select {
+	case h.msgCh <- msg:
+		return nil
+	default:
+		return fmt.Errorf("syslog logger queue is full, dropping entry")
+	}
+}
+
+// Levels - indicate log levels supported, up to the configured level.
+func (h *syslogHook) Levels() []logrus.Level {
+	return h.levels
+}
@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests RFC5424 message formatting.
+func TestFormatSyslogMessage(t *testing.T) {
+	ts := time.Date(2017, time.March, 1, 10, 20, 30, 123456000, time.UTC)
+	testCases := []struct {
+		level    logrus.Level
+		expected string
+	}{
+		{logrus.ErrorLevel, fmt.Sprintf("<27>1 2017-03-01T10:20:30.123456Z node1 minio %d - - msg", os.Getpid())},
+		{logrus.FatalLevel, fmt.Sprintf("<26>1 2017-03-01T10:20:30.123456Z node1 minio %d - - msg", os.Getpid())},
+		{logrus.PanicLevel, fmt.Sprintf("<24>1 2017-03-01T10:20:30.123456Z node1 minio %d - - msg", os.Getpid())},
+	}
+	for i, testCase := range testCases {
+		if msg := formatSyslogMessage("node1", ts, testCase.level, "msg"); msg != testCase.expected {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expected, msg)
+		}
+	}
+}
+
+// Tests invalid syslog configurations are rejected.
+func TestNewSyslogHook(t *testing.T) {
+	testCases := []struct {
+		network    string
+		address    string
+		shouldPass bool
+	}{
+		{"udp", "localhost:514", true},
+		{"tcp", "localhost:514", true},
+		{"tls", "localhost:6514", true},
+		{"unix", "localhost:514", false},
+		{"udp", "localhost", false},
+	}
+	for i, testCase := range testCases {
+		_, err := newSyslogHook(testCase.network, testCase.address, logrus.ErrorLevel)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+	}
+}
+
+// Tests syslog hook sends octet counted messages over TCP.
+func TestSyslogHookTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	msgCh := make(chan string, 1)
+	go func() {
+		conn, aerr := listener.Accept()
+		if aerr != nil {
+			return
+		}
+		defer conn.Close()
+		var length int
+		reader := bufio.NewReader(conn)
+		if _, aerr = fmt.Fscanf(reader, "%d ", &length); aerr != nil {
+			return
+		}
+		buf := make([]byte, length)
+		if _, aerr = reader.Read(buf); aerr != nil {
+			return
+		}
+		msgCh <- string(buf)
+	}()
+
+	hook, err := newSyslogHook("tcp", listener.Addr().String(), logrus.ErrorLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testLog := logrus.New()
+	testLog.Formatter = new(logrus.JSONFormatter)
+	if err = hook.Fire(testLog.WithField("cause", "disk failure")); err != nil {
+		t.Fatalf("Unable to fire syslog hook, %s", err)
+	}
+
+	select {
+	case msg := <-msgCh:
+		if !strings.HasPrefix(msg, "<") || !strings.Contains(msg, "disk failure") {
+			t.Errorf("Unexpected syslog message %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for syslog message")
+	}
+}
+
+// Tests syslog hook never waits for the server and fires on the
+// configured levels.
+func TestSyslogHookQueue(t *testing.T) {
+	// Hook without a sending go-routine, as if the server was stuck.
+	hook := &syslogHook{
+		network: "tcp",
+		levels:  logLevelsUpTo(logrus.WarnLevel),
+		msgCh:   make(chan string, 1),
+	}
+	testLog := logrus.New()
+	testLog.Formatter = new(logrus.JSONFormatter)
+	if err := hook.Fire(testLog.WithField("cause", "first")); err != nil {
+		t.Fatalf("Unable to fire syslog hook, %s", err)
+	}
+	if err := hook.Fire(testLog.WithField("cause", "second")); err == nil {
+		t.Fatal("Expected entry to be dropped when the queue is full")
+	}
+
+	expected := []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+	if !reflect.DeepEqual(hook.Levels(), expected) {
+		t.Errorf("Expected levels %v, got %v", expected, hook.Levels())
+	}
+}
@@ -38,13 +38,29 @@ var log = struct {
 //
 //   - console [default]
 //   - file
+//   - syslog
+//   - http
 type logger struct {
 	sync.RWMutex
 	Console consoleLogger `json:"console"`
 	File    fileLogger    `json:"file"`
+	Syslog  syslogLogger  `json:"syslog"`
+	HTTP    httpLogger    `json:"http"`
 	// Add new loggers here.
 }
 
+// logLevelsUpTo - returns the levels as severe as level or more, for
+// hooks to fire on the levels of their logger configuration.
+func logLevelsUpTo(level logrus.Level) []logrus.Level {
+	var levels []logrus.Level
+	for _, l := range logrus.AllLevels {
+		if l <= level {
+			levels = append(levels, l)
+		}
+	}
+	return levels
+}
+
 /// Logger related.
 
 // SetFile set new file logger.
@@ -74,6 +90,34 @@ func (l *logger) GetConsole() consoleLogger {
 	return l.Console
 }
 
+// SetSyslog set new syslog logger.
+func (l *logger) SetSyslog(slogger syslogLogger) {
+	l.Lock()
+	defer l.Unlock()
+	l.Syslog = slogger
+}
+
+// GetSyslog get current syslog logger.
+func (l *logger) GetSyslog() syslogLogger {
+	l.RLock()
+	defer l.RUnlock()
+	return l.Syslog
+}
+
+// SetHTTP set new http logger.
+func (l *logger) SetHTTP(hlogger httpLogger) {
+	l.Lock()
+	defer l.Unlock()
+	l.HTTP = hlogger
+}
+
+// GetHTTP get current http logger.
+func (l *logger) GetHTTP() httpLogger {
+	l.RLock()
+	defer l.RUnlock()
+	return l.HTTP
+}
+
 // Get file, line, function name of the caller.
 func callerSource() string {
 	pc, file, line, success := runtime.Caller(2)
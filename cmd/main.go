@@ -19,7 +19,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"sort"
 	"time"
 
 	"github.com/minio/cli"
@@ -73,28 +72,14 @@ func enableLoggers() {
 	// Enable all loggers here.
 	enableConsoleLogger()
 	enableFileLogger()
+	enableSyslogLogger()
+	enableHTTPLogger()
 	// Add your logger here.
 }
 
+// findClosestCommands - returns commands close to a mistyped command.
 func findClosestCommands(command string) []string {
-	var closestCommands []string
-	for _, value := range commandsTree.PrefixMatch(command) {
-		closestCommands = append(closestCommands, value.(string))
-	}
-	sort.Strings(closestCommands)
-	// Suggest other close commands - allow missed, wrongly added and
-	// even transposed characters
-	for _, value := range commandsTree.Walk(commandsTree.Root()) {
-		if sort.SearchStrings(closestCommands, value.(string)) < len(closestCommands) {
-			continue
-		}
-		// 2 is arbitrary and represents the max
-		// allowed number of typed errors
-		if DamerauLevenshteinDistance(command, value.(string)) < 2 {
-			closestCommands = append(closestCommands, value.(string))
-		}
-	}
-	return closestCommands
+	return commandsIndex.closest(command)
 }
 
 func registerApp() *cli.App {
@@ -102,6 +87,12 @@ func registerApp() *cli.App {
 	registerCommand(serverCmd)
 	registerCommand(versionCmd)
 	registerCommand(updateCmd)
+	registerCommand(completionCmd)
+	registerCommand(configCmd)
+	registerCommand(fsHealCmd)
+	registerCommand(adoptCmd)
+	registerCommand(xlMetaCmd)
+	registerCommand(witnessCmd)
 
 	// Set up app.
 	cli.HelpFlag = cli.BoolFlag{
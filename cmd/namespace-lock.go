@@ -36,6 +36,23 @@ type RWLocker interface {
 	RUnlock()
 }
 
+// readLocker - read only locker.
+type readLocker interface {
+	RLock()
+	RUnlock()
+}
+
+// locklessReadLocker - readLocker which does not lock, it only counts
+// the reads in progress so that the data they may use is not purged
+// under them.
+type locklessReadLocker struct {
+	ns    *nsLockMap
+	param nsParam
+}
+
+func (l locklessReadLocker) RLock()   { l.ns.locklessRLock(l.param) }
+func (l locklessReadLocker) RUnlock() { l.ns.locklessRUnlock(l.param) }
+
 // Initialize distributed locking only in case of distributed setup.
 // Returns if the setup is distributed or not on success.
 func initDsyncNodes(eps []*url.URL) error {
@@ -59,6 +76,11 @@ func initDsyncNodes(eps []*url.URL) error {
 			myNode = index
 		}
 	}
+	if globalWitness != nil {
+		for _, clnt := range globalWitness.newLockClients() {
+			clnts = append(clnts, clnt)
+		}
+	}
 
 	return dsync.Init(clnts, myNode)
 }
@@ -66,9 +88,11 @@ func initDsyncNodes(eps []*url.URL) error {
 // initNSLock - initialize name space lock map.
 func initNSLock(isDistXL bool) {
 	globalNSMutex = &nsLockMap{
-		isDistXL: isDistXL,
-		lockMap:  make(map[nsParam]*nsLock),
-		counters: &lockStat{},
+		isDistXL:       isDistXL,
+		writerPriority: globalLockPolicy == lockPolicyWriterPriority,
+		lockMap:        make(map[nsParam]*nsLock),
+		counters:       &lockStat{},
+		locklessMap:    make(map[nsParam]*locklessReads),
 	}
 
 	// Initialize nsLockMap with entry for instrumentation information.
@@ -83,9 +107,44 @@ type nsParam struct {
 }
 
 // nsLock - provides primitives for locking critical namespace regions.
+// Lock requests of this server are queued fairly on local, then in a
+// distributed setup the lock is also acquired across servers on dist.
 type nsLock struct {
-	RWLocker
-	ref uint
+	local *fairRWMutex
+	dist  RWLocker
+	ref   uint
+}
+
+// Lock - locks for writing.
+func (l *nsLock) Lock() {
+	l.local.Lock()
+	if l.dist != nil {
+		l.dist.Lock()
+	}
+}
+
+// Unlock - releases the write lock.
+func (l *nsLock) Unlock() {
+	if l.dist != nil {
+		l.dist.Unlock()
+	}
+	l.local.Unlock()
+}
+
+// RLock - locks for reading.
+func (l *nsLock) RLock() {
+	l.local.RLock()
+	if l.dist != nil {
+		l.dist.RLock()
+	}
+}
+
+// RUnlock - releases a read lock.
+func (l *nsLock) RUnlock() {
+	if l.dist != nil {
+		l.dist.RUnlock()
+	}
+	l.local.RUnlock()
 }
 
 // nsLockMap - namespace lock map, provides primitives to Lock,
@@ -96,11 +155,31 @@ type nsLockMap struct {
 	debugLockMap map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
 
 	// Indicates if namespace is part of a distributed setup.
-	isDistXL     bool
-	lockMap      map[nsParam]*nsLock
-	lockMapMutex sync.Mutex
+	isDistXL bool
+	// Grant queued writers before queued readers.
+	writerPriority bool
+	lockMap        map[nsParam]*nsLock
+	lockMapMutex   sync.Mutex
+
+	// Metrics of locks removed from lockMap, at most maxIdleLockStats
+	// entries.
+	idleLockStats map[nsParam]fairLockStats
+
+	// Lockless reads in progress.
+	locklessMap   map[nsParam]*locklessReads
+	locklessMutex sync.Mutex
+}
+
+// locklessReads - number of lockless reads of a volume/path in
+// progress and the purge waiting for them to be done.
+type locklessReads struct {
+	readers int
+	purge   func()
 }
 
+// Maximum number of idle locks whose metrics are kept.
+const maxIdleLockStats = 10000
+
 // Lock the namespace resource.
 func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool) {
 	var nsLk *nsLock
@@ -110,13 +189,11 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	nsLk, found := n.lockMap[param]
 	if !found {
 		nsLk = &nsLock{
-			RWLocker: func() RWLocker {
-				if n.isDistXL {
-					return dsync.NewDRWMutex(pathJoin(volume, path))
-				}
-				return &sync.RWMutex{}
-			}(),
-			ref: 0,
+			local: newFairRWMutex(n.writerPriority),
+			ref:   0,
+		}
+		if n.isDistXL {
+			nsLk.dist = dsync.NewDRWMutex(pathJoin(volume, path))
 		}
 		n.lockMap[param] = nsLk
 	}
@@ -169,15 +246,18 @@ func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
 		if nsLk.ref != 0 {
 			nsLk.ref--
 
-			// delete the lock state entry for given operation ID.
+			// delete the lock state entry for given operation ID,
+			// already deleted if the lock was released by ForceUnlock.
 			err := n.deleteLockInfoEntryForOps(param, opsID)
-			if err != nil {
+			if _, ok := errorCause(err).(LockInfoOpsIDNotFound); err != nil && !ok {
 				errorIf(err, "Failed to delete lock info entry")
 			}
 		}
 		if nsLk.ref == 0 {
-			// Remove from the map if there are no more references.
+			// Remove from the map if there are no more references,
+			// keeping its metrics.
 			delete(n.lockMap, param)
+			n.saveIdleLockStats(param, nsLk.local.Stats())
 
 			// delete the lock state entry for given
 			// <volume, path> pair.
@@ -189,6 +269,33 @@ func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
 	}
 }
 
+// saveIdleLockStats - accumulates the metrics of a lock removed from
+// lockMap. Caller must hold lockMapMutex.
+func (n *nsLockMap) saveIdleLockStats(param nsParam, stats fairLockStats) {
+	if n.idleLockStats == nil {
+		n.idleLockStats = make(map[nsParam]fairLockStats)
+	}
+	idleStats, ok := n.idleLockStats[param]
+	if !ok && len(n.idleLockStats) >= maxIdleLockStats {
+		// Make room by dropping the metrics of any other lock.
+		for evictParam := range n.idleLockStats {
+			delete(n.idleLockStats, evictParam)
+			break
+		}
+	}
+	n.idleLockStats[param] = idleStats.add(stats)
+}
+
+// lockStats - returns the metrics of the lock on param since the
+// server started. Caller must hold lockMapMutex.
+func (n *nsLockMap) lockStats(param nsParam) fairLockStats {
+	stats := n.idleLockStats[param]
+	if nsLk, ok := n.lockMap[param]; ok {
+		stats = stats.add(nsLk.local.Stats())
+	}
+	return stats
+}
+
 // Lock - locks the given resource for writes, using a previously
 // allocated name space lock or initializing a new one.
 func (n *nsLockMap) Lock(volume, path, opsID string) {
@@ -225,32 +332,27 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 
 	// Clarification on operation:
 	// - In case of FS or XL we call ForceUnlock on the local globalNSMutex
-	//   (since there is only a single server) which will cause the 'stuck'
-	//   mutex to be removed from the map. Existing operations for this
-	//   will continue to be blocked (and timeout). New operations on this
-	//   resource will use a new mutex and proceed normally.
+	//   (since there is only a single server) which releases the 'stuck'
+	//   mutex. Operations queued for this resource are granted the lock
+	//   and new operations proceed normally.
 	//
 	// - In case of Distributed setup (using dsync), there is no need to call
 	//   ForceUnlock on the server where the lock was acquired and is presumably
 	//   'stuck'. Instead dsync.ForceUnlock() will release the underlying locks
 	//   that participated in granting the lock. Any pending dsync locks that
 	//   are blocking can now proceed as normal and any new locks will also
-	//   participate normally.
+	//   participate normally. Operations of this server queued on the local
+	//   mutex are released as in the single server case.
 	if n.isDistXL { // For distributed mode, broadcast ForceUnlock message.
 		dsync.NewDRWMutex(pathJoin(volume, path)).ForceUnlock()
 	}
 
+	// The lock stays in the map, so that the operations it is granted
+	// to, and the released ones, unlock the same mutex.
 	param := nsParam{volume, path}
-	if _, found := n.lockMap[param]; found {
-		// Remove lock from the map.
-		delete(n.lockMap, param)
-
-		// delete the lock state entry for given
-		// <volume, path> pair.
-		err := n.deleteLockInfoEntryForVolumePath(param)
-		if err != nil {
-			errorIf(err, "Failed to delete lock info entry")
-		}
+	if nsLk, found := n.lockMap[param]; found {
+		nsLk.local.ForceUnlock()
+		n.deleteRunningLockInfo(param)
 	}
 }
 
@@ -267,6 +369,82 @@ func (n *nsLockMap) NewNSLock(volume, path string) RWLocker {
 	return &lockInstance{n, volume, path, getOpsID()}
 }
 
+// NewObjectReadLock - returns the read lock GET and HEAD object
+// requests take on volume/path. With lockless reads it does not lock,
+// such requests read the object metadata once and use it to read the
+// data. Overwrites keep the data of the previous version until such
+// reads are done, see deferWhileLocklessReads, but a read racing with
+// a delete of the object, or in distributed setups with an overwrite
+// through another server, may fail.
+func (n *nsLockMap) NewObjectReadLock(volume, path string) readLocker {
+	if globalLocklessReads {
+		return locklessReadLocker{n, nsParam{volume, path}}
+	}
+	return n.NewNSLock(volume, path)
+}
+
+// locklessRLock - counts a lockless read of param in progress.
+func (n *nsLockMap) locklessRLock(param nsParam) {
+	n.locklessMutex.Lock()
+	defer n.locklessMutex.Unlock()
+
+	reads, found := n.locklessMap[param]
+	if !found {
+		reads = &locklessReads{}
+		n.locklessMap[param] = reads
+	}
+	reads.readers++
+}
+
+// locklessRUnlock - counts a lockless read of param done, the last one
+// starts the purge waiting for the reads, if any.
+func (n *nsLockMap) locklessRUnlock(param nsParam) {
+	n.locklessMutex.Lock()
+	defer n.locklessMutex.Unlock()
+
+	reads, found := n.locklessMap[param]
+	if !found {
+		return
+	}
+	if reads.readers--; reads.readers > 0 {
+		return
+	}
+	delete(n.locklessMap, param)
+	if reads.purge == nil {
+		return
+	}
+
+	// Purge with the write lock held so that no write commits
+	// meanwhile, reads started since then defer it once more.
+	go func(purge func()) {
+		writeLock := n.NewNSLock(param.volume, param.path)
+		writeLock.Lock()
+		defer writeLock.Unlock()
+
+		if !n.deferWhileLocklessReads(param.volume, param.path, purge) {
+			purge()
+		}
+	}(reads.purge)
+}
+
+// deferWhileLocklessReads - returns false if no lockless reads of
+// volume/path are in progress. Otherwise purge is called once they are
+// all done, with the write lock of volume/path held, and true is
+// returned. Callers must hold the write lock. A later purge replaces
+// an earlier one still waiting, so purge must find out what is stale
+// when it is called.
+func (n *nsLockMap) deferWhileLocklessReads(volume, path string, purge func()) bool {
+	n.locklessMutex.Lock()
+	defer n.locklessMutex.Unlock()
+
+	reads, found := n.locklessMap[nsParam{volume, path}]
+	if !found {
+		return false
+	}
+	reads.purge = purge
+	return true
+}
+
 // Lock - block until write lock is taken.
 func (li *lockInstance) Lock() {
 	lockSource := callerSource()
@@ -388,19 +388,27 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 	// Create lock.
 	lock := globalNSMutex.NewNSLock("bucket", "object")
 	lock.Lock()
-	// Forcefully unlock lock.
-	globalNSMutex.ForceUnlock("bucket", "object")
 
 	ch := make(chan struct{}, 1)
 
+	// Try to claim lock again, queued behind the first one.
+	anotherLock := globalNSMutex.NewNSLock("bucket", "object")
 	go func() {
-		// Try to claim lock again.
-		anotherLock := globalNSMutex.NewNSLock("bucket", "object")
 		anotherLock.Lock()
 		// And signal succes.
 		ch <- struct{}{}
 	}()
 
+	// Forcefully unlock lock once the other one is queued.
+	deadline := time.Now().Add(5 * time.Second)
+	for globalNSMutex.lockStats(nsParam{"bucket", "object"}).WaitingWriters != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the lock to be queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	globalNSMutex.ForceUnlock("bucket", "object")
+
 	select {
 	case <-ch:
 		// Signalled so all is fine.
@@ -411,6 +419,126 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 		t.Errorf("Lock not cleared.")
 	}
 
-	// Clean up lock.
-	globalNSMutex.ForceUnlock("bucket", "object")
+	// Clean up locks, the forcefully unlocked one included.
+	lock.Unlock()
+	anotherLock.Unlock()
+	globalNSMutex.lockMapMutex.Lock()
+	_, found := globalNSMutex.lockMap[nsParam{"bucket", "object"}]
+	globalNSMutex.lockMapMutex.Unlock()
+	if found {
+		t.Errorf("Expected the lock to be removed once unlocked")
+	}
+}
+
+// Tests that object read locks do not wait for writers with lockless
+// reads.
+func TestNewObjectReadLock(t *testing.T) {
+	defer func() { globalLocklessReads = false }()
+
+	writeLock := globalNSMutex.NewNSLock("bucket", "object")
+	writeLock.Lock()
+	defer writeLock.Unlock()
+
+	globalLocklessReads = true
+	readLock := globalNSMutex.NewObjectReadLock("bucket", "object")
+	locked := make(chan struct{})
+	go func() {
+		readLock.RLock()
+		readLock.RUnlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the read lock not to wait for the write lock")
+	}
+
+	globalLocklessReads = false
+	if _, ok := globalNSMutex.NewObjectReadLock("bucket", "object").(*lockInstance); !ok {
+		t.Error("Expected a namespace lock without lockless reads")
+	}
+}
+
+// Tests that purges wait for lockless reads in progress, and take the
+// write lock once they are done.
+func TestDeferWhileLocklessReads(t *testing.T) {
+	defer func() { globalLocklessReads = false }()
+	globalLocklessReads = true
+
+	purged := make(chan struct{}, 2)
+	purge := func() { purged <- struct{}{} }
+	if globalNSMutex.deferWhileLocklessReads("bucket", "object", purge) {
+		t.Fatal("Expected no purge deferred without lockless reads")
+	}
+
+	readLock := globalNSMutex.NewObjectReadLock("bucket", "object")
+	readLock.RLock()
+	otherReadLock := globalNSMutex.NewObjectReadLock("bucket", "object")
+	otherReadLock.RLock()
+
+	writeLock := globalNSMutex.NewNSLock("bucket", "object")
+	writeLock.Lock()
+	// A later purge replaces the earlier one.
+	if !globalNSMutex.deferWhileLocklessReads("bucket", "object", func() { t.Error("Expected the earlier purge to be replaced") }) {
+		t.Fatal("Expected the purge deferred with lockless reads")
+	}
+	if !globalNSMutex.deferWhileLocklessReads("bucket", "object", purge) {
+		t.Fatal("Expected the purge deferred with lockless reads")
+	}
+
+	readLock.RUnlock()
+	otherReadLock.RUnlock()
+	select {
+	case <-purged:
+		t.Fatal("Expected the purge to wait for the write lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	writeLock.Unlock()
+
+	select {
+	case <-purged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the purge once lockless reads are done")
+	}
+
+	// The write lock of the purge is released.
+	locked := make(chan struct{})
+	go func() {
+		writeLock.Lock()
+		writeLock.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the write lock to be released after the purge")
+	}
+}
+
+// Tests that lock metrics are kept once a lock is no longer referenced,
+// and that at most maxIdleLockStats idle locks are tracked.
+func TestNamespaceLockIdleStats(t *testing.T) {
+	n := &nsLockMap{
+		debugLockMap: make(map[nsParam]*debugLockInfoPerVolumePath),
+		lockMap:      make(map[nsParam]*nsLock),
+		counters:     &lockStat{},
+	}
+	param := nsParam{"bucket", "object"}
+	for i := 0; i < 2; i++ {
+		n.Lock(param.volume, param.path, "opsID")
+		n.Unlock(param.volume, param.path, "opsID")
+	}
+	if _, ok := n.lockMap[param]; ok {
+		t.Fatal("Expected the lock to be removed once unlocked")
+	}
+	if stats := n.lockStats(param); stats.Acquired != 2 {
+		t.Errorf("Expected the lock to be granted 2 times, got %d", stats.Acquired)
+	}
+
+	for i := 0; i < maxIdleLockStats; i++ {
+		n.saveIdleLockStats(nsParam{"bucket", strconv.Itoa(i)}, fairLockStats{Acquired: 1})
+	}
+	if len(n.idleLockStats) != maxIdleLockStats {
+		t.Errorf("Expected %d idle locks, got %d", maxIdleLockStats, len(n.idleLockStats))
+	}
 }
@@ -157,9 +157,27 @@ func (d *naughtyDisk) DeleteFile(volume string, path string) (err error) {
 	return d.disk.DeleteFile(volume, path)
 }
 
+func (d *naughtyDisk) MakeDir(volume string, dirPath string) (err error) {
+	if err := d.calcError(); err != nil {
+		return err
+	}
+	return d.disk.MakeDir(volume, dirPath)
+}
+
 func (d *naughtyDisk) ReadAll(volume string, path string) (buf []byte, err error) {
 	if err := d.calcError(); err != nil {
 		return nil, err
 	}
 	return d.disk.ReadAll(volume, path)
 }
+
+func (d *naughtyDisk) ReadAllBulk(volume string, paths []string) (bufs [][]byte, errs []error) {
+	if err := d.calcError(); err != nil {
+		errs = make([]error, len(paths))
+		for i := range errs {
+			errs[i] = err
+		}
+		return make([][]byte, len(paths)), errs
+	}
+	return d.disk.ReadAllBulk(volume, paths)
+}
@@ -77,11 +77,24 @@ func (rpcClient *RPCClient) dial() (netRPCClient *rpc.Client, err error) {
 			return nil, err
 		}
 
-		// ServerName in tls.Config needs to be specified to support SNI certificates.
-		conn, err = tls.Dial("tcp", rpcClient.serverAddr, &tls.Config{ServerName: hostname, RootCAs: globalRootCAs})
+		tlsConfig := newRPCClientTLSConfig(hostname)
+		if globalRPCClientAuth {
+			// Present this node's certificate so that the peer can authenticate us.
+			var cert tls.Certificate
+			if cert, err = tls.LoadX509KeyPair(getCertFile(), getKeyFile()); err != nil {
+				return nil, &net.OpError{
+					Op:   "dial-http",
+					Net:  rpcClient.serverAddr + rpcClient.serviceEndpoint,
+					Addr: nil,
+					Err:  fmt.Errorf("Unable to load client certificate: %s", err.Error()),
+				}
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		conn, err = tls.Dial("tcp", globalPeerResolver.resolve(rpcClient.serverAddr), tlsConfig)
 	} else {
 		// Dial with a timeout.
-		conn, err = net.DialTimeout("tcp", rpcClient.serverAddr, defaultDialTimeout)
+		conn, err = net.DialTimeout("tcp", globalPeerResolver.resolve(rpcClient.serverAddr), defaultDialTimeout)
 	}
 
 	if err != nil {
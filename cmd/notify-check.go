@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Time given to all notification targets of a config to be dialed
+// when the config is set.
+const notifyTargetCheckTimeout = 10 * time.Second
+
+var errNotifyTargetCheckTimeout = errors.New("Timed out connecting to the notification target")
+
+// notifyTargetCheck - represents the result of connecting to an
+// enabled notification target of a config.
+type notifyTargetCheck struct {
+	ARN    string `json:"arn"`
+	ErrSet bool   `json:"errSet"`
+	ErrMsg string `json:"errMsg"`
+}
+
+// notifyTargetsFailed - returns true if any of the targets could not
+// be connected to.
+func notifyTargetsFailed(targets []notifyTargetCheck) bool {
+	for _, target := range targets {
+		if target.ErrSet {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotifyTargets - connects to every enabled notification target
+// of srvCfg, as servers do on startup, and closes the connections
+// right away. Returns the results sorted by target ARN.
+func checkNotifyTargets(srvCfg *serverConfigV16) []notifyTargetCheck {
+	if srvCfg.Notify == nil {
+		return nil
+	}
+
+	dials := make(map[string]func() error)
+	addDial := func(accountID, queueType string, dial func() error) {
+		dials[minioSqs+srvCfg.Region+":"+accountID+":"+queueType] = dial
+	}
+	for accountID, amqpN := range srvCfg.Notify.GetAMQP() {
+		if !amqpN.Enable {
+			continue
+		}
+		amqpN := amqpN
+		addDial(accountID, queueTypeAMQP, func() error {
+			conn, err := dialAMQP(amqpN)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		})
+	}
+	for accountID, natsN := range srvCfg.Notify.GetNATS() {
+		if !natsN.Enable {
+			continue
+		}
+		natsN := natsN
+		addDial(accountID, queueTypeNATS, func() error {
+			conn, err := dialNATS(natsN, true)
+			if err != nil {
+				return err
+			}
+			closeNATS(conn)
+			return nil
+		})
+	}
+	for accountID, redisN := range srvCfg.Notify.GetRedis() {
+		if !redisN.Enable {
+			continue
+		}
+		redisN := redisN
+		addDial(accountID, queueTypeRedis, func() error {
+			pool, err := dialRedis(redisN)
+			if err != nil {
+				return err
+			}
+			return pool.Close()
+		})
+	}
+	for accountID, webhookN := range srvCfg.Notify.GetWebhook() {
+		if !webhookN.Enable {
+			continue
+		}
+		webhookN := webhookN
+		addDial(accountID, queueTypeWebhook, func() error {
+			if webhookN.Endpoint == "" {
+				return errInvalidArgument
+			}
+			u, err := url.Parse(webhookN.Endpoint)
+			if err != nil {
+				return err
+			}
+			return lookupEndpoint(u)
+		})
+	}
+	for accountID, elasticN := range srvCfg.Notify.GetElasticSearch() {
+		if !elasticN.Enable {
+			continue
+		}
+		elasticN := elasticN
+		addDial(accountID, queueTypeElastic, func() error {
+			client, err := dialElastic(elasticN)
+			if err != nil {
+				return err
+			}
+			client.Stop()
+			return nil
+		})
+	}
+	for accountID, pgN := range srvCfg.Notify.GetPostgreSQL() {
+		if !pgN.Enable {
+			continue
+		}
+		pgN := pgN
+		addDial(accountID, queueTypePostgreSQL, func() error {
+			conn, err := dialPostgreSQL(pgN)
+			if err != nil {
+				return err
+			}
+			conn.Close()
+			return nil
+		})
+	}
+	for accountID, kafkaN := range srvCfg.Notify.GetKafka() {
+		if !kafkaN.Enable {
+			continue
+		}
+		kafkaN := kafkaN
+		addDial(accountID, queueTypeKafka, func() error {
+			conn, err := dialKafka(kafkaN)
+			if err != nil {
+				return err
+			}
+			conn.Close()
+			return nil
+		})
+	}
+
+	// Dial all targets in parallel, targets not answering in time
+	// are reported as such.
+	type dialResult struct {
+		arn string
+		err error
+	}
+	resultCh := make(chan dialResult, len(dials))
+	for arn, dial := range dials {
+		go func(arn string, dial func() error) {
+			resultCh <- dialResult{arn, dial()}
+		}(arn, dial)
+	}
+
+	errs := make(map[string]error)
+	timer := time.NewTimer(notifyTargetCheckTimeout)
+	defer timer.Stop()
+	for len(errs) < len(dials) {
+		select {
+		case result := <-resultCh:
+			errs[result.arn] = result.err
+		case <-timer.C:
+			for arn := range dials {
+				if _, ok := errs[arn]; !ok {
+					errs[arn] = errNotifyTargetCheckTimeout
+				}
+			}
+		}
+	}
+
+	var arns []string
+	for arn := range dials {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	var targets []notifyTargetCheck
+	for _, arn := range arns {
+		target := notifyTargetCheck{ARN: arn}
+		if err := errs[arn]; err != nil {
+			target.ErrSet = true
+			target.ErrMsg = err.Error()
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
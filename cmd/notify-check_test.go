@@ -0,0 +1,86 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests connecting to the notification targets of a config.
+func TestCheckNotifyTargets(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// No notification configured.
+	if targets := checkNotifyTargets(&serverConfigV16{}); len(targets) != 0 {
+		t.Fatalf("Expected no targets, got %v", targets)
+	}
+
+	server := httptest.NewServer(postHandler{})
+	defer server.Close()
+
+	// Address nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedEndpoint := "http://" + listener.Addr().String()
+	listener.Close()
+
+	srvCfg := &serverConfigV16{Region: "us-west-1", Notify: &notifier{}}
+	srvCfg.Notify.Webhook = webhookConfigs{
+		"1": {Enable: true, Endpoint: server.URL},
+		"2": {Enable: false, Endpoint: closedEndpoint},
+		"3": {Enable: true, Endpoint: closedEndpoint},
+		"4": {Enable: true, Endpoint: ""},
+	}
+	srvCfg.Notify.Redis = redisConfigs{
+		"1": {Enable: false, Addr: "127.0.0.1:1"},
+	}
+
+	targets := checkNotifyTargets(srvCfg)
+	expected := []struct {
+		arn    string
+		errSet bool
+	}{
+		{"arn:minio:sqs:us-west-1:1:webhook", false},
+		{"arn:minio:sqs:us-west-1:3:webhook", true},
+		{"arn:minio:sqs:us-west-1:4:webhook", true},
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %v", len(expected), targets)
+	}
+	for i, e := range expected {
+		if targets[i].ARN != e.arn || targets[i].ErrSet != e.errSet {
+			t.Errorf("Test %d: expected %s with error %v, got %v", i+1, e.arn, e.errSet, targets[i])
+		}
+		if targets[i].ErrSet && targets[i].ErrMsg == "" {
+			t.Errorf("Test %d: expected an error message", i+1)
+		}
+	}
+	if !notifyTargetsFailed(targets) {
+		t.Error("Expected targets to have failed")
+	}
+	if notifyTargetsFailed(targets[:1]) {
+		t.Error("Expected target not to have failed")
+	}
+}
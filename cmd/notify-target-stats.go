@@ -0,0 +1,130 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// NotifyTargetStats holds the event counters of a notification target
+// of a server, since the server started.
+type NotifyTargetStats struct {
+	ARN    string `json:"arn"`
+	Sent   uint64 `json:"sent"`
+	Failed uint64 `json:"failed"`
+	// Events being sent, targets send events synchronously so this is
+	// the number of requests waiting on the target.
+	Pending       int64     `json:"pending"`
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// notifyTargetCounters - event counters of a notification target.
+type notifyTargetCounters struct {
+	sent    counter
+	failed  counter
+	pending int64 // Updated atomically.
+
+	mu            sync.Mutex
+	lastError     string
+	lastErrorTime time.Time
+}
+
+// Counters of all notification targets, by ARN. They are kept when
+// targets are re-initialized with the same ARN.
+var notifyTargetCountersMap = struct {
+	sync.Mutex
+	targets map[string]*notifyTargetCounters
+}{targets: make(map[string]*notifyTargetCounters)}
+
+// getNotifyTargetCounters - returns the counters of the target arn.
+func getNotifyTargetCounters(arn string) *notifyTargetCounters {
+	notifyTargetCountersMap.Lock()
+	defer notifyTargetCountersMap.Unlock()
+	c, ok := notifyTargetCountersMap.targets[arn]
+	if !ok {
+		c = &notifyTargetCounters{}
+		notifyTargetCountersMap.targets[arn] = c
+	}
+	return c
+}
+
+// notifyStatsHook - counts events fired through a target hook.
+type notifyStatsHook struct {
+	hook     logrus.Hook
+	counters *notifyTargetCounters
+}
+
+func (h notifyStatsHook) Levels() []logrus.Level {
+	return h.hook.Levels()
+}
+
+func (h notifyStatsHook) Fire(entry *logrus.Entry) error {
+	atomic.AddInt64(&h.counters.pending, 1)
+	err := h.hook.Fire(entry)
+	atomic.AddInt64(&h.counters.pending, -1)
+	if err != nil {
+		h.counters.failed.Inc(1)
+		h.counters.mu.Lock()
+		h.counters.lastError = err.Error()
+		h.counters.lastErrorTime = time.Now().UTC()
+		h.counters.mu.Unlock()
+		return err
+	}
+	h.counters.sent.Inc(1)
+	return nil
+}
+
+// countNotifyTargetEvents - counts events sent through the hooks of
+// the target logger of arn.
+func countNotifyTargetEvents(arn string, logger *logrus.Logger) {
+	counters := getNotifyTargetCounters(arn)
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		for _, hook := range levelHooks {
+			hooks[level] = append(hooks[level], notifyStatsHook{hook, counters})
+		}
+	}
+	logger.Hooks = hooks
+}
+
+// getNotifyTargetStats - returns the counters of the notification
+// targets of this server, sorted by ARN.
+func getNotifyTargetStats(arns []string) []NotifyTargetStats {
+	sort.Strings(arns)
+	stats := make([]NotifyTargetStats, 0, len(arns))
+	for _, arn := range arns {
+		c := getNotifyTargetCounters(arn)
+		c.mu.Lock()
+		lastError, lastErrorTime := c.lastError, c.lastErrorTime
+		c.mu.Unlock()
+		stats = append(stats, NotifyTargetStats{
+			ARN:           arn,
+			Sent:          c.sent.Value(),
+			Failed:        c.failed.Value(),
+			Pending:       atomic.LoadInt64(&c.pending),
+			LastError:     lastError,
+			LastErrorTime: lastErrorTime,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,77 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// testNotifyHook - fails events when err is set.
+type testNotifyHook struct {
+	err *error
+}
+
+func (h testNotifyHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.InfoLevel}
+}
+
+func (h testNotifyHook) Fire(entry *logrus.Entry) error {
+	return *h.err
+}
+
+// Tests counting events sent to notification targets.
+func TestNotifyTargetStats(t *testing.T) {
+	arn := "arn:minio:sqs:us-east-1:stats:webhook"
+	var fireErr error
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.Hooks.Add(testNotifyHook{&fireErr})
+	countNotifyTargetEvents(arn, logger)
+
+	logger.Info("sent")
+	logger.Info("sent")
+	fireErr = errors.New("connection refused")
+	logger.Info("failed")
+	// Other levels are not sent to the target.
+	logger.Warn("ignored")
+
+	stats := getNotifyTargetStats([]string{arn})
+	if len(stats) != 1 {
+		t.Fatalf("Expected stats of 1 target, got %d", len(stats))
+	}
+	if stats[0].ARN != arn || stats[0].Sent != 2 || stats[0].Failed != 1 || stats[0].Pending != 0 {
+		t.Fatalf("Unexpected stats %#v", stats[0])
+	}
+	if stats[0].LastError != "connection refused" || stats[0].LastErrorTime.IsZero() {
+		t.Fatalf("Unexpected last error %q at %v", stats[0].LastError, stats[0].LastErrorTime)
+	}
+
+	// Counters are kept when the target is re-initialized.
+	fireErr = nil
+	logger = logrus.New()
+	logger.Out = ioutil.Discard
+	logger.Hooks.Add(testNotifyHook{&fireErr})
+	countNotifyTargetEvents(arn, logger)
+	logger.Info("sent")
+	if stats = getNotifyTargetStats([]string{arn}); stats[0].Sent != 3 || stats[0].Failed != 1 {
+		t.Fatalf("Unexpected stats %#v", stats[0])
+	}
+}
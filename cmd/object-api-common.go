@@ -61,23 +61,37 @@ func isObjectDir(object string, size int64) bool {
 	return hasSuffix(object, slashSeparator) && size == 0
 }
 
-// Converts just bucket, object metadata into ObjectInfo datatype.
-func dirObjectInfo(bucket, object string, size int64, metadata map[string]string) ObjectInfo {
-	// This is a special case with size as '0' and object ends with
-	// a slash separator, we treat it like a valid operation and
-	// return success.
-	md5Sum := metadata["md5Sum"]
-	delete(metadata, "md5Sum")
+// md5Sum of the empty content of directory objects.
+const dirObjectMD5Sum = "d41d8cd98f00b204e9800998ecf8427e"
+
+// Converts a directory object, an empty directory created by writing
+// a zero byte object with a trailing slash, into ObjectInfo datatype.
+// Directory objects keep no metadata.
+func dirObjectInfo(bucket, object string, modTime time.Time) ObjectInfo {
 	return ObjectInfo{
 		Bucket:      bucket,
 		Name:        object,
-		ModTime:     time.Now().UTC(),
+		ModTime:     modTime,
 		ContentType: "application/octet-stream",
-		IsDir:       true,
-		Size:        size,
-		MD5Sum:      md5Sum,
-		UserDefined: metadata,
+		MD5Sum:      dirObjectMD5Sum,
+		UserDefined: map[string]string{},
+	}
+}
+
+// checkObjectDirMetadata - returns ObjectDirMetadata unless metadata
+// is only what directory objects are returned with anyway, as they
+// keep no metadata.
+func checkObjectDirMetadata(bucket, object string, metadata map[string]string) error {
+	for key, value := range metadata {
+		switch {
+		case key == "md5Sum" && (value == "" || value == dirObjectMD5Sum):
+		case key == "content-type" && (value == "" || value == "application/octet-stream" || value == fuseDirContentType):
+		default:
+
+			return traceError(ObjectDirMetadata{Bucket: bucket, Object: object})
+		}
 	}
+	return nil
 }
 
 // House keeping code for FS/XL and distributed Minio setup.
@@ -134,13 +148,14 @@ func isLocalStorage(ep *url.URL) bool {
 	if globalMinioHost != "" && globalMinioPort != "" {
 		// if --address host:port was specified for distXL we short
 		// circuit only the endPoint that matches host:port
-		if net.JoinHostPort(globalMinioHost, globalMinioPort) == ep.Host {
+		if isSameHostPort(net.JoinHostPort(globalMinioHost, globalMinioPort), ep.Host) {
 			return true
 		}
 		return false
 	}
-	// Split host to extract host information.
-	host, _, err := net.SplitHostPort(ep.Host)
+	// Split host to extract host information, of the address found by
+	// service discovery if any.
+	host, _, err := net.SplitHostPort(globalPeerResolver.resolve(ep.Host))
 	if err != nil {
 		errorIf(err, "Cannot split host port")
 		return false
@@ -114,6 +114,30 @@ type ObjectInfo struct {
 	// User-Defined metadata
 	UserDefined    map[string]string
 	HealObjectInfo *HealObjectInfo `xml:"HealObjectInfo,omitempty"`
+
+	// Optimal range boundaries of the object, nil if not known.
+	RangeHint *RangeHint
+}
+
+// RangeHint - describes the erasure layout of an object so that
+// clients can issue aligned parallel range GETs. Each part is erasure
+// coded in blocks of BlockSize, aligned ranges start at a part
+// boundary or a multiple of BlockSize within a part.
+type RangeHint struct {
+	BlockSize int64
+	PartSizes []int64
+}
+
+// IsAligned - returns true if offset is an aligned range boundary,
+// the end of the object is a boundary as well.
+func (h RangeHint) IsAligned(offset int64) bool {
+	for _, partSize := range h.PartSizes {
+		if offset < partSize {
+			return offset%h.BlockSize == 0
+		}
+		offset -= partSize
+	}
+	return offset == 0
 }
 
 // ListPartsInfo - represents list of all parts.
@@ -220,6 +244,39 @@ type ListObjectsInfo struct {
 	Prefixes []string
 }
 
+// ObjectVersionInfo - represents a version of an object.
+type ObjectVersionInfo struct {
+	ObjectInfo
+
+	// Version id of the object, "null" for versions written while
+	// versioning was not enabled on the bucket.
+	VersionID string
+
+	// Indicates whether this is the latest version of the object.
+	IsLatest bool
+
+	// Indicates whether this version is a delete marker.
+	DeleteMarker bool
+}
+
+// ListObjectVersionsInfo - container for list object versions.
+type ListObjectVersionsInfo struct {
+	// Indicates whether the returned list of versions is truncated.
+	IsTruncated bool
+
+	// When the list is truncated, NextKeyMarker and NextVersionIDMarker
+	// are to be used as key-marker and version-id-marker in the
+	// subsequent request.
+	NextKeyMarker       string
+	NextVersionIDMarker string
+
+	// List of versions for this request, newest first for each object.
+	Versions []ObjectVersionInfo
+
+	// List of prefixes for this request.
+	Prefixes []string
+}
+
 // PartInfo - represents individual part metadata.
 type PartInfo struct {
 	// Part number that identifies the part. This is a positive integer between
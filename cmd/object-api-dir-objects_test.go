@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Wrapper for calling directory object tests for both XL multiple disks and single node setup.
+func TestObjectDirs(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectDirs)
+}
+
+// testObjectDirs - tests creating, listing and deleting directory objects.
+func testObjectDirs(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	objInfo, err := obj.PutObject(bucket, "dir/", 0, bytes.NewReader(nil), nil, "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.Name != "dir/" || objInfo.Size != 0 || objInfo.MD5Sum != dirObjectMD5Sum {
+		t.Fatalf("%s: Unexpected directory object info %+v", instanceType, objInfo)
+	}
+	if _, err = obj.PutObject(bucket, "other/sub/", 0, bytes.NewReader(nil), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err = obj.PutObject(bucket, "file", 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	// Directory objects cannot be created under objects.
+	if _, err = obj.PutObject(bucket, "file/dir/", 0, bytes.NewReader(nil), nil, ""); err == nil {
+		t.Fatalf("%s: Expected directory object under an object to fail", instanceType)
+	}
+
+	if objInfo, err = obj.GetObjectInfo(bucket, "dir/"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.Name != "dir/" || objInfo.Size != 0 || objInfo.IsDir {
+		t.Fatalf("%s: Unexpected directory object info %+v", instanceType, objInfo)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, "dir/", 0, 0, &buffer); err != nil || buffer.Len() != 0 {
+		t.Fatalf("%s: Expected empty directory object, got %q, %v", instanceType, buffer.String(), err)
+	}
+	// Directories with objects under them are not directory objects,
+	// their names stay invalid object names.
+	if _, err = obj.GetObjectInfo(bucket, "other/"); !isErrObjectNameInvalid(err) {
+		t.Fatalf("%s: Expected ObjectNameInvalid, got %v", instanceType, err)
+	}
+	if err = obj.DeleteObject(bucket, "other/"); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected ObjectNotFound, got %v", instanceType, err)
+	}
+	// Metadata directory objects would lose is rejected.
+	for _, metadata := range []map[string]string{
+		{"content-type": "text/plain"},
+		{"X-Amz-Meta-Key": "value"},
+	} {
+		if _, err = obj.PutObject(bucket, "meta/", 0, bytes.NewReader(nil), metadata, ""); !isObjectDirMetadata(err) {
+			t.Fatalf("%s: Expected ObjectDirMetadata for %v, got %v", instanceType, metadata, err)
+		}
+	}
+	metadata := map[string]string{"content-type": fuseDirContentType, "md5Sum": dirObjectMD5Sum}
+	if _, err = obj.PutObject(bucket, "meta/", 0, bytes.NewReader(nil), metadata, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err = obj.DeleteObject(bucket, "meta/"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		prefix, marker, delimiter string
+		maxKeys                   int
+		objects, prefixes         []string
+	}{
+		{"", "", "", 1000, []string{"dir/", "file", "other/sub/"}, nil},
+		{"", "", "/", 1000, []string{"file"}, []string{"dir/", "other/"}},
+		{"dir/", "", "/", 1000, []string{"dir/"}, nil},
+		{"other/", "", "/", 1000, nil, []string{"other/sub/"}},
+		{"other/", "", "", 1000, []string{"other/sub/"}, nil},
+		{"", "", "", 1, []string{"dir/"}, nil},
+		{"", "dir/", "", 1000, []string{"file", "other/sub/"}, nil},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjects(bucket, testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("%s: Test %d: %v", instanceType, i+1, err)
+		}
+		var objects []string
+		for _, objInfo := range result.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		if !reflect.DeepEqual(objects, testCase.objects) {
+			t.Errorf("%s: Test %d: Expected objects %v, got %v", instanceType, i+1, testCase.objects, objects)
+		}
+		if !reflect.DeepEqual(result.Prefixes, testCase.prefixes) {
+			t.Errorf("%s: Test %d: Expected prefixes %v, got %v", instanceType, i+1, testCase.prefixes, result.Prefixes)
+		}
+	}
+
+	// Writing under a directory object hides it.
+	if _, err = obj.PutObject(bucket, "dir/child", 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err = obj.GetObjectInfo(bucket, "dir/"); !isErrObjectNameInvalid(err) {
+		t.Fatalf("%s: Expected ObjectNameInvalid, got %v", instanceType, err)
+	}
+	if err = obj.DeleteObject(bucket, "dir/child"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	if err = obj.DeleteObject(bucket, "other/sub/"); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err = obj.GetObjectInfo(bucket, "other/sub/"); !isErrObjectNameInvalid(err) {
+		t.Fatalf("%s: Expected ObjectNameInvalid, got %v", instanceType, err)
+	}
+	result, err := obj.ListObjects(bucket, "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "file" {
+		t.Fatalf("%s: Expected only file to be left, got %+v", instanceType, result.Objects)
+	}
+}
+
+func isObjectDirMetadata(err error) bool {
+	_, ok := errorCause(err).(ObjectDirMetadata)
+	return ok
+}
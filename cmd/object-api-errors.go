@@ -159,6 +159,17 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Bucket + "#" + e.Object
 }
 
+// VersionNotFound version of an object does not exist.
+type VersionNotFound struct {
+	Bucket    string
+	Object    string
+	VersionID string
+}
+
+func (e VersionNotFound) Error() string {
+	return "Version not found: " + e.Bucket + "#" + e.Object + "#" + e.VersionID
+}
+
 // ObjectExistsAsDirectory object already exists as a directory.
 type ObjectExistsAsDirectory GenericError
 
@@ -234,6 +245,13 @@ func (e BucketNameInvalid) Error() string {
 	return "Bucket name invalid: " + e.Bucket
 }
 
+// BucketReadOnly - bucket is a read-only bucket mount.
+type BucketReadOnly GenericError
+
+func (e BucketReadOnly) Error() string {
+	return "Bucket is read-only: " + e.Bucket
+}
+
 /// Object related errors.
 
 // ObjectNameInvalid - object name provided is invalid.
@@ -244,6 +262,24 @@ func (e ObjectNameInvalid) Error() string {
 	return "Object name invalid: " + e.Bucket + "#" + e.Object
 }
 
+// ObjectNameNotAllowed - object name is not allowed in a bucket in
+// strict names mode.
+type ObjectNameNotAllowed GenericError
+
+// Return string an error formatted as the given text.
+func (e ObjectNameNotAllowed) Error() string {
+	return "Object name not allowed in strict names mode: " + e.Bucket + "#" + e.Object
+}
+
+// ObjectDirMetadata - metadata was given for a directory object, which
+// keeps none.
+type ObjectDirMetadata GenericError
+
+// Return string an error formatted as the given text.
+func (e ObjectDirMetadata) Error() string {
+	return "Directory objects keep no metadata: " + e.Bucket + "#" + e.Object
+}
+
 // IncompleteBody You did not provide the number of bytes specified by the Content-Length HTTP header.
 type IncompleteBody GenericError
 
@@ -361,3 +397,13 @@ func isErrObjectNotFound(err error) bool {
 	}
 	return false
 }
+
+// Check if error type is ObjectNameInvalid.
+func isErrObjectNameInvalid(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case ObjectNameInvalid:
+		return true
+	}
+	return false
+}
@@ -16,7 +16,11 @@
 
 package cmd
 
-import "github.com/skyrings/skyring-common/tools/uuid"
+import (
+	"strings"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
 
 // Checks on GetObject arguments, bucket and object.
 func checkGetObjArgs(bucket, object string) error {
@@ -28,6 +32,18 @@ func checkDelObjArgs(bucket, object string) error {
 	return checkBucketAndObjectNames(bucket, object)
 }
 
+// Checks bucket and directory object name validity, directory objects
+// are named after a valid object name with a trailing slash.
+func checkObjectDirArgs(bucket, object string) error {
+	if !IsValidBucketName(bucket) {
+		return traceError(BucketNameInvalid{Bucket: bucket})
+	}
+	if !hasSuffix(object, slashSeparator) || !IsValidObjectName(strings.TrimSuffix(object, slashSeparator)) {
+		return traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
+	}
+	return nil
+}
+
 // Checks bucket and object name validity, returns nil if both are valid.
 func checkBucketAndObjectNames(bucket, object string) error {
 	// Verify if bucket is valid.
@@ -38,6 +38,14 @@ type ObjectLayer interface {
 	CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (objInfo ObjectInfo, err error)
 	DeleteObject(bucket, object string) error
 
+	// Versioning operations.
+	GetBucketVersioning(bucket string) (status string, err error)
+	SetBucketVersioning(bucket, status string) error
+	GetObjectVersion(bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) (err error)
+	GetObjectVersionInfo(bucket, object, versionID string) (objInfo ObjectInfo, err error)
+	DeleteObjectVersion(bucket, object, versionID string) error
+	ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (result ListObjectVersionsInfo, err error)
+
 	// Multipart operations.
 	ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error)
 	NewMultipartUpload(bucket, object string, metadata map[string]string) (uploadID string, err error)
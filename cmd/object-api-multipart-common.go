@@ -70,6 +70,16 @@ func (u *uploadsV1) RemoveUploadID(uploadID string) {
 	}
 }
 
+// HasUploadID - returns true if uploadID is present in uploads metadata.
+func (u uploadsV1) HasUploadID(uploadID string) bool {
+	for _, upload := range u.Uploads {
+		if upload.UploadID == uploadID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - is true if no more uploads available.
 func (u *uploadsV1) IsEmpty() bool {
 	return len(u.Uploads) == 0
@@ -82,12 +92,8 @@ func (u *uploadsV1) WriteTo(lk *lock.LockedFile) (n int64, err error) {
 	if err != nil {
 		return 0, traceError(err)
 	}
-	if err = lk.Truncate(0); err != nil {
-		return 0, traceError(err)
-	}
-	_, err = lk.Write(uplBytes)
-	if err != nil {
-		return 0, traceError(err)
+	if err = fsWriteJournaled(lk, uplBytes); err != nil {
+		return 0, err
 	}
 	return int64(len(uplBytes)), nil
 }
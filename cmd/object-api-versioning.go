@@ -0,0 +1,906 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+const (
+	// Versioning status of buckets, buckets on which versioning was
+	// never enabled have none.
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+
+	// Versioning status of a bucket, saved under the bucket config prefix.
+	bucketVersioningConfig = "versioning.json"
+
+	// Noncurrent versions of objects are kept in the minio meta bucket
+	// under this prefix. Object names are hex encoded, so that objects
+	// are listed in the order of their names, and split in segments
+	// short enough for any filesystem.
+	objectVersionsPrefix     = "versions"
+	objectVersionsSegmentLen = 200
+
+	// Current version of `versions.json`.
+	objectVersionsVersion = "1.0.0"
+
+	// Metadata key carrying the version id of an object, returned as
+	// response header.
+	versionIDKey = "X-Amz-Version-Id"
+
+	// Version id of objects written while versioning was not enabled.
+	nullVersionID = "null"
+
+	// Metadata key asking the object layer to restore a noncurrent
+	// version as is, removed before saving the metadata.
+	objectRestoreVersionKey = "x-minio-internal-restore-version"
+)
+
+// bucketVersioningV1 - represents `versioning.json`.
+type bucketVersioningV1 struct {
+	Status string `json:"status"`
+}
+
+// objectVersionV1 - a noncurrent version of an object, or a delete marker.
+type objectVersionV1 struct {
+	VersionID       string            `json:"versionId"`
+	DeleteMarker    bool              `json:"deleteMarker,omitempty"`
+	ModTime         time.Time         `json:"modTime"`
+	Size            int64             `json:"size"`
+	MD5Sum          string            `json:"md5Sum,omitempty"`
+	ContentType     string            `json:"contentType,omitempty"`
+	ContentEncoding string            `json:"contentEncoding,omitempty"`
+	UserDefined     map[string]string `json:"userDefined,omitempty"`
+}
+
+// objectVersionsV1 - represents the `versions.json` of an object,
+// listing its noncurrent versions newest first.
+type objectVersionsV1 struct {
+	Version  string            `json:"version"`
+	Versions []objectVersionV1 `json:"versions"`
+}
+
+// newObjectVersion - returns the noncurrent version of objInfo.
+func newObjectVersion(objInfo ObjectInfo) objectVersionV1 {
+	return objectVersionV1{
+		VersionID:       getObjectVersionID(objInfo),
+		ModTime:         objInfo.ModTime,
+		Size:            objInfo.Size,
+		MD5Sum:          objInfo.MD5Sum,
+		ContentType:     objInfo.ContentType,
+		ContentEncoding: objInfo.ContentEncoding,
+		UserDefined:     objInfo.UserDefined,
+	}
+}
+
+// toObjectVersionInfo - returns the version info of a noncurrent version.
+func (v objectVersionV1) toObjectVersionInfo(bucket, object string) ObjectVersionInfo {
+	return ObjectVersionInfo{
+		ObjectInfo: ObjectInfo{
+			Bucket:          bucket,
+			Name:            object,
+			ModTime:         v.ModTime,
+			Size:            v.Size,
+			MD5Sum:          v.MD5Sum,
+			ContentType:     v.ContentType,
+			ContentEncoding: v.ContentEncoding,
+			UserDefined:     v.UserDefined,
+		},
+		VersionID:    v.VersionID,
+		DeleteMarker: v.DeleteMarker,
+	}
+}
+
+// find - returns the index of versionID, -1 if not found.
+func (m objectVersionsV1) find(versionID string) int {
+	for i, version := range m.Versions {
+		if version.VersionID == versionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// without - returns the versions except the ones with versionID.
+func (m objectVersionsV1) without(versionID string) objectVersionsV1 {
+	versions := objectVersionsV1{Version: m.Version}
+	for _, version := range m.Versions {
+		if version.VersionID != versionID {
+			versions.Versions = append(versions.Versions, version)
+		}
+	}
+	return versions
+}
+
+// getObjectVersionID - returns the version id of an object.
+func getObjectVersionID(objInfo ObjectInfo) string {
+	if versionID := objInfo.UserDefined[versionIDKey]; versionID != "" {
+		return versionID
+	}
+	return nullVersionID
+}
+
+// isValidVersionID - returns true if versionID may name a version.
+func isValidVersionID(versionID string) bool {
+	if versionID == nullVersionID {
+		return true
+	}
+	_, err := uuid.Parse(versionID)
+	return err == nil
+}
+
+// hexObjectPath - returns the hex encoded name split in segments.
+func hexObjectPath(name string) string {
+	encoded := hex.EncodeToString([]byte(name))
+	var segments []string
+	for len(encoded) > objectVersionsSegmentLen {
+		segments = append(segments, encoded[:objectVersionsSegmentLen])
+		encoded = encoded[objectVersionsSegmentLen:]
+	}
+	return strings.Join(append(segments, encoded), slashSeparator)
+}
+
+// getObjectVersionsDir - returns the path under which the noncurrent
+// versions of all objects of bucket are kept.
+func getObjectVersionsDir(bucket string) string {
+	return pathJoin(objectVersionsPrefix, bucket) + slashSeparator
+}
+
+// getObjectVersionsPath - returns the path of `versions.json` of object,
+// sorting before the paths of objects whose names it prefixes.
+func getObjectVersionsPath(bucket, object string) string {
+	return getObjectVersionsDir(bucket) + hexObjectPath(object) + ".json"
+}
+
+// getObjectVersionPath - returns the path of the data of a noncurrent
+// version of object.
+func getObjectVersionPath(bucket, object, versionID string) string {
+	return pathJoin(getObjectVersionsDir(bucket), hexObjectPath(object), versionID)
+}
+
+// getObjectVersionsName - returns the name of the object whose
+// `versions.json` is at versionsPath, false for version data.
+func getObjectVersionsName(bucket, versionsPath string) (string, bool) {
+	if !hasSuffix(versionsPath, ".json") {
+		return "", false
+	}
+	encoded := strings.TrimPrefix(strings.TrimSuffix(versionsPath, ".json"), getObjectVersionsDir(bucket))
+	name, err := hex.DecodeString(strings.Replace(encoded, slashSeparator, "", -1))
+	if err != nil {
+		return "", false
+	}
+	return string(name), true
+}
+
+// readBucketVersioning - returns the versioning status of bucket.
+func readBucketVersioning(obj ObjectLayer, bucket string) (string, error) {
+	var buffer bytes.Buffer
+	configPath := pathJoin(bucketConfigPrefix, bucket, bucketVersioningConfig)
+	if err := obj.GetObject(minioMetaBucket, configPath, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var config bucketVersioningV1
+	if err := json.Unmarshal(buffer.Bytes(), &config); err != nil {
+		return "", traceError(err)
+	}
+	return config.Status, nil
+}
+
+// Variable represents the versioning status of buckets in memory.
+var globalBucketVersioning *bucketVersioningStatuses
+
+// Versioning status of all buckets, looked up by every write to a
+// bucket instead of reading `versioning.json`.
+type bucketVersioningStatuses struct {
+	rwMutex *sync.RWMutex
+
+	// Versioning status of each bucket, buckets on which versioning
+	// was never enabled are not listed.
+	statuses map[string]string
+}
+
+// GetBucketVersioning - returns the versioning status of bucket.
+func (bv bucketVersioningStatuses) GetBucketVersioning(bucket string) string {
+	bv.rwMutex.RLock()
+	defer bv.rwMutex.RUnlock()
+	return bv.statuses[bucket]
+}
+
+// SetBucketVersioning - sets the versioning status of bucket, an empty
+// status removes it.
+func (bv *bucketVersioningStatuses) SetBucketVersioning(bucket, status string) {
+	bv.rwMutex.Lock()
+	defer bv.rwMutex.Unlock()
+	if status == "" {
+		delete(bv.statuses, bucket)
+	} else {
+		bv.statuses[bucket] = status
+	}
+}
+
+// initBucketVersioning - loads the versioning status of all buckets.
+func initBucketVersioning(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		return errorCause(err)
+	}
+	statuses := make(map[string]string)
+	for _, bucket := range buckets {
+		status, err := readBucketVersioning(objAPI, bucket.Name)
+		if err != nil {
+			return errorCause(err)
+		}
+		if status != "" {
+			statuses[bucket.Name] = status
+		}
+	}
+	globalBucketVersioning = &bucketVersioningStatuses{
+		rwMutex:  &sync.RWMutex{},
+		statuses: statuses,
+	}
+	return nil
+}
+
+// updateBucketVersioning - updates the versioning status of bucket in
+// memory on this server, also when it runs without peers, and on all
+// peers.
+func updateBucketVersioning(bucket, status string) {
+	if globalBucketVersioning != nil {
+		globalBucketVersioning.SetBucketVersioning(bucket, status)
+	}
+	S3PeersUpdateBucketVersioning(bucket, status)
+}
+
+// readWriteBucketVersioning - returns the versioning status of bucket
+// for a write to it, from memory once loaded. Writes which cannot read
+// the status for lack of quorum fail the way the write itself would.
+func readWriteBucketVersioning(obj ObjectLayer, bucket string) (string, error) {
+	if globalBucketVersioning != nil {
+		return globalBucketVersioning.GetBucketVersioning(bucket), nil
+	}
+	status, err := readBucketVersioning(obj, bucket)
+	if _, ok := errorCause(err).(InsufficientReadQuorum); ok {
+		return "", traceError(InsufficientWriteQuorum{})
+	}
+	return status, err
+}
+
+// getBucketVersioning - returns the versioning status of bucket, empty
+// if versioning was never enabled on it.
+func getBucketVersioning(obj ObjectLayer, bucket string) (string, error) {
+	if _, err := obj.GetBucketInfo(bucket); err != nil {
+		return "", err
+	}
+
+	configPath := pathJoin(bucketConfigPrefix, bucket, bucketVersioningConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	return readBucketVersioning(obj, bucket)
+}
+
+// setBucketVersioning - enables or suspends versioning on bucket.
+func setBucketVersioning(obj ObjectLayer, bucket, status string) error {
+	if status != versioningEnabled && status != versioningSuspended {
+		return traceError(NotImplemented{})
+	}
+	if _, err := obj.GetBucketInfo(bucket); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(bucketVersioningV1{Status: status})
+	if err != nil {
+		return traceError(err)
+	}
+
+	configPath := pathJoin(bucketConfigPrefix, bucket, bucketVersioningConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	if _, err = obj.PutObject(minioMetaBucket, configPath, int64(len(buf)), bytes.NewReader(buf), nil, ""); err != nil {
+		return err
+	}
+	updateBucketVersioning(bucket, status)
+	return nil
+}
+
+// removeBucketVersioning - removes the versioning status of a bucket.
+func removeBucketVersioning(bucket string, obj ObjectLayer) error {
+	configPath := pathJoin(bucketConfigPrefix, bucket, bucketVersioningConfig)
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	if err := obj.DeleteObject(minioMetaBucket, configPath); err != nil && !isErrObjectNotFound(err) {
+		return err
+	}
+	updateBucketVersioning(bucket, "")
+	return nil
+}
+
+// readObjectVersions - reads the noncurrent versions of object.
+func readObjectVersions(obj ObjectLayer, bucket, object string) (objectVersionsV1, error) {
+	versions := objectVersionsV1{Version: objectVersionsVersion}
+	var buffer bytes.Buffer
+	if err := obj.GetObject(minioMetaBucket, getObjectVersionsPath(bucket, object), 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return versions, nil
+		}
+		return versions, err
+	}
+	if err := json.Unmarshal(buffer.Bytes(), &versions); err != nil {
+		return versions, traceError(err)
+	}
+	return versions, nil
+}
+
+// writeObjectVersions - saves the noncurrent versions of object, removes
+// `versions.json` once there are none.
+func writeObjectVersions(obj ObjectLayer, bucket, object string, versions objectVersionsV1) error {
+	versionsPath := getObjectVersionsPath(bucket, object)
+	if len(versions.Versions) == 0 {
+		if err := obj.DeleteObject(minioMetaBucket, versionsPath); err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	buf, err := json.Marshal(versions)
+	if err != nil {
+		return traceError(err)
+	}
+	_, err = obj.PutObject(minioMetaBucket, versionsPath, int64(len(buf)), bytes.NewReader(buf), nil, "")
+	return err
+}
+
+// deleteObjectVersionData - removes the data of a noncurrent version,
+// errors are only logged as the version is no longer listed.
+func deleteObjectVersionData(obj ObjectLayer, bucket, object string, version objectVersionV1) {
+	if version.DeleteMarker {
+		return
+	}
+	versionPath := getObjectVersionPath(bucket, object, version.VersionID)
+	if err := obj.DeleteObject(minioMetaBucket, versionPath); err != nil && !isErrObjectNotFound(err) {
+		errorIf(err, "Unable to remove version %s of %s/%s.", version.VersionID, bucket, object)
+	}
+}
+
+// objectDataLinker - implemented by object layers which can share the
+// data of an object with another object without copying it.
+type objectDataLinker interface {
+	linkObjectData(srcBucket, srcObject, dstBucket, dstObject string) error
+}
+
+// objectDataMover - implemented by object layers which can move the
+// data of an object to another object without copying it.
+type objectDataMover interface {
+	moveObjectData(srcBucket, srcObject, dstBucket, dstObject string) error
+}
+
+// archiveObjectData - makes the data of the current version of object
+// the data of the noncurrent version at versionPath. The data is linked
+// or, if the current version loses its data anyway, moved where the
+// object layer supports it, else copied. Returns a function undoing the
+// archival.
+func archiveObjectData(obj ObjectLayer, bucket, object, versionPath string, replaced bool) (undo func(), err error) {
+	removeCopy := func() {
+		if err := obj.DeleteObject(minioMetaBucket, versionPath); err != nil && !isErrObjectNotFound(err) {
+			errorIf(err, "Unable to remove %s.", versionPath)
+		}
+	}
+	if linker, ok := obj.(objectDataLinker); ok {
+		// Filesystems without hard links fall back to copying.
+		if err = linker.linkObjectData(bucket, object, minioMetaBucket, versionPath); err == nil {
+			return removeCopy, nil
+		}
+	}
+	if mover, ok := obj.(objectDataMover); ok && replaced {
+		if err = mover.moveObjectData(bucket, object, minioMetaBucket, versionPath); err != nil {
+			return nil, err
+		}
+		return func() {
+			err := mover.moveObjectData(minioMetaBucket, versionPath, bucket, object)
+			errorIf(err, "Unable to move back the data of %s/%s.", bucket, object)
+		}, nil
+	}
+	if _, err = obj.CopyObject(bucket, object, minioMetaBucket, versionPath, nil); err != nil {
+		return nil, err
+	}
+	return removeCopy, nil
+}
+
+// archiveObjectVersion - keeps the current version of object as a
+// noncurrent one, followed by a delete marker if deleteMarker is set.
+// replaced is set if the data of the current version is replaced or
+// deleted. While versioning is suspended the current version is
+// replaced if it is the null version, as is a noncurrent null version.
+// Returns a function to call once the current version was replaced,
+// or not after all, which completes or undoes the archival.
+func archiveObjectVersion(obj ObjectLayer, bucket, object, status string, deleteMarker, replaced bool) (done func(committed bool), err error) {
+	done = func(bool) {}
+
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return done, nil
+		}
+		return done, err
+	}
+	previous, err := readObjectVersions(obj, bucket, object)
+	if err != nil {
+		return done, err
+	}
+	// The null version is replaced by the new current version, its
+	// data is kept until then.
+	versions := previous
+	var replacedNull []objectVersionV1
+	if status == versioningSuspended {
+		if i := versions.find(nullVersionID); i >= 0 {
+			replacedNull = append(replacedNull, versions.Versions[i])
+			versions = versions.without(nullVersionID)
+		}
+	}
+
+	archived := objectVersionsV1{Version: objectVersionsVersion}
+	if deleteMarker {
+		markerID := nullVersionID
+		if status == versioningEnabled {
+			markerID = mustGetUUID()
+		}
+		archived.Versions = append(archived.Versions, objectVersionV1{
+			VersionID:    markerID,
+			DeleteMarker: true,
+			ModTime:      time.Now().UTC(),
+		})
+	}
+
+	version := newObjectVersion(objInfo)
+	keep := status == versioningEnabled || version.VersionID != nullVersionID
+	undoData := func() {}
+	if keep {
+		versionPath := getObjectVersionPath(bucket, object, version.VersionID)
+		if undoData, err = archiveObjectData(obj, bucket, object, versionPath, replaced); err != nil {
+			return done, err
+		}
+		archived.Versions = append(archived.Versions, version)
+	}
+	archived.Versions = append(archived.Versions, versions.Versions...)
+
+	if err = writeObjectVersions(obj, bucket, object, archived); err != nil {
+		undoData()
+		return done, err
+	}
+	return func(committed bool) {
+		if !committed {
+			if uerr := writeObjectVersions(obj, bucket, object, previous); uerr != nil {
+				errorIf(uerr, "Unable to restore versions of %s/%s.", bucket, object)
+			}
+			undoData()
+			return
+		}
+		for _, version := range replacedNull {
+			deleteObjectVersionData(obj, bucket, object, version)
+		}
+	}, nil
+}
+
+// preserveObjectVersion - archives the current version of object before
+// it is replaced by a new one with the given metadata, and sets the
+// version id of the new one, if versioning is enabled or suspended on
+// bucket. Returns a function to call once the new version was written,
+// or not after all.
+func preserveObjectVersion(obj ObjectLayer, bucket, object string, metadata map[string]string) (done func(committed bool), err error) {
+	return preserveVersion(obj, bucket, object, metadata, true)
+}
+
+// preserveObjectMetadataVersion - archives the current version of
+// object before its metadata is replaced, see preserveObjectVersion.
+// The current version keeps its data.
+func preserveObjectMetadataVersion(obj ObjectLayer, bucket, object string, metadata map[string]string) (done func(committed bool), err error) {
+	return preserveVersion(obj, bucket, object, metadata, false)
+}
+
+// preserveVersion - common function that preserveObjectVersion and
+// preserveObjectMetadataVersion use.
+func preserveVersion(obj ObjectLayer, bucket, object string, metadata map[string]string, replaced bool) (done func(committed bool), err error) {
+	done = func(bool) {}
+	if isMinioMetaBucketName(bucket) {
+		return done, nil
+	}
+	// Restored versions keep their version id.
+	if _, ok := metadata[objectRestoreVersionKey]; ok {
+		delete(metadata, objectRestoreVersionKey)
+		return done, nil
+	}
+	delete(metadata, versionIDKey)
+
+	status, err := readWriteBucketVersioning(obj, bucket)
+	if err != nil || status == "" {
+		return done, err
+	}
+	if status == versioningEnabled {
+		metadata[versionIDKey] = mustGetUUID()
+	}
+	return archiveObjectVersion(obj, bucket, object, status, false, replaced)
+}
+
+// preserveDeletedObject - archives the current version of object before
+// it is deleted and adds a delete marker, if versioning is enabled or
+// suspended on bucket. Returns a function to call once the object was
+// deleted, or not after all.
+func preserveDeletedObject(obj ObjectLayer, bucket, object string) (done func(committed bool), err error) {
+	done = func(bool) {}
+	if isMinioMetaBucketName(bucket) {
+		return done, nil
+	}
+	status, err := readWriteBucketVersioning(obj, bucket)
+	if err != nil || status == "" {
+		return done, err
+	}
+	return archiveObjectVersion(obj, bucket, object, status, true, true)
+}
+
+// restoreLatestVersion - makes the latest noncurrent version of object
+// the current one, unless it is a delete marker.
+func restoreLatestVersion(obj ObjectLayer, bucket, object string, versions objectVersionsV1) error {
+	if len(versions.Versions) == 0 || versions.Versions[0].DeleteMarker {
+		return nil
+	}
+	latest := versions.Versions[0]
+
+	metadata := make(map[string]string)
+	for k, v := range latest.UserDefined {
+		metadata[k] = v
+	}
+	// The ETag of multipart objects is not the md5sum of their data.
+	delete(metadata, "md5Sum")
+	delete(metadata, versionIDKey)
+	if latest.VersionID != nullVersionID {
+		metadata[versionIDKey] = latest.VersionID
+	}
+	metadata[objectRestoreVersionKey] = latest.VersionID
+	metadata[objectModTimeKey] = latest.ModTime.Format(time.RFC3339Nano)
+
+	versionPath := getObjectVersionPath(bucket, object, latest.VersionID)
+	if _, err := obj.CopyObject(minioMetaBucket, versionPath, bucket, object, metadata); err != nil {
+		return err
+	}
+	if err := writeObjectVersions(obj, bucket, object, versions.without(latest.VersionID)); err != nil {
+		return err
+	}
+	deleteObjectVersionData(obj, bucket, object, latest)
+	return nil
+}
+
+// getObjectVersions - returns all versions of object, newest first.
+func getObjectVersions(obj ObjectLayer, bucket, object string) ([]ObjectVersionInfo, error) {
+	var versions []ObjectVersionInfo
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err == nil {
+		versions = append(versions, ObjectVersionInfo{
+			ObjectInfo: objInfo,
+			VersionID:  getObjectVersionID(objInfo),
+		})
+	} else if !isErrObjectNotFound(err) {
+		return nil, err
+	}
+	archived, err := readObjectVersions(obj, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range archived.Versions {
+		versions = append(versions, version.toObjectVersionInfo(bucket, object))
+	}
+	if len(versions) > 0 {
+		versions[0].IsLatest = true
+	}
+	return versions, nil
+}
+
+// getObjectVersionInfo - returns info of a version of object, delete
+// markers are not found.
+func getObjectVersionInfo(obj ObjectLayer, bucket, object, versionID string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err == nil && getObjectVersionID(objInfo) == versionID {
+		return objInfo, nil
+	}
+	if err != nil && !isErrObjectNotFound(err) {
+		return ObjectInfo{}, err
+	}
+	versions, err := readObjectVersions(obj, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	i := versions.find(versionID)
+	if i == -1 || versions.Versions[i].DeleteMarker {
+		return ObjectInfo{}, traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	return versions.Versions[i].toObjectVersionInfo(bucket, object).ObjectInfo, nil
+}
+
+// getObjectVersion - reads a version of object.
+func getObjectVersion(obj ObjectLayer, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	objInfo, err := getObjectVersionInfo(obj, bucket, object, versionID)
+	if err != nil {
+		return err
+	}
+	if currentInfo, cerr := obj.GetObjectInfo(bucket, object); cerr == nil && getObjectVersionID(currentInfo) == versionID {
+		return obj.GetObject(bucket, object, startOffset, length, writer)
+	}
+	if length < 0 {
+		length = objInfo.Size - startOffset
+	}
+	return obj.GetObject(minioMetaBucket, getObjectVersionPath(bucket, object, versionID), startOffset, length, writer)
+}
+
+// deleteObjectVersion - permanently deletes a version of object with
+// deleteObject, which deletes the current version without archiving
+// it. Once the latest version is deleted the previous one, if not a
+// delete marker, becomes the current version.
+func deleteObjectVersion(obj ObjectLayer, bucket, object, versionID string, deleteObject func(bucket, object string) error) error {
+	if err := checkDelObjArgs(bucket, object); err != nil {
+		return err
+	}
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err != nil && !isErrObjectNotFound(err) {
+		return err
+	}
+	current := err == nil
+	versions, err := readObjectVersions(obj, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	if current && getObjectVersionID(objInfo) == versionID {
+		if err = deleteObject(bucket, object); err != nil {
+			return err
+		}
+		return restoreLatestVersion(obj, bucket, object, versions)
+	}
+
+	i := versions.find(versionID)
+	if i == -1 {
+		return traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	removed := versions.Versions[i]
+	versions = versions.without(versionID)
+	if err = writeObjectVersions(obj, bucket, object, versions); err != nil {
+		return err
+	}
+	deleteObjectVersionData(obj, bucket, object, removed)
+
+	// Deleting the delete marker of a deleted object restores it.
+	if !current && i == 0 {
+		return restoreLatestVersion(obj, bucket, object, versions)
+	}
+	return nil
+}
+
+// checkBucketVersionsEmpty - returns BucketNotEmpty if noncurrent
+// versions of objects of bucket are kept.
+func checkBucketVersionsEmpty(obj ObjectLayer, bucket string) error {
+	result, err := obj.ListObjects(minioMetaBucket, getObjectVersionsDir(bucket), "", "", 1)
+	if err != nil {
+		return err
+	}
+	if len(result.Objects) > 0 {
+		return traceError(BucketNotEmpty{Bucket: bucket})
+	}
+	return nil
+}
+
+// purgeObjectVersions - removes all noncurrent versions of objects of bucket.
+func purgeObjectVersions(obj ObjectLayer, bucket string) error {
+	marker := ""
+	for {
+		// Listing continues after the last listed version, versions
+		// already gone are never listed again.
+		result, err := obj.ListObjects(minioMetaBucket, getObjectVersionsDir(bucket), marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			marker = objInfo.Name
+			if err = obj.DeleteObject(minioMetaBucket, objInfo.Name); err != nil && !isErrObjectNotFound(err) {
+				return err
+			}
+		}
+		if !result.IsTruncated || len(result.Objects) == 0 {
+			return nil
+		}
+	}
+}
+
+// objectKeyLister - pages through the names of objects listed
+// recursively, in lexical order.
+type objectKeyLister struct {
+	list   func(marker string) (ListObjectsInfo, error)
+	name   func(entry string) (string, bool)
+	marker string
+	keys   []string
+	done   bool
+}
+
+// peek - returns the next name without consuming it, empty once all
+// names were listed.
+func (l *objectKeyLister) peek() (string, error) {
+	for len(l.keys) == 0 && !l.done {
+		result, err := l.list(l.marker)
+		if err != nil {
+			return "", err
+		}
+		for _, objInfo := range result.Objects {
+			if key, ok := l.name(objInfo.Name); ok {
+				l.keys = append(l.keys, key)
+			}
+		}
+		if len(result.Objects) > 0 {
+			l.marker = result.Objects[len(result.Objects)-1].Name
+		}
+		l.done = !result.IsTruncated || len(result.Objects) == 0
+	}
+	if len(l.keys) == 0 {
+		return "", nil
+	}
+	return l.keys[0], nil
+}
+
+// pop - consumes the next name.
+func (l *objectKeyLister) pop() {
+	l.keys = l.keys[1:]
+}
+
+// listObjectVersions - lists the versions of objects of bucket under
+// prefix, starting after the version versionIDMarker of keyMarker, or
+// after all versions of keyMarker if versionIDMarker is empty. Versions
+// of an object are listed newest first.
+func listObjectVersions(obj ObjectLayer, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (result ListObjectVersionsInfo, err error) {
+	if err = checkListObjsArgs(bucket, prefix, keyMarker, delimiter, obj); err != nil {
+		return result, err
+	}
+	if maxKeys == 0 {
+		return result, nil
+	}
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	// add - adds a version or a common prefix, returns false once the
+	// list is full.
+	add := func(version *ObjectVersionInfo, commonPrefix string) bool {
+		if len(result.Versions)+len(result.Prefixes) == maxKeys {
+			result.IsTruncated = true
+			return false
+		}
+		if version != nil {
+			result.Versions = append(result.Versions, *version)
+			result.NextKeyMarker, result.NextVersionIDMarker = version.Name, version.VersionID
+		} else {
+			result.Prefixes = append(result.Prefixes, commonPrefix)
+			result.NextKeyMarker, result.NextVersionIDMarker = commonPrefix, ""
+		}
+		return true
+	}
+
+	// The remaining versions of the key marker come first.
+	if keyMarker != "" && versionIDMarker != "" {
+		var versions []ObjectVersionInfo
+		if versions, err = getObjectVersions(obj, bucket, keyMarker); err != nil {
+			return result, err
+		}
+		found := false
+		for i := range versions {
+			if found && !add(&versions[i], "") {
+				return result, nil
+			}
+			found = found || versions[i].VersionID == versionIDMarker
+		}
+	}
+
+	// Objects with a current version and objects with noncurrent ones
+	// only are listed side by side.
+	currentMarker, versionsMarker := keyMarker, ""
+	if keyMarker != "" {
+		versionsMarker = getObjectVersionsPath(bucket, keyMarker)
+	}
+	current := &objectKeyLister{
+		list: func(marker string) (ListObjectsInfo, error) {
+			return obj.ListObjects(bucket, prefix, marker, "", maxObjectList)
+		},
+		name:   func(entry string) (string, bool) { return entry, true },
+		marker: currentMarker,
+	}
+	archived := &objectKeyLister{
+		list: func(marker string) (ListObjectsInfo, error) {
+			return obj.ListObjects(minioMetaBucket, getObjectVersionsDir(bucket)+hexObjectPath(prefix), marker, "", maxObjectList)
+		},
+		name:   func(entry string) (string, bool) { return getObjectVersionsName(bucket, entry) },
+		marker: versionsMarker,
+	}
+
+	lastPrefix := ""
+	for {
+		var currentKey, archivedKey string
+		if currentKey, err = current.peek(); err != nil {
+			return result, err
+		}
+		if archivedKey, err = archived.peek(); err != nil {
+			return result, err
+		}
+		key := currentKey
+		if key == "" || (archivedKey != "" && archivedKey < key) {
+			key = archivedKey
+		}
+		if key == "" {
+			break
+		}
+		if currentKey == key {
+			current.pop()
+		}
+		if archivedKey == key {
+			archived.pop()
+		}
+		if !hasPrefix(key, prefix) || key <= keyMarker {
+			continue
+		}
+
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				commonPrefix := key[:len(prefix)+i+len(delimiter)]
+				if commonPrefix == lastPrefix || commonPrefix <= keyMarker {
+					continue
+				}
+				if !add(nil, commonPrefix) {
+					return result, nil
+				}
+				lastPrefix = commonPrefix
+				continue
+			}
+		}
+
+		var versions []ObjectVersionInfo
+		if versions, err = getObjectVersions(obj, bucket, key); err != nil {
+			return result, err
+		}
+		for i := range versions {
+			if !add(&versions[i], "") {
+				return result, nil
+			}
+		}
+	}
+
+	result.NextKeyMarker, result.NextVersionIDMarker = "", ""
+	return result, nil
+}
@@ -0,0 +1,297 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Wrapper for calling object versioning tests for both XL multiple disks and single node setup.
+func TestObjectVersioning(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectVersioning)
+}
+
+// testObjectVersioning - tests overwrites, deletes and restores of
+// objects in a bucket with versioning enabled.
+func testObjectVersioning(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket, object := "bucket", "object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status, err := obj.GetBucketVersioning(bucket); err != nil || status != "" {
+		t.Fatalf("%s: Expected versioning to be disabled, got %q, %v", instanceType, status, err)
+	}
+	if err := obj.SetBucketVersioning(bucket, "Disabled"); err == nil {
+		t.Fatalf("%s: Expected invalid versioning status to be rejected", instanceType)
+	}
+	if err := obj.SetBucketVersioning(bucket, versioningEnabled); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status, err := obj.GetBucketVersioning(bucket); err != nil || status != versioningEnabled {
+		t.Fatalf("%s: Expected versioning to be enabled, got %q, %v", instanceType, status, err)
+	}
+
+	putObject := func(data string) string {
+		objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader([]byte(data)), nil, "")
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		versionID := objInfo.UserDefined[versionIDKey]
+		if !isValidVersionID(versionID) || versionID == nullVersionID {
+			t.Fatalf("%s: Expected a version id, got %q", instanceType, versionID)
+		}
+		return versionID
+	}
+	readVersion := func(versionID string) string {
+		var buffer bytes.Buffer
+		if err := obj.GetObjectVersion(bucket, object, versionID, 0, -1, &buffer); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return buffer.String()
+	}
+	listVersions := func() []ObjectVersionInfo {
+		result, err := obj.ListObjectVersions(bucket, "", "", "", "", 1000)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return result.Versions
+	}
+
+	// Overwrites keep the previous version.
+	v1 := putObject("a")
+	v2 := putObject("bb")
+	if data := readVersion(v1); data != "a" {
+		t.Fatalf("%s: Expected first version to read %q, got %q", instanceType, "a", data)
+	}
+	if data := readVersion(v2); data != "bb" {
+		t.Fatalf("%s: Expected second version to read %q, got %q", instanceType, "bb", data)
+	}
+	versions := listVersions()
+	if len(versions) != 2 || versions[0].VersionID != v2 || !versions[0].IsLatest || versions[1].VersionID != v1 || versions[1].IsLatest {
+		t.Fatalf("%s: Unexpected versions %+v", instanceType, versions)
+	}
+
+	// Deletes add a delete marker.
+	if err := obj.DeleteObject(bucket, object); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err := obj.GetObjectInfo(bucket, object); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected deleted object not to be found, got %v", instanceType, err)
+	}
+	versions = listVersions()
+	if len(versions) != 3 || !versions[0].DeleteMarker || !versions[0].IsLatest {
+		t.Fatalf("%s: Expected a delete marker, got %+v", instanceType, versions)
+	}
+	if _, err := obj.GetObjectVersionInfo(bucket, object, versions[0].VersionID); err == nil {
+		t.Fatalf("%s: Expected delete marker not to be found", instanceType)
+	}
+
+	// Deleting the delete marker restores the object.
+	if err := obj.DeleteObjectVersion(bucket, object, versions[0].VersionID); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err != nil || getObjectVersionID(objInfo) != v2 || objInfo.Size != 2 {
+		t.Fatalf("%s: Expected second version to be restored, got %+v, %v", instanceType, objInfo, err)
+	}
+
+	// Deleting the current version restores the previous one.
+	if err = obj.DeleteObjectVersion(bucket, object, v2); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	objInfo, err = obj.GetObjectInfo(bucket, object)
+	if err != nil || getObjectVersionID(objInfo) != v1 || objInfo.Size != 1 {
+		t.Fatalf("%s: Expected first version to be restored, got %+v, %v", instanceType, objInfo, err)
+	}
+	if err = obj.DeleteObjectVersion(bucket, object, v2); err == nil {
+		t.Fatalf("%s: Expected deleted version not to be found", instanceType)
+	}
+
+	// Buckets are empty once all versions are deleted.
+	if err = obj.DeleteObject(bucket, object); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err = obj.DeleteBucket(bucket); err == nil {
+		t.Fatalf("%s: Expected bucket with versions not to be deleted", instanceType)
+	}
+	for _, version := range listVersions() {
+		if err = obj.DeleteObjectVersion(bucket, object, version.VersionID); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	if versions = listVersions(); len(versions) != 0 {
+		t.Fatalf("%s: Expected no versions, got %+v", instanceType, versions)
+	}
+	if err = obj.DeleteBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+}
+
+// Wrapper for calling suspended versioning tests for both XL multiple disks and single node setup.
+func TestObjectVersioningSuspended(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectVersioningSuspended)
+}
+
+// testObjectVersioningSuspended - tests that writes replace the null
+// version once versioning is suspended, keeping the other versions.
+func testObjectVersioningSuspended(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	putObject := func(object, data string) ObjectInfo {
+		objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader([]byte(data)), nil, "")
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return objInfo
+	}
+
+	// Objects written before versioning was enabled are the null version.
+	putObject("dir/a", "1")
+	putObject("b", "1")
+	if err := obj.SetBucketVersioning(bucket, versioningEnabled); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	v1 := getObjectVersionID(putObject("dir/a", "22"))
+	if err := obj.SetBucketVersioning(bucket, versioningSuspended); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo := putObject("dir/a", "333"); getObjectVersionID(objInfo) != nullVersionID {
+		t.Fatalf("%s: Expected the null version, got %q", instanceType, getObjectVersionID(objInfo))
+	}
+	putObject("dir/a", "4444")
+
+	result, err := obj.ListObjectVersions(bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	var got []string
+	for _, version := range result.Versions {
+		got = append(got, version.Name+"#"+version.VersionID)
+	}
+	// The null version written before versioning was enabled is replaced.
+	want := []string{"b#null", "dir/a#null", "dir/a#" + v1}
+	if len(got) != len(want) {
+		t.Fatalf("%s: Expected versions %v, got %v", instanceType, want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: Expected versions %v, got %v", instanceType, want, got)
+		}
+	}
+
+	// Listings are paged by key and version, and fold common prefixes.
+	result, err = obj.ListObjectVersions(bucket, "", "", "", "", 2)
+	if err != nil || !result.IsTruncated || result.NextKeyMarker != "dir/a" || result.NextVersionIDMarker != nullVersionID {
+		t.Fatalf("%s: Unexpected first page %+v, %v", instanceType, result, err)
+	}
+	result, err = obj.ListObjectVersions(bucket, "", result.NextKeyMarker, result.NextVersionIDMarker, "", 2)
+	if err != nil || result.IsTruncated || len(result.Versions) != 1 || result.Versions[0].VersionID != v1 {
+		t.Fatalf("%s: Unexpected second page %+v, %v", instanceType, result, err)
+	}
+	result, err = obj.ListObjectVersions(bucket, "", "", "", slashSeparator, 1000)
+	if err != nil || len(result.Versions) != 1 || len(result.Prefixes) != 1 || result.Prefixes[0] != "dir/" {
+		t.Fatalf("%s: Unexpected delimited listing %+v, %v", instanceType, result, err)
+	}
+
+	if err = purgeObjectVersions(obj, bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if err = checkBucketVersionsEmpty(obj, bucket); err != nil {
+		t.Fatalf("%s: Expected versions to be purged, got %v", instanceType, err)
+	}
+}
+
+// Wrapper for calling object versioning undo tests for both XL multiple disks and single node setup.
+func TestObjectVersioningUndo(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectVersioningUndo)
+}
+
+// testObjectVersioningUndo - tests that an archival undone restores the
+// current version and the noncurrent null version with their data, and
+// that the data of the null version is only removed once replaced.
+func testObjectVersioningUndo(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket, object := "bucket", "object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	putObject := func(data string) ObjectInfo {
+		objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader([]byte(data)), nil, "")
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return objInfo
+	}
+	getVersion := func(versionID string) string {
+		var buffer bytes.Buffer
+		if err := obj.GetObjectVersion(bucket, object, versionID, 0, -1, &buffer); err != nil {
+			t.Fatalf("%s: Unable to read version %s, %v", instanceType, versionID, err)
+		}
+		return buffer.String()
+	}
+
+	putObject("1")
+	if err := obj.SetBucketVersioning(bucket, versioningEnabled); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status := globalBucketVersioning.GetBucketVersioning(bucket); status != versioningEnabled {
+		t.Fatalf("%s: Expected the cached status %q, got %q", instanceType, versioningEnabled, status)
+	}
+	v1 := getObjectVersionID(putObject("22"))
+	if err := obj.SetBucketVersioning(bucket, versioningSuspended); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	// The write of a new version fails after the archival.
+	versionDone, err := preserveObjectVersion(obj, bucket, object, make(map[string]string))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	versionDone(false)
+	if data := getVersion(v1); data != "22" {
+		t.Fatalf("%s: Expected the current version to be restored, got %q", instanceType, data)
+	}
+	if data := getVersion(nullVersionID); data != "1" {
+		t.Fatalf("%s: Expected the null version to be restored, got %q", instanceType, data)
+	}
+	versions, err := obj.ListObjectVersions(bucket, "", "", "", "", 1000)
+	if err != nil || len(versions.Versions) != 2 {
+		t.Fatalf("%s: Expected 2 versions, got %+v, %v", instanceType, versions, err)
+	}
+
+	// The null version is replaced along with its data.
+	putObject("333")
+	if data := getVersion(nullVersionID); data != "333" {
+		t.Fatalf("%s: Expected the new null version, got %q", instanceType, data)
+	}
+	if data := getVersion(v1); data != "22" {
+		t.Fatalf("%s: Expected the archived version, got %q", instanceType, data)
+	}
+	nullPath := getObjectVersionPath(bucket, object, nullVersionID)
+	if _, err = obj.GetObjectInfo(minioMetaBucket, nullPath); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected the data of the replaced null version to be removed, got %v", instanceType, err)
+	}
+
+	if err = removeBucketVersioning(bucket, obj); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status := globalBucketVersioning.GetBucketVersioning(bucket); status != "" {
+		t.Fatalf("%s: Expected the cached status to be removed, got %q", instanceType, status)
+	}
+}
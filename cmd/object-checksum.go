@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client supplied checksum headers, verified by the server while the
+// object data is being written and persisted as object metadata.
+const (
+	amzChecksumSHA256 = "X-Amz-Checksum-Sha256"
+	amzChecksumCRC32C = "X-Amz-Checksum-Crc32c"
+)
+
+// Object attributes which may be requested via GetObjectAttributes.
+const (
+	objectAttributeETag         = "ETag"
+	objectAttributeChecksum     = "Checksum"
+	objectAttributeObjectSize   = "ObjectSize"
+	objectAttributeStorageClass = "StorageClass"
+)
+
+// newChecksumHash - returns a new hash and the expected decoded
+// length of its sum for a given checksum header.
+func newChecksumHash(header string) (hash.Hash, int) {
+	switch header {
+	case amzChecksumSHA256:
+		return sha256.New(), sha256.Size
+	case amzChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), crc32.Size
+	}
+	return nil, 0
+}
+
+// extractChecksumsFromHeader - extracts and validates all checksum
+// headers sent by the client, values are base64 encoded digests.
+func extractChecksumsFromHeader(header http.Header) (map[string]string, APIErrorCode) {
+	checksums := make(map[string]string)
+	for _, key := range []string{amzChecksumSHA256, amzChecksumCRC32C} {
+		if _, ok := header[key]; !ok {
+			continue
+		}
+		value := strings.TrimSpace(header.Get(key))
+		_, size := newChecksumHash(key)
+		sum, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(sum) != size {
+			return nil, ErrInvalidChecksum
+		}
+		checksums[key] = value
+	}
+	return checksums, ErrNone
+}
+
+// checksumReader - computes client requested checksums over the
+// incoming data and fails the read once all the expected data has
+// been consumed and any of the checksums does not match.
+type checksumReader struct {
+	reader   io.Reader
+	size     int64
+	read     int64
+	hashes   map[string]hash.Hash
+	expected map[string][]byte
+	verified bool
+}
+
+// newChecksumReader - wraps reader to verify checksums, size is the
+// expected length of data or -1 if unknown in which case the checksums
+// are verified at io.EOF.
+func newChecksumReader(reader io.Reader, size int64, checksums map[string]string) io.Reader {
+	if len(checksums) == 0 {
+		return reader
+	}
+	cr := &checksumReader{
+		reader:   reader,
+		size:     size,
+		hashes:   make(map[string]hash.Hash),
+		expected: make(map[string][]byte),
+	}
+	for key, value := range checksums {
+		// Values are already validated by extractChecksumsFromHeader.
+		sum, _ := base64.StdEncoding.DecodeString(value)
+		cr.hashes[key], _ = newChecksumHash(key)
+		cr.expected[key] = sum
+	}
+	return cr
+}
+
+func (c *checksumReader) verify() error {
+	c.verified = true
+	for key, h := range c.hashes {
+		if !bytes.Equal(h.Sum(nil), c.expected[key]) {
+			return errChecksumMismatch
+		}
+	}
+	return nil
+}
+
+func (c *checksumReader) Read(p []byte) (n int, err error) {
+	n, err = c.reader.Read(p)
+	if n > 0 {
+		for _, h := range c.hashes {
+			h.Write(p[:n])
+		}
+		c.read += int64(n)
+	}
+	// Callers read exactly size bytes and may never see io.EOF,
+	// so verify as soon as the last expected byte has been read.
+	if !c.verified && (err == io.EOF || (c.size >= 0 && c.read >= c.size)) {
+		if vErr := c.verify(); vErr != nil {
+			// Do not hand out the last chunk, so that the
+			// caller fails instead of committing the data.
+			return 0, vErr
+		}
+	}
+	return n, err
+}
+
+// parseObjectAttributes - parses comma separated list of attributes
+// sent in x-amz-object-attributes header.
+func parseObjectAttributes(header http.Header) (map[string]bool, APIErrorCode) {
+	attributes := make(map[string]bool)
+	for _, value := range header[http.CanonicalHeaderKey("X-Amz-Object-Attributes")] {
+		for _, attribute := range strings.Split(value, ",") {
+			attribute = strings.TrimSpace(attribute)
+			switch attribute {
+			case objectAttributeETag, objectAttributeChecksum,
+				objectAttributeObjectSize, objectAttributeStorageClass:
+				attributes[attribute] = true
+			default:
+				return nil, ErrInvalidObjectAttributes
+			}
+		}
+	}
+	if len(attributes) == 0 {
+		return nil, ErrInvalidObjectAttributes
+	}
+	return attributes, ErrNone
+}
@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// Returns base64 encoded sha256 and crc32c checksums of data.
+func getTestChecksums(data []byte) (string, string) {
+	sha256Sum := sha256.Sum256(data)
+	crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+	crcSum := []byte{byte(crc >> 24), byte(crc >> 16), byte(crc >> 8), byte(crc)}
+	return base64.StdEncoding.EncodeToString(sha256Sum[:]), base64.StdEncoding.EncodeToString(crcSum)
+}
+
+// Tests validate extraction of checksum headers.
+func TestExtractChecksumsFromHeader(t *testing.T) {
+	sha256Sum, crcSum := getTestChecksums([]byte("hello"))
+	testCases := []struct {
+		header      http.Header
+		checksums   map[string]string
+		expectedErr APIErrorCode
+	}{
+		// No checksums.
+		{http.Header{}, map[string]string{}, ErrNone},
+		// Both checksums.
+		{
+			http.Header{amzChecksumSHA256: []string{sha256Sum}, amzChecksumCRC32C: []string{crcSum}},
+			map[string]string{amzChecksumSHA256: sha256Sum, amzChecksumCRC32C: crcSum},
+			ErrNone,
+		},
+		// Invalid base64.
+		{http.Header{amzChecksumSHA256: []string{"not-base64!"}}, nil, ErrInvalidChecksum},
+		// Valid base64 with wrong digest length.
+		{http.Header{amzChecksumSHA256: []string{crcSum}}, nil, ErrInvalidChecksum},
+		{http.Header{amzChecksumCRC32C: []string{sha256Sum}}, nil, ErrInvalidChecksum},
+	}
+	for i, testCase := range testCases {
+		checksums, errCode := extractChecksumsFromHeader(testCase.header)
+		if errCode != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, errCode)
+		}
+		if errCode != ErrNone {
+			continue
+		}
+		if len(checksums) != len(testCase.checksums) {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, testCase.checksums, checksums)
+		}
+		for key, value := range testCase.checksums {
+			if checksums[key] != value {
+				t.Fatalf("Test %d: Expected %s for %s, got %s", i+1, value, key, checksums[key])
+			}
+		}
+	}
+}
+
+// Tests validate checksum verification while reading data.
+func TestChecksumReader(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	sha256Sum, crcSum := getTestChecksums(data)
+	badSHA256Sum, badCRCSum := getTestChecksums([]byte("hello"))
+	testCases := []struct {
+		checksums   map[string]string
+		size        int64
+		expectedErr error
+	}{
+		{map[string]string{amzChecksumSHA256: sha256Sum, amzChecksumCRC32C: crcSum}, int64(len(data)), nil},
+		{map[string]string{amzChecksumCRC32C: crcSum}, -1, nil},
+		{map[string]string{amzChecksumSHA256: badSHA256Sum}, int64(len(data)), errChecksumMismatch},
+		{map[string]string{amzChecksumCRC32C: badCRCSum}, -1, errChecksumMismatch},
+		{map[string]string{amzChecksumSHA256: sha256Sum, amzChecksumCRC32C: badCRCSum}, int64(len(data)), errChecksumMismatch},
+	}
+	for i, testCase := range testCases {
+		reader := newChecksumReader(bytes.NewReader(data), testCase.size, testCase.checksums)
+		// Read exactly size bytes when known, to validate that
+		// checksums are verified without reaching io.EOF.
+		var err error
+		if testCase.size >= 0 {
+			buf := make([]byte, testCase.size)
+			_, err = io.ReadFull(reader, buf)
+		} else {
+			_, err = ioutil.ReadAll(reader)
+		}
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+
+	// No checksums should return reader as is.
+	plainReader := bytes.NewReader(data)
+	if newChecksumReader(plainReader, int64(len(data)), nil) != plainReader {
+		t.Fatal("Expected reader to be returned as is without checksums")
+	}
+}
+
+// Tests validate parsing of x-amz-object-attributes header.
+func TestParseObjectAttributes(t *testing.T) {
+	testCases := []struct {
+		values      []string
+		expected    []string
+		expectedErr APIErrorCode
+	}{
+		{nil, nil, ErrInvalidObjectAttributes},
+		{[]string{"ETag"}, []string{"ETag"}, ErrNone},
+		{[]string{"ETag, Checksum", "ObjectSize,StorageClass"}, []string{"ETag", "Checksum", "ObjectSize", "StorageClass"}, ErrNone},
+		{[]string{"ETag,Unknown"}, nil, ErrInvalidObjectAttributes},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		for _, value := range testCase.values {
+			header.Add("X-Amz-Object-Attributes", value)
+		}
+		attributes, errCode := parseObjectAttributes(header)
+		if errCode != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, errCode)
+		}
+		if len(attributes) != len(testCase.expected) {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, testCase.expected, attributes)
+		}
+		for _, attribute := range testCase.expected {
+			if !attributes[attribute] {
+				t.Fatalf("Test %d: Expected attribute %s to be set", i+1, attribute)
+			}
+		}
+	}
+}
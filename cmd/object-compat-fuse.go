@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// Compatibility profile for FUSE clients mounting buckets, such
+	// as s3fs and goofys.
+	compatFUSE = "fuse"
+
+	// Content type of directories, as created by FUSE clients.
+	fuseDirContentType = "application/x-directory"
+)
+
+// validateCompat - validates the compatibility profile, empty for none.
+func validateCompat(compat string) error {
+	if compat != "" && compat != compatFUSE {
+		return fmt.Errorf("unknown compatibility profile %s", compat)
+	}
+	return nil
+}
+
+// fuseObjects adjusts the object layer to what FUSE clients expect of
+// directories. Directories holding objects can be read as empty
+// directory objects, listings of a directory do not include its own
+// directory object, and deleting the last object of a directory
+// keeps the directory.
+type fuseObjects struct {
+	ObjectLayer
+}
+
+// newFUSEObjectLayer - wraps objAPI for FUSE clients.
+func newFUSEObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	return fuseObjects{objAPI}
+}
+
+// isErrDirNotFound - returns true if err is returned for a directory
+// name which is not a directory object.
+func isErrDirNotFound(err error) bool {
+	return isErrObjectNotFound(err) || isErrObjectNameInvalid(err)
+}
+
+// getImplicitDirInfo - returns the info of a directory which has no
+// directory object but holds objects, ObjectNotFound if it is empty.
+func (l fuseObjects) getImplicitDirInfo(bucket, object string) (ObjectInfo, error) {
+	result, err := l.ObjectLayer.ListObjects(bucket, object, "", "", 1)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if len(result.Objects) == 0 {
+		return ObjectInfo{}, traceError(ObjectNotFound{Bucket: bucket, Object: object})
+	}
+	return dirObjectInfo(bucket, object, result.Objects[0].ModTime), nil
+}
+
+// GetObjectInfo - returns the info of an object, or of a directory
+// holding objects.
+func (l fuseObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	objInfo, err := l.ObjectLayer.GetObjectInfo(bucket, object)
+	if hasSuffix(object, slashSeparator) && isErrDirNotFound(err) {
+		objInfo, err = l.getImplicitDirInfo(bucket, object)
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if hasSuffix(object, slashSeparator) {
+		objInfo.ContentType = fuseDirContentType
+	}
+	return objInfo, nil
+}
+
+// GetObject - reads an object, directories holding objects read
+// as empty.
+func (l fuseObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	err := l.ObjectLayer.GetObject(bucket, object, startOffset, length, writer)
+	if !hasSuffix(object, slashSeparator) || !isErrDirNotFound(err) {
+		return err
+	}
+	if _, err = l.getImplicitDirInfo(bucket, object); err != nil {
+		return err
+	}
+	if startOffset != 0 || length > 0 {
+		return traceError(InvalidRange{startOffset, length, 0})
+	}
+	return nil
+}
+
+// ListObjects - lists objects, listings with a delimiter leave out the
+// directory object of the listed directory.
+func (l fuseObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	result, err := l.ObjectLayer.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil || delimiter == "" || !hasSuffix(prefix, slashSeparator) {
+		return result, err
+	}
+	objects := result.Objects[:0]
+	for _, objInfo := range result.Objects {
+		if objInfo.Name != prefix {
+			objects = append(objects, objInfo)
+		}
+	}
+	result.Objects = objects
+	return result, nil
+}
+
+// DeleteObject - deletes an object, its directory is kept as a
+// directory object if the object was the last one in it.
+func (l fuseObjects) DeleteObject(bucket, object string) error {
+	if err := l.ObjectLayer.DeleteObject(bucket, object); err != nil {
+		return err
+	}
+	dir := path.Dir(strings.TrimSuffix(object, slashSeparator))
+	if dir == "." {
+		return nil
+	}
+	dir += slashSeparator
+	if _, err := l.GetObjectInfo(bucket, dir); !isErrObjectNotFound(err) {
+		return nil
+	}
+	_, err := l.ObjectLayer.PutObject(bucket, dir, 0, bytes.NewReader(nil), nil, "")
+	errorIf(err, "Unable to keep directory %s/%s.", bucket, dir)
+	return nil
+}
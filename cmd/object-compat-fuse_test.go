@@ -0,0 +1,111 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests validation of the compatibility profile.
+func TestValidateCompat(t *testing.T) {
+	for _, compat := range []string{"", compatFUSE} {
+		if err := validateCompat(compat); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", compat, err)
+		}
+	}
+	if err := validateCompat("s3fs"); err == nil {
+		t.Error("Expected unknown profile to be rejected")
+	}
+}
+
+// Tests that the FUSE compatibility layer is added by wrapObjectLayer.
+func TestWrapObjectLayerFUSE(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	var objAPI ObjectLayer = &fsObjects{}
+	if _, ok := wrapObjectLayer(objAPI).(fuseObjects); ok {
+		t.Error("Expected object layer not to adjust directories")
+	}
+	serverConfig.SetCompat(compatFUSE)
+	if _, ok := wrapObjectLayer(objAPI).(fuseObjects); !ok {
+		t.Error("Expected object layer to adjust directories")
+	}
+}
+
+// Wrapper for calling FUSE compatibility tests for both XL multiple disks and single node setup.
+func TestFUSEObjects(t *testing.T) {
+	ExecObjectLayerTest(t, testFUSEObjects)
+}
+
+// testFUSEObjects - tests directory handling of the FUSE compatibility layer.
+func testFUSEObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	obj = newFUSEObjectLayer(obj)
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	for _, object := range []string{"dir/a", "dir/b", "empty/"} {
+		if _, err := obj.PutObject(bucket, object, 0, bytes.NewReader(nil), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	// Directories holding objects read as empty directory objects.
+	for _, object := range []string{"dir/", "empty/"} {
+		objInfo, err := obj.GetObjectInfo(bucket, object)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		if objInfo.Name != object || objInfo.Size != 0 || objInfo.ContentType != fuseDirContentType {
+			t.Fatalf("%s: Unexpected directory info %+v", instanceType, objInfo)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(bucket, object, 0, 0, &buffer); err != nil || buffer.Len() != 0 {
+			t.Fatalf("%s: Expected empty directory %s, got %q, %v", instanceType, object, buffer.String(), err)
+		}
+	}
+	if _, err := obj.GetObjectInfo(bucket, "missing/"); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected ObjectNotFound, got %v", instanceType, err)
+	}
+
+	// Listing a directory leaves out its directory object.
+	result, err := obj.ListObjects(bucket, "empty/", "", slashSeparator, 1000)
+	if err != nil || len(result.Objects) != 0 {
+		t.Fatalf("%s: Expected empty listing, got %+v, %v", instanceType, result.Objects, err)
+	}
+	if result, err = obj.ListObjects(bucket, "empty/", "", "", 1000); err != nil || len(result.Objects) != 1 {
+		t.Fatalf("%s: Expected the directory object in recursive listings, got %+v, %v", instanceType, result.Objects, err)
+	}
+
+	// Deleting the last object of a directory keeps the directory.
+	for _, object := range []string{"dir/a", "dir/b"} {
+		if err = obj.DeleteObject(bucket, object); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	if result, err = obj.ListObjects(bucket, "", "", slashSeparator, 1000); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Prefixes) != 2 || result.Prefixes[0] != "dir/" || result.Prefixes[1] != "empty/" {
+		t.Fatalf("%s: Expected dir/ and empty/ to be kept, got %v", instanceType, result.Prefixes)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// Objects smaller than this are not compressed by default, the
+// savings do not make up for the gzip framing.
+const defaultCompressionMinSize = 1024
+
+// Content types compressed by default.
+var defaultCompressionContentTypes = []string{
+	"text/*",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// compressionConfig - gzip compression of GET object responses.
+type compressionConfig struct {
+	// Compress responses to clients accepting gzip.
+	Enable bool `json:"enable"`
+
+	// Content types compressed, '*' wildcards are supported, e.g.
+	// "text/*". Defaults to text, JavaScript, JSON, XML and SVG.
+	ContentTypes []string `json:"contentTypes"`
+
+	// Objects smaller than this many bytes are sent as is, 1024 if
+	// zero.
+	MinSize int64 `json:"minSize"`
+}
+
+// validateCompressionConfig - validates compression settings.
+func validateCompressionConfig(config compressionConfig) error {
+	for _, pattern := range config.ContentTypes {
+		if pattern == "" {
+			return errors.New("contentTypes: empty content type")
+		}
+	}
+	if config.MinSize < 0 {
+		return errors.New("minSize: must not be negative")
+	}
+	return nil
+}
+
+// isCompressible - returns true if objects of contentType and size
+// are compressed.
+func (config compressionConfig) isCompressible(contentType string, size int64) bool {
+	if !config.Enable {
+		return false
+	}
+	minSize := config.MinSize
+	if minSize == 0 {
+		minSize = defaultCompressionMinSize
+	}
+	if size < minSize {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	patterns := config.ContentTypes
+	if len(patterns) == 0 {
+		patterns = defaultCompressionContentTypes
+	}
+	for _, pattern := range patterns {
+		if wildcard.MatchSimple(strings.ToLower(pattern), mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip - returns true if the Accept-Encoding header value
+// accepts gzip, explicitly or with '*', with a non zero quality.
+func acceptsGzip(acceptEncoding string) bool {
+	accepted := false
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(coding, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				quality, _ = strconv.ParseFloat(param[len("q="):], 64)
+			}
+		}
+		// An explicit gzip coding takes precedence over '*'.
+		if name == "gzip" {
+			return quality > 0
+		}
+		accepted = quality > 0
+	}
+	return accepted
+}
+
+// getResponseCompression - returns true if the GET or HEAD object
+// response to r of objInfo is to be gzip compressed. Ranges apply to
+// the object as stored, so ranged requests and objects already encoded
+// are sent as is. Also sets Vary on w if the response depends on the
+// Accept-Encoding of the request.
+func getResponseCompression(w http.ResponseWriter, r *http.Request, objInfo ObjectInfo) bool {
+	if serverConfig == nil || !serverConfig.GetCompression().isCompressible(objInfo.ContentType, objInfo.Size) {
+		return false
+	}
+	if objInfo.ContentEncoding != "" || r.URL.Query().Get("response-content-encoding") != "" {
+		return false
+	}
+	if r.Header.Get("Range") != "" {
+		return false
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	return acceptsGzip(r.Header.Get("Accept-Encoding"))
+}
+
+// gzipResponseWriter - gzip compresses successful responses. As the
+// compressed data differs from the object data, the ETag is made weak
+// and the length is left unknown. Responses to HEAD requests get the
+// same headers and need not be closed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gzw         *gzip.Writer
+	compress    bool
+	wroteHeader bool
+}
+
+func newGzipResponseWriter(w http.ResponseWriter) *gzipResponseWriter {
+	return &gzipResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader - compresses the body of 200 responses, marks the ETag
+// of 200 and 304 responses as weak.
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	header := g.ResponseWriter.Header()
+	if code == http.StatusOK || code == http.StatusNotModified {
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+	}
+	if code == http.StatusOK {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		g.compress = true
+	}
+	g.ResponseWriter.WriteHeader(code)
+}
+
+// Write - writes p, compressed if the response is.
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.compress {
+		if g.gzw == nil {
+			g.gzw = gzip.NewWriter(g.ResponseWriter)
+		}
+		return g.gzw.Write(p)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+// Close - flushes the compressed data, to be called once the response
+// is written.
+func (g *gzipResponseWriter) Close() error {
+	if !g.compress {
+		return nil
+	}
+	if g.gzw == nil {
+		g.gzw = gzip.NewWriter(g.ResponseWriter)
+	}
+	return g.gzw.Close()
+}
@@ -0,0 +1,202 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// Tests negotiation of gzip with Accept-Encoding.
+func TestAcceptsGzip(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		accepted       bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip", true},
+		{"GZIP;q=0.5", true},
+		{"gzip;q=0", false},
+		{"br", false},
+		{"*", true},
+		{"*;q=0", false},
+		{"gzip;q=0, *", false},
+		{"*, gzip;q=0", false},
+	}
+	for i, testCase := range testCases {
+		if accepted := acceptsGzip(testCase.acceptEncoding); accepted != testCase.accepted {
+			t.Errorf("Test %d: Expected %v for %q, got %v", i+1, testCase.accepted, testCase.acceptEncoding, accepted)
+		}
+	}
+}
+
+// Tests which objects are compressed.
+func TestIsCompressible(t *testing.T) {
+	testCases := []struct {
+		config       compressionConfig
+		contentType  string
+		size         int64
+		compressible bool
+	}{
+		{compressionConfig{}, "text/plain", 4096, false},
+		{compressionConfig{Enable: true}, "text/plain", 4096, true},
+		{compressionConfig{Enable: true}, "text/html; charset=utf-8", 4096, true},
+		{compressionConfig{Enable: true}, "application/json", 4096, true},
+		{compressionConfig{Enable: true}, "image/png", 4096, false},
+		{compressionConfig{Enable: true}, "", 4096, false},
+		{compressionConfig{Enable: true}, "text/plain", 100, false},
+		{compressionConfig{Enable: true, MinSize: 10}, "text/plain", 100, true},
+		{compressionConfig{Enable: true, ContentTypes: []string{"application/*"}}, "text/plain", 4096, false},
+		{compressionConfig{Enable: true, ContentTypes: []string{"application/*"}}, "application/wasm", 4096, true},
+	}
+	for i, testCase := range testCases {
+		if compressible := testCase.config.isCompressible(testCase.contentType, testCase.size); compressible != testCase.compressible {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.compressible, compressible)
+		}
+	}
+}
+
+// Wrapper for calling compressed GetObject tests for both XL multiple disks and single node setup.
+func TestGetObjectCompression(t *testing.T) {
+	ExecObjectLayerAPITest(t, testGetObjectCompression, []string{"GetObject", "HeadObject"})
+}
+
+// testGetObjectCompression - Tests that GET object responses are gzip
+// compressed when enabled and accepted by the client, and that HEAD
+// replies with the same headers.
+func testGetObjectCompression(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	content := bytes.Repeat([]byte("compressible text "), 1024)
+	objInfo, err := obj.PutObject(bucketName, "text.txt", int64(len(content)), bytes.NewReader(content),
+		map[string]string{"content-type": "text/plain"}, "")
+	if err != nil {
+		t.Fatalf("%s: Failed to create object: <ERROR> %v", instanceType, err)
+	}
+	etag := "\"" + objInfo.MD5Sum + "\""
+
+	serverConfig.SetCompression(compressionConfig{Enable: true})
+	defer serverConfig.SetCompression(compressionConfig{})
+
+	sendRequest := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		req, rErr := newTestSignedRequestV4(method, getGetObjectURL("", bucketName, "text.txt"),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if rErr != nil {
+			t.Fatalf("%s: Failed to create request: <ERROR> %v", instanceType, rErr)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	getObject := func(headers map[string]string) *httptest.ResponseRecorder {
+		return sendRequest("GET", headers)
+	}
+
+	// Compressed with a weak ETag.
+	rec := getObject(map[string]string{"Accept-Encoding": "gzip"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("%s: Expected gzip encoding, got %q", instanceType, encoding)
+	}
+	if got := rec.Header().Get("ETag"); got != "W/"+etag {
+		t.Errorf("%s: Expected weak ETag W/%s, got %s", instanceType, etag, got)
+	}
+	if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("%s: Expected Vary: Accept-Encoding, got %q", instanceType, vary)
+	}
+	if rec.Body.Len() >= len(content) {
+		t.Errorf("%s: Expected compressed body, got %d bytes", instanceType, rec.Body.Len())
+	}
+	gzr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("%s: Failed to read gzip body: <ERROR> %v", instanceType, err)
+	}
+	data, err := ioutil.ReadAll(gzr)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Fatalf("%s: Expected decompressed body to match the object, err %v", instanceType, err)
+	}
+
+	// The weak ETag matches the object on revalidation.
+	rec = getObject(map[string]string{"Accept-Encoding": "gzip", "If-None-Match": "W/" + etag})
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotModified, rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != "W/"+etag {
+		t.Errorf("%s: Expected weak ETag W/%s, got %s", instanceType, etag, got)
+	}
+
+	// Sent as is to clients not accepting gzip and for ranges.
+	for i, headers := range []map[string]string{
+		{},
+		{"Accept-Encoding": "gzip;q=0"},
+		{"Accept-Encoding": "gzip", "Range": "bytes=0-9"},
+	} {
+		rec = getObject(headers)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusPartialContent {
+			t.Fatalf("%s: Test %d: Unexpected response status `%d`", instanceType, i+1, rec.Code)
+		}
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != "" {
+			t.Errorf("%s: Test %d: Expected no encoding, got %q", instanceType, i+1, encoding)
+		}
+		if got := rec.Header().Get("ETag"); got != etag {
+			t.Errorf("%s: Test %d: Expected ETag %s, got %s", instanceType, i+1, etag, got)
+		}
+		if !bytes.HasPrefix(content, rec.Body.Bytes()) || rec.Body.Len() == 0 {
+			t.Errorf("%s: Test %d: Expected object data, got %d bytes", instanceType, i+1, rec.Body.Len())
+		}
+	}
+	// HEAD replies with the headers of the response to a GET.
+	for i, testCase := range []struct {
+		headers       map[string]string
+		encoding      string
+		contentLength string
+		etag          string
+	}{
+		{map[string]string{"Accept-Encoding": "gzip"}, "gzip", "", "W/" + etag},
+		{map[string]string{}, "", strconv.Itoa(len(content)), etag},
+	} {
+		rec = sendRequest("HEAD", testCase.headers)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`", instanceType, i+1, http.StatusOK, rec.Code)
+		}
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != testCase.encoding {
+			t.Errorf("%s: Test %d: Expected encoding %q, got %q", instanceType, i+1, testCase.encoding, encoding)
+		}
+		if contentLength := rec.Header().Get("Content-Length"); contentLength != testCase.contentLength {
+			t.Errorf("%s: Test %d: Expected Content-Length %q, got %q", instanceType, i+1, testCase.contentLength, contentLength)
+		}
+		if got := rec.Header().Get("ETag"); got != testCase.etag {
+			t.Errorf("%s: Test %d: Expected ETag %s, got %s", instanceType, i+1, testCase.etag, got)
+		}
+		if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+			t.Errorf("%s: Test %d: Expected Vary: Accept-Encoding, got %q", instanceType, i+1, vary)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("%s: Test %d: Expected no body, got %d bytes", instanceType, i+1, rec.Body.Len())
+		}
+	}
+}
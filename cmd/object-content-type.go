@@ -0,0 +1,164 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/minio/minio/pkg/mimedb"
+)
+
+// Number of leading bytes of an upload looked at to sniff its
+// content type, see http.DetectContentType.
+const contentTypeSniffLen = 512
+
+// Content type sent by clients which do not know the type of the data.
+const octetStreamContentType = "application/octet-stream"
+
+// contentTypeConfig - content type policy of new objects.
+type contentTypeConfig struct {
+	// Guess the content type of uploads sent without one or as
+	// application/octet-stream, from the object name extension and
+	// otherwise from the leading bytes of the data.
+	Sniff bool `json:"sniff"`
+
+	// Per bucket mappings of extensions, without the leading dot, to
+	// content types, used instead of the built-in ones.
+	Buckets map[string]map[string]string `json:"buckets"`
+}
+
+// contentTypePolicy - validated form of contentTypeConfig.
+type contentTypePolicy struct {
+	sniff bool
+	// Extensions are lower case.
+	buckets map[string]map[string]string
+}
+
+// newContentTypePolicy - validates config, returns nil if sniffing is
+// disabled and no bucket has a mapping.
+func newContentTypePolicy(config contentTypeConfig) (*contentTypePolicy, error) {
+	buckets := make(map[string]map[string]string)
+	for bucket, types := range config.Buckets {
+		if !IsValidBucketName(bucket) {
+			return nil, fmt.Errorf("Invalid bucket name %s", bucket)
+		}
+		extTypes := make(map[string]string)
+		for ext, contentType := range types {
+			if ext == "" || strings.ContainsAny(ext, "./") {
+				return nil, fmt.Errorf("Invalid extension %s of bucket %s", ext, bucket)
+			}
+			if _, _, err := mime.ParseMediaType(contentType); err != nil {
+				return nil, fmt.Errorf("Invalid content type %s of bucket %s: %v", contentType, bucket, err)
+			}
+			extTypes[strings.ToLower(ext)] = contentType
+		}
+		if len(extTypes) > 0 {
+			buckets[bucket] = extTypes
+		}
+	}
+	if !config.Sniff && len(buckets) == 0 {
+		return nil, nil
+	}
+	return &contentTypePolicy{
+		sniff:   config.Sniff,
+		buckets: buckets,
+	}, nil
+}
+
+// getExtContentType - returns the content type of the object name
+// extension from the mapping of bucket, falling back to the built-in
+// types if builtin is set. Returns "" if the extension is unknown.
+func (p *contentTypePolicy) getExtContentType(bucket, object string, builtin bool) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(object), "."))
+	if ext == "" {
+		return ""
+	}
+	if contentType, ok := p.buckets[bucket][ext]; ok {
+		return contentType
+	}
+	if builtin {
+		if content, ok := mimedb.DB[ext]; ok {
+			return content.ContentType
+		}
+	}
+	return ""
+}
+
+// setContentType - sets the content type in metadata of an upload
+// of size bytes read from reader, which is nil if the data is not
+// available yet, e.g. for multipart uploads. Returns a reader of the
+// full data. A nil policy leaves metadata as is.
+//
+// Objects sent without a content type get the type mapped to their
+// extension for bucket, if any. The object layers guess the type of
+// the others from the built-in types. With sniffing enabled, objects
+// sent without a content type or as application/octet-stream get the
+// type of their extension, or else the type detected from the data.
+func (p *contentTypePolicy) setContentType(bucket, object string, size int64, metadata map[string]string, reader io.Reader) (io.Reader, error) {
+	if p == nil {
+		return reader, nil
+	}
+	contentType := metadata["content-type"]
+	if contentType != "" && !(p.sniff && isOctetStreamContentType(contentType)) {
+		return reader, nil
+	}
+	if extType := p.getExtContentType(bucket, object, p.sniff); extType != "" {
+		metadata["content-type"] = extType
+		return reader, nil
+	}
+	if !p.sniff || reader == nil || size == 0 {
+		return reader, nil
+	}
+
+	// Sniff the leading bytes, and put them back in front of the
+	// rest of the data.
+	buf := make([]byte, contentTypeSniffLen)
+	if size > 0 && size < contentTypeSniffLen {
+		buf = buf[:size]
+	}
+	n, err := io.ReadFull(reader, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	buf = buf[:n]
+	metadata["content-type"] = http.DetectContentType(buf)
+	return io.MultiReader(bytes.NewReader(buf), reader), nil
+}
+
+// isOctetStreamContentType - returns true if contentType is
+// application/octet-stream, with or without parameters.
+func isOctetStreamContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == octetStreamContentType
+}
+
+// initContentTypePolicy - initializes the global content type policy
+// from server config.
+func initContentTypePolicy() error {
+	policy, err := newContentTypePolicy(serverConfig.GetContentType())
+	if err != nil {
+		return err
+	}
+	globalContentTypePolicy = policy
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// Tests validating content type configuration.
+func TestNewContentTypePolicy(t *testing.T) {
+	testCases := []struct {
+		config    contentTypeConfig
+		isNil     bool
+		shouldErr bool
+	}{
+		// Nothing configured.
+		{contentTypeConfig{}, true, false},
+		// Sniffing only.
+		{contentTypeConfig{Sniff: true}, false, false},
+		// Bucket mapping only.
+		{contentTypeConfig{Buckets: map[string]map[string]string{"www": {"HTML": "text/html; charset=utf-8"}}}, false, false},
+		// Invalid bucket name.
+		{contentTypeConfig{Buckets: map[string]map[string]string{"w": {"html": "text/html"}}}, false, true},
+		// Extension with a leading dot.
+		{contentTypeConfig{Buckets: map[string]map[string]string{"www": {".html": "text/html"}}}, false, true},
+		// Invalid content type.
+		{contentTypeConfig{Buckets: map[string]map[string]string{"www": {"html": "text/html; charset"}}}, false, true},
+	}
+	for i, testCase := range testCases {
+		policy, err := newContentTypePolicy(testCase.config)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+		if err == nil && testCase.isNil != (policy == nil) {
+			t.Fatalf("Test %d: Expected nil policy %v, got %v", i+1, testCase.isNil, policy)
+		}
+	}
+}
+
+// Tests guessing content types of uploads.
+func TestSetContentType(t *testing.T) {
+	htmlData := []byte("<html><body>hello</body></html>")
+	policy, err := newContentTypePolicy(contentTypeConfig{
+		Sniff: true,
+		Buckets: map[string]map[string]string{
+			"www": {"page": "text/html; charset=utf-8"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapOnly, err := newContentTypePolicy(contentTypeConfig{
+		Buckets: map[string]map[string]string{
+			"www": {"page": "text/html; charset=utf-8"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		policy       *contentTypePolicy
+		bucket       string
+		object       string
+		contentType  string
+		data         []byte
+		expectedType string
+	}{
+		// Nil policy keeps the content type as is.
+		{nil, "www", "index.page", "", htmlData, ""},
+		// Bucket mapping applies to uploads without content type.
+		{mapOnly, "www", "index.PAGE", "", htmlData, "text/html; charset=utf-8"},
+		// Content type sent by the client is kept.
+		{mapOnly, "www", "index.page", octetStreamContentType, htmlData, octetStreamContentType},
+		// Without sniffing other buckets are left to the object layer.
+		{mapOnly, "other", "index.page", "", htmlData, ""},
+		// Sniffing replaces application/octet-stream by the bucket mapping.
+		{policy, "www", "index.page", octetStreamContentType, htmlData, "text/html; charset=utf-8"},
+		// Then by the built-in types.
+		{policy, "other", "style.css", "application/octet-stream; charset=binary", htmlData, "text/css"},
+		// Then by the type detected from the data.
+		{policy, "other", "index", octetStreamContentType, htmlData, "text/html; charset=utf-8"},
+		{policy, "other", "index", "", []byte("\x89PNG\x0D\x0A\x1A\x0A"), "image/png"},
+		// Other content types are kept.
+		{policy, "www", "index.page", "text/plain", htmlData, "text/plain"},
+	}
+	for i, testCase := range testCases {
+		metadata := map[string]string{}
+		if testCase.contentType != "" {
+			metadata["content-type"] = testCase.contentType
+		}
+		reader, err := testCase.policy.setContentType(testCase.bucket, testCase.object, int64(len(testCase.data)),
+			metadata, bytes.NewReader(testCase.data))
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if metadata["content-type"] != testCase.expectedType {
+			t.Errorf("Test %d: Expected content type %q, got %q", i+1, testCase.expectedType, metadata["content-type"])
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if !bytes.Equal(data, testCase.data) {
+			t.Errorf("Test %d: Expected data to be preserved, got %q", i+1, data)
+		}
+	}
+
+	// Multipart uploads have no data to sniff.
+	metadata := map[string]string{}
+	reader, err := policy.setContentType("other", "index", -1, metadata, nil)
+	if err != nil || reader != nil || metadata["content-type"] != "" {
+		t.Fatalf("Expected no content type, got %q, %v", metadata["content-type"], err)
+	}
+
+	// Read errors are returned.
+	metadata = map[string]string{}
+	if _, err = policy.setContentType("other", "index", 10, metadata, errorReader{errUnexpected}); err != errUnexpected {
+		t.Fatalf("Expected %v, got %v", errUnexpected, err)
+	}
+}
+
+// errorReader - fails all reads with err.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
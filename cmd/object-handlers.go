@@ -19,6 +19,7 @@ package cmd
 import (
 	"encoding/hex"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -26,6 +27,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	mux "github.com/gorilla/mux"
 )
@@ -40,6 +42,16 @@ var supportedGetReqParams = map[string]string{
 	"response-content-disposition": "Content-Disposition",
 }
 
+// hasGetRespParams - returns true if any response header is requested.
+func hasGetRespParams(reqParams url.Values) bool {
+	for k := range reqParams {
+		if _, ok := supportedGetReqParams[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // setGetRespHeaders - set any requested parameters as response headers.
 func setGetRespHeaders(w http.ResponseWriter, reqParams url.Values) {
 	for k, v := range reqParams {
@@ -85,28 +97,50 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	// Fetch object stat info.
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	// Noncurrent versions need their own permission.
+	versionID, action := r.URL.Query().Get("versionId"), "s3:GetObject"
+	if versionID != "" {
+		action = "s3:GetObjectVersion"
+	}
+	if s3Error := checkRequestAuthType(r, bucket, action, serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	if versionID != "" && !isValidVersionID(versionID) {
+		writeErrorResponse(w, ErrInvalidVersionID, r)
+		return
+	}
+
+	// Like S3, only signed requests may override response headers,
+	// anyone could otherwise serve public objects with any type.
+	if getRequestAuthType(r) == authTypeAnonymous && hasGetRespParams(r.URL.Query()) {
+		writeErrorResponse(w, ErrAnonymousResponseHeaders, r)
 		return
 	}
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	var objInfo ObjectInfo
+	var err error
+	if versionID != "" {
+		objInfo, err = objectAPI.GetObjectVersionInfo(bucket, object, versionID)
+	} else {
+		objInfo, err = objectAPI.GetObjectInfo(bucket, object)
+	}
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
 		}
-		writeErrorResponse(w, apiErr, r.URL)
+		writeErrorResponse(w, apiErr, r)
 		return
 	}
 
@@ -118,7 +152,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
 			if err == errInvalidRange {
-				writeErrorResponse(w, ErrInvalidRange, r.URL)
+				writeErrorResponse(w, ErrInvalidRange, r)
 				return
 			}
 
@@ -127,6 +161,13 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Compress the response if configured and accepted by the client.
+	if getResponseCompression(w, r, objInfo) {
+		gzw := newGzipResponseWriter(w)
+		defer gzw.Close()
+		w = gzw
+	}
+
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
@@ -151,20 +192,29 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			// Set any additional requested response headers.
 			setGetRespHeaders(w, r.URL.Query())
 
+			if isPartialContent(hrange) {
+				w.WriteHeader(http.StatusPartialContent)
+			}
+
 			dataWritten = true
 		}
 		return w.Write(p)
 	})
 
 	// Reads the object at startOffset and writes to mw.
-	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
+	if versionID != "" {
+		err = objectAPI.GetObjectVersion(bucket, object, versionID, startOffset, length, writer)
+	} else {
+		err = objectAPI.GetObject(bucket, object, startOffset, length, writer)
+	}
+	if err != nil {
 		errorIf(err, "Unable to write to client.")
 		if !dataWritten {
 			// Error response only if no data has been written to client yet. i.e if
 			// partial data has already been written before an error
 			// occurred then no point in setting StatusCode and
 			// sending error XML.
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(err), r)
 		}
 		return
 	}
@@ -191,17 +241,31 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	versionID, action := r.URL.Query().Get("versionId"), "s3:GetObject"
+	if versionID != "" {
+		action = "s3:GetObjectVersion"
+	}
+	if s3Error := checkRequestAuthType(r, bucket, action, serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponseHeadersOnly(w, s3Error)
 		return
 	}
+	if versionID != "" && !isValidVersionID(versionID) {
+		writeErrorResponseHeadersOnly(w, ErrInvalidVersionID)
+		return
+	}
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	var objInfo ObjectInfo
+	var err error
+	if versionID != "" {
+		objInfo, err = objectAPI.GetObjectVersionInfo(bucket, object, versionID)
+	} else {
+		objInfo, err = objectAPI.GetObjectInfo(bucket, object)
+	}
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
@@ -212,6 +276,12 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Reply with the headers of the response to a GET, compressed if
+	// configured and accepted by the client.
+	if getResponseCompression(w, r, objInfo) {
+		w = newGzipResponseWriter(w)
+	}
+
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
@@ -220,10 +290,70 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	// Set standard object headers.
 	setObjectHeaders(w, objInfo, nil)
 
+	// Set range hint headers, a Minio extension.
+	setRangeHintHeaders(w, objInfo)
+
 	// Successful response.
 	w.WriteHeader(http.StatusOK)
 }
 
+// GetObjectAttributesHandler - GET Object attributes
+// ----------
+// This operation returns the requested attributes of an object, such as
+// its ETag, size and checksums saved during upload, without its data.
+func (api objectAPIHandlers) GetObjectAttributesHandler(w http.ResponseWriter, r *http.Request) {
+	var object, bucket string
+	vars := mux.Vars(r)
+	bucket = vars["bucket"]
+	object = vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	attributes, s3Error := parseObjectAttributes(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIf(err, "Unable to fetch object info.")
+		apiErr := toAPIErrorCode(err)
+		if apiErr == ErrNoSuchKey {
+			apiErr = errAllowableObjectNotFound(bucket, r)
+		}
+		writeErrorResponse(w, apiErr, r)
+		return
+	}
+
+	// Validate pre-conditions if any.
+	if checkPreconditions(w, r, objInfo) {
+		return
+	}
+
+	response := generateGetObjectAttributesResponse(objInfo, attributes)
+	encodedSuccessResponse := encodeResponse(response)
+
+	w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+}
+
 // Extract metadata relevant for an CopyObject operation based on conditional
 // header values specified in X-Amz-Metadata-Directive.
 func getCpObjMetadataFromHeader(header http.Header, defaultMeta map[string]string) map[string]string {
@@ -255,12 +385,12 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -276,13 +406,19 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	srcBucket, srcObject := path2BucketAndObject(cpSrcPath)
 	// If source object is empty or bucket is empty, reply back invalid copy source.
 	if srcObject == "" || srcBucket == "" {
-		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		writeErrorResponse(w, ErrInvalidCopySource, r)
+		return
+	}
+
+	// IAM users must also be allowed to read the source object.
+	if s3Error := checkIAMPolicy(r, getRequestAccessKey(r), "s3:GetObject", cpSrcPath); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	// Check if metadata directive is valid.
 	if !isMetadataDirectiveValid(r.Header) {
-		writeErrorResponse(w, ErrInvalidMetadataDirective, r.URL)
+		writeErrorResponse(w, ErrInvalidMetadataDirective, r)
 		return
 	}
 
@@ -295,6 +431,11 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	objectDWLock.Lock()
 	defer objectDWLock.Unlock()
 
+	if err := checkObjectOverwrite(objectAPI, dstBucket, dstObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
 	// if source and destination are different, we have to hold
 	// additional read lock as well to protect against writes on
 	// source.
@@ -310,7 +451,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -321,7 +462,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	/// maximum Upload size for object in a single CopyObject operation.
 	if isMaxObjectSize(objInfo.Size) {
-		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		writeErrorResponse(w, ErrEntityTooLarge, r)
 		return
 	}
 
@@ -338,21 +479,43 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	if !isMetadataReplace(r.Header) && cpSrcDstSame {
 		// If x-amz-metadata-directive is not set to REPLACE then we need
 		// to error out if source and destination are same.
-		writeErrorResponse(w, ErrInvalidCopyDest, r.URL)
+		writeErrorResponse(w, ErrInvalidCopyDest, r)
+		return
+	}
+
+	// Replaced metadata is subject to the same limits as uploads.
+	if isMetadataReplace(r.Header) && isMetadataTooLarge(newMetadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r)
+		return
+	}
+
+	// Preserve source ETag and Last-Modified, replication tools
+	// rely on them to detect changes after a migration.
+	if !cpSrcDstSame {
+		setSourceMetadata(newMetadata, objInfo)
+	}
+
+	modTime, s3Error := extractSourceMtimeFromHeader(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
+	if !modTime.IsZero() {
+		newMetadata[objectModTimeKey] = modTime.Format(time.RFC3339Nano)
+	}
 
 	// Copy source object to destination, if source and destination
 	// object is same then only metadata is updated.
 	objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
 	md5Sum := objInfo.MD5Sum
 	response := generateCopyObjectResponse(md5Sum, objInfo.ModTime)
 	encodedSuccessResponse := encodeResponse(response)
+	setVersionIDHeader(w, objInfo)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -374,13 +537,13 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// X-Amz-Copy-Source shouldn't be set for this call.
 	if _, ok := r.Header["X-Amz-Copy-Source"]; ok {
-		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		writeErrorResponse(w, ErrInvalidCopySource, r)
 		return
 	}
 
@@ -392,7 +555,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
 		errorIf(err, "Unable to validate content-md5 format.")
-		writeErrorResponse(w, ErrInvalidDigest, r.URL)
+		writeErrorResponse(w, ErrInvalidDigest, r)
 		return
 	}
 
@@ -404,23 +567,30 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
 			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(err), r)
 			return
 		}
 	}
-	if size == -1 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+	// Clients and proxies dropping Content-Length send the data with
+	// chunked transfer encoding, the object layer then commits the
+	// size read at EOF.
+	if size == -1 && !isChunkedRequest(r) {
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 
 	/// maximum Upload size for objects in a single operation
 	if isMaxObjectSize(size) {
-		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		writeErrorResponse(w, ErrEntityTooLarge, r)
 		return
 	}
 
 	// Extract metadata to be saved from incoming HTTP header.
 	metadata := extractMetadataFromHeader(r.Header)
+	if isMetadataTooLarge(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r)
+		return
+	}
 	if rAuthType == authTypeStreamingSigned {
 		// Make sure to delete the content-encoding parameter
 		// for a streaming signature which is set to value
@@ -431,6 +601,27 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	// Make sure we hex encode md5sum here.
 	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
 
+	// Extract checksums sent by client, verified while writing the
+	// object and saved along with its metadata.
+	checksums, s3Error := extractChecksumsFromHeader(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	for key, value := range checksums {
+		metadata[key] = value
+	}
+
+	// Preserve the modification time of the original object.
+	modTime, s3Error := extractSourceMtimeFromHeader(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	if !modTime.IsZero() {
+		metadata[objectModTimeKey] = modTime.Format(time.RFC3339Nano)
+	}
+
 	sha256sum := ""
 
 	// Lock the object.
@@ -438,56 +629,85 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	var objInfo ObjectInfo
+	if err := checkObjectOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	var reader io.Reader
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
-		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		writeErrorResponse(w, ErrAccessDenied, r)
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
 		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL.Path,
 			r.Referer(), r.URL.Query()); s3Error != ErrNone {
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
 		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		reader = r.Body
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
-		reader, s3Error := newSignV4ChunkedReader(r)
+		var s3Error APIErrorCode
+		reader, s3Error = newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		reader = r.Body
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
 		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		reader = r.Body
+	}
+
+	// IAM users must be allowed to put the object.
+	if s3Error := checkIAMPolicy(r, getRequestAccessKey(r), "s3:PutObject", r.URL.Path); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	// Limit data of unknown size.
+	if size == -1 {
+		reader = newMaxSizeReader(reader, globalMaxChunkedUploadSize)
 	}
+
+	// Guess the content type if the client did not know it.
+	if reader, err = globalContentTypePolicy.setContentType(bucket, object, size, metadata, reader); err != nil {
+		errorIf(err, "Unable to read object data. %s", r.URL.Path)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
+	objInfo, err := objectAPI.PutObject(bucket, object, size, newChecksumReader(reader, size, checksums), metadata, sha256sum)
 	if err != nil {
 		errorIf(err, "Unable to create an object. %s", r.URL.Path)
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	for key, value := range checksums {
+		w.Header().Set(key, value)
+	}
+	setVersionIDHeader(w, objInfo)
 	writeSuccessResponseHeadersOnly(w)
 
 	// Notify object created event.
@@ -512,22 +732,29 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
+	if isMetadataTooLarge(metadata) {
+		writeErrorResponse(w, ErrMetadataTooLarge, r)
+		return
+	}
+
+	// Only the extension is known before the parts are uploaded.
+	globalContentTypePolicy.setContentType(bucket, object, -1, metadata, nil)
 
 	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
 	if err != nil {
 		errorIf(err, "Unable to initiate new multipart upload id.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -546,12 +773,12 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -565,7 +792,13 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 	srcBucket, srcObject := path2BucketAndObject(cpSrcPath)
 	// If source object is empty or bucket is empty, reply back invalid copy source.
 	if srcObject == "" || srcBucket == "" {
-		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		writeErrorResponse(w, ErrInvalidCopySource, r)
+		return
+	}
+
+	// IAM users must also be allowed to read the source object.
+	if s3Error := checkIAMPolicy(r, getRequestAccessKey(r), "s3:GetObject", cpSrcPath); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -574,13 +807,13 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 
 	partID, err := strconv.Atoi(partIDString)
 	if err != nil {
-		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		writeErrorResponse(w, ErrInvalidPart, r)
 		return
 	}
 
 	// check partID with maximum part ID for multipart objects
 	if isMaxPartID(partID) {
-		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		writeErrorResponse(w, ErrInvalidMaxParts, r)
 		return
 	}
 
@@ -593,7 +826,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
 	if err != nil {
 		errorIf(err, "Unable to fetch object info.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -605,7 +838,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
 			if err == errInvalidRange {
-				writeErrorResponse(w, ErrInvalidRange, r.URL)
+				writeErrorResponse(w, ErrInvalidRange, r)
 				return
 			}
 
@@ -628,8 +861,8 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 	}
 
 	/// maximum copy size for multipart objects in a single operation
-	if isMaxObjectSize(length) {
-		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+	if isMaxPartSize(length) {
+		writeErrorResponse(w, ErrEntityTooLarge, r)
 		return
 	}
 
@@ -637,7 +870,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 	// object is same then only metadata is updated.
 	partInfo, err := objectAPI.CopyObjectPart(srcBucket, srcObject, dstBucket, dstObject, uploadID, partID, startOffset, length)
 	if err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 
@@ -656,14 +889,14 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	// get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
-		writeErrorResponse(w, ErrInvalidDigest, r.URL)
+		writeErrorResponse(w, ErrInvalidDigest, r)
 		return
 	}
 
@@ -677,18 +910,18 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
 			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(err), r)
 			return
 		}
 	}
 	if size == -1 {
-		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		writeErrorResponse(w, ErrMissingContentLength, r)
 		return
 	}
 
 	/// maximum Upload size for multipart objects in a single operation
-	if isMaxObjectSize(size) {
-		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+	if isMaxPartSize(size) {
+		writeErrorResponse(w, ErrEntityTooLarge, r)
 		return
 	}
 
@@ -697,13 +930,28 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 
 	partID, err := strconv.Atoi(partIDString)
 	if err != nil {
-		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		writeErrorResponse(w, ErrInvalidPart, r)
 		return
 	}
 
 	// check partID with maximum part ID for multipart objects
 	if isMaxPartID(partID) {
-		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		writeErrorResponse(w, ErrInvalidMaxParts, r)
+		return
+	}
+
+	// Checksums of a part are only verified, they are not saved
+	// along with the part.
+	checksums, s3Error := extractChecksumsFromHeader(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+
+	// IAM users must be allowed to put the object, their signature
+	// is verified below before the part is stored.
+	if s3Error := checkIAMPolicy(r, getRequestAccessKey(r), "s3:PutObject", r.URL.Path); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -713,55 +961,58 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
-		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		writeErrorResponse(w, ErrAccessDenied, r)
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
 		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL.Path,
 			r.Referer(), r.URL.Query()); s3Error != ErrNone {
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
 		// No need to verify signature, anonymous request access is already allowed.
-		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, newChecksumReader(r.Body, size, checksums), incomingMD5, sha256sum)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
-		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
+		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, newChecksumReader(reader, size, checksums), incomingMD5, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
-		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, newChecksumReader(r.Body, size, checksums), incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
-			writeErrorResponse(w, s3Error, r.URL)
+			writeErrorResponse(w, s3Error, r)
 			return
 		}
 
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
+		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, newChecksumReader(r.Body, size, checksums), incomingMD5, sha256sum)
 	}
 	if err != nil {
 		errorIf(err, "Unable to create object part.")
 		// Verify if the underlying error is signature mismatch.
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	if partInfo.ETag != "" {
 		w.Header().Set("ETag", "\""+partInfo.ETag+"\"")
 	}
+	for key, value := range checksums {
+		w.Header().Set(key, value)
+	}
 
 	writeSuccessResponseHeadersOnly(w)
 }
@@ -774,19 +1025,19 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:AbortMultipartUpload", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
 	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
 		errorIf(err, "Unable to abort multipart upload.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	writeSuccessNoContent(w)
@@ -800,28 +1051,28 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:ListMultipartUploadParts", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
 	uploadID, partNumberMarker, maxParts, _ := getObjectResources(r.URL.Query())
 	if partNumberMarker < 0 {
-		writeErrorResponse(w, ErrInvalidPartNumberMarker, r.URL)
+		writeErrorResponse(w, ErrInvalidPartNumberMarker, r)
 		return
 	}
 	if maxParts < 0 {
-		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		writeErrorResponse(w, ErrInvalidMaxParts, r)
 		return
 	}
 	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
 		errorIf(err, "Unable to list uploaded parts.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r)
 		return
 	}
 	response := generateListPartsResponse(listPartsInfo)
@@ -839,12 +1090,12 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
 	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+		writeErrorResponse(w, s3Error, r)
 		return
 	}
 
@@ -854,21 +1105,21 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	completeMultipartBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		errorIf(err, "Unable to complete multipart upload.")
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		return
 	}
 	complMultipartUpload := &completeMultipartUpload{}
 	if err = xml.Unmarshal(completeMultipartBytes, complMultipartUpload); err != nil {
 		errorIf(err, "Unable to parse complete multipart upload XML.")
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		writeErrorResponse(w, ErrMalformedXML, r)
 		return
 	}
 	if len(complMultipartUpload.Parts) == 0 {
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		writeErrorResponse(w, ErrMalformedXML, r)
 		return
 	}
 	if !sort.IsSorted(completedParts(complMultipartUpload.Parts)) {
-		writeErrorResponse(w, ErrInvalidPartOrder, r.URL)
+		writeErrorResponse(w, ErrInvalidPartOrder, r)
 		return
 	}
 
@@ -885,6 +1136,11 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	destLock.Lock()
 	defer destLock.Unlock()
 
+	if err = checkObjectOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r)
+		return
+	}
+
 	objInfo, err := objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
 	if err != nil {
 		errorIf(err, "Unable to complete multipart upload.")
@@ -895,7 +1151,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 			writePartSmallErrorResponse(w, r, oErr)
 		default:
 			// Handle all other generic issues.
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			writeErrorResponse(w, toAPIErrorCode(err), r)
 		}
 		return
 	}
@@ -907,12 +1163,13 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	encodedSuccessResponse := encodeResponse(response)
 	if err != nil {
 		errorIf(err, "Unable to parse CompleteMultipartUpload response")
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeErrorResponse(w, ErrInternalError, r)
 		return
 	}
 
 	// Set etag.
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	setVersionIDHeader(w, objInfo)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -938,12 +1195,21 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 
 	objectAPI := api.ObjectAPI()
 	if objectAPI == nil {
-		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		writeErrorResponse(w, ErrServerNotInitialized, r)
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObject", serverConfig.GetRegion()); s3Error != ErrNone {
-		writeErrorResponse(w, s3Error, r.URL)
+	// Versions are deleted for good, which needs its own permission.
+	versionID, action := r.URL.Query().Get("versionId"), "s3:DeleteObject"
+	if versionID != "" {
+		action = "s3:DeleteObjectVersion"
+	}
+	if s3Error := checkRequestAuthType(r, bucket, action, serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r)
+		return
+	}
+	if versionID != "" && !isValidVersionID(versionID) {
+		writeErrorResponse(w, ErrInvalidVersionID, r)
 		return
 	}
 
@@ -951,6 +1217,17 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
+	if versionID != "" {
+		if err := objectAPI.DeleteObjectVersion(bucket, object, versionID); err != nil {
+			errorIf(err, "Unable to delete object version.")
+			writeErrorResponse(w, toAPIErrorCode(err), r)
+			return
+		}
+		w.Header().Set(versionIDKey, versionID)
+		writeSuccessNoContent(w)
+		return
+	}
+
 	/// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	/// Ignore delete object errors, since we are suppposed to reply
 	/// only 204.
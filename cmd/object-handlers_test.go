@@ -26,6 +26,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
@@ -191,6 +192,133 @@ func testAPIHeadObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling GetObjectAttributes API handler tests for both XL multiple disks and FS single drive setup.
+func TestAPIGetObjectAttributesHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIGetObjectAttributesHandler, []string{"PutObject", "GetObjectAttributes"})
+}
+
+func testAPIGetObjectAttributesHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	data := generateBytesData(6 * humanize.MiByte)
+	sha256Sum, crcSum := getTestChecksums(data)
+	_, badCRCSum := getTestChecksums([]byte("hello"))
+
+	// Test cases for uploading objects with checksums.
+	putTestCases := []struct {
+		objectName         string
+		checksums          map[string]string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid checksums are verified and saved.
+		{objectName, map[string]string{amzChecksumSHA256: sha256Sum, amzChecksumCRC32C: crcSum}, http.StatusOK},
+		// Test case - 2.
+		// Mismatching checksum fails the upload.
+		{"bad-object", map[string]string{amzChecksumCRC32C: badCRCSum}, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed checksum fails the upload.
+		{"bad-object", map[string]string{amzChecksumSHA256: "invalid"}, http.StatusBadRequest},
+	}
+	for i, testCase := range putTestCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		for key, value := range testCase.checksums {
+			req.Header.Set(key, value)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Object failing checksum verification must not be created.
+	if _, err := obj.GetObjectInfo(bucketName, "bad-object"); err == nil {
+		t.Fatalf("%s: Expected object failing checksum verification to not exist", instanceType)
+	}
+
+	testCases := []struct {
+		objectName         string
+		attributes         string
+		expectedRespStatus int
+		expectedResponse   GetObjectAttributesResponse
+	}{
+		// Test case - 1.
+		// Fetch all attributes.
+		{
+			objectName:         objectName,
+			attributes:         "ETag,Checksum,ObjectSize,StorageClass",
+			expectedRespStatus: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ETag:         getMD5Hash(data),
+				Checksum:     &ObjectChecksum{ChecksumCRC32C: crcSum, ChecksumSHA256: sha256Sum},
+				StorageClass: globalMinioDefaultStorageClass,
+			},
+		},
+		// Test case - 2.
+		// Fetch only checksums.
+		{
+			objectName:         objectName,
+			attributes:         "Checksum",
+			expectedRespStatus: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				Checksum: &ObjectChecksum{ChecksumCRC32C: crcSum, ChecksumSHA256: sha256Sum},
+			},
+		},
+		// Test case - 3.
+		// Invalid attribute.
+		{
+			objectName:         objectName,
+			attributes:         "Unknown",
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 4.
+		// Non-existent object.
+		{
+			objectName:         "abcd",
+			attributes:         "ETag",
+			expectedRespStatus: http.StatusNotFound,
+		},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getGetObjectAttributesURL("", bucketName, testCase.objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Get Object Attributes: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set("X-Amz-Object-Attributes", testCase.attributes)
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var response GetObjectAttributesResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: Failed to parse response: <ERROR> %v", i+1, instanceType, err)
+		}
+		expected := testCase.expectedResponse
+		if response.ETag != expected.ETag || response.StorageClass != expected.StorageClass {
+			t.Fatalf("Test %d: %s: Expected %#v, got %#v", i+1, instanceType, expected, response)
+		}
+		if expected.Checksum != nil && (response.Checksum == nil || *response.Checksum != *expected.Checksum) {
+			t.Fatalf("Test %d: %s: Expected checksum %#v, got %#v", i+1, instanceType, expected.Checksum, response.Checksum)
+		}
+		if (response.ObjectSize != nil) != strings.Contains(testCase.attributes, "ObjectSize") {
+			t.Fatalf("Test %d: %s: Unexpected object size %v", i+1, instanceType, response.ObjectSize)
+		}
+		if response.ObjectSize != nil && *response.ObjectSize != int64(len(data)) {
+			t.Fatalf("Test %d: %s: Expected object size %d, got %d", i+1, instanceType, len(data), *response.ObjectSize)
+		}
+	}
+}
+
 // Wrapper for calling GetObject API handler tests for both XL multiple disks and FS single drive setup.
 func TestAPIGetObjectHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -263,7 +391,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			accessKey:  credentials.AccessKey,
 			secretKey:  credentials.SecretKey,
 
-			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrNoSuchKey), getGetObjectURL("", bucketName, "abcd"))),
+			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrNoSuchKey), getGetObjectURL("", bucketName, "abcd"), "")),
 			expectedRespStatus: http.StatusNotFound,
 		},
 		// Test case - 3.
@@ -287,7 +415,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			accessKey:  credentials.AccessKey,
 			secretKey:  credentials.SecretKey,
 
-			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidRange), getGetObjectURL("", bucketName, objectName))),
+			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidRange), getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusRequestedRangeNotSatisfiable,
 		},
 		// Test case - 5.
@@ -313,7 +441,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			accessKey:  "Invalid-AccessID",
 			secretKey:  credentials.SecretKey,
 
-			expectedContent:    encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidAccessKeyID), getGetObjectURL("", bucketName, objectName))),
+			expectedContent:    getInvalidAccessKeyIDResponse(getGetObjectURL("", bucketName, objectName), "Invalid-AccessID"),
 			expectedRespStatus: http.StatusForbidden,
 		},
 	}
@@ -344,6 +472,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if err != nil {
 			t.Fatalf("Test %d: %s: Failed parsing response body: <ERROR> %v", i+1, instanceType, err)
 		}
+		actualContent = blankRequestID(actualContent, rec)
 		// Verify whether the bucket obtained object is same as the one created.
 		if !bytes.Equal(testCase.expectedContent, actualContent) {
 			t.Errorf("Test %d: %s: Object content differs from expected value.: %s", i+1, instanceType, string(actualContent))
@@ -376,6 +505,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if err != nil {
 			t.Fatalf("Test %d: %s: Failed parsing response body: <ERROR> %v", i+1, instanceType, err)
 		}
+		actualContent = blankRequestID(actualContent, recV2)
 		// Verify whether the bucket obtained object is same as the one created.
 		if !bytes.Equal(testCase.expectedContent, actualContent) {
 			t.Errorf("Test %d: %s: Object content differs from expected value.", i+1, instanceType)
@@ -413,6 +543,122 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling GetObject API handler tests of response header overrides for both XL multiple disks and FS single drive setup.
+func TestAPIGetObjectRespHeadersHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIGetObjectRespHeadersHandler, []string{"GetObject"})
+}
+
+func testAPIGetObjectRespHeadersHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "report.bin"
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data),
+		map[string]string{"content-type": "application/octet-stream", "cache-control": "no-cache"}, ""); err != nil {
+		t.Fatalf("%s: Failed to create object: <ERROR> %v", instanceType, err)
+	}
+
+	overrides := url.Values{}
+	overrides.Set("response-content-type", "application/pdf")
+	overrides.Set("response-content-disposition", `attachment; filename="report.pdf"`)
+	overrides.Set("response-cache-control", "max-age=3600")
+	getURL := makeTestTargetURL("", bucketName, objectName, overrides)
+	expectedHeaders := map[string]string{
+		"Content-Type":        "application/pdf",
+		"Content-Disposition": `attachment; filename="report.pdf"`,
+		"Cache-Control":       "max-age=3600",
+	}
+
+	testCases := []struct {
+		newRequest         func() (*http.Request, error)
+		rangeHeader        string
+		expectedRespStatus int
+		expectedBody       []byte
+	}{
+		// Test case - 1.
+		// Signed request.
+		{
+			newRequest: func() (*http.Request, error) {
+				return newTestSignedRequestV4("GET", getURL, 0, nil, credentials.AccessKey, credentials.SecretKey)
+			},
+			expectedRespStatus: http.StatusOK,
+			expectedBody:       data,
+		},
+		// Test case - 2.
+		// Overrides also apply to ranged requests.
+		{
+			newRequest: func() (*http.Request, error) {
+				return newTestSignedRequestV4("GET", getURL, 0, nil, credentials.AccessKey, credentials.SecretKey)
+			},
+			rangeHeader:        "bytes=0-4",
+			expectedRespStatus: http.StatusPartialContent,
+			expectedBody:       data[:5],
+		},
+		// Test case - 3.
+		// Presigned request, as generated by the SDKs for download links.
+		{
+			newRequest: func() (*http.Request, error) {
+				req, err := newTestRequest("GET", getURL, 0, nil)
+				if err != nil {
+					return nil, err
+				}
+				return req, preSignV4(req, credentials.AccessKey, credentials.SecretKey, 3600)
+			},
+			expectedRespStatus: http.StatusOK,
+			expectedBody:       data,
+		},
+	}
+	for i, testCase := range testCases {
+		req, err := testCase.newRequest()
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Get Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.rangeHeader != "" {
+			req.Header.Set("Range", testCase.rangeHeader)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if !bytes.Equal(rec.Body.Bytes(), testCase.expectedBody) {
+			t.Errorf("Test %d: %s: Expected body %q, got %q", i+1, instanceType, testCase.expectedBody, rec.Body.Bytes())
+		}
+		for key, value := range expectedHeaders {
+			if got := rec.Header().Get(key); got != value {
+				t.Errorf("Test %d: %s: Expected %s to be %q, got %q", i+1, instanceType, key, value, got)
+			}
+		}
+	}
+
+	// Anonymous requests may read the object, but not override
+	// response headers.
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getReadOnlyObjectStatement(bucketName, "")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	for _, testCase := range []struct {
+		url                string
+		expectedRespStatus int
+	}{
+		{getGetObjectURL("", bucketName, objectName), http.StatusOK},
+		{getURL, http.StatusBadRequest},
+	} {
+		req, err := newTestRequest("GET", testCase.url, 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for Get Object: <ERROR> %v", instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("%s: Expected anonymous request for %s to return `%d`, but instead found `%d`", instanceType, testCase.url, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+}
+
 // Wrapper for calling PutObject API handler tests using streaming signature v4 for both XL multiple disks and FS single drive setup.
 func TestAPIPutObjectStreamSigV4Handler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -840,7 +1086,7 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			req.ContentLength = -1
 			req.TransferEncoding = []string{}
 		case TooBigObject:
-			req.ContentLength = maxObjectSize + 1
+			req.ContentLength = defaultMaxObjectSize + 1
 		}
 		// Since `apiRouter` satisfies `http.Handler` it has a ServeHTTP to execute the logic of the handler.
 		// Call the ServeHTTP to execute the handler,`func (api objectAPIHandlers) GetObjectHandler`  handles the request.
@@ -883,7 +1129,7 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			reqV2.ContentLength = -1
 			reqV2.TransferEncoding = []string{}
 		case TooBigObject:
-			reqV2.ContentLength = maxObjectSize + 1
+			reqV2.ContentLength = defaultMaxObjectSize + 1
 		}
 
 		// Since `apiRouter` satisfies `http.Handler` it has a ServeHTTP to execute the logic of the handler.
@@ -939,6 +1185,153 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 
 }
 
+// Wrapper for calling Put Object API handler tests of user metadata for both XL multiple disks and single node setup.
+func TestAPIPutObjectMetadataHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIPutObjectMetadataHandler, []string{"PutObject", "HeadObject"})
+}
+
+func testAPIPutObjectMetadataHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := []byte("hello")
+
+	testCases := []struct {
+		objectName         string
+		metadata           http.Header
+		expectedRespStatus int
+		expectedMetadata   map[string]string
+	}{
+		// Test case - 1.
+		// UTF-8 values are RFC 2047 encoded in responses, repeated
+		// headers are joined.
+		{
+			objectName: "object-1",
+			metadata: http.Header{
+				"X-Amz-Meta-Name":  []string{"héllo wörld"},
+				"X-Amz-Meta-Color": []string{"red", "blue"},
+			},
+			expectedRespStatus: http.StatusOK,
+			expectedMetadata: map[string]string{
+				"X-Amz-Meta-Name":  "=?UTF-8?b?aMOpbGxvIHfDtnJsZA==?=",
+				"X-Amz-Meta-Color": "red,blue",
+			},
+		},
+		// Test case - 2.
+		// Metadata exceeding the limit is rejected.
+		{
+			objectName: "object-2",
+			metadata: http.Header{
+				"X-Amz-Meta-Large": []string{strings.Repeat("a", defaultMaxUserMetadataSize)},
+			},
+			expectedRespStatus: http.StatusBadRequest,
+		},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		for key, values := range testCase.metadata {
+			req.Header[key] = values
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			if !strings.Contains(rec.Body.String(), "<Code>MetadataTooLarge</Code>") {
+				t.Fatalf("Test %d: %s: Expected MetadataTooLarge error, got %s", i+1, instanceType, rec.Body.String())
+			}
+			continue
+		}
+
+		rec = httptest.NewRecorder()
+		req, err = newTestSignedRequestV4("HEAD", getHeadObjectURL("", bucketName, testCase.objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Head Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, http.StatusOK, rec.Code)
+		}
+		for key, value := range testCase.expectedMetadata {
+			if got := rec.Header().Get(key); got != value {
+				t.Errorf("Test %d: %s: Expected %s to be %q, got %q", i+1, instanceType, key, value, got)
+			}
+		}
+	}
+}
+
+// Wrapper for calling Put Object API handler tests of uploads without Content-Length for both XL multiple disks and single node setup.
+func TestAPIPutObjectChunkedHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIPutObjectChunkedHandler, []string{"PutObject"})
+}
+
+func testAPIPutObjectChunkedHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	data := generateBytesData(6 * humanize.MiByte)
+
+	defer func(maxSize int64) { globalMaxChunkedUploadSize = maxSize }(globalMaxChunkedUploadSize)
+	globalMaxChunkedUploadSize = int64(len(data))
+
+	testCases := []struct {
+		objectName         string
+		data               []byte
+		chunked            bool
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Chunked upload is committed with the size read.
+		{"object-1", data, true, http.StatusOK},
+		// Test case - 2.
+		// Empty chunked upload.
+		{"object-2", nil, true, http.StatusOK},
+		// Test case - 3.
+		// Missing Content-Length without chunked encoding.
+		{"object-3", data, false, http.StatusLengthRequired},
+		// Test case - 4.
+		// Chunked upload exceeding the limit.
+		{"object-4", append(data, 'a'), true, http.StatusBadRequest},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(testCase.data)), bytes.NewReader(testCase.data), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Put Object: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.ContentLength = -1
+		if testCase.chunked {
+			req.TransferEncoding = []string{"chunked"}
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		objInfo, err := obj.GetObjectInfo(bucketName, testCase.objectName)
+		if rec.Code != http.StatusOK {
+			if err == nil {
+				t.Fatalf("Test %d: %s: Expected failed upload to not create the object", i+1, instanceType)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to get object info: <ERROR> %v", i+1, instanceType, err)
+		}
+		if objInfo.Size != int64(len(testCase.data)) {
+			t.Fatalf("Test %d: %s: Expected size %d, got %d", i+1, instanceType, len(testCase.data), objInfo.Size)
+		}
+		if objInfo.MD5Sum != getMD5Hash(testCase.data) {
+			t.Fatalf("Test %d: %s: Expected md5sum %s, got %s", i+1, instanceType, getMD5Hash(testCase.data), objInfo.MD5Sum)
+		}
+	}
+}
+
 // Wrapper for calling Copy Object Part API handler tests for both XL multiple disks and single node setup.
 func TestAPICopyObjectPartHandler(t *testing.T) {
 	defer DetectTestLeak(t)()
@@ -1504,6 +1897,17 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 				t.Errorf("Test %d: %s: Data Mismatch: Data fetched back from the copied object doesn't match the original one.", i+1, instanceType)
 			}
 			buffers[0].Reset()
+
+			// Source ETag and Last-Modified are preserved on the copy.
+			if testCase.newObjectName != objectName {
+				newObjInfo, ierr := obj.GetObjectInfo(testCase.bucketName, testCase.newObjectName)
+				if ierr != nil {
+					t.Fatalf("Test %d: %s: Failed to fetch the copied object info: <ERROR> %s", i+1, instanceType, ierr)
+				}
+				if newObjInfo.UserDefined[minioSourceETagKey] == "" || newObjInfo.UserDefined[minioSourceLastModifiedKey] == "" {
+					t.Errorf("Test %d: %s: Source metadata was not preserved, found %v", i+1, instanceType, newObjInfo.UserDefined)
+				}
+			}
 		}
 
 		// Verify response of the V2 signed HTTP request.
@@ -1958,7 +2362,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			secretKey: credentials.SecretKey,
 
 			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(toAPIErrorCode(BadDigest{})),
-				getGetObjectURL("", bucketName, objectName))),
+				getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusBadRequest,
 		},
 		// Test case - 2.
@@ -1973,7 +2377,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			secretKey: credentials.SecretKey,
 
 			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(ErrMalformedXML),
-				getGetObjectURL("", bucketName, objectName))),
+				getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusBadRequest,
 		},
 		// Test case - 3.
@@ -1988,7 +2392,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			secretKey: credentials.SecretKey,
 
 			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(toAPIErrorCode(InvalidUploadID{UploadID: "abc"})),
-				getGetObjectURL("", bucketName, objectName))),
+				getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusNotFound,
 		},
 		// Test case - 4.
@@ -2003,7 +2407,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 
 			expectedContent: encodeResponse(completeMultipartAPIError{int64(4), int64(5242880), 1, "e2fc714c4727ee9395f324cd2e7f331f",
 				getAPIErrorResponse(getAPIError(toAPIErrorCode(PartTooSmall{PartNumber: 1})),
-					getGetObjectURL("", bucketName, objectName))}),
+					getGetObjectURL("", bucketName, objectName), "")}),
 			expectedRespStatus: http.StatusBadRequest,
 		},
 		// Test case - 5.
@@ -2017,7 +2421,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			secretKey: credentials.SecretKey,
 
 			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(toAPIErrorCode(InvalidPart{})),
-				getGetObjectURL("", bucketName, objectName))),
+				getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusBadRequest,
 		},
 		// Test case - 6.
@@ -2032,7 +2436,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			secretKey: credentials.SecretKey,
 
 			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidPartOrder),
-				getGetObjectURL("", bucketName, objectName))),
+				getGetObjectURL("", bucketName, objectName), "")),
 			expectedRespStatus: http.StatusBadRequest,
 		},
 		// Test case - 7.
@@ -2046,8 +2450,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 			accessKey: "Invalid-AccessID",
 			secretKey: credentials.SecretKey,
 
-			expectedContent: encodeResponse(getAPIErrorResponse(getAPIError(ErrInvalidAccessKeyID),
-				getGetObjectURL("", bucketName, objectName))),
+			expectedContent:    getInvalidAccessKeyIDResponse(getGetObjectURL("", bucketName, objectName), "Invalid-AccessID"),
 			expectedRespStatus: http.StatusForbidden,
 		},
 		// Test case - 8.
@@ -2099,6 +2502,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 		if err != nil {
 			t.Fatalf("Test %d : Minio %s: Failed parsing response body: <ERROR> %v", i+1, instanceType, err)
 		}
+		actualContent = blankRequestID(actualContent, rec)
 		// Verify whether the bucket obtained object is same as the one created.
 		if !bytes.Equal(testCase.expectedContent, actualContent) {
 			t.Errorf("Test %d : Minio %s: Object content differs from expected value.", i+1, instanceType)
@@ -2722,7 +3126,7 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 		{
 			objectName: testObject,
 			reader:     bytes.NewReader([]byte("hello")),
-			partNumber: strconv.Itoa(maxPartID + 1),
+			partNumber: strconv.Itoa(defaultMaxPartID + 1),
 			fault:      None,
 			accessKey:  credentials.AccessKey,
 			secretKey:  credentials.SecretKey,
@@ -2880,7 +3284,7 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 					// Setting the content length to a value greater than the max allowed size of a part.
 					// Used in test case  4.
 				case TooBigObject:
-					req.ContentLength = maxObjectSize + 1
+					req.ContentLength = defaultMaxPartSize + 1
 					// Malformed signature.
 					// Used in test case  6.
 				case BadSignature:
@@ -0,0 +1,222 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"sort"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// nfcObjects normalizes names of new objects to Unicode NFC, so that
+// clients which decompose names (e.g. macOS, which uses NFD) do not
+// create objects visually identical to existing ones. Objects stored
+// under non normalized names before normalization was enabled remain
+// accessible under their original names.
+type nfcObjects struct {
+	ObjectLayer
+}
+
+// newNFCObjectLayer - wraps objAPI to normalize object names.
+func newNFCObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	return nfcObjects{objAPI}
+}
+
+// resolveObjectName - returns the name an existing object is stored
+// under, the normalized name is preferred over the name as given, which
+// is preferred over the decomposed (NFD) name objects stored before
+// normalization was enabled may have. Returns the name as given if no
+// object exists under any of them.
+func (l nfcObjects) resolveObjectName(bucket, object string) string {
+	nfcObject := norm.NFC.String(object)
+	nfdObject := norm.NFD.String(object)
+	if nfcObject == object && nfdObject == object {
+		return object
+	}
+	names := []string{nfcObject}
+	if object != nfcObject {
+		names = append(names, object)
+	}
+	if nfdObject != object && nfdObject != nfcObject {
+		names = append(names, nfdObject)
+	}
+	for _, name := range names {
+		if _, err := l.ObjectLayer.GetObjectInfo(bucket, name); !isErrObjectNotFound(err) {
+			return name
+		}
+	}
+	return object
+}
+
+// normalizeListArgs - returns prefix normalized as object names are
+// and marker listing under it. Markers are positions in the listing of
+// stored names, which may be non normalized, so only the part of
+// marker which is prefix is normalized along with it.
+func normalizeListArgs(prefix, marker string) (string, string) {
+	nfcPrefix := norm.NFC.String(prefix)
+	if nfcPrefix != prefix && hasPrefix(marker, prefix) {
+		marker = nfcPrefix + marker[len(prefix):]
+	}
+	return nfcPrefix, marker
+}
+
+// ListObjects - lists objects under the normalized prefix.
+func (l nfcObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	prefix, marker = normalizeListArgs(prefix, marker)
+	return l.ObjectLayer.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+}
+
+// ListObjectVersions - lists versions of objects under the normalized prefix.
+func (l nfcObjects) ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	prefix, keyMarker = normalizeListArgs(prefix, keyMarker)
+	return l.ObjectLayer.ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+}
+
+// ListMultipartUploads - lists uploads of objects under the normalized prefix.
+func (l nfcObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	prefix, keyMarker = normalizeListArgs(prefix, keyMarker)
+	return l.ObjectLayer.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+}
+
+// GetObject - reads an object by its resolved name.
+func (l nfcObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	return l.ObjectLayer.GetObject(bucket, l.resolveObjectName(bucket, object), startOffset, length, writer)
+}
+
+// GetObjectInfo - returns info of an object by its resolved name.
+func (l nfcObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	return l.ObjectLayer.GetObjectInfo(bucket, l.resolveObjectName(bucket, object))
+}
+
+// PutObject - creates an object under the normalized name.
+func (l nfcObjects) PutObject(bucket, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (ObjectInfo, error) {
+	return l.ObjectLayer.PutObject(bucket, norm.NFC.String(object), size, data, metadata, sha256sum)
+}
+
+// CopyObject - copies an object by its resolved name to the normalized name.
+func (l nfcObjects) CopyObject(srcBucket, srcObject, destBucket, destObject string, metadata map[string]string) (ObjectInfo, error) {
+	return l.ObjectLayer.CopyObject(srcBucket, l.resolveObjectName(srcBucket, srcObject), destBucket, norm.NFC.String(destObject), metadata)
+}
+
+// DeleteObject - deletes an object by its resolved name.
+func (l nfcObjects) DeleteObject(bucket, object string) error {
+	return l.ObjectLayer.DeleteObject(bucket, l.resolveObjectName(bucket, object))
+}
+
+// GetObjectVersion - reads a version of an object by its resolved name.
+func (l nfcObjects) GetObjectVersion(bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	return l.ObjectLayer.GetObjectVersion(bucket, l.resolveObjectName(bucket, object), versionID, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - returns info of a version of an object by its resolved name.
+func (l nfcObjects) GetObjectVersionInfo(bucket, object, versionID string) (ObjectInfo, error) {
+	return l.ObjectLayer.GetObjectVersionInfo(bucket, l.resolveObjectName(bucket, object), versionID)
+}
+
+// DeleteObjectVersion - deletes a version of an object by its resolved name.
+func (l nfcObjects) DeleteObjectVersion(bucket, object, versionID string) error {
+	return l.ObjectLayer.DeleteObjectVersion(bucket, l.resolveObjectName(bucket, object), versionID)
+}
+
+// NewMultipartUpload - initiates a multipart upload of the normalized name.
+func (l nfcObjects) NewMultipartUpload(bucket, object string, metadata map[string]string) (string, error) {
+	return l.ObjectLayer.NewMultipartUpload(bucket, norm.NFC.String(object), metadata)
+}
+
+// CopyObjectPart - copies a part of an object by its resolved name.
+func (l nfcObjects) CopyObjectPart(srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int, startOffset int64, length int64) (PartInfo, error) {
+	return l.ObjectLayer.CopyObjectPart(srcBucket, l.resolveObjectName(srcBucket, srcObject), destBucket, norm.NFC.String(destObject), uploadID, partID, startOffset, length)
+}
+
+// PutObjectPart - uploads a part of the normalized name.
+func (l nfcObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (PartInfo, error) {
+	return l.ObjectLayer.PutObjectPart(bucket, norm.NFC.String(object), uploadID, partID, size, data, md5Hex, sha256sum)
+}
+
+// ListObjectParts - lists parts of an upload of the normalized name.
+func (l nfcObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker int, maxParts int) (ListPartsInfo, error) {
+	return l.ObjectLayer.ListObjectParts(bucket, norm.NFC.String(object), uploadID, partNumberMarker, maxParts)
+}
+
+// AbortMultipartUpload - aborts an upload of the normalized name.
+func (l nfcObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+	return l.ObjectLayer.AbortMultipartUpload(bucket, norm.NFC.String(object), uploadID)
+}
+
+// CompleteMultipartUpload - completes an upload of the normalized name.
+func (l nfcObjects) CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []completePart) (ObjectInfo, error) {
+	return l.ObjectLayer.CompleteMultipartUpload(bucket, norm.NFC.String(object), uploadID, uploadedParts)
+}
+
+// HealObject - heals an object by its resolved name.
+func (l nfcObjects) HealObject(bucket, object string) error {
+	return l.ObjectLayer.HealObject(bucket, l.resolveObjectName(bucket, object))
+}
+
+// unicodeDuplicate - objects whose names differ only in Unicode
+// normalization, Name is the NFC normalized name.
+type unicodeDuplicate struct {
+	Name    string   `json:"name"`
+	Objects []string `json:"objects"`
+}
+
+// listUnicodeDuplicates - lists objects of bucket under prefix whose
+// names are identical once normalized to NFC. Every such group has
+// at least one non normalized name, so only those are kept around
+// while listing.
+func listUnicodeDuplicates(objAPI ObjectLayer, bucket, prefix string) ([]unicodeDuplicate, error) {
+	denormalized := make(map[string][]string)
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, objInfo := range result.Objects {
+			if !norm.NFC.IsNormalString(objInfo.Name) {
+				nfcName := norm.NFC.String(objInfo.Name)
+				denormalized[nfcName] = append(denormalized[nfcName], objInfo.Name)
+			}
+		}
+		if !result.IsTruncated || len(result.Objects) == 0 {
+			break
+		}
+		marker = result.Objects[len(result.Objects)-1].Name
+	}
+
+	nfcNames := make([]string, 0, len(denormalized))
+	for nfcName := range denormalized {
+		nfcNames = append(nfcNames, nfcName)
+	}
+	sort.Strings(nfcNames)
+
+	duplicates := []unicodeDuplicate{}
+	for _, nfcName := range nfcNames {
+		objects := denormalized[nfcName]
+		_, err := objAPI.GetObjectInfo(bucket, nfcName)
+		if err == nil {
+			objects = append([]string{nfcName}, objects...)
+		} else if !isErrObjectNotFound(err) {
+			return nil, err
+		}
+		if len(objects) > 1 {
+			duplicates = append(duplicates, unicodeDuplicate{Name: nfcName, Objects: objects})
+		}
+	}
+	return duplicates, nil
+}
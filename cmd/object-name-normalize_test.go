@@ -0,0 +1,185 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// "cafe" with a precomposed and a decomposed accented "e".
+const (
+	nfcCafe = "caf\u00e9"
+	nfdCafe = "cafe\u0301"
+)
+
+// Wrapper for calling object name normalization tests for both XL and FS.
+func TestNFCObjectLayer(t *testing.T) {
+	ExecObjectLayerTest(t, testNFCObjectLayer)
+}
+
+// Tests that new objects are normalized while existing non normalized
+// objects remain accessible.
+func testNFCObjectLayer(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	nfcObj := newNFCObjectLayer(obj)
+	data := []byte("hello")
+
+	// Object stored under a decomposed name before normalization.
+	if _, err := obj.PutObject(bucket, "legacy/"+nfdCafe, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err := nfcObj.GetObjectInfo(bucket, "legacy/"+nfdCafe); err != nil {
+		t.Errorf("%s: Expected legacy object to remain accessible, got %v", instanceType, err)
+	}
+	// Also under its normalized name, as long as no object is stored under it.
+	if objInfo, err := nfcObj.GetObjectInfo(bucket, "legacy/"+nfcCafe); err != nil || objInfo.Name != "legacy/"+nfdCafe {
+		t.Errorf("%s: Expected legacy object to be accessible by its normalized name, got %q, %v", instanceType, objInfo.Name, err)
+	}
+
+	// New objects are stored under the normalized name and are
+	// accessible under both names.
+	objInfo, err := nfcObj.PutObject(bucket, "new/"+nfdCafe, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.Name != "new/"+nfcCafe {
+		t.Errorf("%s: Expected object name %q, got %q", instanceType, "new/"+nfcCafe, objInfo.Name)
+	}
+	if _, err = obj.GetObjectInfo(bucket, "new/"+nfdCafe); !isErrObjectNotFound(err) {
+		t.Errorf("%s: Expected no object under the decomposed name, got %v", instanceType, err)
+	}
+	var buf bytes.Buffer
+	if err = nfcObj.GetObject(bucket, "new/"+nfdCafe, 0, int64(len(data)), &buf); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("%s: Expected %q, got %q", instanceType, data, buf.Bytes())
+	}
+
+	// Prefixes of listings are normalized too.
+	listResult, err := nfcObj.ListObjects(bucket, "new/"+nfdCafe, "", "", 10)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(listResult.Objects) != 1 || listResult.Objects[0].Name != "new/"+nfcCafe {
+		t.Errorf("%s: Expected %q listed under the decomposed prefix, got %v", instanceType, "new/"+nfcCafe, listResult.Objects)
+	}
+
+	// Multipart uploads are normalized too.
+	uploadID, err := nfcObj.NewMultipartUpload(bucket, "multipart/"+nfdCafe, nil)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	uploadsResult, err := nfcObj.ListMultipartUploads(bucket, "multipart/"+nfdCafe, "", "", "", 10)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(uploadsResult.Uploads) != 1 || uploadsResult.Uploads[0].Object != "multipart/"+nfcCafe {
+		t.Errorf("%s: Expected the upload listed under the decomposed prefix, got %v", instanceType, uploadsResult.Uploads)
+	}
+	partInfo, err := nfcObj.PutObjectPart(bucket, "multipart/"+nfdCafe, uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err = nfcObj.CompleteMultipartUpload(bucket, "multipart/"+nfdCafe, uploadID, []completePart{{PartNumber: 1, ETag: partInfo.ETag}}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if _, err = obj.GetObjectInfo(bucket, "multipart/"+nfcCafe); err != nil {
+		t.Errorf("%s: Expected multipart object under the normalized name, got %v", instanceType, err)
+	}
+
+	if err = nfcObj.DeleteObject(bucket, "new/"+nfdCafe); err != nil {
+		t.Errorf("%s: Expected object to be deleted by its decomposed name, got %v", instanceType, err)
+	}
+}
+
+// Tests normalization of the prefix and marker of listings.
+func TestNormalizeListArgs(t *testing.T) {
+	testCases := []struct {
+		prefix, marker                 string
+		expectedPrefix, expectedMarker string
+	}{
+		{"", "", "", ""},
+		{"", nfdCafe, "", nfdCafe},
+		{nfdCafe, "", nfcCafe, ""},
+		// Only the part of the marker which is the prefix is normalized.
+		{nfdCafe + "/", nfdCafe + "/" + nfdCafe, nfcCafe + "/", nfcCafe + "/" + nfdCafe},
+		{nfcCafe, nfdCafe, nfcCafe, nfdCafe},
+	}
+	for i, testCase := range testCases {
+		prefix, marker := normalizeListArgs(testCase.prefix, testCase.marker)
+		if prefix != testCase.expectedPrefix || marker != testCase.expectedMarker {
+			t.Errorf("Test %d: Expected %q, %q, got %q, %q", i+1, testCase.expectedPrefix, testCase.expectedMarker, prefix, marker)
+		}
+	}
+}
+
+// Wrapper for calling unicode duplicates listing tests for both XL and FS.
+func TestListUnicodeDuplicates(t *testing.T) {
+	ExecObjectLayerTest(t, testListUnicodeDuplicates)
+}
+
+// Tests listing objects whose names differ only in Unicode normalization.
+func testListUnicodeDuplicates(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	data := []byte("hello")
+	for _, object := range []string{"dup/" + nfcCafe, "dup/" + nfdCafe, "single/" + nfdCafe, "plain"} {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	duplicates, err := listUnicodeDuplicates(obj, bucket, "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	expected := []unicodeDuplicate{
+		{Name: "dup/" + nfcCafe, Objects: []string{"dup/" + nfcCafe, "dup/" + nfdCafe}},
+	}
+	if !reflect.DeepEqual(duplicates, expected) {
+		t.Errorf("%s: Expected %q, got %q", instanceType, expected, duplicates)
+	}
+
+	// No duplicates under another prefix.
+	if duplicates, err = listUnicodeDuplicates(obj, bucket, "single/"); err != nil || len(duplicates) != 0 {
+		t.Errorf("%s: Expected no duplicates, got %q, %v", instanceType, duplicates, err)
+	}
+}
+
+// Tests that the object layer is wrapped for name normalization only
+// if enabled.
+func TestWrapObjectLayerNFC(t *testing.T) {
+	defer func(normalize bool) { globalNormalizeObjectNames = normalize }(globalNormalizeObjectNames)
+
+	var objAPI ObjectLayer = &fsObjects{}
+	globalNormalizeObjectNames = false
+	if _, ok := wrapObjectLayer(objAPI).(nfcObjects); ok {
+		t.Error("Expected object layer not to normalize names")
+	}
+	globalNormalizeObjectNames = true
+	if _, ok := wrapObjectLayer(objAPI).(nfcObjects); !ok {
+		t.Error("Expected object layer to normalize names")
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+const (
+	// Default maximum object name length in strict names mode, in
+	// characters, keeps mounted paths within Windows MAX_PATH.
+	defaultStrictNameMaxLength = 255
+
+	// Maximum length of each name component, in characters.
+	strictNameMaxComponentLength = 255
+
+	// Characters not allowed in file names on Windows/SMB.
+	strictNameReservedChars = `<>:"\|?*`
+)
+
+// Device names reserved on Windows, with or without an extension.
+var strictNameReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// strictNamesConfig - enables strict names mode on buckets consumed
+// over Windows/SMB or NFS, where new objects must have names which
+// are valid file paths on those systems. Existing objects are never
+// affected.
+type strictNamesConfig struct {
+	// Buckets in strict names mode, '*' wildcards are supported.
+	Buckets []string `json:"buckets"`
+
+	// Maximum object name length in characters, defaults to 255.
+	MaxNameLength int `json:"maxNameLength"`
+}
+
+// strictNamesPolicy - validated form of strictNamesConfig.
+type strictNamesPolicy struct {
+	buckets       []string
+	maxNameLength int
+}
+
+// newStrictNamesPolicy - validates config, returns nil if no bucket
+// is in strict names mode.
+func newStrictNamesPolicy(config strictNamesConfig) (*strictNamesPolicy, error) {
+	if config.MaxNameLength < 0 {
+		return nil, fmt.Errorf("Invalid maximum object name length %d", config.MaxNameLength)
+	}
+	if len(config.Buckets) == 0 {
+		return nil, nil
+	}
+	policy := &strictNamesPolicy{
+		buckets:       config.Buckets,
+		maxNameLength: config.MaxNameLength,
+	}
+	if policy.maxNameLength == 0 {
+		policy.maxNameLength = defaultStrictNameMaxLength
+	}
+	return policy, nil
+}
+
+// isStrictBucket - returns true if bucket is in strict names mode.
+func (p *strictNamesPolicy) isStrictBucket(bucket string) bool {
+	if isMinioMetaBucketName(bucket) {
+		return false
+	}
+	for _, pattern := range p.buckets {
+		if wildcard.MatchSimple(pattern, bucket) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidStrictName - returns true if object is a valid file path on
+// Windows/SMB and NFS consumers.
+func isValidStrictName(object string, maxNameLength int) bool {
+	if utf8.RuneCountInString(object) > maxNameLength {
+		return false
+	}
+	for _, r := range object {
+		if r < 0x20 || strings.ContainsRune(strictNameReservedChars, r) {
+			return false
+		}
+	}
+	for _, component := range strings.Split(object, slashSeparator) {
+		if component == "" || component == "." || component == ".." {
+			return false
+		}
+		if utf8.RuneCountInString(component) > strictNameMaxComponentLength {
+			return false
+		}
+		// Windows silently strips trailing dots and spaces.
+		if strings.HasSuffix(component, ".") || strings.HasSuffix(component, " ") {
+			return false
+		}
+		baseName := strings.ToUpper(strings.SplitN(component, ".", 2)[0])
+		if _, ok := strictNameReservedNames[baseName]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStrictObjectName - returns an error if bucket is in strict
+// names mode and object is not a valid strict name, a nil policy
+// allows everything.
+func (p *strictNamesPolicy) checkStrictObjectName(bucket, object string) error {
+	if p == nil || !p.isStrictBucket(bucket) {
+		return nil
+	}
+	if !isValidStrictName(object, p.maxNameLength) {
+		return traceError(ObjectNameNotAllowed{Bucket: bucket, Object: object})
+	}
+	return nil
+}
+
+// initStrictNamesPolicy - initializes the global strict names policy
+// from server config.
+func initStrictNamesPolicy() error {
+	policy, err := newStrictNamesPolicy(serverConfig.GetStrictNames())
+	if err != nil {
+		return err
+	}
+	globalStrictNamesPolicy = policy
+	return nil
+}
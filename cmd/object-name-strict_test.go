@@ -0,0 +1,128 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Tests strict object name validation.
+func TestIsValidStrictName(t *testing.T) {
+	testCases := []struct {
+		object string
+		valid  bool
+	}{
+		{"photos/2017/january/sample.jpg", true},
+		{"reports/Q1 summary.pdf", true},
+		{"console.log", true},
+		// Characters invalid on Windows.
+		{"photos/a:b.jpg", false},
+		{"photos/a?b.jpg", false},
+		{"photos/a*b.jpg", false},
+		{"photos/a|b.jpg", false},
+		{"photos/\"quoted\".jpg", false},
+		{"photos/<tag>.jpg", false},
+		{"photos/tab\tname.jpg", false},
+		// Empty and relative components.
+		{"photos//sample.jpg", false},
+		{"photos/./sample.jpg", false},
+		{"photos/../sample.jpg", false},
+		// Trailing dots and spaces.
+		{"photos./sample.jpg", false},
+		{"photos/sample.jpg ", false},
+		// Reserved device names.
+		{"CON", false},
+		{"photos/nul.txt", false},
+		{"photos/Com1.jpg", false},
+		{"photos/lpt9", false},
+		// Length limits.
+		{strings.Repeat("a", 255), true},
+		{strings.Repeat("a", 256), false},
+		{strings.Repeat("a/", 127) + "a", true},
+	}
+
+	for i, testCase := range testCases {
+		if valid := isValidStrictName(testCase.object, defaultStrictNameMaxLength); valid != testCase.valid {
+			t.Errorf("Test %d: Expected %q to be valid %v, got %v", i+1, testCase.object, testCase.valid, valid)
+		}
+	}
+}
+
+// Tests validation of strict names configuration.
+func TestNewStrictNamesPolicy(t *testing.T) {
+	if policy, err := newStrictNamesPolicy(strictNamesConfig{}); policy != nil || err != nil {
+		t.Errorf("Expected nil policy without buckets, got %v, %v", policy, err)
+	}
+	if _, err := newStrictNamesPolicy(strictNamesConfig{Buckets: []string{"*"}, MaxNameLength: -1}); err == nil {
+		t.Error("Expected negative maximum name length to fail")
+	}
+	policy, err := newStrictNamesPolicy(strictNamesConfig{Buckets: []string{"smb-*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.maxNameLength != defaultStrictNameMaxLength {
+		t.Errorf("Expected default maximum name length %d, got %d", defaultStrictNameMaxLength, policy.maxNameLength)
+	}
+	if !policy.isStrictBucket("smb-share") || policy.isStrictBucket("photos") || policy.isStrictBucket(minioMetaBucket) {
+		t.Error("Unexpected strict names mode of buckets")
+	}
+}
+
+// Wrapper for calling strict object names tests for both XL and FS.
+func TestStrictObjectNames(t *testing.T) {
+	ExecObjectLayerTest(t, testStrictObjectNames)
+}
+
+// Tests that objects are validated only in buckets in strict names mode.
+func testStrictObjectNames(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	for _, bucket := range []string{"smb-share", "photos"} {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("%s: Failed to make bucket %s - %v", instanceType, bucket, err)
+		}
+	}
+
+	policy, err := newStrictNamesPolicy(strictNamesConfig{Buckets: []string{"smb-*"}})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	globalStrictNamesPolicy = policy
+	defer func() { globalStrictNamesPolicy = nil }()
+
+	data := []byte("hello")
+	testCases := []struct {
+		bucket  string
+		object  string
+		allowed bool
+	}{
+		{"smb-share", "docs/report.txt", true},
+		{"smb-share", "docs/report?.txt", false},
+		{"photos", "docs/report?.txt", true},
+	}
+
+	for i, testCase := range testCases {
+		_, err = obj.PutObject(testCase.bucket, testCase.object, int64(len(data)), bytes.NewReader(data), nil, "")
+		if _, notAllowed := errorCause(err).(ObjectNameNotAllowed); testCase.allowed == notAllowed || (testCase.allowed && err != nil) {
+			t.Errorf("Test %d: %s: Expected PutObject allowed %v, got %v", i+1, instanceType, testCase.allowed, err)
+		}
+		_, err = obj.NewMultipartUpload(testCase.bucket, testCase.object, nil)
+		if _, notAllowed := errorCause(err).(ObjectNameNotAllowed); testCase.allowed == notAllowed || (testCase.allowed && err != nil) {
+			t.Errorf("Test %d: %s: Expected NewMultipartUpload allowed %v, got %v", i+1, instanceType, testCase.allowed, err)
+		}
+	}
+}
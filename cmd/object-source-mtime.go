@@ -0,0 +1,68 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// Minio extension header on PutObject and CopyObject, sets the
+	// modification time of the object to the given RFC 3339 time
+	// instead of the time it is written, for migration tools which
+	// preserve the timestamps of the original objects.
+	minioSourceMtimeHeader = "X-Minio-Source-Mtime"
+
+	// Metadata key handing the requested modification time of an
+	// object to the object layer, which removes it before saving the
+	// metadata.
+	objectModTimeKey = "x-minio-internal-mtime"
+)
+
+// extractSourceMtimeFromHeader - returns the modification time asked
+// for with X-Minio-Source-Mtime, zero if not set. Only signed requests
+// may set it, anonymous uploads allowed by the bucket policy may not.
+func extractSourceMtimeFromHeader(r *http.Request) (time.Time, APIErrorCode) {
+	value := r.Header.Get(minioSourceMtimeHeader)
+	if value == "" {
+		return time.Time{}, ErrNone
+	}
+	if getRequestAuthType(r) == authTypeAnonymous {
+		return time.Time{}, ErrAccessDenied
+	}
+	modTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || modTime.After(time.Now().UTC()) {
+		return time.Time{}, ErrInvalidSourceMtime
+	}
+	return modTime.UTC(), ErrNone
+}
+
+// popObjectModTime - removes the requested modification time of an
+// object from metadata, returns false if none was requested.
+func popObjectModTime(metadata map[string]string) (time.Time, bool) {
+	value, ok := metadata[objectModTimeKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	delete(metadata, objectModTimeKey)
+	modTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return modTime, true
+}
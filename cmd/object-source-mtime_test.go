@@ -0,0 +1,118 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling source mtime tests for both XL multiple disks and single node setup.
+func TestSourceMtime(t *testing.T) {
+	ExecObjectLayerAPITest(t, testSourceMtime, []string{"CopyObject", "PutObject"})
+}
+
+// testSourceMtime - Tests that X-Minio-Source-Mtime sets the
+// modification time of uploaded and copied objects.
+func testSourceMtime(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	mtime := time.Date(2015, 3, 14, 9, 26, 53, 0, time.UTC)
+	data := []byte("hello")
+
+	newRequest := func(method, object, mtimeValue string, header map[string]string, signed bool) *http.Request {
+		var body []byte
+		if method == "PUT" && header["X-Amz-Copy-Source"] == "" {
+			body = data
+		}
+		req, err := newTestRequest(method, makeTestTargetURL("", bucketName, object, nil), int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("%s: Failed to create request: <ERROR> %v", instanceType, err)
+		}
+		if mtimeValue != "" {
+			req.Header.Set(minioSourceMtimeHeader, mtimeValue)
+		}
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		if signed {
+			if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+				t.Fatalf("%s: Failed to sign request: <ERROR> %v", instanceType, err)
+			}
+		}
+		return req
+	}
+
+	// Anonymous uploads allowed by the bucket policy cannot set the
+	// modification time.
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getWriteOnlyObjectStatement(bucketName, "")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	testCases := []struct {
+		object     string
+		mtime      string
+		header     map[string]string
+		signed     bool
+		expectCode int
+		modTime    time.Time
+	}{
+		{"upload", mtime.Format(time.RFC3339), nil, true, http.StatusOK, mtime},
+		{"upload-nano", mtime.Add(time.Millisecond).Format(time.RFC3339Nano), nil, true, http.StatusOK, mtime.Add(time.Millisecond)},
+		{"copy", mtime.Add(time.Hour).Format(time.RFC3339), map[string]string{"X-Amz-Copy-Source": "/" + bucketName + "/upload"}, true, http.StatusOK, mtime.Add(time.Hour)},
+		// Metadata only copies change the modification time too.
+		{"upload", mtime.Add(-time.Hour).Format(time.RFC3339), map[string]string{"X-Amz-Copy-Source": "/" + bucketName + "/upload", "X-Amz-Metadata-Directive": "REPLACE"}, true, http.StatusOK, mtime.Add(-time.Hour)},
+		{"malformed", "yesterday", nil, true, http.StatusBadRequest, time.Time{}},
+		{"future", time.Now().UTC().Add(time.Hour).Format(time.RFC3339), nil, true, http.StatusBadRequest, time.Time{}},
+		{"anonymous", mtime.Format(time.RFC3339), nil, false, http.StatusForbidden, time.Time{}},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, newRequest("PUT", testCase.object, testCase.mtime, testCase.header, testCase.signed))
+		if rec.Code != testCase.expectCode {
+			t.Fatalf("%s: Test %d: Expected the response status to be `%d`, but instead found `%d`: %s", instanceType, i+1, testCase.expectCode, rec.Code, rec.Body.String())
+		}
+		if testCase.expectCode != http.StatusOK {
+			continue
+		}
+		objInfo, err := obj.GetObjectInfo(bucketName, testCase.object)
+		if err != nil {
+			t.Fatalf("%s: Test %d: Failed to get object info: <ERROR> %v", instanceType, i+1, err)
+		}
+		if !objInfo.ModTime.Equal(testCase.modTime) {
+			t.Errorf("%s: Test %d: Expected modification time %s, got %s", instanceType, i+1, testCase.modTime, objInfo.ModTime)
+		}
+		if _, ok := objInfo.UserDefined[minioSourceMtimeHeader]; ok {
+			t.Errorf("%s: Test %d: Expected modification time not to be saved as metadata, got %v", instanceType, i+1, objInfo.UserDefined)
+		}
+		if _, ok := objInfo.UserDefined[objectModTimeKey]; ok {
+			t.Errorf("%s: Test %d: Expected modification time not to be saved as metadata, got %v", instanceType, i+1, objInfo.UserDefined)
+		}
+	}
+
+	// Anonymous uploads without the header are still allowed.
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, newRequest("PUT", "anonymous", "", nil, false))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected anonymous upload to succeed, got `%d`", instanceType, rec.Code)
+	}
+}
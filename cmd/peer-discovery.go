@@ -0,0 +1,254 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Service discovery providers of peer addresses.
+const (
+	discoveryConsul = "consul"
+	discoveryEtcd   = "etcd"
+)
+
+// Interval between lookups of peer addresses, unless configured.
+const defaultDiscoveryInterval = 10 * time.Second
+
+// discoveryConfig - service discovery of the addresses of the hosts in
+// the storage endpoints. Hosts of endpoints are names, e.g
+// "http://minio1/export", registered in Consul as the id of an instance
+// of Service, in etcd as a key under the Service directory whose value
+// is the address, e.g "/minio/minio1" = "10.0.0.5:9000".
+type discoveryConfig struct {
+	// "consul" or "etcd", disabled if empty.
+	Provider string `json:"provider"`
+
+	// URL of the Consul agent or etcd member, e.g "http://127.0.0.1:8500".
+	Address string `json:"address"`
+
+	// Name of the Consul service or etcd directory, e.g "minio".
+	Service string `json:"service"`
+
+	// Interval between lookups, e.g "30s", defaults to 10 seconds.
+	Interval string `json:"interval"`
+}
+
+// validateDiscoveryConfig - validates service discovery settings.
+func validateDiscoveryConfig(config discoveryConfig) error {
+	if config.Provider == "" {
+		return nil
+	}
+	if config.Provider != discoveryConsul && config.Provider != discoveryEtcd {
+		return fmt.Errorf("provider: unknown provider %s", config.Provider)
+	}
+	u, err := url.Parse(config.Address)
+	if err != nil {
+		return fmt.Errorf("address: %v", err)
+	}
+	if (u.Scheme != httpScheme && u.Scheme != httpsScheme) || u.Host == "" {
+		return fmt.Errorf("address: invalid URL %s", config.Address)
+	}
+	if strings.Trim(config.Service, "/") == "" {
+		return errors.New("service: must not be empty")
+	}
+	if config.Interval != "" {
+		interval, err := time.ParseDuration(config.Interval)
+		if err != nil {
+			return fmt.Errorf("interval: %v", err)
+		}
+		if interval <= 0 {
+			return errors.New("interval: must be positive")
+		}
+	}
+	return nil
+}
+
+// getInterval - returns the interval between lookups.
+func (config discoveryConfig) getInterval() time.Duration {
+	if interval, _ := time.ParseDuration(config.Interval); interval > 0 {
+		return interval
+	}
+	return defaultDiscoveryInterval
+}
+
+// lookup - returns the addresses of the registered hosts by name.
+func (config discoveryConfig) lookup(client *http.Client) (map[string]string, error) {
+	if config.Provider == discoveryConsul {
+		return lookupConsul(client, config.Address, config.Service)
+	}
+	return lookupEtcd(client, config.Address, config.Service)
+}
+
+// Service instance in a Consul catalog response.
+type consulService struct {
+	Address        string
+	ServiceID      string
+	ServiceAddress string
+	ServicePort    int
+}
+
+// lookupConsul - returns the addresses of the instances of service by
+// id from the Consul catalog.
+func lookupConsul(client *http.Client, address, service string) (map[string]string, error) {
+	var services []consulService
+	if err := getDiscoveryJSON(client, strings.TrimSuffix(address, "/")+"/v1/catalog/service/"+strings.Replace(getURLEncodedName(service), "/", "%2F", -1), &services); err != nil {
+		return nil, err
+	}
+	addrs := make(map[string]string)
+	for _, s := range services {
+		host := s.ServiceAddress
+		if host == "" {
+			host = s.Address
+		}
+		if s.ServiceID == "" || host == "" {
+			continue
+		}
+		addr := host
+		if s.ServicePort != 0 {
+			addr = net.JoinHostPort(host, strconv.Itoa(s.ServicePort))
+		}
+		addrs[s.ServiceID] = addr
+	}
+	return addrs, nil
+}
+
+// Node of an etcd v2 keys response.
+type etcdNode struct {
+	Key   string     `json:"key"`
+	Value string     `json:"value"`
+	Dir   bool       `json:"dir"`
+	Nodes []etcdNode `json:"nodes"`
+}
+
+// lookupEtcd - returns the values of the keys in the etcd directory
+// named service by key name.
+func lookupEtcd(client *http.Client, address, service string) (map[string]string, error) {
+	var response struct {
+		Node etcdNode `json:"node"`
+	}
+	if err := getDiscoveryJSON(client, strings.TrimSuffix(address, "/")+"/v2/keys/"+strings.Trim(service, "/"), &response); err != nil {
+		return nil, err
+	}
+	addrs := make(map[string]string)
+	for _, node := range response.Node.Nodes {
+		if node.Dir || node.Value == "" {
+			continue
+		}
+		addrs[node.Key[strings.LastIndex(node.Key, "/")+1:]] = node.Value
+	}
+	return addrs, nil
+}
+
+// getDiscoveryJSON - decodes the JSON response to a GET of u into v.
+func getDiscoveryJSON(client *http.Client, u string, v interface{}) error {
+	resp, err := client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected response %s from %s", resp.Status, u)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// peerResolver - addresses of the hosts of endpoints found by service
+// discovery, hosts not found are used as is.
+type peerResolver struct {
+	mu    sync.RWMutex
+	addrs map[string]string
+}
+
+// set - replaces the known addresses, returns true if they changed.
+func (r *peerResolver) set(addrs map[string]string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reflect.DeepEqual(r.addrs, addrs) {
+		return false
+	}
+	r.addrs = addrs
+	return true
+}
+
+// resolve - returns the address to connect to for the host:port addr
+// of an endpoint. Addresses found without a port get the port of addr.
+func (r *peerResolver) resolve(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found, ok := r.addrs[host]
+	if !ok {
+		return addr
+	}
+	if _, _, err = net.SplitHostPort(found); err != nil {
+		return net.JoinHostPort(strings.Trim(found, "[]"), port)
+	}
+	return found
+}
+
+// startPeerDiscovery - looks up the addresses of the hosts of endpoints
+// if service discovery is configured, and keeps looking them up so that
+// peers moved to another address are reconnected to there.
+func startPeerDiscovery(config discoveryConfig) error {
+	if config.Provider == "" {
+		return nil
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	addrs, err := config.lookup(client)
+	if err != nil {
+		return err
+	}
+	globalPeerResolver.set(addrs)
+
+	go func() {
+		ticker := time.NewTicker(config.getInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				addrs, err := config.lookup(client)
+				if err != nil {
+					errorIf(err, "Unable to look up peer addresses in %s.", config.Provider)
+					continue
+				}
+				// Connections to moved peers fail and are dialed again
+				// at their new address.
+				globalPeerResolver.set(addrs)
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
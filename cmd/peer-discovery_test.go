@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// Tests validation of service discovery settings.
+func TestValidateDiscoveryConfig(t *testing.T) {
+	testCases := []struct {
+		config  discoveryConfig
+		success bool
+	}{
+		{discoveryConfig{}, true},
+		{discoveryConfig{Provider: "consul", Address: "http://127.0.0.1:8500", Service: "minio"}, true},
+		{discoveryConfig{Provider: "etcd", Address: "https://etcd:2379", Service: "/minio/", Interval: "1m"}, true},
+		{discoveryConfig{Provider: "zookeeper", Address: "http://127.0.0.1:2181", Service: "minio"}, false},
+		{discoveryConfig{Provider: "consul", Address: "127.0.0.1:8500", Service: "minio"}, false},
+		{discoveryConfig{Provider: "consul", Address: "http://127.0.0.1:8500", Service: "/"}, false},
+		{discoveryConfig{Provider: "consul", Address: "http://127.0.0.1:8500", Service: "minio", Interval: "-1s"}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateDiscoveryConfig(testCase.config)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests lookups of peer addresses in Consul and etcd.
+func TestDiscoveryLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/catalog/service/minio":
+			w.Write([]byte(`[
+				{"Address": "10.0.0.1", "ServiceID": "minio1", "ServiceAddress": "", "ServicePort": 9000},
+				{"Address": "10.0.0.2", "ServiceID": "minio2", "ServiceAddress": "fd00::2", "ServicePort": 9000},
+				{"Address": "10.0.0.3", "ServiceID": "minio3", "ServiceAddress": "10.0.1.3", "ServicePort": 0}
+			]`))
+		case "/v2/keys/minio":
+			w.Write([]byte(`{"action": "get", "node": {"key": "/minio", "dir": true, "nodes": [
+				{"key": "/minio/minio1", "value": "10.0.0.1:9000"},
+				{"key": "/minio/minio2", "value": "[fd00::2]:9000"},
+				{"key": "/minio/sub", "dir": true}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		config  discoveryConfig
+		addrs   map[string]string
+		success bool
+	}{
+		{
+			discoveryConfig{Provider: discoveryConsul, Address: server.URL, Service: "minio"},
+			map[string]string{"minio1": "10.0.0.1:9000", "minio2": "[fd00::2]:9000", "minio3": "10.0.1.3"},
+			true,
+		},
+		{
+			discoveryConfig{Provider: discoveryEtcd, Address: server.URL + "/", Service: "/minio/"},
+			map[string]string{"minio1": "10.0.0.1:9000", "minio2": "[fd00::2]:9000"},
+			true,
+		},
+		{discoveryConfig{Provider: discoveryEtcd, Address: server.URL, Service: "other"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		addrs, err := testCase.config.lookup(http.DefaultClient)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+			continue
+		}
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: Expected failure, got success", i+1)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(addrs, testCase.addrs) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.addrs, addrs)
+		}
+	}
+}
+
+// Tests resolution of endpoint hosts to discovered addresses.
+func TestPeerResolver(t *testing.T) {
+	r := &peerResolver{}
+	if addr := r.resolve("minio1:9000"); addr != "minio1:9000" {
+		t.Fatalf("Expected unknown host to be used as is, got %s", addr)
+	}
+
+	if !r.set(map[string]string{"minio1": "10.0.0.1:9001", "minio2": "fd00::2"}) {
+		t.Fatal("Expected addresses to change")
+	}
+	if r.set(map[string]string{"minio1": "10.0.0.1:9001", "minio2": "fd00::2"}) {
+		t.Fatal("Expected addresses not to change")
+	}
+
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{"minio1:9000", "10.0.0.1:9001"},
+		// Addresses without a port get the port of the endpoint.
+		{"minio2:9000", "[fd00::2]:9000"},
+		{"minio3:9000", "minio3:9000"},
+		{"minio1", "minio1"},
+	}
+	for i, testCase := range testCases {
+		if addr := r.resolve(testCase.addr); addr != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, addr)
+		}
+	}
+}
+
+// Tests that endpoints are local if their discovered address is.
+func TestIsLocalStorageDiscovery(t *testing.T) {
+	globalPeerResolver.set(map[string]string{"minio-discovery-test": "127.0.0.1:9000"})
+	defer globalPeerResolver.set(nil)
+
+	ep, err := url.Parse("http://minio-discovery-test:9000/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isLocalStorage(ep) {
+		t.Fatalf("Expected %s to be local", ep)
+	}
+}
@@ -112,6 +112,20 @@ var readDirBufPool = sync.Pool{
 
 // Return all the entries at the directory dirPath.
 func readDir(dirPath string) (entries []string, err error) {
+	err = readDirBatches(dirPath, func(batch []string) error {
+		entries = append(entries, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Reads the entries at the directory dirPath in batches, calling
+// batchFn with the entries of each syscall.ReadDirent() call in
+// directory order.
+func readDirBatches(dirPath string, batchFn func(entries []string) error) error {
 	bufp := readDirBufPool.Get().(*[]byte)
 	buf := *bufp
 	defer readDirBufPool.Put(bufp)
@@ -120,17 +134,17 @@ func readDir(dirPath string) (entries []string, err error) {
 	if err != nil {
 		// File is really not found.
 		if os.IsNotExist(err) {
-			return nil, errFileNotFound
+			return errFileNotFound
 		}
 		if os.IsPermission(err) {
-			return nil, errFileAccessDenied
+			return errFileAccessDenied
 		}
 
 		// File path cannot be verified since one of the parents is a file.
 		if strings.Contains(err.Error(), "not a directory") {
-			return nil, errFileNotFound
+			return errFileNotFound
 		}
-		return nil, err
+		return err
 	}
 	defer d.Close()
 
@@ -138,16 +152,18 @@ func readDir(dirPath string) (entries []string, err error) {
 	for {
 		nbuf, err := syscall.ReadDirent(fd, buf)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if nbuf <= 0 {
 			break
 		}
 		var tmpEntries []string
 		if tmpEntries, err = parseDirents(dirPath, buf[:nbuf]); err != nil {
-			return nil, err
+			return err
+		}
+		if err = batchFn(tmpEntries); err != nil {
+			return err
 		}
-		entries = append(entries, tmpEntries...)
 	}
-	return
+	return nil
 }
@@ -27,18 +27,31 @@ import (
 
 // Return all the entries at the directory dirPath.
 func readDir(dirPath string) (entries []string, err error) {
+	err = readDirBatches(dirPath, func(batch []string) error {
+		entries = append(entries, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Reads the entries at the directory dirPath in batches of up to 1000
+// entries, calling batchFn with each batch in directory order.
+func readDirBatches(dirPath string, batchFn func(entries []string) error) error {
 	d, err := os.Open(preparePath(dirPath))
 	if err != nil {
 		// File is really not found.
 		if os.IsNotExist(err) {
-			return nil, errFileNotFound
+			return errFileNotFound
 		}
 
 		// File path cannot be verified since one of the parents is a file.
 		if strings.Contains(err.Error(), "not a directory") {
-			return nil, errFileNotFound
+			return errFileNotFound
 		}
-		return nil, err
+		return err
 	}
 	defer d.Close()
 
@@ -49,8 +62,9 @@ func readDir(dirPath string) (entries []string, err error) {
 			if err == io.EOF {
 				break
 			}
-			return nil, err
+			return err
 		}
+		var entries []string
 		for _, fi := range fis {
 			// Stat symbolic link and follow to get the final value.
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
@@ -75,6 +89,9 @@ func readDir(dirPath string) (entries []string, err error) {
 				entries = append(entries, fi.Name())
 			}
 		}
+		if err = batchFn(entries); err != nil {
+			return err
+		}
 	}
-	return entries, nil
+	return nil
 }
@@ -168,6 +168,38 @@ func TestPosixReadAll(t *testing.T) {
 	}
 }
 
+// TestPosixReadAllBulk - tests reading many files with posix ReadAllBulk storage API.
+func TestPosixReadAllBulk(t *testing.T) {
+	posixStorage, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatalf("Unable to create posix test setup, %s", err)
+	}
+	defer removeAll(path)
+
+	if err = posixStorage.MakeVol("exists"); err != nil {
+		t.Fatalf("Unable to create a volume \"exists\", %s", err)
+	}
+	if err = posixStorage.AppendFile("exists", "dir/file", []byte("Hello, World")); err != nil {
+		t.Fatalf("Unable to create a file \"dir/file\", %s", err)
+	}
+
+	bufs, errs := posixStorage.ReadAllBulk("exists", []string{"dir/file", "dir/missing", "dir/file/child"})
+	if len(bufs) != 3 || len(errs) != 3 {
+		t.Fatalf("Expected 3 results, got %d and %d", len(bufs), len(errs))
+	}
+	if errs[0] != nil || string(bufs[0]) != "Hello, World" {
+		t.Errorf("Expected \"Hello, World\", got %q and %v", string(bufs[0]), errs[0])
+	}
+	if errs[1] != errFileNotFound || errs[2] != errFileNotFound {
+		t.Errorf("Expected \"%s\", got %v and %v", errFileNotFound, errs[1], errs[2])
+	}
+
+	_, errs = posixStorage.ReadAllBulk("missing", []string{"dir/file"})
+	if errs[0] != errVolumeNotFound {
+		t.Errorf("Expected \"%s\", got %v", errVolumeNotFound, errs[0])
+	}
+}
+
 // TestPosixNewPosix all the cases handled in posix storage layer initialization.
 func TestPosixNewPosix(t *testing.T) {
 	// Temporary dir name.
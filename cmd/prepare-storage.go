@@ -233,7 +233,9 @@ func retryFormattingXLDisks(firstDisk bool, endpoints []*url.URL, storageDisks [
 				return err
 			}
 			// Check if this is a XL or distributed XL, anything > 1 is considered XL backend.
-			switch prepForInitXL(firstDisk, sErrs, len(storageDisks)) {
+			// A witness breaks the tie of a cluster split in two.
+			voteErrs := withWitnessVotes(sErrs)
+			switch prepForInitXL(firstDisk, voteErrs, len(voteErrs)) {
 			case Abort:
 				return errCorruptedFormat
 			case FormatDisks:
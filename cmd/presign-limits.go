@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lifetime of presigned URLs generated by the browser, unless a
+// shorter maximum is configured.
+const defaultPresignExpiry = 7 * 24 * time.Hour
+
+// presignConfig - restrictions on presigned URLs.
+type presignConfig struct {
+	// Maximum lifetime of presigned URLs, e.g. "24h", unlimited if
+	// empty.
+	MaxExpiry string `json:"maxExpiry"`
+
+	// Presigned URLs signed with an access key before the time
+	// recorded for it are rejected.
+	RevokedBefore map[string]time.Time `json:"revokedBefore,omitempty"`
+}
+
+// validatePresignConfig - validates presigned URL restrictions.
+func validatePresignConfig(config presignConfig) error {
+	if config.MaxExpiry == "" {
+		return nil
+	}
+	maxExpiry, err := time.ParseDuration(config.MaxExpiry)
+	if err != nil {
+		return fmt.Errorf("maxExpiry: %v", err)
+	}
+	if maxExpiry <= 0 {
+		return errors.New("maxExpiry: must be positive")
+	}
+	return nil
+}
+
+// getMaxExpiry - returns the maximum lifetime of presigned URLs, zero
+// if unlimited.
+func (config presignConfig) getMaxExpiry() time.Duration {
+	maxExpiry, _ := time.ParseDuration(config.MaxExpiry)
+	return maxExpiry
+}
+
+// getPresignExpiry - returns the lifetime of presigned URLs generated
+// by the server.
+func getPresignExpiry() time.Duration {
+	if maxExpiry := serverConfig.GetPresign().getMaxExpiry(); maxExpiry > 0 && maxExpiry < defaultPresignExpiry {
+		return maxExpiry
+	}
+	return defaultPresignExpiry
+}
+
+// checkPresignV4Lifetime - verifies that a V4 presigned URL signed
+// with accessKey at date and valid for expires is neither longer
+// lived than allowed nor revoked.
+func checkPresignV4Lifetime(accessKey string, date time.Time, expires time.Duration) APIErrorCode {
+	config := serverConfig.GetPresign()
+	if maxExpiry := config.getMaxExpiry(); maxExpiry > 0 && expires > maxExpiry {
+		return ErrMaximumExpires
+	}
+	if revokedBefore, ok := config.RevokedBefore[accessKey]; ok && date.Before(revokedBefore) {
+		return ErrExpiredPresignRequest
+	}
+	return ErrNone
+}
+
+// checkPresignV2Lifetime - verifies that a V2 presigned URL signed
+// with accessKey and valid until expires is neither longer lived than
+// allowed nor revoked. V2 presigned URLs do not carry the time they
+// were signed at, so once presigned URLs of accessKey are revoked all
+// its V2 presigned URLs are rejected, whatever their expiry.
+func checkPresignV2Lifetime(accessKey string, expires time.Time) APIErrorCode {
+	config := serverConfig.GetPresign()
+	if maxExpiry := config.getMaxExpiry(); maxExpiry > 0 && expires.After(time.Now().UTC().Add(maxExpiry)) {
+		return ErrMaximumExpires
+	}
+	if _, ok := config.RevokedBefore[accessKey]; ok {
+		return ErrExpiredPresignRequest
+	}
+	return ErrNone
+}
+
+// revokePresigned - rejects presigned URLs signed with accessKey
+// before the given time from now on, and saves it in the config.
+// Revocations are never moved back in time.
+func revokePresigned(accessKey string, before time.Time) error {
+	serverConfig.RevokePresigned(accessKey, before)
+	return serverConfig.Save()
+}
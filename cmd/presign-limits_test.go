@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests validation of presigned URL restrictions.
+func TestValidatePresignConfig(t *testing.T) {
+	testCases := []struct {
+		maxExpiry string
+		success   bool
+	}{
+		{"", true},
+		{"24h", true},
+		{"90m", true},
+		{"1 day", false},
+		{"0s", false},
+		{"-1h", false},
+	}
+	for i, testCase := range testCases {
+		err := validatePresignConfig(presignConfig{MaxExpiry: testCase.maxExpiry})
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests that presigned URLs are limited in lifetime and revoked.
+func TestCheckPresignLifetime(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	now := time.Now().UTC()
+	serverConfig.SetPresign(presignConfig{MaxExpiry: "24h"})
+	if err = revokePresigned("revoked", now); err != nil {
+		t.Fatal(err)
+	}
+	// Revocations are never moved back in time.
+	if err = revokePresigned("revoked", now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	v4TestCases := []struct {
+		accessKey string
+		date      time.Time
+		expires   time.Duration
+		expected  APIErrorCode
+	}{
+		{"minio", now, time.Hour, ErrNone},
+		{"minio", now, 24 * time.Hour, ErrNone},
+		{"minio", now, 25 * time.Hour, ErrMaximumExpires},
+		{"revoked", now.Add(-time.Minute), time.Hour, ErrExpiredPresignRequest},
+		{"revoked", now, time.Hour, ErrNone},
+	}
+	for i, testCase := range v4TestCases {
+		if code := checkPresignV4Lifetime(testCase.accessKey, testCase.date, testCase.expires); code != testCase.expected {
+			t.Errorf("V4 test %d: Expected %d, got %d", i+1, testCase.expected, code)
+		}
+	}
+
+	v2TestCases := []struct {
+		accessKey string
+		expires   time.Time
+		expected  APIErrorCode
+	}{
+		{"minio", now.Add(time.Hour), ErrNone},
+		{"minio", now.Add(25 * time.Hour), ErrMaximumExpires},
+		// May have been signed before the revocation.
+		{"revoked", now.Add(time.Hour), ErrExpiredPresignRequest},
+	}
+	for i, testCase := range v2TestCases {
+		if code := checkPresignV2Lifetime(testCase.accessKey, testCase.expires); code != testCase.expected {
+			t.Errorf("V2 test %d: Expected %d, got %d", i+1, testCase.expected, code)
+		}
+	}
+
+	// Without a maximum lifetime, V2 presigned URLs expiring long
+	// after the revocation are rejected as well.
+	serverConfig.SetPresign(presignConfig{RevokedBefore: map[string]time.Time{"revoked": now}})
+	for i, expires := range []time.Time{now.Add(time.Hour), now.Add(30 * 24 * time.Hour), now.Add(10 * 365 * 24 * time.Hour)} {
+		if code := checkPresignV2Lifetime("revoked", expires); code != ErrExpiredPresignRequest {
+			t.Errorf("V2 unlimited test %d: Expected %d, got %d", i+1, ErrExpiredPresignRequest, code)
+		}
+	}
+	if code := checkPresignV2Lifetime("minio", now.Add(30*24*time.Hour)); code != ErrNone {
+		t.Errorf("Expected V2 presigned URL of another access key to be accepted, got %d", code)
+	}
+	serverConfig.SetPresign(presignConfig{MaxExpiry: "24h", RevokedBefore: map[string]time.Time{"revoked": now}})
+
+	// Revocations are saved.
+	if err = loadConfig(envParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if revokedBefore := serverConfig.GetPresign().RevokedBefore; !revokedBefore["revoked"].Equal(now) {
+		t.Errorf("Expected revocation at %s to be saved, got %v", now, revokedBefore)
+	}
+}
+
+// Tests that presigned URLs signed before a revocation are rejected.
+func TestRevokePresignedSignatureV4(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	req, err := newTestRequest("GET", "http://example.com:9000/bucket/object", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = preSignV4(req, cred.AccessKey, cred.SecretKey, 3600); err != nil {
+		t.Fatal(err)
+	}
+	if code := isReqAuthenticated(req, serverConfig.GetRegion()); code != ErrNone {
+		t.Fatalf("Expected presigned request to be authenticated, got %d", code)
+	}
+
+	if err = revokePresigned(cred.AccessKey, time.Now().UTC().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if code := isReqAuthenticated(req, serverConfig.GetRegion()); code != ErrExpiredPresignRequest {
+		t.Fatalf("Expected revoked presigned request to be rejected, got %d", code)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestLimitConfig - limits on the requests signed with an access
+// key, zero is unlimited.
+type requestLimitConfig struct {
+	// Maximum number of requests served at the same time.
+	MaxConcurrent int `json:"maxConcurrent"`
+
+	// Maximum number of requests per second, bursts of up to one
+	// second worth of requests are allowed.
+	MaxRequestsPerSecond int `json:"maxRequestsPerSecond"`
+}
+
+// isUnlimited - returns true if no limit is set.
+func (limit requestLimitConfig) isUnlimited() bool {
+	return limit.MaxConcurrent == 0 && limit.MaxRequestsPerSecond == 0
+}
+
+// validateRequestLimit - validates the limits of an access key.
+func validateRequestLimit(limit requestLimitConfig) error {
+	if limit.MaxConcurrent < 0 {
+		return errors.New("maxConcurrent: must not be negative")
+	}
+	if limit.MaxRequestsPerSecond < 0 {
+		return errors.New("maxRequestsPerSecond: must not be negative")
+	}
+	return nil
+}
+
+// validateRequestLimits - validates the limits of all access keys.
+func validateRequestLimits(limits map[string]requestLimitConfig) error {
+	for accessKey, limit := range limits {
+		if accessKey == "" {
+			return errors.New("empty access key")
+		}
+		if err := validateRequestLimit(limit); err != nil {
+			return fmt.Errorf("%s: %v", accessKey, err)
+		}
+	}
+	return nil
+}
+
+// requestLimitState - requests of an access key in flight and the
+// tokens left to admit new ones.
+type requestLimitState struct {
+	inflight int
+	tokens   float64
+	updated  time.Time
+}
+
+// requestLimiter - admits the requests of each access key within its
+// limits. Only access keys with limits are tracked.
+type requestLimiter struct {
+	mu     sync.Mutex
+	states map[string]*requestLimitState
+}
+
+func newRequestLimiter() *requestLimiter {
+	return &requestLimiter{states: make(map[string]*requestLimitState)}
+}
+
+// acquire - admits a request signed with accessKey at now within limit,
+// returns false if it would exceed the limit. release must be called
+// once an admitted request is served.
+func (l *requestLimiter) acquire(accessKey string, limit requestLimitConfig, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.states[accessKey]
+	if !ok {
+		state = &requestLimitState{tokens: float64(limit.MaxRequestsPerSecond), updated: now}
+		l.states[accessKey] = state
+	}
+	if limit.MaxConcurrent > 0 && state.inflight >= limit.MaxConcurrent {
+		return false
+	}
+	if limit.MaxRequestsPerSecond > 0 {
+		rate := float64(limit.MaxRequestsPerSecond)
+		state.tokens = math.Min(rate, state.tokens+now.Sub(state.updated).Seconds()*rate)
+		state.updated = now
+		if state.tokens < 1 {
+			return false
+		}
+		state.tokens--
+	}
+	state.inflight++
+	return true
+}
+
+// release - marks an admitted request of accessKey as served.
+func (l *requestLimiter) release(accessKey string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state, ok := l.states[accessKey]; ok && state.inflight > 0 {
+		state.inflight--
+	}
+}
+
+// requestLimitKey - context key of the requestAdmission of a request.
+type requestLimitKey struct{}
+
+// requestAdmission - admission of a request within the limits of the
+// access key it is signed with, charged once its signature is verified.
+type requestAdmission struct {
+	// Set once the request is admitted.
+	admitted bool
+	// Access key whose limits the request counts against, if any.
+	limitedKey string
+}
+
+// trackRequestLimit - returns r set up to be admitted within the
+// limits of its access key once its signature is verified, and a
+// function to call once r is served.
+func trackRequestLimit(r *http.Request) (*http.Request, func()) {
+	admission := &requestAdmission{}
+	return r.WithContext(context.WithValue(r.Context(), requestLimitKey{}, admission)), func() {
+		if admission.limitedKey != "" {
+			globalRequestLimiter.release(admission.limitedKey)
+		}
+	}
+}
+
+// checkRequestLimit - admits r, whose signature with accessKey was
+// just verified, within the limits of accessKey, so that requests over
+// the limit are rejected before any work is done for them. Requests
+// are charged once however often they are verified, requests not
+// tracked by the auth handler are never limited.
+func checkRequestLimit(r *http.Request, accessKey string) APIErrorCode {
+	admission, ok := r.Context().Value(requestLimitKey{}).(*requestAdmission)
+	if !ok || admission.admitted || serverConfig == nil {
+		return ErrNone
+	}
+	limit, ok := serverConfig.GetRequestLimits()[accessKey]
+	if ok && !limit.isUnlimited() {
+		if !globalRequestLimiter.acquire(accessKey, limit, time.Now().UTC()) {
+			return ErrSlowDown
+		}
+		admission.limitedKey = accessKey
+	}
+	admission.admitted = true
+	return ErrNone
+}
+
+// setRequestLimit - sets the limits of accessKey from now on, removes
+// them if unlimited, and saves them in the config.
+func setRequestLimit(accessKey string, limit requestLimitConfig) error {
+	serverConfig.SetRequestLimit(accessKey, limit)
+	return serverConfig.Save()
+}
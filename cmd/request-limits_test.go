@@ -0,0 +1,211 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests validation of request limits.
+func TestValidateRequestLimits(t *testing.T) {
+	testCases := []struct {
+		limits  map[string]requestLimitConfig
+		success bool
+	}{
+		{nil, true},
+		{map[string]requestLimitConfig{"key": {MaxConcurrent: 10, MaxRequestsPerSecond: 100}}, true},
+		{map[string]requestLimitConfig{"key": {}}, true},
+		{map[string]requestLimitConfig{"": {MaxConcurrent: 10}}, false},
+		{map[string]requestLimitConfig{"key": {MaxConcurrent: -1}}, false},
+		{map[string]requestLimitConfig{"key": {MaxRequestsPerSecond: -1}}, false},
+	}
+	for i, testCase := range testCases {
+		err := validateRequestLimits(testCase.limits)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: Expected failure, got success", i+1)
+		}
+	}
+}
+
+// Tests the concurrency and rate limits of the request limiter.
+func TestRequestLimiter(t *testing.T) {
+	l := newRequestLimiter()
+	now := time.Now().UTC()
+
+	// Concurrent requests.
+	limit := requestLimitConfig{MaxConcurrent: 2}
+	if !l.acquire("concurrent", limit, now) || !l.acquire("concurrent", limit, now) {
+		t.Fatal("Expected requests within the limit to be admitted")
+	}
+	if l.acquire("concurrent", limit, now) {
+		t.Fatal("Expected request over the concurrency limit to be rejected")
+	}
+	if !l.acquire("other", limit, now) {
+		t.Fatal("Expected request of another access key to be admitted")
+	}
+	l.release("concurrent")
+	if !l.acquire("concurrent", limit, now) {
+		t.Fatal("Expected request to be admitted once another one was served")
+	}
+
+	// Requests per second, bursts of one second worth of requests.
+	limit = requestLimitConfig{MaxRequestsPerSecond: 2}
+	for i := 0; i < 2; i++ {
+		if !l.acquire("rate", limit, now) {
+			t.Fatalf("Request %d: Expected burst within the limit to be admitted", i+1)
+		}
+		l.release("rate")
+	}
+	if l.acquire("rate", limit, now) {
+		t.Fatal("Expected request over the rate limit to be rejected")
+	}
+	if l.acquire("rate", limit, now.Add(100*time.Millisecond)) {
+		t.Fatal("Expected request before a token is available to be rejected")
+	}
+	if !l.acquire("rate", limit, now.Add(600*time.Millisecond)) {
+		t.Fatal("Expected request to be admitted once a token is available")
+	}
+}
+
+// Tests that signed requests are limited by their access key once
+// their signature is verified.
+func TestCheckRequestLimit(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	newRequest := func(accessKey, secretKey string) (*http.Request, func()) {
+		req, rErr := newTestSignedRequestV4("GET", "http://127.0.0.1:9000/bucket/object", 0, nil, accessKey, secretKey)
+		if rErr != nil {
+			t.Fatal(rErr)
+		}
+		return trackRequestLimit(req)
+	}
+
+	// Unlimited by default.
+	req, release := newRequest(cred.AccessKey, cred.SecretKey)
+	if errCode := isReqAuthenticated(req, globalMinioDefaultRegion); errCode != ErrNone {
+		t.Fatalf("Expected request to be admitted, got %d", errCode)
+	}
+	release()
+
+	if err = setRequestLimit(cred.AccessKey, requestLimitConfig{MaxConcurrent: 1}); err != nil {
+		t.Fatal(err)
+	}
+	req, release = newRequest(cred.AccessKey, cred.SecretKey)
+	if errCode := isReqAuthenticated(req, globalMinioDefaultRegion); errCode != ErrNone {
+		t.Fatalf("Expected request to be admitted, got %d", errCode)
+	}
+	// Requests are charged once however often they are verified.
+	if errCode := isReqAuthenticated(req, globalMinioDefaultRegion); errCode != ErrNone {
+		t.Fatalf("Expected request verified again to be admitted, got %d", errCode)
+	}
+
+	// Rejected with SlowDown by the handler verifying the signature.
+	handler := setAuthHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if errCode := checkRequestAuthType(r, "", "", globalMinioDefaultRegion); errCode != ErrNone {
+			writeErrorResponse(w, errCode, r)
+		}
+	}))
+	rec := httptest.NewRecorder()
+	other, err := newTestSignedRequestV4("GET", "http://127.0.0.1:9000/bucket/object", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.ServeHTTP(rec, other)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected request over the limit to be rejected with %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	// Requests with an invalid signature are not charged to the
+	// access key.
+	forged, _ := newRequest(cred.AccessKey, "forged-secret-key")
+	if errCode := isReqAuthenticated(forged, globalMinioDefaultRegion); errCode != ErrSignatureDoesNotMatch {
+		t.Fatalf("Expected request with invalid signature to be rejected for its signature, got %d", errCode)
+	}
+
+	// Streaming signed requests are charged as well.
+	data := []byte("hello")
+	streaming, err := newTestStreamingSignedRequest("PUT", "http://127.0.0.1:9000/bucket/object", int64(len(data)), 64*humanize.KiByte, bytes.NewReader(data), cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	streaming, _ = trackRequestLimit(streaming)
+	if _, errCode := newSignV4ChunkedReader(streaming); errCode != ErrSlowDown {
+		t.Fatalf("Expected streaming signed request over the limit to be rejected, got %d", errCode)
+	}
+
+	release()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, other)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected request to be admitted once the other one was served, got %d", rec.Code)
+	}
+
+	// Requests signed with admin credentials are limited as well.
+	adminCred := newCredentialWithKeys("ADMINLIMITKEY", "adminlimitsecret")
+	globalAdminCredentials, err = newAdminCredentials([]adminCredentialConfig{{
+		AccessKey: adminCred.AccessKey,
+		SecretKey: adminCred.SecretKey,
+		Actions:   []string{adminActionAll},
+	}}, cred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { globalAdminCredentials = nil }()
+	if err = setRequestLimit(adminCred.AccessKey, requestLimitConfig{MaxConcurrent: 1}); err != nil {
+		t.Fatal(err)
+	}
+	req, release = newRequest(adminCred.AccessKey, adminCred.SecretKey)
+	defer release()
+	if errCode := checkAdminRequestAuthType(req, adminActionAll); errCode != ErrNone {
+		t.Fatalf("Expected admin request to be admitted, got %d", errCode)
+	}
+	req, _ = newRequest(adminCred.AccessKey, adminCred.SecretKey)
+	if errCode := checkAdminRequestAuthType(req, adminActionAll); errCode != ErrSlowDown {
+		t.Fatalf("Expected admin request over the limit to be rejected, got %d", errCode)
+	}
+	if err = setRequestLimit(adminCred.AccessKey, requestLimitConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Limits are saved, and removed when unlimited.
+	if err = loadConfig(envParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if limit := serverConfig.GetRequestLimits()[cred.AccessKey]; limit.MaxConcurrent != 1 {
+		t.Fatalf("Expected request limits to be saved, got %v", serverConfig.GetRequestLimits())
+	}
+	if err = setRequestLimit(cred.AccessKey, requestLimitConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := serverConfig.GetRequestLimits()[cred.AccessKey]; ok {
+		t.Fatalf("Expected request limits to be removed, got %v", serverConfig.GetRequestLimits())
+	}
+}
@@ -166,6 +166,18 @@ func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
 	return buf, err
 }
 
+// ReadAllBulk - a retryable implementation of reading all the content
+// from many files.
+func (f retryStorage) ReadAllBulk(volume string, paths []string) (bufs [][]byte, errs []error) {
+	bufs, errs = f.remoteStorage.ReadAllBulk(volume, paths)
+	if len(errs) > 0 && errs[0] == errDiskNotFound {
+		if err := f.reInit(); err == nil {
+			return f.remoteStorage.ReadAllBulk(volume, paths)
+		}
+	}
+	return bufs, errs
+}
+
 // ReadFile - a retryable implementation of reading at offset from a file.
 func (f retryStorage) ReadFile(volume, path string, offset int64, buffer []byte) (m int64, err error) {
 	m, err = f.remoteStorage.ReadFile(volume, path, offset, buffer)
@@ -202,6 +214,18 @@ func (f retryStorage) DeleteFile(volume, path string) (err error) {
 	return err
 }
 
+// MakeDir - a retryable implementation of creating a directory.
+func (f retryStorage) MakeDir(volume, dirPath string) (err error) {
+	err = f.remoteStorage.MakeDir(volume, dirPath)
+	if err == errDiskNotFound {
+		err = f.reInit()
+		if err == nil {
+			return f.remoteStorage.MakeDir(volume, dirPath)
+		}
+	}
+	return err
+}
+
 // RenameFile - a retryable implementation of renaming a file.
 func (f retryStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
 	err = f.remoteStorage.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
@@ -234,6 +258,8 @@ func (f retryStorage) reInit() (err error) {
 			if i < f.maxRetryAttempts {
 				continue
 			}
+			raiseAlert(alertDiskOffline, f.String(), "Disk %s is offline: %s", f.String(), err)
+			serverEventNotify(ServerEventDiskOffline, f.String(), "Disk %s is offline: %s", f.String(), err)
 			return err
 		}
 
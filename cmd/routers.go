@@ -70,6 +70,10 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		return nil, err
 	}
 
+	// Add Admin router, registered before the web router so that
+	// versioned admin API paths are not taken by its catch-all route.
+	registerAdminRouter(mux)
+
 	// Register web router when its enabled.
 	if globalIsBrowserEnabled {
 		if err := registerWebRouter(mux); err != nil {
@@ -77,9 +81,6 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		}
 	}
 
-	// Add Admin router.
-	registerAdminRouter(mux)
-
 	// Add API router.
 	registerAPIRouter(mux)
 
@@ -87,6 +88,8 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 	var handlerFns = []HandlerFunc{
 		// Network statistics
 		setHTTPStatsHandler,
+		// Records a trace span per request when tracing is enabled.
+		setTracingHandler,
 		// Limits all requests size to a maximum fixed limit
 		setRequestSizeLimitHandler,
 		// Adds 'crossdomain.xml' policy handler to serve legacy flash clients.
@@ -95,6 +98,11 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		setBrowserRedirectHandler,
 		// Validates if incoming request is for restricted buckets.
 		setPrivateBucketHandler,
+		// Rejects requests to buckets from clients outside of their
+		// allowed networks, before any policy is evaluated.
+		setBucketNetworkHandler,
+		// Validates inter-node RPC requests carry a verified client certificate.
+		setRPCClientAuthHandler,
 		// Adds cache control for all browser requests.
 		setBrowserCacheControlHandler,
 		// Validates all incoming requests to have a valid date header.
@@ -108,6 +116,12 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		// routes them accordingly. Client receives a HTTP error for
 		// invalid/unsupported signatures.
 		setAuthHandler,
+		// Writes one access log line per request, applied last so
+		// that rejected requests are logged as well.
+		setAccessLogHandler,
+		// Adds security headers such as HSTS to all responses,
+		// applied outermost so that rejected requests get them too.
+		setSecurityHeadersHandler,
 		// Add new handlers here.
 	}
 
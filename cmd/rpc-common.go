@@ -37,6 +37,9 @@ func isRequestTimeAllowed(requestTime time.Time) bool {
 type AuthRPCArgs struct {
 	// Authentication token to be verified by the server for every RPC call.
 	AuthToken string
+
+	// Span of the caller if the call is traced.
+	Trace traceContext
 }
 
 // SetAuthToken - sets the token to the supplied value.
@@ -44,6 +47,17 @@ func (args *AuthRPCArgs) SetAuthToken(authToken string) {
 	args.AuthToken = authToken
 }
 
+// setTraceContext - sets the span of the caller.
+func (args *AuthRPCArgs) setTraceContext(ctx traceContext) {
+	args.Trace = ctx
+}
+
+// startTraceSpan - starts a server span of the call, returns nil
+// unless the caller traces it.
+func (args AuthRPCArgs) startTraceSpan(name string) *traceSpan {
+	return globalTracer.startChildSpan(name, traceKindServer, args.Trace)
+}
+
 // IsAuthenticated - validated whether this auth RPC args are already authenticated or not.
 func (args AuthRPCArgs) IsAuthenticated() error {
 	// Check whether the token is valid
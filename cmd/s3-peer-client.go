@@ -197,3 +197,17 @@ func S3PeersUpdateBucketPolicy(bucket string, pCh policyChange) {
 		)
 	}
 }
+
+// S3PeersUpdateBucketVersioning - Sends update bucket versioning
+// request to all peers. Currently we log an error and continue.
+func S3PeersUpdateBucketVersioning(bucket string, status string) {
+	setBVPArgs := &SetBucketVersioningPeerArgs{Bucket: bucket, Status: status}
+	errs := globalS3Peers.SendUpdate(nil, setBVPArgs)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending update bucket versioning to %s - %v",
+			globalS3Peers[idx].addr, err,
+		)
+	}
+}
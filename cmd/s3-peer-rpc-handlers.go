@@ -116,3 +116,31 @@ func (s3 *s3PeerAPIHandlers) SetBucketPolicyPeer(args *SetBucketPolicyPeerArgs,
 
 	return s3.bms.UpdateBucketPolicy(args)
 }
+
+// SetBucketVersioningPeerArgs - Arguments collection for
+// SetBucketVersioningPeer RPC call
+type SetBucketVersioningPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Versioning status of the bucket, empty once removed.
+	Status string
+}
+
+// BucketUpdate - implements bucket versioning updates,
+// the underlying operation is a network call updates all
+// the peers participating in versioning the bucket.
+func (s *SetBucketVersioningPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketVersioning(s)
+}
+
+// tell receiving server to update the versioning status of a bucket
+func (s3 *s3PeerAPIHandlers) SetBucketVersioningPeer(args *SetBucketVersioningPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketVersioning(args)
+}
@@ -99,6 +99,13 @@ func (s *TestRPCS3PeerSuite) testS3PeerRPC(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Check bucket versioning update call works.
+	BVPArgs := SetBucketVersioningPeerArgs{Bucket: "bucket", Status: versioningEnabled}
+	err = client.Call("S3.SetBucketVersioningPeer", &BVPArgs, &AuthRPCReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	// Check event send event call works.
 	evArgs := EventArgs{Event: nil, Arn: "localhost:9000"}
 	err = client.Call("S3.Event", &evArgs, &AuthRPCReply{})
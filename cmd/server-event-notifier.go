@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Minio server ARN prefix, identifies the source of server events
+// as opposed to bucket ARNs for bucket events.
+const minioServer = "arn:minio:server:"
+
+// Server event types.
+const (
+	ServerEventStarted       = "minio:Server:Started"
+	ServerEventStopped       = "minio:Server:Stopped"
+	ServerEventDiskOffline   = "minio:Disk:Offline"
+	ServerEventConfigChanged = "minio:Config:Changed"
+	ServerEventHealCompleted = "minio:Heal:Completed"
+)
+
+// Supported server event types, including wildcards.
+var supportedServerEventTypes = map[string]struct{}{
+	"minio:*":                {},
+	"minio:Server:*":         {},
+	ServerEventStarted:       {},
+	ServerEventStopped:       {},
+	"minio:Disk:*":           {},
+	ServerEventDiskOffline:   {},
+	"minio:Config:*":         {},
+	ServerEventConfigChanged: {},
+	"minio:Heal:*":           {},
+	ServerEventHealCompleted: {},
+}
+
+// Server event source, analogous to 'aws:s3' of bucket events.
+const serverEventSource = "minio:server"
+
+// serverEventConfig - sends server events matching Events to the
+// queue target identified by QueueARN, configured in config.json.
+type serverEventConfig struct {
+	Events   []string `json:"events"`
+	QueueARN string   `json:"queueARN"`
+}
+
+// ServerEvent represents a server lifecycle event.
+type ServerEvent struct {
+	EventVersion string `json:"eventVersion"`
+	EventSource  string `json:"eventSource"`
+	AwsRegion    string `json:"awsRegion"`
+	EventTime    string `json:"eventTime"`
+	EventName    string `json:"eventName"`
+	ARN          string `json:"arn"`
+	Server       string `json:"server"`
+	Subject      string `json:"subject,omitempty"`
+	Message      string `json:"message"`
+}
+
+// getServerEventEndpoint - returns the endpoint identifying this server.
+func getServerEventEndpoint() string {
+	if len(globalAPIEndpoints) >= 1 {
+		return globalAPIEndpoints[0]
+	}
+	return globalMinioAddr
+}
+
+// newServerEvent - constructs a new server event.
+func newServerEvent(eventName, subject, message string) ServerEvent {
+	region := serverConfig.GetRegion()
+	server := getServerEventEndpoint()
+	return ServerEvent{
+		EventVersion: eventVersion,
+		EventSource:  serverEventSource,
+		AwsRegion:    region,
+		EventTime:    time.Now().UTC().Format(timeFormatAMZ),
+		EventName:    eventName,
+		ARN:          minioServer + region + ":" + server,
+		Server:       server,
+		Subject:      subject,
+		Message:      message,
+	}
+}
+
+// checkServerEventConfigs - validates server event configs against
+// supported event types and configured queue targets.
+func checkServerEventConfigs(configs []serverEventConfig, queueTargets map[string]*logrus.Logger) error {
+	for _, config := range configs {
+		if len(config.Events) == 0 {
+			return fmt.Errorf("No server events configured for %s", config.QueueARN)
+		}
+		for _, event := range config.Events {
+			if _, ok := supportedServerEventTypes[event]; !ok {
+				return fmt.Errorf("Unsupported server event %s", event)
+			}
+		}
+		if _, ok := queueTargets[config.QueueARN]; !ok {
+			return fmt.Errorf("Server event target %s is not configured", config.QueueARN)
+		}
+	}
+	return nil
+}
+
+// serverEventNotify - sends a server event to all targets configured
+// for it. Subject identifies what the event is about, e.g. a disk.
+func serverEventNotify(eventName, subject string, format string, args ...interface{}) {
+	if globalEventNotifier == nil {
+		return
+	}
+	configs := globalEventNotifier.GetServerEventConfigs()
+	if len(configs) == 0 {
+		return
+	}
+
+	event := newServerEvent(eventName, subject, fmt.Sprintf(format, args...))
+	for _, config := range configs {
+		if !eventMatch(eventName, config.Events) {
+			continue
+		}
+		targetLog := globalEventNotifier.GetExternalTarget(config.QueueARN)
+		if targetLog != nil {
+			targetLog.WithFields(logrus.Fields{
+				// Key groups events about the same subject, so that
+				// key-value targets keep the latest state of it.
+				"Key":       path.Join(event.Server, subject),
+				"EventType": eventName,
+				"Records":   []ServerEvent{event},
+			}).Info()
+		}
+	}
+}
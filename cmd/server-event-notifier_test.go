@@ -0,0 +1,122 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests validating server event configs.
+func TestCheckServerEventConfigs(t *testing.T) {
+	queueARN := minioSqs + "us-east-1:1:webhook"
+	queueTargets := map[string]*logrus.Logger{queueARN: logrus.New()}
+
+	testCases := []struct {
+		configs    []serverEventConfig
+		shouldPass bool
+	}{
+		// Test 1 - no configs.
+		{nil, true},
+		// Test 2 - valid events and target.
+		{[]serverEventConfig{{Events: []string{ServerEventStarted, "minio:Disk:*"}, QueueARN: queueARN}}, true},
+		// Test 3 - all server events.
+		{[]serverEventConfig{{Events: []string{"minio:*"}, QueueARN: queueARN}}, true},
+		// Test 4 - bucket events are not server events.
+		{[]serverEventConfig{{Events: []string{"s3:ObjectCreated:*"}, QueueARN: queueARN}}, false},
+		// Test 5 - no events.
+		{[]serverEventConfig{{QueueARN: queueARN}}, false},
+		// Test 6 - unknown target.
+		{[]serverEventConfig{{Events: []string{ServerEventStopped}, QueueARN: minioSqs + "us-east-1:1:amqp"}}, false},
+	}
+	for i, testCase := range testCases {
+		err := checkServerEventConfigs(testCase.configs, queueTargets)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, failed with %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail, passed", i+1)
+		}
+	}
+}
+
+// Tests sending server events to matching targets only.
+func TestServerEventNotify(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	diskARN := minioSqs + "us-east-1:1:webhook"
+	allARN := minioSqs + "us-east-1:2:webhook"
+	diskBuf, allBuf := &syncBuffer{}, &syncBuffer{}
+
+	savedNotifier := globalEventNotifier
+	defer func() { globalEventNotifier = savedNotifier }()
+	globalEventNotifier = &eventNotifier{
+		external: externalNotifier{
+			targets: map[string]*logrus.Logger{
+				diskARN: newTestAlertTarget(diskBuf),
+				allARN:  newTestAlertTarget(allBuf),
+			},
+			rwMutex: &sync.RWMutex{},
+		},
+		serverEventConfigs: []serverEventConfig{
+			{Events: []string{"minio:Disk:*"}, QueueARN: diskARN},
+			{Events: []string{"minio:*"}, QueueARN: allARN},
+		},
+	}
+
+	serverEventNotify(ServerEventStarted, "", "Server started")
+	serverEventNotify(ServerEventDiskOffline, "/mnt/disk1", "Disk %s is offline", "/mnt/disk1")
+
+	if lines := allBuf.Lines(); len(lines) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(lines))
+	}
+	lines := diskBuf.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(lines))
+	}
+
+	var entry struct {
+		EventType string
+		Records   []ServerEvent
+	}
+	if err = json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.EventType != ServerEventDiskOffline {
+		t.Errorf("Expected event type %s, got %s", ServerEventDiskOffline, entry.EventType)
+	}
+	if len(entry.Records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(entry.Records))
+	}
+	record := entry.Records[0]
+	if record.EventSource != serverEventSource {
+		t.Errorf("Expected event source %s, got %s", serverEventSource, record.EventSource)
+	}
+	if record.Subject != "/mnt/disk1" || record.Message != "Disk /mnt/disk1 is offline" {
+		t.Errorf("Unexpected record %#v", record)
+	}
+	if record.ARN != minioServer+globalMinioDefaultRegion+":"+record.Server {
+		t.Errorf("Unexpected ARN %s", record.ARN)
+	}
+}
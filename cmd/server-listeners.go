@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"strings"
+)
+
+// parseListenHosts - resolves the value of --listeners, comma separated
+// IPs, hostnames and network interface names, into the hosts to bind
+// to. IPv6 addresses may be bracketed. Interfaces are bound on all
+// their addresses, hostnames on all the addresses they resolve to.
+func parseListenHosts(listeners string) ([]string, error) {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(listeners, ",") {
+		entry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(entry), "["), "]")
+		if entry == "" {
+			continue
+		}
+		var entryHosts []string
+		if ip := net.ParseIP(entry); ip != nil {
+			entryHosts = []string{ip.String()}
+		} else if strings.Contains(entry, "%") {
+			// Link-local IPv6 address with a zone.
+			entryHosts = []string{entry}
+		} else if interfaceHosts, err := getInterfaceHosts(entry); err == nil {
+			if len(interfaceHosts) == 0 {
+				return nil, errInvalidArgument
+			}
+			entryHosts = interfaceHosts
+		} else {
+			addrs, err := net.LookupHost(entry)
+			if err != nil {
+				return nil, err
+			}
+			entryHosts = addrs
+		}
+		for _, host := range entryHosts {
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts, nil
+}
+
+// getListenAddrs - returns the host:port addresses to bind to for the
+// --address serverAddr, on listenHosts instead of its host if any. An
+// empty host binds all addresses, IPv4 and IPv6, a hostname all the
+// addresses it resolves to.
+func getListenAddrs(serverAddr string, listenHosts []string) ([]string, error) {
+	host, port, err := net.SplitHostPort(serverAddr)
+	if err != nil {
+		return nil, err
+	}
+	if len(listenHosts) > 0 {
+		var addrs []string
+		for _, listenHost := range listenHosts {
+			addrs = append(addrs, net.JoinHostPort(listenHost, port))
+		}
+		return addrs, nil
+	}
+	if host == "" {
+		return []string{serverAddr}, nil
+	}
+	if net.ParseIP(host) != nil {
+		return []string{net.JoinHostPort(host, port)}, nil
+	}
+	hosts, err := net.LookupHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		return nil, errUnexpected
+	}
+	var addrs []string
+	for _, h := range hosts {
+		addrs = append(addrs, net.JoinHostPort(h, port))
+	}
+	return addrs, nil
+}
+
+// isSameHostPort - returns true if the host:port addresses a and b are
+// the same, IP addresses are compared by value so that different forms
+// of the same IPv6 address match.
+func isSameHostPort(a, b string) bool {
+	if a == b {
+		return true
+	}
+	aHost, aPort, err := net.SplitHostPort(a)
+	if err != nil {
+		return false
+	}
+	bHost, bPort, err := net.SplitHostPort(b)
+	if err != nil || aPort != bPort {
+		return false
+	}
+	aIP, bIP := net.ParseIP(aHost), net.ParseIP(bHost)
+	return aIP != nil && aIP.Equal(bIP)
+}
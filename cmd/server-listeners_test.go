@@ -0,0 +1,176 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+// Tests parsing of --listeners.
+func TestParseListenHosts(t *testing.T) {
+	// Name of the loopback interface.
+	var loopback string
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+			break
+		}
+	}
+
+	testCases := []struct {
+		listeners string
+		hosts     []string
+		success   bool
+	}{
+		{"", nil, true},
+		{"127.0.0.1", []string{"127.0.0.1"}, true},
+		{"127.0.0.1, ::1", []string{"127.0.0.1", "::1"}, true},
+		// IPv6 addresses may be bracketed, and are normalized.
+		{"[::1],0:0::1", []string{"::1"}, true},
+		{"fe80::1%eth0", []string{"fe80::1%eth0"}, true},
+		{"localhost", nil, true},
+		{"no-such-host.invalid", nil, false},
+	}
+	for i, testCase := range testCases {
+		hosts, err := parseListenHosts(testCase.listeners)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+			continue
+		}
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: Expected failure, got success", i+1)
+			}
+			continue
+		}
+		if testCase.hosts != nil && !reflect.DeepEqual(hosts, testCase.hosts) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.hosts, hosts)
+		}
+	}
+
+	// Interfaces are bound on all their addresses.
+	if loopback != "" {
+		hosts, err := parseListenHosts(loopback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, host := range hosts {
+			if host == "127.0.0.1" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected addresses of %s to contain 127.0.0.1, got %v", loopback, hosts)
+		}
+	}
+}
+
+// Tests the addresses bound to for --address and --listeners.
+func TestGetListenAddrs(t *testing.T) {
+	testCases := []struct {
+		serverAddr  string
+		listenHosts []string
+		addrs       []string
+		success     bool
+	}{
+		{":9000", nil, []string{":9000"}, true},
+		{"127.0.0.1:9000", nil, []string{"127.0.0.1:9000"}, true},
+		{"[::1]:9000", nil, []string{"[::1]:9000"}, true},
+		{":9000", []string{"127.0.0.1", "::1", "fe80::1%eth0"}, []string{"127.0.0.1:9000", "[::1]:9000", "[fe80::1%eth0]:9000"}, true},
+		// --listeners replace the host of --address.
+		{"10.0.0.1:9000", []string{"127.0.0.1"}, []string{"127.0.0.1:9000"}, true},
+		{"::1", nil, nil, false},
+	}
+	for i, testCase := range testCases {
+		addrs, err := getListenAddrs(testCase.serverAddr, testCase.listenHosts)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: Expected success, got %v", i+1, err)
+			continue
+		}
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: Expected failure, got success", i+1)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(addrs, testCase.addrs) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.addrs, addrs)
+		}
+	}
+}
+
+// Tests comparison of host:port addresses.
+func TestIsSameHostPort(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		same bool
+	}{
+		{"host:9000", "host:9000", true},
+		{"host:9000", "host:9001", false},
+		{"[::1]:9000", "[0:0::1]:9000", true},
+		{"[fd00::1]:9000", "[FD00::1]:9000", true},
+		{"[::1]:9000", "[::1]:9001", false},
+		{"127.0.0.1:9000", "[::ffff:127.0.0.1]:9000", true},
+		{"host1:9000", "host2:9000", false},
+		{"::1", "::1", true},
+		{"::1", "0::1", false},
+	}
+	for i, testCase := range testCases {
+		if same := isSameHostPort(testCase.a, testCase.b); same != testCase.same {
+			t.Errorf("Test %d: Expected %v for %s and %s, got %v", i+1, testCase.same, testCase.a, testCase.b, same)
+		}
+	}
+}
+
+// Tests the endpoints advertised for --listeners.
+func TestFinalizeAPIEndpointsListeners(t *testing.T) {
+	globalListenHosts = []string{"127.0.0.1", "::1", "fe80::1%eth0"}
+	defer func() { globalListenHosts = nil }()
+
+	endPoints, err := finalizeAPIEndpoints(":9000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"http://127.0.0.1:9000", "http://[::1]:9000"}
+	if !reflect.DeepEqual(endPoints, expected) {
+		t.Fatalf("Expected %v, got %v", expected, endPoints)
+	}
+}
+
+// Tests that IPv6 storage endpoints get the default port.
+func TestParseStorageEndpointsIPv6(t *testing.T) {
+	savedMinioHost, savedMinioPort := globalMinioHost, globalMinioPort
+	globalMinioHost, globalMinioPort = "", "9000"
+	defer func() { globalMinioHost, globalMinioPort = savedMinioHost, savedMinioPort }()
+
+	endpoints, err := parseStorageEndpoints([]string{"http://[fd00::1]/export", "http://[fd00::2]/export"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, host := range []string{"[fd00::1]:9000", "[fd00::2]:9000"} {
+		if endpoints[i].Host != host {
+			t.Errorf("Expected host %s, got %s", host, endpoints[i].Host)
+		}
+	}
+}
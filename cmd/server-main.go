@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"os"
 	"path"
 	"sort"
 	"strconv"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/ellipses"
 )
 
 var serverFlags = []cli.Flag{
@@ -39,6 +41,18 @@ var serverFlags = []cli.Flag{
 		Value: ":9000",
 		Usage: "Bind to a specific ADDRESS:PORT, ADDRESS can be an IP or hostname.",
 	},
+	cli.StringFlag{
+		Name:  "listeners",
+		Usage: "Bind to a comma separated list of IPs, hostnames or network interfaces instead of ADDRESS, on the PORT of --address.",
+	},
+	cli.StringFlag{
+		Name:  "witness",
+		Usage: "HOST:PORT of a witness node breaking ties between the halves of a distributed setup.",
+	},
+	cli.BoolFlag{
+		Name:  "check",
+		Usage: "Run preflight checks and exit without starting the server.",
+	},
 }
 
 var serverCmd = cli.Command{
@@ -63,6 +77,55 @@ ENVIRONMENT VARIABLES:
   BROWSER:
      MINIO_BROWSER: To disable web browser access, set this value to "off".
 
+  CONSISTENCY:
+     MINIO_CONSISTENCY: To make listings and object info reflect every completed write even when disks are offline, at the cost of reading more disks, set this value to "strict".
+
+  COPY:
+     MINIO_SOURCE_METADATA: To expose the source ETag and Last-Modified preserved on copied objects, set this value to "on".
+
+  ERASURE:
+     MINIO_ERASURE_WORKERS: Maximum number of concurrent erasure encode and decode operations, defaults to the number of CPUs available.
+
+  LOCKING:
+     MINIO_LOCK_POLICY: Order in which waiting operations on an object are granted its lock, "fifo" or "writer-priority", defaults to "fifo". With "writer-priority" waiting writes go before waiting reads.
+     MINIO_LOCKLESS_READS: To serve object reads without taking object locks, set this value to "on". A read racing with an overwrite or delete of the same object may then fail.
+
+  JANITOR:
+     MINIO_JANITOR_INTERVAL: Interval between removals of stale temporary data and orphaned multipart uploads, e.g. "30m", or "off" to disable. Defaults to "1h".
+     MINIO_JANITOR_AGE: Minimum time since the last write to stale data before it is removed, at least "1h", defaults to "24h".
+
+  LOGGING:
+     MINIO_ACCESS_LOG: Path of the access log file, or "syslog" to log to the local syslog daemon.
+     MINIO_ACCESS_LOG_FORMAT: Access log format, one of "combined" or "json", defaults to "combined".
+
+  MEMORY:
+     MINIO_MEMORY_LIMIT: Maximum memory used by upload and download buffers, e.g. "2GiB", or "off" to disable. Defaults to a quarter of the available memory.
+
+  METADATA:
+     MINIO_METADATA_MAX_SIZE: Maximum size of the user metadata of an object, e.g. "16KiB", defaults to "8KiB".
+     MINIO_XL_META_FORMAT: Format of new erasure coded object metadata, "binary" or "json", defaults to "json". Use "binary" only once no older servers read the disks.
+
+  NAMES:
+     MINIO_NORMALIZE_OBJECT_NAMES: To normalize new object names to Unicode NFC, set this value to "on".
+
+  POLICY:
+     MINIO_POLICY_MAX_STATEMENTS: Maximum number of statements allowed in a bucket policy, defaults to 256.
+
+  RPC:
+     MINIO_RPC_CLIENT_AUTH: To require verified TLS client certificates on inter-node RPC, set this value to "on".
+
+  TLS:
+     MINIO_TLS_FIPS: To restrict TLS to FIPS approved cipher suites and curves, set this value to "on".
+     MINIO_TLS_MIN_VERSION: Minimum TLS version accepted, one of "1.0", "1.1" or "1.2", defaults to "1.2".
+     MINIO_TLS_CIPHERS: Comma separated list of TLS cipher suites accepted, e.g. "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384".
+
+  UPLOAD:
+     MINIO_OBJECT_MAX_SIZE: Maximum size of objects uploaded or copied in a single request, e.g. "1GiB", defaults to "5GiB".
+     MINIO_PART_MIN_SIZE: Minimum size of the parts of a multipart upload but the last one, e.g. "1MiB", defaults to "5MiB".
+     MINIO_PART_MAX_SIZE: Maximum size of the parts of a multipart upload, e.g. "16GiB", defaults to "5GiB".
+     MINIO_UPLOAD_MAX_PARTS: Maximum number of parts of a multipart upload, defaults to 10000.
+     MINIO_CHUNKED_UPLOAD_MAX_SIZE: Maximum size of objects uploaded with chunked transfer encoding and no Content-Length, e.g. "1GiB", defaults to MINIO_OBJECT_MAX_SIZE.
+
 EXAMPLES:
   1. Start minio server on "/home/shared" directory.
       $ {{.HelpName}} /home/shared
@@ -70,16 +133,32 @@ EXAMPLES:
   2. Start minio server bound to a specific ADDRESS:PORT.
       $ {{.HelpName}} --address 192.168.1.101:9000 /home/shared
 
-  3. Start erasure coded minio server on a 12 disks server.
+  3. Start minio server bound to the IPv4 and IPv6 addresses of "eth0" and to the IPv6 loopback.
+      $ {{.HelpName}} --address :9000 --listeners eth0,::1 /home/shared
+
+  4. Start erasure coded minio server on a 12 disks server.
       $ {{.HelpName}} /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/ \
           /mnt/export5/ /mnt/export6/ /mnt/export7/ /mnt/export8/ /mnt/export9/ \
           /mnt/export10/ /mnt/export11/ /mnt/export12/
 
-  4. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
+  5. Start erasure coded minio server on a 12 disks server using ellipses, same as the above.
+      $ {{.HelpName}} /mnt/export{1...12}/
+
+  6. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
       $ export MINIO_ACCESS_KEY=minio
       $ export MINIO_SECRET_KEY=miniostorage
       $ {{.HelpName}} http://192.168.1.11/mnt/export/ http://192.168.1.12/mnt/export/ \
           http://192.168.1.13/mnt/export/ http://192.168.1.14/mnt/export/
+
+  7. Start erasure coded distributed minio server on a 4 node setup using ellipses, same as the above.
+      $ {{.HelpName}} http://192.168.1.1{1...4}/mnt/export/
+
+  8. Start erasure coded distributed minio server on 2 nodes with 2 drives each, with a witness node
+     started with "minio witness" on 192.168.1.10.
+      $ {{.HelpName}} --witness 192.168.1.10:9000 http://192.168.1.1{1...2}/mnt/export{1...2}/
+
+  9. Verify the host is ready to serve "/home/shared" without starting the server.
+      $ {{.HelpName}} --check /home/shared
 `,
 }
 
@@ -88,6 +167,23 @@ type serverCmdConfig struct {
 	endpoints  []*url.URL
 }
 
+// Expands ellipses patterns such as "/mnt/export{1...12}" in the
+// endpoint arguments, arguments without patterns are returned as is.
+func expandEndpointArgs(args []string) ([]string, error) {
+	if !ellipses.HasEllipses(args...) {
+		return args, nil
+	}
+	return ellipses.ExpandAll(args)
+}
+
+// Returns the endpoint arguments of the server command with all
+// ellipses patterns expanded, exits in case of invalid patterns.
+func serverEndpointArgs(c *cli.Context) []string {
+	args, err := expandEndpointArgs(c.Args())
+	fatalIf(err, "Unable to expand storage endpoints %s", strings.Join(c.Args(), " "))
+	return args
+}
+
 // Parse an array of end-points (from the command line)
 func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 	for _, ep := range eps {
@@ -113,7 +209,8 @@ func parseStorageEndpoints(eps []string) (endpoints []*url.URL, err error) {
 				if port != "" {
 					return nil, fmt.Errorf("Invalid Argument %s, port configurable using --address :<port>", u.Host)
 				}
-				u.Host = net.JoinHostPort(u.Host, globalMinioPort)
+				// IPv6 hosts are bracketed, e.g "[::1]".
+				u.Host = net.JoinHostPort(strings.Trim(u.Host, "[]"), globalMinioPort)
 			} else {
 				// For ex.: minio server --address host:port host1:port1 host2:port2...
 				// i.e if "--address host:port" is specified
@@ -141,6 +238,104 @@ func initServerConfig(c *cli.Context) {
 
 	// Set system resources to maximum.
 	errorIf(setMaxResources(), "Unable to change resource limit")
+
+	// Load bucket policy limits.
+	globalMaxPolicyStatements = mustGetPolicyMaxStatementsFromEnv()
+
+	// Load user metadata size limit.
+	globalMaxUserMetadataSize = mustGetMetadataMaxSizeFromEnv()
+
+	// Load object and multipart upload limits, before the size limit of
+	// uploads without Content-Length which may not exceed them.
+	globalMaxObjectSize = mustGetObjectMaxSizeFromEnv()
+	globalMinPartSize = mustGetPartMinSizeFromEnv()
+	globalMaxPartSize = mustGetPartMaxSizeFromEnv()
+	globalMaxPartID = mustGetUploadMaxPartsFromEnv()
+
+	// Load the size limit of uploads without Content-Length.
+	globalMaxChunkedUploadSize = mustGetChunkedUploadMaxSizeFromEnv()
+
+	// Load source metadata exposure setting.
+	globalExposeSourceMetadata = mustGetSourceMetadataFromEnv()
+
+	// Load object name normalization setting.
+	globalNormalizeObjectNames = mustGetNormalizeObjectNamesFromEnv()
+
+	// Load the format of new `xl.json`.
+	globalXLMetaFormat = mustGetXLMetaFormatFromEnv()
+
+	// Load the consistency mode.
+	globalStrictConsistency = mustGetStrictConsistencyFromEnv()
+
+	// Load the grant policy of namespace locks.
+	globalLockPolicy = mustGetLockPolicyFromEnv()
+
+	// Load lockless reads setting.
+	globalLocklessReads = mustGetLocklessReadsFromEnv()
+
+	// Load deep scrub rate, 0 if disabled.
+	globalDeepScrubRate = mustGetDeepScrubRateFromEnv()
+
+	// Load janitor interval and age, interval 0 if disabled.
+	globalJanitorInterval = mustGetJanitorIntervalFromEnv()
+	globalJanitorAge = mustGetJanitorAgeFromEnv()
+
+	// Limit parallelism to the CPUs available to the process.
+	errorIf(setMaxProcs(), "Unable to read CPU quota")
+	globalErasureWorkers = newErasureWorkers(mustGetErasureWorkersFromEnv())
+
+	// Cap the memory used by erasure coding and copy buffers.
+	globalBufferPool.SetLimit(mustGetMemoryLimitFromEnv())
+
+	// Load the size of the read ahead cache, read ahead is opt-in.
+	globalReadAheadCacheSize = mustGetReadAheadCacheSizeFromEnv()
+
+	// Load inter-node RPC client authentication setting, it is
+	// only meaningful when TLS is configured.
+	globalRPCClientAuth = mustGetRPCClientAuthFromEnv()
+	if globalRPCClientAuth && !globalIsSSL {
+		console.Fatalln("MINIO_RPC_CLIENT_AUTH requires TLS certificates to be configured.")
+	}
+	if globalRPCClientAuth {
+		var err error
+		globalRPCClientCAs, err = loadRPCClientCAs()
+		fatalIf(err, "MINIO_RPC_CLIENT_AUTH requires the CAs of the node certificates to be installed.")
+	}
+
+	// Load FIPS TLS mode setting.
+	globalTLSFIPS = mustGetTLSFIPSFromEnv()
+
+	// Load TLS minimum version and cipher suites.
+	globalTLSMinVersion = mustGetTLSMinVersionFromEnv()
+	globalTLSCipherSuites = mustGetTLSCipherSuitesFromEnv()
+	fatalIf(checkTLSSettings(), "Invalid TLS settings.")
+
+	// Initialize access logging if enabled.
+	fatalIf(initAccessLogger(), "Unable to initialize access logger.")
+
+	// Initialize alerting if any alert target is configured.
+	fatalIf(initAlerter(), "Unable to initialize alerting.")
+
+	// Initialize tracing if a tracing endpoint is configured.
+	fatalIf(initTracing(), "Invalid tracing configuration.")
+
+	// Initialize StatsD metrics if a StatsD address is configured.
+	fatalIf(initStatsd(), "Unable to initialize StatsD metrics.")
+
+	// Initialize bucket creation restrictions if any.
+	fatalIf(initBucketCreationPolicy(), "Invalid bucket creation configuration.")
+
+	// Initialize strict object names if any bucket is in strict names mode.
+	fatalIf(initStrictNamesPolicy(), "Invalid strict names configuration.")
+
+	// Initialize content type policy if any.
+	fatalIf(initContentTypePolicy(), "Invalid content type configuration.")
+
+	// Initialize admin API only credentials if any.
+	fatalIf(initAdminCredentials(), "Invalid admin credentials configuration.")
+
+	// Initialize IAM users if any.
+	fatalIf(initIAMUsers(), "Invalid IAM users configuration.")
 }
 
 // Validate if input disks are sufficient for initializing XL.
@@ -242,7 +437,7 @@ func checkServerSyntax(c *cli.Context) {
 	fatalIf(err, "Unable to parse %s.", serverAddr)
 
 	// Verify syntax for all the XL disks.
-	disks := c.Args()
+	disks := serverEndpointArgs(c)
 
 	// Parse disks check if they comply with expected URI style.
 	endpoints, err := parseStorageEndpoints(disks)
@@ -259,7 +454,8 @@ func checkServerSyntax(c *cli.Context) {
 	if len(endpoints) > 1 {
 		// Validate if we have sufficient disks for XL setup.
 		err = checkSufficientDisks(endpoints)
-		fatalIf(err, "Insufficient number of disks.")
+		fatalIf(err, "Invalid number of disks %d, erasure code requires an even number of disks between %d and %d.",
+			len(endpoints), minErasureBlocks, maxErasureBlocks)
 	} else {
 		// Validate if we have invalid disk for FS setup.
 		if endpoints[0].Host != "" && endpoints[0].Scheme != "" {
@@ -281,7 +477,7 @@ func checkServerSyntax(c *cli.Context) {
 		}
 		foundCnt := 0
 		for _, ep := range endpoints {
-			if ep.Host == serverAddr {
+			if isSameHostPort(ep.Host, serverAddr) {
 				foundCnt++
 			}
 		}
@@ -362,17 +558,8 @@ func serverMain(c *cli.Context) {
 	// Get quiet flag from command line argument.
 	quietFlag := c.Bool("quiet") || c.GlobalBool("quiet")
 
-	// Get configuration directory from command line argument.
-	configDir := c.String("config-dir")
-	if !c.IsSet("config-dir") && c.GlobalIsSet("config-dir") {
-		configDir = c.GlobalString("config-dir")
-	}
-	if configDir == "" {
-		console.Fatalf("Configuration directory cannot be empty.")
-	}
-
-	// Set configuration directory.
-	setConfigDir(configDir)
+	// Set configuration directory from command line argument.
+	mustSetConfigDirFromContext(c)
 
 	// Initializes server config, certs, logging and system settings.
 	initServerConfig(c)
@@ -389,14 +576,23 @@ func serverMain(c *cli.Context) {
 	globalMinioHost, globalMinioPort, err = getHostPort(serverAddr)
 	fatalIf(err, "Unable to extract host and port %s", serverAddr)
 
+	// Hosts to bind to instead of the host of --address.
+	globalListenHosts, err = parseListenHosts(c.String("listeners"))
+	fatalIf(err, "Unable to parse listeners %s", c.String("listeners"))
+
+	// Look up the addresses of peers before endpoints are checked for
+	// being local.
+	fatalIf(startPeerDiscovery(serverConfig.GetDiscovery()), "Unable to look up peer addresses.")
+
 	// Check server syntax and exit in case of errors.
 	// Done after globalMinioHost and globalMinioPort is set
 	// as parseStorageEndpoints() depends on it.
 	checkServerSyntax(c)
 
 	// Disks to be used in server init.
-	endpoints, err := parseStorageEndpoints(c.Args())
-	fatalIf(err, "Unable to parse storage endpoints %s", c.Args())
+	disks := serverEndpointArgs(c)
+	endpoints, err := parseStorageEndpoints(disks)
+	fatalIf(err, "Unable to parse storage endpoints %s", strings.Join(disks, " "))
 
 	// Should exit gracefully if none of the endpoints passed
 	// as command line args are local to this server.
@@ -404,6 +600,20 @@ func serverMain(c *cli.Context) {
 		fatalIf(errInvalidArgument, "None of the disks passed as command line args are local to this server.")
 	}
 
+	// Run preflight checks, with `--check` report all issues and exit.
+	preflightIssues := runPreflightChecks(globalMinioPort, endpoints)
+	if c.Bool("check") {
+		printPreflightIssues(preflightIssues)
+		if len(preflightIssues) > 0 {
+			os.Exit(1)
+		}
+		console.Println("All preflight checks passed.")
+		os.Exit(0)
+	}
+	if printPreflightIssues(preflightIssues) {
+		console.Fatalln("Preflight checks failed, run `minio server --check` after fixing the issues above.")
+	}
+
 	// Sort endpoints for consistent ordering across multiple
 	// nodes in a distributed setup. This is to avoid format.json
 	// corruption if the disks aren't supplied in the same order
@@ -419,6 +629,16 @@ func serverMain(c *cli.Context) {
 	// Check if endpoints are part of distributed setup.
 	globalIsDistXL = isDistributedSetup(endpoints)
 
+	// Claim the lease of the witness, before the lock and format
+	// quorums it takes part in.
+	if witnessAddr := c.String("witness"); witnessAddr != "" {
+		if !globalIsDistXL {
+			fatalIf(errWitnessNotDistXL, "Unable to use witness %s.", witnessAddr)
+		}
+		globalWitness = newWitnessClient(witnessAddr, endpoints)
+		startWitness(globalWitness)
+	}
+
 	// Set nodes for dsync for distributed setup.
 	if globalIsDistXL {
 		fatalIf(initDsyncNodes(endpoints), "Unable to initialize distributed locking clients")
@@ -430,6 +650,10 @@ func serverMain(c *cli.Context) {
 		globalIsXL = true
 	}
 
+	// Check that the loss of any failure domain is tolerated.
+	globalFailureDomains, err = getFailureDomains(serverConfig.GetFailureDomains(), endpoints)
+	fatalIf(err, "Invalid failure domains.")
+
 	// Initialize name space lock.
 	initNSLock(globalIsDistXL)
 
@@ -471,10 +695,50 @@ func serverMain(c *cli.Context) {
 	newObject, err := newObjectLayer(srvConfig)
 	fatalIf(err, "Initializing object layer failed")
 
+	// XL object layer before any wrapping, nil in FS mode.
+	xl, _ := newObject.(*xlObjects)
+
+	// Start deep scrubbing of the local disks if enabled, XL mode only.
+	if globalDeepScrubRate > 0 && xl != nil {
+		startXLScrubber(xl, globalDeepScrubRate)
+	}
+
+	// Remove stale temporary data and orphaned multipart uploads from
+	// the local disks if enabled.
+	if globalJanitorInterval > 0 {
+		fatalIf(startJanitor(newObject, globalJanitorInterval, globalJanitorAge), "Unable to start janitor.")
+	}
+
+	// Serve read-only bucket mounts if configured, FS mode only.
+	if mounts := serverConfig.GetBucketMounts(); len(mounts) > 0 {
+		if globalIsXL {
+			fatalIf(errBucketMountsXL, "Unable to initialize bucket mounts.")
+		}
+		newObject, err = newBucketMountObjectLayer(newObject, mounts)
+		fatalIf(err, "Unable to initialize bucket mounts.")
+
+		// Reload bucket policies and versioning to include the
+		// mounted buckets.
+		fatalIf(initBucketPolicies(newObject), "Unable to initialize bucket policies.")
+		fatalIf(initBucketVersioning(newObject), "Unable to initialize bucket versioning.")
+	}
+
+	// Add the layers enabled by the server configuration.
+	newObject = wrapObjectLayer(newObject)
+
 	globalObjLayerMutex.Lock()
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
 
+	// Repair diverged bucket metadata in the background.
+	startBucketMetaChecker(newObject, xl)
+
+	// Back up the configuration in the background, if enabled.
+	startBackups(newObject)
+
+	// Expire objects of buckets with a lifecycle configuration.
+	startLifecycleExpiry(newObject)
+
 	// Prints the formatted startup message once object layer is initialized.
 	if !quietFlag {
 		printStartupMessage(apiEndPoints)
@@ -483,10 +747,26 @@ func serverMain(c *cli.Context) {
 	// Set uptime time after object layer has initialized.
 	globalBootTime = time.Now().UTC()
 
+	serverEventNotify(ServerEventStarted, "", "Server started")
+
 	// Waits on the server.
 	<-globalServiceDoneCh
 }
 
+// wrapObjectLayer - wraps objAPI with the layers enabled by the server
+// configuration, every assignment of globalObjectAPI goes through it.
+func wrapObjectLayer(objAPI ObjectLayer) ObjectLayer {
+	// Normalize new object names if enabled.
+	if globalNormalizeObjectNames {
+		objAPI = newNFCObjectLayer(objAPI)
+	}
+	// Adjust directory handling for FUSE clients if configured.
+	if serverConfig != nil && serverConfig.GetCompat() == compatFUSE {
+		objAPI = newFUSEObjectLayer(objAPI)
+	}
+	return objAPI
+}
+
 // Initialize object layer with the supplied disks, objectLayer is nil upon any error.
 func newObjectLayer(srvCmdCfg serverCmdConfig) (newObject ObjectLayer, err error) {
 	// For FS only, directly use the disk.
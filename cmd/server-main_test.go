@@ -309,6 +309,54 @@ func TestParseStorageEndpoints(t *testing.T) {
 	globalMinioHost = ""
 }
 
+// Tests expansion of ellipses patterns in endpoint arguments.
+func TestExpandEndpointArgs(t *testing.T) {
+	testCases := []struct {
+		args         []string
+		expectedArgs []string
+		shouldPass   bool
+	}{
+		// Arguments without ellipses are returned as is.
+		{[]string{"/export1", "/export2"}, []string{"/export1", "/export2"}, true},
+		{
+			[]string{"/mnt/export{1...4}"},
+			[]string{"/mnt/export1", "/mnt/export2", "/mnt/export3", "/mnt/export4"},
+			true,
+		},
+		{
+			[]string{"http://192.168.1.1{1...2}/mnt/export{1...2}"},
+			[]string{
+				"http://192.168.1.11/mnt/export1", "http://192.168.1.11/mnt/export2",
+				"http://192.168.1.12/mnt/export1", "http://192.168.1.12/mnt/export2",
+			},
+			true,
+		},
+		// Malformed and reversed patterns.
+		{[]string{"/mnt/export{1..4}"}, nil, true},
+		{[]string{"/mnt/export{a...d}"}, nil, false},
+		{[]string{"/mnt/export{4...1}"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		args, err := expandEndpointArgs(testCase.args)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %v", i+1, args)
+			}
+			continue
+		}
+		if testCase.expectedArgs == nil {
+			testCase.expectedArgs = testCase.args
+		}
+		if !reflect.DeepEqual(args, testCase.expectedArgs) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedArgs, args)
+		}
+	}
+}
+
 // Test check endpoints syntax function for syntax verification
 // across various scenarios of inputs.
 func TestCheckEndpointsSyntax(t *testing.T) {
@@ -354,36 +354,18 @@ func NewServerMux(addr string, handler http.Handler) *ServerMux {
 
 // Initialize listeners on all ports.
 func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
-	host, port, err := net.SplitHostPort(serverAddr)
+	addrs, err := getListenAddrs(serverAddr, globalListenHosts)
 	if err != nil {
 		return nil, err
 	}
 	var listeners []*ListenerMux
-	if host == "" {
-		var listener net.Listener
-		listener, err = net.Listen("tcp", serverAddr)
-		if err != nil {
-			return nil, err
-		}
-		listeners = append(listeners, newListenerMux(listener, tls))
-		return listeners, nil
-	}
-	var addrs []string
-	if net.ParseIP(host) != nil {
-		addrs = append(addrs, host)
-	} else {
-		addrs, err = net.LookupHost(host)
-		if err != nil {
-			return nil, err
-		}
-		if len(addrs) == 0 {
-			return nil, errUnexpected
-		}
-	}
 	for _, addr := range addrs {
 		var listener net.Listener
-		listener, err = net.Listen("tcp", net.JoinHostPort(addr, port))
+		listener, err = net.Listen("tcp", addr)
 		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
 			return nil, err
 		}
 		listeners = append(listeners, newListenerMux(listener, tls))
@@ -397,28 +379,7 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 
 	tlsEnabled := certFile != "" && keyFile != ""
 
-	config := &tls.Config{
-		// Causes servers to use Go's default ciphersuite preferences,
-		// which are tuned to avoid attacks. Does nothing on clients.
-		PreferServerCipherSuites: true,
-		// Only use curves which have assembly implementations
-		CurvePreferences: []tls.CurveID{
-			tls.CurveP256,
-		},
-		// Set minimum version to TLS 1.2
-		MinVersion: tls.VersionTLS12,
-		CipherSuites: []uint16{
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-
-			// Best disabled, as they don't provide Forward Secrecy,
-			// but might be necessary for some clients
-			// tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-			// tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-		},
-	} // Always instantiate.
+	config := newServerTLSConfig() // Always instantiate.
 
 	if tlsEnabled {
 		// Configure TLS in the server
@@ -430,6 +391,13 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 		if err != nil {
 			return err
 		}
+		if globalRPCClientAuth {
+			// Ask peers for a client certificate, requests without one are
+			// still accepted here since only RPC paths mandate it, see
+			// setRPCClientAuthHandler.
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+			config.ClientCAs = globalRPCClientCAs
+		}
 	}
 
 	go m.handleServiceSignals()
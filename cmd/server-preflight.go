@@ -0,0 +1,255 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/disk"
+	"github.com/minio/minio/pkg/sys"
+)
+
+const (
+	// Recommended minimum of the open files limit.
+	minOpenFilesLimit = 4096
+
+	// Timeout for reaching remote endpoints.
+	preflightTimeout = 5 * time.Second
+)
+
+// preflightIssue - a problem found by a preflight check along with
+// the action required to resolve it. Fatal issues prevent the server
+// from serving traffic, the others are reported as warnings.
+type preflightIssue struct {
+	check  string
+	err    error
+	action string
+	fatal  bool
+}
+
+// runPreflightChecks - verifies the host is ready to serve the given
+// endpoints on port, returns all issues found.
+func runPreflightChecks(port string, endpoints []*url.URL) (issues []preflightIssue) {
+	issues = append(issues, checkOpenFilesLimit()...)
+	issues = append(issues, checkListenPort(port)...)
+	for _, ep := range endpoints {
+		if isLocalStorage(ep) {
+			issues = append(issues, checkLocalDisk(getPath(ep), len(endpoints) > 1)...)
+		}
+	}
+	issues = append(issues, checkRemoteEndpoints(endpoints)...)
+	return issues
+}
+
+// checkOpenFilesLimit - verifies the open files limit can be raised
+// high enough to serve many concurrent requests.
+func checkOpenFilesLimit() []preflightIssue {
+	_, maxLimit, err := sys.GetMaxOpenFileLimit()
+	if err != nil {
+		return []preflightIssue{{
+			check:  "ulimit",
+			err:    fmt.Errorf("unable to read the open files limit: %v", err),
+			action: "Verify the limits configured for the minio process",
+		}}
+	}
+	// Some platforms such as windows do not report a limit.
+	if maxLimit == 0 || maxLimit >= minOpenFilesLimit {
+		return nil
+	}
+	return []preflightIssue{{
+		check:  "ulimit",
+		err:    fmt.Errorf("open files limit is %d, at least %d is recommended", maxLimit, minOpenFilesLimit),
+		action: "Raise the limit with `ulimit -n 65536`, or with LimitNOFILE when running under systemd",
+	}}
+}
+
+// checkListenPort - verifies no other process listens on port.
+func checkListenPort(port string) []preflightIssue {
+	if err := checkPortAvailability(port); err != nil {
+		return []preflightIssue{{
+			check:  "port",
+			err:    fmt.Errorf("port %s is already in use", port),
+			action: "Stop the process listening on the port or pick another port with `--address`",
+			fatal:  true,
+		}}
+	}
+	return nil
+}
+
+// checkLocalDisk - verifies the filesystem type, write access and
+// extended attributes support of a local disk.
+func checkLocalDisk(diskPath string, isXL bool) (issues []preflightIssue) {
+	// The disk path is created on startup if it does not exist,
+	// check the filesystem it would be created on.
+	dir := diskPath
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	info, err := disk.GetInfo(dir)
+	if err != nil {
+		return []preflightIssue{{
+			check:  "filesystem",
+			err:    fmt.Errorf("unable to stat %s: %v", diskPath, err),
+			action: "Make sure the disk is mounted and accessible",
+			fatal:  true,
+		}}
+	}
+	switch info.FSType {
+	case "NFS":
+		if isXL {
+			issues = append(issues, preflightIssue{
+				check:  "filesystem",
+				err:    fmt.Errorf("%s is on NFS", diskPath),
+				action: "Use local disks for erasure coding, network filesystems do not provide the required locking and consistency",
+			})
+		}
+	case "TMPFS":
+		issues = append(issues, preflightIssue{
+			check:  "filesystem",
+			err:    fmt.Errorf("%s is on tmpfs", diskPath),
+			action: "Use a persistent disk, data on tmpfs is lost on reboot",
+		})
+	}
+
+	if dir != diskPath {
+		// Nothing else to verify before the disk path is created.
+		return issues
+	}
+	f, err := ioutil.TempFile(dir, ".minio-preflight-")
+	if err != nil {
+		return append(issues, preflightIssue{
+			check:  "filesystem",
+			err:    fmt.Errorf("%s is not writable: %v", diskPath, err),
+			action: "Fix the ownership or permissions of the disk path for the user running minio",
+			fatal:  true,
+		})
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if err = checkXattrSupport(f.Name()); err != nil {
+		issues = append(issues, preflightIssue{
+			check:  "xattr",
+			err:    fmt.Errorf("%s does not support extended attributes: %v", diskPath, err),
+			action: "Remount the filesystem with extended attributes enabled, e.g. `user_xattr`",
+		})
+	}
+	return issues
+}
+
+// checkRemoteEndpoints - verifies all remote nodes are reachable and
+// that their clocks are in sync with the local clock.
+func checkRemoteEndpoints(endpoints []*url.URL) []preflightIssue {
+	hostsMap := make(map[string]*url.URL)
+	for _, ep := range endpoints {
+		if !isLocalStorage(ep) {
+			hostsMap[ep.Host] = ep
+		}
+	}
+	var hosts []string
+	for host := range hostsMap {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	client := &http.Client{
+		Timeout: preflightTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: globalRootCAs},
+		},
+	}
+
+	hostIssues := make([][]preflightIssue, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, ep *url.URL) {
+			defer wg.Done()
+			hostIssues[i] = checkRemoteEndpoint(client, ep)
+		}(i, hostsMap[host])
+	}
+	wg.Wait()
+
+	var issues []preflightIssue
+	for _, hostIssue := range hostIssues {
+		issues = append(issues, hostIssue...)
+	}
+	return issues
+}
+
+// checkRemoteEndpoint - verifies a remote node is reachable and that
+// its clock, as reported in the Date header, is in sync.
+func checkRemoteEndpoint(client *http.Client, ep *url.URL) []preflightIssue {
+	reqTime := time.Now().UTC()
+	resp, err := client.Head(ep.Scheme + "://" + ep.Host + "/")
+	if err != nil {
+		return []preflightIssue{{
+			check:  "endpoint",
+			err:    fmt.Errorf("%s is not reachable: %v", ep.Host, err),
+			action: "Start minio on all nodes and make sure the port is not blocked by a firewall",
+		}}
+	}
+	resp.Body.Close()
+
+	remoteTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		// Nothing to compare against.
+		return nil
+	}
+	localTime := reqTime.Add(time.Now().UTC().Sub(reqTime) / 2)
+	skew := remoteTime.Sub(localTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	// Date header is only precise to the second.
+	if skew <= rpcSkewTimeAllowed+time.Second {
+		return nil
+	}
+	return []preflightIssue{{
+		check:  "clock",
+		err:    fmt.Errorf("clock of %s differs by %s", ep.Host, skew-skew%time.Second),
+		action: "Synchronize the clocks of all nodes, e.g. with NTP",
+	}}
+}
+
+// printPreflightIssues - prints issues along with their actions,
+// returns true if any issue is fatal.
+func printPreflightIssues(issues []preflightIssue) (fatal bool) {
+	for _, issue := range issues {
+		if issue.fatal {
+			fatal = true
+			console.Errorf("Preflight %s check failed: %v. %s.\n", issue.check, issue.err, issue.action)
+			continue
+		}
+		console.Println(colorBlue("Preflight %s check warning: ", issue.check) + fmt.Sprintf("%v. %s.", issue.err, issue.action))
+	}
+	return fatal
+}
@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests preflight checks of local disks.
+func TestCheckLocalDisk(t *testing.T) {
+	dir := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	if err := mkdirAll(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	for _, diskPath := range []string{dir, filepath.Join(dir, "not", "created", "yet")} {
+		for _, issue := range checkLocalDisk(diskPath, false) {
+			if issue.fatal {
+				t.Errorf("%s: unexpected fatal issue %v", diskPath, issue.err)
+			}
+		}
+	}
+}
+
+// Tests preflight check of the listen port.
+func TestCheckListenPort(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := checkListenPort(port)
+	if len(issues) != 1 || !issues[0].fatal {
+		t.Fatalf("Expected a fatal issue for a port in use, got %v", issues)
+	}
+	l.Close()
+	if issues = checkListenPort(port); len(issues) != 0 {
+		t.Fatalf("Expected no issues for a free port, got %v", issues)
+	}
+}
+
+// Tests preflight checks of remote endpoints.
+func TestCheckRemoteEndpoint(t *testing.T) {
+	var offset time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().UTC().Add(offset).Format(http.TimeFormat))
+	}))
+	ep, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Timeout: preflightTimeout}
+
+	if issues := checkRemoteEndpoint(client, ep); len(issues) != 0 {
+		t.Fatalf("Expected no issues, got %v", issues)
+	}
+	offset = time.Minute
+	if issues := checkRemoteEndpoint(client, ep); len(issues) != 1 || issues[0].check != "clock" {
+		t.Fatalf("Expected a clock issue, got %v", issues)
+	}
+	server.Close()
+	if issues := checkRemoteEndpoint(client, ep); len(issues) != 1 || issues[0].check != "endpoint" {
+		t.Fatalf("Expected an endpoint issue, got %v", issues)
+	}
+}
@@ -16,7 +16,14 @@
 
 package cmd
 
-import "github.com/minio/minio/pkg/sys"
+import (
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/sys"
+)
+
+// Minimum memory cap of the buffer pool, enough for a few concurrent
+// erasure coded uploads.
+const minMemoryLimit = 64 * humanize.MiByte
 
 func setMaxResources() (err error) {
 	var maxLimit uint64
@@ -55,6 +62,31 @@ func getMaxCacheSize(curLimit, totalRAM uint64) (cacheSize uint64) {
 	return cacheSize
 }
 
+// getDefaultMemoryLimit returns the default memory cap of the buffer
+// pool, a quarter of the current memory limit or total RAM, zero when
+// neither is known.
+func getDefaultMemoryLimit() uint64 {
+	curLimit, _, err := sys.GetMaxMemoryLimit()
+	if err != nil {
+		return 0
+	}
+	stats, err := sys.GetStats()
+	if err != nil {
+		return 0
+	}
+	if curLimit == 0 || curLimit > stats.TotalRAM {
+		curLimit = stats.TotalRAM
+	}
+	if curLimit == 0 {
+		return 0
+	}
+	limit := curLimit / 4
+	if limit < minMemoryLimit {
+		limit = minMemoryLimit
+	}
+	return limit
+}
+
 // GetMaxCacheSize returns maximum cache size based on current RAM size and memory limit.
 func GetMaxCacheSize() (cacheSize uint64, err error) {
 	// Get max memory limit
@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"strings"
 )
 
 // getListenIPs - gets all the ips to listen on.
@@ -29,12 +30,12 @@ func getListenIPs(serverAddr string) (hosts []string, port string, err error) {
 		return nil, port, fmt.Errorf("Unable to parse host address %s", err)
 	}
 	if host == "" {
-		var ipv4s []net.IP
-		ipv4s, err = getInterfaceIPv4s()
+		var ips []net.IP
+		ips, err = getInterfaceIPs()
 		if err != nil {
 			return nil, port, fmt.Errorf("Unable reverse sort ips from hosts %s", err)
 		}
-		for _, ip := range ipv4s {
+		for _, ip := range ips {
 			hosts = append(hosts, ip.String())
 		}
 		return hosts, port, nil
@@ -61,9 +62,20 @@ func finalizeAPIEndpoints(addr string) (endPoints []string, err error) {
 		return nil, err1
 	}
 
-	// Construct proper endpoints.
+	// Hosts of --listeners replace the host of the address, but for
+	// link-local addresses which cannot be used without a zone.
+	if len(globalListenHosts) > 0 {
+		hosts = nil
+		for _, host := range globalListenHosts {
+			if !strings.Contains(host, "%") {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	// Construct proper endpoints, IPv6 addresses are bracketed.
 	for _, host := range hosts {
-		endPoints = append(endPoints, fmt.Sprintf("%s://%s:%s", scheme, host, port))
+		endPoints = append(endPoints, scheme+"://"+net.JoinHostPort(host, port))
 	}
 
 	// Success.
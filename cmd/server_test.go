@@ -925,6 +925,15 @@ func (s *TestSuiteCommon) TestNotImplemented(c *C) {
 	response, err := client.Do(request)
 	c.Assert(err, IsNil)
 	c.Assert(response.StatusCode, Equals, http.StatusNotImplemented)
+
+	// There are no archived objects to restore.
+	request, err = newTestSignedRequest("POST", s.endPoint+"/"+bucketName+"/object?restore",
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+
+	response, err = client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusNotImplemented)
 }
 
 // TestHeader - Validates the error response for an attempt to fetch non-existent object.
@@ -943,6 +952,24 @@ func (s *TestSuiteCommon) TestHeader(c *C) {
 	verifyError(c, response, "NoSuchBucket", "The specified bucket does not exist", http.StatusNotFound)
 }
 
+// TestSecurityHeadersRejected - Validates security headers are set on
+// requests rejected before reaching the API handlers.
+func (s *TestSuiteCommon) TestSecurityHeadersRejected(c *C) {
+	bucketName := getRandomBucketName()
+	request, err := newTestSignedRequest("GET", getGetObjectURL(s.endPoint, bucketName, "testObject"),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, IsNil)
+	// Remove the date headers so that the request is rejected.
+	request.Header.Del("X-Amz-Date")
+	request.Header.Del("Date")
+
+	client := http.Client{Transport: s.transport}
+	response, err := client.Do(request)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(response.Header.Get("X-Content-Type-Options"), Equals, "nosniff")
+}
+
 func (s *TestSuiteCommon) TestPutBucket(c *C) {
 	// generate a random bucket name.
 	bucketName := getRandomBucketName()
@@ -94,6 +94,7 @@ func (m *ServerMux) handleServiceSignals() error {
 		case serviceStatus:
 			/// We don't do anything for this.
 		case serviceRestart:
+			serverEventNotify(ServerEventStopped, "", "Server restarting")
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
@@ -102,6 +103,7 @@ func (m *ServerMux) handleServiceSignals() error {
 			}
 			runExitFn(nil)
 		case serviceStop:
+			serverEventNotify(ServerEventStopped, "", "Server stopping")
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
@@ -0,0 +1,123 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// signatureDetailsKey - request context key of the signatureDetails
+// of a request.
+type signatureDetailsKey struct{}
+
+// signatureDetails - the canonical request and string to sign the
+// server calculated for a signature V4 request which did not match.
+type signatureDetails struct {
+	canonicalRequest string
+	stringToSign     string
+}
+
+// setSignatureDetails - saves the canonical request and string to sign
+// calculated for r, reported by setSignatureErrorDetails.
+func setSignatureDetails(r *http.Request, canonicalRequest, stringToSign string) {
+	*r = *r.WithContext(context.WithValue(r.Context(), signatureDetailsKey{}, signatureDetails{
+		canonicalRequest: canonicalRequest,
+		stringToSign:     stringToSign,
+	}))
+}
+
+// setSignatureErrorDetails - adds the fields AWS S3 returns with
+// authentication errors to errorResponse, so that clients can compare
+// the string they signed with the one the server signed.
+func setSignatureErrorDetails(errorResponse *APIErrorResponse, errorCode APIErrorCode, r *http.Request) {
+	switch errorCode {
+	case ErrInvalidRegion, ErrAuthorizationHeaderMalformed:
+		if serverConfig != nil {
+			errorResponse.Region = serverConfig.GetRegion()
+		}
+	case ErrInvalidAccessKeyID:
+		errorResponse.AWSAccessKeyID = getRequestAccessKey(r)
+	case ErrSignatureDoesNotMatch:
+		errorResponse.AWSAccessKeyID = getRequestAccessKey(r)
+		var canonicalRequest, stringToSign, signature string
+		switch getRequestAuthType(r) {
+		case authTypeSigned, authTypeStreamingSigned:
+			signV4Values, _ := parseSignV4(r.Header.Get("Authorization"))
+			signature = signV4Values.Signature
+		case authTypePresigned:
+			signature = r.URL.Query().Get("X-Amz-Signature")
+		case authTypeSignedV2:
+			stringToSign, signature = getSignV2StringToSign(r)
+		case authTypePresignedV2:
+			signature = r.URL.Query().Get("Signature")
+		}
+		// Saved while verifying the signature V4.
+		if details, ok := r.Context().Value(signatureDetailsKey{}).(signatureDetails); ok {
+			canonicalRequest, stringToSign = details.canonicalRequest, details.stringToSign
+		}
+		errorResponse.SignatureProvided = signature
+		if stringToSign != "" {
+			errorResponse.StringToSign = stringToSign
+			errorResponse.StringToSignBytes = fmt.Sprintf("% x", stringToSign)
+		}
+		if canonicalRequest != "" {
+			errorResponse.CanonicalRequest = canonicalRequest
+			errorResponse.CanonicalRequestBytes = fmt.Sprintf("% x", canonicalRequest)
+		}
+	}
+}
+
+// getRequestAccessKey - returns the access key a request was signed
+// with, empty if it could not be parsed.
+func getRequestAccessKey(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		signV4Values, errCode := parseSignV4(r.Header.Get("Authorization"))
+		if errCode == ErrNone {
+			return signV4Values.Credential.accessKey
+		}
+	case authTypePresigned:
+		credential, errCode := parseCredentialHeader("Credential=" + r.URL.Query().Get("X-Amz-Credential"))
+		if errCode == ErrNone {
+			return credential.accessKey
+		}
+	case authTypeSignedV2:
+		authFields := strings.SplitN(strings.TrimPrefix(r.Header.Get("Authorization"), signV2Algorithm+" "), ":", 2)
+		if len(authFields) == 2 {
+			return authFields[0]
+		}
+	case authTypePresignedV2:
+		return r.URL.Query().Get("AWSAccessKeyId")
+	}
+	return ""
+}
+
+// getSignV2StringToSign - returns the string to sign of a request
+// signed with signature V2 in its authorization header, along with the
+// signature provided.
+func getSignV2StringToSign(r *http.Request) (stringToSign, signature string) {
+	authFields := strings.SplitN(r.Header.Get("Authorization"), ":", 2)
+	if len(authFields) == 2 {
+		signature = authFields[1]
+	}
+	// r.RequestURI will have raw encoded URI as sent by the client.
+	splits := splitStr(r.RequestURI, "?", 2)
+	return signV2STS(r.Method, splits[0], splits[1], r.Header), signature
+}
@@ -0,0 +1,169 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// writeTestErrorResponse - returns the error response written for
+// errorCode to r.
+func writeTestErrorResponse(t *testing.T, errorCode APIErrorCode, r *http.Request) (*httptest.ResponseRecorder, APIErrorResponse) {
+	rec := httptest.NewRecorder()
+	writeErrorResponse(rec, errorCode, r)
+	var errorResponse APIErrorResponse
+	if err := xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatal(err)
+	}
+	return rec, errorResponse
+}
+
+// Tests the request id, host id and resource of error responses.
+func TestWriteErrorResponse(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	testCases := []struct {
+		url        string
+		bucketName string
+		key        string
+	}{
+		{"http://127.0.0.1:9000/bucket/dir/object", "bucket", "dir/object"},
+		{"http://127.0.0.1:9000/bucket", "bucket", ""},
+		{"http://127.0.0.1:9000/minio/admin/v1/config", "", ""},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("GET", testCase.url, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec, errorResponse := writeTestErrorResponse(t, ErrNoSuchKey, req)
+		if rec.Code != http.StatusNotFound || errorResponse.Code != "NoSuchKey" {
+			t.Errorf("Test %d: Unexpected response %d %#v", i+1, rec.Code, errorResponse)
+		}
+		if errorResponse.RequestID == "" || errorResponse.RequestID != rec.Header().Get(responseRequestIDKey) {
+			t.Errorf("Test %d: Expected request id %s, got %s", i+1, rec.Header().Get(responseRequestIDKey), errorResponse.RequestID)
+		}
+		if errorResponse.HostID == "" || errorResponse.HostID != rec.Header().Get(responseHostIDKey) {
+			t.Errorf("Test %d: Expected host id %s, got %s", i+1, rec.Header().Get(responseHostIDKey), errorResponse.HostID)
+		}
+		if errorResponse.Resource != req.URL.Path || errorResponse.BucketName != testCase.bucketName || errorResponse.Key != testCase.key {
+			t.Errorf("Test %d: Unexpected resource %#v", i+1, errorResponse)
+		}
+	}
+}
+
+// Tests the details of signature errors.
+func TestWriteSignatureErrorResponse(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	cred := serverConfig.GetCredential()
+	wrongSecretKey := "wrong-secret-key"
+	url := "http://127.0.0.1:9000/bucket/object?uploads"
+
+	// Signature V4, the string to sign returned signed with the client
+	// secret key gives the signature provided.
+	req, err := newTestSignedRequestV4("GET", url, 0, nil, cred.AccessKey, wrongSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errCode := reqSignatureV4Verify(req)
+	if errCode != ErrSignatureDoesNotMatch {
+		t.Fatalf("Expected ErrSignatureDoesNotMatch, got %d", errCode)
+	}
+	_, errorResponse := writeTestErrorResponse(t, errCode, req)
+	if errorResponse.AWSAccessKeyID != cred.AccessKey || errorResponse.CanonicalRequest == "" || errorResponse.CanonicalRequestBytes == "" {
+		t.Fatalf("Unexpected signature V4 error response %#v", errorResponse)
+	}
+	date, err := time.Parse(iso8601Format, req.Header.Get("x-amz-date"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := getSignature(getSigningKey(wrongSecretKey, date, globalMinioDefaultRegion), errorResponse.StringToSign)
+	if signature != errorResponse.SignatureProvided {
+		t.Fatalf("Expected string to sign to give signature %s, got %s", errorResponse.SignatureProvided, signature)
+	}
+
+	// Presigned signature V4.
+	req, err = newTestRequest("GET", url, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = preSignV4(req, cred.AccessKey, wrongSecretKey, 60); err != nil {
+		t.Fatal(err)
+	}
+	if errCode = reqSignatureV4Verify(req); errCode != ErrSignatureDoesNotMatch {
+		t.Fatalf("Expected ErrSignatureDoesNotMatch, got %d", errCode)
+	}
+	_, errorResponse = writeTestErrorResponse(t, errCode, req)
+	if errorResponse.CanonicalRequest == "" {
+		t.Fatalf("Unexpected presigned signature V4 error response %#v", errorResponse)
+	}
+	date, err = time.Parse(iso8601Format, req.URL.Query().Get("X-Amz-Date"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature = getSignature(getSigningKey(wrongSecretKey, date, globalMinioDefaultRegion), errorResponse.StringToSign)
+	if signature != errorResponse.SignatureProvided {
+		t.Fatalf("Expected string to sign to give signature %s, got %s", errorResponse.SignatureProvided, signature)
+	}
+
+	// Signature V2.
+	req, err = newTestSignedRequestV2("GET", url, 0, nil, cred.AccessKey, wrongSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Set by the server for incoming requests only.
+	req.RequestURI = "/bucket/object?uploads"
+	if errCode = doesSignV2Match(req); errCode != ErrSignatureDoesNotMatch {
+		t.Fatalf("Expected ErrSignatureDoesNotMatch, got %d", errCode)
+	}
+	_, errorResponse = writeTestErrorResponse(t, errCode, req)
+	if errorResponse.AWSAccessKeyID != cred.AccessKey || errorResponse.CanonicalRequest != "" {
+		t.Fatalf("Unexpected signature V2 error response %#v", errorResponse)
+	}
+	if signature = calculateSignatureV2(errorResponse.StringToSign, wrongSecretKey); signature != errorResponse.SignatureProvided {
+		t.Fatalf("Expected string to sign to give signature %s, got %s", errorResponse.SignatureProvided, signature)
+	}
+
+	// Unknown access key.
+	req, err = newTestSignedRequestV4("GET", url, 0, nil, "unknown-access-key", wrongSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, errorResponse = writeTestErrorResponse(t, ErrInvalidAccessKeyID, req)
+	if errorResponse.AWSAccessKeyID != "unknown-access-key" || errorResponse.StringToSign != "" {
+		t.Fatalf("Unexpected invalid access key error response %#v", errorResponse)
+	}
+
+	// Region errors carry the region of the server.
+	_, errorResponse = writeTestErrorResponse(t, ErrInvalidRegion, req)
+	if errorResponse.Region != globalMinioDefaultRegion {
+		t.Fatalf("Expected region %s, got %#v", globalMinioDefaultRegion, errorResponse)
+	}
+}
@@ -41,6 +41,7 @@ const (
 // The list should be alphabetically sorted
 var resourceList = []string{
 	"acl",
+	"attributes",
 	"delete",
 	"lifecycle",
 	"location",
@@ -65,8 +66,8 @@ var resourceList = []string{
 }
 
 func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
-	cred := serverConfig.GetCredential()
 	accessKey := formValues["Awsaccesskeyid"]
+	cred := getSignatureCredential(accessKey)
 	if accessKey != cred.AccessKey {
 		return ErrInvalidAccessKeyID
 	}
@@ -82,9 +83,6 @@ func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html#RESTAuthenticationQueryStringAuth
 // returns ErrNone if matches. S3 errors otherwise.
 func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// r.RequestURI will have raw encoded URI as sent by the client.
 	splits := splitStr(r.RequestURI, "?", 2)
 	encodedResource, encodedQuery := splits[0], splits[1]
@@ -124,6 +122,9 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		return ErrInvalidQueryParams
 	}
 
+	// Access credentials.
+	cred := getSignatureCredential(accessKey)
+
 	// Validate if access key id same.
 	if accessKey != cred.AccessKey {
 		return ErrInvalidAccessKeyID
@@ -140,7 +141,12 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		return ErrExpiredPresignRequest
 	}
 
-	expectedSignature := preSignatureV2(r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
+	// Presigned URLs may be limited in lifetime and revoked.
+	if s3Error := checkPresignV2Lifetime(accessKey, time.Unix(expiresInt, 0).UTC()); s3Error != ErrNone {
+		return s3Error
+	}
+
+	expectedSignature := preSignatureV2(cred, r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
 	if gotSignature != expectedSignature {
 		return ErrSignatureDoesNotMatch
 	}
@@ -191,7 +197,7 @@ func validateV2AuthHeader(v2Auth string) APIErrorCode {
 	}
 
 	// Access credentials.
-	cred := serverConfig.GetCredential()
+	cred := getSignatureCredential(keySignFields[0])
 	if keySignFields[0] != cred.AccessKey {
 		return ErrInvalidAccessKeyID
 	}
@@ -210,7 +216,8 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 	splits := splitStr(r.RequestURI, "?", 2)
 	encodedResource, encodedQuery := splits[0], splits[1]
 
-	expectedAuth := signatureV2(r.Method, encodedResource, encodedQuery, r.Header)
+	cred := getSignatureCredential(getRequestAccessKey(r))
+	expectedAuth := signatureV2(cred, r.Method, encodedResource, encodedQuery, r.Header)
 	if v2Auth != expectedAuth {
 		return ErrSignatureDoesNotMatch
 	}
@@ -225,15 +232,13 @@ func calculateSignatureV2(stringToSign string, secret string) string {
 }
 
 // Return signature-v2 for the presigned request.
-func preSignatureV2(method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
-	cred := serverConfig.GetCredential()
+func preSignatureV2(cred credential, method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
 	stringToSign := presignV2STS(method, encodedResource, encodedQuery, headers, expires)
 	return calculateSignatureV2(stringToSign, cred.SecretKey)
 }
 
 // Return signature-v2 authrization header.
-func signatureV2(method string, encodedResource string, encodedQuery string, headers http.Header) string {
-	cred := serverConfig.GetCredential()
+func signatureV2(cred credential, method string, encodedResource string, encodedQuery string, headers http.Header) string {
 	stringToSign := signV2STS(method, encodedResource, encodedQuery, headers)
 	signature := calculateSignatureV2(stringToSign, cred.SecretKey)
 	return fmt.Sprintf("%s %s:%s", signV2Algorithm, cred.AccessKey, signature)
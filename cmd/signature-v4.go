@@ -155,13 +155,23 @@ func doesPolicySignatureMatch(formValues map[string]string) APIErrorCode {
 	return doesPolicySignatureV4Match(formValues)
 }
 
+// getPolicyAccessKey - returns the access key the post policy in
+// formValues is signed with.
+func getPolicyAccessKey(formValues map[string]string) string {
+	if formValues["Signature"] != "" {
+		return formValues["Awsaccesskeyid"]
+	}
+	credHeader, err := parseCredentialHeader("Credential=" + formValues["X-Amz-Credential"])
+	if err != ErrNone {
+		return ""
+	}
+	return credHeader.accessKey
+}
+
 // doesPolicySignatureMatch - Verify query headers with post policy
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
 // returns ErrNone if the signature matches.
 func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Server region.
 	region := serverConfig.GetRegion()
 
@@ -171,6 +181,9 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 		return ErrMissingFields
 	}
 
+	// Access credentials.
+	cred := getSignatureCredential(credHeader.accessKey)
+
 	// Verify if the access key id matches.
 	if credHeader.accessKey != cred.AccessKey {
 		return ErrInvalidAccessKeyID
@@ -197,29 +210,33 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 
 // doesPresignedSignatureMatch - Verify query headers with presigned signature
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html
-// returns ErrNone if the signature matches.
-func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
+// returns ErrNone if the signature matches, along with the canonical
+// request and string to sign calculated.
+func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) (canonicalRequest, stringToSign string, s3Error APIErrorCode) {
+	return doesPresignedSignatureMatchWithCred(getSignatureCredential(getRequestAccessKey(r)), hashedPayload, r, region)
+}
 
+// doesPresignedSignatureMatchWithCred - same as doesPresignedSignatureMatch
+// but verifies against the given credential.
+func doesPresignedSignatureMatchWithCred(cred credential, hashedPayload string, r *http.Request, region string) (canonicalRequest, stringToSign string, s3Error APIErrorCode) {
 	// Copy request
 	req := *r
 
 	// Parse request query string.
 	pSignValues, err := parsePreSignV4(req.URL.Query())
 	if err != ErrNone {
-		return err
+		return "", "", err
 	}
 
 	// Verify if the access key id matches.
 	if pSignValues.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+		return "", "", ErrInvalidAccessKeyID
 	}
 
 	// Hashed payload mismatch, return content sha256 mismatch.
 	contentSha256 := req.URL.Query().Get("X-Amz-Content-Sha256")
 	if contentSha256 != "" && hashedPayload != contentSha256 {
-		return ErrContentSHA256Mismatch
+		return "", "", ErrContentSHA256Mismatch
 	}
 
 	// Verify if region is valid.
@@ -229,13 +246,13 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		region = sRegion
 	}
 	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+		return "", "", ErrInvalidRegion
 	}
 
 	// Extract all the signed headers along with its values.
 	extractedSignedHeaders, errCode := extractSignedHeaders(pSignValues.SignedHeaders, req.Header)
 	if errCode != ErrNone {
-		return errCode
+		return "", "", errCode
 	}
 	// Construct new query.
 	query := make(url.Values)
@@ -248,11 +265,16 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	// If the host which signed the request is slightly ahead in time (by less than globalMaxSkewTime) the
 	// request should still be allowed.
 	if pSignValues.Date.After(time.Now().UTC().Add(globalMaxSkewTime)) {
-		return ErrRequestNotReadyYet
+		return "", "", ErrRequestNotReadyYet
 	}
 
 	if time.Now().UTC().Sub(pSignValues.Date) > time.Duration(pSignValues.Expires) {
-		return ErrExpiredPresignRequest
+		return "", "", ErrExpiredPresignRequest
+	}
+
+	// Presigned URLs may be limited in lifetime and revoked.
+	if s3Error := checkPresignV4Lifetime(cred.AccessKey, pSignValues.Date, pSignValues.Expires); s3Error != ErrNone {
+		return "", "", s3Error
 	}
 
 	// Save the date and expires.
@@ -276,57 +298,61 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	// Get the encoded query.
 	encodedQuery := query.Encode()
 
+	// Get canonical request.
+	canonicalRequest = getCanonicalRequest(extractedSignedHeaders, hashedPayload, encodedQuery, req.URL.Path, req.Method, req.Host)
+
+	// Get string to sign from canonical request.
+	stringToSign = getStringToSign(canonicalRequest, t, pSignValues.Credential.getScope())
+
 	// Verify if date query is same.
 	if req.URL.Query().Get("X-Amz-Date") != query.Get("X-Amz-Date") {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
 	// Verify if expires query is same.
 	if req.URL.Query().Get("X-Amz-Expires") != query.Get("X-Amz-Expires") {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
 	// Verify if signed headers query is same.
 	if req.URL.Query().Get("X-Amz-SignedHeaders") != query.Get("X-Amz-SignedHeaders") {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
 	// Verify if credential query is same.
 	if req.URL.Query().Get("X-Amz-Credential") != query.Get("X-Amz-Credential") {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
 	// Verify if sha256 payload query is same.
 	if req.URL.Query().Get("X-Amz-Content-Sha256") != "" {
 		if req.URL.Query().Get("X-Amz-Content-Sha256") != query.Get("X-Amz-Content-Sha256") {
-			return ErrSignatureDoesNotMatch
+			return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 		}
 	}
 
 	/// Verify finally if signature is same.
 
-	// Get canonical request.
-	presignedCanonicalReq := getCanonicalRequest(extractedSignedHeaders, hashedPayload, encodedQuery, req.URL.Path, req.Method, req.Host)
-
-	// Get string to sign from canonical request.
-	presignedStringToSign := getStringToSign(presignedCanonicalReq, t, pSignValues.Credential.getScope())
-
 	// Get hmac presigned signing key.
 	presignedSigningKey := getSigningKey(cred.SecretKey, pSignValues.Credential.scope.date, region)
 
 	// Get new signature.
-	newSignature := getSignature(presignedSigningKey, presignedStringToSign)
+	newSignature := getSignature(presignedSigningKey, stringToSign)
 
 	// Verify signature.
 	if req.URL.Query().Get("X-Amz-Signature") != newSignature {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
-	return ErrNone
+	return canonicalRequest, stringToSign, ErrNone
 }
 
 // doesSignatureMatch - Verify authorization header with calculated header in accordance with
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
-// returns ErrNone if signature matches.
-func doesSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
+// returns ErrNone if signature matches, along with the canonical
+// request and string to sign calculated.
+func doesSignatureMatch(hashedPayload string, r *http.Request, region string) (canonicalRequest, stringToSign string, s3Error APIErrorCode) {
+	return doesSignatureMatchWithCred(getSignatureCredential(getRequestAccessKey(r)), hashedPayload, r, region)
+}
 
+// doesSignatureMatchWithCred - same as doesSignatureMatch but verifies
+// against the given credential.
+func doesSignatureMatchWithCred(cred credential, hashedPayload string, r *http.Request, region string) (canonicalRequest, stringToSign string, s3Error APIErrorCode) {
 	// Copy request.
 	req := *r
 
@@ -336,12 +362,12 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	// Parse signature version '4' header.
 	signV4Values, err := parseSignV4(v4Auth)
 	if err != ErrNone {
-		return err
+		return "", "", err
 	}
 
 	// Hashed payload mismatch, return content sha256 mismatch.
 	if hashedPayload != req.Header.Get("X-Amz-Content-Sha256") {
-		return ErrContentSHA256Mismatch
+		return "", "", ErrContentSHA256Mismatch
 	}
 
 	header := req.Header
@@ -360,12 +386,12 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	// Extract all the signed headers along with its values.
 	extractedSignedHeaders, errCode := extractSignedHeaders(signV4Values.SignedHeaders, header)
 	if errCode != ErrNone {
-		return errCode
+		return "", "", errCode
 	}
 
 	// Verify if the access key id matches.
 	if signV4Values.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+		return "", "", ErrInvalidAccessKeyID
 	}
 
 	// Verify if region is valid.
@@ -379,30 +405,30 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	}
 	// Should validate region, only if region is set.
 	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+		return "", "", ErrInvalidRegion
 	}
 
 	// Extract date, if not present throw error.
 	var date string
 	if date = req.Header.Get(http.CanonicalHeaderKey("x-amz-date")); date == "" {
 		if date = r.Header.Get("Date"); date == "" {
-			return ErrMissingDateHeader
+			return "", "", ErrMissingDateHeader
 		}
 	}
 	// Parse date header.
 	t, e := time.Parse(iso8601Format, date)
 	if e != nil {
-		return ErrMalformedDate
+		return "", "", ErrMalformedDate
 	}
 
 	// Query string.
 	queryStr := req.URL.Query().Encode()
 
 	// Get canonical request.
-	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, hashedPayload, queryStr, req.URL.Path, req.Method, req.Host)
+	canonicalRequest = getCanonicalRequest(extractedSignedHeaders, hashedPayload, queryStr, req.URL.Path, req.Method, req.Host)
 
 	// Get string to sign from canonical request.
-	stringToSign := getStringToSign(canonicalRequest, t, signV4Values.Credential.getScope())
+	stringToSign = getStringToSign(canonicalRequest, t, signV4Values.Credential.getScope())
 
 	// Get hmac signing key.
 	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, region)
@@ -412,9 +438,9 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 
 	// Verify if signature match.
 	if newSignature != signV4Values.Signature {
-		return ErrSignatureDoesNotMatch
+		return canonicalRequest, stringToSign, ErrSignatureDoesNotMatch
 	}
 
 	// Return error none.
-	return ErrNone
+	return canonicalRequest, stringToSign, ErrNone
 }
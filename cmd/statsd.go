@@ -0,0 +1,263 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interval between flushes of metrics, unless configured.
+const defaultStatsdFlushInterval = 10 * time.Second
+
+// Metrics are sent in UDP packets of at most this size, which fits
+// in the MTU of common networks.
+const statsdMaxPacketSize = 1432
+
+// statsdConfig - configures the StatsD metrics emitter.
+type statsdConfig struct {
+	// StatsD or Datadog agent UDP address, e.g. "localhost:8125".
+	// Metrics are not sent if empty.
+	Address string `json:"address"`
+
+	// Prefix of all metric names, e.g. "minio.".
+	Prefix string `json:"prefix"`
+
+	// Interval between flushes, e.g. "10s", defaults to 10 seconds.
+	FlushInterval string `json:"flushInterval"`
+}
+
+// validateStatsdConfig - validates config, returns the flush
+// interval.
+func validateStatsdConfig(config statsdConfig) (time.Duration, error) {
+	if config.Address == "" {
+		return 0, nil
+	}
+	if _, _, err := net.SplitHostPort(config.Address); err != nil {
+		return 0, err
+	}
+	if strings.ContainsAny(config.Prefix, ":|@\n") {
+		return 0, fmt.Errorf("Invalid prefix %s", config.Prefix)
+	}
+	if config.FlushInterval == "" {
+		return defaultStatsdFlushInterval, nil
+	}
+	interval, err := time.ParseDuration(config.FlushInterval)
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, errors.New("Flush interval must be positive")
+	}
+	return interval, nil
+}
+
+// statsdEmitter - aggregates counters between flushes and sends
+// them, along with timings and storage gauges, to a StatsD server.
+type statsdEmitter struct {
+	conn     net.Conn
+	prefix   string
+	interval time.Duration
+
+	mu       sync.Mutex
+	counters map[string]int64
+	// Pending timings, sent once a packet is full or on flush.
+	buf bytes.Buffer
+
+	// Network totals at the last flush.
+	lastInputBytes  uint64
+	lastOutputBytes uint64
+
+	// Notification target counters at the last flush, by ARN.
+	lastNotifyTargets map[string]NotifyTargetStats
+}
+
+// newStatsdEmitter - returns nil if no StatsD address is configured.
+func newStatsdEmitter(config statsdConfig) (*statsdEmitter, error) {
+	interval, err := validateStatsdConfig(config)
+	if err != nil || config.Address == "" {
+		return nil, err
+	}
+	conn, err := net.Dial("udp", config.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &statsdEmitter{
+		conn:     conn,
+		prefix:   config.Prefix,
+		interval: interval,
+		counters: make(map[string]int64),
+
+		lastNotifyTargets: make(map[string]NotifyTargetStats),
+		lastInputBytes:    globalConnStats.getTotalInputBytes(),
+		lastOutputBytes:   globalConnStats.getTotalOutputBytes(),
+	}, nil
+}
+
+// writeMetric - adds a metric line to the pending packet, sending the
+// packet first if the line does not fit. Called with s.mu held.
+func (s *statsdEmitter) writeMetric(name, value, kind string) {
+	line := fmt.Sprintf("%s%s:%s|%s", s.prefix, name, value, kind)
+	if s.buf.Len() > 0 && s.buf.Len()+1+len(line) > statsdMaxPacketSize {
+		s.send()
+	}
+	if s.buf.Len() > 0 {
+		s.buf.WriteByte('\n')
+	}
+	s.buf.WriteString(line)
+}
+
+// send - sends the pending packet. Called with s.mu held.
+func (s *statsdEmitter) send() {
+	if s.buf.Len() == 0 {
+		return
+	}
+	// Metrics are best effort, the server may not be up yet.
+	s.conn.Write(s.buf.Bytes())
+	s.buf.Reset()
+}
+
+// count - adds n to a counter.
+func (s *statsdEmitter) count(name string, n int64) {
+	s.mu.Lock()
+	s.counters[name] += n
+	s.mu.Unlock()
+}
+
+// timing - records the duration of an operation.
+func (s *statsdEmitter) timing(name string, d time.Duration) {
+	s.mu.Lock()
+	s.writeMetric(name, fmt.Sprintf("%d", d/time.Millisecond), "ms")
+	s.mu.Unlock()
+}
+
+// recordRequest - records the latency and outcome of an API request.
+func (s *statsdEmitter) recordRequest(method string, status int, d time.Duration) {
+	if s == nil {
+		return
+	}
+	method = strings.ToLower(method)
+	s.count("api.requests."+method, 1)
+	switch {
+	case status >= http.StatusInternalServerError:
+		s.count("api.errors."+method, 1)
+	case status >= http.StatusBadRequest:
+		s.count("api.client_errors."+method, 1)
+	}
+	s.timing("api.latency."+method, d)
+}
+
+// flush - sends counters aggregated since the last flush, network
+// totals and storage gauges.
+func (s *statsdEmitter) flush() {
+	inputBytes := globalConnStats.getTotalInputBytes()
+	outputBytes := globalConnStats.getTotalOutputBytes()
+
+	var notifyTargets []NotifyTargetStats
+	if globalEventNotifier != nil {
+		var arns []string
+		for arn := range globalEventNotifier.GetAllExternalTargets() {
+			arns = append(arns, arn)
+		}
+		notifyTargets = getNotifyTargetStats(arns)
+	}
+
+	var storageInfo *StorageInfo
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		info := objAPI.StorageInfo()
+		storageInfo = &info
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters["network.received_bytes"] += int64(inputBytes - s.lastInputBytes)
+	s.counters["network.sent_bytes"] += int64(outputBytes - s.lastOutputBytes)
+	s.lastInputBytes, s.lastOutputBytes = inputBytes, outputBytes
+
+	for _, target := range notifyTargets {
+		name := notifyTargetMetricName(target.ARN)
+		last := s.lastNotifyTargets[target.ARN]
+		s.counters[name+".sent"] += int64(target.Sent - last.Sent)
+		s.counters[name+".failed"] += int64(target.Failed - last.Failed)
+		s.lastNotifyTargets[target.ARN] = target
+	}
+
+	// Sorted for a stable packet layout.
+	names := make([]string, 0, len(s.counters))
+	for name := range s.counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.writeMetric(name, fmt.Sprintf("%d", s.counters[name]), "c")
+	}
+	s.counters = make(map[string]int64)
+
+	for _, target := range notifyTargets {
+		s.writeMetric(notifyTargetMetricName(target.ARN)+".pending", fmt.Sprintf("%d", target.Pending), "g")
+	}
+	if storageInfo != nil {
+		s.writeMetric("storage.total_bytes", fmt.Sprintf("%d", storageInfo.Total), "g")
+		s.writeMetric("storage.free_bytes", fmt.Sprintf("%d", storageInfo.Free), "g")
+		if storageInfo.Backend.Type == Erasure {
+			s.writeMetric("storage.online_disks", fmt.Sprintf("%d", storageInfo.Backend.OnlineDisks), "g")
+			s.writeMetric("storage.offline_disks", fmt.Sprintf("%d", storageInfo.Backend.OfflineDisks), "g")
+		}
+	}
+	s.send()
+}
+
+// notifyTargetMetricName - returns the metric name prefix of a
+// notification target, e.g. "notify.webhook.1" for
+// arn:minio:sqs:us-east-1:1:webhook.
+func notifyTargetMetricName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 2 {
+		return "notify." + arn
+	}
+	return "notify." + parts[len(parts)-1] + "." + parts[len(parts)-2]
+}
+
+// run - flushes metrics every interval, never returns.
+func (s *statsdEmitter) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.flush()
+	}
+}
+
+// initStatsd - initializes the global StatsD emitter from server
+// config and starts flushing metrics, metrics are not sent when no
+// address is configured.
+func initStatsd() error {
+	s, err := newStatsdEmitter(serverConfig.GetStatsd())
+	if err != nil || s == nil {
+		return err
+	}
+	go s.run()
+	globalStatsd = s
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests validating StatsD configuration.
+func TestValidateStatsdConfig(t *testing.T) {
+	testCases := []struct {
+		config    statsdConfig
+		interval  time.Duration
+		shouldErr bool
+	}{
+		// Metrics disabled.
+		{statsdConfig{}, 0, false},
+		{statsdConfig{Address: "localhost:8125"}, defaultStatsdFlushInterval, false},
+		{statsdConfig{Address: "localhost:8125", Prefix: "minio.", FlushInterval: "1m"}, time.Minute, false},
+		// Missing port.
+		{statsdConfig{Address: "localhost"}, 0, true},
+		// Prefix with a StatsD separator.
+		{statsdConfig{Address: "localhost:8125", Prefix: "minio|"}, 0, true},
+		// Invalid flush intervals.
+		{statsdConfig{Address: "localhost:8125", FlushInterval: "10"}, 0, true},
+		{statsdConfig{Address: "localhost:8125", FlushInterval: "-1s"}, 0, true},
+	}
+	for i, testCase := range testCases {
+		interval, err := validateStatsdConfig(testCase.config)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+		if interval != testCase.interval {
+			t.Errorf("Test %d: Expected interval %v, got %v", i+1, testCase.interval, interval)
+		}
+	}
+}
+
+// Tests metrics sent to a StatsD server.
+func TestStatsdEmitter(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	s, err := newStatsdEmitter(statsdConfig{Address: server.LocalAddr().String(), Prefix: "minio."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == nil {
+		t.Fatal("Expected an emitter")
+	}
+
+	readPacket := func() []string {
+		buf := make([]byte, 2*statsdMaxPacketSize)
+		server.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, rerr := server.ReadFrom(buf)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		if n > statsdMaxPacketSize {
+			t.Fatalf("Packet of %d bytes exceeds the maximum size", n)
+		}
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	// No target metrics without an event notifier.
+	savedEventNotifier := globalEventNotifier
+	globalEventNotifier = nil
+	defer func() { globalEventNotifier = savedEventNotifier }()
+
+	// No storage gauges without an object layer.
+	globalObjLayerMutex.Lock()
+	savedObjectAPI := globalObjectAPI
+	globalObjectAPI = nil
+	globalObjLayerMutex.Unlock()
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = savedObjectAPI
+		globalObjLayerMutex.Unlock()
+	}()
+
+	s.recordRequest("GET", 200, 15*time.Millisecond)
+	s.recordRequest("GET", 404, time.Millisecond)
+	s.recordRequest("PUT", 503, 2*time.Second)
+	s.flush()
+
+	expected := []string{
+		"minio.api.latency.get:15|ms",
+		"minio.api.latency.get:1|ms",
+		"minio.api.latency.put:2000|ms",
+		"minio.api.client_errors.get:1|c",
+		"minio.api.errors.put:1|c",
+		"minio.api.requests.get:2|c",
+		"minio.api.requests.put:1|c",
+		"minio.network.received_bytes:0|c",
+		"minio.network.sent_bytes:0|c",
+	}
+	lines := readPacket()
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected metrics %v, got %v", expected, lines)
+	}
+
+	// Counters are reset by flushes, network totals are sent as
+	// increments.
+	globalConnStats.incInputBytes(100)
+	s.flush()
+	lines = readPacket()
+	if len(lines) != 2 || lines[0] != "minio.network.received_bytes:100|c" {
+		t.Fatalf("Unexpected metrics %v", lines)
+	}
+
+	// Storage gauges are sent on flush.
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+	s.flush()
+	lines = readPacket()
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "minio.storage.total_bytes:") ||
+		!strings.HasPrefix(lines[3], "minio.storage.free_bytes:") {
+		t.Fatalf("Unexpected metrics %v", lines)
+	}
+
+	// Notification target metrics.
+	arn := "arn:minio:sqs:us-east-1:1:webhook"
+	counters := getNotifyTargetCounters(arn)
+	counters.sent.Inc(3)
+	counters.failed.Inc(1)
+	globalEventNotifier = &eventNotifier{
+		external: externalNotifier{
+			targets: map[string]*logrus.Logger{arn: logrus.New()},
+			rwMutex: &sync.RWMutex{},
+		},
+	}
+	s.flush()
+	lines = readPacket()
+	expected = []string{
+		"minio.network.received_bytes:0|c",
+		"minio.network.sent_bytes:0|c",
+		"minio.notify.webhook.1.failed:1|c",
+		"minio.notify.webhook.1.sent:3|c",
+		"minio.notify.webhook.1.pending:0|g",
+	}
+	if strings.Join(lines[:5], "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected metrics %v, got %v", expected, lines)
+	}
+
+	// Only new events are counted by the next flush.
+	counters.sent.Inc(2)
+	s.flush()
+	lines = readPacket()
+	if lines[2] != "minio.notify.webhook.1.failed:0|c" || lines[3] != "minio.notify.webhook.1.sent:2|c" {
+		t.Fatalf("Unexpected metrics %v", lines)
+	}
+
+	// Timings are sent once a packet is full.
+	for i := 0; i < statsdMaxPacketSize; i++ {
+		s.recordRequest("HEAD", 200, time.Millisecond)
+	}
+	lines = readPacket()
+	if lines[0] != "minio.api.latency.head:1|ms" {
+		t.Fatalf("Unexpected metrics %v", lines)
+	}
+
+	// A nil emitter records nothing.
+	var nilEmitter *statsdEmitter
+	nilEmitter.recordRequest("GET", 200, time.Millisecond)
+}
@@ -40,9 +40,11 @@ type StorageAPI interface {
 	PrepareFile(volume string, path string, len int64) (err error)
 	AppendFile(volume string, path string, buf []byte) (err error)
 	RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error
+	MakeDir(volume string, dirPath string) (err error)
 	StatFile(volume string, path string) (file FileInfo, err error)
 	DeleteFile(volume string, path string) (err error)
 
 	// Read all.
 	ReadAll(volume string, path string) (buf []byte, err error)
+	ReadAllBulk(volume string, paths []string) (bufs [][]byte, errs []error)
 }
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net"
 	"net/rpc"
@@ -249,6 +250,34 @@ func (n *networkStorage) ReadAll(volume, path string) (buf []byte, err error) {
 	return buf, nil
 }
 
+// ReadAllBulk - reads the entire contents of each of the files at
+// paths with a single round trip, errors are returned per file.
+func (n *networkStorage) ReadAllBulk(volume string, paths []string) (bufs [][]byte, errs []error) {
+	reply := ReadAllBulkReply{}
+	errs = make([]error, len(paths))
+	if err := n.rpcClient.Call("Storage.ReadAllBulkHandler", &ReadAllBulkArgs{
+		Vol:   volume,
+		Paths: paths,
+	}, &reply); err != nil {
+		for i := range errs {
+			errs[i] = toStorageErr(err)
+		}
+		return make([][]byte, len(paths)), errs
+	}
+	if len(reply.Bufs) != len(paths) || len(reply.Errs) != len(paths) {
+		for i := range errs {
+			errs[i] = errUnexpected
+		}
+		return make([][]byte, len(paths)), errs
+	}
+	for i, errStr := range reply.Errs {
+		if errStr != "" {
+			errs[i] = toStorageErr(errors.New(errStr))
+		}
+	}
+	return reply.Bufs, errs
+}
+
 // ReadFile - reads a file at remote path and fills the buffer.
 func (n *networkStorage) ReadFile(volume string, path string, offset int64, buffer []byte) (m int64, err error) {
 	defer func() {
@@ -297,6 +326,18 @@ func (n *networkStorage) DeleteFile(volume, path string) (err error) {
 	return nil
 }
 
+// MakeDir - create a remote directory at path.
+func (n *networkStorage) MakeDir(volume, dirPath string) (err error) {
+	reply := AuthRPCReply{}
+	if err = n.rpcClient.Call("Storage.MakeDirHandler", &MakeDirArgs{
+		Vol:  volume,
+		Path: dirPath,
+	}, &reply); err != nil {
+		return toStorageErr(err)
+	}
+	return nil
+}
+
 // RenameFile - rename a remote file from source to destination.
 func (n *networkStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
 	reply := AuthRPCReply{}
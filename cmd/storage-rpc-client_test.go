@@ -310,6 +310,16 @@ func (s *TestRPCStorageSuite) testRPCStorageFileOps(t *testing.T) {
 		if !bytes.Equal(buf, []byte("Hello, world")) {
 			t.Errorf("Expected `Hello, world`, got %s", string(buf))
 		}
+		bufs, errs := storageDisk.ReadAllBulk("myvol", []string{"file1", "file3"})
+		if len(bufs) != 2 || len(errs) != 2 {
+			t.Fatalf("Expected 2 results, got %d and %d", len(bufs), len(errs))
+		}
+		if errs[0] != nil || !bytes.Equal(bufs[0], buf) {
+			t.Errorf("Expected `Hello, world`, got %s and %v", string(bufs[0]), errs[0])
+		}
+		if errs[1] != errFileNotFound {
+			t.Errorf("Expected %s, got %v", errFileNotFound, errs[1])
+		}
 		buf1 := make([]byte, 5)
 		n, err := storageDisk.ReadFile("myvol", "file1", 4, buf1)
 		if err != nil {
@@ -43,6 +43,27 @@ type ReadAllArgs struct {
 	Path string
 }
 
+// ReadAllBulkArgs represents read all bulk RPC arguments.
+type ReadAllBulkArgs struct {
+	// Authentication token generated by Login.
+	AuthRPCArgs
+
+	// Name of the volume.
+	Vol string
+
+	// Names of the paths.
+	Paths []string
+}
+
+// ReadAllBulkReply represents read all bulk RPC reply.
+type ReadAllBulkReply struct {
+	// Contents of the files, in the order of the paths.
+	Bufs [][]byte
+
+	// Errors reading the files, empty on success.
+	Errs []string
+}
+
 // ReadFileArgs represents read file RPC arguments.
 type ReadFileArgs struct {
 	// Authentication token generated by Login.
@@ -115,6 +136,18 @@ type DeleteFileArgs struct {
 	Path string
 }
 
+// MakeDirArgs represents make dir RPC arguments.
+type MakeDirArgs struct {
+	// Authentication token generated by Login.
+	AuthRPCArgs
+
+	// Name of the volume.
+	Vol string
+
+	// Name of the path.
+	Path string
+}
+
 // ListDirArgs represents list contents RPC arguments.
 type ListDirArgs struct {
 	// Authentication token generated by Login.
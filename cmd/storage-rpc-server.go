@@ -35,6 +35,16 @@ type storageServer struct {
 	timestamp time.Time
 }
 
+// startTraceSpan - starts the span of a disk operation on vol and
+// path, returns nil unless the caller traces the call.
+func (s *storageServer) startTraceSpan(args AuthRPCArgs, op, vol, path string) *traceSpan {
+	span := args.startTraceSpan("Storage." + op)
+	span.setTag("disk", s.path)
+	span.setTag("vol", vol)
+	span.setTag("path", path)
+	return span
+}
+
 /// Storage operations handlers.
 
 // DiskInfoHandler - disk info handler is rpc wrapper for DiskInfo operation.
@@ -43,7 +53,9 @@ func (s *storageServer) DiskInfoHandler(args *AuthRPCArgs, reply *disk.Info) err
 		return err
 	}
 
+	span := s.startTraceSpan(*args, "DiskInfo", "", "")
 	info, err := s.storage.DiskInfo()
+	span.finish(err)
 	*reply = info
 	return err
 }
@@ -56,7 +68,10 @@ func (s *storageServer) MakeVolHandler(args *GenericVolArgs, reply *AuthRPCReply
 		return err
 	}
 
-	return s.storage.MakeVol(args.Vol)
+	span := s.startTraceSpan(args.AuthRPCArgs, "MakeVol", args.Vol, "")
+	err := s.storage.MakeVol(args.Vol)
+	span.finish(err)
+	return err
 }
 
 // ListVolsHandler - list vols handler is rpc wrapper for ListVols operation.
@@ -65,7 +80,9 @@ func (s *storageServer) ListVolsHandler(args *AuthRPCArgs, reply *ListVolsReply)
 		return err
 	}
 
+	span := s.startTraceSpan(*args, "ListVols", "", "")
 	vols, err := s.storage.ListVols()
+	span.finish(err)
 	if err != nil {
 		return err
 	}
@@ -79,7 +96,9 @@ func (s *storageServer) StatVolHandler(args *GenericVolArgs, reply *VolInfo) err
 		return err
 	}
 
+	span := s.startTraceSpan(args.AuthRPCArgs, "StatVol", args.Vol, "")
 	volInfo, err := s.storage.StatVol(args.Vol)
+	span.finish(err)
 	if err != nil {
 		return err
 	}
@@ -94,7 +113,10 @@ func (s *storageServer) DeleteVolHandler(args *GenericVolArgs, reply *AuthRPCRep
 		return err
 	}
 
-	return s.storage.DeleteVol(args.Vol)
+	span := s.startTraceSpan(args.AuthRPCArgs, "DeleteVol", args.Vol, "")
+	err := s.storage.DeleteVol(args.Vol)
+	span.finish(err)
+	return err
 }
 
 /// File operations
@@ -105,7 +127,9 @@ func (s *storageServer) StatFileHandler(args *StatFileArgs, reply *FileInfo) err
 		return err
 	}
 
+	span := s.startTraceSpan(args.AuthRPCArgs, "StatFile", args.Vol, args.Path)
 	fileInfo, err := s.storage.StatFile(args.Vol, args.Path)
+	span.finish(err)
 	if err != nil {
 		return err
 	}
@@ -119,7 +143,9 @@ func (s *storageServer) ListDirHandler(args *ListDirArgs, reply *[]string) error
 		return err
 	}
 
+	span := s.startTraceSpan(args.AuthRPCArgs, "ListDir", args.Vol, args.Path)
 	entries, err := s.storage.ListDir(args.Vol, args.Path)
+	span.finish(err)
 	if err != nil {
 		return err
 	}
@@ -133,7 +159,9 @@ func (s *storageServer) ReadAllHandler(args *ReadFileArgs, reply *[]byte) error
 		return err
 	}
 
+	span := s.startTraceSpan(args.AuthRPCArgs, "ReadAll", args.Vol, args.Path)
 	buf, err := s.storage.ReadAll(args.Vol, args.Path)
+	span.finish(err)
 	if err != nil {
 		return err
 	}
@@ -141,6 +169,25 @@ func (s *storageServer) ReadAllHandler(args *ReadFileArgs, reply *[]byte) error
 	return nil
 }
 
+// ReadAllBulkHandler - read all bulk handler is rpc wrapper to read all bulk storage API.
+func (s *storageServer) ReadAllBulkHandler(args *ReadAllBulkArgs, reply *ReadAllBulkReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	span := s.startTraceSpan(args.AuthRPCArgs, "ReadAllBulk", args.Vol, "")
+	bufs, errs := s.storage.ReadAllBulk(args.Vol, args.Paths)
+	span.finish(nil)
+	reply.Bufs = bufs
+	reply.Errs = make([]string, len(errs))
+	for i, err := range errs {
+		if err != nil {
+			reply.Errs[i] = err.Error()
+		}
+	}
+	return nil
+}
+
 // ReadFileHandler - read file handler is rpc wrapper to read file.
 func (s *storageServer) ReadFileHandler(args *ReadFileArgs, reply *[]byte) (err error) {
 	if err = args.IsAuthenticated(); err != nil {
@@ -148,7 +195,9 @@ func (s *storageServer) ReadFileHandler(args *ReadFileArgs, reply *[]byte) (err
 	}
 
 	var n int64
+	span := s.startTraceSpan(args.AuthRPCArgs, "ReadFile", args.Vol, args.Path)
 	n, err = s.storage.ReadFile(args.Vol, args.Path, args.Offset, args.Buffer)
+	span.finish(err)
 	// Sending an error over the rpc layer, would cause unmarshalling to fail. In situations
 	// when we have short read i.e `io.ErrUnexpectedEOF` treat it as good condition and copy
 	// the buffer properly.
@@ -166,7 +215,10 @@ func (s *storageServer) PrepareFileHandler(args *PrepareFileArgs, reply *AuthRPC
 		return err
 	}
 
-	return s.storage.PrepareFile(args.Vol, args.Path, args.Size)
+	span := s.startTraceSpan(args.AuthRPCArgs, "PrepareFile", args.Vol, args.Path)
+	err := s.storage.PrepareFile(args.Vol, args.Path, args.Size)
+	span.finish(err)
+	return err
 }
 
 // AppendFileHandler - append file handler is rpc wrapper to append file.
@@ -175,7 +227,10 @@ func (s *storageServer) AppendFileHandler(args *AppendFileArgs, reply *AuthRPCRe
 		return err
 	}
 
-	return s.storage.AppendFile(args.Vol, args.Path, args.Buffer)
+	span := s.startTraceSpan(args.AuthRPCArgs, "AppendFile", args.Vol, args.Path)
+	err := s.storage.AppendFile(args.Vol, args.Path, args.Buffer)
+	span.finish(err)
+	return err
 }
 
 // DeleteFileHandler - delete file handler is rpc wrapper to delete file.
@@ -184,7 +239,22 @@ func (s *storageServer) DeleteFileHandler(args *DeleteFileArgs, reply *AuthRPCRe
 		return err
 	}
 
-	return s.storage.DeleteFile(args.Vol, args.Path)
+	span := s.startTraceSpan(args.AuthRPCArgs, "DeleteFile", args.Vol, args.Path)
+	err := s.storage.DeleteFile(args.Vol, args.Path)
+	span.finish(err)
+	return err
+}
+
+// MakeDirHandler - make dir handler is rpc wrapper to create a directory.
+func (s *storageServer) MakeDirHandler(args *MakeDirArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	span := s.startTraceSpan(args.AuthRPCArgs, "MakeDir", args.Vol, args.Path)
+	err := s.storage.MakeDir(args.Vol, args.Path)
+	span.finish(err)
+	return err
 }
 
 // RenameFileHandler - rename file handler is rpc wrapper to rename file.
@@ -193,7 +263,10 @@ func (s *storageServer) RenameFileHandler(args *RenameFileArgs, reply *AuthRPCRe
 		return err
 	}
 
-	return s.storage.RenameFile(args.SrcVol, args.SrcPath, args.DstVol, args.DstPath)
+	span := s.startTraceSpan(args.AuthRPCArgs, "RenameFile", args.SrcVol, args.SrcPath)
+	err := s.storage.RenameFile(args.SrcVol, args.SrcPath, args.DstVol, args.DstPath)
+	span.finish(err)
+	return err
 }
 
 // Initialize new storage rpc.
@@ -152,6 +152,13 @@ func TestStorageRPCInvalidToken(t *testing.T) {
 	err = storageRPC.ReadFileHandler(readFileArgs, readFileReply)
 	errorIfInvalidToken(t, err)
 
+	readAllBulkArgs := &ReadAllBulkArgs{
+		AuthRPCArgs: badAuthRPCArgs,
+	}
+	readAllBulkReply := &ReadAllBulkReply{}
+	err = storageRPC.ReadAllBulkHandler(readAllBulkArgs, readAllBulkReply)
+	errorIfInvalidToken(t, err)
+
 	// 10. PrepareFileHandler
 	prepFileArgs := &PrepareFileArgs{
 		AuthRPCArgs: badAuthRPCArgs,
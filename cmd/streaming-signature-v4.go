@@ -41,10 +41,7 @@ const (
 )
 
 // getChunkSignature - get chunk signature.
-func getChunkSignature(seedSignature string, date time.Time, hashedChunk string) string {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
+func getChunkSignature(cred credential, seedSignature string, date time.Time, hashedChunk string) string {
 	// Server region.
 	region := serverConfig.GetRegion()
 
@@ -70,9 +67,6 @@ func getChunkSignature(seedSignature string, date time.Time, hashedChunk string)
 // returns signature, error otherwise if the signature mismatches or any other
 // error while parsing and validating.
 func calculateSeedSignature(r *http.Request) (signature string, date time.Time, errCode APIErrorCode) {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Server region.
 	region := serverConfig.GetRegion()
 
@@ -101,6 +95,9 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 	if errCode != ErrNone {
 		return "", time.Time{}, errCode
 	}
+	// Access credentials.
+	cred := getSignatureCredential(signV4Values.Credential.accessKey)
+
 	// Verify if the access key id matches.
 	if signV4Values.Credential.accessKey != cred.AccessKey {
 		return "", time.Time{}, ErrInvalidAccessKeyID
@@ -146,9 +143,15 @@ func calculateSeedSignature(r *http.Request) (signature string, date time.Time,
 
 	// Verify if signature match.
 	if newSignature != signV4Values.Signature {
+		setSignatureDetails(r, canonicalRequest, stringToSign)
 		return "", time.Time{}, ErrSignatureDoesNotMatch
 	}
 
+	// Admit the request within the limits of its access key.
+	if errCode = checkRequestLimit(r, cred.AccessKey); errCode != ErrNone {
+		return "", time.Time{}, errCode
+	}
+
 	// Return caculated signature.
 	return newSignature, date, ErrNone
 }
@@ -174,6 +177,7 @@ func newSignV4ChunkedReader(req *http.Request) (io.Reader, APIErrorCode) {
 	}
 	return &s3ChunkedReader{
 		reader:            bufio.NewReader(req.Body),
+		cred:              getSignatureCredential(getRequestAccessKey(req)),
 		seedSignature:     seedSignature,
 		seedDate:          seedDate,
 		chunkSHA256Writer: sha256.New(),
@@ -185,6 +189,7 @@ func newSignV4ChunkedReader(req *http.Request) (io.Reader, APIErrorCode) {
 // AWS Signature V4 chunked reader.
 type s3ChunkedReader struct {
 	reader            *bufio.Reader
+	cred              credential
 	seedSignature     string
 	seedDate          time.Time
 	state             chunkState
@@ -304,7 +309,7 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 			// Calculate the hashed chunk.
 			hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
 			// Calculate the chunk signature.
-			newSignature := getChunkSignature(cr.seedSignature, cr.seedDate, hashedChunk)
+			newSignature := getChunkSignature(cr.cred, cr.seedSignature, cr.seedDate, hashedChunk)
 			if cr.chunkSignature != newSignature {
 				// Chunk signature doesn't match we return signature does not match.
 				cr.err = errSignatureMismatch
@@ -1403,6 +1403,23 @@ func makeTestTargetURL(endPoint, bucketName, objectName string, queryValues url.
 	return urlStr
 }
 
+// blankRequestID - blanks the request id of the error response body
+// written to rec, which differs for every request, to compare it with
+// a response built with an empty request id.
+func blankRequestID(content []byte, rec *httptest.ResponseRecorder) []byte {
+	requestID := rec.Header().Get(responseRequestIDKey)
+	return bytes.Replace(content, []byte("<RequestId>"+requestID+"</RequestId>"), []byte("<RequestId></RequestId>"), 1)
+}
+
+// getInvalidAccessKeyIDResponse - returns the error response to a
+// request on resource signed with an unknown accessKey, with a blank
+// request id.
+func getInvalidAccessKeyIDResponse(resource, accessKey string) []byte {
+	errorResponse := getAPIErrorResponse(getAPIError(ErrInvalidAccessKeyID), resource, "")
+	errorResponse.AWSAccessKeyID = accessKey
+	return encodeResponse(errorResponse)
+}
+
 // return URL for uploading object into the bucket.
 func getPutObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
@@ -1427,6 +1444,13 @@ func getGetObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
 }
 
+// return URL for fetching attributes of the object.
+func getGetObjectAttributesURL(endPoint, bucketName, objectName string) string {
+	queryValues := url.Values{}
+	queryValues.Set("attributes", "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValues)
+}
+
 // return URL for deleting the object from the bucket.
 func getDeleteObjectURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, url.Values{})
@@ -1541,6 +1565,30 @@ func getListObjectsV2URL(endPoint, bucketName string, maxKeys string, fetchOwner
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for fetching usage of a prefix in the bucket.
+func getBucketUsageURL(endPoint, bucketName, prefix string) string {
+	queryValue := url.Values{}
+	queryValue.Set("du", "")
+	queryValue.Set("prefix", prefix)
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for a tar archive of the objects under prefix.
+func getBucketTarURL(endPoint, bucketName, prefix string) string {
+	queryValue := url.Values{}
+	queryValue.Set("tar", "")
+	queryValue.Set("prefix", prefix)
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for extracting an archive under prefix.
+func getBucketExtractURL(endPoint, bucketName, prefix string) string {
+	queryValue := url.Values{}
+	queryValue.Set("extract", "")
+	queryValue.Set("prefix", prefix)
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for a new multipart upload.
 func getNewMultipartURL(endPoint, bucketName, objectName string) string {
 	queryValue := url.Values{}
@@ -1830,7 +1878,7 @@ func ExecObjectLayerAPIAnonTest(t *testing.T, testName, bucketName, objectName,
 	}
 
 	// expected error response in bytes when objectLayer is not initialized, or set to `nil`.
-	expectedErrResponse := encodeResponse(getAPIErrorResponse(getAPIError(ErrAccessDenied), getGetObjectURL("", bucketName, objectName)))
+	expectedErrResponse := encodeResponse(getAPIErrorResponse(getAPIError(ErrAccessDenied), getGetObjectURL("", bucketName, objectName), ""))
 
 	// HEAD HTTTP request doesn't contain response body.
 	if anonReq.Method != "HEAD" {
@@ -1839,6 +1887,7 @@ func ExecObjectLayerAPIAnonTest(t *testing.T, testName, bucketName, objectName,
 		if err != nil {
 			t.Fatal(failTestStr(anonTestStr, fmt.Sprintf("Failed parsing response body: <ERROR> %v", err)))
 		}
+		actualContent = blankRequestID(actualContent, rec)
 		// verify whether actual error response (from the response body), matches the expected error response.
 		if !bytes.Equal(expectedErrResponse, actualContent) {
 			t.Fatal(failTestStr(anonTestStr, "error response content differs from expected value"))
@@ -1864,7 +1913,7 @@ func ExecObjectLayerAPIAnonTest(t *testing.T, testName, bucketName, objectName,
 	if testName == "TestAPIDeleteObjectHandler" || testName == "TestAPIAbortMultipartHandler" {
 		expectedHTTPStatus = http.StatusNoContent
 	} else if strings.Contains(testName, "BucketPolicyHandler") || testName == "ListBucketsHandler" {
-		// BucketPolicyHandlers and `ListBucketsHandler` doesn't support anonymous request, policy changes should allow unsigned requests.
+		// BucketPolicyHandlers don't support anonymous requests, and `ListBucketsHandler` needs s3:ListAllMyBuckets which the write only policy doesn't grant.
 		expectedHTTPStatus = http.StatusForbidden
 	} else {
 		// other API handlers return 200OK on success.
@@ -1892,6 +1941,7 @@ func ExecObjectLayerAPIAnonTest(t *testing.T, testName, bucketName, objectName,
 		if err != nil {
 			t.Fatal(failTestStr(unknownSignTestStr, fmt.Sprintf("Failed parsing response body: <ERROR> %v", err)))
 		}
+		actualContent = blankRequestID(actualContent, rec)
 		// verify whether actual error response (from the response body), matches the expected error response.
 		if !bytes.Equal(expectedErrResponse, actualContent) {
 			fmt.Println(string(expectedErrResponse))
@@ -1932,7 +1982,7 @@ func ExecObjectLayerAPINilTest(t TestErrHandler, bucketName, objectName, instanc
 	}
 	// expected error response in bytes when objectLayer is not initialized, or set to `nil`.
 	expectedErrResponse := encodeResponse(getAPIErrorResponse(getAPIError(ErrServerNotInitialized),
-		getGetObjectURL("", bucketName, objectName)))
+		getGetObjectURL("", bucketName, objectName), ""))
 
 	// HEAD HTTP Request doesn't contain body in its response,
 	// for other type of HTTP requests compare the response body content with the expected one.
@@ -1942,6 +1992,7 @@ func ExecObjectLayerAPINilTest(t TestErrHandler, bucketName, objectName, instanc
 		if err != nil {
 			t.Fatalf("Minio %s: Failed parsing response body: <ERROR> %v", instanceType, err)
 		}
+		actualContent = blankRequestID(actualContent, rec)
 		// verify whether actual error response (from the response body), matches the expected error response.
 		if !bytes.Equal(expectedErrResponse, actualContent) {
 			t.Errorf("Minio %s: Object content differs from expected value", instanceType)
@@ -2089,6 +2140,9 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "GetObject":
 			// Register GetObject handler.
 			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+		case "GetObjectAttributes":
+			// Register GetObjectAttributes handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectAttributesHandler).Queries("attributes", "")
 		case "PutObject":
 			// Register PutObject handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
@@ -2113,6 +2167,42 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "HeadBucket":
 			// Register HeadBucket handler.
 			bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
+		case "PutBucket":
+			// Register PutBucket handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
+		case "GetBucketTar":
+			// Register GetBucketTar handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketTarHandler).Queries("tar", "")
+		case "PutBucketExtract":
+			// Register PutBucketExtract handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketExtractHandler).Queries("extract", "")
+		case "GetBucketUsage":
+			// Register GetBucketUsage handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketUsageHandler).Queries("du", "")
+		case "GetBucketVersioning":
+			// Register GetBucketVersioning handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketVersioningHandler).Queries("versioning", "")
+		case "PutBucketVersioning":
+			// Register PutBucketVersioning handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketVersioningHandler).Queries("versioning", "")
+		case "ListObjectVersions":
+			// Register ListObjectVersions handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectVersionsHandler).Queries("versions", "")
+		case "GetBucketLifecycle":
+			// Register GetBucketLifecycle handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
+		case "PutBucketLifecycle":
+			// Register PutBucketLifecycle handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+		case "DeleteBucketLifecycle":
+			// Register DeleteBucketLifecycle handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+		case "ListObjectsV1":
+			// Register ListObjectsV1 handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
+		case "DeleteBucket":
+			// Register DeleteBucket handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
 		case "DeleteMultipleObjects":
 			// Register DeleteMultipleObjects handler.
 			bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler).Queries("delete", "")
@@ -0,0 +1,47 @@
+// +build go1.8
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+// Tests ChaCha20-Poly1305 cipher suites are parsed but not allowed in
+// FIPS mode.
+func TestParseTLSCipherSuitesChaCha(t *testing.T) {
+	defer func(fips bool, minVersion uint16, suites []uint16) {
+		globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites = fips, minVersion, suites
+	}(globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites)
+
+	suites, err := parseTLSCipherSuites("tls_ecdhe_rsa_with_chacha20_poly1305,TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305}
+	if !reflect.DeepEqual(suites, expected) {
+		t.Fatalf("Expected %v, got %v", expected, suites)
+	}
+
+	globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites = true, tls.VersionTLS12, suites
+	if err = checkTLSSettings(); err == nil {
+		t.Fatal("Expected ChaCha20-Poly1305 to be rejected in FIPS mode")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// TLS modes reported by ServerInfo API.
+const (
+	tlsModeOff     = "off"
+	tlsModeDefault = "default"
+	tlsModeFIPS    = "fips"
+)
+
+// Cipher suites used by the server by default.
+var defaultTLSCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+
+	// Best disabled, as they don't provide Forward Secrecy,
+	// but might be necessary for some clients
+	// tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	// tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+}
+
+// Only use curves which have assembly implementations.
+var defaultTLSCurves = []tls.CurveID{
+	tls.CurveP256,
+}
+
+// FIPS 140-2 approved cipher suites, AES-GCM with ECDHE key exchange.
+var fipsTLSCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+}
+
+// FIPS 140-2 approved NIST curves, X25519 is not approved.
+var fipsTLSCurves = []tls.CurveID{
+	tls.CurveP256,
+	tls.CurveP384,
+}
+
+// Supported values of MINIO_TLS_MIN_VERSION.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+}
+
+// Supported values of MINIO_TLS_CIPHERS, named as in the TLS registry.
+var tlsCipherSuites = map[string]uint16{
+	"TLS_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256": tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":   tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	// ChaCha20-Poly1305 suites are added in tls-config-chacha.go when
+	// built with go1.8 or later.
+}
+
+// parseTLSVersion - parses a TLS version such as "1.2".
+func parseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[strings.TrimSpace(version)]
+	if !ok {
+		return 0, fmt.Errorf("Unsupported TLS version %s", version)
+	}
+	return v, nil
+}
+
+// parseTLSCipherSuites - parses a comma separated list of cipher suite names.
+func parseTLSCipherSuites(ciphers string) ([]uint16, error) {
+	var suites []uint16
+	for _, name := range strings.Split(ciphers, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		suite, ok := tlsCipherSuites[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("Unsupported TLS cipher suite %s", name)
+		}
+		suites = append(suites, suite)
+	}
+	if len(suites) == 0 {
+		return nil, errInvalidArgument
+	}
+	return suites, nil
+}
+
+// checkTLSSettings - validates user provided TLS settings against
+// the FIPS mode, which does not allow weakening TLS.
+func checkTLSSettings() error {
+	if !globalTLSFIPS {
+		return nil
+	}
+	if globalTLSMinVersion < tls.VersionTLS12 {
+		return fmt.Errorf("TLS versions below 1.2 are not allowed in FIPS mode")
+	}
+	for _, suite := range globalTLSCipherSuites {
+		approved := false
+		for _, fipsSuite := range fipsTLSCipherSuites {
+			if suite == fipsSuite {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			return fmt.Errorf("TLS cipher suite 0x%04x is not allowed in FIPS mode", suite)
+		}
+	}
+	return nil
+}
+
+// getTLSMode - returns the TLS mode the server is running with.
+func getTLSMode() string {
+	if !globalIsSSL {
+		return tlsModeOff
+	}
+	if globalTLSFIPS {
+		return tlsModeFIPS
+	}
+	return tlsModeDefault
+}
+
+// newServerTLSConfig - returns the TLS configuration for the public
+// listener, restricted to FIPS approved algorithms in FIPS mode and
+// honoring the user provided minimum version and cipher suites.
+func newServerTLSConfig() *tls.Config {
+	config := &tls.Config{
+		// Causes servers to use Go's default ciphersuite preferences,
+		// which are tuned to avoid attacks. Does nothing on clients.
+		PreferServerCipherSuites: true,
+		// Defaults to TLS 1.2, see MINIO_TLS_MIN_VERSION.
+		MinVersion:       globalTLSMinVersion,
+		CurvePreferences: defaultTLSCurves,
+		CipherSuites:     defaultTLSCipherSuites,
+	}
+	if globalTLSFIPS {
+		config.CurvePreferences = fipsTLSCurves
+		config.CipherSuites = fipsTLSCipherSuites
+	}
+	// User provided cipher suites, already validated against FIPS mode.
+	if len(globalTLSCipherSuites) > 0 {
+		config.CipherSuites = globalTLSCipherSuites
+	}
+	return config
+}
+
+// newRPCClientTLSConfig - returns the TLS configuration used to dial
+// peers, in FIPS mode it is restricted the same way as the listener.
+func newRPCClientTLSConfig(serverName string) *tls.Config {
+	// ServerName in tls.Config needs to be specified to support SNI certificates.
+	config := &tls.Config{ServerName: serverName, RootCAs: globalRootCAs}
+	if globalTLSFIPS {
+		config.MinVersion = tls.VersionTLS12
+		config.CurvePreferences = fipsTLSCurves
+		config.CipherSuites = fipsTLSCipherSuites
+	}
+	return config
+}
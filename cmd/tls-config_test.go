@@ -0,0 +1,170 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+// Tests getTLSMode for all TLS settings.
+func TestGetTLSMode(t *testing.T) {
+	defer func(isSSL, fips bool) {
+		globalIsSSL, globalTLSFIPS = isSSL, fips
+	}(globalIsSSL, globalTLSFIPS)
+
+	testCases := []struct {
+		isSSL    bool
+		fips     bool
+		expected string
+	}{
+		{false, false, tlsModeOff},
+		{false, true, tlsModeOff},
+		{true, false, tlsModeDefault},
+		{true, true, tlsModeFIPS},
+	}
+	for i, testCase := range testCases {
+		globalIsSSL, globalTLSFIPS = testCase.isSSL, testCase.fips
+		if mode := getTLSMode(); mode != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, mode)
+		}
+	}
+}
+
+// Tests TLS configs are restricted in FIPS mode.
+func TestTLSConfigFIPS(t *testing.T) {
+	defer func(fips bool) { globalTLSFIPS = fips }(globalTLSFIPS)
+
+	globalTLSFIPS = false
+	if config := newRPCClientTLSConfig("localhost"); config.CipherSuites != nil {
+		t.Errorf("Expected default client cipher suites, got %v", config.CipherSuites)
+	}
+
+	globalTLSFIPS = true
+	serverConfig := newServerTLSConfig()
+	clientConfig := newRPCClientTLSConfig("localhost")
+	for i, config := range []*tls.Config{serverConfig, clientConfig} {
+		if !reflect.DeepEqual(config.CipherSuites, fipsTLSCipherSuites) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, fipsTLSCipherSuites, config.CipherSuites)
+		}
+		if !reflect.DeepEqual(config.CurvePreferences, fipsTLSCurves) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, fipsTLSCurves, config.CurvePreferences)
+		}
+		if config.MinVersion != tls.VersionTLS12 {
+			t.Errorf("Test %d: Expected TLS 1.2 minimum version, got %x", i+1, config.MinVersion)
+		}
+	}
+	if clientConfig.ServerName != "localhost" {
+		t.Errorf("Expected server name localhost, got %s", clientConfig.ServerName)
+	}
+}
+
+// Tests parsing of TLS versions.
+func TestParseTLSVersion(t *testing.T) {
+	testCases := []struct {
+		version    string
+		expected   uint16
+		shouldPass bool
+	}{
+		{"1.0", tls.VersionTLS10, true},
+		{"1.1", tls.VersionTLS11, true},
+		{" 1.2 ", tls.VersionTLS12, true},
+		{"1.3", 0, false},
+		{"ssl3", 0, false},
+	}
+	for i, testCase := range testCases {
+		version, err := parseTLSVersion(testCase.version)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if version != testCase.expected {
+			t.Errorf("Test %d: Expected %x, got %x", i+1, testCase.expected, version)
+		}
+	}
+}
+
+// Tests parsing of TLS cipher suite lists.
+func TestParseTLSCipherSuites(t *testing.T) {
+	testCases := []struct {
+		ciphers    string
+		expected   []uint16
+		shouldPass bool
+	}{
+		{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, true},
+		{"tls_rsa_with_aes_128_gcm_sha256, TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,", []uint16{
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		}, true},
+		{"TLS_RSA_WITH_RC4_128_SHA", nil, false},
+		{",", nil, false},
+	}
+	for i, testCase := range testCases {
+		suites, err := parseTLSCipherSuites(testCase.ciphers)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if !reflect.DeepEqual(suites, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, suites)
+		}
+	}
+}
+
+// Tests user provided TLS settings are validated against FIPS mode.
+func TestCheckTLSSettings(t *testing.T) {
+	defer func(fips bool, minVersion uint16, suites []uint16) {
+		globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites = fips, minVersion, suites
+	}(globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites)
+
+	testCases := []struct {
+		fips       bool
+		minVersion uint16
+		suites     []uint16
+		shouldPass bool
+	}{
+		{false, tls.VersionTLS10, []uint16{tls.TLS_RSA_WITH_AES_128_CBC_SHA}, true},
+		{true, tls.VersionTLS12, nil, true},
+		{true, tls.VersionTLS12, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, true},
+		{true, tls.VersionTLS11, nil, false},
+		{true, tls.VersionTLS12, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA}, false},
+	}
+	for i, testCase := range testCases {
+		globalTLSFIPS, globalTLSMinVersion, globalTLSCipherSuites = testCase.fips, testCase.minVersion, testCase.suites
+		err := checkTLSSettings()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if testCase.shouldPass {
+			config := newServerTLSConfig()
+			if config.MinVersion != testCase.minVersion {
+				t.Errorf("Test %d: Expected min version %x, got %x", i+1, testCase.minVersion, config.MinVersion)
+			}
+			if testCase.suites != nil && !reflect.DeepEqual(config.CipherSuites, testCase.suites) {
+				t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.suites, config.CipherSuites)
+			}
+		}
+	}
+}
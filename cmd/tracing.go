@@ -0,0 +1,375 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of spans, as defined by the Zipkin v2 API.
+const (
+	traceKindServer = "SERVER"
+	traceKindClient = "CLIENT"
+)
+
+// B3 headers propagating the trace of incoming requests, see
+// https://github.com/openzipkin/b3-propagation
+const (
+	b3TraceIDHeader = "X-B3-TraceId"
+	b3SpanIDHeader  = "X-B3-SpanId"
+	b3SampledHeader = "X-B3-Sampled"
+	b3FlagsHeader   = "X-B3-Flags"
+)
+
+const (
+	// Spans are exported in batches of at most traceBatchSize spans,
+	// at least every traceExportInterval.
+	traceBatchSize      = 100
+	traceExportInterval = time.Second
+
+	// Spans finished while this many are waiting to be exported are
+	// dropped, so that a slow collector never slows down requests.
+	traceQueueSize = 10000
+)
+
+// tracingConfig - configures export of trace spans to a Zipkin or
+// Jaeger collector.
+type tracingConfig struct {
+	// Zipkin v2 API spans endpoint, e.g.
+	// http://zipkin:9411/api/v2/spans, which Jaeger collectors
+	// serve as well. Tracing is disabled if empty.
+	Endpoint string `json:"endpoint"`
+
+	// Fraction of API calls and internal RPCs traced, between 0 and
+	// 1, defaults to 1 if 0.
+	SampleRate float64 `json:"sampleRate"`
+}
+
+// traceContext - identifies the span a traced RPC call belongs to.
+// Exported fields, sent along with RPC arguments.
+type traceContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// traceEndpoint - Zipkin v2 endpoint of a span.
+type traceEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// traceSpan - a timed operation in Zipkin v2 JSON form. A nil span is
+// not traced, all its methods are no-ops.
+type traceSpan struct {
+	TraceID       string            `json:"traceId"`
+	ParentID      string            `json:"parentId,omitempty"`
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	Timestamp     int64             `json:"timestamp"` // Microseconds since epoch.
+	Duration      int64             `json:"duration"`  // Microseconds.
+	LocalEndpoint traceEndpoint     `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+
+	start  time.Time
+	tracer *tracer
+}
+
+// tracer - records sampled spans and exports them to a collector. A
+// nil tracer records nothing.
+type tracer struct {
+	endpoint   string
+	sampleRate float64
+	node       string
+	client     *http.Client
+	queue      chan *traceSpan
+}
+
+// newTracer - validates config, returns nil if tracing is disabled.
+func newTracer(config tracingConfig) (*tracer, error) {
+	if config.Endpoint == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != httpScheme && u.Scheme != httpsScheme {
+		return nil, fmt.Errorf("Unsupported tracing endpoint %s", config.Endpoint)
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("Sample rate %v is not between 0 and 1", config.SampleRate)
+	}
+	sampleRate := config.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+
+	// Spans of all nodes are reported under the same service, tagged
+	// with the node which recorded them.
+	node, err := os.Hostname()
+	if err != nil {
+		node = "localhost"
+	}
+	node = net.JoinHostPort(node, globalMinioPort)
+
+	return &tracer{
+		endpoint:   config.Endpoint,
+		sampleRate: sampleRate,
+		node:       node,
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		queue: make(chan *traceSpan, traceQueueSize),
+	}, nil
+}
+
+// newTraceID - returns a random 64-bit span or trace ID in hex.
+func newTraceID() string {
+	var id [8]byte
+	// crypto/rand only fails if the OS has no entropy source.
+	crand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// sample - returns true if a new trace should be recorded.
+func (t *tracer) sample() bool {
+	return t.sampleRate >= 1 || rand.Float64() < t.sampleRate
+}
+
+// newSpan - starts a span of a new trace, or of the trace of parent
+// if its TraceID is set.
+func (t *tracer) newSpan(name, kind string, parent traceContext) *traceSpan {
+	span := &traceSpan{
+		TraceID:       parent.TraceID,
+		ParentID:      parent.SpanID,
+		ID:            newTraceID(),
+		Name:          name,
+		Kind:          kind,
+		LocalEndpoint: traceEndpoint{ServiceName: "minio"},
+		Tags:          map[string]string{"minio.node": t.node},
+		start:         time.Now().UTC(),
+		tracer:        t,
+	}
+	if span.TraceID == "" {
+		span.TraceID = span.ID
+	}
+	return span
+}
+
+// startSpan - starts the span of a new trace, returns nil unless the
+// trace is sampled.
+func (t *tracer) startSpan(name, kind string) *traceSpan {
+	if t == nil || !t.sample() {
+		return nil
+	}
+	return t.newSpan(name, kind, traceContext{})
+}
+
+// startChildSpan - starts a span of the trace of parent, returns nil
+// if parent is not traced.
+func (t *tracer) startChildSpan(name, kind string, parent traceContext) *traceSpan {
+	if t == nil || parent.TraceID == "" {
+		return nil
+	}
+	return t.newSpan(name, kind, parent)
+}
+
+// startRequestSpan - starts a server span of an HTTP request, joining
+// the trace of the B3 headers of r if any. Returns nil unless the
+// trace is sampled, by the client or else by the sample rate.
+func (t *tracer) startRequestSpan(r *http.Request) *traceSpan {
+	if t == nil {
+		return nil
+	}
+	parent := traceContext{
+		TraceID: r.Header.Get(b3TraceIDHeader),
+		SpanID:  r.Header.Get(b3SpanIDHeader),
+	}
+	if !isValidTraceID(parent.TraceID) || !isValidTraceID(parent.SpanID) {
+		parent = traceContext{}
+	}
+	switch strings.ToLower(r.Header.Get(b3SampledHeader)) {
+	case "0", "false":
+		return nil
+	case "1", "true":
+	default:
+		if r.Header.Get(b3FlagsHeader) != "1" && !t.sample() {
+			return nil
+		}
+	}
+	return t.newSpan(r.Method, traceKindServer, parent)
+}
+
+// isValidTraceID - returns true if id is a 64 or 128-bit hex ID.
+func isValidTraceID(id string) bool {
+	if len(id) != 16 && len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// context - returns the context passed to child spans.
+func (s *traceSpan) context() traceContext {
+	if s == nil {
+		return traceContext{}
+	}
+	return traceContext{TraceID: s.TraceID, SpanID: s.ID}
+}
+
+// setTag - tags the span with key and value, empty values are skipped.
+func (s *traceSpan) setTag(key, value string) {
+	if s == nil || value == "" {
+		return
+	}
+	s.Tags[key] = value
+}
+
+// finish - ends the span, tagging it with err if not nil, and queues
+// it for export.
+func (s *traceSpan) finish(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.Tags["error"] = errorCause(err).Error()
+	}
+	s.Timestamp = s.start.UnixNano() / int64(time.Microsecond)
+	s.Duration = int64(time.Since(s.start) / time.Microsecond)
+	// Collectors drop spans with a zero duration.
+	if s.Duration == 0 {
+		s.Duration = 1
+	}
+	select {
+	case s.tracer.queue <- s:
+	default:
+	}
+}
+
+// run - exports queued spans in batches, never returns.
+func (t *tracer) run() {
+	ticker := time.NewTicker(traceExportInterval)
+	defer ticker.Stop()
+
+	var batch []*traceSpan
+	failing := false
+	for {
+		select {
+		case span := <-t.queue:
+			batch = append(batch, span)
+			if len(batch) < traceBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		// Only the first of consecutive failures is logged, the
+		// collector may be down for a while.
+		err := t.export(batch)
+		if err != nil && !failing {
+			errorIf(err, "Unable to export trace spans to %s.", t.endpoint)
+		}
+		failing = err != nil
+		batch = nil
+	}
+}
+
+// export - posts spans to the collector.
+func (t *tracer) export(spans []*traceSpan) error {
+	data, err := json.Marshal(spans)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// tracingHandler records a server span per request when tracing is
+// enabled.
+type tracingHandler struct {
+	handler http.Handler
+}
+
+// setTracingHandler traces requests, except inter-node RPC
+// connections which are traced per call.
+func setTracingHandler(h http.Handler) http.Handler {
+	return tracingHandler{h}
+}
+
+func (h tracingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var span *traceSpan
+	if r.Method != http.MethodConnect {
+		span = globalTracer.startRequestSpan(r)
+	}
+	if span == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Wraps w to record http response information
+	ww := &httpResponseRecorder{ResponseWriter: w}
+	h.handler.ServeHTTP(ww, r)
+
+	status := ww.respStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	span.setTag("http.method", r.Method)
+	span.setTag("http.path", r.URL.Path)
+	span.setTag("http.status_code", strconv.Itoa(status))
+	span.setTag("s3.bucket", bucket)
+	span.setTag("s3.object", object)
+	if status >= http.StatusInternalServerError {
+		span.setTag("error", http.StatusText(status))
+	}
+	span.finish(nil)
+}
+
+// initTracing - initializes the global tracer from server config and
+// starts exporting spans, tracing stays disabled when no endpoint is
+// configured.
+func initTracing() error {
+	t, err := newTracer(serverConfig.GetTracing())
+	if err != nil || t == nil {
+		return err
+	}
+	go t.run()
+	globalTracer = t
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validating tracing configuration.
+func TestNewTracer(t *testing.T) {
+	testCases := []struct {
+		config    tracingConfig
+		isNil     bool
+		shouldErr bool
+	}{
+		// Tracing disabled.
+		{tracingConfig{}, true, false},
+		{tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans"}, false, false},
+		{tracingConfig{Endpoint: "https://jaeger:9411/api/v2/spans", SampleRate: 0.1}, false, false},
+		// Unsupported scheme.
+		{tracingConfig{Endpoint: "udp://jaeger:6831"}, false, true},
+		// Invalid sample rates.
+		{tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans", SampleRate: -0.1}, false, true},
+		{tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans", SampleRate: 1.5}, false, true},
+	}
+	for i, testCase := range testCases {
+		tracer, err := newTracer(testCase.config)
+		if testCase.shouldErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+		if err == nil && testCase.isNil != (tracer == nil) {
+			t.Fatalf("Test %d: Expected nil tracer %v, got %v", i+1, testCase.isNil, tracer)
+		}
+	}
+
+	// Sample rate defaults to 1.
+	tracer, err := newTracer(tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracer.sampleRate != 1 {
+		t.Fatalf("Expected sample rate 1, got %v", tracer.sampleRate)
+	}
+}
+
+// Tests sampling and B3 propagation of request spans.
+func TestStartRequestSpan(t *testing.T) {
+	tracer, err := newTracer(tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing is sampled by rate.
+	tracer.sampleRate = 0
+
+	traceID := "463ac35c9f6413ad48485a3953bb6124"
+	spanID := "a2fb4a1d1a96d312"
+	testCases := []struct {
+		header         map[string]string
+		traced         bool
+		expectedTrace  string
+		expectedParent string
+	}{
+		// Not sampled by rate.
+		{map[string]string{}, false, "", ""},
+		{map[string]string{b3TraceIDHeader: traceID, b3SpanIDHeader: spanID}, false, "", ""},
+		// Sampled by the client.
+		{map[string]string{b3TraceIDHeader: traceID, b3SpanIDHeader: spanID, b3SampledHeader: "1"}, true, traceID, spanID},
+		{map[string]string{b3TraceIDHeader: traceID, b3SpanIDHeader: spanID, b3FlagsHeader: "1"}, true, traceID, spanID},
+		// Sampled by the client, without a trace.
+		{map[string]string{b3SampledHeader: "true"}, true, "", ""},
+		// Invalid IDs start a new trace.
+		{map[string]string{b3TraceIDHeader: "xyz", b3SpanIDHeader: spanID, b3SampledHeader: "1"}, true, "", ""},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", "http://localhost:9000/bucket/object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key, value := range testCase.header {
+			req.Header.Set(key, value)
+		}
+		span := tracer.startRequestSpan(req)
+		if testCase.traced != (span != nil) {
+			t.Fatalf("Test %d: Expected traced %v, got %v", i+1, testCase.traced, span != nil)
+		}
+		if span == nil {
+			continue
+		}
+		if testCase.expectedTrace != "" && span.TraceID != testCase.expectedTrace {
+			t.Errorf("Test %d: Expected trace %s, got %s", i+1, testCase.expectedTrace, span.TraceID)
+		}
+		if testCase.expectedTrace == "" && span.TraceID != span.ID {
+			t.Errorf("Test %d: Expected a new trace, got %s", i+1, span.TraceID)
+		}
+		if span.ParentID != testCase.expectedParent {
+			t.Errorf("Test %d: Expected parent %q, got %q", i+1, testCase.expectedParent, span.ParentID)
+		}
+	}
+
+	// Clients can turn tracing off.
+	tracer.sampleRate = 1
+	req, err := http.NewRequest("GET", "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(b3SampledHeader, "0")
+	if span := tracer.startRequestSpan(req); span != nil {
+		t.Fatalf("Expected request not to be traced, got %v", span)
+	}
+}
+
+// Tests spans recorded for requests and storage RPC calls.
+func TestTracingSpans(t *testing.T) {
+	tracer, err := newTracer(tracingConfig{Endpoint: "http://zipkin:9411/api/v2/spans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalTracer = tracer
+	defer func() { globalTracer = nil }()
+
+	// Request spans.
+	handler := setTracingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "http://localhost:9000/bucket/dir/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.ServeHTTP(rec, req)
+	span := <-tracer.queue
+	if span.Name != "GET" || span.Kind != traceKindServer || span.Duration <= 0 {
+		t.Fatalf("Unexpected span %#v", span)
+	}
+	expectedTags := map[string]string{
+		"minio.node":       tracer.node,
+		"http.method":      "GET",
+		"http.path":        "/bucket/dir/object",
+		"http.status_code": "404",
+		"s3.bucket":        "bucket",
+		"s3.object":        "dir/object",
+	}
+	for key, value := range expectedTags {
+		if span.Tags[key] != value {
+			t.Errorf("Expected tag %s to be %q, got %q", key, value, span.Tags[key])
+		}
+	}
+
+	// Storage RPC spans are children of the span of the caller.
+	st := createTestStorageServer(t)
+	defer removeRoots(st.diskDirs)
+	defer removeAll(st.configDir)
+
+	parent := tracer.startSpan("Storage.MakeVolHandler", traceKindClient)
+	args := &GenericVolArgs{Vol: "myvol"}
+	args.SetAuthToken(st.token)
+	args.setTraceContext(parent.context())
+	if err = st.stServer.MakeVolHandler(args, &AuthRPCReply{}); err != nil {
+		t.Fatal(err)
+	}
+	span = <-tracer.queue
+	if span.TraceID != parent.TraceID || span.ParentID != parent.ID || span.Name != "Storage.MakeVol" {
+		t.Fatalf("Unexpected span %#v of parent %#v", span, parent)
+	}
+	if span.Tags["disk"] != "/disk1" || span.Tags["vol"] != "myvol" {
+		t.Errorf("Unexpected tags %v", span.Tags)
+	}
+
+	// Failed operations are tagged with the error.
+	if err = st.stServer.MakeVolHandler(args, &AuthRPCReply{}); err != errVolumeExists {
+		t.Fatalf("Expected %v, got %v", errVolumeExists, err)
+	}
+	if span = <-tracer.queue; span.Tags["error"] != errVolumeExists.Error() {
+		t.Errorf("Expected error tag %q, got %q", errVolumeExists.Error(), span.Tags["error"])
+	}
+
+	// Calls of untraced callers are not traced.
+	args = &GenericVolArgs{Vol: "othervol"}
+	args.SetAuthToken(st.token)
+	if err = st.stServer.MakeVolHandler(args, &AuthRPCReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.queue) != 0 {
+		t.Fatalf("Expected no span, got %d", len(tracer.queue))
+	}
+}
+
+// Tests exporting spans to a collector.
+func TestTracerExport(t *testing.T) {
+	var received []map[string]interface{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/spans" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	tracer, err := newTracer(tracingConfig{Endpoint: collector.URL + "/api/v2/spans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := tracer.startSpan("Storage.ReadAllHandler", traceKindClient)
+	child := tracer.startChildSpan("Storage.ReadAll", traceKindServer, parent.context())
+	child.finish(nil)
+	parent.finish(nil)
+	if err = tracer.export([]*traceSpan{<-tracer.queue, <-tracer.queue}); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(received))
+	}
+	for _, key := range []string{"traceId", "id", "name", "kind", "timestamp", "duration", "localEndpoint"} {
+		if _, ok := received[0][key]; !ok {
+			t.Errorf("Expected span field %s, got %v", key, received[0])
+		}
+	}
+	if received[0]["parentId"] != parent.ID {
+		t.Errorf("Expected parent %s, got %v", parent.ID, received[0]["parentId"])
+	}
+
+	// Collector errors are returned.
+	tracer.endpoint = collector.URL + "/unknown"
+	if err = tracer.export([]*traceSpan{parent}); err == nil {
+		t.Fatal("Expected export to fail")
+	}
+}
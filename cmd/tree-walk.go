@@ -26,6 +26,10 @@ type treeWalkResult struct {
 	entry string
 	err   error
 	end   bool
+
+	// Set if entry is an empty directory listed as a directory
+	// object, entry keeps its trailing "/".
+	dirObject bool
 }
 
 // posix.ListDir returns entries with trailing "/" for directories. At the object layer
@@ -95,6 +99,11 @@ type listDirFunc func(bucket, prefixDir, prefixEntry string) (entries []string,
 // 4. XL backend multipart listing - isLeaf is true if the entry is a directory and contains uploads.json
 type isLeafFunc func(string, string) bool
 
+// A function isLeafDir of type isLeafDirFunc is used to detect if a directory entry is an empty directory,
+// which is listed as a directory object named after it with a trailing "/" instead of a prefix. nil if the
+// listing has no directory objects.
+type isLeafDirFunc func(string, string) bool
+
 func filterListEntries(bucket, prefixDir string, entries []string, prefixEntry string, isLeaf isLeafFunc) ([]string, bool) {
 	// Listing needs to be sorted.
 	sort.Strings(entries)
@@ -121,8 +130,66 @@ func filterListEntries(bucket, prefixDir string, entries []string, prefixEntry s
 	return entries, false
 }
 
+// dirEntryStream - sorted entries of a directory, read one at a time.
+type dirEntryStream interface {
+	// Next returns the next entry, false once all entries are read.
+	Next() (entry string, ok bool, err error)
+	// Close releases the resources held by the stream.
+	Close()
+}
+
+// "listDir" function of type listDirStreamFunc is the streaming form of listDirFunc, entries
+// before markerDir are skipped. Used where directories may be too large to be held in memory.
+type listDirStreamFunc func(bucket, prefixDir, prefixEntry, markerDir string) (dirEntryStream, error)
+
+// sliceEntryStream - dirEntryStream over the entries returned by a listDirFunc.
+type sliceEntryStream struct {
+	bucket, prefixDir string
+	entries           []string
+	delayIsLeaf       bool
+	isLeaf            isLeafFunc
+}
+
+func (s *sliceEntryStream) Next() (string, bool, error) {
+	if len(s.entries) == 0 {
+		return "", false, nil
+	}
+	entry := s.entries[0]
+	s.entries = s.entries[1:]
+	// Decision to do isLeaf check was pushed from listDir() to here.
+	if s.delayIsLeaf && s.isLeaf(s.bucket, pathJoin(s.prefixDir, entry)) {
+		entry = strings.TrimSuffix(entry, slashSeparator)
+	}
+	return entry, true, nil
+}
+
+func (s *sliceEntryStream) Close() {}
+
+// listDirStream - returns listDir as a listDirStreamFunc.
+func listDirStream(listDir listDirFunc, isLeaf isLeafFunc) listDirStreamFunc {
+	return func(bucket, prefixDir, prefixEntry, markerDir string) (dirEntryStream, error) {
+		entries, delayIsLeaf, err := listDir(bucket, prefixDir, prefixEntry)
+		if err != nil {
+			return nil, err
+		}
+		// example:
+		// If markerDir="four/" Search() returns the index of "four/" in the sorted
+		// entries list so we skip all the entries till "four/"
+		idx := sort.Search(len(entries), func(i int) bool {
+			return entries[i] >= markerDir
+		})
+		return &sliceEntryStream{
+			bucket:      bucket,
+			prefixDir:   prefixDir,
+			entries:     entries[idx:],
+			delayIsLeaf: delayIsLeaf,
+			isLeaf:      isLeaf,
+		}, nil
+	}
+}
+
 // treeWalk walks directory tree recursively pushing treeWalkResult into the channel as and when it encounters files.
-func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, resultCh chan treeWalkResult, endWalkCh chan struct{}, isEnd bool) error {
+func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bool, listDir listDirStreamFunc, isLeafDir isLeafDirFunc, resultCh chan treeWalkResult, endWalkCh chan struct{}, isEnd bool) error {
 	// Example:
 	// if prefixDir="one/two/three/" and marker="four/five.txt" treeWalk is recursively
 	// called with prefixDir="one/two/three/four/" and marker="five.txt"
@@ -137,8 +204,7 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 			markerBase = markerSplit[1]
 		}
 	}
-	entries, delayIsLeaf, err := listDir(bucket, prefixDir, entryPrefixMatch)
-	if err != nil {
+	sendErr := func(err error) error {
 		select {
 		case <-endWalkCh:
 			return traceError(errWalkAbort)
@@ -146,43 +212,45 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 			return err
 		}
 	}
-	// For an empty list return right here.
-	if len(entries) == 0 {
-		return nil
+	entries, err := listDir(bucket, prefixDir, entryPrefixMatch, markerDir)
+	if err != nil {
+		return sendErr(err)
 	}
+	defer entries.Close()
 
-	// example:
-	// If markerDir="four/" Search() returns the index of "four/" in the sorted
-	// entries list so we skip all the entries till "four/"
-	idx := sort.Search(len(entries), func(i int) bool {
-		return entries[i] >= markerDir
-	})
-	entries = entries[idx:]
-	// For an empty list after search through the entries, return right here.
-	if len(entries) == 0 {
-		return nil
+	// The entry after the current one is read ahead to know if the
+	// current entry is the last one.
+	nextEntry, ok, err := entries.Next()
+	if err != nil {
+		return sendErr(err)
 	}
-	for i, entry := range entries {
-		// Decision to do isLeaf check was pushed from listDir() to here.
-		if delayIsLeaf && isLeaf(bucket, pathJoin(prefixDir, entry)) {
-			entry = strings.TrimSuffix(entry, slashSeparator)
+	for i := 0; ok; i++ {
+		entry := nextEntry
+		if nextEntry, ok, err = entries.Next(); err != nil {
+			return sendErr(err)
 		}
+		isLast := !ok
+
+		// Recursive listings list empty directories as directory
+		// objects instead of walking into them.
+		dirObject := recursive && hasSuffix(entry, slashSeparator) && isLeafDir != nil && isLeafDir(bucket, pathJoin(prefixDir, entry))
 
 		if i == 0 && markerDir == entry {
 			if !recursive {
 				// Skip as the marker would already be listed in the previous listing.
 				continue
 			}
-			if recursive && !hasSuffix(entry, slashSeparator) {
+			if recursive && (!hasSuffix(entry, slashSeparator) || dirObject) {
 				// We should not skip for recursive listing and if markerDir is a directory
 				// for ex. if marker is "four/five.txt" markerDir will be "four/" which
 				// should not be skipped, instead it will need to be treeWalk()'ed into.
 
-				// Skip if it is a file though as it would be listed in previous listing.
+				// Skip if it is a file or a directory object though as it would be listed
+				// in previous listing.
 				continue
 			}
 		}
-		if recursive && hasSuffix(entry, slashSeparator) {
+		if recursive && hasSuffix(entry, slashSeparator) && !dirObject {
 			// If the entry is a directory, we will need recurse into it.
 			markerArg := ""
 			if entry == markerDir {
@@ -193,18 +261,18 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 			prefixMatch := "" // Valid only for first level treeWalk and empty for subdirectories.
 			// markIsEnd is passed to this entry's treeWalk() so that treeWalker.end can be marked
 			// true at the end of the treeWalk stream.
-			markIsEnd := i == len(entries)-1 && isEnd
-			if tErr := doTreeWalk(bucket, pathJoin(prefixDir, entry), prefixMatch, markerArg, recursive, listDir, isLeaf, resultCh, endWalkCh, markIsEnd); tErr != nil {
+			markIsEnd := isLast && isEnd
+			if tErr := doTreeWalk(bucket, pathJoin(prefixDir, entry), prefixMatch, markerArg, recursive, listDir, isLeafDir, resultCh, endWalkCh, markIsEnd); tErr != nil {
 				return tErr
 			}
 			continue
 		}
 		// EOF is set if we are at last entry and the caller indicated we at the end.
-		isEOF := ((i == len(entries)-1) && isEnd)
+		isEOF := isLast && isEnd
 		select {
 		case <-endWalkCh:
 			return traceError(errWalkAbort)
-		case resultCh <- treeWalkResult{entry: pathJoin(prefixDir, entry), end: isEOF}:
+		case resultCh <- treeWalkResult{entry: pathJoin(prefixDir, entry), end: isEOF, dirObject: dirObject}:
 		}
 	}
 
@@ -213,7 +281,12 @@ func doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker string, recursive bo
 }
 
 // Initiate a new treeWalk in a goroutine.
-func startTreeWalk(bucket, prefix, marker string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, endWalkCh chan struct{}) chan treeWalkResult {
+func startTreeWalk(bucket, prefix, marker string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, isLeafDir isLeafDirFunc, endWalkCh chan struct{}) chan treeWalkResult {
+	return startTreeWalkStream(bucket, prefix, marker, recursive, listDirStream(listDir, isLeaf), isLeafDir, endWalkCh)
+}
+
+// Initiate a new treeWalk in a goroutine, listing directories as streams.
+func startTreeWalkStream(bucket, prefix, marker string, recursive bool, listDir listDirStreamFunc, isLeafDir isLeafDirFunc, endWalkCh chan struct{}) chan treeWalkResult {
 	// Example 1
 	// If prefix is "one/two/three/" and marker is "one/two/three/four/five.txt"
 	// treeWalk is called with prefixDir="one/two/three/" and marker="four/five.txt"
@@ -232,11 +305,23 @@ func startTreeWalk(bucket, prefix, marker string, recursive bool, listDir listDi
 		entryPrefixMatch = prefix[lastIndex+1:]
 		prefixDir = prefix[:lastIndex+1]
 	}
+	// Example 3
+	// if prefix is "one/two/" and "one/two/" is an empty directory, the
+	// directory object "one/two/" is the only entry listed, unless the
+	// marker is past it.
+	listPrefixDir := entryPrefixMatch == "" && prefixDir != "" && marker < prefixDir && isLeafDir != nil
 	marker = strings.TrimPrefix(marker, prefixDir)
 	go func() {
+		defer close(resultCh)
+		if listPrefixDir && isLeafDir(bucket, prefixDir) {
+			select {
+			case <-endWalkCh:
+			case resultCh <- treeWalkResult{entry: prefixDir, end: true, dirObject: true}:
+			}
+			return
+		}
 		isEnd := true // Indication to start walking the tree with end as true.
-		doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker, recursive, listDir, isLeaf, resultCh, endWalkCh, isEnd)
-		close(resultCh)
+		doTreeWalk(bucket, prefixDir, entryPrefixMatch, marker, recursive, listDir, isLeafDir, resultCh, endWalkCh, isEnd)
 	}()
 	return resultCh
 }
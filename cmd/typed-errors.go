@@ -30,6 +30,9 @@ var errInvalidToken = errors.New("Invalid token")
 // If x-amz-content-sha256 header value mismatches with what we calculate.
 var errContentSHA256Mismatch = errors.New("Content checksum SHA256 mismatch")
 
+// If x-amz-checksum-* header value mismatches with what we calculate.
+var errChecksumMismatch = errors.New("Content checksum mismatch")
+
 // used when we deal with data larger than expected
 var errSizeUnexpected = errors.New("Data size larger than expected")
 
@@ -39,6 +42,9 @@ var errSizeUnspecified = errors.New("Data size is unspecified")
 // When upload object size is greater than 5G in a single PUT/POST operation.
 var errDataTooLarge = errors.New("Object size larger than allowed limit")
 
+// When user metadata of an object is larger than allowed.
+var errMetadataTooLarge = errors.New("Your metadata headers exceed the maximum allowed metadata size")
+
 // When upload object size is less than what was expected.
 var errDataTooSmall = errors.New("Object size smaller than expected")
 
@@ -51,6 +57,15 @@ var errServerVersionMismatch = errors.New("Server versions do not match")
 // errServerTimeMismatch - server times are too far apart.
 var errServerTimeMismatch = errors.New("Server times are too far apart")
 
+// errTooManyBuckets - bucket creation policy limits the number of buckets.
+var errTooManyBuckets = errors.New("You have attempted to create more buckets than allowed")
+
+// errBucketNameNotAllowed - bucket creation policy does not allow the name.
+var errBucketNameNotAllowed = errors.New("Bucket name is not allowed by the bucket creation policy")
+
 // errReservedBucket - bucket name is reserved for Minio, usually
 // returned for 'minio', '.minio.sys'
 var errReservedBucket = errors.New("All access to this bucket is disabled")
+
+// errInvalidArchive - uploaded archive is malformed or of an unknown format.
+var errInvalidArchive = errors.New("Archive is malformed or not a tar, tar.gz or zip file")
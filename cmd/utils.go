@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
@@ -24,7 +25,10 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"encoding/json"
 
@@ -170,27 +174,35 @@ func checkValidMD5(md5 string) ([]byte, error) {
 
 /// http://docs.aws.amazon.com/AmazonS3/latest/dev/UploadingObjects.html
 const (
-	// maximum object size per PUT request is 5GiB
-	maxObjectSize = 5 * humanize.GiByte
-	// minimum Part size for multipart upload is 5MiB
-	minPartSize = 5 * humanize.MiByte
-	// maximum Part ID for multipart upload is 10000 (Acceptable values range from 1 to 10000 inclusive)
-	maxPartID = 10000
+	// default maximum object size per PUT request is 5GiB
+	defaultMaxObjectSize = 5 * humanize.GiByte
+	// default minimum Part size for multipart upload is 5MiB
+	defaultMinPartSize = 5 * humanize.MiByte
+	// default maximum Part size for multipart upload is 5GiB
+	defaultMaxPartSize = 5 * humanize.GiByte
+	// default maximum Part ID for multipart upload is 10000 (Acceptable values range from 1 to 10000 inclusive)
+	defaultMaxPartID = 10000
 )
 
 // isMaxObjectSize - verify if max object size
 func isMaxObjectSize(size int64) bool {
-	return size > maxObjectSize
+	return size > globalMaxObjectSize
 }
 
 // Check if part size is more than or equal to minimum allowed size.
 func isMinAllowedPartSize(size int64) bool {
-	return size >= minPartSize
+	return size >= globalMinPartSize
+}
+
+// isMaxPartSize - verify if part size is greater than the maximum
+// allowed part size.
+func isMaxPartSize(size int64) bool {
+	return size > globalMaxPartSize
 }
 
 // isMaxPartNumber - Check if part ID is greater than the maximum allowed ID.
 func isMaxPartID(partID int) bool {
-	return partID > maxPartID
+	return partID > globalMaxPartID
 }
 
 func contains(stringList []string, element string) bool {
@@ -263,6 +275,519 @@ func getBrowserFromEnv() (string, error) {
 	return strings.ToLower(b), nil
 }
 
+// Variant of getPolicyMaxStatementsFromEnv but upon error fails right here.
+func mustGetPolicyMaxStatementsFromEnv() int {
+	maxStatements, err := getPolicyMaxStatementsFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_POLICY_MAX_STATEMENTS value from environment. Err: %s.\n", err)
+	}
+	return maxStatements
+}
+
+// getPolicyMaxStatementsFromEnv - returns the maximum number of
+// statements allowed in a bucket policy, defaults to
+// defaultMaxPolicyStatements when the env is not set.
+func getPolicyMaxStatementsFromEnv() (int, error) {
+	v := os.Getenv("MINIO_POLICY_MAX_STATEMENTS")
+	if strings.TrimSpace(v) == "" {
+		return defaultMaxPolicyStatements, nil
+	}
+	maxStatements, err := strconv.Atoi(v)
+	if err != nil || maxStatements <= 0 {
+		return 0, errInvalidArgument
+	}
+	return maxStatements, nil
+}
+
+// Variant of getMetadataMaxSizeFromEnv but upon error fails right here.
+func mustGetMetadataMaxSizeFromEnv() int64 {
+	maxSize, err := getMetadataMaxSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_METADATA_MAX_SIZE value from environment. Err: %s.\n", err)
+	}
+	return maxSize
+}
+
+// getMetadataMaxSizeFromEnv - returns the maximum size of the user
+// metadata of an object, defaults to defaultMaxUserMetadataSize when
+// the env is not set.
+func getMetadataMaxSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_METADATA_MAX_SIZE"))
+	if v == "" {
+		return defaultMaxUserMetadataSize, nil
+	}
+	maxSize, err := humanize.ParseBytes(v)
+	if err != nil || maxSize == 0 {
+		return 0, errInvalidArgument
+	}
+	return int64(maxSize), nil
+}
+
+// Variant of getObjectMaxSizeFromEnv but upon error fails right here.
+func mustGetObjectMaxSizeFromEnv() int64 {
+	maxSize, err := getObjectMaxSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_OBJECT_MAX_SIZE value from environment. Err: %s.\n", err)
+	}
+	return maxSize
+}
+
+// getObjectMaxSizeFromEnv - returns the maximum size of objects
+// uploaded or copied in a single request, defaults to
+// defaultMaxObjectSize when the env is not set.
+func getObjectMaxSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_OBJECT_MAX_SIZE"))
+	if v == "" {
+		return defaultMaxObjectSize, nil
+	}
+	maxSize, err := humanize.ParseBytes(v)
+	if err != nil || maxSize == 0 {
+		return 0, errInvalidArgument
+	}
+	return int64(maxSize), nil
+}
+
+// Variant of getPartMinSizeFromEnv but upon error fails right here.
+func mustGetPartMinSizeFromEnv() int64 {
+	minSize, err := getPartMinSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_PART_MIN_SIZE value from environment. Err: %s.\n", err)
+	}
+	return minSize
+}
+
+// getPartMinSizeFromEnv - returns the minimum size of the parts of a
+// multipart upload, but the last one, defaults to defaultMinPartSize
+// when the env is not set.
+func getPartMinSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_PART_MIN_SIZE"))
+	if v == "" {
+		return defaultMinPartSize, nil
+	}
+	minSize, err := humanize.ParseBytes(v)
+	if err != nil || minSize == 0 {
+		return 0, errInvalidArgument
+	}
+	return int64(minSize), nil
+}
+
+// Variant of getPartMaxSizeFromEnv but upon error fails right here.
+func mustGetPartMaxSizeFromEnv() int64 {
+	maxSize, err := getPartMaxSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_PART_MAX_SIZE value from environment. Err: %s.\n", err)
+	}
+	return maxSize
+}
+
+// getPartMaxSizeFromEnv - returns the maximum size of the parts of a
+// multipart upload, at least the minimum part size, defaults to
+// defaultMaxPartSize when the env is not set.
+func getPartMaxSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_PART_MAX_SIZE"))
+	if v == "" {
+		if defaultMaxPartSize < globalMinPartSize {
+			return 0, errInvalidArgument
+		}
+		return defaultMaxPartSize, nil
+	}
+	maxSize, err := humanize.ParseBytes(v)
+	if err != nil || int64(maxSize) < globalMinPartSize {
+		return 0, errInvalidArgument
+	}
+	return int64(maxSize), nil
+}
+
+// Variant of getUploadMaxPartsFromEnv but upon error fails right here.
+func mustGetUploadMaxPartsFromEnv() int {
+	maxParts, err := getUploadMaxPartsFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_UPLOAD_MAX_PARTS value from environment. Err: %s.\n", err)
+	}
+	return maxParts
+}
+
+// getUploadMaxPartsFromEnv - returns the highest part number of a
+// multipart upload, defaults to defaultMaxPartID when the env is not
+// set.
+func getUploadMaxPartsFromEnv() (int, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_UPLOAD_MAX_PARTS"))
+	if v == "" {
+		return defaultMaxPartID, nil
+	}
+	maxParts, err := strconv.Atoi(v)
+	if err != nil || maxParts <= 0 {
+		return 0, errInvalidArgument
+	}
+	return maxParts, nil
+}
+
+// Variant of getChunkedUploadMaxSizeFromEnv but upon error fails right here.
+func mustGetChunkedUploadMaxSizeFromEnv() int64 {
+	maxSize, err := getChunkedUploadMaxSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_CHUNKED_UPLOAD_MAX_SIZE value from environment. Err: %s.\n", err)
+	}
+	return maxSize
+}
+
+// getChunkedUploadMaxSizeFromEnv - returns the maximum size of objects
+// uploaded without Content-Length, at most and by default the maximum
+// size of a single PUT.
+func getChunkedUploadMaxSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_CHUNKED_UPLOAD_MAX_SIZE"))
+	if v == "" {
+		return globalMaxObjectSize, nil
+	}
+	maxSize, err := humanize.ParseBytes(v)
+	if err != nil || maxSize == 0 || int64(maxSize) > globalMaxObjectSize {
+		return 0, errInvalidArgument
+	}
+	return int64(maxSize), nil
+}
+
+// getBoolFromEnv - returns true if the named env is "on", false if
+// it is "off" or not set.
+func getBoolFromEnv(name string) (bool, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" || strings.EqualFold(v, "off") {
+		return false, nil
+	}
+	if strings.EqualFold(v, "on") {
+		return true, nil
+	}
+	return false, errInvalidArgument
+}
+
+// Variant of getRPCClientAuthFromEnv but upon error fails right here.
+func mustGetRPCClientAuthFromEnv() bool {
+	clientAuth, err := getRPCClientAuthFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_RPC_CLIENT_AUTH value from environment. Err: %s.\n", err)
+	}
+	return clientAuth
+}
+
+// getRPCClientAuthFromEnv - returns true if inter-node RPC requests
+// must be authenticated with a verified TLS client certificate.
+func getRPCClientAuthFromEnv() (bool, error) {
+	return getBoolFromEnv("MINIO_RPC_CLIENT_AUTH")
+}
+
+// Variant of getTLSFIPSFromEnv but upon error fails right here.
+func mustGetTLSFIPSFromEnv() bool {
+	fips, err := getTLSFIPSFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_TLS_FIPS value from environment. Err: %s.\n", err)
+	}
+	return fips
+}
+
+// getTLSFIPSFromEnv - returns true if TLS must be restricted to
+// FIPS approved cipher suites and curves.
+func getTLSFIPSFromEnv() (bool, error) {
+	return getBoolFromEnv("MINIO_TLS_FIPS")
+}
+
+// Variant of getTLSMinVersionFromEnv but upon error fails right here.
+func mustGetTLSMinVersionFromEnv() uint16 {
+	version, err := getTLSMinVersionFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_TLS_MIN_VERSION value from environment. Err: %s.\n", err)
+	}
+	return version
+}
+
+// getTLSMinVersionFromEnv - returns the minimum TLS version accepted
+// by the server, defaults to TLS 1.2.
+func getTLSMinVersionFromEnv() (uint16, error) {
+	v := os.Getenv("MINIO_TLS_MIN_VERSION")
+	if strings.TrimSpace(v) == "" {
+		return tls.VersionTLS12, nil
+	}
+	return parseTLSVersion(v)
+}
+
+// Variant of getTLSCipherSuitesFromEnv but upon error fails right here.
+func mustGetTLSCipherSuitesFromEnv() []uint16 {
+	suites, err := getTLSCipherSuitesFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_TLS_CIPHERS value from environment. Err: %s.\n", err)
+	}
+	return suites
+}
+
+// getTLSCipherSuitesFromEnv - returns the cipher suites accepted by
+// the server, nil when the defaults should be used.
+func getTLSCipherSuitesFromEnv() ([]uint16, error) {
+	v := os.Getenv("MINIO_TLS_CIPHERS")
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	return parseTLSCipherSuites(v)
+}
+
+// Variant of getSourceMetadataFromEnv but upon error fails right here.
+func mustGetSourceMetadataFromEnv() bool {
+	expose, err := getSourceMetadataFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_SOURCE_METADATA value from environment. Err: %s.\n", err)
+	}
+	return expose
+}
+
+// getSourceMetadataFromEnv - returns true if preserved source ETag
+// and Last-Modified of copied objects are exposed in responses.
+func getSourceMetadataFromEnv() (bool, error) {
+	return getBoolFromEnv("MINIO_SOURCE_METADATA")
+}
+
+// Variant of getStrictConsistencyFromEnv but upon error fails right here.
+func mustGetStrictConsistencyFromEnv() bool {
+	strict, err := getStrictConsistencyFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_CONSISTENCY value from environment. Err: %s.\n", err)
+	}
+	return strict
+}
+
+// getStrictConsistencyFromEnv - returns true if MINIO_CONSISTENCY env
+// is "strict", defaults to false when the env is not set.
+func getStrictConsistencyFromEnv() (bool, error) {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("MINIO_CONSISTENCY")))
+	switch v {
+	case "", "default":
+		return false, nil
+	case "strict":
+		return true, nil
+	}
+	return false, errInvalidArgument
+}
+
+// Variant of getNormalizeObjectNamesFromEnv but upon error fails right here.
+func mustGetNormalizeObjectNamesFromEnv() bool {
+	normalize, err := getNormalizeObjectNamesFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_NORMALIZE_OBJECT_NAMES value from environment. Err: %s.\n", err)
+	}
+	return normalize
+}
+
+// getNormalizeObjectNamesFromEnv - returns true if object names are
+// to be normalized to Unicode NFC when written.
+func getNormalizeObjectNamesFromEnv() (bool, error) {
+	return getBoolFromEnv("MINIO_NORMALIZE_OBJECT_NAMES")
+}
+
+// Variant of getErasureWorkersFromEnv but upon error fails right here.
+func mustGetErasureWorkersFromEnv() int {
+	workers, err := getErasureWorkersFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_ERASURE_WORKERS value from environment. Err: %s.\n", err)
+	}
+	return workers
+}
+
+// getErasureWorkersFromEnv - returns the number of concurrent erasure
+// encode and decode operations, defaults to GOMAXPROCS when the env
+// is not set.
+func getErasureWorkersFromEnv() (int, error) {
+	v := os.Getenv("MINIO_ERASURE_WORKERS")
+	if strings.TrimSpace(v) == "" {
+		return runtime.GOMAXPROCS(0), nil
+	}
+	workers, err := strconv.Atoi(v)
+	if err != nil || workers <= 0 {
+		return 0, errInvalidArgument
+	}
+	return workers, nil
+}
+
+// Variant of getXLMetaFormatFromEnv but upon error fails right here.
+func mustGetXLMetaFormatFromEnv() string {
+	format, err := getXLMetaFormatFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_XL_META_FORMAT value from environment. Err: %s.\n", err)
+	}
+	return format
+}
+
+// getXLMetaFormatFromEnv - returns the format new `xl.json` are
+// written in, defaults to json when the env is not set.
+func getXLMetaFormatFromEnv() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("MINIO_XL_META_FORMAT")))
+	switch v {
+	case "":
+		return xlMetaFormatJSON, nil
+	case xlMetaFormatBinary, xlMetaFormatJSON:
+		return v, nil
+	}
+	return "", errInvalidArgument
+}
+
+// Variant of getLockPolicyFromEnv but upon error fails right here.
+func mustGetLockPolicyFromEnv() string {
+	policy, err := getLockPolicyFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_LOCK_POLICY value from environment. Err: %s.\n", err)
+	}
+	return policy
+}
+
+// getLockPolicyFromEnv - returns the grant policy of namespace locks,
+// defaults to fifo when the env is not set.
+func getLockPolicyFromEnv() (string, error) {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("MINIO_LOCK_POLICY")))
+	switch v {
+	case "":
+		return lockPolicyFIFO, nil
+	case lockPolicyFIFO, lockPolicyWriterPriority:
+		return v, nil
+	}
+	return "", errInvalidArgument
+}
+
+// Variant of getLocklessReadsFromEnv but upon error fails right here.
+func mustGetLocklessReadsFromEnv() bool {
+	lockless, err := getLocklessReadsFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_LOCKLESS_READS value from environment. Err: %s.\n", err)
+	}
+	return lockless
+}
+
+// getLocklessReadsFromEnv - returns true if GET and HEAD object
+// requests are not to take namespace read locks.
+func getLocklessReadsFromEnv() (bool, error) {
+	return getBoolFromEnv("MINIO_LOCKLESS_READS")
+}
+
+// Variant of getDeepScrubRateFromEnv but upon error fails right here.
+func mustGetDeepScrubRateFromEnv() int64 {
+	rate, err := getDeepScrubRateFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_DEEP_SCRUB value from environment. Err: %s.\n", err)
+	}
+	return rate
+}
+
+// getDeepScrubRateFromEnv - returns the rate in bytes per second at
+// which deep scrubbing reads data, "on" selects the default rate.
+// Returns 0 if deep scrubbing is disabled, the default.
+func getDeepScrubRateFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_DEEP_SCRUB"))
+	if v == "" || strings.EqualFold(v, "off") {
+		return 0, nil
+	}
+	if strings.EqualFold(v, "on") {
+		return defaultDeepScrubRate, nil
+	}
+	rate, err := humanize.ParseBytes(v)
+	if err != nil || rate == 0 {
+		return 0, errInvalidArgument
+	}
+	return int64(rate), nil
+}
+
+// Variant of getJanitorIntervalFromEnv but upon error fails right here.
+func mustGetJanitorIntervalFromEnv() time.Duration {
+	interval, err := getJanitorIntervalFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_JANITOR_INTERVAL value from environment. Err: %s.\n", err)
+	}
+	return interval
+}
+
+// getJanitorIntervalFromEnv - returns the interval between janitor
+// passes, defaults to an hour. Returns 0 if the janitor is disabled.
+func getJanitorIntervalFromEnv() (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_JANITOR_INTERVAL"))
+	if v == "" {
+		return defaultJanitorInterval, nil
+	}
+	if strings.EqualFold(v, "off") {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil || interval <= 0 {
+		return 0, errInvalidArgument
+	}
+	return interval, nil
+}
+
+// Variant of getJanitorAgeFromEnv but upon error fails right here.
+func mustGetJanitorAgeFromEnv() time.Duration {
+	age, err := getJanitorAgeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_JANITOR_AGE value from environment. Err: %s.\n", err)
+	}
+	return age
+}
+
+// getJanitorAgeFromEnv - returns the time since the last write after
+// which the janitor removes stale data, defaults to a day.
+func getJanitorAgeFromEnv() (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_JANITOR_AGE"))
+	if v == "" {
+		return defaultJanitorAge, nil
+	}
+	age, err := time.ParseDuration(v)
+	if err != nil || age < minJanitorAge {
+		return 0, errInvalidArgument
+	}
+	return age, nil
+}
+
+// Variant of getMemoryLimitFromEnv but upon error fails right here.
+func mustGetMemoryLimitFromEnv() int64 {
+	limit, err := getMemoryLimitFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_MEMORY_LIMIT value from environment. Err: %s.\n", err)
+	}
+	return limit
+}
+
+// getMemoryLimitFromEnv - returns the memory cap of the buffer pool,
+// "off" disables the cap, defaults to a quarter of the available
+// memory when the env is not set.
+func getMemoryLimitFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_MEMORY_LIMIT"))
+	if v == "" {
+		return int64(getDefaultMemoryLimit()), nil
+	}
+	if strings.EqualFold(v, "off") {
+		return 0, nil
+	}
+	limit, err := humanize.ParseBytes(v)
+	if err != nil || limit < minMemoryLimit {
+		return 0, errInvalidArgument
+	}
+	return int64(limit), nil
+}
+
+// Variant of getReadAheadCacheSizeFromEnv but upon error fails right here.
+func mustGetReadAheadCacheSizeFromEnv() int64 {
+	size, err := getReadAheadCacheSizeFromEnv()
+	if err != nil {
+		console.Fatalf("Unable to load MINIO_READAHEAD_CACHE value from environment. Err: %s.\n", err)
+	}
+	return size
+}
+
+// getReadAheadCacheSizeFromEnv - returns the memory used for prefetched
+// ranges, read ahead is disabled when the env is not set or "off".
+func getReadAheadCacheSizeFromEnv() (int64, error) {
+	v := strings.TrimSpace(os.Getenv("MINIO_READAHEAD_CACHE"))
+	if v == "" || strings.EqualFold(v, "off") {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(v)
+	if err != nil || size == 0 {
+		return 0, errInvalidArgument
+	}
+	return int64(size), nil
+}
+
 // isFile - returns whether given path is a file or not.
 func isFile(path string) bool {
 	if fi, err := os.Stat(path); err == nil {
@@ -21,9 +21,13 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 // Tests http.Header clone.
@@ -110,12 +114,12 @@ func TestMaxObjectSize(t *testing.T) {
 		// Test - 1 - maximum object size.
 		{
 			true,
-			maxObjectSize + 1,
+			defaultMaxObjectSize + 1,
 		},
 		// Test - 2 - not maximum object size.
 		{
 			false,
-			maxObjectSize - 1,
+			defaultMaxObjectSize - 1,
 		},
 	}
 	for i, s := range sizes {
@@ -135,12 +139,12 @@ func TestMinAllowedPartSize(t *testing.T) {
 		// Test - 1 - within minimum part size.
 		{
 			true,
-			minPartSize + 1,
+			defaultMinPartSize + 1,
 		},
 		// Test - 2 - smaller than minimum part size.
 		{
 			false,
-			minPartSize - 1,
+			defaultMinPartSize - 1,
 		},
 	}
 
@@ -152,6 +156,22 @@ func TestMinAllowedPartSize(t *testing.T) {
 	}
 }
 
+// Tests maximum allowed part size.
+func TestMaxPartSize(t *testing.T) {
+	sizes := []struct {
+		isMax bool
+		size  int64
+	}{
+		{true, defaultMaxPartSize + 1},
+		{false, defaultMaxPartSize},
+	}
+	for i, s := range sizes {
+		if isMax := isMaxPartSize(s.size); isMax != s.isMax {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, s.isMax, isMax)
+		}
+	}
+}
+
 // Tests maximum allowed part number.
 func TestMaxPartID(t *testing.T) {
 	sizes := []struct {
@@ -161,12 +181,12 @@ func TestMaxPartID(t *testing.T) {
 		// Test - 1 part number within max part number.
 		{
 			false,
-			maxPartID - 1,
+			defaultMaxPartID - 1,
 		},
 		// Test - 2 part number bigger than max part number.
 		{
 			true,
-			maxPartID + 1,
+			defaultMaxPartID + 1,
 		},
 	}
 
@@ -388,3 +408,547 @@ func TestLocalAddress(t *testing.T) {
 	}
 
 }
+
+// Test reading bucket policy statement limit from env.
+func TestGetPolicyMaxStatementsFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_POLICY_MAX_STATEMENTS")
+
+	testCases := []struct {
+		env           string
+		maxStatements int
+		expectedErr   error
+	}{
+		{"", defaultMaxPolicyStatements, nil},
+		{"10", 10, nil},
+		{"0", 0, errInvalidArgument},
+		{"-1", 0, errInvalidArgument},
+		{"ten", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_POLICY_MAX_STATEMENTS", testCase.env)
+		maxStatements, err := getPolicyMaxStatementsFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxStatements != testCase.maxStatements {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.maxStatements, maxStatements)
+		}
+	}
+}
+
+// Tests parsing of MINIO_METADATA_MAX_SIZE env.
+func TestGetMetadataMaxSizeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_METADATA_MAX_SIZE")
+
+	testCases := []struct {
+		env         string
+		maxSize     int64
+		expectedErr error
+	}{
+		{"", defaultMaxUserMetadataSize, nil},
+		{"16KiB", 16 * humanize.KiByte, nil},
+		{"2048", 2048, nil},
+		{"0", 0, errInvalidArgument},
+		{"large", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_METADATA_MAX_SIZE", testCase.env)
+		maxSize, err := getMetadataMaxSizeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxSize != testCase.maxSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.maxSize, maxSize)
+		}
+	}
+}
+
+// Tests parsing of MINIO_OBJECT_MAX_SIZE env.
+func TestGetObjectMaxSizeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_OBJECT_MAX_SIZE")
+
+	testCases := []struct {
+		env         string
+		maxSize     int64
+		expectedErr error
+	}{
+		{"", defaultMaxObjectSize, nil},
+		{"1GiB", humanize.GiByte, nil},
+		{"50GiB", 50 * humanize.GiByte, nil},
+		{"0", 0, errInvalidArgument},
+		{"large", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_OBJECT_MAX_SIZE", testCase.env)
+		maxSize, err := getObjectMaxSizeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxSize != testCase.maxSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.maxSize, maxSize)
+		}
+	}
+}
+
+// Tests parsing of MINIO_PART_MIN_SIZE and MINIO_PART_MAX_SIZE env.
+func TestGetPartSizeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_PART_MIN_SIZE")
+	defer os.Unsetenv("MINIO_PART_MAX_SIZE")
+	defer func(minSize int64) { globalMinPartSize = minSize }(globalMinPartSize)
+
+	testCases := []struct {
+		minEnv      string
+		maxEnv      string
+		minSize     int64
+		maxSize     int64
+		expectedErr error
+	}{
+		{"", "", defaultMinPartSize, defaultMaxPartSize, nil},
+		{"1MiB", "16GiB", humanize.MiByte, 16 * humanize.GiByte, nil},
+		{"1GiB", "1GiB", humanize.GiByte, humanize.GiByte, nil},
+		// Maximum smaller than the minimum.
+		{"1GiB", "100MiB", humanize.GiByte, 0, errInvalidArgument},
+		{"6GiB", "", 6 * humanize.GiByte, 0, errInvalidArgument},
+		{"", "0", defaultMinPartSize, 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_PART_MIN_SIZE", testCase.minEnv)
+		os.Setenv("MINIO_PART_MAX_SIZE", testCase.maxEnv)
+		minSize, err := getPartMinSizeFromEnv()
+		if err != nil || minSize != testCase.minSize {
+			t.Errorf("Test %d: Expected minimum %d, got %d, %v", i+1, testCase.minSize, minSize, err)
+			continue
+		}
+		globalMinPartSize = minSize
+		maxSize, err := getPartMaxSizeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxSize != testCase.maxSize {
+			t.Errorf("Test %d: Expected maximum %d, got %d", i+1, testCase.maxSize, maxSize)
+		}
+	}
+
+	os.Setenv("MINIO_PART_MIN_SIZE", "0")
+	if _, err := getPartMinSizeFromEnv(); err != errInvalidArgument {
+		t.Errorf("Expected errInvalidArgument, got %v", err)
+	}
+}
+
+// Tests parsing of MINIO_UPLOAD_MAX_PARTS env.
+func TestGetUploadMaxPartsFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_UPLOAD_MAX_PARTS")
+
+	testCases := []struct {
+		env         string
+		maxParts    int
+		expectedErr error
+	}{
+		{"", defaultMaxPartID, nil},
+		{"1000", 1000, nil},
+		{"0", 0, errInvalidArgument},
+		{"-1", 0, errInvalidArgument},
+		{"many", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_UPLOAD_MAX_PARTS", testCase.env)
+		maxParts, err := getUploadMaxPartsFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxParts != testCase.maxParts {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.maxParts, maxParts)
+		}
+	}
+}
+
+// Tests parsing of MINIO_CHUNKED_UPLOAD_MAX_SIZE env.
+func TestGetChunkedUploadMaxSizeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_CHUNKED_UPLOAD_MAX_SIZE")
+
+	testCases := []struct {
+		env         string
+		maxSize     int64
+		expectedErr error
+	}{
+		{"", defaultMaxObjectSize, nil},
+		{"1GiB", humanize.GiByte, nil},
+		{"5GiB", defaultMaxObjectSize, nil},
+		{"6GiB", 0, errInvalidArgument},
+		{"0", 0, errInvalidArgument},
+		{"large", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_CHUNKED_UPLOAD_MAX_SIZE", testCase.env)
+		maxSize, err := getChunkedUploadMaxSizeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if maxSize != testCase.maxSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.maxSize, maxSize)
+		}
+	}
+}
+
+// Tests parsing of MINIO_RPC_CLIENT_AUTH env.
+func TestGetRPCClientAuthFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_RPC_CLIENT_AUTH")
+
+	testCases := []struct {
+		env         string
+		clientAuth  bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"on", true, nil},
+		{"ON", true, nil},
+		{"off", false, nil},
+		{"yes", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_RPC_CLIENT_AUTH", testCase.env)
+		clientAuth, err := getRPCClientAuthFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if clientAuth != testCase.clientAuth {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.clientAuth, clientAuth)
+		}
+	}
+}
+
+// Tests parsing of MINIO_TLS_FIPS env.
+func TestGetTLSFIPSFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_TLS_FIPS")
+
+	testCases := []struct {
+		env         string
+		fips        bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"on", true, nil},
+		{"off", false, nil},
+		{"fips", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_TLS_FIPS", testCase.env)
+		fips, err := getTLSFIPSFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if fips != testCase.fips {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.fips, fips)
+		}
+	}
+}
+
+func TestGetSourceMetadataFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_SOURCE_METADATA")
+
+	testCases := []struct {
+		env         string
+		expose      bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"on", true, nil},
+		{"OFF", false, nil},
+		{"yes", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_SOURCE_METADATA", testCase.env)
+		expose, err := getSourceMetadataFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if expose != testCase.expose {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.expose, expose)
+		}
+	}
+}
+
+// Tests fetching object name normalization setting from env.
+func TestGetNormalizeObjectNamesFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_NORMALIZE_OBJECT_NAMES")
+
+	testCases := []struct {
+		env         string
+		normalize   bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"ON", true, nil},
+		{"off", false, nil},
+		{"nfc", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_NORMALIZE_OBJECT_NAMES", testCase.env)
+		normalize, err := getNormalizeObjectNamesFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if normalize != testCase.normalize {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.normalize, normalize)
+		}
+	}
+}
+
+func TestGetMemoryLimitFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_MEMORY_LIMIT")
+
+	testCases := []struct {
+		env         string
+		limit       int64
+		expectedErr error
+	}{
+		{"", int64(getDefaultMemoryLimit()), nil},
+		{"OFF", 0, nil},
+		{"2GiB", 2 * humanize.GiByte, nil},
+		{"512MB", 512 * humanize.MByte, nil},
+		{"1MiB", 0, errInvalidArgument},
+		{"lots", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_MEMORY_LIMIT", testCase.env)
+		limit, err := getMemoryLimitFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if limit != testCase.limit {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.limit, limit)
+		}
+	}
+}
+
+func TestGetReadAheadCacheSizeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_READAHEAD_CACHE")
+
+	testCases := []struct {
+		env         string
+		size        int64
+		expectedErr error
+	}{
+		{"", 0, nil},
+		{"off", 0, nil},
+		{"128MiB", 128 * humanize.MiByte, nil},
+		{"0", 0, errInvalidArgument},
+		{"lots", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_READAHEAD_CACHE", testCase.env)
+		size, err := getReadAheadCacheSizeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if size != testCase.size {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.size, size)
+		}
+	}
+}
+
+func TestGetErasureWorkersFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_ERASURE_WORKERS")
+
+	testCases := []struct {
+		env         string
+		workers     int
+		expectedErr error
+	}{
+		{"", runtime.GOMAXPROCS(0), nil},
+		{"4", 4, nil},
+		{"0", 0, errInvalidArgument},
+		{"-2", 0, errInvalidArgument},
+		{"all", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_ERASURE_WORKERS", testCase.env)
+		workers, err := getErasureWorkersFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if workers != testCase.workers {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.workers, workers)
+		}
+	}
+}
+
+func TestGetXLMetaFormatFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_XL_META_FORMAT")
+
+	testCases := []struct {
+		env         string
+		format      string
+		expectedErr error
+	}{
+		{"", xlMetaFormatJSON, nil},
+		{"binary", xlMetaFormatBinary, nil},
+		{"JSON", xlMetaFormatJSON, nil},
+		{"yaml", "", errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_XL_META_FORMAT", testCase.env)
+		format, err := getXLMetaFormatFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if format != testCase.format {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.format, format)
+		}
+	}
+}
+
+func TestGetLockPolicyFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_LOCK_POLICY")
+
+	testCases := []struct {
+		env         string
+		policy      string
+		expectedErr error
+	}{
+		{"", lockPolicyFIFO, nil},
+		{"fifo", lockPolicyFIFO, nil},
+		{"Writer-Priority", lockPolicyWriterPriority, nil},
+		{"reader-priority", "", errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_LOCK_POLICY", testCase.env)
+		policy, err := getLockPolicyFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if policy != testCase.policy {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.policy, policy)
+		}
+	}
+}
+
+func TestGetLocklessReadsFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_LOCKLESS_READS")
+
+	testCases := []struct {
+		env         string
+		lockless    bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"on", true, nil},
+		{"OFF", false, nil},
+		{"yes", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_LOCKLESS_READS", testCase.env)
+		lockless, err := getLocklessReadsFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if lockless != testCase.lockless {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.lockless, lockless)
+		}
+	}
+}
+
+func TestGetStrictConsistencyFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_CONSISTENCY")
+
+	testCases := []struct {
+		env         string
+		strict      bool
+		expectedErr error
+	}{
+		{"", false, nil},
+		{"default", false, nil},
+		{"Strict", true, nil},
+		{"on", false, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_CONSISTENCY", testCase.env)
+		strict, err := getStrictConsistencyFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if strict != testCase.strict {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.strict, strict)
+		}
+	}
+}
+
+func TestGetDeepScrubRateFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_DEEP_SCRUB")
+
+	testCases := []struct {
+		env         string
+		rate        int64
+		expectedErr error
+	}{
+		{"", 0, nil},
+		{"off", 0, nil},
+		{"ON", defaultDeepScrubRate, nil},
+		{"50MiB", 50 * humanize.MiByte, nil},
+		{"0", 0, errInvalidArgument},
+		{"fast", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_DEEP_SCRUB", testCase.env)
+		rate, err := getDeepScrubRateFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if rate != testCase.rate {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.rate, rate)
+		}
+	}
+}
+
+func TestGetJanitorIntervalFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_JANITOR_INTERVAL")
+
+	testCases := []struct {
+		env         string
+		interval    time.Duration
+		expectedErr error
+	}{
+		{"", defaultJanitorInterval, nil},
+		{"off", 0, nil},
+		{"30m", 30 * time.Minute, nil},
+		{"0s", 0, errInvalidArgument},
+		{"-1h", 0, errInvalidArgument},
+		{"hourly", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_JANITOR_INTERVAL", testCase.env)
+		interval, err := getJanitorIntervalFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if interval != testCase.interval {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.interval, interval)
+		}
+	}
+}
+
+func TestGetJanitorAgeFromEnv(t *testing.T) {
+	defer os.Unsetenv("MINIO_JANITOR_AGE")
+
+	testCases := []struct {
+		env         string
+		age         time.Duration
+		expectedErr error
+	}{
+		{"", defaultJanitorAge, nil},
+		{"1h", time.Hour, nil},
+		{"72h", 72 * time.Hour, nil},
+		{"10m", 0, errInvalidArgument},
+		{"off", 0, errInvalidArgument},
+	}
+	for i, testCase := range testCases {
+		os.Setenv("MINIO_JANITOR_AGE", testCase.env)
+		age, err := getJanitorAgeFromEnv()
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if age != testCase.age {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.age, age)
+		}
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/minio/minio-go/pkg/policy"
 	"github.com/minio/minio/browser"
+	"github.com/minio/minio/pkg/bpool"
 )
 
 // WebGenericArgs - empty struct for calls that don't accept arguments
@@ -54,6 +55,7 @@ type ServerInfoRep struct {
 	MinioPlatform string
 	MinioRuntime  string
 	MinioEnvVars  []string
+	MinioReadOnly bool
 	UIVersion     string `json:"uiVersion"`
 }
 
@@ -84,6 +86,7 @@ func (web *webAPIHandlers) ServerInfo(r *http.Request, args *WebGenericArgs, rep
 	reply.MinioMemory = mem
 	reply.MinioPlatform = platform
 	reply.MinioRuntime = goruntime
+	reply.MinioReadOnly = serverConfig.GetBrowserReadOnly()
 	reply.UIVersion = browser.UIVersion
 	return nil
 }
@@ -122,6 +125,12 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
 
 	// Check if bucket is a reserved bucket name.
 	if isMinioMetaBucket(args.BucketName) || isMinioReservedBucket(args.BucketName) {
@@ -131,7 +140,7 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	bucketLock := globalNSMutex.NewNSLock(args.BucketName, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
-	if err := objectAPI.MakeBucket(args.BucketName); err != nil {
+	if err := globalBucketCreationPolicy.makeBucket(args.BucketName, objectAPI); err != nil {
 		return toJSONError(err, args.BucketName)
 	}
 
@@ -168,6 +177,10 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 		return toJSONError(err)
 	}
 	for _, bucket := range buckets {
+		// Buckets the client may not access are not listed.
+		if !isBucketNetworkAllowed(r, bucket.Name) {
+			continue
+		}
 		reply.Buckets = append(reply.Buckets, WebBucketInfo{
 			Name:         bucket.Name,
 			CreationDate: bucket.Created,
@@ -212,6 +225,9 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
 	prefix := args.Prefix + "test" // To test if GetObject/PutObject with the specified prefix is allowed.
 	readable := isBucketActionAllowed("s3:GetObject", args.BucketName, prefix)
 	writable := isBucketActionAllowed("s3:PutObject", args.BucketName, prefix)
@@ -239,6 +255,10 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	reply.NextMarker = lo.NextMarker
 	reply.IsTruncated = lo.IsTruncated
 	for _, obj := range lo.Objects {
+		// The directory object of the listed folder is not in it.
+		if obj.Name == args.Prefix {
+			continue
+		}
 		reply.Objects = append(reply.Objects, WebObjectInfo{
 			Key:          obj.Name,
 			LastModified: obj.ModTime,
@@ -273,6 +293,12 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
 	if args.BucketName == "" || len(args.Objects) == 0 {
 		return toJSONError(errUnexpected)
 	}
@@ -400,6 +426,9 @@ func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *Se
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
 
 	// If creds are set through ENV disallow changing credentials.
 	if globalIsEnvCreds {
@@ -503,6 +532,10 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
+	if serverConfig.GetBrowserReadOnly() {
+		writeWebErrorResponse(w, errBrowserReadOnly)
+		return
+	}
 
 	// Require Content-Length to be set in the request
 	size := r.ContentLength
@@ -513,14 +546,29 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 
 	// Extract incoming metadata if any.
 	metadata := extractMetadataFromHeader(r.Header)
+	if isMetadataTooLarge(metadata) {
+		writeWebErrorResponse(w, errMetadataTooLarge)
+		return
+	}
 
 	// Lock the object.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
+	if err := checkObjectOverwrite(objectAPI, bucket, object); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	reader, err := globalContentTypePolicy.setContentType(bucket, object, size, metadata, r.Body)
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
 	sha256sum := ""
-	objInfo, err := objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+	objInfo, err := objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
@@ -537,6 +585,248 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// NewUploadArgs - new resumable upload arguments.
+type NewUploadArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+}
+
+// NewUploadRep - new resumable upload reply.
+type NewUploadRep struct {
+	UploadID  string `json:"uploadId"`
+	UIVersion string `json:"uiVersion"`
+}
+
+// NewUpload - starts a resumable upload session. The session is a
+// multipart upload, its state is persisted by the object layer and
+// survives dropped browser connections and server restarts.
+func (web *webAPIHandlers) NewUpload(r *http.Request, args *NewUploadArgs, reply *NewUploadRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
+
+	// Extract incoming metadata if any.
+	metadata := extractMetadataFromHeader(r.Header)
+	if isMetadataTooLarge(metadata) {
+		return toJSONError(errMetadataTooLarge)
+	}
+	globalContentTypePolicy.setContentType(args.BucketName, args.ObjectName, -1, metadata, nil)
+	uploadID, err := objectAPI.NewMultipartUpload(args.BucketName, args.ObjectName, metadata)
+	if err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+	reply.UploadID = uploadID
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// UploadArgs - resumable upload session arguments.
+type UploadArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectName string `json:"objectName"`
+	UploadID   string `json:"uploadId"`
+}
+
+// UploadPart - a completed chunk of a resumable upload.
+type UploadPart struct {
+	PartNumber int    `json:"partNumber"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag"`
+}
+
+// ListUploadPartsRep - resumable upload parts reply.
+type ListUploadPartsRep struct {
+	Parts     []UploadPart `json:"parts"`
+	UIVersion string       `json:"uiVersion"`
+}
+
+// listAllUploadParts - lists all parts uploaded so far for uploadID.
+func listAllUploadParts(objectAPI ObjectLayer, args *UploadArgs) ([]PartInfo, error) {
+	var parts []PartInfo
+	partNumberMarker := 0
+	for {
+		lpi, err := objectAPI.ListObjectParts(args.BucketName, args.ObjectName, args.UploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, lpi.Parts...)
+		if !lpi.IsTruncated {
+			return parts, nil
+		}
+		partNumberMarker = lpi.NextPartNumberMarker
+	}
+}
+
+// ListUploadParts - lists completed chunks of a resumable upload, the
+// browser resumes a dropped upload after the last completed chunk.
+func (web *webAPIHandlers) ListUploadParts(r *http.Request, args *UploadArgs, reply *ListUploadPartsRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+
+	parts, err := listAllUploadParts(objectAPI, args)
+	if err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+	for _, part := range parts {
+		reply.Parts = append(reply.Parts, UploadPart{
+			PartNumber: part.PartNumber,
+			Size:       part.Size,
+			ETag:       part.ETag,
+		})
+	}
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// CompleteUploadRep - complete resumable upload reply.
+type CompleteUploadRep struct {
+	ETag      string `json:"etag"`
+	UIVersion string `json:"uiVersion"`
+}
+
+// CompleteUpload - assembles all uploaded chunks into the object.
+func (web *webAPIHandlers) CompleteUpload(r *http.Request, args *UploadArgs, reply *CompleteUploadRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
+
+	parts, err := listAllUploadParts(objectAPI, args)
+	if err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+	var completeParts []completePart
+	for _, part := range parts {
+		completeParts = append(completeParts, completePart{
+			PartNumber: part.PartNumber,
+			ETag:       part.ETag,
+		})
+	}
+
+	// Lock the object.
+	objectLock := globalNSMutex.NewNSLock(args.BucketName, args.ObjectName)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	if err = checkObjectOverwrite(objectAPI, args.BucketName, args.ObjectName); err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+
+	objInfo, err := objectAPI.CompleteMultipartUpload(args.BucketName, args.ObjectName, args.UploadID, completeParts)
+	if err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+
+	// Notify object created event.
+	eventNotify(eventData{
+		Type:    ObjectCreatedCompleteMultipartUpload,
+		Bucket:  args.BucketName,
+		ObjInfo: objInfo,
+		ReqParams: map[string]string{
+			"sourceIPAddress": r.RemoteAddr,
+		},
+	})
+
+	reply.ETag = objInfo.MD5Sum
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// AbortUpload - cancels a resumable upload and removes its chunks.
+func (web *webAPIHandlers) AbortUpload(r *http.Request, args *UploadArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
+
+	if err := objectAPI.AbortMultipartUpload(args.BucketName, args.ObjectName, args.UploadID); err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// UploadPart - uploads one chunk of a resumable upload started by
+// NewUpload, re-uploading a part number replaces it.
+func (web *webAPIHandlers) UploadPart(w http.ResponseWriter, r *http.Request) {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		writeWebErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	if webRequestAuthenticate(r) != nil {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		writeWebErrorResponse(w, errBrowserReadOnly)
+		return
+	}
+
+	partID, err := strconv.Atoi(vars["partNumber"])
+	if err != nil || partID < 1 || isMaxPartID(partID) {
+		writeWebErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	// Require Content-Length to be set in the request
+	size := r.ContentLength
+	if size < 0 {
+		writeWebErrorResponse(w, errSizeUnspecified)
+		return
+	}
+
+	md5hex, sha256sum := "", ""
+	partInfo, err := objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, md5hex, sha256sum)
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", "\""+partInfo.ETag+"\"")
+}
+
 // Download - file download handler.
 func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	objectAPI := web.ObjectAPI()
@@ -550,7 +840,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	object := vars["object"]
 	token := r.URL.Query().Get("token")
 
-	if !isAuthTokenValid(token) && !isBucketActionAllowed("s3:GetObject", bucket, object) {
+	if !isWebTokenValid(token) && !isBucketActionAllowed("s3:GetObject", bucket, object) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
@@ -559,7 +849,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", path.Base(object)))
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
@@ -588,7 +878,7 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 
 	token := r.URL.Query().Get("token")
 
-	if !isAuthTokenValid(token) {
+	if !isWebTokenValid(token) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
@@ -598,6 +888,10 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, decodeErr)
 		return
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		writeWebErrorResponse(w, errBucketNetworkDenied)
+		return
+	}
 
 	archive := zip.NewWriter(w)
 	defer archive.Close()
@@ -700,6 +994,9 @@ func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
 	if err != nil {
@@ -739,6 +1036,9 @@ func (web *webAPIHandlers) ListAllBucketPolicies(r *http.Request, args *ListAllB
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
 	if err != nil {
@@ -772,6 +1072,12 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
+	if serverConfig.GetBrowserReadOnly() {
+		return toJSONError(errBrowserReadOnly)
+	}
 
 	bucketP := policy.BucketPolicy(args.Policy)
 	if !bucketP.IsValidBucketPolicy() {
@@ -840,6 +1146,9 @@ func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs,
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if !isBucketNetworkAllowed(r, args.BucketName) {
+		return toJSONError(errBucketNetworkDenied)
+	}
 
 	if args.BucketName == "" || args.ObjectName == "" {
 		return &json2.Error{
@@ -863,8 +1172,10 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	dateStr := date.Format(iso8601Format)
 	credential := fmt.Sprintf("%s/%s", accessKey, getScope(date, region))
 
-	var expiryStr = "604800" // Default set to be expire in 7days.
-	if expiry < 604800 && expiry > 0 {
+	// Default to the longest expiry allowed, 7 days at most.
+	maxExpiry := int64(getPresignExpiry() / time.Second)
+	expiryStr := strconv.FormatInt(maxExpiry, 10)
+	if expiry < maxExpiry && expiry > 0 {
 		expiryStr = strconv.FormatInt(expiry, 10)
 	}
 	query := strings.Join([]string{
@@ -963,6 +1274,12 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errInvalidArgument {
+		return APIError{
+			Code:           "InvalidArgument",
+			HTTPStatusCode: http.StatusBadRequest,
+			Description:    err.Error(),
+		}
 	} else if err == errSizeUnspecified {
 		return APIError{
 			Code:           "InvalidRequest",
@@ -975,12 +1292,27 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusMethodNotAllowed,
 			Description:    err.Error(),
 		}
+	} else if err == errBrowserReadOnly {
+		return APIError{
+			Code:           "AccessDenied",
+			HTTPStatusCode: http.StatusForbidden,
+			Description:    err.Error(),
+		}
+	} else if err == errBucketNetworkDenied {
+		return APIError{
+			Code:           "AccessDenied",
+			HTTPStatusCode: http.StatusForbidden,
+			Description:    err.Error(),
+		}
 	} else if err == errReservedBucket {
 		return APIError{
 			Code:           "AllAccessDisabled",
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errTooManyBuckets || err == errBucketNameNotAllowed || err == errMetadataTooLarge ||
+		err == errObjectOverwriteDenied || err == bpool.ErrBpoolTimeout {
+		return getAPIError(toAPIErrorCode(err))
 	}
 	// Convert error type to api error code.
 	var apiErrCode APIErrorCode
@@ -1003,12 +1335,22 @@ func toWebAPIError(err error) APIError {
 		apiErrCode = ErrNoSuchKey
 	case ObjectNameInvalid:
 		apiErrCode = ErrNoSuchKey
+	case ObjectNameNotAllowed:
+		apiErrCode = ErrObjectNameNotAllowed
+	case BucketReadOnly:
+		apiErrCode = ErrBucketReadOnly
 	case InsufficientWriteQuorum:
 		apiErrCode = ErrWriteQuorum
 	case InsufficientReadQuorum:
 		apiErrCode = ErrReadQuorum
 	case PolicyNesting:
 		apiErrCode = ErrPolicyNesting
+	case InvalidUploadID:
+		apiErrCode = ErrNoSuchUpload
+	case InvalidPart:
+		apiErrCode = ErrInvalidPart
+	case PartTooSmall:
+		apiErrCode = ErrEntityTooSmall
 	default:
 		// Log unexpected and unhandled errors.
 		errorIf(err, errUnexpected.Error())
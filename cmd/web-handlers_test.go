@@ -726,6 +726,117 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 	}
 }
 
+// Wrapper for calling resumable upload handlers
+func TestWebHandlerResumableUpload(t *testing.T) {
+	ExecObjectLayerTest(t, testResumableUploadWebHandler)
+}
+
+// testResumableUploadWebHandler - Test resumable upload web handlers
+func testResumableUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	credentials := serverConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	objectName := "a/test.file"
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		// failed to create newbucket, abort.
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	callRPC := func(method string, args interface{}, reply interface{}) error {
+		rec := httptest.NewRecorder()
+		req, rErr := newTestWebRPCRequest("Web."+method, authorization, args)
+		if rErr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", rErr)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
+	}
+
+	uploadPart := func(token, uploadID, partNumber string, content []byte) int {
+		rec := httptest.NewRecorder()
+		req, rErr := http.NewRequest("PUT", "/minio/upload/"+bucketName+"/"+objectName+
+			"?uploadId="+uploadID+"&partNumber="+partNumber, bytes.NewReader(content))
+		if rErr != nil {
+			t.Fatalf("Cannot create upload request, %v", rErr)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	newUploadReply := &NewUploadRep{}
+	if err = callRPC("NewUpload", NewUploadArgs{BucketName: bucketName, ObjectName: objectName}, newUploadReply); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	uploadArgs := UploadArgs{BucketName: bucketName, ObjectName: objectName, UploadID: newUploadReply.UploadID}
+
+	content := []byte("temporary file's content")
+	if code := uploadPart(authorization, uploadArgs.UploadID, "1", content); code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+	// Unauthenticated part upload should fail.
+	if code := uploadPart("", uploadArgs.UploadID, "2", content); code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	}
+	// Invalid part number should fail.
+	if code := uploadPart(authorization, uploadArgs.UploadID, "0", content); code != http.StatusBadRequest {
+		t.Fatalf("Expected the response status to be 400, but instead found `%d`", code)
+	}
+	// Unknown upload id should fail.
+	if code := uploadPart(authorization, "invalid-id", "1", content); code != http.StatusNotFound {
+		t.Fatalf("Expected the response status to be 404, but instead found `%d`", code)
+	}
+
+	// A resuming browser finds the completed part.
+	listReply := &ListUploadPartsRep{}
+	if err = callRPC("ListUploadParts", uploadArgs, listReply); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	if len(listReply.Parts) != 1 || listReply.Parts[0].PartNumber != 1 || listReply.Parts[0].Size != int64(len(content)) {
+		t.Fatalf("%s: Unexpected parts %#v", instanceType, listReply.Parts)
+	}
+
+	completeReply := &CompleteUploadRep{}
+	if err = callRPC("CompleteUpload", uploadArgs, completeReply); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	if completeReply.ETag == "" {
+		t.Fatalf("%s: Expected an ETag", instanceType)
+	}
+
+	var byteBuffer bytes.Buffer
+	if err = obj.GetObject(bucketName, objectName, 0, int64(len(content)), &byteBuffer); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if !bytes.Equal(byteBuffer.Bytes(), content) {
+		t.Fatalf("The upload file is different from the download file")
+	}
+
+	// Aborted uploads are gone.
+	if err = callRPC("NewUpload", NewUploadArgs{BucketName: bucketName, ObjectName: objectName}, newUploadReply); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	uploadArgs.UploadID = newUploadReply.UploadID
+	if err = callRPC("AbortUpload", uploadArgs, &WebGenericRep{}); err != nil {
+		t.Fatalf("%s: Failed, %v", instanceType, err)
+	}
+	if err = callRPC("ListUploadParts", uploadArgs, listReply); err == nil {
+		t.Fatalf("%s: Expected listing an aborted upload to fail", instanceType)
+	}
+}
+
 // Wrapper for calling Download Handler
 func TestWebHandlerDownload(t *testing.T) {
 	ExecObjectLayerTest(t, testDownloadWebHandler)
@@ -1262,7 +1373,8 @@ func TestWebCheckAuthorization(t *testing.T) {
 		"ListBuckets", "ListObjects", "RemoveObject",
 		"GenerateAuth", "SetAuth", "GetAuth",
 		"GetBucketPolicy", "SetBucketPolicy", "ListAllBucketPolicies",
-		"PresignedGet",
+		"PresignedGet", "NewUpload", "ListUploadParts",
+		"CompleteUpload", "AbortUpload",
 	}
 	for _, rpcCall := range webRPCs {
 		args := &AuthRPCArgs{}
@@ -1322,6 +1434,221 @@ func TestWebCheckAuthorization(t *testing.T) {
 	}
 }
 
+// TestWebBrowserReadOnly - Test that read-only browsers cannot change
+// buckets, objects, policies or credentials.
+func TestWebBrowserReadOnly(t *testing.T) {
+	// Prepare XL backend
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Initialization of object layer failed for XL setup: %s", err)
+	}
+	// Executing the object layer tests for XL.
+	defer removeRoots(fsDirs)
+
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal("Init Test config failed", err)
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	credentials := serverConfig.GetCredential()
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if err = setBrowserReadOnly(true); err != nil {
+		t.Fatal(err)
+	}
+
+	webRPCs := []string{
+		"MakeBucket", "RemoveObject", "SetAuth", "SetBucketPolicy",
+		"NewUpload", "CompleteUpload", "AbortUpload",
+	}
+	for _, rpcCall := range webRPCs {
+		rec := httptest.NewRecorder()
+		args := &AuthRPCArgs{}
+		reply := &WebGenericRep{}
+		req, nerr := newTestWebRPCRequest("Web."+rpcCall, authorization, args)
+		if nerr != nil {
+			t.Fatalf("Test %s: Failed to create HTTP request: <ERROR> %v", rpcCall, nerr)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		err = getTestWebRPCResponse(rec, &reply)
+		if err == nil || !strings.Contains(err.Error(), errBrowserReadOnly.Error()) {
+			t.Fatalf("Test %s: should fail with read-only browser. Found error: %v", rpcCall, err)
+		}
+	}
+
+	// Listing is allowed.
+	rec := httptest.NewRecorder()
+	listReply := &ListBucketsRep{}
+	req, err := newTestWebRPCRequest("Web.ListBuckets", authorization, &WebGenericArgs{})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if err = getTestWebRPCResponse(rec, &listReply); err != nil {
+		t.Fatalf("Expected listing to succeed, %v", err)
+	}
+
+	// Uploads are rejected.
+	upload := func() *httptest.ResponseRecorder {
+		content := []byte("temporary file's content")
+		rec = httptest.NewRecorder()
+		req, err = http.NewRequest("PUT", "/minio/upload/"+bucketName+"/object", bytes.NewReader(content))
+		if err != nil {
+			t.Fatalf("Cannot create upload request, %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+authorization)
+		req.Header.Set("x-amz-date", "20160814T114029Z")
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec = upload(); rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", rec.Code)
+	}
+	if resp := rec.Body.String(); resp != errBrowserReadOnly.Error() {
+		t.Fatalf("Unexpected error message, expected: `%s`, found: `%s`", errBrowserReadOnly, resp)
+	}
+
+	// Uploads succeed once the browser is writable again.
+	if err = setBrowserReadOnly(false); err != nil {
+		t.Fatal(err)
+	}
+	if rec = upload(); rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+}
+
+// TestWebBucketNetwork - tests that browser calls naming a bucket are
+// rejected from clients outside of its networks.
+func TestWebBucketNetwork(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal("Init Test config failed", err)
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	// Prepare XL backend
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatalf("Initialization of object layer failed for XL setup: %s", err)
+	}
+	// Executing the object layer tests for XL.
+	defer removeRoots(fsDirs)
+
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+
+	credentials := serverConfig.GetCredential()
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+	bucketName := getRandomBucketName()
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if err = setBucketNetwork(bucketName, bucketNetworkConfig{Allow: []string{"10.0.0.0/8"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	const insideAddr, outsideAddr = "10.1.2.3:5000", "203.0.113.1:5000"
+	args := map[string]interface{}{
+		"bucketName": bucketName,
+		"bucket":     bucketName,
+		"objectName": "object",
+		"objects":    []string{"object"},
+		"uploadId":   "uploadid",
+		"policy":     "readonly",
+	}
+	webRPCs := []string{
+		"MakeBucket", "ListObjects", "RemoveObject", "NewUpload",
+		"ListUploadParts", "CompleteUpload", "AbortUpload",
+		"GetBucketPolicy", "ListAllBucketPolicies", "SetBucketPolicy",
+		"PresignedGet",
+	}
+	for _, rpcCall := range webRPCs {
+		rec := httptest.NewRecorder()
+		reply := &WebGenericRep{}
+		req, nerr := newTestWebRPCRequest("Web."+rpcCall, authorization, args)
+		if nerr != nil {
+			t.Fatalf("Test %s: Failed to create HTTP request: <ERROR> %v", rpcCall, nerr)
+		}
+		req.RemoteAddr = outsideAddr
+		apiRouter.ServeHTTP(rec, req)
+		err = getTestWebRPCResponse(rec, &reply)
+		if err == nil || !strings.Contains(err.Error(), errBucketNetworkDenied.Error()) {
+			t.Fatalf("Test %s: should fail from outside of the bucket networks. Found error: %v", rpcCall, err)
+		}
+	}
+
+	// Buckets are only listed to clients inside of their networks.
+	listBuckets := func(remoteAddr string) []WebBucketInfo {
+		rec := httptest.NewRecorder()
+		listReply := &ListBucketsRep{}
+		req, nerr := newTestWebRPCRequest("Web.ListBuckets", authorization, &WebGenericArgs{})
+		if nerr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", nerr)
+		}
+		req.RemoteAddr = remoteAddr
+		apiRouter.ServeHTTP(rec, req)
+		if nerr = getTestWebRPCResponse(rec, &listReply); nerr != nil {
+			t.Fatalf("Expected listing to succeed, %v", nerr)
+		}
+		return listReply.Buckets
+	}
+	if buckets := listBuckets(outsideAddr); len(buckets) != 0 {
+		t.Fatalf("Expected no buckets to be listed from outside of the bucket networks, got %v", buckets)
+	}
+	if buckets := listBuckets(insideAddr); len(buckets) != 1 || buckets[0].Name != bucketName {
+		t.Fatalf("Expected bucket %s to be listed, got %v", bucketName, buckets)
+	}
+
+	// Listing objects succeeds from inside of the bucket networks.
+	rec := httptest.NewRecorder()
+	listReply := &ListObjectsRep{}
+	req, err := newTestWebRPCRequest("Web.ListObjects", authorization, &ListObjectsArgs{BucketName: bucketName})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	req.RemoteAddr = insideAddr
+	apiRouter.ServeHTTP(rec, req)
+	if err = getTestWebRPCResponse(rec, &listReply); err != nil {
+		t.Fatalf("Expected listing objects to succeed, %v", err)
+	}
+
+	// Zip downloads name the bucket in their body.
+	downloadZip := func(remoteAddr string) int {
+		argsData, merr := json.Marshal(DownloadZipArgs{Objects: []string{"object"}, BucketName: bucketName})
+		if merr != nil {
+			t.Fatal(merr)
+		}
+		rec = httptest.NewRecorder()
+		req, err = http.NewRequest("POST", "/minio/zip?token="+authorization, bytes.NewReader(argsData))
+		if err != nil {
+			t.Fatalf("Cannot create zip download request, %v", err)
+		}
+		req.RemoteAddr = remoteAddr
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := downloadZip(outsideAddr); code != http.StatusForbidden {
+		t.Fatalf("Expected zip download from outside of the bucket networks to fail with %d, got %d", http.StatusForbidden, code)
+	}
+	if code := downloadZip(insideAddr); code != http.StatusOK {
+		t.Fatalf("Expected zip download to succeed, got %d", code)
+	}
+}
+
 // TestWebObjectLayerNotReady - Test RPCs responses when disks are not ready
 func TestWebObjectLayerNotReady(t *testing.T) {
 	// Initialize web rpc endpoint.
@@ -1347,7 +1674,8 @@ func TestWebObjectLayerNotReady(t *testing.T) {
 	// Check if web rpc calls return Server not initialized. ServerInfo, GenerateAuth,
 	// SetAuth and GetAuth are not concerned
 	webRPCs := []string{"StorageInfo", "MakeBucket", "ListBuckets", "ListObjects", "RemoveObject",
-		"GetBucketPolicy", "SetBucketPolicy", "ListAllBucketPolicies"}
+		"GetBucketPolicy", "SetBucketPolicy", "ListAllBucketPolicies",
+		"NewUpload", "ListUploadParts", "CompleteUpload", "AbortUpload"}
 	for _, rpcCall := range webRPCs {
 		args := &AuthRPCArgs{}
 		reply := &WebGenericRep{}
@@ -0,0 +1,89 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	router "github.com/gorilla/mux"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var witnessFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "address",
+		Value: ":9000",
+		Usage: "Bind to a specific ADDRESS:PORT, ADDRESS can be an IP or hostname.",
+	},
+}
+
+var witnessCmd = cli.Command{
+	Name:   "witness",
+	Usage:  "Start a witness node for a distributed server.",
+	Flags:  append(witnessFlags, globalFlags...),
+	Action: mainWitness,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} {{if .VisibleFlags}}[FLAGS]{{end}}
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+DESCRIPTION:
+   A witness node stores no data but votes in the lock and format quorums
+   of the servers started with --witness pointing to it, so that when the
+   servers are split in two halves, e.g. across two racks, the half that
+   reaches the witness first carries on and the other one stops serving.
+   It needs the same credentials as the servers.
+
+EXAMPLES:
+   1. Start a witness node for 2 servers with 2 drives each.
+       $ {{.HelpName}} --address :9000
+       $ minio server --witness 192.168.1.10:9000 http://192.168.1.1{1...2}/mnt/export{1...2}/
+`,
+}
+
+func mainWitness(c *cli.Context) {
+	if c.Args().Present() {
+		cli.ShowCommandHelpAndExit(c, "witness", 1)
+	}
+
+	// Set configuration directory from command line argument.
+	mustSetConfigDirFromContext(c)
+
+	// Initializes server config, certs, logging and system settings.
+	initServerConfig(c)
+
+	serverAddr := c.String("address")
+	var err error
+	globalMinioHost, globalMinioPort, err = getHostPort(serverAddr)
+	fatalIf(err, "Unable to extract host and port %s", serverAddr)
+
+	// Initialize router. `SkipClean(true)` stops gorilla/mux from
+	// normalizing URL path minio/minio#3256
+	mux := router.NewRouter().SkipClean(true)
+	fatalIf(registerWitnessRouter(mux), "Unable to configure witness RPC services.")
+
+	apiServer := NewServerMux(serverAddr, mux)
+	cert, key := "", ""
+	if globalIsSSL {
+		cert, key = getCertFile(), getKeyFile()
+	}
+	console.Println("Witness listening on " + apiServer.Addr)
+	fatalIf(apiServer.ListenAndServe(cert, key), "Failed to start witness.")
+}
@@ -0,0 +1,313 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// A witness node stores no data but casts witnessVotes votes in the lock
+// and format quorums of a distributed setup, so that of the two halves
+// of a cluster split in two only the one that holds its lease carries
+// on. Two votes keep the number of lock nodes even as dsync requires.
+const witnessVotes = 2
+
+const (
+	// RPC path of the witness lease service.
+	witnessPath = "/witness"
+
+	// Lifetime of a lease of the witness, renewed every
+	// witnessClaimInterval by the nodes.
+	witnessLeaseDuration = 10 * time.Second
+	witnessClaimInterval = 3 * time.Second
+)
+
+// Returned when a witness is configured for a setup other than
+// distributed XL.
+var errWitnessNotDistXL = errors.New("A witness can only be used with a distributed XL setup")
+
+// getWitnessLockPath - returns the lock RPC path of the vote-th vote of
+// a witness.
+func getWitnessLockPath(vote int) string {
+	return path.Join(witnessPath, strconv.Itoa(vote+1))
+}
+
+// WitnessClaimArgs - claim of the lease of a witness by Node for the
+// nodes it can reach, itself included.
+type WitnessClaimArgs struct {
+	AuthRPCArgs
+	Node  string
+	Group []string
+}
+
+// witnessServer - lease of a witness node. The lease is granted to the
+// first node claiming it for the nodes it can reach once the previous
+// lease expired, and renewed for any node of the group of the lease.
+type witnessServer struct {
+	AuthRPCServer
+	mu      sync.Mutex
+	group   []string
+	expires time.Time
+}
+
+// Claim - rpc handler granting the lease, replies false if it is held
+// by a group Node is not part of.
+func (w *witnessServer) Claim(args *WitnessClaimArgs, reply *bool) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now().UTC()
+	if now.Before(w.expires) && !contains(w.group, args.Node) {
+		*reply = false
+		return nil
+	}
+	w.group = args.Group
+	w.expires = now.Add(witnessLeaseDuration)
+	*reply = true
+	return nil
+}
+
+// isMember - returns true if node is part of the group of the lease.
+func (w *witnessServer) isMember(node string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return time.Now().UTC().Before(w.expires) && contains(w.group, node)
+}
+
+// witnessLockServer - lock server of a vote of a witness, only nodes of
+// the group of its lease are granted locks.
+type witnessLockServer struct {
+	*lockServer
+	witness *witnessServer
+}
+
+// Lock - rpc handler for write lock operation.
+func (l *witnessLockServer) Lock(args *LockArgs, reply *bool) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	if !l.witness.isMember(args.LockArgs.ServerAddr) {
+		*reply = false
+		return nil
+	}
+	return l.lockServer.Lock(args, reply)
+}
+
+// RLock - rpc handler for read lock operation.
+func (l *witnessLockServer) RLock(args *LockArgs, reply *bool) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	if !l.witness.isMember(args.LockArgs.ServerAddr) {
+		*reply = false
+		return nil
+	}
+	return l.lockServer.RLock(args, reply)
+}
+
+// registerWitnessRouter - registers the lease and lock services of a
+// witness node.
+func registerWitnessRouter(mux *router.Router) error {
+	witness := &witnessServer{}
+	witnessRPCServer := rpc.NewServer()
+	if err := witnessRPCServer.RegisterName("Witness", witness); err != nil {
+		return traceError(err)
+	}
+	witnessRouter := mux.PathPrefix(minioReservedBucketPath).Subrouter()
+	witnessRouter.Path(witnessPath).Handler(witnessRPCServer)
+
+	var lockServers []*lockServer
+	for vote := 0; vote < witnessVotes; vote++ {
+		locker := &lockServer{
+			rpcPath: getWitnessLockPath(vote),
+			lockMap: make(map[string][]lockRequesterInfo),
+		}
+		lockServers = append(lockServers, locker)
+
+		lockRPCServer := rpc.NewServer()
+		if err := lockRPCServer.RegisterName("Dsync", &witnessLockServer{locker, witness}); err != nil {
+			return traceError(err)
+		}
+		witnessRouter.Path(path.Join(lockRPCPath, locker.rpcPath)).Handler(lockRPCServer)
+	}
+	startLockMaintainence(lockServers)
+	return nil
+}
+
+// witnessClient - lease of the witness of this node.
+type witnessClient struct {
+	*AuthRPCClient
+
+	// Lock node of this server and of all the servers.
+	node  string
+	nodes []string
+
+	mu       sync.RWMutex
+	expires  time.Time
+	degraded bool
+}
+
+// Witness of this node, nil unless configured with --witness.
+var globalWitness *witnessClient
+
+// newWitnessClient - returns the client of the witness at addr for the
+// distributed setup of endpoints.
+func newWitnessClient(addr string, endpoints []*url.URL) *witnessClient {
+	cred := serverConfig.GetCredential()
+	w := &witnessClient{
+		AuthRPCClient: newAuthRPCClient(authConfig{
+			accessKey:       cred.AccessKey,
+			secretKey:       cred.SecretKey,
+			serverAddr:      addr,
+			serviceEndpoint: path.Join(minioReservedBucketPath, witnessPath),
+			secureConn:      globalIsSSL,
+			serviceName:     "Witness",
+		}),
+	}
+	for _, ep := range endpoints {
+		if w.node == "" && isLocalStorage(ep) {
+			w.node = ep.Host
+		}
+		if !contains(w.nodes, ep.Host) {
+			w.nodes = append(w.nodes, ep.Host)
+		}
+	}
+	return w
+}
+
+// newLockClients - returns the lock clients of the votes of the witness.
+func (w *witnessClient) newLockClients() []*LockRPCClient {
+	cred := serverConfig.GetCredential()
+	var clnts []*LockRPCClient
+	for vote := 0; vote < witnessVotes; vote++ {
+		clnts = append(clnts, newLockRPCClient(authConfig{
+			accessKey:       cred.AccessKey,
+			secretKey:       cred.SecretKey,
+			serverAddr:      w.ServerAddr(),
+			serviceEndpoint: path.Join(minioReservedBucketPath, lockRPCPath, getWitnessLockPath(vote)),
+			secureConn:      globalIsSSL,
+			serviceName:     "Dsync",
+		}))
+	}
+	return clnts
+}
+
+// claim - claims the lease of the witness for the nodes this node can
+// reach.
+func (w *witnessClient) claim() error {
+	group := []string{w.node}
+	for _, node := range w.nodes {
+		if node == w.node {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", globalPeerResolver.resolve(node), defaultDialTimeout)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+		group = append(group, node)
+	}
+
+	// The lease is held until witnessLeaseDuration after the claim was
+	// sent, never after the witness expires it.
+	sent := time.Now().UTC()
+	var granted bool
+	err := w.Call("Witness.Claim", &WitnessClaimArgs{Node: w.node, Group: group}, &granted)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil || !granted {
+		w.expires = time.Time{}
+		return err
+	}
+	w.expires = sent.Add(witnessLeaseDuration)
+	w.degraded = len(group) < len(w.nodes)
+	return nil
+}
+
+// holdsLease - returns true if this node holds the lease of the witness.
+func (w *witnessClient) holdsLease() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return time.Now().UTC().Before(w.expires)
+}
+
+// isDegraded - returns true if this node holds the lease of the witness
+// but cannot reach all the nodes. No node outside of the group of the
+// lease can take locks then.
+func (w *witnessClient) isDegraded() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.degraded && time.Now().UTC().Before(w.expires)
+}
+
+// startWitness - claims the lease of the witness now and then every
+// witnessClaimInterval.
+func startWitness(w *witnessClient) {
+	errorIf(w.claim(), "Unable to claim the lease of witness %s.", w.ServerAddr())
+	go func() {
+		ticker := time.NewTicker(witnessClaimInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				errorIf(w.claim(), "Unable to claim the lease of witness %s.", w.ServerAddr())
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
+
+// withWitnessVotes - returns the errors of loading the formats of the
+// disks with the votes of the witness for the formatted disks, if this
+// node holds its lease. The witness has no say in formatting new disks.
+func withWitnessVotes(sErrs []error) []error {
+	if globalWitness == nil || !globalWitness.holdsLease() {
+		return sErrs
+	}
+	formatted := false
+	for _, sErr := range sErrs {
+		if sErr == nil {
+			formatted = true
+		}
+	}
+	if !formatted {
+		return sErrs
+	}
+	voteErrs := append([]error{}, sErrs...)
+	for vote := 0; vote < witnessVotes; vote++ {
+		voteErrs = append(voteErrs, nil)
+	}
+	return voteErrs
+}
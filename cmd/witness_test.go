@@ -0,0 +1,151 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/dsync"
+)
+
+// Tests granting and renewal of the lease of a witness, and that only
+// nodes of its group are granted locks.
+func TestWitnessServer(t *testing.T) {
+	testPath, locker, token := createLockTestServer(t)
+	defer removeAll(testPath)
+
+	witness := &witnessServer{}
+	claim := func(node string, group ...string) bool {
+		args := &WitnessClaimArgs{Node: node, Group: group}
+		args.SetAuthToken(token)
+		var granted bool
+		if err := witness.Claim(args, &granted); err != nil {
+			t.Fatal(err)
+		}
+		return granted
+	}
+
+	// Unauthenticated claims fail.
+	var granted bool
+	if err := witness.Claim(&WitnessClaimArgs{Node: "a1", Group: []string{"a1"}}, &granted); err != errInvalidToken {
+		t.Fatalf("Expected errInvalidToken, got %v", err)
+	}
+
+	// The first claim is granted, nodes of the group renew the lease
+	// for the nodes they reach.
+	if !claim("a1", "a1", "a2", "b1", "b2") {
+		t.Fatal("Expected first claim to be granted")
+	}
+	if !claim("b1", "b1", "b2") {
+		t.Fatal("Expected claim of a node of the group to be granted")
+	}
+	if claim("a1", "a1", "a2") || claim("a2", "a1", "a2") {
+		t.Fatal("Expected claims of nodes outside of the group to be rejected")
+	}
+	if !witness.isMember("b2") || witness.isMember("a1") {
+		t.Fatalf("Unexpected group %v", witness.group)
+	}
+
+	// Locks are only granted to nodes of the group.
+	witnessLocker := &witnessLockServer{locker, witness}
+	for _, testCase := range []struct {
+		node   string
+		locked bool
+	}{
+		{"a1", false},
+		{"b1", true},
+	} {
+		la := newLockArgs(dsync.LockArgs{UID: "0123-4567", Resource: testCase.node, ServerAddr: testCase.node, ServiceEndpoint: "rpc-path"})
+		la.SetAuthToken(token)
+		var locked bool
+		if err := witnessLocker.Lock(&la, &locked); err != nil {
+			t.Fatal(err)
+		}
+		if locked != testCase.locked {
+			t.Errorf("Expected lock of %s to be %v, got %v", testCase.node, testCase.locked, locked)
+		}
+		if err := witnessLocker.RLock(&la, &locked); err != nil {
+			t.Fatal(err)
+		}
+		if locked {
+			t.Errorf("Expected read lock of %s over its write lock to be rejected", testCase.node)
+		}
+	}
+
+	// Once the lease expires any node may claim it.
+	witness.expires = time.Now().UTC().Add(-time.Second)
+	if witness.isMember("b1") {
+		t.Fatal("Expected expired lease to have no members")
+	}
+	if !claim("a1", "a1", "a2") {
+		t.Fatal("Expected claim of expired lease to be granted")
+	}
+}
+
+// Tests the votes of the witness in the format quorum.
+func TestWithWitnessVotes(t *testing.T) {
+	defer func() { globalWitness = nil }()
+
+	sErrs := []error{nil, nil, errDiskNotFound, errDiskNotFound}
+	if errs := withWitnessVotes(sErrs); len(errs) != len(sErrs) {
+		t.Fatalf("Expected no votes without a witness, got %v", errs)
+	}
+	if action := prepForInitXL(true, sErrs, len(sErrs)); action != WaitForQuorum {
+		t.Fatalf("Expected half of the disks to wait for quorum, got %v", action)
+	}
+
+	globalWitness = &witnessClient{}
+	if errs := withWitnessVotes(sErrs); len(errs) != len(sErrs) {
+		t.Fatalf("Expected no votes without the lease, got %v", errs)
+	}
+
+	globalWitness.expires = time.Now().UTC().Add(time.Minute)
+	errs := withWitnessVotes(sErrs)
+	if len(errs) != len(sErrs)+witnessVotes {
+		t.Fatalf("Expected %d votes, got %v", witnessVotes, errs)
+	}
+	if action := prepForInitXL(true, errs, len(errs)); action != InitObjectLayer {
+		t.Fatalf("Expected half of the disks with the witness to initialize, got %v", action)
+	}
+
+	// The witness does not vote for unformatted disks.
+	sErrs = []error{errUnformattedDisk, errUnformattedDisk, errUnformattedDisk, errUnformattedDisk}
+	if errs = withWitnessVotes(sErrs); len(errs) != len(sErrs) {
+		t.Fatalf("Expected no votes for unformatted disks, got %v", errs)
+	}
+}
+
+// Tests the write quorum of the half of a split cluster holding the
+// lease of the witness.
+func TestWitnessWriteQuorum(t *testing.T) {
+	defer func() { globalWitness = nil }()
+
+	xl := xlObjects{storageDisks: make([]StorageAPI, 4), writeQuorum: 3}
+	globalWitness = &witnessClient{expires: time.Now().UTC().Add(time.Minute)}
+	if quorum := xl.getWriteQuorum(); quorum != 3 {
+		t.Fatalf("Expected write quorum 3 with all nodes reachable, got %d", quorum)
+	}
+	globalWitness.degraded = true
+	if quorum := xl.getWriteQuorum(); quorum != 2 {
+		t.Fatalf("Expected write quorum 2 holding the lease for half of the nodes, got %d", quorum)
+	}
+	globalWitness.expires = time.Time{}
+	if quorum := xl.getWriteQuorum(); quorum != 3 {
+		t.Fatalf("Expected write quorum 3 without the lease, got %d", quorum)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var xlMetaCmd = cli.Command{
+	Name:   "xl-meta",
+	Usage:  "Print erasure coded object metadata as JSON.",
+	Action: mainXLMeta,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} PATH [PATH...]
+
+DESCRIPTION:
+   Decodes xl.json of either format and prints it as JSON. PATH is an xl.json
+   file or the object directory holding it on one of the disks.
+
+EXAMPLES:
+   1. Print metadata of "photos/1.jpg" in bucket "mybucket" on the first disk:
+       $ {{.HelpName}} /mnt/export1/mybucket/photos/1.jpg
+`,
+}
+
+// readXLMetaFile - decodes `xl.json` at path, or inside the directory
+// at path.
+func readXLMetaFile(path string) (xlMetaV1, error) {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		path = filepath.Join(path, xlMetaJSONFile)
+	}
+	xlMetaBuf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return xlMetaV1{}, err
+	}
+	return xlMetaUnmarshal(xlMetaBuf)
+}
+
+func mainXLMeta(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "xl-meta", 1)
+	}
+
+	for _, path := range ctx.Args() {
+		xlMeta, err := readXLMetaFile(path)
+		if err != nil {
+			console.Fatalf("Unable to read metadata %s. Err: %s.\n", path, err)
+		}
+		xlMetaBytes, err := json.MarshalIndent(xlMeta, "", "  ")
+		if err != nil {
+			console.Fatalf("Unable to encode metadata %s. Err: %s.\n", path, err)
+		}
+		if len(ctx.Args()) > 1 {
+			console.Println(path + ":")
+		}
+		console.Println(string(xlMetaBytes))
+	}
+}
@@ -0,0 +1,298 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+// `xl.json` may be written in a compact binary encoding, format v2,
+// which is an order of magnitude smaller and faster to parse than
+// JSON, the original format v1. As older servers only read v1, v2 is
+// only written if MINIO_XL_META_FORMAT is "binary". Both are read
+// transparently, metadata is migrated to the configured format
+// whenever it is rewritten, e.g. by healing. The file keeps its name
+// for compatibility with existing deployments.
+//
+// A v2 file starts with xlMetaV2Magic followed by the fields of
+// xlMetaV1 in declaration order. Integers are varints, strings and
+// byte slices are prefixed with their length, maps are sorted by key.
+// Hex encoded checksums are stored as raw bytes.
+
+// xlMetaV2Magic - leading bytes of format v2 `xl.json`, JSON always
+// starts with '{'.
+var xlMetaV2Magic = []byte("XLv2")
+
+// Formats of `xl.json`, set with MINIO_XL_META_FORMAT env.
+const (
+	xlMetaFormatJSON   = "json"
+	xlMetaFormatBinary = "binary"
+)
+
+// errXLMetaCorrupted - `xl.json` could not be decoded or is internally
+// inconsistent.
+var errXLMetaCorrupted = errors.New("xl.json is corrupted")
+
+// isXLMetaV2 - returns true if buf holds format v2 `xl.json`.
+func isXLMetaV2(buf []byte) bool {
+	return bytes.HasPrefix(buf, xlMetaV2Magic)
+}
+
+// xlMetaV2Writer - appends format v2 fields to a buffer.
+type xlMetaV2Writer struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *xlMetaV2Writer) writeUvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *xlMetaV2Writer) writeVarint(v int64) {
+	n := binary.PutVarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *xlMetaV2Writer) writeBytes(b []byte) {
+	w.writeUvarint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *xlMetaV2Writer) writeString(s string) {
+	w.writeUvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+// xlMetaV2Reader - reads format v2 fields from a buffer, the first
+// error is sticky and returned by all further reads.
+type xlMetaV2Reader struct {
+	buf []byte
+	err error
+}
+
+func (r *xlMetaV2Reader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = errXLMetaCorrupted
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *xlMetaV2Reader) readVarint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errXLMetaCorrupted
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// readLen - reads a length prefix, which must not exceed the bytes left.
+func (r *xlMetaV2Reader) readLen() int {
+	l := r.readUvarint()
+	if r.err == nil && l > uint64(len(r.buf)) {
+		r.err = errXLMetaCorrupted
+		return 0
+	}
+	return int(l)
+}
+
+func (r *xlMetaV2Reader) readBytes() []byte {
+	l := r.readLen()
+	if r.err != nil {
+		return nil
+	}
+	b := r.buf[:l]
+	r.buf = r.buf[l:]
+	return b
+}
+
+func (r *xlMetaV2Reader) readString() string {
+	return string(r.readBytes())
+}
+
+// xlMetaV2Marshal - encodes xlMeta as format v2 `xl.json`.
+func xlMetaV2Marshal(xlMeta xlMetaV1) []byte {
+	w := &xlMetaV2Writer{}
+	w.buf.Write(xlMetaV2Magic)
+
+	w.writeString(xlMeta.Version)
+	w.writeString(xlMeta.Format)
+	w.writeVarint(xlMeta.Stat.Size)
+	w.writeVarint(xlMeta.Stat.ModTime.Unix())
+	w.writeUvarint(uint64(xlMeta.Stat.ModTime.Nanosecond()))
+
+	w.writeString(xlMeta.Erasure.Algorithm)
+	w.writeVarint(int64(xlMeta.Erasure.DataBlocks))
+	w.writeVarint(int64(xlMeta.Erasure.ParityBlocks))
+	w.writeVarint(xlMeta.Erasure.BlockSize)
+	w.writeVarint(int64(xlMeta.Erasure.Index))
+	w.writeUvarint(uint64(len(xlMeta.Erasure.Distribution)))
+	for _, d := range xlMeta.Erasure.Distribution {
+		w.writeVarint(int64(d))
+	}
+	w.writeUvarint(uint64(len(xlMeta.Erasure.Checksum)))
+	for _, ckSum := range xlMeta.Erasure.Checksum {
+		w.writeString(ckSum.Name)
+		w.writeString(ckSum.Algorithm)
+		// Store lower case hex hashes as raw bytes, anything
+		// else as is, so that decoding yields the same string.
+		if hash, err := hex.DecodeString(ckSum.Hash); err == nil && hex.EncodeToString(hash) == ckSum.Hash {
+			w.writeUvarint(1)
+			w.writeBytes(hash)
+		} else {
+			w.writeUvarint(0)
+			w.writeString(ckSum.Hash)
+		}
+	}
+
+	w.writeString(xlMeta.Minio.Release)
+
+	keys := make([]string, 0, len(xlMeta.Meta))
+	for key := range xlMeta.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	w.writeUvarint(uint64(len(keys)))
+	for _, key := range keys {
+		w.writeString(key)
+		w.writeString(xlMeta.Meta[key])
+	}
+
+	w.writeUvarint(uint64(len(xlMeta.Parts)))
+	for _, part := range xlMeta.Parts {
+		w.writeVarint(int64(part.Number))
+		w.writeString(part.Name)
+		w.writeString(part.ETag)
+		w.writeVarint(part.Size)
+	}
+	return w.buf.Bytes()
+}
+
+// xlMetaV2Unmarshal - decodes format v2 `xl.json`.
+func xlMetaV2Unmarshal(buf []byte) (xlMetaV1, error) {
+	if !isXLMetaV2(buf) {
+		return xlMetaV1{}, errXLMetaCorrupted
+	}
+	r := &xlMetaV2Reader{buf: buf[len(xlMetaV2Magic):]}
+
+	xlMeta := xlMetaV1{}
+	xlMeta.Version = r.readString()
+	xlMeta.Format = r.readString()
+	xlMeta.Stat.Size = r.readVarint()
+	sec := r.readVarint()
+	nsec := r.readUvarint()
+	xlMeta.Stat.ModTime = time.Unix(sec, int64(nsec)).UTC()
+
+	xlMeta.Erasure.Algorithm = r.readString()
+	xlMeta.Erasure.DataBlocks = int(r.readVarint())
+	xlMeta.Erasure.ParityBlocks = int(r.readVarint())
+	xlMeta.Erasure.BlockSize = r.readVarint()
+	xlMeta.Erasure.Index = int(r.readVarint())
+	// Every element takes at least a byte, which bounds the counts
+	// read from corrupted metadata.
+	n := r.readLen()
+	xlMeta.Erasure.Distribution = make([]int, n)
+	for i := 0; i < n; i++ {
+		xlMeta.Erasure.Distribution[i] = int(r.readVarint())
+	}
+	n = r.readLen()
+	xlMeta.Erasure.Checksum = make([]checkSumInfo, n)
+	for i := 0; i < n; i++ {
+		ckSum := checkSumInfo{}
+		ckSum.Name = r.readString()
+		ckSum.Algorithm = r.readString()
+		if r.readUvarint() == 1 {
+			ckSum.Hash = hex.EncodeToString(r.readBytes())
+		} else {
+			ckSum.Hash = r.readString()
+		}
+		xlMeta.Erasure.Checksum[i] = ckSum
+	}
+
+	xlMeta.Minio.Release = r.readString()
+
+	n = r.readLen()
+	xlMeta.Meta = make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		key := r.readString()
+		xlMeta.Meta[key] = r.readString()
+	}
+
+	n = r.readLen()
+	xlMeta.Parts = make([]objectPartInfo, n)
+	for i := 0; i < n; i++ {
+		part := objectPartInfo{}
+		part.Number = int(r.readVarint())
+		part.Name = r.readString()
+		part.ETag = r.readString()
+		part.Size = r.readVarint()
+		xlMeta.Parts[i] = part
+	}
+
+	if r.err != nil {
+		return xlMetaV1{}, r.err
+	}
+	return xlMeta, nil
+}
+
+// xlMetaUnmarshal - decodes `xl.json` of either format.
+func xlMetaUnmarshal(buf []byte) (xlMetaV1, error) {
+	if isXLMetaV2(buf) {
+		return xlMetaV2Unmarshal(buf)
+	}
+	return xlMetaV1UnmarshalJSON(buf)
+}
+
+// xlMetaMarshal - encodes xlMeta in the configured format.
+func xlMetaMarshal(xlMeta xlMetaV1) ([]byte, error) {
+	if globalXLMetaFormat == xlMetaFormatJSON {
+		return json.Marshal(&xlMeta)
+	}
+	return xlMetaV2Marshal(xlMeta), nil
+}
+
+// xlMetaRangeHint - returns the range hint of an object.
+func xlMetaRangeHint(xlMeta xlMetaV1) *RangeHint {
+	if xlMeta.Erasure.BlockSize <= 0 {
+		return nil
+	}
+	partSizes := make([]int64, len(xlMeta.Parts))
+	for i, part := range xlMeta.Parts {
+		partSizes[i] = part.Size
+	}
+	return &RangeHint{
+		BlockSize: xlMeta.Erasure.BlockSize,
+		PartSizes: partSizes,
+	}
+}
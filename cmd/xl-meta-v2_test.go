@@ -0,0 +1,115 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// Tests that format v2 `xl.json` decodes to the same metadata as JSON.
+func TestXLMetaV2MarshalUnmarshal(t *testing.T) {
+	for _, totalParts := range []int{0, 1, 10, 1000} {
+		xlMeta := getSampleXLMeta(totalParts)
+		// Hashes which are not lower case hex are kept as strings.
+		if totalParts > 0 {
+			xlMeta.Erasure.Checksum[0].Hash = "NOT-HEX"
+		}
+
+		buf := xlMetaV2Marshal(xlMeta)
+		if !isXLMetaV2(buf) {
+			t.Fatalf("Parts %d: Expected format v2 magic", totalParts)
+		}
+		v2Meta, err := xlMetaUnmarshal(buf)
+		if err != nil {
+			t.Fatalf("Parts %d: Unexpected error %s", totalParts, err)
+		}
+
+		jsonBuf, err := json.Marshal(xlMeta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonMeta, err := xlMetaUnmarshal(jsonBuf)
+		if err != nil {
+			t.Fatalf("Parts %d: Unexpected error %s", totalParts, err)
+		}
+		compareXLMetaV1(t, jsonMeta, v2Meta)
+		if !v2Meta.Stat.ModTime.Equal(xlMeta.Stat.ModTime) {
+			t.Errorf("Parts %d: Expected ModTime %s, got %s", totalParts, xlMeta.Stat.ModTime, v2Meta.Stat.ModTime)
+		}
+		if !reflect.DeepEqual(v2Meta.Meta, xlMeta.Meta) {
+			t.Errorf("Parts %d: Expected Meta %v, got %v", totalParts, xlMeta.Meta, v2Meta.Meta)
+		}
+		if totalParts >= 10 && len(buf)*2 > len(jsonBuf) {
+			t.Errorf("Parts %d: Expected format v2 (%d bytes) to be less than half of JSON (%d bytes)",
+				totalParts, len(buf), len(jsonBuf))
+		}
+	}
+}
+
+// Tests that corrupted format v2 `xl.json` is rejected.
+func TestXLMetaV2UnmarshalCorrupted(t *testing.T) {
+	buf := xlMetaV2Marshal(getSampleXLMeta(10))
+	for i := len(xlMetaV2Magic); i < len(buf); i++ {
+		if _, err := xlMetaV2Unmarshal(buf[:i]); err != errXLMetaCorrupted {
+			t.Fatalf("Truncated at %d: Expected %s, got %v", i, errXLMetaCorrupted, err)
+		}
+	}
+
+	// A huge element count must not allocate.
+	huge := append(append([]byte{}, xlMetaV2Magic...), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f)
+	if _, err := xlMetaV2Unmarshal(huge); err != errXLMetaCorrupted {
+		t.Fatalf("Expected %s, got %v", errXLMetaCorrupted, err)
+	}
+}
+
+// Tests that xlMetaMarshal honours the configured format.
+func TestXLMetaMarshalFormat(t *testing.T) {
+	defer func(format string) { globalXLMetaFormat = format }(globalXLMetaFormat)
+
+	xlMeta := getSampleXLMeta(1)
+	globalXLMetaFormat = xlMetaFormatJSON
+	buf, err := xlMetaMarshal(xlMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isXLMetaV2(buf) {
+		t.Fatal("Expected JSON format")
+	}
+
+	globalXLMetaFormat = xlMetaFormatBinary
+	buf, err = xlMetaMarshal(xlMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isXLMetaV2(buf) {
+		t.Fatal("Expected format v2")
+	}
+}
+
+// Benchmarks decoding of format v2 `xl.json`.
+func BenchmarkXLMetaV2Unmarshal(b *testing.B) {
+	buf := xlMetaV2Marshal(getSampleXLMeta(10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := xlMetaV2Unmarshal(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -62,24 +62,26 @@ func (xl xlObjects) MakeBucket(bucket string) error {
 	// Wait for all make vol to finish.
 	wg.Wait()
 
-	err := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.writeQuorum)
-	if errorCause(err) == errXLWriteQuorum {
-		// Purge successfully created buckets if we don't have writeQuorum.
-		undoMakeBucket(xl.storageDisks, bucket)
+	err := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.getWriteQuorum())
+	if err != nil && errorCause(err) != errVolumeExists {
+		// Purge successfully created buckets, a bucket existing on
+		// a quorum of disks is healed by the disks which created it.
+		undoMakeBucket(xl.storageDisks, bucket, dErrs)
 	}
 	return toObjectErr(err, bucket)
 }
 
-func (xl xlObjects) undoDeleteBucket(bucket string) {
+// undo delete bucket operation on disks where DeleteVol succeeded.
+func (xl xlObjects) undoDeleteBucket(bucket string, errs []error) {
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
-	// Undo previous make bucket entry on all underlying storage disks.
+	// Undo previous delete bucket entry on all underlying storage disks.
 	for index, disk := range xl.storageDisks {
-		if disk == nil {
+		if disk == nil || errs[index] != nil {
 			continue
 		}
 		wg.Add(1)
-		// Delete a bucket inside a go-routine.
+		// Make a bucket inside a go-routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
 			_ = disk.MakeVol(bucket)
@@ -90,13 +92,13 @@ func (xl xlObjects) undoDeleteBucket(bucket string) {
 	wg.Wait()
 }
 
-// undo make bucket operation upon quorum failure.
-func undoMakeBucket(storageDisks []StorageAPI, bucket string) {
+// undo make bucket operation on disks where MakeVol succeeded.
+func undoMakeBucket(storageDisks []StorageAPI, bucket string, errs []error) {
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
 	// Undo previous make bucket entry on all underlying storage disks.
 	for index, disk := range storageDisks {
-		if disk == nil {
+		if disk == nil || errs[index] != nil {
 			continue
 		}
 		wg.Add(1)
@@ -207,6 +209,11 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 		return BucketNameInvalid{Bucket: bucket}
 	}
 
+	// Buckets are not empty while noncurrent versions of objects are kept.
+	if err := checkBucketVersionsEmpty(xl, bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
 	// Collect if all disks report volume not found.
 	var wg = &sync.WaitGroup{}
 	var dErrs = make([]error, len(xl.storageDisks))
@@ -225,15 +232,6 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 			err := disk.DeleteVol(bucket)
 			if err != nil {
 				dErrs[index] = traceError(err)
-				return
-			}
-			// Cleanup all the previously incomplete multiparts.
-			err = cleanupDir(disk, minioMetaMultipartBucket, bucket)
-			if err != nil {
-				if errorCause(err) == errVolumeNotFound {
-					return
-				}
-				dErrs[index] = err
 			}
 		}(index, disk)
 	}
@@ -241,9 +239,31 @@ func (xl xlObjects) DeleteBucket(bucket string) error {
 	// Wait for all the delete vols to finish.
 	wg.Wait()
 
-	err := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.writeQuorum)
-	if errorCause(err) == errXLWriteQuorum {
-		xl.undoDeleteBucket(bucket)
+	err := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, xl.getWriteQuorum())
+	if err != nil {
+		// Recreate the bucket where it was deleted, unless it is
+		// missing on a quorum of disks.
+		if errorCause(err) != errVolumeNotFound {
+			xl.undoDeleteBucket(bucket, dErrs)
+		}
+		return toObjectErr(err, bucket)
 	}
-	return toObjectErr(err, bucket)
+
+	// Cleanup all the previously incomplete multiparts, only once
+	// the bucket is deleted since this cannot be undone.
+	for _, disk := range xl.storageDisks {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			err := cleanupDir(disk, minioMetaMultipartBucket, bucket)
+			if err != nil && errorCause(err) != errVolumeNotFound {
+				errorIf(err, "Unable to cleanup incomplete uploads of bucket %s.", bucket)
+			}
+		}(disk)
+	}
+	wg.Wait()
+	return nil
 }
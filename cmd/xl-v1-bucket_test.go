@@ -0,0 +1,95 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// Tests that MakeBucket without write quorum leaves no bucket behind.
+func TestXLMakeBucketUndo(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	// Half of the disks are faulty, one less than write quorum succeeds.
+	for i := 0; i < len(xl.storageDisks)/2; i++ {
+		xl.storageDisks[i] = newNaughtyDisk(xl.storageDisks[i].(*retryStorage), nil, errFaultyDisk)
+	}
+
+	bucket := "bucket"
+	err = obj.MakeBucket(bucket)
+	if _, ok := errorCause(err).(InsufficientWriteQuorum); !ok {
+		t.Fatalf("Expected InsufficientWriteQuorum, got %v", err)
+	}
+	for i := len(fsDirs) / 2; i < len(fsDirs); i++ {
+		if _, err = os.Stat(path.Join(fsDirs[i], bucket)); !os.IsNotExist(err) {
+			t.Errorf("Expected bucket to be removed from disk %d, got %v", i, err)
+		}
+	}
+}
+
+// Tests that DeleteBucket failing on a quorum of disks recreates the
+// bucket on the disks where it was deleted.
+func TestXLDeleteBucketUndo(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leave an entry in the bucket on a quorum of disks only.
+	quorum := len(fsDirs)/2 + 1
+	for i := 0; i < quorum; i++ {
+		if err = ioutil.WriteFile(path.Join(fsDirs[i], bucket, "entry"), []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = obj.DeleteBucket(bucket)
+	if _, ok := errorCause(err).(BucketNotEmpty); !ok {
+		t.Fatalf("Expected BucketNotEmpty, got %v", err)
+	}
+	for i, fsDir := range fsDirs {
+		if _, err = os.Stat(path.Join(fsDir, bucket)); err != nil {
+			t.Errorf("Expected bucket to exist on disk %d, got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// isObjectDir - returns true if prefix names an empty directory of
+// bucket, i.e. a directory object.
+func (xl xlObjects) isObjectDir(bucket, prefix string) (ok bool) {
+	for _, disk := range xl.getLoadBalancedDisks() {
+		if disk == nil {
+			continue
+		}
+		// Check if 'prefix' is a directory on this 'disk', else continue the check the next disk
+		entries, err := disk.ListDir(bucket, prefix)
+		if err == nil {
+			return len(entries) == 0
+		}
+		// Ignore for file not found,  disk not found or faulty disk.
+		if isErrIgnored(err, xlTreeWalkIgnoredErrs...) {
+			continue
+		}
+		errorIf(err, "Unable to list directory %s/%s", bucket, prefix)
+		return false
+	} // Exhausted all disks - return false.
+	return false
+}
+
+// putObjectDir - creates the directory object on all disks, also
+// creating the directories it is under. Metadata which would be lost
+// is rejected.
+func (xl xlObjects) putObjectDir(bucket, object string, metadata map[string]string) (ObjectInfo, error) {
+	if err := checkObjectDirArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := checkObjectDirMetadata(bucket, object, metadata); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := globalStrictNamesPolicy.checkStrictObjectName(bucket, strings.TrimSuffix(object, slashSeparator)); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := checkBucketExist(bucket, xl); err != nil {
+		return ObjectInfo{}, traceError(err)
+	}
+	// Check if an object is present as one of the parent dir.
+	if xl.parentDirIsObject(bucket, path.Dir(object)) {
+		return ObjectInfo{}, toObjectErr(traceError(errFileAccessDenied), bucket, object)
+	}
+
+	var wg = &sync.WaitGroup{}
+	var errs = make([]error, len(xl.storageDisks))
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			errs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			if err := disk.MakeDir(bucket, object); err != nil {
+				errs[index] = traceError(err)
+			}
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if err := reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, xl.getWriteQuorum()); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	return xl.getObjectDirInfo(bucket, object)
+}
+
+// getObjectDirInfo - returns the info of the directory object if it is
+// an empty directory on a read quorum of disks. Other names with a
+// trailing slash, including directories once objects are written under
+// them, are invalid object names as they always were.
+func (xl xlObjects) getObjectDirInfo(bucket, object string) (ObjectInfo, error) {
+	if err := checkObjectDirArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	var wg = &sync.WaitGroup{}
+	var errs = make([]error, len(xl.storageDisks))
+	var modTimes = make([]time.Time, len(xl.storageDisks))
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			errs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			fi, err := disk.StatFile(bucket, object)
+			if err != nil {
+				errs[index] = traceError(err)
+				return
+			}
+			entries, err := disk.ListDir(bucket, object)
+			if err != nil {
+				errs[index] = traceError(err)
+				return
+			}
+			if len(entries) > 0 {
+				errs[index] = traceError(errFileNotFound)
+				return
+			}
+			modTimes[index] = fi.ModTime
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if err := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); err != nil {
+		if errorCause(err) == errFileNotFound {
+			return ObjectInfo{}, traceError(ObjectNameInvalid{Bucket: bucket, Object: object})
+		}
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	// Directories are created one disk at a time, the
+	// newest of them is the time of the directory object.
+	var modTime time.Time
+	for _, t := range modTimes {
+		if t.After(modTime) {
+			modTime = t
+		}
+	}
+	return dirObjectInfo(bucket, object, modTime.UTC()), nil
+}
+
+// getObjectDir - reads the empty content of the directory object.
+func (xl xlObjects) getObjectDir(bucket, object string, offset int64, length int64, writer io.Writer) error {
+	if _, err := xl.getObjectDirInfo(bucket, object); err != nil {
+		return err
+	}
+	if offset != 0 || length > 0 {
+		return traceError(InvalidRange{offset, length, 0})
+	}
+	return nil
+}
+
+// deleteObjectDir - deletes the directory object from all disks, and
+// the directories it is under if they are left empty, as for any other
+// object.
+func (xl xlObjects) deleteObjectDir(bucket, object string) error {
+	if _, err := xl.getObjectDirInfo(bucket, object); err != nil {
+		// Names which are not directory objects have no object to delete.
+		if isErrObjectNameInvalid(err) {
+			return traceError(ObjectNotFound{Bucket: bucket, Object: object})
+		}
+		return err
+	}
+
+	// Trim the trailing slash, DeleteFile removes the parents of the
+	// path it is given.
+	dirPath := strings.TrimSuffix(object, slashSeparator)
+	var wg = &sync.WaitGroup{}
+	var errs = make([]error, len(xl.storageDisks))
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			errs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			if err := disk.DeleteFile(bucket, dirPath); err != nil && err != errFileNotFound {
+				errs[index] = traceError(err)
+			}
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if err := reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, xl.getWriteQuorum()); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
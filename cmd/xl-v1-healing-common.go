@@ -107,7 +107,8 @@ func outDatedDisks(disks []StorageAPI, partsMetadata []xlMetaV1, errs []error) (
 	outDatedDisks = make([]StorageAPI, len(disks))
 	latestDisks, _ := listOnlineDisks(disks, partsMetadata, errs)
 	for index, disk := range latestDisks {
-		if errorCause(errs[index]) == errFileNotFound {
+		// Corrupted xl.json is replaced like a missing one.
+		if errorCause(errs[index]) == errFileNotFound || errorCause(errs[index]) == errXLMetaCorrupted {
 			outDatedDisks[index] = disks[index]
 			continue
 		}
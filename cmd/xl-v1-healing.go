@@ -64,7 +64,7 @@ func (xl xlObjects) HealBucket(bucket string) error {
 	}
 
 	// Heal bucket.
-	if err := healBucket(xl.storageDisks, bucket, xl.writeQuorum); err != nil {
+	if err := healBucket(xl.storageDisks, bucket, xl.getWriteQuorum()); err != nil {
 		return err
 	}
 
@@ -84,24 +84,31 @@ func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error
 	// Initialize list of errors.
 	var dErrs = make([]error, len(storageDisks))
 
+	// Errors of MakeVol, errVolumeExists where the bucket was found.
+	var mkErrs = make([]error, len(storageDisks))
+
 	// Make a volume entry on all underlying storage disks.
 	for index, disk := range storageDisks {
 		if disk == nil {
 			dErrs[index] = traceError(errDiskNotFound)
+			mkErrs[index] = dErrs[index]
 			continue
 		}
 		wg.Add(1)
 		// Make a volume inside a go-routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
-			if _, err := disk.StatVol(bucket); err != nil {
-				if err != errVolumeNotFound {
-					dErrs[index] = traceError(err)
-					return
-				}
-				if err = disk.MakeVol(bucket); err != nil {
+			_, err := disk.StatVol(bucket)
+			if err != errVolumeNotFound {
+				if err != nil {
 					dErrs[index] = traceError(err)
 				}
+				mkErrs[index] = traceError(errVolumeExists)
+				return
+			}
+			if err = disk.MakeVol(bucket); err != nil {
+				dErrs[index] = traceError(err)
+				mkErrs[index] = dErrs[index]
 			}
 		}(index, disk)
 	}
@@ -112,7 +119,7 @@ func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error
 	reducedErr := reduceWriteQuorumErrs(dErrs, bucketOpIgnoredErrs, writeQuorum)
 	if errorCause(reducedErr) == errXLWriteQuorum {
 		// Purge successfully created buckets if we don't have writeQuorum.
-		undoMakeBucket(storageDisks, bucket)
+		undoMakeBucket(storageDisks, bucket, mkErrs)
 	}
 	return reducedErr
 }
@@ -145,7 +152,19 @@ func healBucketMetadata(storageDisks []StorageAPI, bucket string, readQuorum int
 
 	// Heal `listeners.json` for missing entries, ignores if `listeners.json` is not found.
 	lConfigPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
-	return healBucketMetaFn(lConfigPath)
+	if err := healBucketMetaFn(lConfigPath); err != nil {
+		return err
+	}
+
+	// Heal `versioning.json` for missing entries, ignores if `versioning.json` is not found.
+	vConfigPath := path.Join(bucketConfigPrefix, bucket, bucketVersioningConfig)
+	if err := healBucketMetaFn(vConfigPath); err != nil {
+		return err
+	}
+
+	// Heal `lifecycle.xml` for missing entries, ignores if `lifecycle.xml` is not found.
+	lcConfigPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+	return healBucketMetaFn(lcConfigPath)
 }
 
 // listAllBuckets lists all buckets from all disks. It also
@@ -202,7 +221,7 @@ func reduceHealStatus(status []healStatus) healStatus {
 // directory and returns the worst heal status that can be found
 func (xl xlObjects) bucketHealStatus(bucketName string) (healStatus, error) {
 	// A list of all the bucket config files
-	configFiles := []string{bucketPolicyConfig, bucketNotificationConfig, bucketListenerConfig}
+	configFiles := []string{bucketPolicyConfig, bucketNotificationConfig, bucketListenerConfig, bucketVersioningConfig, bucketLifecycleConfig}
 	// The status of buckets config files
 	configsHealStatus := make([]healStatus, len(configFiles))
 	// The list of errors found during checking heal status of each config file
@@ -331,42 +350,26 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 	}
 
 	for index, disk := range outDatedDisks {
-		// Before healing outdated disks, we need to remove xl.json
-		// and part files from "bucket/object/" so that
+		// Before healing outdated disks, we need to remove the
+		// contents of "bucket/object/" so that
 		// rename(".minio.sys", "tmp/tmpuuid/", "bucket", "object/") succeeds.
 		if disk == nil {
 			// Not an outdated disk.
 			continue
 		}
 
-		// errFileNotFound implies that xl.json is missing. We
-		// may have object parts still present in the object
-		// directory. This needs to be deleted for object to
+		// errFileNotFound implies that xl.json is missing,
+		// errXLMetaCorrupted that it is unreadable. We may have
+		// object parts still present in the object directory. This needs to be deleted for object to
 		// healed successfully.
-		if errs[index] != nil && !isErr(errs[index], errFileNotFound) {
+		if errs[index] != nil && !isErr(errs[index], errFileNotFound, errXLMetaCorrupted) {
 			continue
 		}
 
-		// Outdated object with the same name exists that needs to be deleted.
-		outDatedMeta := partsMetadata[index]
-		// Consult valid metadata picked when there is no
-		// metadata available on this disk.
-		if isErr(errs[index], errFileNotFound) {
-			outDatedMeta = latestMeta
-		}
-
-		// Delete all the parts. Ignore if parts are not found.
-		for _, part := range outDatedMeta.Parts {
-			err := disk.DeleteFile(bucket, pathJoin(object, part.Name))
-			if err != nil && !isErr(err, errFileNotFound) {
-				return traceError(err)
-			}
-		}
-
-		// Delete xl.json file. Ignore if xl.json not found.
-		err := disk.DeleteFile(bucket, pathJoin(object, xlMetaJSONFile))
-		if err != nil && !isErr(err, errFileNotFound) {
-			return traceError(err)
+		// Delete the whole object directory, it may hold parts
+		// of an interrupted overwrite besides the outdated ones.
+		if err := cleanupDir(disk, bucket, object); err != nil {
+			return err
 		}
 	}
 
@@ -300,7 +300,7 @@ func TestUndoMakeBucket(t *testing.T) {
 		t.Fatal(err)
 	}
 	xl := obj.(*xlObjects)
-	undoMakeBucket(xl.storageDisks, bucketName)
+	undoMakeBucket(xl.storageDisks, bucketName, make([]error, len(xl.storageDisks)))
 
 	// Validate if bucket was deleted properly.
 	_, err = obj.GetBucketInfo(bucketName)
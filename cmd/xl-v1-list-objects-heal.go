@@ -91,7 +91,7 @@ func (xl xlObjects) listObjectsHeal(bucket, prefix, marker, delimiter string, ma
 		endWalkCh = make(chan struct{})
 		isLeaf := xl.isObject
 		listDir := listDirHealFactory(isLeaf, xl.storageDisks...)
-		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, nil, endWalkCh)
+		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, nil, nil, endWalkCh)
 	}
 
 	var objInfos []ObjectInfo
@@ -0,0 +1,284 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Maximum number of prefetched `xl.json` held for a tree walk, beyond
+// it entries are looked up one by one.
+const xlListMetaMaxEntries = 4 * maxObjectList
+
+// xlListMetaEntry - object info parsed from a prefetched `xl.json`, or
+// the error reading it.
+type xlListMetaEntry struct {
+	objInfo ObjectInfo
+	err     error
+}
+
+// xlListMeta - `xl.json` of the entries of a tree walk, read in batches
+// of a list page with a single ReadAllBulk round trip per disk. Without
+// it every listed object costs a StatFile round trip for the isLeaf
+// check and a ReadAll round trip for its object info, which dominates
+// listing latency on distributed setups.
+type xlListMeta struct {
+	xl     xlObjects
+	bucket string
+
+	mutex     *sync.Mutex
+	batchSize int
+	// Sorted entries of listed directories which are not yet prefetched.
+	dirs map[string][]string
+	// Prefetched entries by object name.
+	metas map[string]xlListMetaEntry
+}
+
+// newXLListMeta - initializes the metadata of a new tree walk.
+func newXLListMeta(xl xlObjects, bucket string) *xlListMeta {
+	return &xlListMeta{
+		xl:        xl,
+		bucket:    bucket,
+		mutex:     &sync.Mutex{},
+		batchSize: maxObjectList,
+		dirs:      make(map[string][]string),
+		metas:     make(map[string]xlListMetaEntry),
+	}
+}
+
+// xlListMetaDisk - records the entries listed by ListDir for prefetching.
+type xlListMetaDisk struct {
+	StorageAPI
+	listMeta *xlListMeta
+}
+
+// ListDir - lists the directory and records its entries.
+func (d xlListMetaDisk) ListDir(volume, dirPath string) ([]string, error) {
+	entries, err := d.StorageAPI.ListDir(volume, dirPath)
+	if err == nil && volume == d.listMeta.bucket {
+		d.listMeta.addDir(dirPath, entries)
+	}
+	return entries, err
+}
+
+// wrapDisks - returns the disks with ListDir recording entries.
+func (m *xlListMeta) wrapDisks(disks []StorageAPI) []StorageAPI {
+	wrapped := make([]StorageAPI, len(disks))
+	for i, disk := range disks {
+		if disk != nil {
+			wrapped[i] = xlListMetaDisk{disk, m}
+		}
+	}
+	return wrapped
+}
+
+// setBatchSize - sets the number of entries prefetched at once,
+// usually the size of the list page.
+func (m *xlListMeta) setBatchSize(batchSize int) {
+	if m == nil || batchSize <= 0 {
+		return
+	}
+	m.mutex.Lock()
+	m.batchSize = batchSize
+	m.mutex.Unlock()
+}
+
+func (m *xlListMeta) addDir(dirPath string, entries []string) {
+	sorted := make([]string, len(entries))
+	copy(sorted, entries)
+	sort.Strings(sorted)
+
+	// Entries are looked up by their parent directory with a trailing
+	// slash, or "" for the top level.
+	if dirPath != "" {
+		dirPath = retainSlash(dirPath)
+	}
+	m.mutex.Lock()
+	m.dirs[dirPath] = sorted
+	m.mutex.Unlock()
+}
+
+// isLeaf - isLeafFunc answering from prefetched `xl.json`, entries which
+// could not be prefetched are checked with xl.isObject.
+func (m *xlListMeta) isLeaf(bucket, entry string) bool {
+	object := strings.TrimSuffix(entry, slashSeparator)
+
+	m.mutex.Lock()
+	meta, ok := m.metas[object]
+	if !ok {
+		m.prefetch(object)
+		meta, ok = m.metas[object]
+	}
+	if ok && meta.err != nil {
+		delete(m.metas, object)
+	}
+	m.mutex.Unlock()
+
+	if ok && meta.err == nil {
+		return true
+	}
+	if ok && meta.err == errFileNotFound {
+		return false
+	}
+	return m.xl.isObject(bucket, entry)
+}
+
+// objectInfo - returns and forgets the prefetched object info of object.
+func (m *xlListMeta) objectInfo(object string) (ObjectInfo, bool) {
+	if m == nil {
+		return ObjectInfo{}, false
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	meta, ok := m.metas[object]
+	if !ok || meta.err != nil {
+		return ObjectInfo{}, false
+	}
+	delete(m.metas, object)
+	return meta.objInfo, true
+}
+
+// prefetch - reads the `xl.json` of the directory entries from object
+// on, up to the batch size. Entries not found on a disk are retried on
+// the next disk, as the object may be missing on some disks.
+func (m *xlListMeta) prefetch(object string) {
+	if len(m.metas) >= xlListMetaMaxEntries {
+		return
+	}
+	dir, name := "", object+slashSeparator
+	if i := strings.LastIndex(object, slashSeparator); i >= 0 {
+		dir, name = object[:i+1], object[i+1:]+slashSeparator
+	}
+	entries := m.dirs[dir]
+	start := sort.SearchStrings(entries, name)
+	if start == len(entries) || entries[start] != name {
+		return
+	}
+
+	// Only directories can be objects.
+	var objects, paths []string
+	end := start
+	for ; end < len(entries) && len(objects) < m.batchSize; end++ {
+		if hasSuffix(entries[end], slashSeparator) {
+			objects = append(objects, dir+strings.TrimSuffix(entries[end], slashSeparator))
+			paths = append(paths, pathJoin(dir, entries[end], xlMetaJSONFile))
+		}
+	}
+	if end == len(entries) {
+		delete(m.dirs, dir)
+	} else {
+		m.dirs[dir] = entries[end:]
+	}
+
+	pending := make([]int, len(objects))
+	for i := range pending {
+		pending[i] = i
+	}
+	lastErrs := make([]error, len(objects))
+	for _, disk := range m.xl.getLoadBalancedDisks() {
+		if disk == nil || len(pending) == 0 {
+			continue
+		}
+		pendingPaths := make([]string, len(pending))
+		for i, index := range pending {
+			pendingPaths[i] = paths[index]
+		}
+		bufs, errs := disk.ReadAllBulk(m.bucket, pendingPaths)
+		var stillPending []int
+		for i, index := range pending {
+			err := errs[i]
+			if err == nil {
+				xlStat, xlMetaMap, rangeHint, perr := parseXLMetaStat(bufs[i])
+				if perr == nil {
+					m.metas[objects[index]] = xlListMetaEntry{
+						objInfo: newXLObjectInfo(m.bucket, objects[index], xlStat, xlMetaMap, rangeHint),
+					}
+					continue
+				}
+				err = errorCause(perr)
+			}
+			if err != errFileNotFound || lastErrs[index] == nil {
+				lastErrs[index] = err
+			}
+			stillPending = append(stillPending, index)
+		}
+		pending = stillPending
+	}
+	for _, index := range pending {
+		err := lastErrs[index]
+		if err == nil || isErrIgnored(err, xlTreeWalkIgnoredErrs...) {
+			err = errFileNotFound
+		}
+		m.metas[objects[index]] = xlListMetaEntry{err: err}
+	}
+}
+
+// xlListMetas - metadata of the tree walks kept in the list pool,
+// looked up by their result channel.
+type xlListMetas struct {
+	mutex *sync.Mutex
+	metas map[chan treeWalkResult]xlListMetaWalk
+}
+
+type xlListMetaWalk struct {
+	listMeta  *xlListMeta
+	endWalkCh chan struct{}
+}
+
+// newXLListMetas - initializes metadata of tree walks.
+func newXLListMetas() *xlListMetas {
+	return &xlListMetas{
+		mutex: &sync.Mutex{},
+		metas: make(map[chan treeWalkResult]xlListMetaWalk),
+	}
+}
+
+// Set - keeps the metadata of a tree walk added to the list pool.
+func (l *xlListMetas) Set(resultCh chan treeWalkResult, endWalkCh chan struct{}, listMeta *xlListMeta) {
+	if l == nil || listMeta == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.metas[resultCh] = xlListMetaWalk{listMeta, endWalkCh}
+}
+
+// Release - returns and forgets the metadata of a tree walk released
+// from the list pool, nil if there is none. Metadata of tree walks
+// timed out of the list pool is dropped.
+func (l *xlListMetas) Release(resultCh chan treeWalkResult) *xlListMeta {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for ch, walk := range l.metas {
+		select {
+		case <-walk.endWalkCh:
+			delete(l.metas, ch)
+		default:
+		}
+	}
+	walk, ok := l.metas[resultCh]
+	if !ok {
+		return nil
+	}
+	delete(l.metas, resultCh)
+	return walk.listMeta
+}
@@ -0,0 +1,114 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// countingDisk - counts the per file metadata round trips.
+type countingDisk struct {
+	StorageAPI
+	calls *int32
+}
+
+func (d countingDisk) StatFile(volume, path string) (FileInfo, error) {
+	atomic.AddInt32(d.calls, 1)
+	return d.StorageAPI.StatFile(volume, path)
+}
+
+func (d countingDisk) ReadAll(volume, path string) ([]byte, error) {
+	atomic.AddInt32(d.calls, 1)
+	return d.StorageAPI.ReadAll(volume, path)
+}
+
+// Tests that listing with bulk read metadata matches listing object by
+// object, without a round trip per object.
+func TestXLListObjectsBulkMeta(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	objects := []string{"a", "a-b", "b/c", "b/d/e", "f", "g/h", "i"}
+	for _, object := range objects {
+		_, err = obj.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), map[string]string{"x-amz-meta-key": object}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Object missing on a disk is listed from the other disks.
+	for _, fsDir := range fsDirs {
+		if err = os.Remove(path.Join(fsDir, bucket, "f", xlMetaJSONFile)); err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+
+	var calls int32
+	for i, disk := range xl.storageDisks {
+		xl.storageDisks[i] = countingDisk{disk, &calls}
+	}
+
+	xlPerObject := *xl
+	xlPerObject.listMetas = nil
+	xlPerObject.listPool = newTreeWalkPool(globalLookupTimeout)
+
+	for _, delimiter := range []string{"", slashSeparator} {
+		for _, maxKeys := range []int{1, 2, 1000} {
+			var results []ListObjectsInfo
+			for _, layer := range []*xlObjects{xl, &xlPerObject} {
+				atomic.StoreInt32(&calls, 0)
+				var result ListObjectsInfo
+				marker := ""
+				for {
+					page, lerr := layer.ListObjects(bucket, "", marker, delimiter, maxKeys)
+					if lerr != nil {
+						t.Fatal(lerr)
+					}
+					result.Objects = append(result.Objects, page.Objects...)
+					result.Prefixes = append(result.Prefixes, page.Prefixes...)
+					if !page.IsTruncated {
+						break
+					}
+					marker = page.NextMarker
+				}
+				if layer == xl && atomic.LoadInt32(&calls) != 0 {
+					t.Errorf("Delimiter %q, max keys %d: Expected no per object round trips, got %d", delimiter, maxKeys, calls)
+				}
+				results = append(results, result)
+			}
+			if !reflect.DeepEqual(results[0], results[1]) {
+				t.Errorf("Delimiter %q, max keys %d: Expected %v, got %v", delimiter, maxKeys, results[1], results[0])
+			}
+			if delimiter == "" && len(results[0].Objects) != len(objects) {
+				t.Errorf("Max keys %d: Expected %d objects, got %d", maxKeys, len(objects), len(results[0].Objects))
+			}
+		}
+	}
+}
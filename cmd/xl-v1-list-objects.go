@@ -44,6 +44,53 @@ func listDirFactory(isLeaf isLeafFunc, treeWalkIgnoredErrs []error, disks ...Sto
 	return listDir
 }
 
+// listDirQuorum - returns a listDir function merging the entries of
+// read quorum disks. Every write quorum shares a disk with them, so
+// unlike listing the first disk responding, the entries include every
+// object whose write succeeded even if some disks missed it.
+func (xl xlObjects) listDirQuorum(isLeaf isLeafFunc) listDirFunc {
+	listDir := func(bucket, prefixDir, prefixEntry string) (entries []string, delayIsLeaf bool, err error) {
+		entrySet := make(map[string]struct{})
+		listed, found := 0, false
+		for _, disk := range xl.getLoadBalancedDisks() {
+			if disk == nil {
+				continue
+			}
+			var diskEntries []string
+			diskEntries, err = disk.ListDir(bucket, prefixDir)
+			if err == nil {
+				found = true
+				for _, entry := range diskEntries {
+					entrySet[entry] = struct{}{}
+				}
+			} else if err != errFileNotFound {
+				// For any reason disk was deleted or goes offline,
+				// continue and list from other disks if possible.
+				if isErrIgnored(err, xlTreeWalkIgnoredErrs...) {
+					continue
+				}
+				return nil, false, traceError(err)
+			}
+			if listed++; listed == xl.readQuorum {
+				break
+			}
+		}
+		if listed < xl.readQuorum {
+			return nil, false, traceError(errXLReadQuorum)
+		}
+		if !found {
+			return nil, false, traceError(errFileNotFound)
+		}
+
+		for entry := range entrySet {
+			entries = append(entries, entry)
+		}
+		entries, delayIsLeaf = filterListEntries(bucket, prefixDir, entries, prefixEntry, isLeaf)
+		return entries, delayIsLeaf, nil
+	}
+	return listDir
+}
+
 // listObjects - wrapper function implemented over file tree walk.
 func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	// Default is recursive, if delimiter is set then list non recursive.
@@ -54,12 +101,27 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 
 	heal := false // true only for xl.ListObjectsHeal
 	walkResultCh, endWalkCh := xl.listPool.Release(listParams{bucket, recursive, marker, prefix, heal})
+	listMeta := xl.listMetas.Release(walkResultCh)
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
 		isLeaf := xl.isObject
-		listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, xl.getLoadBalancedDisks()...)
-		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
+		var listDir listDirFunc
+		if globalStrictConsistency {
+			// Object info is read with quorum, one by one.
+			listDir = xl.listDirQuorum(isLeaf)
+		} else {
+			disks := xl.getLoadBalancedDisks()
+			if xl.listMetas != nil {
+				// Read `xl.json` of the listed objects in bulk.
+				listMeta = newXLListMeta(xl, bucket)
+				isLeaf = listMeta.isLeaf
+				disks = listMeta.wrapDisks(disks)
+			}
+			listDir = listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, disks...)
+		}
+		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, xl.isObjectDir, endWalkCh)
 	}
+	listMeta.setBatchSize(maxKeys)
 
 	var objInfos []ObjectInfo
 	var eof bool
@@ -81,7 +143,16 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 		}
 		entry := walkResult.entry
 		var objInfo ObjectInfo
-		if hasSuffix(entry, slashSeparator) {
+		if walkResult.dirObject {
+			var err error
+			if objInfo, err = xl.getObjectDirInfo(bucket, entry); err != nil {
+				// Ignore directories no longer empty.
+				if isErrObjectNameInvalid(err) {
+					continue
+				}
+				return ListObjectsInfo{}, toObjectErr(err, bucket, prefix)
+			}
+		} else if hasSuffix(entry, slashSeparator) {
 			// Object name needs to be full path.
 			objInfo.Bucket = bucket
 			objInfo.Name = entry
@@ -89,7 +160,10 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 		} else {
 			// Set the Mode to a "regular" file.
 			var err error
-			objInfo, err = xl.getObjectInfo(bucket, entry)
+			var ok bool
+			if objInfo, ok = listMeta.objectInfo(entry); !ok {
+				objInfo, err = xl.getObjectInfo(bucket, entry)
+			}
 			if err != nil {
 				// Ignore errFileNotFound
 				if errorCause(err) == errFileNotFound {
@@ -109,7 +183,14 @@ func (xl xlObjects) listObjects(bucket, prefix, marker, delimiter string, maxKey
 
 	params := listParams{bucket, recursive, nextMarker, prefix, heal}
 	if !eof {
-		xl.listPool.Set(params, walkResultCh, endWalkCh)
+		if globalStrictConsistency {
+			// The walk has run ahead of this page, the next page
+			// walks again to include objects written meanwhile.
+			close(endWalkCh)
+		} else {
+			xl.listPool.Set(params, walkResultCh, endWalkCh)
+			xl.listMetas.Set(walkResultCh, endWalkCh, listMeta)
+		}
 	}
 
 	result := ListObjectsInfo{IsTruncated: !eof}
@@ -0,0 +1,91 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+// Tests that strict consistency lists objects missing on some disks
+// and hides objects left behind on less than read quorum disks.
+func TestXLListObjectsStrictConsistency(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	defer func(strict bool) { globalStrictConsistency = strict }(globalStrictConsistency)
+	globalStrictConsistency = true
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"a", "b/c", "d", "stale"} {
+		_, err = obj.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "a" was missed by 7 disks, "stale" was deleted from all but one.
+	for i, fsDir := range fsDirs {
+		if i < 7 {
+			if err = os.RemoveAll(path.Join(fsDir, bucket, "a")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if i > 0 {
+			if err = os.RemoveAll(path.Join(fsDir, bucket, "stale")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	expected := []string{"a", "b/c", "d"}
+	// Disks are listed in random order.
+	for i := 0; i < 10; i++ {
+		for _, maxKeys := range []int{1, 1000} {
+			var names []string
+			marker := ""
+			for {
+				result, lerr := obj.ListObjects(bucket, "", marker, "", maxKeys)
+				if lerr != nil {
+					t.Fatal(lerr)
+				}
+				for _, objInfo := range result.Objects {
+					names = append(names, objInfo.Name)
+				}
+				if !result.IsTruncated {
+					break
+				}
+				marker = result.NextMarker
+			}
+			if !reflect.DeepEqual(names, expected) {
+				t.Fatalf("Max keys %d: Expected %v, got %v", maxKeys, expected, names)
+			}
+		}
+	}
+
+	if _, err = obj.GetObjectInfo(bucket, "stale"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected object not found, got %v", err)
+	}
+}
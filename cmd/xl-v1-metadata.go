@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"path"
 	"runtime"
@@ -245,7 +244,7 @@ func pickValidXLMeta(metaArr []xlMetaV1, modTime time.Time) (xlMetaV1, error) {
 }
 
 // list of all errors that can be ignored in a metadata operation.
-var objMetadataOpIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied, errVolumeNotFound, errFileNotFound, errFileAccessDenied)
+var objMetadataOpIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied, errVolumeNotFound, errFileNotFound, errFileAccessDenied, errXLMetaCorrupted)
 
 // readXLMetaParts - returns the XL Metadata Parts from xl.json of one of the disks picked at random.
 func (xl xlObjects) readXLMetaParts(bucket, object string) (xlMetaParts []objectPartInfo, err error) {
@@ -268,16 +267,16 @@ func (xl xlObjects) readXLMetaParts(bucket, object string) (xlMetaParts []object
 	return nil, err
 }
 
-// readXLMetaStat - return xlMetaV1.Stat and xlMetaV1.Meta from  one of the disks picked at random.
-func (xl xlObjects) readXLMetaStat(bucket, object string) (xlStat statInfo, xlMeta map[string]string, err error) {
+// readXLMetaStat - return xlMetaV1.Stat, xlMetaV1.Meta and the range hint from one of the disks picked at random.
+func (xl xlObjects) readXLMetaStat(bucket, object string) (xlStat statInfo, xlMeta map[string]string, rangeHint *RangeHint, err error) {
 	for _, disk := range xl.getLoadBalancedDisks() {
 		if disk == nil {
 			continue
 		}
-		// parses only xlMetaV1.Meta and xlMeta.Stat
-		xlStat, xlMeta, err = readXLMetaStat(disk, bucket, object)
+		// parses only xlMetaV1.Meta, xlMeta.Stat and the range hint
+		xlStat, xlMeta, rangeHint, err = readXLMetaStat(disk, bucket, object)
 		if err == nil {
-			return xlStat, xlMeta, nil
+			return xlStat, xlMeta, rangeHint, nil
 		}
 		// For any reason disk or bucket is not available continue
 		// and read from other disks.
@@ -287,7 +286,7 @@ func (xl xlObjects) readXLMetaStat(bucket, object string) (xlStat statInfo, xlMe
 		break
 	}
 	// Return error here.
-	return statInfo{}, nil, err
+	return statInfo{}, nil, nil, err
 }
 
 // deleteXLMetadata - deletes `xl.json` on a single disk.
@@ -300,8 +299,8 @@ func deleteXLMetdata(disk StorageAPI, bucket, prefix string) error {
 func writeXLMetadata(disk StorageAPI, bucket, prefix string, xlMeta xlMetaV1) error {
 	jsonFile := path.Join(prefix, xlMetaJSONFile)
 
-	// Marshal json.
-	metadataBytes, err := json.Marshal(&xlMeta)
+	// Marshal in the configured format.
+	metadataBytes, err := xlMetaMarshal(xlMeta)
 	if err != nil {
 		return traceError(err)
 	}
@@ -17,8 +17,13 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path"
 	"testing"
 	"time"
+
+	humanize "github.com/dustin/go-humanize"
 )
 
 func TestUpdateUploadJSON(t *testing.T) {
@@ -73,3 +78,73 @@ func TestUpdateUploadJSON(t *testing.T) {
 		t.Errorf("Expected write quorum error, but got: %v", testErrVal)
 	}
 }
+
+// Tests that completing a multipart upload moves the part shards of
+// each disk into the object without rewriting them.
+func TestXLCompleteMultipartMovesShards(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parts []completePart
+	for partID, size := range []int64{5 * humanize.MiByte, 1 * humanize.KiByte} {
+		data := bytes.Repeat([]byte{byte('a' + partID)}, int(size))
+		info, pErr := obj.PutObjectPart(bucket, object, uploadID, partID+1, size, bytes.NewReader(data), "", "")
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		parts = append(parts, completePart{PartNumber: info.PartNumber, ETag: info.ETag})
+	}
+
+	// Shards of the parts on each disk before completing.
+	shards := make(map[string]os.FileInfo)
+	for _, fsDir := range fsDirs {
+		for _, partName := range []string{"part.1", "part.2"} {
+			fi, sErr := os.Stat(path.Join(fsDir, minioMetaMultipartBucket, bucket, object, uploadID, partName))
+			if sErr != nil {
+				t.Fatal(sErr)
+			}
+			shards[path.Join(fsDir, partName)] = fi
+		}
+	}
+
+	if _, err = obj.CompleteMultipartUpload(bucket, object, uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+	for _, fsDir := range fsDirs {
+		for _, partName := range []string{"part.1", "part.2"} {
+			fi, sErr := os.Stat(path.Join(fsDir, bucket, object, partName))
+			if sErr != nil {
+				t.Fatal(sErr)
+			}
+			if !os.SameFile(shards[path.Join(fsDir, partName)], fi) {
+				t.Fatalf("Expected %s of %s to be moved, not rewritten", partName, fsDir)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, 5*humanize.MiByte+humanize.KiByte, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Bytes()[0] != 'a' || buf.Bytes()[buf.Len()-1] != 'b' {
+		t.Fatal("Unexpected content of completed object")
+	}
+}
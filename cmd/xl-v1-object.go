@@ -36,6 +36,14 @@ import (
 // list all errors which can be ignored in object operations.
 var objectOpIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied)
 
+// list all errors which can be ignored when reading an object, disks
+// with a corrupted `xl.json` are repaired by healing like offline ones.
+var objectReadIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied, errXLMetaCorrupted)
+
+// Number of times the remaining range of a part is read again when
+// disks fail in the middle of a GET, before the request is aborted.
+const xlDegradedReadRetries = 2
+
 /// Object Operations
 
 // CopyObject - copy object source object to destination object.
@@ -44,7 +52,7 @@ var objectOpIgnoredErrs = append(baseIgnoredErrs, errDiskAccessDenied)
 func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, srcBucket, srcObject)
-	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+	if reducedErr := reduceReadQuorumErrs(errs, objectReadIgnoredErrs, xl.readQuorum); reducedErr != nil {
 		return ObjectInfo{}, toObjectErr(reducedErr, srcBucket, srcObject)
 	}
 
@@ -66,6 +74,16 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	// Check if this request is only metadata update.
 	cpMetadataOnly := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	if cpMetadataOnly {
+		// Keep the previous version if versioning is enabled or
+		// suspended on the bucket.
+		var versionDone func(committed bool)
+		versionDone, err = preserveObjectMetadataVersion(xl, srcBucket, srcObject, metadata)
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+		if modTime, ok := popObjectModTime(metadata); ok {
+			xlMeta.Stat.ModTime = modTime
+		}
 		xlMeta.Meta = metadata
 		partsMetadata := make([]xlMetaV1, len(xl.storageDisks))
 		// Update `xl.json` content on each disks.
@@ -76,13 +94,16 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 		tempObj := mustGetUUID()
 
 		// Write unique `xl.json` for each disk.
-		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.getWriteQuorum()); err != nil {
+			versionDone(false)
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 		// Rename atomically `xl.json` from tmp location to destination for each disk.
-		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
+		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.getWriteQuorum()); err != nil {
+			versionDone(false)
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
+		versionDone(true)
 
 		objInfo := ObjectInfo{
 			IsDir:           false,
@@ -133,6 +154,9 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
 func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	if hasSuffix(object, slashSeparator) {
+		return xl.getObjectDir(bucket, object, startOffset, length, writer)
+	}
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -149,7 +173,15 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
-	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+
+	// Objects which can no longer be read because of corrupted
+	// metadata are moved out of the way.
+	checkXLMetaIntegrity(metaArr, errs)
+	if xl.shouldQuarantine(errs) {
+		xl.startQuarantine(bucket, object)
+		return traceError(ObjectNotFound{bucket, object})
+	}
+	if reducedErr := reduceReadQuorumErrs(errs, objectReadIgnoredErrs, xl.readQuorum); reducedErr != nil {
 		return toObjectErr(reducedErr, bucket, object)
 	}
 
@@ -242,10 +274,63 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 	}
 
+	// Serve aligned ranges prefetched by an earlier read.
+	readAheadKey := getReadAheadKey(bucket, object, modTime, startOffset, length)
+	if data, ok := xl.readAhead.Get(readAheadKey); ok {
+		if _, err = mw.Write(data); err != nil {
+			return traceError(err)
+		}
+		xl.prefetchNextRange(bucket, object, xlMeta, metaArr, onlineDisks, modTime, startOffset, length)
+		return nil
+	}
+
+	if err = xl.readObjectRange(mw, bucket, object, xlMeta, metaArr, onlineDisks, partIndex, partOffset, lastPartIndex, length); err != nil {
+		return err
+	}
+
+	// Prefetch the next range when clients read aligned ranges.
+	xl.prefetchNextRange(bucket, object, xlMeta, metaArr, onlineDisks, modTime, startOffset, length)
+
+	// Return success.
+	return nil
+}
+
+// errTrackingWriter - remembers the last error of the wrapped writer,
+// telling failures of the client apart from failures of the disks.
+type errTrackingWriter struct {
+	io.Writer
+	err error
+}
+
+func (w *errTrackingWriter) Write(p []byte) (n int, err error) {
+	n, err = w.Writer.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// readObjectRange - erasure decodes length bytes of the object starting
+// at partOffset of partIndex, up to lastPartIndex, into writer.
+//
+// When too many disks fail in the middle of a part to reconstruct it,
+// the disks are listed again and the remaining range of the part is
+// read from the ones still online, so the stream continues instead of
+// being aborted. Every block written before the failure was verified
+// against its checksum, the client never receives corrupted data.
+func (xl xlObjects) readObjectRange(writer io.Writer, bucket, object string, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, partIndex int, partOffset int64, lastPartIndex int, length int64) error {
 	var totalBytesRead int64
 
+	// Writer errors are returned to the caller as is.
+	tw := &errTrackingWriter{Writer: writer}
+
 	chunkSize := getChunkSize(xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
-	pool := bpool.NewBytePool(chunkSize, len(onlineDisks))
+	buf, err := globalBufferPool.Get(chunkSize * int64(len(onlineDisks)))
+	if err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+	defer globalBufferPool.Put(buf)
+	pool := bpool.NewBytePoolFromBuffer(chunkSize, buf)
 
 	// Read from all parts.
 	for ; partIndex <= lastPartIndex; partIndex++ {
@@ -262,27 +347,21 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 			readSize = length - totalBytesRead
 		}
 
-		// Get the checksums of the current part.
-		checkSums := make([]string, len(onlineDisks))
-		var ckSumAlgo string
-		for index, disk := range onlineDisks {
-			// Disk is not found skip the checksum.
-			if disk == nil {
-				checkSums[index] = ""
-				continue
-			}
-			ckSumInfo := metaArr[index].Erasure.GetCheckSumInfo(partName)
-			checkSums[index] = ckSumInfo.Hash
-			// Set checksum algo only once, while it is possible to have
-			// different algos per block because of our `xl.json`.
-			// It is not a requirement, set this only once for all the disks.
-			if ckSumAlgo == "" {
-				ckSumAlgo = ckSumInfo.Algorithm
+		// Start erasure decoding and writing to the client.
+		checkSums, ckSumAlgo := getPartCheckSums(onlineDisks, metaArr, partName)
+		n, err := erasureReadFile(tw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		for retry := 0; err != nil && tw.err == nil && errorCause(err) == errXLReadQuorum && retry < xlDegradedReadRetries; retry++ {
+			errorIf(err, "Unable to read %s of the object `%s/%s`, reading the remaining %d bytes again.", partName, bucket, object, readSize-n)
+			var rErr error
+			onlineDisks, metaArr, rErr = xl.readDisksAgain(bucket, object, xlMeta)
+			if rErr != nil {
+				break
 			}
+			checkSums, ckSumAlgo = getPartCheckSums(onlineDisks, metaArr, partName)
+			var m int64
+			m, err = erasureReadFile(tw, onlineDisks, bucket, pathJoin(object, partName), partOffset+n, readSize-n, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+			n += m
 		}
-
-		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
 		if err != nil {
 			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
@@ -300,8 +379,51 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 	return nil
 }
 
+// getPartCheckSums - returns the checksums of partName on each of the
+// online disks along with their algorithm.
+func getPartCheckSums(onlineDisks []StorageAPI, metaArr []xlMetaV1, partName string) (checkSums []string, ckSumAlgo string) {
+	checkSums = make([]string, len(onlineDisks))
+	for index, disk := range onlineDisks {
+		// Disk is not found skip the checksum.
+		if disk == nil {
+			checkSums[index] = ""
+			continue
+		}
+		ckSumInfo := metaArr[index].Erasure.GetCheckSumInfo(partName)
+		checkSums[index] = ckSumInfo.Hash
+		// Set checksum algo only once, while it is possible to have
+		// different algos per block because of our `xl.json`.
+		// It is not a requirement, set this only once for all the disks.
+		if ckSumAlgo == "" {
+			ckSumAlgo = ckSumInfo.Algorithm
+		}
+	}
+	return checkSums, ckSumAlgo
+}
+
+// readDisksAgain - lists the online disks of an object being read after
+// a read failed, ordered by the erasure distribution of xlMeta. Fails if
+// the object was replaced meanwhile.
+func (xl xlObjects) readDisksAgain(bucket, object string, xlMeta xlMetaV1) ([]StorageAPI, []xlMetaV1, error) {
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	checkXLMetaIntegrity(metaArr, errs)
+	if reducedErr := reduceReadQuorumErrs(errs, objectReadIgnoredErrs, xl.readQuorum); reducedErr != nil {
+		return nil, nil, reducedErr
+	}
+	onlineDisks, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+	if !modTime.Equal(xlMeta.Stat.ModTime) {
+		return nil, nil, traceError(errXLReadQuorum)
+	}
+	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
+	metaArr = shufflePartsMetadata(metaArr, xlMeta.Erasure.Distribution)
+	return onlineDisks, metaArr, nil
+}
+
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
 func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	if hasSuffix(object, slashSeparator) {
+		return xl.getObjectDirInfo(bucket, object)
+	}
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -315,13 +437,49 @@ func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 
 // getObjectInfo - wrapper for reading object metadata and constructs ObjectInfo.
 func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, err error) {
+	if globalStrictConsistency {
+		return xl.getObjectInfoQuorum(bucket, object)
+	}
+
 	// returns xl meta map and stat info.
-	xlStat, xlMetaMap, err := xl.readXLMetaStat(bucket, object)
+	xlStat, xlMetaMap, rangeHint, err := xl.readXLMetaStat(bucket, object)
 	if err != nil {
+		// Check whether the object is to be quarantined.
+		if errorCause(err) == errXLMetaCorrupted {
+			return xl.getObjectInfoQuorum(bucket, object)
+		}
 		// Return error.
 		return ObjectInfo{}, err
 	}
+	return newXLObjectInfo(bucket, object, xlStat, xlMetaMap, rangeHint), nil
+}
+
+// getObjectInfoQuorum - constructs ObjectInfo from the latest `xl.json`
+// agreed on by read quorum, instead of the first disk responding which
+// may have missed the latest write. Objects present on less than read
+// quorum disks, e.g. left behind by a delete, are not found.
+func (xl xlObjects) getObjectInfoQuorum(bucket, object string) (ObjectInfo, error) {
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	checkXLMetaIntegrity(metaArr, errs)
+	if xl.shouldQuarantine(errs) {
+		xl.startQuarantine(bucket, object)
+		return ObjectInfo{}, traceError(ObjectNotFound{bucket, object})
+	}
+	if err := reduceReadQuorumErrs(errs, objectReadIgnoredErrs, xl.readQuorum); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	_, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+	xlMeta, err := pickValidXLMeta(metaArr, modTime)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return newXLObjectInfo(bucket, object, xlMeta.Stat, xlMeta.Meta, xlMetaRangeHint(xlMeta)), nil
+}
 
+// newXLObjectInfo - returns the object info of an object from the
+// stat info and metadata of its `xl.json`.
+func newXLObjectInfo(bucket, object string, xlStat statInfo, xlMetaMap map[string]string, rangeHint *RangeHint) (objInfo ObjectInfo) {
 	objInfo = ObjectInfo{
 		IsDir:           false,
 		Bucket:          bucket,
@@ -331,6 +489,7 @@ func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, er
 		MD5Sum:          xlMetaMap["md5Sum"],
 		ContentType:     xlMetaMap["content-type"],
 		ContentEncoding: xlMetaMap["content-encoding"],
+		RangeHint:       rangeHint,
 	}
 
 	// md5Sum has already been extracted into objInfo.MD5Sum.  We
@@ -339,7 +498,7 @@ func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, er
 
 	delete(xlMetaMap, "md5Sum")
 	objInfo.UserDefined = xlMetaMap
-	return objInfo, nil
+	return objInfo
 }
 
 func undoRename(disks []StorageAPI, srcBucket, srcEntry, dstBucket, dstEntry string, isDir bool, errs []error) {
@@ -432,27 +591,76 @@ func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject
 	return rename(disks, srcBucket, srcObject, dstBucket, dstObject, isDir, quorum)
 }
 
+// deleteObjectParts - deletes the given parts of an object from all
+// the disks in parallel, errors are ignored.
+func (xl xlObjects) deleteObjectParts(bucket, object string, partNames []string) {
+	var wg = &sync.WaitGroup{}
+	for _, disk := range xl.storageDisks {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			for _, partName := range partNames {
+				_ = disk.DeleteFile(bucket, pathJoin(object, partName))
+			}
+		}(disk)
+	}
+	wg.Wait()
+}
+
+// purgeStaleParts - deletes all files in the object directory except
+// `xl.json` and the given parts, i.e. the parts of previous versions
+// and of interrupted overwrites, on all disks in parallel. Errors are
+// ignored, leftovers are purged by the next overwrite or heal.
+func purgeStaleParts(disks []StorageAPI, bucket, object string, parts []objectPartInfo) {
+	keep := map[string]bool{xlMetaJSONFile: true}
+	for _, part := range parts {
+		keep[part.Name] = true
+	}
+
+	var wg = &sync.WaitGroup{}
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			entries, err := disk.ListDir(bucket, object)
+			if err != nil {
+				return
+			}
+			for _, entry := range entries {
+				if keep[entry] || hasSuffix(entry, slashSeparator) {
+					continue
+				}
+				_ = disk.DeleteFile(bucket, pathJoin(object, entry))
+			}
+		}(disk)
+	}
+	wg.Wait()
+}
+
 // PutObject - creates an object upon reading from the input stream
 // until EOF, erasure codes the data across all disk and additionally
 // writes `xl.json` which carries the necessary metadata for future
 // object operations.
 func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
-	// This is a special case with size as '0' and object ends with
-	// a slash separator, we treat it like a valid operation and
-	// return success.
+	// Zero byte objects ending with a slash separator are
+	// directory objects.
 	if isObjectDir(object, size) {
-		// Check if an object is present as one of the parent dir.
-		// -- FIXME. (needs a new kind of lock).
-		if xl.parentDirIsObject(bucket, path.Dir(object)) {
-			return ObjectInfo{}, toObjectErr(traceError(errFileAccessDenied), bucket, object)
-		}
-		return dirObjectInfo(bucket, object, size, metadata), nil
+		return xl.putObjectDir(bucket, object, metadata)
 	}
 
 	// Validate put object input args.
 	if err = checkPutObjectArgs(bucket, object, xl); err != nil {
 		return ObjectInfo{}, err
 	}
+	if err = globalStrictNamesPolicy.checkStrictObjectName(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
 
 	// Check if an object is present as one of the parent dir.
 	// -- FIXME. (needs a new kind of lock).
@@ -534,6 +742,26 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// object to delete.
 	defer xl.deleteObject(minioMetaTmpBucket, tempObj)
 
+	// New objects are written to a temporary directory which is
+	// renamed into place, so that parts never show up without
+	// `xl.json`. Overwrites place the parts directly into the object
+	// directory under names unique to this upload and are committed
+	// by replacing `xl.json`, saving the renames of both versions.
+	overwrite := xl.isObject(bucket, object)
+	dataBucket, dataPrefix := minioMetaTmpBucket, tempObj
+	if overwrite {
+		dataBucket, dataPrefix = bucket, object
+	}
+
+	// Parts of an overwrite are deleted again unless committed.
+	var partNames []string
+	committed := false
+	defer func() {
+		if overwrite && !committed {
+			xl.deleteObjectParts(bucket, object, partNames)
+		}
+	}()
+
 	// Total size of the written object
 	var sizeWritten int64
 
@@ -541,8 +769,12 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	for partIdx := 1; ; partIdx++ {
 		// Compute part name
 		partName := "part." + strconv.Itoa(partIdx)
+		if overwrite {
+			partName += "." + uniqueID
+		}
+		partNames = append(partNames, partName)
 		// Compute the path of current part
-		tempErasureObj := pathJoin(uniqueID, partName)
+		tempErasureObj := pathJoin(dataPrefix, partName)
 
 		// Calculate the size of the current part, if size is unknown, curPartSize wil be unknown too.
 		// allowEmptyPart will always be true if this is the first part and false otherwise.
@@ -559,7 +791,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 			actualSize := xl.sizeOnDisk(curPartSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
 			for _, disk := range onlineDisks {
 				if disk != nil {
-					disk.PrepareFile(minioMetaTmpBucket, tempErasureObj, actualSize)
+					disk.PrepareFile(dataBucket, tempErasureObj, actualSize)
 				}
 			}
 		}
@@ -572,9 +804,9 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		allowEmptyPart := partIdx == 1
 
 		// Erasure code data and write across all disks.
-		partSizeWritten, checkSums, erasureErr := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, partReader, allowEmptyPart, partsMetadata[0].Erasure.BlockSize, partsMetadata[0].Erasure.DataBlocks, partsMetadata[0].Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
+		partSizeWritten, checkSums, erasureErr := erasureCreateFile(onlineDisks, dataBucket, tempErasureObj, partReader, allowEmptyPart, partsMetadata[0].Erasure.BlockSize, partsMetadata[0].Erasure.DataBlocks, partsMetadata[0].Erasure.ParityBlocks, bitRotAlgo, xl.getWriteQuorum())
 		if erasureErr != nil {
-			return ObjectInfo{}, toObjectErr(erasureErr, minioMetaTmpBucket, tempErasureObj)
+			return ObjectInfo{}, toObjectErr(erasureErr, dataBucket, tempErasureObj)
 		}
 
 		// Should return IncompleteBody{} error when reader has fewer bytes
@@ -628,8 +860,12 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
-	// Save additional erasureMetadata.
-	modTime := time.Now().UTC()
+	// Save additional erasureMetadata, the modification time is the
+	// current time unless the one of the original object is kept.
+	modTime, ok := popObjectModTime(metadata)
+	if !ok {
+		modTime = time.Now().UTC()
+	}
 
 	newMD5Hex := hex.EncodeToString(md5Writer.Sum(nil))
 	// Update the md5sum if not set with the newly calculated one.
@@ -662,21 +898,36 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
-	if xl.isObject(bucket, object) {
-		// Rename if an object already exists to temporary location.
-		newUniqueID := mustGetUUID()
-
-		// Delete successfully renamed object.
-		defer xl.deleteObject(minioMetaTmpBucket, newUniqueID)
-
-		// NOTE: Do not use online disks slice here.
-		// The reason is that existing object should be purged
-		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(err, bucket, object)
+	// Parts of the previous version are no longer referenced once the
+	// overwrite is committed, purge them from all disks regardless of
+	// their status. Lockless reads may still use them, in which case
+	// they are purged once the reads are done.
+	defer func() {
+		if !overwrite || err != nil {
+			return
 		}
+		if globalNSMutex.deferWhileLocklessReads(bucket, object, func() {
+			// Later overwrites may have been committed meanwhile,
+			// keep the parts of the current version.
+			parts, rerr := xl.readXLMetaParts(bucket, object)
+			if rerr == nil {
+				purgeStaleParts(xl.storageDisks, bucket, object, parts)
+			}
+		}) {
+			return
+		}
+		purgeStaleParts(xl.storageDisks, bucket, object, partsMetadata[0].Parts)
+	}()
+
+	// Keep the previous version if versioning is enabled or suspended
+	// on the bucket.
+	versionDone, err := preserveObjectVersion(xl, bucket, object, metadata)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
+	defer func() {
+		versionDone(err == nil)
+	}()
 
 	// Fill all the necessary metadata.
 	// Update `xl.json` content on each disks.
@@ -687,14 +938,37 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	}
 
 	// Write unique `xl.json` for each disk.
-	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.getWriteQuorum()); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
-	// Rename the successfully written temporary object to final location.
-	err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
-	if err != nil {
-		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	if overwrite {
+		// Move `xl.json` of the previous version aside first.
+		// NOTE: Do not use online disks slice here, the previous
+		// version is moved regardless of `xl.json` status so that it
+		// can be rolled back in case of errors.
+		prevObj := mustGetUUID()
+		defer xl.deleteObject(minioMetaTmpBucket, prevObj)
+		prevDisks := append([]StorageAPI(nil), xl.storageDisks...)
+		err = renamePart(prevDisks, bucket, pathJoin(object, xlMetaJSONFile), minioMetaTmpBucket, pathJoin(prevObj, xlMetaJSONFile), xl.getWriteQuorum())
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+
+		// Put `xl.json` of the new version in place, on errors it is
+		// moved back out and the previous one is restored.
+		err = renamePart(onlineDisks, minioMetaTmpBucket, pathJoin(tempObj, xlMetaJSONFile), bucket, pathJoin(object, xlMetaJSONFile), xl.getWriteQuorum())
+		if err != nil {
+			_ = renamePart(prevDisks, minioMetaTmpBucket, pathJoin(prevObj, xlMetaJSONFile), bucket, pathJoin(object, xlMetaJSONFile), xl.getWriteQuorum())
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		committed = true
+	} else {
+		// Rename the successfully written temporary object to final location.
+		err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.getWriteQuorum())
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
 	}
 
 	// Once we have successfully renamed the object, Close the buffer which would
@@ -751,13 +1025,16 @@ func (xl xlObjects) deleteObject(bucket, object string) error {
 	// Wait for all routines to finish.
 	wg.Wait()
 
-	return reduceWriteQuorumErrs(dErrs, objectOpIgnoredErrs, xl.writeQuorum)
+	return reduceWriteQuorumErrs(dErrs, objectOpIgnoredErrs, xl.getWriteQuorum())
 }
 
 // DeleteObject - deletes an object, this call doesn't necessary reply
 // any error as it is not necessary for the handler to reply back a
 // response to the client request.
 func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
+	if hasSuffix(object, slashSeparator) {
+		return xl.deleteObjectDir(bucket, object)
+	}
 	if err = checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -767,17 +1044,32 @@ func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
 		return traceError(ObjectNotFound{bucket, object})
 	} // else proceed to delete the object.
 
-	// Delete the object on all disks.
-	err = xl.deleteObject(bucket, object)
+	// Keep the current version and a delete marker if versioning is
+	// enabled or suspended on the bucket.
+	versionDone, err := preserveDeletedObject(xl, bucket, object)
 	if err != nil {
 		return toObjectErr(err, bucket, object)
 	}
+	err = xl.deleteCurrentObject(bucket, object)
+	versionDone(err == nil)
+	if err != nil {
+		return err
+	}
+
+	// Success.
+	return nil
+}
+
+// deleteCurrentObject - deletes an object on all disks and from the cache.
+func (xl xlObjects) deleteCurrentObject(bucket, object string) error {
+	// Delete the object on all disks.
+	if err := xl.deleteObject(bucket, object); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
 
 	if xl.objCacheEnabled {
 		// Delete from the cache.
 		xl.objCache.Delete(pathJoin(bucket, object))
 	}
-
-	// Success.
 	return nil
 }
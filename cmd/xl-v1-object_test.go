@@ -18,11 +18,13 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -73,7 +75,7 @@ func TestXLDeleteObjectBasic(t *testing.T) {
 		{".test", "obj", BucketNameInvalid{Bucket: ".test"}},
 		{"----", "obj", BucketNameInvalid{Bucket: "----"}},
 		{"bucket", "", ObjectNameInvalid{Bucket: "bucket", Object: ""}},
-		{"bucket", "obj/", ObjectNameInvalid{Bucket: "bucket", Object: "obj/"}},
+		{"bucket", "obj/", ObjectNotFound{Bucket: "bucket", Object: "obj/"}},
 		{"bucket", "/obj", ObjectNameInvalid{Bucket: "bucket", Object: "/obj"}},
 		{"bucket", "doesnotexist", ObjectNotFound{Bucket: "bucket", Object: "doesnotexist"}},
 		{"bucket", "obj", nil},
@@ -212,6 +214,89 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	removeRoots(fsDirs)
 }
 
+// flakyReadDisk - fails the next ReadFile call when armed.
+type flakyReadDisk struct {
+	StorageAPI
+	mu   sync.Mutex
+	fail bool
+}
+
+func (d *flakyReadDisk) arm() {
+	d.mu.Lock()
+	d.fail = true
+	d.mu.Unlock()
+}
+
+func (d *flakyReadDisk) ReadFile(volume, path string, offset int64, buf []byte) (int64, error) {
+	d.mu.Lock()
+	fail := d.fail
+	d.fail = false
+	d.mu.Unlock()
+	if fail {
+		return 0, errFaultyDisk
+	}
+	return d.StorageAPI.ReadFile(volume, path, offset, buf)
+}
+
+// armingWriter - arms the flaky disks once the first data reached the
+// client.
+type armingWriter struct {
+	io.Writer
+	disks []*flakyReadDisk
+}
+
+func (w *armingWriter) Write(p []byte) (int, error) {
+	for _, disk := range w.disks {
+		disk.arm()
+	}
+	w.disks = nil
+	return w.Writer.Write(p)
+}
+
+// Tests that a GET losing the read quorum in the middle of the object
+// continues with the remaining range once the disks are back.
+func TestGetObjectDegradedReadRetry(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	xl.objCacheEnabled = false
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), blockSizeV1+humanize.MiByte)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// 9 disks out of 16 fail reading the second block, which leaves
+	// less than the data blocks to reconstruct it.
+	var disks []*flakyReadDisk
+	for i := range xl.storageDisks[:9] {
+		disk := &flakyReadDisk{StorageAPI: xl.storageDisks[i]}
+		xl.storageDisks[i] = disk
+		disks = append(disks, disk)
+	}
+	var buf bytes.Buffer
+	if err = xl.GetObject(bucket, object, 0, int64(len(data)), &armingWriter{&buf, disks}); err != nil {
+		t.Fatalf("Expected GET to continue after the disks failed, got %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Unexpected object data read")
+	}
+}
+
 func TestPutObjectNoQuorum(t *testing.T) {
 	// Create an instance of xl backend.
 	obj, fsDirs, err := prepareXL()
@@ -263,6 +348,170 @@ func TestPutObjectNoQuorum(t *testing.T) {
 	removeRoots(fsDirs)
 }
 
+// Tests that overwrites replace the parts of the previous version and
+// purge parts left behind by interrupted overwrites.
+func TestPutObjectOverwrite(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parts of an overwrite which never committed.
+	stale := path.Join(fsDirs[0], bucket, object, "part.1.stale")
+
+	for i, content := range []string{"abcd", "efghij", "klm"} {
+		if i == 2 {
+			if err = ioutil.WriteFile(stale, []byte("stale"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = obj.PutObject(bucket, object, int64(len(content)), bytes.NewReader([]byte(content)), nil, "")
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, 0, int64(len(content)), &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if buf.String() != content {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, content, buf.String())
+		}
+
+		// Every disk holds `xl.json` and the single part of the latest version.
+		for _, fsDir := range fsDirs {
+			entries, rerr := ioutil.ReadDir(path.Join(fsDir, bucket, object))
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("Test %d: Expected 2 entries in %s, got %d", i+1, fsDir, len(entries))
+			}
+		}
+	}
+	if _, err = os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be purged, got %v", stale, err)
+	}
+}
+
+// Tests that overwrites keep the parts of previous versions until
+// lockless reads of the object are done.
+func TestPutObjectOverwriteLocklessRead(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	defer func() { globalLocklessReads = false }()
+	globalLocklessReads = true
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, 4, bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	countEntries := func() int {
+		entries, rerr := ioutil.ReadDir(path.Join(fsDirs[0], bucket, object))
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		return len(entries)
+	}
+
+	readLock := globalNSMutex.NewObjectReadLock(bucket, object)
+	readLock.RLock()
+	for _, content := range []string{"efghij", "klm"} {
+		if _, err = obj.PutObject(bucket, object, int64(len(content)), bytes.NewReader([]byte(content)), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// `xl.json` and the parts of all three versions.
+	if n := countEntries(); n != 4 {
+		t.Fatalf("Expected 4 entries while the read is in progress, got %d", n)
+	}
+	readLock.RUnlock()
+
+	for i := 0; countEntries() != 2; i++ {
+		if i == 50 {
+			t.Fatalf("Expected the stale parts purged once the read is done, got %d entries", countEntries())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, 3, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "klm" {
+		t.Errorf("Expected %q, got %q", "klm", buf.String())
+	}
+}
+
+// commitFailDisk - fails the first rename of a temporary `xl.json`
+// onto the `xl.json` of an object.
+type commitFailDisk struct {
+	StorageAPI
+	failed bool
+}
+
+func (d *commitFailDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) error {
+	if !d.failed && srcVolume == minioMetaTmpBucket && path.Base(dstPath) == xlMetaJSONFile {
+		d.failed = true
+		return errFaultyDisk
+	}
+	return d.StorageAPI.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+}
+
+// Tests that an overwrite which fails to commit keeps the previous
+// version.
+func TestPutObjectOverwriteNoQuorum(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// for a 16 disk setup, quorum is 9, with one disk offline fail
+	// the commit on 7 disks, leaving less than read quorum disks with
+	// the `xl.json` of the previous version unless it is restored.
+	xl := obj.(*xlObjects)
+	for i := range xl.storageDisks[:7] {
+		xl.storageDisks[i] = &commitFailDisk{StorageAPI: xl.storageDisks[i]}
+	}
+	xl.storageDisks[15] = nil
+	_, err = obj.PutObject(bucket, object, int64(len("efghij")), bytes.NewReader([]byte("efghij")), nil, "")
+	if _, ok := errorCause(err).(InsufficientWriteQuorum); !ok {
+		t.Fatalf("Expected InsufficientWriteQuorum, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len("abcd")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "abcd" {
+		t.Errorf("Expected %q, got %q", "abcd", buf.String())
+	}
+}
+
 // Tests both object and bucket healing.
 func TestHealing(t *testing.T) {
 	obj, fsDirs, err := prepareXL()
@@ -0,0 +1,336 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+const (
+	// Objects with corrupted `xl.json` are moved under this prefix of
+	// the meta bucket, one directory per quarantined object.
+	xlQuarantinePrefix = "quarantine"
+
+	// Record of a quarantined object, next to its data directory.
+	xlQuarantineJSONFile = "quarantine.json"
+
+	// Directory of a quarantined object holding its `xl.json` and parts.
+	xlQuarantineDataDir = "data"
+)
+
+// errNoSuchQuarantinedObject - no quarantined object has the given id.
+var errNoSuchQuarantinedObject = errors.New("No quarantined object with this id")
+
+// errQuarantinedObjectExists - an object was created under the name of
+// a quarantined object since, which must be deleted before restoring.
+var errQuarantinedObjectExists = errors.New("An object with the same name exists")
+
+// quarantinedObject - record of an object moved out of its bucket
+// because its `xl.json` is corrupted.
+type quarantinedObject struct {
+	ID     string    `json:"id"`
+	Bucket string    `json:"bucket"`
+	Object string    `json:"object"`
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+}
+
+// byQuarantineTime - sorts quarantined objects oldest first.
+type byQuarantineTime []quarantinedObject
+
+func (t byQuarantineTime) Len() int           { return len(t) }
+func (t byQuarantineTime) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byQuarantineTime) Less(i, j int) bool { return t[i].Time.Before(t[j].Time) }
+
+// checkXLMetaIntegrity - marks the `xl.json` read from a disk as
+// errXLMetaCorrupted if it is internally inconsistent, i.e. its erasure
+// layout does not match the disks, its parts do not add up to the
+// object size or a part has no checksum.
+func checkXLMetaIntegrity(metaArr []xlMetaV1, errs []error) {
+	for index, meta := range metaArr {
+		if errs[index] != nil {
+			continue
+		}
+		if !isXLMetaConsistent(meta, len(metaArr)) {
+			errs[index] = traceError(errXLMetaCorrupted)
+		}
+	}
+}
+
+// isXLMetaConsistent - returns false if meta cannot describe an object
+// erasure coded over diskCount disks.
+func isXLMetaConsistent(meta xlMetaV1, diskCount int) bool {
+	if !meta.IsValid() {
+		return false
+	}
+	erasure := meta.Erasure
+	if erasure.DataBlocks <= 0 || erasure.ParityBlocks < 0 || erasure.BlockSize <= 0 {
+		return false
+	}
+	if len(erasure.Distribution) != diskCount || erasure.DataBlocks+erasure.ParityBlocks != diskCount {
+		return false
+	}
+	if erasure.Index < 1 || erasure.Index > diskCount {
+		return false
+	}
+	// Distribution is a permutation of 1..diskCount.
+	seen := make([]bool, diskCount+1)
+	for _, n := range erasure.Distribution {
+		if n < 1 || n > diskCount || seen[n] {
+			return false
+		}
+		seen[n] = true
+	}
+	var size int64
+	for _, part := range meta.Parts {
+		if part.Size < 0 || erasure.GetCheckSumInfo(part.Name).Hash == "" {
+			return false
+		}
+		size += part.Size
+	}
+	return size == meta.Stat.Size
+}
+
+// countCorruptedXLMeta - returns the number of disks holding a
+// corrupted `xl.json`.
+func countCorruptedXLMeta(errs []error) (corrupted int) {
+	for _, err := range errs {
+		if errorCause(err) == errXLMetaCorrupted {
+			corrupted++
+		}
+	}
+	return corrupted
+}
+
+// shouldQuarantine - returns true if so many disks hold a corrupted
+// `xl.json` that the remaining ones can never make read quorum, even
+// once offline disks are back.
+func (xl xlObjects) shouldQuarantine(errs []error) bool {
+	return countCorruptedXLMeta(errs) > len(xl.storageDisks)-xl.readQuorum
+}
+
+// startQuarantine - quarantines an object in the background, once the
+// locks held by the caller's request are released. Requests are
+// answered as if the object did not exist meanwhile.
+func (xl xlObjects) startQuarantine(bucket, object string) {
+	go func() {
+		if err := xl.quarantineObject(bucket, object); err != nil {
+			errorIf(err, "Unable to quarantine %s/%s.", bucket, object)
+		}
+	}()
+}
+
+// quarantineObject - moves an object with corrupted `xl.json` on most
+// disks out of its bucket, under the quarantine prefix of the meta
+// bucket.
+func (xl xlObjects) quarantineObject(bucket, object string) error {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	// The object may have been overwritten or quarantined meanwhile.
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	checkXLMetaIntegrity(metaArr, errs)
+	if !xl.shouldQuarantine(errs) {
+		return nil
+	}
+
+	record := quarantinedObject{
+		ID:     mustGetUUID(),
+		Bucket: bucket,
+		Object: object,
+		Time:   time.Now().UTC(),
+		Reason: fmt.Sprintf("xl.json is corrupted on %d of %d disks", countCorruptedXLMeta(errs), len(xl.storageDisks)),
+	}
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return traceError(err)
+	}
+
+	quarantinePath := pathJoin(xlQuarantinePrefix, record.ID)
+	var wg sync.WaitGroup
+	mErrs := make([]error, len(xl.storageDisks))
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			mErrs[index] = errDiskNotFound
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			err := disk.RenameFile(bucket, retainSlash(object), minioMetaBucket, retainSlash(pathJoin(quarantinePath, xlQuarantineDataDir)))
+			if err != nil {
+				// Nothing to move from disks without the object.
+				if err != errFileNotFound {
+					mErrs[index] = err
+				}
+				return
+			}
+			mErrs[index] = disk.AppendFile(minioMetaBucket, pathJoin(quarantinePath, xlQuarantineJSONFile), recordBytes)
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if xl.objCacheEnabled {
+		xl.objCache.Delete(pathJoin(bucket, object))
+	}
+	errorIf(traceError(errXLMetaCorrupted), "Quarantined %s/%s as %s, %s.", bucket, object, record.ID, record.Reason)
+	return reduceWriteQuorumErrs(mErrs, objectOpIgnoredErrs, xl.getWriteQuorum())
+}
+
+// readQuarantineRecord - reads the record of a quarantined object from
+// the first disk holding it.
+func (xl xlObjects) readQuarantineRecord(id string) (record quarantinedObject, err error) {
+	if _, err = uuid.Parse(id); err != nil {
+		return quarantinedObject{}, traceError(errNoSuchQuarantinedObject)
+	}
+	recordPath := pathJoin(xlQuarantinePrefix, id, xlQuarantineJSONFile)
+	err = errNoSuchQuarantinedObject
+	for _, disk := range xl.getLoadBalancedDisks() {
+		if disk == nil {
+			continue
+		}
+		buf, rErr := disk.ReadAll(minioMetaBucket, recordPath)
+		if rErr != nil {
+			if rErr != errFileNotFound && rErr != errVolumeNotFound {
+				err = rErr
+			}
+			continue
+		}
+		if rErr = json.Unmarshal(buf, &record); rErr != nil {
+			err = rErr
+			continue
+		}
+		return record, nil
+	}
+	return quarantinedObject{}, traceError(err)
+}
+
+// ListQuarantinedObjects - lists the objects quarantined from bucket,
+// or from all buckets if bucket is empty, oldest first.
+func (xl xlObjects) ListQuarantinedObjects(bucket string) ([]quarantinedObject, error) {
+	ids := make(map[string]bool)
+	for _, disk := range xl.storageDisks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(minioMetaBucket, xlQuarantinePrefix)
+		if err != nil {
+			if err == errFileNotFound || isErrIgnored(err, objMetadataOpIgnoredErrs...) {
+				continue
+			}
+			return nil, traceError(err)
+		}
+		for _, entry := range entries {
+			ids[strings.TrimSuffix(entry, slashSeparator)] = true
+		}
+	}
+
+	records := []quarantinedObject{}
+	for id := range ids {
+		record, err := xl.readQuarantineRecord(id)
+		if err != nil {
+			// Restored meanwhile.
+			if errorCause(err) == errNoSuchQuarantinedObject {
+				continue
+			}
+			return nil, err
+		}
+		if bucket == "" || record.Bucket == bucket {
+			records = append(records, record)
+		}
+	}
+	sort.Sort(byQuarantineTime(records))
+	return records, nil
+}
+
+// RestoreQuarantinedObject - moves a quarantined object back into its
+// bucket, e.g. once its `xl.json` was repaired by hand. Fails if an
+// object with the same name exists.
+func (xl xlObjects) RestoreQuarantinedObject(id string) (quarantinedObject, error) {
+	record, err := xl.readQuarantineRecord(id)
+	if err != nil {
+		return quarantinedObject{}, err
+	}
+
+	objectLock := globalNSMutex.NewNSLock(record.Bucket, record.Object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	if _, err = xl.getBucketInfo(record.Bucket); err != nil {
+		return quarantinedObject{}, toObjectErr(err, record.Bucket)
+	}
+	if xl.isObject(record.Bucket, record.Object) {
+		return quarantinedObject{}, traceError(errQuarantinedObjectExists)
+	}
+
+	quarantinePath := pathJoin(xlQuarantinePrefix, id)
+	var wg sync.WaitGroup
+	rErrs := make([]error, len(xl.storageDisks))
+	for index, disk := range xl.storageDisks {
+		if disk == nil {
+			rErrs[index] = errDiskNotFound
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			err := disk.RenameFile(minioMetaBucket, retainSlash(pathJoin(quarantinePath, xlQuarantineDataDir)), record.Bucket, retainSlash(record.Object))
+			if err != nil && err != errFileNotFound {
+				rErrs[index] = err
+				return
+			}
+			if err = disk.DeleteFile(minioMetaBucket, pathJoin(quarantinePath, xlQuarantineJSONFile)); err != nil && err != errFileNotFound {
+				rErrs[index] = err
+			}
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if err = reduceWriteQuorumErrs(rErrs, objectOpIgnoredErrs, xl.getWriteQuorum()); err != nil {
+		return quarantinedObject{}, toObjectErr(err, record.Bucket, record.Object)
+	}
+	return record, nil
+}
+
+// listQuarantinedObjects - lists the quarantined objects of objAPI,
+// only the XL backend quarantines objects.
+func listQuarantinedObjects(objAPI ObjectLayer, bucket string) ([]quarantinedObject, error) {
+	xl, ok := unwrapObjectLayer(objAPI).(*xlObjects)
+	if !ok {
+		return nil, traceError(NotImplemented{})
+	}
+	return xl.ListQuarantinedObjects(bucket)
+}
+
+// restoreQuarantinedObject - restores the quarantined object id of
+// objAPI.
+func restoreQuarantinedObject(objAPI ObjectLayer, id string) (quarantinedObject, error) {
+	xl, ok := unwrapObjectLayer(objAPI).(*xlObjects)
+	if !ok {
+		return quarantinedObject{}, traceError(NotImplemented{})
+	}
+	return xl.RestoreQuarantinedObject(id)
+}
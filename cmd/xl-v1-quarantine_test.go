@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+// Tests the consistency checks of `xl.json`.
+func TestIsXLMetaConsistent(t *testing.T) {
+	newMeta := func() xlMetaV1 {
+		meta := newXLMetaV1("object", 2, 2)
+		meta.Erasure.Index = 1
+		meta.Stat.Size = 10
+		meta.AddObjectPart(1, "part.1", "etag", 10)
+		meta.Erasure.AddCheckSumInfo(checkSumInfo{Name: "part.1", Algorithm: bitRotAlgo, Hash: "hash"})
+		return meta
+	}
+
+	testCases := []struct {
+		modify     func(*xlMetaV1)
+		consistent bool
+	}{
+		{func(meta *xlMetaV1) {}, true},
+		{func(meta *xlMetaV1) { meta.Format = "fs" }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.DataBlocks = 0 }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.BlockSize = 0 }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.Index = 5 }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.Distribution = []int{1, 2, 3} }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.Distribution = []int{1, 2, 2, 4} }, false},
+		{func(meta *xlMetaV1) { meta.Stat.Size = 11 }, false},
+		{func(meta *xlMetaV1) { meta.Erasure.Checksum = nil }, false},
+	}
+	for i, testCase := range testCases {
+		meta := newMeta()
+		testCase.modify(&meta)
+		if consistent := isXLMetaConsistent(meta, 4); consistent != testCase.consistent {
+			t.Errorf("Test %d: Expected consistent %v, got %v", i+1, testCase.consistent, consistent)
+		}
+	}
+}
+
+// Tests quarantine and restore of an object with corrupted `xl.json`.
+func TestXLQuarantine(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	bucket := "bucket"
+	object := "dir/object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corruption within the tolerance of the disks is left to healing.
+	for _, fsDir := range fsDirs[:len(fsDirs)-xl.readQuorum] {
+		if err = ioutil.WriteFile(path.Join(fsDir, bucket, object, xlMetaJSONFile), []byte("{garbage"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); err != nil {
+		t.Fatalf("Expected object to remain readable, got %v", err)
+	}
+
+	// One more corrupted `xl.json`, the object can no longer be read.
+	corruptPath := path.Join(fsDirs[len(fsDirs)-xl.readQuorum], bucket, object, xlMetaJSONFile)
+	if err = ioutil.WriteFile(corruptPath, []byte(`{"version":"1.0.0","format":"xl"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.GetObject(bucket, object, 0, int64(len(data)), &buf); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+	if err = xl.quarantineObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if xl.isObject(bucket, object) {
+		t.Fatal("Expected object to be moved out of its bucket")
+	}
+
+	records, err := xl.ListQuarantinedObjects(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Bucket != bucket || records[0].Object != object {
+		t.Fatalf("Unexpected quarantined objects %v", records)
+	}
+	id := records[0].ID
+	if records, err = xl.ListQuarantinedObjects("other"); err != nil || len(records) != 0 {
+		t.Fatalf("Expected no quarantined objects of other bucket, got %v, %v", records, err)
+	}
+
+	// Ids are validated.
+	if _, err = xl.RestoreQuarantinedObject("../../bucket"); errorCause(err) != errNoSuchQuarantinedObject {
+		t.Fatalf("Expected errNoSuchQuarantinedObject, got %v", err)
+	}
+
+	// Restoring over a new object fails.
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = xl.RestoreQuarantinedObject(id); errorCause(err) != errQuarantinedObjectExists {
+		t.Fatalf("Expected errQuarantinedObjectExists, got %v", err)
+	}
+	if err = obj.DeleteObject(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := xl.RestoreQuarantinedObject(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != id {
+		t.Fatalf("Expected record %s, got %v", id, record)
+	}
+	if _, err = ioutil.ReadFile(corruptPath); err != nil {
+		t.Fatalf("Expected xl.json to be restored, got %v", err)
+	}
+	if records, err = xl.ListQuarantinedObjects(""); err != nil || len(records) != 0 {
+		t.Fatalf("Expected no quarantined objects, got %v, %v", records, err)
+	}
+}
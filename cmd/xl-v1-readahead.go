@@ -0,0 +1,189 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Ranges larger than this are not prefetched.
+	readAheadMaxRangeSize = 32 * humanize.MiByte
+	// Prefetched ranges not read within this period are dropped.
+	readAheadExpiry = time.Minute
+)
+
+// readAheadEntry - a prefetched range, data is nil while in flight.
+type readAheadEntry struct {
+	data   []byte
+	size   int64
+	expiry time.Time
+}
+
+// readAheadCache holds ranges prefetched after aligned range reads,
+// each entry is served at most once.
+type readAheadCache struct {
+	mu      sync.Mutex
+	entries map[string]*readAheadEntry
+	size    int64
+	maxSize int64
+}
+
+// newReadAheadCache - returns a new read ahead cache of maxSize bytes,
+// nil if read ahead is disabled with a maxSize of 0.
+func newReadAheadCache(maxSize int64) *readAheadCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &readAheadCache{
+		entries: make(map[string]*readAheadEntry),
+		maxSize: maxSize,
+	}
+}
+
+// getReadAheadKey - returns the cache key of a range of an object
+// version, identified by its modification time.
+func getReadAheadKey(bucket, object string, modTime time.Time, offset, length int64) string {
+	return fmt.Sprintf("%s@%d:%d-%d", path.Join(bucket, object), modTime.UnixNano(), offset, length)
+}
+
+// evictExpired - removes expired entries, caller must hold the lock.
+func (c *readAheadCache) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiry) {
+			c.size -= entry.size
+			delete(c.entries, key)
+		}
+	}
+}
+
+// reserve - reserves room for a range which is about to be prefetched,
+// returns false if it is already cached or the cache is full.
+func (c *readAheadCache) reserve(key string, size int64) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	c.evictExpired(now)
+	if _, ok := c.entries[key]; ok {
+		return false
+	}
+	if c.size+size > c.maxSize {
+		return false
+	}
+	c.entries[key] = &readAheadEntry{size: size, expiry: now.Add(readAheadExpiry)}
+	c.size += size
+	return true
+}
+
+// fill - stores prefetched data of a reserved range.
+func (c *readAheadCache) fill(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		entry.data = data
+	}
+}
+
+// release - drops a reserved range, e.g. when prefetching failed.
+func (c *readAheadCache) release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		c.size -= entry.size
+		delete(c.entries, key)
+	}
+}
+
+// Get - returns and removes a prefetched range.
+func (c *readAheadCache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.data == nil || time.Now().UTC().After(entry.expiry) {
+		return nil, false
+	}
+	c.size -= entry.size
+	delete(c.entries, key)
+	return entry.data, true
+}
+
+// prefetchNextRange - when a client reads a range aligned to the
+// range hint, it is likely to read the following range of the same
+// length next, prefetch it in the background.
+func (xl xlObjects) prefetchNextRange(bucket, object string, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, modTime time.Time, startOffset, length int64) {
+	if xl.readAhead == nil || length <= 0 {
+		return
+	}
+	nextOffset := startOffset + length
+	if nextOffset >= xlMeta.Stat.Size {
+		return
+	}
+	hint := RangeHint{BlockSize: xlMeta.Erasure.BlockSize}
+	for _, part := range xlMeta.Parts {
+		hint.PartSizes = append(hint.PartSizes, part.Size)
+	}
+	if hint.BlockSize <= 0 || !hint.IsAligned(startOffset) || !hint.IsAligned(nextOffset) {
+		return
+	}
+	nextLength := length
+	if nextOffset+nextLength > xlMeta.Stat.Size {
+		nextLength = xlMeta.Stat.Size - nextOffset
+	}
+	if nextLength > readAheadMaxRangeSize {
+		return
+	}
+	partIndex, partOffset, err := xlMeta.ObjectToPartOffset(nextOffset)
+	if err != nil {
+		return
+	}
+	lastPartIndex, _, err := xlMeta.ObjectToPartOffset(nextOffset + nextLength - 1)
+	if err != nil {
+		return
+	}
+
+	key := getReadAheadKey(bucket, object, modTime, nextOffset, nextLength)
+	if !xl.readAhead.reserve(key, nextLength) {
+		return
+	}
+	// Disks failing during the read are dropped from the slice, use a copy.
+	disks := append([]StorageAPI(nil), onlineDisks...)
+	go func() {
+		objectLock := globalNSMutex.NewObjectReadLock(bucket, object)
+		objectLock.RLock()
+		defer objectLock.RUnlock()
+
+		buf := bytes.NewBuffer(make([]byte, 0, nextLength))
+		if err := xl.readObjectRange(buf, bucket, object, xlMeta, metaArr, disks, partIndex, partOffset, lastPartIndex, nextLength); err != nil {
+			xl.readAhead.release(key)
+			return
+		}
+		xl.readAhead.fill(key, buf.Bytes())
+	}()
+}
@@ -0,0 +1,163 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests aligned range boundaries of a range hint.
+func TestRangeHintIsAligned(t *testing.T) {
+	hint := RangeHint{BlockSize: 10, PartSizes: []int64{25, 5}}
+	testCases := []struct {
+		offset  int64
+		aligned bool
+	}{
+		{0, true},
+		{10, true},
+		{15, false},
+		{20, true},
+		{25, true},
+		{27, false},
+		{30, true},
+		{35, false},
+	}
+	for i, testCase := range testCases {
+		if aligned := hint.IsAligned(testCase.offset); aligned != testCase.aligned {
+			t.Errorf("Test %d: expected aligned %v for offset %d, got %v", i+1, testCase.aligned, testCase.offset, aligned)
+		}
+	}
+}
+
+// Tests reserving, filling and reading prefetched ranges.
+func TestReadAheadCache(t *testing.T) {
+	cache := newReadAheadCache(10)
+	if !cache.reserve("a", 6) {
+		t.Fatal("Expected reserve to succeed")
+	}
+	// Same range is not prefetched twice.
+	if cache.reserve("a", 6) {
+		t.Fatal("Expected reserve of a reserved range to fail")
+	}
+	// Cache is full.
+	if cache.reserve("b", 6) {
+		t.Fatal("Expected reserve beyond cache size to fail")
+	}
+	// In flight ranges are not served.
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected in flight range not to be served")
+	}
+	cache.fill("a", []byte("abcdef"))
+	data, ok := cache.Get("a")
+	if !ok || string(data) != "abcdef" {
+		t.Fatalf("Expected prefetched range, got %q", data)
+	}
+	// Ranges are served once.
+	if _, ok = cache.Get("a"); ok {
+		t.Fatal("Expected range to be served once")
+	}
+	if !cache.reserve("b", 6) {
+		t.Fatal("Expected reserve to succeed")
+	}
+	cache.release("b")
+	if cache.size != 0 {
+		t.Fatalf("Expected empty cache, got size %d", cache.size)
+	}
+
+	// Disabled read ahead.
+	var nilCache *readAheadCache
+	if nilCache.reserve("a", 1) {
+		t.Fatal("Expected reserve on disabled read ahead to fail")
+	}
+	if _, ok = nilCache.Get("a"); ok {
+		t.Fatal("Expected get on disabled read ahead to fail")
+	}
+}
+
+// Tests that an aligned range read prefetches the next range.
+func TestXLReadAhead(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	// Read ahead is opt-in.
+	defer func(size int64) { globalReadAheadCacheSize = size }(globalReadAheadCacheSize)
+	globalReadAheadCacheSize = 128 * humanize.MiByte
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	// Disable caching to avoid returning early.
+	xl.objCacheEnabled = false
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), int(blockSizeV1+humanize.KiByte))
+	copy(data[blockSizeV1:], bytes.Repeat([]byte("b"), humanize.KiByte))
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	objInfo, err := obj.GetObjectInfo(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.RangeHint == nil || objInfo.RangeHint.BlockSize != blockSizeV1 || len(objInfo.RangeHint.PartSizes) != 1 {
+		t.Fatalf("Unexpected range hint %#v", objInfo.RangeHint)
+	}
+
+	// Read the first block, the rest of the object is prefetched.
+	var buf bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, blockSizeV1, &buf); err != nil {
+		t.Fatal(err)
+	}
+	key := getReadAheadKey(bucket, object, objInfo.ModTime, blockSizeV1, humanize.KiByte)
+	prefetched := func() bool {
+		xl.readAhead.mu.Lock()
+		defer xl.readAhead.mu.Unlock()
+		entry, ok := xl.readAhead.entries[key]
+		return ok && entry.data != nil
+	}
+	for i := 0; i < 100 && !prefetched(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !prefetched() {
+		t.Fatal("Expected the next range to be prefetched")
+	}
+
+	buf.Reset()
+	if err = obj.GetObject(bucket, object, blockSizeV1, humanize.KiByte, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data[blockSizeV1:]) {
+		t.Fatal("Prefetched range is different from the object")
+	}
+	if prefetched() {
+		t.Fatal("Expected the prefetched range to be consumed")
+	}
+}
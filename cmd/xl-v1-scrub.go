@@ -0,0 +1,307 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Every object is deep scrubbed once within this period.
+	deepScrubInterval = 30 * 24 * time.Hour
+
+	// Pause between two passes over all objects.
+	deepScrubCycleWait = 24 * time.Hour
+
+	// Default rate at which deep scrubbing reads data, per server.
+	defaultDeepScrubRate = 16 * humanize.MiByte
+
+	// Last deep scrub of an object, kept next to `xl.json` on each
+	// disk. Overwrites purge it along with the previous parts.
+	xlScrubJSONFile = "scrub.json"
+)
+
+// errBitrotDetected - part data does not match its erasure checksum.
+var errBitrotDetected = errors.New("Part data does not match its checksum")
+
+// xlScrubRecord - last deep scrub of an object on a disk.
+type xlScrubRecord struct {
+	// Modification time of the scrubbed version of the object.
+	ModTime time.Time `json:"modTime"`
+	// Time the object was scrubbed at.
+	Time time.Time `json:"time"`
+}
+
+// ScrubInfo - deep scrub progress and coverage of a server. Objects
+// are counted once per local disk holding them.
+type ScrubInfo struct {
+	// Bytes read per second.
+	Rate int64 `json:"rate"`
+	// Start of the ongoing pass, end of the previous one.
+	CycleStart   time.Time `json:"cycleStart"`
+	LastCycleEnd time.Time `json:"lastCycleEnd,omitempty"`
+	// Objects visited by the ongoing pass, and how many of them
+	// were scrubbed within the deep scrub interval.
+	ObjectsChecked int64 `json:"objectsChecked"`
+	ObjectsCovered int64 `json:"objectsCovered"`
+	// Share of objects scrubbed within the deep scrub interval at
+	// the end of the previous pass, from 0 to 1.
+	LastCoverage float64 `json:"lastCoverage"`
+	// Totals since the server started.
+	BytesScrubbed  int64 `json:"bytesScrubbed"`
+	CorruptedParts int64 `json:"corruptedParts"`
+}
+
+// xlScrubber - reads every part of every object on the local disks
+// and verifies it against its erasure checksum.
+type xlScrubber struct {
+	xl    *xlObjects
+	disks []StorageAPI
+	rate  int64
+
+	mu   sync.Mutex
+	info ScrubInfo
+}
+
+// Deep scrubber of this server, nil unless enabled.
+var globalXLScrubber *xlScrubber
+
+// isLocalDisk - returns true if disk is attached to this server.
+func isLocalDisk(disk StorageAPI) bool {
+	switch d := disk.(type) {
+	case *posix:
+		return true
+	case *retryStorage:
+		return isLocalDisk(d.remoteStorage)
+	}
+	return false
+}
+
+func newXLScrubber(xl *xlObjects, rate int64) *xlScrubber {
+	var disks []StorageAPI
+	for _, disk := range xl.storageDisks {
+		if disk != nil && isLocalDisk(disk) {
+			disks = append(disks, disk)
+		}
+	}
+	return &xlScrubber{
+		xl:    xl,
+		disks: disks,
+		rate:  rate,
+		info:  ScrubInfo{Rate: rate},
+	}
+}
+
+// startXLScrubber - starts deep scrubbing of the local disks of xl,
+// one pass over all objects a day until the server stops.
+func startXLScrubber(xl *xlObjects, rate int64) {
+	globalXLScrubber = newXLScrubber(xl, rate)
+	go func() {
+		for {
+			globalXLScrubber.scrubAll()
+			select {
+			case <-time.After(deepScrubCycleWait):
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
+
+// Info - returns a snapshot of the scrub progress.
+func (s *xlScrubber) Info() ScrubInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info
+}
+
+// scrubAll - one pass over all objects, scrubbing those not scrubbed
+// within the deep scrub interval.
+func (s *xlScrubber) scrubAll() {
+	s.mu.Lock()
+	s.info.CycleStart = time.Now().UTC()
+	s.info.ObjectsChecked = 0
+	s.info.ObjectsCovered = 0
+	s.mu.Unlock()
+
+	buckets, err := s.xl.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets for deep scrub.")
+		return
+	}
+	for _, bucket := range buckets {
+		marker := ""
+		for {
+			lo, err := s.xl.ListObjects(bucket.Name, "", marker, "", 1000)
+			if err != nil {
+				errorIf(err, "Unable to list objects of %s for deep scrub.", bucket.Name)
+				break
+			}
+			for _, obj := range lo.Objects {
+				for _, disk := range s.disks {
+					s.scrubObject(disk, bucket.Name, obj.Name)
+				}
+			}
+			if !lo.IsTruncated {
+				break
+			}
+			marker = lo.NextMarker
+		}
+	}
+
+	s.mu.Lock()
+	s.info.LastCycleEnd = time.Now().UTC()
+	s.info.LastCoverage = 1
+	if s.info.ObjectsChecked > 0 {
+		s.info.LastCoverage = float64(s.info.ObjectsCovered) / float64(s.info.ObjectsChecked)
+	}
+	s.mu.Unlock()
+}
+
+// scrubObject - verifies all parts of an object on disk unless it
+// was scrubbed within the deep scrub interval. Objects are not locked,
+// overwrites while scrubbing are told apart from corruption by the
+// modification time in `xl.json`.
+func (s *xlScrubber) scrubObject(disk StorageAPI, bucket, object string) {
+	xlMeta, err := readXLMeta(disk, bucket, object)
+	if err != nil {
+		// Missing on this disk, up to healing.
+		return
+	}
+
+	covered := false
+	defer func() {
+		s.mu.Lock()
+		s.info.ObjectsChecked++
+		if covered {
+			s.info.ObjectsCovered++
+		}
+		s.mu.Unlock()
+	}()
+
+	now := time.Now().UTC()
+	if record, err := readScrubRecord(disk, bucket, object); err == nil &&
+		record.ModTime.Equal(xlMeta.Stat.ModTime) && now.Sub(record.Time) < deepScrubInterval {
+		covered = true
+		return
+	}
+
+	for _, part := range xlMeta.Parts {
+		err = s.verifyPart(disk, bucket, pathJoin(object, part.Name), xlMeta.Erasure.GetCheckSumInfo(part.Name))
+		if err == nil {
+			continue
+		}
+		latestMeta, mErr := readXLMeta(disk, bucket, object)
+		if mErr != nil || !latestMeta.Stat.ModTime.Equal(xlMeta.Stat.ModTime) {
+			// Overwritten or deleted meanwhile.
+			return
+		}
+		if errorCause(err) == errBitrotDetected {
+			s.mu.Lock()
+			s.info.CorruptedParts++
+			s.mu.Unlock()
+			raiseAlert(alertBitrot, pathJoin(bucket, object), "Part %s of %s/%s on %s does not match its checksum", part.Name, bucket, object, disk)
+		}
+		errorIf(err, "Unable to deep scrub %s of %s/%s on %s.", part.Name, bucket, object, disk)
+		return
+	}
+
+	record := xlScrubRecord{ModTime: xlMeta.Stat.ModTime, Time: now}
+	if err = writeScrubRecord(disk, bucket, object, record); err != nil {
+		errorIf(err, "Unable to record deep scrub of %s/%s on %s.", bucket, object, disk)
+		return
+	}
+	covered = true
+}
+
+// verifyPart - reads a part at the scrub rate and verifies it
+// against its checksum.
+func (s *xlScrubber) verifyPart(disk StorageAPI, volume, path string, ckSum checkSumInfo) error {
+	if ckSum.Hash == "" {
+		return traceError(errBitrotDetected)
+	}
+	buf, err := globalBufferPool.Get(readSizeV1)
+	if err != nil {
+		return traceError(err)
+	}
+	defer globalBufferPool.Put(buf)
+
+	hashWriter := newHash(ckSum.Algorithm)
+	if err = copyBuffer(&scrubWriter{s: s, w: hashWriter}, disk, volume, path, buf); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hashWriter.Sum(nil)) != ckSum.Hash {
+		return traceError(errBitrotDetected)
+	}
+	return nil
+}
+
+// scrubWriter - counts scrubbed bytes and throttles writes to the
+// scrub rate.
+type scrubWriter struct {
+	s *xlScrubber
+	w io.Writer
+}
+
+func (sw *scrubWriter) Write(p []byte) (int, error) {
+	n, err := sw.w.Write(p)
+	sw.s.mu.Lock()
+	sw.s.info.BytesScrubbed += int64(n)
+	sw.s.mu.Unlock()
+	time.Sleep(time.Duration(int64(n) * int64(time.Second) / sw.s.rate))
+	return n, err
+}
+
+// readScrubRecord - reads the last deep scrub of an object on disk.
+func readScrubRecord(disk StorageAPI, bucket, object string) (record xlScrubRecord, err error) {
+	buf, err := disk.ReadAll(bucket, pathJoin(object, xlScrubJSONFile))
+	if err != nil {
+		return record, traceError(err)
+	}
+	err = json.Unmarshal(buf, &record)
+	return record, err
+}
+
+// writeScrubRecord - replaces the last deep scrub of an object on
+// disk. Records left behind by objects deleted meanwhile are removed
+// again, so that they do not show up as directories.
+func writeScrubRecord(disk StorageAPI, bucket, object string, record xlScrubRecord) error {
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	tmpPath := mustGetUUID()
+	if err = disk.AppendFile(minioMetaTmpBucket, tmpPath, buf); err != nil {
+		return traceError(err)
+	}
+	recordPath := pathJoin(object, xlScrubJSONFile)
+	if err = disk.RenameFile(minioMetaTmpBucket, tmpPath, bucket, recordPath); err != nil {
+		_ = disk.DeleteFile(minioMetaTmpBucket, tmpPath)
+		return traceError(err)
+	}
+	if _, err = disk.StatFile(bucket, pathJoin(object, xlMetaJSONFile)); err != nil {
+		_ = disk.DeleteFile(bucket, recordPath)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests that deep scrubbing verifies all local copies of an object,
+// skips recently scrubbed ones and detects corrupted parts.
+func TestXLScrubber(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1*humanize.MiByte)
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newXLScrubber(obj.(*xlObjects), humanize.GiByte)
+	if len(s.disks) != len(fsDirs) {
+		t.Fatalf("Expected %d local disks, got %d", len(fsDirs), len(s.disks))
+	}
+
+	// First pass scrubs every copy.
+	s.scrubAll()
+	info := s.Info()
+	if info.ObjectsChecked != int64(len(fsDirs)) || info.ObjectsCovered != info.ObjectsChecked || info.LastCoverage != 1 {
+		t.Fatalf("Expected all copies to be scrubbed, got %#v", info)
+	}
+	scrubbed := info.BytesScrubbed
+	if scrubbed == 0 {
+		t.Fatal("Expected data to be read")
+	}
+	for _, fsDir := range fsDirs {
+		if _, err = os.Stat(path.Join(fsDir, bucket, object, xlScrubJSONFile)); err != nil {
+			t.Fatalf("Expected scrub record on %s, got %v", fsDir, err)
+		}
+	}
+
+	// Recently scrubbed copies are not read again.
+	s.scrubAll()
+	if info = s.Info(); info.BytesScrubbed != scrubbed || info.ObjectsCovered != int64(len(fsDirs)) {
+		t.Fatalf("Expected scrubbed copies to be skipped, got %#v", info)
+	}
+
+	// Corrupt the part on one disk, and expire its scrub record.
+	partPath := path.Join(fsDirs[0], bucket, object, "part.1")
+	part, err := ioutil.ReadFile(partPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part[0] ^= 0xff
+	if err = ioutil.WriteFile(partPath, part, 0644); err != nil {
+		t.Fatal(err)
+	}
+	xlMeta, err := readXLMeta(s.xl.storageDisks[0], bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := xlScrubRecord{ModTime: xlMeta.Stat.ModTime, Time: time.Now().UTC().Add(-deepScrubInterval)}
+	for _, disk := range s.disks {
+		if err = writeScrubRecord(disk, bucket, object, expired); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.scrubAll()
+	info = s.Info()
+	if info.CorruptedParts != 1 {
+		t.Fatalf("Expected 1 corrupted part, got %d", info.CorruptedParts)
+	}
+	if info.ObjectsCovered != int64(len(fsDirs)-1) {
+		t.Fatalf("Expected all but the corrupted copy to be covered, got %#v", info)
+	}
+
+	// Overwrites purge the scrub records of the previous version.
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, fsDir := range fsDirs {
+		if _, err = os.Stat(path.Join(fsDir, bucket, object, xlScrubJSONFile)); !os.IsNotExist(err) {
+			t.Fatalf("Expected scrub record on %s to be purged, got %v", fsDir, err)
+		}
+	}
+}
+
+// Tests that scrub records of deleted objects are not left behind.
+func TestWriteScrubRecordDeletedObject(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	disk := obj.(*xlObjects).storageDisks[0]
+	if err = writeScrubRecord(disk, "bucket", "dir/object", xlScrubRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = disk.StatFile("bucket", pathJoin("dir/object", xlScrubJSONFile)); errorCause(err) != errFileNotFound {
+		t.Fatalf("Expected scrub record to be removed, got %v", err)
+	}
+	if entries, _ := disk.ListDir("bucket", ""); len(entries) != 0 {
+		t.Fatalf("Expected no entries left, got %v", entries)
+	}
+}
@@ -67,6 +67,7 @@ func reduceQuorumErrs(errs []error, ignoredErrs []error, quorum int, quorumErr e
 		return traceError(maxErr, errs...)
 	}
 	// No quorum satisfied.
+	raiseAlert(alertQuorumLost, "", "%s", quorumErr)
 	maxErr = traceError(quorumErr, errs...)
 	return
 }
@@ -189,6 +190,22 @@ func parseXLParts(xlMetaBuf []byte) []objectPartInfo {
 	return partInfo
 }
 
+func parseXLRangeHint(xlMetaBuf []byte) *RangeHint {
+	blockSize := gjson.GetBytes(xlMetaBuf, "erasure.blockSize").Int()
+	if blockSize <= 0 {
+		return nil
+	}
+	partsResult := gjson.GetBytes(xlMetaBuf, "parts.#.size").Array()
+	partSizes := make([]int64, len(partsResult))
+	for i, size := range partsResult {
+		partSizes[i] = size.Int()
+	}
+	return &RangeHint{
+		BlockSize: blockSize,
+		PartSizes: partSizes,
+	}
+}
+
 func parseXLMetaMap(xlMetaBuf []byte) map[string]string {
 	// Get xlMetaV1.Meta map.
 	metaMapResult := gjson.GetBytes(xlMetaBuf, "meta").Map()
@@ -233,6 +250,13 @@ func readXLMetaParts(disk StorageAPI, bucket string, object string) ([]objectPar
 	if err != nil {
 		return nil, traceError(err)
 	}
+	if isXLMetaV2(xlMetaBuf) {
+		xlMeta, err := xlMetaV2Unmarshal(xlMetaBuf)
+		if err != nil {
+			return nil, traceError(errXLMetaCorrupted)
+		}
+		return xlMeta.Parts, nil
+	}
 	// obtain xlMetaV1{}.Partsusing `github.com/tidwall/gjson`.
 	xlMetaParts := parseXLParts(xlMetaBuf)
 
@@ -240,11 +264,24 @@ func readXLMetaParts(disk StorageAPI, bucket string, object string) ([]objectPar
 }
 
 // read xl.json from the given disk and parse xlV1Meta.Stat and xlV1Meta.Meta using gjson.
-func readXLMetaStat(disk StorageAPI, bucket string, object string) (statInfo, map[string]string, error) {
+func readXLMetaStat(disk StorageAPI, bucket string, object string) (statInfo, map[string]string, *RangeHint, error) {
 	// Reads entire `xl.json`.
 	xlMetaBuf, err := disk.ReadAll(bucket, path.Join(object, xlMetaJSONFile))
 	if err != nil {
-		return statInfo{}, nil, traceError(err)
+		return statInfo{}, nil, nil, traceError(err)
+	}
+	return parseXLMetaStat(xlMetaBuf)
+}
+
+// parseXLMetaStat - parses xlV1Meta.Stat and xlV1Meta.Meta of `xl.json`
+// in either format.
+func parseXLMetaStat(xlMetaBuf []byte) (statInfo, map[string]string, *RangeHint, error) {
+	if isXLMetaV2(xlMetaBuf) {
+		xlMeta, err := xlMetaV2Unmarshal(xlMetaBuf)
+		if err != nil {
+			return statInfo{}, nil, nil, traceError(errXLMetaCorrupted)
+		}
+		return xlMeta.Stat, xlMeta.Meta, xlMetaRangeHint(xlMeta), nil
 	}
 	// obtain xlMetaV1{}.Meta using `github.com/tidwall/gjson`.
 	xlMetaMap := parseXLMetaMap(xlMetaBuf)
@@ -252,10 +289,14 @@ func readXLMetaStat(disk StorageAPI, bucket string, object string) (statInfo, ma
 	// obtain xlMetaV1{}.Stat using `github.com/tidwall/gjson`.
 	xlStat, err := parseXLStat(xlMetaBuf)
 	if err != nil {
-		return statInfo{}, nil, traceError(err)
+		return statInfo{}, nil, nil, traceError(errXLMetaCorrupted)
 	}
+
+	// obtain range hint from xlMetaV1{}.Erasure and xlMetaV1{}.Parts.
+	rangeHint := parseXLRangeHint(xlMetaBuf)
+
 	// Return structured `xl.json`.
-	return xlStat, xlMetaMap, nil
+	return xlStat, xlMetaMap, rangeHint, nil
 }
 
 // readXLMeta reads `xl.json` and returns back XL metadata structure.
@@ -265,10 +306,10 @@ func readXLMeta(disk StorageAPI, bucket string, object string) (xlMeta xlMetaV1,
 	if err != nil {
 		return xlMetaV1{}, traceError(err)
 	}
-	// obtain xlMetaV1{} using `github.com/tidwall/gjson`.
-	xlMeta, err = xlMetaV1UnmarshalJSON(xlMetaBuf)
+	// obtain xlMetaV1{} from either format.
+	xlMeta, err = xlMetaUnmarshal(xlMetaBuf)
 	if err != nil {
-		return xlMetaV1{}, traceError(err)
+		return xlMetaV1{}, traceError(errXLMetaCorrupted)
 	}
 	// Return structured `xl.json`.
 	return xlMeta, nil
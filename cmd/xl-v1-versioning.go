@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "io"
+
+// GetBucketVersioning - returns the versioning status of a bucket.
+func (xl xlObjects) GetBucketVersioning(bucket string) (string, error) {
+	return getBucketVersioning(xl, bucket)
+}
+
+// SetBucketVersioning - enables or suspends versioning on a bucket.
+func (xl xlObjects) SetBucketVersioning(bucket, status string) error {
+	return setBucketVersioning(xl, bucket, status)
+}
+
+// GetObjectVersion - reads a version of an object.
+func (xl xlObjects) GetObjectVersion(bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	return getObjectVersion(xl, bucket, object, versionID, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - returns info of a version of an object.
+func (xl xlObjects) GetObjectVersionInfo(bucket, object, versionID string) (ObjectInfo, error) {
+	return getObjectVersionInfo(xl, bucket, object, versionID)
+}
+
+// DeleteObjectVersion - permanently deletes a version of an object.
+func (xl xlObjects) DeleteObjectVersion(bucket, object, versionID string) error {
+	return deleteObjectVersion(xl, bucket, object, versionID, xl.deleteCurrentObject)
+}
+
+// ListObjectVersions - lists versions of objects of a bucket.
+func (xl xlObjects) ListObjectVersions(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	return listObjectVersions(xl, bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+}
+
+// moveObjectData - moves the parts and `xl.json` of an object to
+// another object on all disks. Other files in the object directory,
+// like the parts of an overwrite not committed yet, are left behind.
+func (xl xlObjects) moveObjectData(srcBucket, srcObject, dstBucket, dstObject string) error {
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, srcBucket, srcObject)
+	if reducedErr := reduceReadQuorumErrs(errs, objectReadIgnoredErrs, xl.readQuorum); reducedErr != nil {
+		return toObjectErr(reducedErr, srcBucket, srcObject)
+	}
+	_, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+	xlMeta, err := pickValidXLMeta(metaArr, modTime)
+	if err != nil {
+		return toObjectErr(err, srcBucket, srcObject)
+	}
+
+	// `xl.json` is moved last, the object shows up once complete.
+	var names []string
+	for _, part := range xlMeta.Parts {
+		names = append(names, part.Name)
+	}
+	names = append(names, xlMetaJSONFile)
+
+	// rename() drops the disks it fails on, each rename gets a copy.
+	renameAll := func(names []string, srcBucket, srcObject, dstBucket, dstObject string) (moved int, err error) {
+		for _, name := range names {
+			disks := make([]StorageAPI, len(xl.storageDisks))
+			copy(disks, xl.storageDisks)
+			if err = renamePart(disks, srcBucket, pathJoin(srcObject, name), dstBucket, pathJoin(dstObject, name), xl.getWriteQuorum()); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+		return moved, nil
+	}
+	moved, err := renameAll(names, srcBucket, srcObject, dstBucket, dstObject)
+	if err != nil {
+		// Move back the files moved so far.
+		_, rerr := renameAll(names[:moved], dstBucket, dstObject, srcBucket, srcObject)
+		errorIf(rerr, "Unable to move back the parts of %s/%s.", srcBucket, srcObject)
+		return toObjectErr(err, srcBucket, srcObject)
+	}
+
+	if xl.objCacheEnabled {
+		xl.objCache.Delete(pathJoin(srcBucket, srcObject))
+	}
+	return nil
+}
@@ -71,6 +71,12 @@ type xlObjects struct {
 
 	// Object cache enabled.
 	objCacheEnabled bool
+
+	// Ranges prefetched after aligned range reads, nil if disabled.
+	readAhead *readAheadCache
+
+	// Metadata prefetched by ongoing listings, nil if disabled.
+	listMetas *xlListMetas
 }
 
 // list of all errors that can be ignored in tree walk operation in XL
@@ -86,6 +92,10 @@ func newXLObjectLayer(storageDisks []StorageAPI) (ObjectLayer, error) {
 	err = initBucketPolicies(objAPI)
 	fatalIf(err, "Unable to load all bucket policies.")
 
+	// Initialize and load the versioning status of buckets.
+	err = initBucketVersioning(objAPI)
+	fatalIf(err, "Unable to load the versioning status of buckets.")
+
 	// Initialize a new event notifier.
 	err = initEventNotifier(objAPI)
 	fatalIf(err, "Unable to initialize event notification.")
@@ -125,6 +135,8 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		dataBlocks:   dataBlocks,
 		parityBlocks: parityBlocks,
 		listPool:     listPool,
+		readAhead:    newReadAheadCache(globalReadAheadCacheSize),
+		listMetas:    newXLListMetas(),
 	}
 
 	// Get cache size if _MINIO_CACHE environment variable is set.
@@ -161,7 +173,7 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 	xl.writeQuorum = writeQuorum
 
 	// Do a quick heal on the buckets themselves for any discrepancies.
-	if err := quickHeal(xl.storageDisks, xl.writeQuorum, xl.readQuorum); err != nil {
+	if err := quickHeal(xl.storageDisks, xl.getWriteQuorum(), xl.readQuorum); err != nil {
 		return xl, err
 	}
 
@@ -169,6 +181,16 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 	return xl, nil
 }
 
+// getWriteQuorum - returns the number of disks required to write data,
+// N/2 while this node holds the lease of a witness for the half of a
+// split cluster it is in, as the other half cannot take locks then.
+func (xl xlObjects) getWriteQuorum() int {
+	if globalWitness != nil && globalWitness.isDegraded() {
+		return len(xl.storageDisks) / 2
+	}
+	return xl.writeQuorum
+}
+
 // Shutdown function for object storage interface.
 func (xl xlObjects) Shutdown() error {
 	// Add any object layer shutdown activities here.
@@ -263,6 +285,6 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 func (xl xlObjects) StorageInfo() StorageInfo {
 	storageInfo := getStorageInfo(xl.storageDisks)
 	storageInfo.Backend.ReadQuorum = xl.readQuorum
-	storageInfo.Backend.WriteQuorum = xl.writeQuorum
+	storageInfo.Backend.WriteQuorum = xl.getWriteQuorum()
 	return storageInfo
 }
@@ -67,3 +67,16 @@ func NewBytePool(size int64, n int) *BytePool {
 	buf := make([][]byte, n)
 	return &BytePool{buf, used, size, sync.Mutex{}}
 }
+
+// NewBytePoolFromBuffer - Returns new pool of slices carved out of
+// an existing buffer instead of allocating them.
+// size - length of each slice.
+// buf - backing buffer, the pool has len(buf)/size slices.
+func NewBytePoolFromBuffer(size int64, buf []byte) *BytePool {
+	pool := NewBytePool(size, len(buf)/int(size))
+	for i := range pool.buf {
+		start := int64(i) * size
+		pool.buf[i] = buf[start : start+size : start+size]
+	}
+	return pool
+}
@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bpool
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBpoolTimeout - no memory was released in time to allocate a
+// buffer within the memory cap of the pool.
+var ErrBpoolTimeout = errors.New("timed out waiting for free memory in pool")
+
+// CappedPool - pool of reusable byte slices of arbitrary sizes, the
+// total size of slices in use is bounded by a memory cap. Get waits
+// for slices to be returned while the cap is reached, providing back
+// pressure to callers instead of growing memory usage unbounded.
+type CappedPool struct {
+	mu       sync.Mutex
+	limit    int64                // memory cap, zero for unlimited
+	used     int64                // total size of slices in use
+	timeout  time.Duration        // maximum time Get waits for memory
+	pools    map[int64]*sync.Pool // reusable slices by size
+	released chan struct{}        // closed whenever a slice is returned
+}
+
+// NewCappedPool - returns a new pool capped at limit bytes, zero
+// means unlimited. Get fails with ErrBpoolTimeout after waiting for
+// timeout for memory to be released.
+func NewCappedPool(limit int64, timeout time.Duration) *CappedPool {
+	return &CappedPool{
+		limit:    limit,
+		timeout:  timeout,
+		pools:    make(map[int64]*sync.Pool),
+		released: make(chan struct{}),
+	}
+}
+
+// SetLimit - changes the memory cap, zero means unlimited.
+func (p *CappedPool) SetLimit(limit int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = limit
+	p.notify()
+}
+
+// Limit - returns the memory cap.
+func (p *CappedPool) Limit() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limit
+}
+
+// Used - returns the total size of slices in use.
+func (p *CappedPool) Used() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.used
+}
+
+// Get - returns a slice of length size, waits for other slices to
+// be returned if it does not fit in the memory cap. A slice larger
+// than the cap is handed out only when no other slice is in use.
+func (p *CappedPool) Get(size int64) ([]byte, error) {
+	var timer *time.Timer
+	for {
+		p.mu.Lock()
+		if p.limit <= 0 || p.used == 0 || p.used+size <= p.limit {
+			p.used += size
+			pool, ok := p.pools[size]
+			if !ok {
+				pool = &sync.Pool{}
+				p.pools[size] = pool
+			}
+			p.mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			if bufp, ok := pool.Get().(*[]byte); ok {
+				return *bufp, nil
+			}
+			return make([]byte, size), nil
+		}
+		released := p.released
+		p.mu.Unlock()
+
+		if timer == nil {
+			timer = time.NewTimer(p.timeout)
+		}
+		select {
+		case <-released:
+		case <-timer.C:
+			return nil, ErrBpoolTimeout
+		}
+	}
+}
+
+// Put - returns a slice obtained from Get to the pool.
+func (p *CappedPool) Put(buf []byte) {
+	size := int64(cap(buf))
+	buf = buf[:size]
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.used -= size
+	if pool, ok := p.pools[size]; ok {
+		pool.Put(&buf)
+	}
+	p.notify()
+}
+
+// notify - wakes up all callers waiting for memory, must be called
+// with the lock held.
+func (p *CappedPool) notify() {
+	close(p.released)
+	p.released = make(chan struct{})
+}
@@ -0,0 +1,81 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bpool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCappedPool(t *testing.T) {
+	pool := NewCappedPool(10, 100*time.Millisecond)
+
+	buf1, err := pool.Get(6)
+	if err != nil {
+		t.Fatal("expected nil, got", err)
+	}
+	if len(buf1) != 6 {
+		t.Fatalf("expected size 6, got %d", len(buf1))
+	}
+	buf2, err := pool.Get(4)
+	if err != nil {
+		t.Fatal("expected nil, got", err)
+	}
+	if used := pool.Used(); used != 10 {
+		t.Fatalf("expected 10 bytes in use, got %d", used)
+	}
+
+	// The cap is reached, Get times out.
+	if _, err = pool.Get(1); err != ErrBpoolTimeout {
+		t.Fatalf("expected %s, got %v", ErrBpoolTimeout, err)
+	}
+
+	// Get waits until enough memory is returned.
+	done := make(chan []byte)
+	go func() {
+		buf, gerr := pool.Get(5)
+		if gerr != nil {
+			t.Error("expected nil, got", gerr)
+		}
+		done <- buf
+	}()
+	pool.Put(buf1[:2])
+	buf3 := <-done
+	if len(buf3) != 5 {
+		t.Fatalf("expected size 5, got %d", len(buf3))
+	}
+	pool.Put(buf2)
+	pool.Put(buf3)
+	if used := pool.Used(); used != 0 {
+		t.Fatalf("expected 0 bytes in use, got %d", used)
+	}
+
+	// Slices larger than the cap are handed out when nothing else is in use.
+	buf4, err := pool.Get(20)
+	if err != nil {
+		t.Fatal("expected nil, got", err)
+	}
+	pool.Put(buf4)
+
+	// Unlimited pool never waits.
+	pool.SetLimit(0)
+	for i := 0; i < 4; i++ {
+		if _, err = pool.Get(20); err != nil {
+			t.Fatal("expected nil, got", err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ellipses implements expansion of ellipses patterns such as
+// "/mnt/disk{1...4}" into "/mnt/disk1" ... "/mnt/disk4".
+package ellipses
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	openBrace  = "{"
+	closeBrace = "}"
+	ellipses   = "..."
+)
+
+// MaxExpansion is the maximum number of strings a single argument
+// may expand to.
+const MaxExpansion = 1024
+
+// Matches a single ellipses pattern, e.g. "{1...16}" or "{01...16}".
+var ellipsesRegexp = regexp.MustCompile(`\{([0-9]+)\.\.\.([0-9]+)\}`)
+
+// Matches anything which looks like an ellipses pattern, valid or not.
+var looseEllipsesRegexp = regexp.MustCompile(`\{[^{}]*\.\.\.[^{}]*\}`)
+
+// HasEllipses - returns true if any of args contains an ellipses pattern.
+func HasEllipses(args ...string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, ellipses) && strings.Contains(arg, openBrace) && strings.Contains(arg, closeBrace) {
+			return true
+		}
+	}
+	return false
+}
+
+// sequence - an expanded ellipses pattern.
+type sequence []string
+
+// parseSequence - parses the bounds of an ellipses pattern, bounds
+// with leading zeros produce zero padded numbers of the same width.
+func parseSequence(pattern, startStr, endStr string) (sequence, error) {
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid ellipses pattern %s: %v", pattern, err)
+	}
+	end, err := strconv.Atoi(endStr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid ellipses pattern %s: %v", pattern, err)
+	}
+	if start > end {
+		return nil, fmt.Errorf("Invalid ellipses pattern %s: range start is greater than range end", pattern)
+	}
+	if end-start+1 > MaxExpansion {
+		return nil, fmt.Errorf("Invalid ellipses pattern %s: expands to more than %d values", pattern, MaxExpansion)
+	}
+
+	format := "%d"
+	if len(startStr) > 1 && strings.HasPrefix(startStr, "0") {
+		format = fmt.Sprintf("%%0%dd", len(startStr))
+	}
+	seq := make(sequence, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		seq = append(seq, fmt.Sprintf(format, i))
+	}
+	return seq, nil
+}
+
+// Expand - expands all ellipses patterns in arg, multiple patterns
+// expand to all their combinations with the leftmost pattern varying
+// slowest, e.g. "http://host{1...2}/disk{1...2}" expands to
+// "http://host1/disk1", "http://host1/disk2", "http://host2/disk1" and
+// "http://host2/disk2". Arguments without patterns expand to themselves.
+func Expand(arg string) ([]string, error) {
+	if !HasEllipses(arg) {
+		return []string{arg}, nil
+	}
+
+	// Every pattern which looks like an ellipses pattern must be valid.
+	for _, pattern := range looseEllipsesRegexp.FindAllString(arg, -1) {
+		if !ellipsesRegexp.MatchString(pattern) || ellipsesRegexp.FindString(pattern) != pattern {
+			return nil, fmt.Errorf("Invalid ellipses pattern %s, expected {START...END} with numeric bounds", pattern)
+		}
+	}
+
+	matches := ellipsesRegexp.FindAllStringSubmatchIndex(arg, -1)
+	if len(matches) == 0 {
+		return []string{arg}, nil
+	}
+
+	// Split arg into literal parts around the patterns.
+	var literals []string
+	var sequences []sequence
+	total := 1
+	prev := 0
+	for _, match := range matches {
+		literals = append(literals, arg[prev:match[0]])
+		seq, err := parseSequence(arg[match[0]:match[1]], arg[match[2]:match[3]], arg[match[4]:match[5]])
+		if err != nil {
+			return nil, err
+		}
+		total *= len(seq)
+		if total > MaxExpansion {
+			return nil, fmt.Errorf("Invalid ellipses in %s: expands to more than %d values", arg, MaxExpansion)
+		}
+		sequences = append(sequences, seq)
+		prev = match[1]
+	}
+	literals = append(literals, arg[prev:])
+
+	expanded := []string{literals[0]}
+	for i, seq := range sequences {
+		next := make([]string, 0, len(expanded)*len(seq))
+		for _, prefix := range expanded {
+			for _, value := range seq {
+				next = append(next, prefix+value+literals[i+1])
+			}
+		}
+		expanded = next
+	}
+	return expanded, nil
+}
+
+// ExpandAll - expands all ellipses patterns in args, see Expand.
+func ExpandAll(args []string) ([]string, error) {
+	var expanded []string
+	for _, arg := range args {
+		values, err := Expand(arg)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, values...)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ellipses_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/ellipses"
+)
+
+// TestHasEllipses - tests detection of ellipses patterns.
+func TestHasEllipses(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"/export"}, false},
+		{[]string{"/export1", "/export2"}, false},
+		{[]string{"/export{1..4}"}, false},
+		{[]string{"/export{1...4}"}, true},
+		{[]string{"/export1", "http://host{1...4}/export"}, true},
+		{[]string{"/export{a...d}"}, true},
+	}
+	for i, testCase := range testCases {
+		if got := ellipses.HasEllipses(testCase.args...); got != testCase.expected {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// TestExpand - tests expansion of ellipses patterns.
+func TestExpand(t *testing.T) {
+	testCases := []struct {
+		arg        string
+		expected   []string
+		shouldPass bool
+	}{
+		{"/export", []string{"/export"}, true},
+		{"/export{1...3}", []string{"/export1", "/export2", "/export3"}, true},
+		{"/export{1...1}", []string{"/export1"}, true},
+		// Leading zeros pad the expanded numbers.
+		{"/export{08...11}", []string{"/export08", "/export09", "/export10", "/export11"}, true},
+		// Multiple patterns, the leftmost pattern varies slowest.
+		{
+			"http://host{1...2}/export{1...2}",
+			[]string{"http://host1/export1", "http://host1/export2", "http://host2/export1", "http://host2/export2"},
+			true,
+		},
+		{"/export{a...d}", nil, false},
+		{"/export{1...}", nil, false},
+		{"/export{...4}", nil, false},
+		{"/export{4...1}", nil, false},
+		{"/export{1...2...3}", nil, false},
+		{"/export{1...5000}", nil, false},
+		{"/export{1...100}/{1...100}", nil, false},
+	}
+	for i, testCase := range testCases {
+		got, err := ellipses.Expand(testCase.arg)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %v", i+1, got)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// TestExpandAll - tests expansion of multiple arguments.
+func TestExpandAll(t *testing.T) {
+	got, err := ellipses.ExpandAll([]string{"/a{1...2}", "/b", "/c{1...2}"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := []string{"/a1", "/a2", "/b", "/c1", "/c2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if _, err = ellipses.ExpandAll([]string{"/a{1...2}", "/b{2...1}"}); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
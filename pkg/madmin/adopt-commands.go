@@ -0,0 +1,73 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// AdoptResult - result of adopting pre-existing files as objects.
+type AdoptResult struct {
+	// Number and total size of files adopted as objects.
+	Adopted int64 `json:"adopted"`
+	Size    int64 `json:"size"`
+
+	// Number of files which already were objects.
+	Existing int64 `json:"existing"`
+
+	// Files skipped because their names are not valid object names,
+	// at most 1000 names are returned.
+	SkippedCount int64    `json:"skippedCount"`
+	Skipped      []string `json:"skipped,omitempty"`
+}
+
+// AdoptObjects - registers files copied into the bucket directory of
+// an FS backend as objects, optionally only those under prefix.
+func (adm *AdminClient) AdoptObjects(bucket, prefix string) (AdoptResult, error) {
+	queryVal := url.Values{}
+	queryVal.Set("adopt", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("prefix", prefix)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "adopt")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?adopt to adopt pre-existing files.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return AdoptResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return AdoptResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result AdoptResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return AdoptResult{}, err
+	}
+	return result, nil
+}
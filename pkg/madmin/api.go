@@ -65,6 +65,9 @@ type AdminClient struct {
 
 	// Random seed.
 	random *rand.Rand
+
+	// Approval token sent with every request, if set.
+	approvalToken string
 }
 
 // Global constants.
@@ -430,6 +433,11 @@ func (c AdminClient) newRequest(method string, reqData requestData) (req *http.R
 		req.Header.Set(k, v[0])
 	}
 
+	// Set approval token, if any.
+	if c.approvalToken != "" {
+		req.Header.Set(minioApprovalTokenHeader, c.approvalToken)
+	}
+
 	// set incoming content-length.
 	if reqData.contentLength > 0 {
 		req.ContentLength = reqData.contentLength
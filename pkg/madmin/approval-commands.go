@@ -0,0 +1,79 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ApprovalResult - an approval token and its expiry.
+type ApprovalResult struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// IssueApproval - issues an approval token for one call of the
+// destructive operation, e.g. "lock.clear", on bucket if not empty,
+// with another credential.
+func (adm *AdminClient) IssueApproval(operation, bucket string) (ApprovalResult, error) {
+	queryVal := url.Values{}
+	queryVal.Set("approval", "")
+	queryVal.Set("operation", operation)
+	if bucket != "" {
+		queryVal.Set("bucket", bucket)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "issue")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?approval to issue an approval token.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return ApprovalResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ApprovalResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result ApprovalResult
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ApprovalResult{}, err
+	}
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return ApprovalResult{}, err
+	}
+	return result, nil
+}
+
+// SetApprovalToken - sends the approval token with the following
+// requests, stops sending one if empty.
+func (adm *AdminClient) SetApprovalToken(token string) {
+	adm.approvalToken = token
+}
@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AuditEntry - represents one admin API call recorded in the admin
+// audit log.
+type AuditEntry struct {
+	Seq           uint64    `json:"seq"`
+	Time          time.Time `json:"time"`
+	AccessKey     string    `json:"accessKey"`
+	RemoteHost    string    `json:"remoteHost"`
+	Operation     string    `json:"operation"`
+	Resource      string    `json:"resource,omitempty"`
+	PayloadSHA256 string    `json:"payloadSHA256"`
+	Status        int       `json:"status"`
+	PrevHash      string    `json:"prevHash"`
+	Hash          string    `json:"hash"`
+}
+
+// AuditLog - represents the admin audit log of a UTC day.
+type AuditLog struct {
+	Date    string       `json:"date"`
+	Entries []AuditEntry `json:"entries"`
+	// BrokenAt is the index of the first entry breaking the hash
+	// chain, the number of entries if entries at the end of the log
+	// are missing, -1 if the log is intact.
+	BrokenAt int `json:"brokenAt"`
+}
+
+// ListAudit - lists the admin API calls recorded on the UTC day of
+// date.
+func (adm *AdminClient) ListAudit(date time.Time) (AuditLog, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("audit", "")
+	queryVal.Set("date", date.UTC().Format("2006-01-02"))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?audit to list the audit log.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return AuditLog{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return AuditLog{}, httpRespToErrorResponse(resp)
+	}
+
+	var auditLog AuditLog
+	if err = json.NewDecoder(resp.Body).Decode(&auditLog); err != nil {
+		return AuditLog{}, err
+	}
+
+	return auditLog, nil
+}
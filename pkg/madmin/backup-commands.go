@@ -0,0 +1,109 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// BackupResult - the backup object taken.
+type BackupResult struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+}
+
+// CreateBackup - backs up the server configuration and the
+// configuration of all buckets into the backup bucket now.
+func (adm *AdminClient) CreateBackup() (BackupResult, error) {
+	queryVal := url.Values{}
+	queryVal.Set("backup", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "create")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?backup to back up configuration.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BackupResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BackupResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result BackupResult
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BackupResult{}, err
+	}
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return BackupResult{}, err
+	}
+	return result, nil
+}
+
+// RestoreBackup - restores the backup object of the given bucket, the
+// backup bucket if empty, and restarts all servers with the restored
+// configuration.
+func (adm *AdminClient) RestoreBackup(bucket, object string) (SetConfigResult, error) {
+	queryVal := url.Values{}
+	queryVal.Set("backup", "")
+	if bucket != "" {
+		queryVal.Set("bucket", bucket)
+	}
+	queryVal.Set("object", object)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "restore")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?backup to restore configuration.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return SetConfigResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return SetConfigResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result SetConfigResult
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return SetConfigResult{}, err
+	}
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return SetConfigResult{}, err
+	}
+	return result, nil
+}
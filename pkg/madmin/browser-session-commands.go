@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RevokeBrowserSessions - revokes browser sessions started before the
+// given time, the current time if zero.
+func (adm *AdminClient) RevokeBrowserSessions(before time.Time) error {
+	queryVal := url.Values{}
+	queryVal.Set("browser-session", "")
+	if !before.IsZero() {
+		queryVal.Set("before", before.UTC().Format(time.RFC3339))
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "revoke")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?browser-session to revoke browser sessions.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// SetBrowserReadOnly - makes the browser read-only, or writable again,
+// on all servers. Read-only browsers cannot upload or delete objects.
+func (adm *AdminClient) SetBrowserReadOnly(readOnly bool) error {
+	queryVal := url.Values{}
+	queryVal.Set("browser-read-only", "")
+	queryVal.Set("enable", strconv.FormatBool(readOnly))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute PUT on /?browser-read-only to set the read-only mode.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	bucketConfigQueryParam = "bucket-config"
+)
+
+// ExportBucketConfig - returns the configuration bundle of a bucket,
+// a JSON document carrying its policy and notification configuration.
+func (adm *AdminClient) ExportBucketConfig(bucket string) ([]byte, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(bucketConfigQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "export")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?bucket-config to export bucket config.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	// Return the JSON marshalled bytes to user.
+	return ioutil.ReadAll(resp.Body)
+}
+
+// ImportBucketConfig - replaces the configuration of a bucket with a
+// bundle returned by ExportBucketConfig, possibly of another setup.
+func (adm *AdminClient) ImportBucketConfig(bucket string, config io.Reader) error {
+	queryVal := make(url.Values)
+	queryVal.Set(bucketConfigQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "import")
+
+	// Read config bytes to calculate MD5, SHA256 and content length.
+	configBytes, err := ioutil.ReadAll(config)
+	if err != nil {
+		return err
+	}
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(configBytes),
+		contentMD5Bytes:    sumMD5(configBytes),
+		contentSHA256Bytes: sum256(configBytes),
+	}
+
+	// Execute PUT on /?bucket-config to import bucket config.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// SetBucketNetwork - sets the client networks, CIDRs or addresses,
+// allowed to access bucket. Networks in deny are rejected even if
+// allowed. Without networks, any client is allowed.
+func (adm *AdminClient) SetBucketNetwork(bucket string, allow, deny []string) error {
+	queryVal := url.Values{}
+	queryVal.Set("bucket-network", "")
+	queryVal.Set("bucket", bucket)
+	for _, network := range allow {
+		queryVal.Add("allow", network)
+	}
+	for _, network := range deny {
+		queryVal.Add("deny", network)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute PUT on /?bucket-network to set the bucket networks.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -38,11 +38,25 @@ type NodeSummary struct {
 	ErrMsg string `json:"errMsg"`
 }
 
+// NotifyTargetSummary - represents the result of connecting to a
+// notification target enabled in the config.
+type NotifyTargetSummary struct {
+	ARN    string `json:"arn"`
+	ErrSet bool   `json:"errSet"`
+	ErrMsg string `json:"errMsg"`
+}
+
 // SetConfigResult - represents detailed results of a set-config
 // operation.
 type SetConfigResult struct {
 	NodeResults []NodeSummary `json:"nodeResults"`
 	Status      bool          `json:"status"`
+	// Partial is set if the config was set on a quorum of nodes
+	// but not on all of them.
+	Partial bool `json:"partial"`
+	// The config is not set if any of the notification targets
+	// it enables cannot be connected to.
+	NotifyTargets []NotifyTargetSummary `json:"notifyTargets,omitempty"`
 }
 
 // GetConfig - returns the config.json of a minio setup.
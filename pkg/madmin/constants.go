@@ -22,4 +22,7 @@ const (
 
 	// Admin operation header.
 	minioAdminOpHeader = "X-Minio-Operation"
+
+	// Approval token header of destructive operations.
+	minioApprovalTokenHeader = "X-Minio-Approval-Token"
 )
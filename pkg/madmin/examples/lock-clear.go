@@ -43,5 +43,13 @@ func main() {
 	if err != nil {
 		log.Fatalln(err)
 	}
-	log.Println(locksCleared)
+	log.Println("Cleared locks of", locksCleared, "objects")
+
+	// Clear locks held on mybucket by the server at
+	// 192.168.1.12:9000, which is offline.
+	locksCleared, err = madmClnt.ClearNodeLocks("192.168.1.12:9000", "mybucket", "", 0)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("Cleared locks of", locksCleared, "objects held by 192.168.1.12:9000")
 }
@@ -18,6 +18,8 @@
 package madmin
 
 import (
+	"bufio"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
@@ -55,6 +57,10 @@ type listBucketHealResult struct {
 	// next set of object keys.
 	NextMarker string
 	Prefix     string
+
+	// Count of objects needing heal over all pages, only set when
+	// requested.
+	TotalCount int64
 }
 
 // commonPrefix container for prefix response.
@@ -166,6 +172,8 @@ const (
 	healDelimiter healQueryKey = "delimiter"
 	healMaxKey    healQueryKey = "max-key"
 	healDryRun    healQueryKey = "dry-run"
+	healTotal     healQueryKey = "total"
+	healToken     healQueryKey = "token"
 )
 
 // mkHealQueryVal - helper function to construct heal REST API query params.
@@ -285,6 +293,44 @@ func (adm *AdminClient) ListObjectsHeal(bucket, prefix string, recursive bool, d
 	return objectStatCh, nil
 }
 
+// CountObjectsHeal - Returns the number of objects needing heal in
+// bucket matching prefix. The server lists all of them to count, so
+// this is as expensive as a full ListObjectsHeal.
+func (adm *AdminClient) CountObjectsHeal(bucket, prefix string, recursive bool) (int64, error) {
+	delimiter := "/"
+	if recursive {
+		delimiter = ""
+	}
+	queryVal := mkHealQueryVal(bucket, prefix, "", delimiter, "0")
+	queryVal.Set(string(healTotal), "true")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list-objects")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?heal to count objects needing heal.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, httpRespToErrorResponse(resp)
+	}
+
+	result := listBucketHealResult{}
+	if err = xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
+
 const timeFormatAMZLong = "2006-01-02T15:04:05.000Z" // Reply date format with nanosecond precision.
 
 // ListBucketsHeal - issues heal bucket list API request
@@ -337,6 +383,129 @@ func (adm *AdminClient) ListBucketsHeal() ([]BucketInfo, error) {
 	return bucketsToBeHealed, nil
 }
 
+// UnicodeDuplicate - objects whose names differ only in Unicode
+// normalization, Name is the NFC normalized name.
+type UnicodeDuplicate struct {
+	Name    string   `json:"name"`
+	Objects []string `json:"objects"`
+}
+
+// ListUnicodeDuplicates - lists objects in bucket matching prefix whose
+// names are identical once normalized to Unicode NFC, e.g. created by
+// clients using NFD names.
+func (adm *AdminClient) ListUnicodeDuplicates(bucket, prefix string) ([]UnicodeDuplicate, error) {
+	queryVal := url.Values{}
+	queryVal.Set("heal", "")
+	queryVal.Set(string(healBucket), bucket)
+	queryVal.Set(string(healPrefix), prefix)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list-unicode-duplicates")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?heal to list unicode duplicates.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var duplicates []UnicodeDuplicate
+	if err = json.NewDecoder(resp.Body).Decode(&duplicates); err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// QuarantinedObject - an object moved out of its bucket because its
+// metadata is corrupted on too many disks to be read or healed.
+type QuarantinedObject struct {
+	ID     string    `json:"id"`
+	Bucket string    `json:"bucket"`
+	Object string    `json:"object"`
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason"`
+}
+
+// ListQuarantined - lists the objects quarantined from bucket, or from
+// all buckets if bucket is empty, oldest first.
+func (adm *AdminClient) ListQuarantined(bucket string) ([]QuarantinedObject, error) {
+	queryVal := url.Values{}
+	queryVal.Set("heal", "")
+	if bucket != "" {
+		queryVal.Set(string(healBucket), bucket)
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list-quarantined")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?heal to list quarantined objects.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var objects []QuarantinedObject
+	if err = json.NewDecoder(resp.Body).Decode(&objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// RestoreQuarantined - moves the quarantined object id back into its
+// bucket, fails if an object with the same name exists.
+func (adm *AdminClient) RestoreQuarantined(id string) (QuarantinedObject, error) {
+	queryVal := url.Values{}
+	queryVal.Set("heal", "")
+	queryVal.Set("id", id)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "restore-quarantined")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?heal&id=id to restore a quarantined object.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return QuarantinedObject{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return QuarantinedObject{}, httpRespToErrorResponse(resp)
+	}
+
+	var object QuarantinedObject
+	if err = json.NewDecoder(resp.Body).Decode(&object); err != nil {
+		return QuarantinedObject{}, err
+	}
+	return object, nil
+}
+
 // HealBucket - Heal the given bucket
 func (adm *AdminClient) HealBucket(bucket string, dryrun bool) error {
 	// Construct query params.
@@ -435,3 +604,127 @@ func (adm *AdminClient) HealFormat(dryrun bool) error {
 
 	return nil
 }
+
+// HealSequenceStatus - progress of a heal sequence.
+type HealSequenceStatus struct {
+	Token     string    `json:"token"`
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix,omitempty"`
+	State     string    `json:"state"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+	// Objects listed, healed and failed to heal so far.
+	ObjectsScanned int64 `json:"objectsScanned"`
+	ObjectsHealed  int64 `json:"objectsHealed"`
+	ObjectsFailed  int64 `json:"objectsFailed"`
+	BytesHealed    int64 `json:"bytesHealed"`
+	// Last error healing an object, or the error which ended the
+	// sequence if it failed.
+	LastError string `json:"lastError,omitempty"`
+
+	// Error reading the status, set on the last status sent by HealStatus.
+	Err error `json:"-"`
+}
+
+// States of heal sequences.
+const (
+	HealSequenceRunning = "running"
+	HealSequenceDone    = "done"
+	HealSequenceFailed  = "failed"
+)
+
+// StartHeal - starts healing bucket and the objects under prefix in
+// the background, returns the status of the heal sequence carrying
+// its token. If dryrun is true the request is only validated.
+func (adm *AdminClient) StartHeal(bucket, prefix string, dryrun bool) (HealSequenceStatus, error) {
+	queryVal := url.Values{}
+	queryVal.Set("heal", "")
+	queryVal.Set(string(healBucket), bucket)
+	queryVal.Set(string(healPrefix), prefix)
+	if dryrun {
+		queryVal.Set(string(healDryRun), "")
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "start")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?heal&bucket=mybucket&prefix=myprefix to start healing.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return HealSequenceStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return HealSequenceStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	var status HealSequenceStatus
+	if dryrun {
+		return status, nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return HealSequenceStatus{}, err
+	}
+	return status, nil
+}
+
+// HealStatus - streams the progress of the heal sequence with token,
+// about every second until it has finished. The channel is closed
+// after the final status, whose State is HealSequenceDone or
+// HealSequenceFailed, or after a status carrying an Err.
+func (adm *AdminClient) HealStatus(token string, doneCh <-chan struct{}) (<-chan HealSequenceStatus, error) {
+	queryVal := url.Values{}
+	queryVal.Set("heal", "")
+	queryVal.Set(string(healToken), token)
+
+	reqData := requestData{
+		queryValues: queryVal,
+	}
+
+	// Execute GET on /?heal&token=mytoken to stream heal progress.
+	resp, err := adm.executeMethod("GET", reqData)
+	if err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer closeResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	statusCh := make(chan HealSequenceStatus, 1)
+	go func() {
+		defer close(statusCh)
+		defer closeResponse(resp)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var status HealSequenceStatus
+			if err := json.Unmarshal(scanner.Bytes(), &status); err != nil {
+				status = HealSequenceStatus{Err: err}
+			}
+			select {
+			case statusCh <- status:
+			case <-doneCh:
+				return
+			}
+			if status.Err != nil {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case statusCh <- HealSequenceStatus{Err: err}:
+			case <-doneCh:
+			}
+		}
+	}()
+	return statusCh, nil
+}
@@ -0,0 +1,144 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Canned policies of IAM users.
+const (
+	// IAMPolicyReadWrite - allows all actions on all buckets.
+	IAMPolicyReadWrite = "readwrite"
+	// IAMPolicyReadOnly - allows listing buckets and objects and
+	// reading objects.
+	IAMPolicyReadOnly = "readonly"
+	// IAMPolicyWriteOnly - allows uploading objects only.
+	IAMPolicyWriteOnly = "writeonly"
+)
+
+// IAMUser - an IAM user along with its policy, either canned or
+// custom. Secret keys are never listed.
+type IAMUser struct {
+	AccessKey    string          `json:"accessKey"`
+	CannedPolicy string          `json:"cannedPolicy,omitempty"`
+	Policy       json.RawMessage `json:"policy,omitempty"`
+}
+
+// iamUserConfig - the body of IAM user requests.
+type iamUserConfig struct {
+	SecretKey    string          `json:"secretKey,omitempty"`
+	CannedPolicy string          `json:"cannedPolicy,omitempty"`
+	Policy       json.RawMessage `json:"policy,omitempty"`
+}
+
+// executeIAMUser - executes the IAM user operation op on accessKey.
+func (adm *AdminClient) executeIAMUser(method, op, accessKey string, config *iamUserConfig) error {
+	queryVal := url.Values{}
+	queryVal.Set("iam-user", "")
+	queryVal.Set("accessKey", accessKey)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, op)
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+	if config != nil {
+		configBytes, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		reqData.contentBody = bytes.NewReader(configBytes)
+		reqData.contentMD5Bytes = sumMD5(configBytes)
+		reqData.contentSHA256Bytes = sum256(configBytes)
+	}
+
+	// Execute the operation on /?iam-user.
+	resp, err := adm.executeMethod(method, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// AddUser - adds the IAM user with accessKey and secretKey, or
+// replaces it, allowed either the canned policy or the custom JSON
+// policy. Custom policies are bucket policies without principals.
+func (adm *AdminClient) AddUser(accessKey, secretKey, cannedPolicy string, policy []byte) error {
+	return adm.executeIAMUser("PUT", "add", accessKey, &iamUserConfig{
+		SecretKey:    secretKey,
+		CannedPolicy: cannedPolicy,
+		Policy:       policy,
+	})
+}
+
+// SetUserPolicy - replaces the policy of the IAM user with accessKey
+// by either the canned policy or the custom JSON policy.
+func (adm *AdminClient) SetUserPolicy(accessKey, cannedPolicy string, policy []byte) error {
+	return adm.executeIAMUser("PUT", "set-policy", accessKey, &iamUserConfig{
+		CannedPolicy: cannedPolicy,
+		Policy:       policy,
+	})
+}
+
+// RemoveUser - removes the IAM user with accessKey.
+func (adm *AdminClient) RemoveUser(accessKey string) error {
+	return adm.executeIAMUser("DELETE", "remove", accessKey, nil)
+}
+
+// ListUsers - lists the IAM users sorted by access key.
+func (adm *AdminClient) ListUsers() ([]IAMUser, error) {
+	queryVal := url.Values{}
+	queryVal.Set("iam-user", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?iam-user to list IAM users.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var users []IAMUser
+	if err = json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -66,6 +66,7 @@ type ServerProperties struct {
 	CommitID string        `json:"commitID"`
 	Region   string        `json:"region"`
 	SQSARN   []string      `json:"sqsARN"`
+	TLSMode  string        `json:"tlsMode"`
 }
 
 // ServerConnStats holds network information
@@ -74,17 +75,88 @@ type ServerConnStats struct {
 	TotalOutputBytes uint64 `json:"received"`
 }
 
-// ServerInfo holds the whole server information that will be
-// returned by ServerInfo API.
-type ServerInfo struct {
+// ServerDiskInfo holds the status of a disk local to a server.
+type ServerDiskInfo struct {
+	Path   string `json:"path"`
+	Online bool   `json:"online"`
+	Total  int64  `json:"total,omitempty"`
+	Free   int64  `json:"free,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ScrubInfo holds the deep scrub progress and coverage of a server,
+// objects are counted once per local disk holding them.
+type ScrubInfo struct {
+	Rate           int64     `json:"rate"`
+	CycleStart     time.Time `json:"cycleStart"`
+	LastCycleEnd   time.Time `json:"lastCycleEnd,omitempty"`
+	ObjectsChecked int64     `json:"objectsChecked"`
+	ObjectsCovered int64     `json:"objectsCovered"`
+	LastCoverage   float64   `json:"lastCoverage"`
+	BytesScrubbed  int64     `json:"bytesScrubbed"`
+	CorruptedParts int64     `json:"corruptedParts"`
+}
+
+// JanitorInfo holds the stale temporary data and orphaned multipart
+// uploads removed from the local disks of a server since it started.
+type JanitorInfo struct {
+	Age               time.Duration `json:"age"`
+	LastRunStart      time.Time     `json:"lastRunStart,omitempty"`
+	LastRunEnd        time.Time     `json:"lastRunEnd,omitempty"`
+	TmpEntriesRemoved int64         `json:"tmpEntriesRemoved"`
+	UploadsRemoved    int64         `json:"uploadsRemoved"`
+	BytesReclaimed    int64         `json:"bytesReclaimed"`
+	LastError         string        `json:"lastError,omitempty"`
+}
+
+// NotifyTargetStats holds the event counters of a notification target
+// of a server since it started. Pending is the number of events being
+// sent to the target.
+type NotifyTargetStats struct {
+	ARN           string    `json:"arn"`
+	Sent          uint64    `json:"sent"`
+	Failed        uint64    `json:"failed"`
+	Pending       int64     `json:"pending"`
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// FailureDomainInfo holds the disks of a failure domain, its loss is
+// tolerated if it holds at most ParityDisks disks.
+type FailureDomainInfo struct {
+	Name        string   `json:"name"`
+	Disks       []string `json:"disks"`
+	ParityDisks int      `json:"parityDisks"`
+}
+
+// ServerInfoData holds the information of a single server.
+type ServerInfoData struct {
 	StorageInfo StorageInfo      `json:"storage"`
 	ConnStats   ServerConnStats  `json:"network"`
 	Properties  ServerProperties `json:"server"`
+	Disks       []ServerDiskInfo `json:"disks"`
+	// Scrub is nil if deep scrubbing is disabled.
+	Scrub *ScrubInfo `json:"scrub,omitempty"`
+	// Janitor is nil if the janitor is disabled.
+	Janitor *JanitorInfo `json:"janitor,omitempty"`
+	// Event counters of the notification targets.
+	NotifyTargets []NotifyTargetStats `json:"notifyTargets,omitempty"`
+	// Disks of the failure domains, if configured.
+	FailureDomains []FailureDomainInfo `json:"failureDomains,omitempty"`
+}
+
+// ServerInfo holds the information of a single server returned by
+// ServerInfo API, Data is nil if the server could not be reached.
+type ServerInfo struct {
+	Addr   string          `json:"addr"`
+	Online bool            `json:"online"`
+	Error  string          `json:"error,omitempty"`
+	Data   *ServerInfoData `json:"data,omitempty"`
 }
 
 // ServerInfo - Connect to a minio server and call Server Info Management API
-// to fetch server's information represented by ServerInfo structure
-func (adm *AdminClient) ServerInfo() (ServerInfo, error) {
+// to fetch the information of every server in the setup.
+func (adm *AdminClient) ServerInfo() ([]ServerInfo, error) {
 	// Prepare web service request
 	reqData := requestData{}
 	reqData.queryValues = make(url.Values)
@@ -94,26 +166,26 @@ func (adm *AdminClient) ServerInfo() (ServerInfo, error) {
 	resp, err := adm.executeMethod("GET", reqData)
 	defer closeResponse(resp)
 	if err != nil {
-		return ServerInfo{}, err
+		return nil, err
 	}
 
 	// Check response http status code
 	if resp.StatusCode != http.StatusOK {
-		return ServerInfo{}, httpRespToErrorResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
 	}
 
 	// Unmarshal the server's json response
-	var info ServerInfo
+	var infos []ServerInfo
 
 	respBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return ServerInfo{}, err
+		return nil, err
 	}
 
-	err = json.Unmarshal(respBytes, &info)
+	err = json.Unmarshal(respBytes, &infos)
 	if err != nil {
-		return ServerInfo{}, err
+		return nil, err
 	}
 
-	return info, nil
+	return infos, nil
 }
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -72,6 +73,16 @@ type VolumeLockInfo struct {
 	// State information containing state of the locks for all operations
 	// on given <volume,path> pair.
 	LockDetailsOnObject []OpsLockState `json:"lockOwners"`
+
+	// Metrics of the lock queue of the server reporting the lock.
+	// Number of times the lock was granted.
+	LocksGranted uint64 `json:"locksGranted"`
+	// Number of operations currently waiting to read and write.
+	WaitingReaders int `json:"waitingReaders"`
+	WaitingWriters int `json:"waitingWriters"`
+	// Total and longest time granted operations waited for the lock.
+	TotalWait time.Duration `json:"totalWait"`
+	MaxWait   time.Duration `json:"maxWait"`
 }
 
 // getLockInfos - unmarshal []VolumeLockInfo from a reader.
@@ -91,14 +102,31 @@ func getLockInfos(body io.Reader) ([]VolumeLockInfo, error) {
 	return lockInfos, nil
 }
 
-// ListLocks - Calls List Locks Management API to fetch locks matching
-// bucket, prefix and held before the duration supplied.
-func (adm *AdminClient) ListLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error) {
+// ListLocksResult - one page of locks returned by ListLocksPage.
+type ListLocksResult struct {
+	Locks []VolumeLockInfo `json:"locks"`
+	// Number of locked objects matching the request, over all pages.
+	Total int `json:"total"`
+	// When IsTruncated is true NextMarker is the marker of the next
+	// page.
+	IsTruncated bool   `json:"isTruncated"`
+	NextMarker  string `json:"nextMarker,omitempty"`
+}
+
+// ListLocksPage - Calls List Locks Management API to fetch locks of
+// upto maxObjects objects following marker, matching bucket, prefix
+// and held before the duration supplied. maxObjects of zero uses the
+// server default of 1000.
+func (adm *AdminClient) ListLocksPage(bucket, prefix string, duration time.Duration, marker string, maxObjects int) (ListLocksResult, error) {
 	queryVal := make(url.Values)
 	queryVal.Set("lock", "")
 	queryVal.Set("bucket", bucket)
 	queryVal.Set("prefix", prefix)
 	queryVal.Set("duration", duration.String())
+	queryVal.Set("marker", marker)
+	if maxObjects > 0 {
+		queryVal.Set("max-key", strconv.Itoa(maxObjects))
+	}
 
 	hdrs := make(http.Header)
 	hdrs.Set(minioAdminOpHeader, "list")
@@ -113,25 +141,47 @@ func (adm *AdminClient) ListLocks(bucket, prefix string, duration time.Duration)
 
 	defer closeResponse(resp)
 	if err != nil {
-		return nil, err
+		return ListLocksResult{}, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, httpRespToErrorResponse(resp)
+		return ListLocksResult{}, httpRespToErrorResponse(resp)
 	}
 
-	return getLockInfos(resp.Body)
+	var result ListLocksResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ListLocksResult{}, err
+	}
+	return result, nil
 }
 
-// ClearLocks - Calls Clear Locks Management API to clear locks held
-// on bucket, matching prefix older than duration supplied.
-func (adm *AdminClient) ClearLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error) {
-	queryVal := make(url.Values)
-	queryVal.Set("lock", "")
-	queryVal.Set("bucket", bucket)
-	queryVal.Set("prefix", prefix)
-	queryVal.Set("duration", duration.String())
+// ListLocks - Calls List Locks Management API to fetch locks matching
+// bucket, prefix and held before the duration supplied, following
+// all pages.
+func (adm *AdminClient) ListLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error) {
+	var volLocks []VolumeLockInfo
+	marker := ""
+	for {
+		result, err := adm.ListLocksPage(bucket, prefix, duration, marker, 0)
+		if err != nil {
+			return nil, err
+		}
+		volLocks = append(volLocks, result.Locks...)
+		if !result.IsTruncated {
+			return volLocks, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// clearLocksResult - clear locks API response.
+type clearLocksResult struct {
+	Cleared int `json:"cleared"`
+}
 
+// clearLocks - Calls Clear Locks Management API with queryVal and
+// returns the number of objects whose locks were cleared.
+func (adm *AdminClient) clearLocks(queryVal url.Values) (int, error) {
 	hdrs := make(http.Header)
 	hdrs.Set(minioAdminOpHeader, "clear")
 
@@ -145,12 +195,44 @@ func (adm *AdminClient) ClearLocks(bucket, prefix string, duration time.Duration
 
 	defer closeResponse(resp)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, httpRespToErrorResponse(resp)
+		return 0, httpRespToErrorResponse(resp)
+	}
+
+	var result clearLocksResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
 	}
+	return result.Cleared, nil
+}
+
+// ClearLocks - Calls Clear Locks Management API to clear locks held
+// on bucket, matching prefix older than duration supplied. Returns the
+// number of objects whose locks were cleared.
+func (adm *AdminClient) ClearLocks(bucket, prefix string, duration time.Duration) (int, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("lock", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("prefix", prefix)
+	queryVal.Set("duration", duration.String())
+
+	return adm.clearLocks(queryVal)
+}
+
+// ClearNodeLocks - Calls Clear Locks Management API to clear locks
+// held by node, the host:port of a possibly offline server, on bucket
+// matching prefix older than duration supplied. Returns the number of
+// objects whose locks were cleared.
+func (adm *AdminClient) ClearNodeLocks(node, bucket, prefix string, duration time.Duration) (int, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("lock", "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("prefix", prefix)
+	queryVal.Set("duration", duration.String())
+	queryVal.Set("node", node)
 
-	return getLockInfos(resp.Body)
+	return adm.clearLocks(queryVal)
 }
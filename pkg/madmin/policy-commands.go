@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// PolicyValidationResult - represents the result of validating a
+// bucket policy.
+type PolicyValidationResult struct {
+	Valid      bool   `json:"valid"`
+	Statements int    `json:"statements"`
+	ErrCode    string `json:"errCode,omitempty"`
+	ErrMsg     string `json:"errMsg,omitempty"`
+}
+
+// ValidatePolicy - validates the bucket policy supplied for bucket
+// without applying it.
+func (adm *AdminClient) ValidatePolicy(bucket string, policy io.Reader) (PolicyValidationResult, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("policy", "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "validate")
+
+	// Read policy bytes to calculate MD5, SHA256 and content length.
+	policyBytes, err := ioutil.ReadAll(policy)
+	if err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(policyBytes),
+		contentMD5Bytes:    sumMD5(policyBytes),
+		contentSHA256Bytes: sum256(policyBytes),
+	}
+
+	// Execute POST on /?policy to validate policy.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return PolicyValidationResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result PolicyValidationResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	return result, nil
+}
+
+// PolicySimulationResult - represents the result of simulating a
+// request against the policies of a bucket.
+type PolicySimulationResult struct {
+	Allowed bool `json:"allowed"`
+	// DecidedBy is one of "root-credential", "unknown-access-key",
+	// "iam-user-policy", "bucket-policy", "no-bucket-policy" or
+	// "implicit-deny".
+	DecidedBy string `json:"decidedBy"`
+	// Statement is the policy statement which decided the result,
+	// only set when DecidedBy is "iam-user-policy" or "bucket-policy".
+	Statement json.RawMessage `json:"statement,omitempty"`
+}
+
+// SimulatePolicy - reports whether accessKey is allowed to perform
+// action on bucket/object and what decided it. An empty accessKey
+// simulates an anonymous request.
+func (adm *AdminClient) SimulatePolicy(accessKey, action, bucket, object string) (PolicySimulationResult, error) {
+	queryVal := make(url.Values)
+	queryVal.Set("policy", "")
+	queryVal.Set("accessKey", accessKey)
+	queryVal.Set("action", action)
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("object", object)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "simulate")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?policy to simulate policy evaluation.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return PolicySimulationResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return PolicySimulationResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result PolicySimulationResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return PolicySimulationResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RevokePresigned - revokes presigned URLs signed with accessKey, the
+// server access key if empty, before the given time, the current time
+// if zero.
+func (adm *AdminClient) RevokePresigned(accessKey string, before time.Time) error {
+	queryVal := url.Values{}
+	queryVal.Set("presign", "")
+	if accessKey != "" {
+		queryVal.Set("accessKey", accessKey)
+	}
+	if !before.IsZero() {
+		queryVal.Set("before", before.UTC().Format(time.RFC3339))
+	}
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "revoke")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?presign to revoke presigned URLs.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RequestLimit - limits on the requests signed with an access key,
+// zero is unlimited.
+type RequestLimit struct {
+	MaxConcurrent        int `json:"maxConcurrent"`
+	MaxRequestsPerSecond int `json:"maxRequestsPerSecond"`
+}
+
+// SetRequestLimit - sets the limits of the requests signed with
+// accessKey, the server access key if empty. A zero limit removes them.
+func (adm *AdminClient) SetRequestLimit(accessKey string, limit RequestLimit) error {
+	queryVal := url.Values{}
+	queryVal.Set("request-limit", "")
+	if accessKey != "" {
+		queryVal.Set("accessKey", accessKey)
+	}
+	queryVal.Set("maxConcurrent", strconv.Itoa(limit.MaxConcurrent))
+	queryVal.Set("maxRequestsPerSecond", strconv.Itoa(limit.MaxRequestsPerSecond))
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute PUT on /?request-limit to set the request limits.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
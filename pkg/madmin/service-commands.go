@@ -28,6 +28,10 @@ import (
 // ServiceStatusMetadata - contains the response of service status API
 type ServiceStatusMetadata struct {
 	Uptime time.Duration `json:"uptime"`
+	// Partial is set if some servers failed to reply, the status
+	// is then computed from the servers that did.
+	Partial     bool          `json:"partial"`
+	FailedNodes []NodeSummary `json:"failedNodes,omitempty"`
 }
 
 // ServiceStatus - Connect to a minio server and call Service Status Management API
@@ -0,0 +1,89 @@
+// +build linux
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sys
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cgroup v2 and v1 files holding the CPU quota of this process.
+const (
+	cgroupV2CPUMax    = "/sys/fs/cgroup/cpu.max"
+	cgroupV1CFSQuota  = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1CFSPeriod = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+)
+
+// GetCPULimit - returns the number of CPUs this process may use as
+// set by its cgroup CPU quota, zero if there is no quota.
+func GetCPULimit() (float64, error) {
+	if data, err := ioutil.ReadFile(cgroupV2CPUMax); err == nil {
+		return parseCPUMax(string(data))
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	quota, err := ioutil.ReadFile(cgroupV1CFSQuota)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	period, err := ioutil.ReadFile(cgroupV1CFSPeriod)
+	if err != nil {
+		return 0, err
+	}
+	return parseCFSQuota(string(quota), string(period))
+}
+
+// parseCPUMax - parses cgroup v2 `cpu.max` of the form "quota period",
+// quota is "max" when there is no limit.
+func parseCPUMax(s string) (float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, errors.New("invalid cpu.max format")
+	}
+	if fields[0] == "max" {
+		return 0, nil
+	}
+	return parseCFSQuota(fields[0], fields[1])
+}
+
+// parseCFSQuota - returns quota/period, a negative quota means no limit.
+func parseCFSQuota(quotaStr, periodStr string) (float64, error) {
+	quota, err := strconv.ParseInt(strings.TrimSpace(quotaStr), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if quota < 0 {
+		return 0, nil
+	}
+	period, err := strconv.ParseInt(strings.TrimSpace(periodStr), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if period <= 0 {
+		return 0, errors.New("invalid cpu quota period")
+	}
+	return float64(quota) / float64(period), nil
+}
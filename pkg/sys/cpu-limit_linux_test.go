@@ -0,0 +1,61 @@
+// +build linux
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sys
+
+import "testing"
+
+// Test parsing of cgroup CPU quotas.
+func TestParseCPUMax(t *testing.T) {
+	testCases := []struct {
+		cpuMax     string
+		limit      float64
+		shouldPass bool
+	}{
+		{"max 100000\n", 0, true},
+		{"200000 100000\n", 2, true},
+		{"50000 100000", 0.5, true},
+		{"-1 100000", 0, true},
+		{"100000 0", 0, false},
+		{"100000", 0, false},
+		{"lots 100000", 0, false},
+	}
+	for i, testCase := range testCases {
+		limit, err := parseCPUMax(testCase.cpuMax)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected `nil`, Got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+		if limit != testCase.limit {
+			t.Errorf("Test %d: Expected %v, Got %v", i+1, testCase.limit, limit)
+		}
+	}
+}
+
+// Test get CPU limit result.
+func TestGetCPULimit(t *testing.T) {
+	limit, err := GetCPULimit()
+	if err != nil {
+		t.Errorf("Tests: Expected `nil`, Got %s", err)
+	}
+	if limit < 0 {
+		t.Errorf("Tests: Expected `n >= 0`, Got %v", limit)
+	}
+}